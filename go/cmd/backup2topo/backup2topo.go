@@ -0,0 +1,70 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// backup2topo restores a backup written by topo2backup into a topology.
+// The topology is expected to be empty, existing records are not
+// overwritten.
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
+)
+
+var (
+	input = flag.String("input", "", "file to read the backup from, instead of stdin")
+)
+
+func main() {
+	defer exit.RecoverAll()
+	defer logutil.Flush()
+
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 0 {
+		flag.Usage()
+		log.Exitf("backup2topo doesn't take any parameter.")
+	}
+
+	var r io.Reader = os.Stdin
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			log.Exitf("Cannot open %v: %v", *input, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	b, err := helpers.ReadBackup(r)
+	if err != nil {
+		log.Exitf("Cannot read the backup: %v", err)
+	}
+
+	ts := topo.Open()
+	defer ts.Close()
+
+	if err := helpers.RestoreTopo(context.Background(), ts, b); err != nil {
+		log.Exitf("Cannot restore the topology: %v", err)
+	}
+	log.Infof("Restored the backup taken at %v", b.Time)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// topo2backup writes all the records of a topology, global and per cell,
+// to a backup file that backup2topo can restore.
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
+)
+
+var (
+	output = flag.String("output", "", "file to write the backup to, instead of stdout")
+)
+
+func main() {
+	defer exit.RecoverAll()
+	defer logutil.Flush()
+
+	flag.Parse()
+	args := flag.Args()
+	if len(args) != 0 {
+		flag.Usage()
+		log.Exitf("topo2backup doesn't take any parameter.")
+	}
+
+	ts := topo.Open()
+	defer ts.Close()
+
+	b, err := helpers.BackupTopo(context.Background(), ts)
+	if err != nil {
+		log.Exitf("Cannot backup the topology: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Exitf("Cannot create %v: %v", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := helpers.WriteBackup(w, b); err != nil {
+		log.Exitf("Cannot write the backup: %v", err)
+	}
+	for _, cb := range b.Cells {
+		log.Infof("Backed up %v files of cell %v", len(cb.Files), cb.Cell)
+	}
+}
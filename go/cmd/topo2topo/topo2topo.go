@@ -40,6 +40,9 @@ var (
 	doShards            = flag.Bool("do-shards", false, "copies the shard information")
 	doShardReplications = flag.Bool("do-shard-replications", false, "copies the shard replication information")
 	doTablets           = flag.Bool("do-tablets", false, "copies the tablet information")
+	doServingGraph      = flag.Bool("do-serving-graph", false, "copies the serving graph (SrvKeyspace and SrvVSchema) of each cell")
+
+	validate = flag.Bool("validate", false, "after the copy, checks that the records of both topologies are the same")
 )
 
 func main() {
@@ -76,4 +79,14 @@ func main() {
 	if *doTablets {
 		helpers.CopyTablets(ctx, fromTS, toTS)
 	}
+	if *doServingGraph {
+		helpers.CopyServingGraph(ctx, fromTS, toTS)
+	}
+
+	if *validate {
+		if err := helpers.CompareTopos(ctx, fromTS, toTS); err != nil {
+			log.Exitf("The topologies differ: %v", err)
+		}
+		log.Infof("The topologies are the same.")
+	}
 }
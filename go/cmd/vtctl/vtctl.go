@@ -19,15 +19,18 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log/syslog"
 	"os"
 	"os/signal"
+	"os/user"
 	"strings"
 	"syscall"
 	"time"
 
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/exit"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/servenv"
@@ -39,7 +42,11 @@ import (
 )
 
 var (
-	waitTime = flag.Duration("wait-time", 24*time.Hour, "time to wait on an action")
+	waitTime   = flag.Duration("wait-time", 24*time.Hour, "time to wait on an action")
+	jsonOutput = flag.Bool("json", false, "print the results of the command as JSON instead of human-readable text")
+
+	batch           = flag.String("batch", "", "file to read commands from, one per line, instead of the command line. Use '-' for stdin. All commands run in this process and share its topology connections, and -wait-time applies to the whole batch")
+	continueOnError = flag.Bool("continue_on_error", false, "in -batch mode, keep running the following commands when one fails")
 )
 
 func init() {
@@ -47,6 +54,7 @@ func init() {
 	flag.CommandLine.SetOutput(logutil.NewLoggerWriter(logger))
 	flag.Usage = func() {
 		logger.Printf("Usage: %s [global parameters] command [command parameters]\n", os.Args[0])
+		logger.Printf("   or: %s [global parameters] -batch <file>\n", os.Args[0])
 		logger.Printf("\nThe global optional parameters are:\n")
 		flag.PrintDefaults()
 		logger.Printf("\nThe commands are listed below, sorted by group. Use '%s <command> -h' for more help.\n\n", os.Args[0])
@@ -65,12 +73,59 @@ func installSignalHandlers(cancel func()) {
 	}()
 }
 
+// parseFlags parses the command line. It returns either the command
+// to run, or the commands of the batch in -batch mode.
+// It is servenv.ParseFlagsWithArgs, except that -batch mode takes no
+// positional arguments.
+func parseFlags() ([]string, []vtctl.BatchCommand) {
+	flag.Parse()
+
+	if *servenv.Version {
+		servenv.AppVersion.Print()
+		os.Exit(0)
+	}
+
+	args := flag.Args()
+	if *batch == "" {
+		if len(args) == 0 {
+			log.Exitf("vtctl expected at least one positional argument")
+		}
+		return args, nil
+	}
+	if len(args) > 0 {
+		flag.Usage()
+		log.Exitf("vtctl doesn't take a command in -batch mode, got '%s'", strings.Join(args, " "))
+	}
+
+	var r io.Reader = os.Stdin
+	if *batch != "-" {
+		f, err := os.Open(*batch)
+		if err != nil {
+			log.Exitf("cannot open batch file: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	commands, err := vtctl.ParseBatch(r)
+	if err != nil {
+		log.Exitf("cannot parse batch file %v: %v", *batch, err)
+	}
+	if commands == nil {
+		// An empty batch is still a batch.
+		commands = []vtctl.BatchCommand{}
+	}
+	return nil, commands
+}
+
 func main() {
 	defer exit.RecoverAll()
 	defer logutil.Flush()
 
-	args := servenv.ParseFlagsWithArgs("vtctl")
-	action := args[0]
+	args, commands := parseFlags()
+	action := "batch"
+	if commands == nil {
+		action = args[0]
+	}
 
 	startMsg := fmt.Sprintf("USER=%v SUDO_USER=%v %v", os.Getenv("USER"), os.Getenv("SUDO_USER"), strings.Join(os.Args, " "))
 
@@ -88,10 +143,25 @@ func main() {
 	vtctl.WorkflowManager = workflow.NewManager(ts)
 
 	ctx, cancel := context.WithTimeout(context.Background(), *waitTime)
+	if *jsonOutput {
+		ctx = vtctl.WithJSONOutput(ctx)
+	}
+	// vtctl talks to the topology directly, so the caller is the
+	// local user running it.
+	if u, err := user.Current(); err == nil {
+		ctx = callerid.NewContext(ctx, nil, callerid.NewImmediateCallerID(u.Username))
+	} else {
+		log.Warningf("cannot find the current user: %v", err)
+	}
 	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
 	installSignalHandlers(cancel)
 
-	err := vtctl.RunCommand(ctx, wr, args)
+	var err error
+	if commands != nil {
+		err = vtctl.RunBatch(ctx, wr, commands, *continueOnError)
+	} else {
+		err = vtctl.RunCommand(ctx, wr, args)
+	}
 	cancel()
 	switch err {
 	case vtctl.ErrUnknownCommand:
@@ -36,6 +36,7 @@ import (
 var (
 	actionTimeout = flag.Duration("action_timeout", time.Hour, "timeout for the total command")
 	server        = flag.String("server", "", "server to use for connection")
+	jsonOutput    = flag.Bool("json", false, "print the results of the command as JSON instead of human-readable text")
 )
 
 func main() {
@@ -54,8 +55,14 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), *actionTimeout)
 	defer cancel()
 
+	// The output mode is passed to vtctld in front of the command.
+	args := flag.Args()
+	if *jsonOutput {
+		args = append([]string{"-json"}, args...)
+	}
+
 	err := vtctlclient.RunCommandAndWait(
-		ctx, *server, flag.Args(),
+		ctx, *server, args,
 		func(e *logutilpb.Event) {
 			logutil.LogEvent(logger, e)
 		})
@@ -21,6 +21,7 @@ import (
 	"flag"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/mysqlctl"
@@ -28,6 +29,7 @@ import (
 	"vitess.io/vitess/go/vt/tableacl"
 	"vitess.io/vitess/go/vt/tableacl/simpleacl"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver"
@@ -39,11 +41,15 @@ var (
 	tableACLConfig        = flag.String("table-acl-config", "", "path to table access checker config file; send SIGHUP to reload this file")
 	tabletPath            = flag.String("tablet-path", "", "tablet alias")
 
+	topoCacheTTLs               flagutil.StringMapValue
+	topoCacheMaxConcurrentReads = flag.Int("topo_cache_max_concurrent_reads", 0, "maximum number of concurrent reads sent to each topo cell when -topo_cache_ttls is set, 0 for no limit")
+
 	agent *tabletmanager.ActionAgent
 )
 
 func init() {
 	servenv.RegisterDefaultFlags()
+	flag.Var(&topoCacheTTLs, "topo_cache_ttls", "comma-separated list of record_type:ttl, like Tablet:1s,SrvKeyspace:10s. If set, the records of these types are cached for that long, and concurrent reads of the same record are coalesced, so many tablets restarting at once don't overload the topo service")
 }
 
 func main() {
@@ -104,6 +110,19 @@ func main() {
 
 	// creates and registers the query service
 	ts := topo.Open()
+	if len(topoCacheTTLs) > 0 {
+		ttls, err := helpers.ParseCacheTTLs(topoCacheTTLs)
+		if err != nil {
+			log.Exitf("invalid -topo_cache_ttls: %v", err)
+		}
+		ts, err = helpers.NewCache(ts, helpers.CacheConfig{
+			TTLs:               ttls,
+			MaxConcurrentReads: *topoCacheMaxConcurrentReads,
+		})
+		if err != nil {
+			log.Exitf("cannot create the topo cache: %v", err)
+		}
+	}
 	qsc := tabletserver.NewServer(ts, *tabletAlias)
 	servenv.OnRun(func() {
 		qsc.Register()
@@ -83,10 +83,12 @@ func main() {
 	wi = worker.NewInstance(ts, *cell, *commandDisplayInterval)
 	wi.InstallSignalHandlers()
 	wi.InitStatusHandling()
+	wi.InitMetrics()
 
 	if len(args) == 0 {
 		// In interactive mode, initialize the web UI to choose a command.
 		wi.InitInteractiveMode()
+		wi.InitJobHandling()
 	} else {
 		// In single command mode, just run it.
 		ctx := context.Background()
@@ -143,6 +143,14 @@ type Conn struct {
 	// fields, this is set to an empty array (but not nil).
 	fields []*querypb.Field
 
+	// preparedStatements are the prepared statements of the
+	// connection, indexed by statement ID. It is only used by the
+	// server, see prepared_statement.go.
+	preparedStatements map[uint32]*preparedStatement
+
+	// lastStatementID is the ID of the last prepared statement.
+	lastStatementID uint32
+
 	// Keep track of how and of the buffer we allocated for an
 	// ephemeral packet on the read and write sides.
 	// These fields are used by:
@@ -715,83 +723,54 @@ func (c *Conn) handleNextCommand(handler Handler) error {
 			return err
 		}
 	case ComQuery:
-		// flush is called at the end of this block.
-		// We cannot encapsulate it with a defer inside a func because
-		// we have to return from this func if it fails.
-		c.startWriterBuffering()
-
-		queryStart := time.Now()
 		query := c.parseComQuery(data)
 		c.recycleReadPacket()
-		fieldSent := false
-		// sendFinished is set if the response should just be an OK packet.
-		sendFinished := false
-
-		err := handler.ComQuery(c, query, func(qr *sqltypes.Result) error {
-			if sendFinished {
-				// Failsafe: Unreachable if server is well-behaved.
-				return io.EOF
-			}
-
-			if !fieldSent {
-				fieldSent = true
-
-				if len(qr.Fields) == 0 {
-					sendFinished = true
-
-					// A successful callback with no fields means that this was a
-					// DML or other write-only operation.
-					//
-					// We should not send any more packets after this, but make sure
-					// to extract the affected rows and last insert id from the result
-					// struct here since clients expect it.
-					return c.writeOKPacket(qr.RowsAffected, qr.InsertID, c.StatusFlags, handler.WarningCount(c))
-				}
-				if err := c.writeFields(qr); err != nil {
-					return err
-				}
-			}
-
-			return c.writeRows(qr)
-		})
+		if err := c.execQuery(handler, query, false /*binary*/); err != nil {
+			return err
+		}
 
-		// If no field was sent, we expect an error.
-		if !fieldSent {
-			// This is just a failsafe. Should never happen.
-			if err == nil || err == io.EOF {
-				err = NewSQLErrorFromError(errors.New("unexpected: query ended without no results and no error"))
-			}
+	case ComStmtPrepare:
+		query := c.parseComStmtPrepare(data)
+		c.recycleReadPacket()
+		if err := c.handleComStmtPrepare(query); err != nil {
+			return err
+		}
+	case ComStmtExecute:
+		query, err := c.parseComStmtExecute(data)
+		c.recycleReadPacket()
+		if err != nil {
 			if werr := c.writeErrorPacketFromError(err); werr != nil {
-				// If we can't even write the error, we're done.
-				log.Errorf("Error writing query error to %s: %v", c, werr)
+				log.Errorf("Error writing ComStmtExecute error to %s: %v", c, werr)
 				return werr
 			}
-		} else {
-			if err != nil {
-				// We can't send an error in the middle of a stream.
-				// All we can do is abort the send, which will cause a 2013.
-				log.Errorf("Error in the middle of a stream to %s: %v", c, err)
-				return err
-			}
-
-			// Send the end packet only sendFinished is false (results were streamed).
-			// In this case the affectedRows and lastInsertID are always 0 since it
-			// was a read operation.
-			if !sendFinished {
-				if err := c.writeEndResult(false, 0, 0, handler.WarningCount(c)); err != nil {
-					log.Errorf("Error writing result to %s: %v", c, err)
-					return err
-				}
+			return nil
+		}
+		if err := c.execQuery(handler, query, true /*binary*/); err != nil {
+			return err
+		}
+	case ComStmtSendLongData:
+		// There is no response to COM_STMT_SEND_LONG_DATA, errors
+		// are returned by the next COM_STMT_EXECUTE.
+		c.handleComStmtSendLongData(data)
+		c.recycleReadPacket()
+	case ComStmtClose:
+		// There is no response to COM_STMT_CLOSE.
+		c.handleComStmtClose(data)
+		c.recycleReadPacket()
+	case ComStmtReset:
+		err := c.handleComStmtReset(data)
+		c.recycleReadPacket()
+		if err != nil {
+			if werr := c.writeErrorPacketFromError(err); werr != nil {
+				log.Errorf("Error writing ComStmtReset error to %s: %v", c, werr)
+				return werr
 			}
+			return nil
 		}
-
-		timings.Record(queryTimingKey, queryStart)
-
-		if err := c.flush(); err != nil {
-			log.Errorf("Conn %v: Flush() failed: %v", c.ID(), err)
+		if err := c.writeOKPacket(0, 0, c.StatusFlags, 0); err != nil {
+			log.Errorf("Error writing ComStmtReset result to %s: %v", c, err)
 			return err
 		}
-
 	case ComPing:
 		c.recycleReadPacket()
 		// Return error if listener was shut down and OK otherwise
@@ -843,6 +822,94 @@ func (c *Conn) handleNextCommand(handler Handler) error {
 	return nil
 }
 
+// execQuery runs a query through the handler, and sends its result.
+// With binary set, the rows are sent with the binary protocol, as
+// expected by COM_STMT_EXECUTE. It only returns an error if the
+// connection should be closed.
+func (c *Conn) execQuery(handler Handler, query string, binary bool) error {
+	// flush is called at the end of this function.
+	// We cannot encapsulate it with a defer inside a func because
+	// we have to return from this func if it fails.
+	c.startWriterBuffering()
+
+	queryStart := time.Now()
+	fieldSent := false
+	// fields are the fields of the result, to encode binary rows.
+	var fields []*querypb.Field
+	// sendFinished is set if the response should just be an OK packet.
+	sendFinished := false
+
+	err := handler.ComQuery(c, query, func(qr *sqltypes.Result) error {
+		if sendFinished {
+			// Failsafe: Unreachable if server is well-behaved.
+			return io.EOF
+		}
+
+		if !fieldSent {
+			fieldSent = true
+			fields = qr.Fields
+
+			if len(qr.Fields) == 0 {
+				sendFinished = true
+
+				// A successful callback with no fields means that this was a
+				// DML or other write-only operation.
+				//
+				// We should not send any more packets after this, but make sure
+				// to extract the affected rows and last insert id from the result
+				// struct here since clients expect it.
+				return c.writeOKPacket(qr.RowsAffected, qr.InsertID, c.StatusFlags, handler.WarningCount(c))
+			}
+			if err := c.writeFields(qr); err != nil {
+				return err
+			}
+		}
+
+		if binary {
+			return c.writeBinaryRows(fields, qr)
+		}
+		return c.writeRows(qr)
+	})
+
+	// If no field was sent, we expect an error.
+	if !fieldSent {
+		// This is just a failsafe. Should never happen.
+		if err == nil || err == io.EOF {
+			err = NewSQLErrorFromError(errors.New("unexpected: query ended without no results and no error"))
+		}
+		if werr := c.writeErrorPacketFromError(err); werr != nil {
+			// If we can't even write the error, we're done.
+			log.Errorf("Error writing query error to %s: %v", c, werr)
+			return werr
+		}
+	} else {
+		if err != nil {
+			// We can't send an error in the middle of a stream.
+			// All we can do is abort the send, which will cause a 2013.
+			log.Errorf("Error in the middle of a stream to %s: %v", c, err)
+			return err
+		}
+
+		// Send the end packet only sendFinished is false (results were streamed).
+		// In this case the affectedRows and lastInsertID are always 0 since it
+		// was a read operation.
+		if !sendFinished {
+			if err := c.writeEndResult(false, 0, 0, handler.WarningCount(c)); err != nil {
+				log.Errorf("Error writing result to %s: %v", c, err)
+				return err
+			}
+		}
+	}
+
+	timings.Record(queryTimingKey, queryStart)
+
+	if err := c.flush(); err != nil {
+		log.Errorf("Conn %v: Flush() failed: %v", c.ID(), err)
+		return err
+	}
+	return nil
+}
+
 //
 // Packet parsing methods, for generic packets.
 //
@@ -153,6 +153,21 @@ const (
 	// ComBinlogDump is COM_BINLOG_DUMP.
 	ComBinlogDump = 0x12
 
+	// ComStmtPrepare is COM_STMT_PREPARE.
+	ComStmtPrepare = 0x16
+
+	// ComStmtExecute is COM_STMT_EXECUTE.
+	ComStmtExecute = 0x17
+
+	// ComStmtSendLongData is COM_STMT_SEND_LONG_DATA.
+	ComStmtSendLongData = 0x18
+
+	// ComStmtClose is COM_STMT_CLOSE.
+	ComStmtClose = 0x19
+
+	// ComStmtReset is COM_STMT_RESET.
+	ComStmtReset = 0x1a
+
 	// ComSetOption is COM_SET_OPTION
 	ComSetOption = 0x1b
 
@@ -246,19 +261,20 @@ const (
 	ERNotSupportedYet = 1235
 
 	// resource exhausted
-	ERDiskFull               = 1021
-	EROutOfMemory            = 1037
-	EROutOfSortMemory        = 1038
-	ERConCount               = 1040
-	EROutOfResources         = 1041
-	ERRecordFileFull         = 1114
-	ERHostIsBlocked          = 1129
-	ERCantCreateThread       = 1135
-	ERTooManyDelayedThreads  = 1151
-	ERNetPacketTooLarge      = 1153
-	ERTooManyUserConnections = 1203
-	ERLockTableFull          = 1206
-	ERUserLimitReached       = 1226
+	ERDiskFull                    = 1021
+	EROutOfMemory                 = 1037
+	EROutOfSortMemory             = 1038
+	ERConCount                    = 1040
+	EROutOfResources              = 1041
+	ERRecordFileFull              = 1114
+	ERHostIsBlocked               = 1129
+	ERCantCreateThread            = 1135
+	ERTooManyDelayedThreads       = 1151
+	ERNetPacketTooLarge           = 1153
+	ERTooManyUserConnections      = 1203
+	ERLockTableFull               = 1206
+	ERUserLimitReached            = 1226
+	ERMaxPreparedStmtCountReached = 1461
 
 	// deadline exceeded
 	ERLockWaitTimeout = 1205
@@ -401,6 +417,7 @@ const (
 	ERIncorrectGlobalLocalVar      = 1238
 	ERWrongFKDef                   = 1239
 	ERKeyRefDoNotMatchTableRef     = 1240
+	ERUnknownStmtHandler           = 1243
 	ERCyclicReference              = 1245
 	ERCollationCharsetMismatch     = 1253
 	ERCantAggregate2Collations     = 1267
@@ -455,6 +472,9 @@ const (
 	// SSBadFieldError is ER_BAD_FIELD_ERROR
 	SSBadFieldError = "42S22"
 
+	// SSMaxPreparedStmtCountReached is ER_MAX_PREPARED_STMT_COUNT_REACHED
+	SSMaxPreparedStmtCountReached = "42000"
+
 	// SSDupKey is ER_DUP_KEY
 	SSDupKey = "23000"
 
@@ -149,6 +149,25 @@ The following types or constructs are not yet supported by our RBR:
   parse it to re-print a text version for re-insertion. Instead, we
   just return NULL. So JSOn is not supported.
 
+--
+Prepared statements:
+
+See: https://dev.mysql.com/doc/internals/en/prepared-statements.html
+
+The server supports COM_STMT_PREPARE, COM_STMT_EXECUTE,
+COM_STMT_SEND_LONG_DATA, COM_STMT_CLOSE and COM_STMT_RESET, without
+changing the Handler interface: the parameters are substituted as SQL
+literals in the query, which is then run with Handler.ComQuery, and the
+result rows are sent with the binary protocol. vtgate normalizes the
+literals into bind variables, so the plans are still shared.
+
+Not supported:
+- the prepare response doesn't describe the result columns, as they
+  are only known when the query runs. Clients get them from the result
+  of COM_STMT_EXECUTE.
+- cursors (COM_STMT_FETCH). The whole result is sent by
+  COM_STMT_EXECUTE.
+
 Replication Notes:
 ==================
 
@@ -0,0 +1,762 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// This file implements the server side of the prepared statements: the
+// COM_STMT_* commands, and the binary protocol they use.
+//
+// The Handler doesn't know about prepared statements. COM_STMT_PREPARE
+// only remembers the query and finds its '?' placeholders. Then
+// COM_STMT_EXECUTE substitutes the parameters in the query, as SQL
+// literals, and runs it with Handler.ComQuery. The result is sent with
+// the binary protocol.
+//
+// The response to COM_STMT_PREPARE doesn't describe the result
+// columns, as they are only known when the query runs. Clients get
+// them with the result of COM_STMT_EXECUTE.
+
+// maxPreparedStatements is the maximum number of prepared statements a
+// connection can have open at the same time, like the
+// max_prepared_stmt_count MySQL variable.
+var maxPreparedStatements = 16382
+
+// preparedStatement is a statement prepared with COM_STMT_PREPARE.
+type preparedStatement struct {
+	// query is the query, with '?' placeholders.
+	query string
+
+	// paramOffsets are the positions of the placeholders in query.
+	paramOffsets []int
+
+	// paramTypes are the types of the parameters, as sent by the
+	// client. COM_STMT_EXECUTE only sends them when they change.
+	paramTypes []uint16
+
+	// longData are the parameter values sent with
+	// COM_STMT_SEND_LONG_DATA for the next execution, indexed by
+	// parameter position.
+	longData map[uint16][]byte
+}
+
+// placeholderOffsets returns the positions of the '?' placeholders of
+// a query. It skips the quoted strings and identifiers, and the
+// comments, except the /*! ... */ ones which MySQL executes.
+func placeholderOffsets(query string) []int {
+	var offsets []int
+	for i := 0; i < len(query); i++ {
+		switch ch := query[i]; ch {
+		case '?':
+			offsets = append(offsets, i)
+		case '\'', '"', '`':
+			// A doubled quote ends the string and starts a new one,
+			// which has the same effect.
+			for i++; i < len(query) && query[i] != ch; i++ {
+				if query[i] == '\\' && ch != '`' {
+					i++
+				}
+			}
+		case '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case '-':
+			if strings.HasPrefix(query[i:], "-- ") || strings.HasPrefix(query[i:], "--\t") || strings.HasPrefix(query[i:], "--\n") || query[i:] == "--" {
+				for i < len(query) && query[i] != '\n' {
+					i++
+				}
+			}
+		case '/':
+			if strings.HasPrefix(query[i:], "/*") && !strings.HasPrefix(query[i:], "/*!") {
+				end := strings.Index(query[i+2:], "*/")
+				if end < 0 {
+					return offsets
+				}
+				i += 2 + end + 1
+			}
+		}
+	}
+	return offsets
+}
+
+func (c *Conn) parseComStmtPrepare(data []byte) string {
+	return string(data[1:])
+}
+
+// handleComStmtPrepare prepares a statement, and sends the response.
+// It only returns an error if the connection should be closed.
+func (c *Conn) handleComStmtPrepare(query string) error {
+	offsets := placeholderOffsets(query)
+	if len(offsets) > math.MaxUint16 {
+		if err := c.writeErrorPacket(ERUnknownError, SSUnknownSQLState, "too many placeholders in prepared statement: %v", len(offsets)); err != nil {
+			log.Errorf("Error writing ComStmtPrepare error to %s: %v", c, err)
+			return err
+		}
+		return nil
+	}
+	if len(c.preparedStatements) >= maxPreparedStatements {
+		if err := c.writeErrorPacket(ERMaxPreparedStmtCountReached, SSMaxPreparedStmtCountReached, "Can't create more than max_prepared_stmt_count statements (current value: %v)", maxPreparedStatements); err != nil {
+			log.Errorf("Error writing ComStmtPrepare error to %s: %v", c, err)
+			return err
+		}
+		return nil
+	}
+
+	if c.preparedStatements == nil {
+		c.preparedStatements = make(map[uint32]*preparedStatement)
+	}
+	c.lastStatementID++
+	c.preparedStatements[c.lastStatementID] = &preparedStatement{
+		query:        query,
+		paramOffsets: offsets,
+		longData:     make(map[uint16][]byte),
+	}
+
+	c.startWriterBuffering()
+	if err := c.writePrepareOK(c.lastStatementID, len(offsets)); err != nil {
+		log.Errorf("Error writing ComStmtPrepare result to %s: %v", c, err)
+		return err
+	}
+	if err := c.flush(); err != nil {
+		log.Errorf("Conn %v: Flush() failed: %v", c.ID(), err)
+		return err
+	}
+	return nil
+}
+
+// writePrepareOK writes the COM_STMT_PREPARE_OK packet, followed by the
+// definitions of the parameters. It doesn't describe the columns.
+func (c *Conn) writePrepareOK(statementID uint32, params int) error {
+	data := c.startEphemeralPacket(12)
+	pos := 0
+	pos = writeByte(data, pos, OKPacket)
+	pos = writeUint32(data, pos, statementID)
+	pos = writeUint16(data, pos, 0) // number of columns
+	pos = writeUint16(data, pos, uint16(params))
+	pos = writeByte(data, pos, 0)   // filler
+	pos = writeUint16(data, pos, 0) // warning count
+	if err := c.writeEphemeralPacket(); err != nil {
+		return err
+	}
+
+	if params == 0 {
+		return nil
+	}
+	for i := 0; i < params; i++ {
+		if err := c.writeColumnDefinition(&querypb.Field{
+			Name:    "?",
+			Type:    sqltypes.VarBinary,
+			Charset: CharacterSetBinary,
+		}); err != nil {
+			return err
+		}
+	}
+	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
+		return c.writeEOFPacket(c.StatusFlags, 0)
+	}
+	return nil
+}
+
+// parseComStmtExecute returns the query to run for a COM_STMT_EXECUTE
+// packet, with the parameters substituted. The long data of the
+// statement is consumed.
+func (c *Conn) parseComStmtExecute(data []byte) (string, error) {
+	stmtID, pos, ok := readUint32(data, 1)
+	if !ok {
+		return "", NewSQLError(ERWrongArguments, SSUnknownSQLState, "Incorrect arguments to mysqld_stmt_execute")
+	}
+	stmt, ok := c.preparedStatements[stmtID]
+	if !ok {
+		return "", NewSQLError(ERUnknownStmtHandler, SSUnknownSQLState, "Unknown prepared statement handler (%v) given to mysqld_stmt_execute", stmtID)
+	}
+	if len(stmt.paramOffsets) == 0 {
+		return stmt.query, nil
+	}
+	longData := stmt.longData
+	stmt.longData = make(map[uint16][]byte)
+
+	query, ok := stmt.bindParams(data, pos, longData)
+	if !ok {
+		return "", NewSQLError(ERWrongArguments, SSUnknownSQLState, "Incorrect arguments to mysqld_stmt_execute")
+	}
+	return query, nil
+}
+
+// bindParams reads the parameters of a COM_STMT_EXECUTE packet, after
+// the statement ID, and substitutes them in the query.
+func (stmt *preparedStatement) bindParams(data []byte, pos int, longData map[uint16][]byte) (string, bool) {
+	// Skip the flags (the cursor type, we don't support cursors) and
+	// the iteration count (always 1).
+	pos += 5
+
+	params := len(stmt.paramOffsets)
+	nullBitmap, pos, ok := readBytes(data, pos, (params+7)/8)
+	if !ok {
+		return "", false
+	}
+	newParamsBound, pos, ok := readByte(data, pos)
+	if !ok {
+		return "", false
+	}
+	if newParamsBound == 1 {
+		types := make([]uint16, params)
+		for i := range types {
+			types[i], pos, ok = readUint16(data, pos)
+			if !ok {
+				return "", false
+			}
+		}
+		stmt.paramTypes = types
+	}
+	if stmt.paramTypes == nil {
+		return "", false
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(stmt.query)+len(data)))
+	last := 0
+	for i, offset := range stmt.paramOffsets {
+		buf.WriteString(stmt.query[last:offset])
+		last = offset + 1
+
+		if value, ok := longData[uint16(i)]; ok {
+			// Parameters sent as long data are not in the packet.
+			sqltypes.MakeTrusted(sqltypes.VarBinary, value).EncodeSQL(buf)
+			continue
+		}
+		if nullBitmap[i/8]&(1<<uint(i%8)) != 0 {
+			buf.WriteString("null")
+			continue
+		}
+		pos, ok = appendBinaryParam(buf, data, pos, stmt.paramTypes[i])
+		if !ok {
+			return "", false
+		}
+	}
+	buf.WriteString(stmt.query[last:])
+	return buf.String(), true
+}
+
+// appendBinaryParam reads a parameter value at pos of data, encoded
+// with the binary protocol, and appends it to buf as an SQL literal.
+func appendBinaryParam(buf *bytes.Buffer, data []byte, pos int, typ uint16) (int, bool) {
+	// The high byte of the type has the unsigned flag.
+	unsigned := typ&0x8000 != 0
+	var ok bool
+	switch typ & 0xff {
+	case TypeNull:
+		buf.WriteString("null")
+	case TypeTiny:
+		var val byte
+		if val, pos, ok = readByte(data, pos); !ok {
+			return 0, false
+		}
+		if unsigned {
+			buf.WriteString(strconv.FormatUint(uint64(val), 10))
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(int8(val)), 10))
+		}
+	case TypeShort, TypeYear:
+		var val uint16
+		if val, pos, ok = readUint16(data, pos); !ok {
+			return 0, false
+		}
+		if unsigned {
+			buf.WriteString(strconv.FormatUint(uint64(val), 10))
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(int16(val)), 10))
+		}
+	case TypeLong, TypeInt24:
+		var val uint32
+		if val, pos, ok = readUint32(data, pos); !ok {
+			return 0, false
+		}
+		if unsigned {
+			buf.WriteString(strconv.FormatUint(uint64(val), 10))
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(int32(val)), 10))
+		}
+	case TypeLongLong:
+		var val uint64
+		if val, pos, ok = readUint64(data, pos); !ok {
+			return 0, false
+		}
+		if unsigned {
+			buf.WriteString(strconv.FormatUint(val, 10))
+		} else {
+			buf.WriteString(strconv.FormatInt(int64(val), 10))
+		}
+	case TypeFloat:
+		var val uint32
+		if val, pos, ok = readUint32(data, pos); !ok {
+			return 0, false
+		}
+		buf.WriteString(strconv.FormatFloat(float64(math.Float32frombits(val)), 'g', -1, 32))
+	case TypeDouble:
+		var val uint64
+		if val, pos, ok = readUint64(data, pos); !ok {
+			return 0, false
+		}
+		buf.WriteString(strconv.FormatFloat(math.Float64frombits(val), 'g', -1, 64))
+	case TypeDate, TypeDateTime, TypeTimestamp:
+		var length byte
+		var val []byte
+		if length, pos, ok = readByte(data, pos); !ok {
+			return 0, false
+		}
+		if val, pos, ok = readBytes(data, pos, int(length)); !ok {
+			return 0, false
+		}
+		s, ok := formatBinaryDateTime(val, typ&0xff == TypeDate)
+		if !ok {
+			return 0, false
+		}
+		buf.WriteByte('\'')
+		buf.WriteString(s)
+		buf.WriteByte('\'')
+	case TypeTime:
+		var length byte
+		var val []byte
+		if length, pos, ok = readByte(data, pos); !ok {
+			return 0, false
+		}
+		if val, pos, ok = readBytes(data, pos, int(length)); !ok {
+			return 0, false
+		}
+		s, ok := formatBinaryTime(val)
+		if !ok {
+			return 0, false
+		}
+		buf.WriteByte('\'')
+		buf.WriteString(s)
+		buf.WriteByte('\'')
+	case TypeDecimal, TypeNewDecimal:
+		var val []byte
+		if val, pos, ok = readLenEncStringAsBytes(data, pos); !ok {
+			return 0, false
+		}
+		if isDecimal(val) {
+			buf.Write(val)
+		} else {
+			sqltypes.MakeTrusted(sqltypes.VarBinary, val).EncodeSQL(buf)
+		}
+	default:
+		// All the other types are sent as strings.
+		var val []byte
+		if val, pos, ok = readLenEncStringAsBytes(data, pos); !ok {
+			return 0, false
+		}
+		sqltypes.MakeTrusted(sqltypes.VarBinary, val).EncodeSQL(buf)
+	}
+	return pos, true
+}
+
+// isDecimal returns true if val is a number, which can be used as an
+// SQL literal as is. It only accepts an optional sign, digits with an
+// optional fraction, and an optional exponent, so nothing else in the
+// value can end up in the query.
+func isDecimal(val []byte) bool {
+	pos := 0
+	if pos < len(val) && (val[pos] == '-' || val[pos] == '+') {
+		pos++
+	}
+	digits := skipDigits(val, pos)
+	pos += digits
+	if pos < len(val) && val[pos] == '.' {
+		pos++
+		fraction := skipDigits(val, pos)
+		pos += fraction
+		digits += fraction
+	}
+	if digits == 0 {
+		return false
+	}
+	if pos < len(val) && (val[pos] == 'e' || val[pos] == 'E') {
+		pos++
+		if pos < len(val) && (val[pos] == '-' || val[pos] == '+') {
+			pos++
+		}
+		exponent := skipDigits(val, pos)
+		if exponent == 0 {
+			return false
+		}
+		pos += exponent
+	}
+	return pos == len(val)
+}
+
+// skipDigits returns the number of decimal digits in val, starting
+// at pos.
+func skipDigits(val []byte, pos int) int {
+	n := 0
+	for pos+n < len(val) && val[pos+n] >= '0' && val[pos+n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// formatBinaryDateTime formats a DATE, DATETIME or TIMESTAMP value
+// encoded with the binary protocol (without the length byte).
+func formatBinaryDateTime(val []byte, dateOnly bool) (string, bool) {
+	var year uint16
+	var month, day, hour, minute, second byte
+	var micro uint32
+	switch len(val) {
+	case 0:
+	case 4, 7, 11:
+		year, _, _ = readUint16(val, 0)
+		month, day = val[2], val[3]
+		if len(val) >= 7 {
+			hour, minute, second = val[4], val[5], val[6]
+		}
+		if len(val) == 11 {
+			micro, _, _ = readUint32(val, 7)
+		}
+	default:
+		return "", false
+	}
+	if dateOnly {
+		return fmt.Sprintf("%04d-%02d-%02d", year, month, day), true
+	}
+	s := fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", year, month, day, hour, minute, second)
+	if micro != 0 {
+		s += fmt.Sprintf(".%06d", micro)
+	}
+	return s, true
+}
+
+// formatBinaryTime formats a TIME value encoded with the binary
+// protocol (without the length byte).
+func formatBinaryTime(val []byte) (string, bool) {
+	var negative, hour, minute, second byte
+	var days, micro uint32
+	switch len(val) {
+	case 0:
+	case 8, 12:
+		negative = val[0]
+		days, _, _ = readUint32(val, 1)
+		hour, minute, second = val[5], val[6], val[7]
+		if len(val) == 12 {
+			micro, _, _ = readUint32(val, 8)
+		}
+	default:
+		return "", false
+	}
+	sign := ""
+	if negative == 1 {
+		sign = "-"
+	}
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, days*24+uint32(hour), minute, second)
+	if micro != 0 {
+		s += fmt.Sprintf(".%06d", micro)
+	}
+	return s, true
+}
+
+// handleComStmtSendLongData appends the data of a
+// COM_STMT_SEND_LONG_DATA packet to a parameter of the statement.
+// Invalid packets are ignored, as the protocol has no response.
+func (c *Conn) handleComStmtSendLongData(data []byte) {
+	stmtID, pos, ok := readUint32(data, 1)
+	if !ok {
+		return
+	}
+	paramID, pos, ok := readUint16(data, pos)
+	if !ok {
+		return
+	}
+	stmt, ok := c.preparedStatements[stmtID]
+	if !ok || int(paramID) >= len(stmt.paramOffsets) {
+		return
+	}
+	stmt.longData[paramID] = append(stmt.longData[paramID], data[pos:]...)
+}
+
+// handleComStmtClose deallocates a statement.
+func (c *Conn) handleComStmtClose(data []byte) {
+	if stmtID, _, ok := readUint32(data, 1); ok {
+		delete(c.preparedStatements, stmtID)
+	}
+}
+
+// handleComStmtReset discards the long data of a statement.
+func (c *Conn) handleComStmtReset(data []byte) error {
+	stmtID, _, ok := readUint32(data, 1)
+	if !ok {
+		return NewSQLError(ERWrongArguments, SSUnknownSQLState, "Incorrect arguments to mysqld_stmt_reset")
+	}
+	stmt, ok := c.preparedStatements[stmtID]
+	if !ok {
+		return NewSQLError(ERUnknownStmtHandler, SSUnknownSQLState, "Unknown prepared statement handler (%v) given to mysqld_stmt_reset", stmtID)
+	}
+	stmt.longData = make(map[uint16][]byte)
+	return nil
+}
+
+// writeBinaryRows sends the rows of a Result with the binary protocol.
+// fields are the fields of the result, which may only be set in the
+// first Result of a stream.
+func (c *Conn) writeBinaryRows(fields []*querypb.Field, result *sqltypes.Result) error {
+	for _, row := range result.Rows {
+		if err := c.writeBinaryRow(fields, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Conn) writeBinaryRow(fields []*querypb.Field, row []sqltypes.Value) error {
+	// The NULL bitmap of a row starts at the third bit.
+	nullBitmap := make([]byte, (len(row)+7+2)/8)
+	var values []byte
+	for i, val := range row {
+		if val.IsNull() {
+			nullBitmap[(i+2)/8] |= 1 << uint((i+2)%8)
+			continue
+		}
+		typ := val.Type()
+		if i < len(fields) {
+			typ = fields[i].Type
+		}
+		var err error
+		if values, err = appendBinaryValue(values, typ, val.Raw()); err != nil {
+			return err
+		}
+	}
+
+	length := 1 + len(nullBitmap) + len(values)
+	data := c.startEphemeralPacket(length)
+	pos := 0
+	pos = writeByte(data, pos, OKPacket)
+	pos += copy(data[pos:], nullBitmap)
+	pos += copy(data[pos:], values)
+	if pos != length {
+		return fmt.Errorf("internal error packet binary row: got %v bytes but expected %v", pos, length)
+	}
+
+	return c.writeEphemeralPacket()
+}
+
+// appendBinaryValue appends a value of type typ to buf, encoded with
+// the binary protocol.
+func appendBinaryValue(buf []byte, typ querypb.Type, val []byte) ([]byte, error) {
+	switch typ {
+	case sqltypes.Int8, sqltypes.Int16, sqltypes.Int24, sqltypes.Int32, sqltypes.Int64:
+		v, err := strconv.ParseInt(string(val), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return appendUint(buf, uint64(v), binaryIntSize(typ)), nil
+	case sqltypes.Uint8, sqltypes.Uint16, sqltypes.Uint24, sqltypes.Uint32, sqltypes.Uint64, sqltypes.Year:
+		v, err := strconv.ParseUint(string(val), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return appendUint(buf, v, binaryIntSize(typ)), nil
+	case sqltypes.Float32:
+		v, err := strconv.ParseFloat(string(val), 32)
+		if err != nil {
+			return nil, err
+		}
+		return appendUint(buf, uint64(math.Float32bits(float32(v))), 4), nil
+	case sqltypes.Float64:
+		v, err := strconv.ParseFloat(string(val), 64)
+		if err != nil {
+			return nil, err
+		}
+		return appendUint(buf, math.Float64bits(v), 8), nil
+	case sqltypes.Date, sqltypes.Datetime, sqltypes.Timestamp:
+		return appendBinaryDateTime(buf, string(val))
+	case sqltypes.Time:
+		return appendBinaryTime(buf, string(val))
+	default:
+		start := len(buf)
+		buf = append(buf, make([]byte, lenEncIntSize(uint64(len(val))))...)
+		writeLenEncInt(buf, start, uint64(len(val)))
+		return append(buf, val...), nil
+	}
+}
+
+// binaryIntSize returns the size of an integer type in the binary
+// protocol.
+func binaryIntSize(typ querypb.Type) int {
+	switch typ {
+	case sqltypes.Int8, sqltypes.Uint8:
+		return 1
+	case sqltypes.Int16, sqltypes.Uint16, sqltypes.Year:
+		return 2
+	case sqltypes.Int24, sqltypes.Uint24, sqltypes.Int32, sqltypes.Uint32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// appendUint appends the size low bytes of v to buf, little endian.
+func appendUint(buf []byte, v uint64, size int) []byte {
+	for i := 0; i < size; i++ {
+		buf = append(buf, byte(v>>(8*uint(i))))
+	}
+	return buf
+}
+
+// parseDigits parses an unsigned decimal number.
+func parseDigits(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	result := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		result = result*10 + int(s[i]-'0')
+	}
+	return result, true
+}
+
+// parseMicroseconds parses the fractional part of a time value, after
+// the dot.
+func parseMicroseconds(s string) (int, bool) {
+	if len(s) > 6 {
+		return 0, false
+	}
+	micro, ok := parseDigits(s)
+	for i := len(s); i < 6; i++ {
+		micro *= 10
+	}
+	return micro, ok
+}
+
+// appendBinaryDateTime appends a 'YYYY-MM-DD[ hh:mm:ss[.ffffff]]' value
+// to buf, encoded with the binary protocol.
+func appendBinaryDateTime(buf []byte, val string) ([]byte, error) {
+	var fields [7]int
+	ok := len(val) >= 10 && val[4] == '-' && val[7] == '-'
+	if ok {
+		fields[0], ok = parseDigits(val[0:4])
+	}
+	if ok {
+		fields[1], ok = parseDigits(val[5:7])
+	}
+	if ok {
+		fields[2], ok = parseDigits(val[8:10])
+	}
+	if ok && len(val) > 10 {
+		ok = len(val) >= 19 && val[10] == ' ' && val[13] == ':' && val[16] == ':'
+		if ok {
+			fields[3], ok = parseDigits(val[11:13])
+		}
+		if ok {
+			fields[4], ok = parseDigits(val[14:16])
+		}
+		if ok {
+			fields[5], ok = parseDigits(val[17:19])
+		}
+		if ok && len(val) > 19 {
+			ok = val[19] == '.'
+			if ok {
+				fields[6], ok = parseMicroseconds(val[20:])
+			}
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %q as a binary date", val)
+	}
+
+	var length byte
+	switch {
+	case fields[6] != 0:
+		length = 11
+	case fields[3] != 0 || fields[4] != 0 || fields[5] != 0:
+		length = 7
+	case fields[0] != 0 || fields[1] != 0 || fields[2] != 0:
+		length = 4
+	}
+	buf = append(buf, length)
+	if length >= 4 {
+		buf = appendUint(buf, uint64(fields[0]), 2)
+		buf = append(buf, byte(fields[1]), byte(fields[2]))
+	}
+	if length >= 7 {
+		buf = append(buf, byte(fields[3]), byte(fields[4]), byte(fields[5]))
+	}
+	if length == 11 {
+		buf = appendUint(buf, uint64(fields[6]), 4)
+	}
+	return buf, nil
+}
+
+// appendBinaryTime appends a '[-]hhh:mm:ss[.ffffff]' value to buf,
+// encoded with the binary protocol.
+func appendBinaryTime(buf []byte, val string) ([]byte, error) {
+	var negative byte
+	if strings.HasPrefix(val, "-") {
+		negative = 1
+		val = val[1:]
+	}
+	var hours, minute, second, micro int
+	colon := strings.IndexByte(val, ':')
+	ok := colon > 0 && len(val) >= colon+6 && val[colon+3] == ':'
+	if ok {
+		hours, ok = parseDigits(val[:colon])
+	}
+	if ok {
+		minute, ok = parseDigits(val[colon+1 : colon+3])
+	}
+	if ok {
+		second, ok = parseDigits(val[colon+4 : colon+6])
+	}
+	if ok && len(val) > colon+6 {
+		ok = val[colon+6] == '.'
+		if ok {
+			micro, ok = parseMicroseconds(val[colon+7:])
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %q as a binary time", val)
+	}
+
+	var length byte
+	switch {
+	case micro != 0:
+		length = 12
+	case hours != 0 || minute != 0 || second != 0:
+		length = 8
+	}
+	buf = append(buf, length)
+	if length >= 8 {
+		buf = append(buf, negative)
+		buf = appendUint(buf, uint64(hours/24), 4)
+		buf = append(buf, byte(hours%24), byte(minute), byte(second))
+	}
+	if length == 12 {
+		buf = appendUint(buf, uint64(micro), 4)
+	}
+	return buf, nil
+}
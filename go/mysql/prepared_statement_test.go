@@ -0,0 +1,340 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestPlaceholderOffsets(t *testing.T) {
+	testcases := []struct {
+		query string
+		want  []int
+	}{{
+		query: "select 1",
+		want:  nil,
+	}, {
+		query: "select ? from t where a = ? and b = '?' and c = \"?\" and `?` = ?",
+		want:  []int{7, 26, 62},
+	}, {
+		query: "select 'it''s ?', 'a\\'?', ? # comment ?\n, ?",
+		want:  []int{26, 42},
+	}, {
+		query: "select ? -- comment ?\n, ?--?",
+		want:  []int{7, 24, 27},
+	}, {
+		query: "select ? /* comment ? */, ? /*! ? */",
+		want:  []int{7, 26, 32},
+	}}
+	for _, tcase := range testcases {
+		if got := placeholderOffsets(tcase.query); !reflect.DeepEqual(got, tcase.want) {
+			t.Errorf("placeholderOffsets(%q) = %v, want %v", tcase.query, got, tcase.want)
+		}
+	}
+}
+
+func TestBinaryDateTime(t *testing.T) {
+	testcases := []struct {
+		typ     querypb.Type
+		value   string
+		encoded []byte
+		decoded string
+	}{{
+		typ:     sqltypes.Date,
+		value:   "2018-01-02",
+		encoded: []byte{4, 0xe2, 0x07, 1, 2},
+		decoded: "2018-01-02",
+	}, {
+		typ:     sqltypes.Datetime,
+		value:   "0000-00-00 00:00:00",
+		encoded: []byte{0},
+		decoded: "0000-00-00 00:00:00",
+	}, {
+		typ:     sqltypes.Datetime,
+		value:   "2018-01-02 03:04:05",
+		encoded: []byte{7, 0xe2, 0x07, 1, 2, 3, 4, 5},
+		decoded: "2018-01-02 03:04:05",
+	}, {
+		typ:     sqltypes.Timestamp,
+		value:   "2018-01-02 03:04:05.5",
+		encoded: []byte{11, 0xe2, 0x07, 1, 2, 3, 4, 5, 0x20, 0xa1, 0x07, 0},
+		decoded: "2018-01-02 03:04:05.500000",
+	}, {
+		typ:     sqltypes.Time,
+		value:   "00:00:00",
+		encoded: []byte{0},
+		decoded: "00:00:00",
+	}, {
+		typ:     sqltypes.Time,
+		value:   "-838:59:59",
+		encoded: []byte{8, 1, 34, 0, 0, 0, 22, 59, 59},
+		decoded: "-838:59:59",
+	}, {
+		typ:     sqltypes.Time,
+		value:   "12:34:56.000001",
+		encoded: []byte{12, 0, 0, 0, 0, 0, 12, 34, 56, 1, 0, 0, 0},
+		decoded: "12:34:56.000001",
+	}}
+	for _, tcase := range testcases {
+		encoded, err := appendBinaryValue(nil, tcase.typ, []byte(tcase.value))
+		if err != nil || !reflect.DeepEqual(encoded, tcase.encoded) {
+			t.Errorf("appendBinaryValue(%v) = %v, %v, want %v", tcase.value, encoded, err, tcase.encoded)
+			continue
+		}
+		var decoded string
+		var ok bool
+		if tcase.typ == sqltypes.Time {
+			decoded, ok = formatBinaryTime(encoded[1:])
+		} else {
+			decoded, ok = formatBinaryDateTime(encoded[1:], tcase.typ == sqltypes.Date)
+		}
+		if !ok || decoded != tcase.decoded {
+			t.Errorf("decoding %v = %v, %v, want %v", encoded, decoded, ok, tcase.decoded)
+		}
+	}
+
+	if _, err := appendBinaryValue(nil, sqltypes.Datetime, []byte("bad")); err == nil {
+		t.Errorf("appendBinaryValue(bad) should have failed")
+	}
+}
+
+func TestDecimalParam(t *testing.T) {
+	testcases := []struct {
+		value string
+		want  string
+	}{{
+		value: "1",
+		want:  "1",
+	}, {
+		value: "-12.50",
+		want:  "-12.50",
+	}, {
+		value: "+.5",
+		want:  "+.5",
+	}, {
+		value: "1.5e-3",
+		want:  "1.5e-3",
+	}, {
+		value: "1--",
+		want:  "'1--'",
+	}, {
+		value: "--1",
+		want:  "'--1'",
+	}, {
+		value: "1e",
+		want:  "'1e'",
+	}, {
+		value: ".",
+		want:  "'.'",
+	}, {
+		value: "",
+		want:  "''",
+	}}
+	for _, tcase := range testcases {
+		buf := &bytes.Buffer{}
+		data := append([]byte{byte(len(tcase.value))}, tcase.value...)
+		if _, ok := appendBinaryParam(buf, data, 0, TypeNewDecimal); !ok {
+			t.Errorf("appendBinaryParam(%q) failed", tcase.value)
+			continue
+		}
+		if got := buf.String(); got != tcase.want {
+			t.Errorf("appendBinaryParam(%q): %s, want %s", tcase.value, got, tcase.want)
+		}
+	}
+}
+
+// preparedStatementHandler records the queries it gets.
+type preparedStatementHandler struct {
+	queries []string
+	result  *sqltypes.Result
+}
+
+func (h *preparedStatementHandler) NewConnection(c *Conn) {
+}
+
+func (h *preparedStatementHandler) ConnectionClosed(c *Conn) {
+}
+
+func (h *preparedStatementHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.Result) error) error {
+	h.queries = append(h.queries, query)
+	return callback(h.result)
+}
+
+func (h *preparedStatementHandler) WarningCount(c *Conn) uint16 {
+	return 0
+}
+
+// writeCommand sends a command from the client side, and makes the
+// server side process it.
+func writeCommand(t *testing.T, cConn, sConn *Conn, handler Handler, data []byte) {
+	cConn.sequence = 0
+	if err := cConn.writePacket(data); err != nil {
+		t.Fatalf("writePacket failed: %v", err)
+	}
+	if err := sConn.handleNextCommand(handler); err != nil {
+		t.Fatalf("handleNextCommand failed: %v", err)
+	}
+}
+
+func readPackets(t *testing.T, cConn *Conn, count int) [][]byte {
+	var result [][]byte
+	for i := 0; i < count; i++ {
+		data, err := cConn.readPacket()
+		if err != nil {
+			t.Fatalf("readPacket failed: %v", err)
+		}
+		result = append(result, data)
+	}
+	return result
+}
+
+func TestPreparedStatement(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	handler := &preparedStatementHandler{
+		result: &sqltypes.Result{
+			Fields: []*querypb.Field{{
+				Name: "id",
+				Type: querypb.Type_INT32,
+			}, {
+				Name: "name",
+				Type: querypb.Type_VARCHAR,
+			}, {
+				Name: "created",
+				Type: querypb.Type_DATETIME,
+			}},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.MakeTrusted(querypb.Type_INT32, []byte("10")),
+				sqltypes.MakeTrusted(querypb.Type_VARCHAR, []byte("nice name")),
+				sqltypes.NULL,
+			}},
+		},
+	}
+
+	// Prepare a statement with 3 parameters.
+	writeCommand(t, cConn, sConn, handler, append([]byte{ComStmtPrepare}, "select id, name, created from t where id = ? and name = ? and '?' = ?"...))
+	packets := readPackets(t, cConn, 1)
+	if want := []byte{OKPacket, 1, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0}; !reflect.DeepEqual(packets[0], want) {
+		t.Fatalf("prepare response: got %v, want %v", packets[0], want)
+	}
+	// The 3 parameter definitions, and an EOF packet.
+	packets = readPackets(t, cConn, 4)
+	if !isEOFPacket(packets[3]) {
+		t.Errorf("expected EOF packet after the parameters, got %v", packets[3])
+	}
+
+	// Execute it.
+	writeCommand(t, cConn, sConn, handler, []byte{
+		ComStmtExecute,
+		1, 0, 0, 0, // statement ID
+		0,          // flags
+		1, 0, 0, 0, // iteration count
+		0x04, // NULL bitmap: the third parameter is NULL
+		1,    // new parameters bound
+		TypeLongLong, 0, TypeVarString, 0, TypeVarString, 0,
+		10, 0, 0, 0, 0, 0, 0, 0,
+		3, 'a', '\'', 'b',
+	})
+	if want := "select id, name, created from t where id = 10 and name = 'a\\'b' and '?' = null"; handler.queries[0] != want {
+		t.Errorf("executed query: got %q, want %q", handler.queries[0], want)
+	}
+	// The column count, 3 column definitions, an EOF packet, the row,
+	// and the final EOF packet.
+	packets = readPackets(t, cConn, 7)
+	if want := []byte{OKPacket, 0x10, 10, 0, 0, 0, 9, 'n', 'i', 'c', 'e', ' ', 'n', 'a', 'm', 'e'}; !reflect.DeepEqual(packets[5], want) {
+		t.Errorf("binary row: got %v, want %v", packets[5], want)
+	}
+	if !isEOFPacket(packets[6]) {
+		t.Errorf("expected EOF packet after the rows, got %v", packets[6])
+	}
+
+	// Execute it again, with the previous parameter types, and the
+	// second parameter sent as long data.
+	writeCommand(t, cConn, sConn, handler, []byte{ComStmtSendLongData, 1, 0, 0, 0, 1, 0, 'l', 'o'})
+	writeCommand(t, cConn, sConn, handler, []byte{ComStmtSendLongData, 1, 0, 0, 0, 1, 0, 'n', 'g'})
+	writeCommand(t, cConn, sConn, handler, []byte{
+		ComStmtExecute,
+		1, 0, 0, 0,
+		0,
+		1, 0, 0, 0,
+		0, // NULL bitmap
+		0, // no new parameters bound
+		20, 0, 0, 0, 0, 0, 0, 0,
+		1, 'x',
+	})
+	if want := "select id, name, created from t where id = 20 and name = 'long' and '?' = 'x'"; handler.queries[1] != want {
+		t.Errorf("executed query: got %q, want %q", handler.queries[1], want)
+	}
+	readPackets(t, cConn, 7)
+
+	// Close it, it can't be executed any more. There is no response to
+	// COM_STMT_CLOSE.
+	writeCommand(t, cConn, sConn, handler, []byte{ComStmtClose, 1, 0, 0, 0})
+	writeCommand(t, cConn, sConn, handler, []byte{ComStmtExecute, 1, 0, 0, 0, 0, 1, 0, 0, 0})
+	packets = readPackets(t, cConn, 1)
+	err := ParseErrorPacket(packets[0])
+	if sqlErr, ok := err.(*SQLError); !ok || sqlErr.Number() != ERUnknownStmtHandler {
+		t.Errorf("execute after close: got %v, want ERUnknownStmtHandler", err)
+	}
+	if len(handler.queries) != 2 {
+		t.Errorf("unexpected queries: %v", handler.queries)
+	}
+}
+
+func TestPreparedStatementLimit(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	defer func(saved int) {
+		maxPreparedStatements = saved
+	}(maxPreparedStatements)
+	maxPreparedStatements = 2
+	handler := &preparedStatementHandler{}
+
+	prepare := append([]byte{ComStmtPrepare}, "select 1"...)
+	for i := 0; i < 2; i++ {
+		writeCommand(t, cConn, sConn, handler, prepare)
+		readPackets(t, cConn, 1)
+	}
+	writeCommand(t, cConn, sConn, handler, prepare)
+	packets := readPackets(t, cConn, 1)
+	err := ParseErrorPacket(packets[0])
+	if sqlErr, ok := err.(*SQLError); !ok || sqlErr.Number() != ERMaxPreparedStmtCountReached {
+		t.Errorf("prepare over the limit: got %v, want ERMaxPreparedStmtCountReached", err)
+	}
+
+	// Closing a statement makes room for a new one.
+	writeCommand(t, cConn, sConn, handler, []byte{ComStmtClose, 1, 0, 0, 0})
+	writeCommand(t, cConn, sConn, handler, prepare)
+	packets = readPackets(t, cConn, 1)
+	if want := []byte{OKPacket, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}; !reflect.DeepEqual(packets[0], want) {
+		t.Errorf("prepare after close: got %v, want %v", packets[0], want)
+	}
+}
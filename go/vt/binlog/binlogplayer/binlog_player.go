@@ -130,6 +130,10 @@ type BinlogPlayer struct {
 	defaultCharset *binlogdatapb.Charset
 	currentCharset *binlogdatapb.Charset
 	deadlockRetry  time.Duration
+
+	// catchUpPosition is set if the stream replays events which were already
+	// copied by an online clone. See SetCatchUpPosition().
+	catchUpPosition mysql.Position
 }
 
 // NewBinlogPlayerKeyRange returns a new BinlogPlayer pointing at the server
@@ -164,6 +168,15 @@ func NewBinlogPlayerTables(dbClient DBClient, tablet *topodatapb.Tablet, tables
 	return result
 }
 
+// SetCatchUpPosition sets the position up to which duplicate key errors
+// are ignored. This is required when a stream starts at a position which is
+// older than the data on the destination e.g. after an online clone:
+// Inserts of rows which were already copied will fail, and the later
+// events will bring the row to the latest state anyway.
+func (blp *BinlogPlayer) SetCatchUpPosition(pos mysql.Position) {
+	blp.catchUpPosition = pos
+}
+
 // ApplyBinlogEvents makes an RPC request to BinlogServer
 // and processes the events. It returns nil if the provided context
 // was canceled, or if we reached the stopping point.
@@ -405,6 +418,11 @@ func (blp *BinlogPlayer) processTransaction(tx *binlogdatapb.BinlogTransaction)
 			}
 			return false, nil
 		}
+		if sqlErr, ok := err.(*mysql.SQLError); ok && sqlErr.Number() == mysql.ERDupEntry && blp.isCatchingUp() {
+			// The row was already copied. Skip the statement.
+			log.Infof("Ignoring duplicate key error while catching up to %v: %v", blp.catchUpPosition, err)
+			continue
+		}
 		_ = blp.dbClient.Rollback()
 		return false, err
 	}
@@ -421,6 +439,12 @@ func (blp *BinlogPlayer) processTransaction(tx *binlogdatapb.BinlogTransaction)
 	return true, nil
 }
 
+// isCatchingUp returns true if the player has not reached the catch up
+// position yet.
+func (blp *BinlogPlayer) isCatchingUp() bool {
+	return !blp.catchUpPosition.IsZero() && !blp.position.AtLeast(blp.catchUpPosition)
+}
+
 func (blp *BinlogPlayer) exec(sql string) (*sqltypes.Result, error) {
 	queryStartTime := time.Now()
 	qr, err := blp.dbClient.ExecuteFetch(sql, 0)
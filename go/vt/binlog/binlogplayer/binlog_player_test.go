@@ -296,6 +296,29 @@ func TestRetryOnDeadlock(t *testing.T) {
 	}
 }
 
+// TestIgnoreDuplicateWhileCatchingUp ensures that duplicate key errors are
+// ignored until the catch up position was reached.
+func TestIgnoreDuplicateWhileCatchingUp(t *testing.T) {
+	dbClient := NewMockDBClient(t)
+	dbClient.ExpectRequest("update _vt.vreplication set state='Running', message='' where id=1", testDMLResponse, nil)
+	dbClient.ExpectRequest("select pos, stop_pos, max_tps, max_replication_lag from _vt.vreplication where id=1", testSettingsResponse, nil)
+	duplicate := &mysql.SQLError{Num: mysql.ERDupEntry, Message: "Duplicate entry '1' for key 'PRIMARY'"}
+	dbClient.ExpectRequest("begin", nil, nil)
+	dbClient.ExpectRequest("insert into t values(1)", nil, duplicate)
+	dbClient.ExpectRequestRE("update _vt.vreplication set pos='MariaDB/0-1-1235', time_updated=.*", testDMLResponse, nil)
+	dbClient.ExpectRequest("commit", nil, nil)
+
+	blp := NewBinlogPlayerTables(dbClient, nil, []string{"a"}, 1, NewStats())
+	blp.SetCatchUpPosition(mysql.Position{GTIDSet: mysql.MustParseGTID("MariaDB", "0-1-1235").GTIDSet()})
+	errfunc := applyEvents(blp)
+
+	dbClient.Wait()
+
+	if err := errfunc(); err != nil {
+		t.Error(err)
+	}
+}
+
 // applyEvents starts a goroutine to apply events, and returns an error function.
 // The error func must be invoked before exiting the test to ensure that apply
 // has finished. Otherwise, it may cause race with other tests.
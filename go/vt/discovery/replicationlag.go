@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -28,8 +29,61 @@ var (
 	lowReplicationLag            = flag.Duration("discovery_low_replication_lag", 30*time.Second, "the replication lag that is considered low enough to be healthy")
 	highReplicationLagMinServing = flag.Duration("discovery_high_replication_lag_minimum_serving", 2*time.Hour, "the replication lag that is considered too high when selecting the minimum num vttablets for serving")
 	minNumTablets                = flag.Int("min_number_serving_vttablets", 2, "the minimum number of vttablets that will be continue to be used even with low replication lag")
+
+	// keyspaceMaxReplicationLag holds the per-keyspace replication lag
+	// above which a tablet is never used.
+	keyspaceMaxReplicationLag = keyspaceLagValue{}
 )
 
+func init() {
+	flag.Var(&keyspaceMaxReplicationLag, "discovery_keyspace_max_replication_lag", "comma-separated list of keyspace:duration pairs, e.g. ks1:30s,ks2:5m. Tablets of these keyspaces with a higher replication lag are never used, even if no other tablet is available")
+}
+
+// keyspaceLagValue is a flag.Value for a map of keyspace to replication lag.
+type keyspaceLagValue map[string]time.Duration
+
+// Set is part of the flag.Value interface.
+func (value *keyspaceLagValue) Set(v string) error {
+	lags := make(map[string]time.Duration)
+	for _, pair := range strings.Split(v, ",") {
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid keyspace:duration pair: %v", pair)
+		}
+		lag, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid duration for keyspace %v: %v", parts[0], err)
+		}
+		lags[parts[0]] = lag
+	}
+	*value = lags
+	return nil
+}
+
+// String is part of the flag.Value interface.
+func (value keyspaceLagValue) String() string {
+	parts := make([]string, 0, len(value))
+	for keyspace, lag := range value {
+		parts = append(parts, keyspace+":"+lag.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// IsReplicationLagTooHigh verifies that the given TabletStats refers to a tablet
+// with a replication lag higher than the one configured for its keyspace by the
+// discovery_keyspace_max_replication_lag flag. Such tablets are never served from.
+func IsReplicationLagTooHigh(tabletStats *TabletStats) bool {
+	if tabletStats.Target == nil {
+		return false
+	}
+	maxLag, ok := keyspaceMaxReplicationLag[tabletStats.Target.Keyspace]
+	return ok && float64(tabletStats.Stats.SecondsBehindMaster) > maxLag.Seconds()
+}
+
 // IsReplicationLagHigh verifies that the given TabletStats refers to a tablet with high
 // replication lag, i.e. higher than the configured discovery_low_replication_lag flag.
 func IsReplicationLagHigh(tabletStats *TabletStats) bool {
@@ -44,6 +98,7 @@ func IsReplicationLagVeryHigh(tabletStats *TabletStats) bool {
 
 // FilterByReplicationLag filters the list of TabletStats by TabletStats.Stats.SecondsBehindMaster.
 // The algorithm (TabletStats that is non-serving or has error is ignored):
+// - Drop the tablets with a lag higher than the discovery_keyspace_max_replication_lag of their keyspace.
 // - Return the list if there is 0 or 1 tablet.
 // - Return the list if all tablets have <=30s lag.
 // - Filter by replication lag: for each tablet, if the mean value without it is more than 0.7 of the mean value across all tablets, it is valid.
@@ -69,9 +124,9 @@ func FilterByReplicationLag(tabletStatsList []*TabletStats) []*TabletStats {
 
 func filterByLag(tabletStatsList []*TabletStats) []*TabletStats {
 	list := make([]*TabletStats, 0, len(tabletStatsList))
-	// filter non-serving tablets
+	// filter non-serving tablets, and those too far behind for their keyspace
 	for _, ts := range tabletStatsList {
-		if !ts.Serving || ts.LastError != nil || ts.Stats == nil {
+		if !ts.Serving || ts.LastError != nil || ts.Stats == nil || IsReplicationLagTooHigh(ts) {
 			continue
 		}
 		list = append(list, ts)
@@ -179,6 +234,12 @@ func mean(tabletStatsList []*TabletStats, idxExclude int) (uint64, error) {
 // TrivialStatsUpdate returns true iff the old and new TabletStats
 // haven't changed enough to warrant re-calling FilterByReplicationLag.
 func TrivialStatsUpdate(o, n *TabletStats) bool {
+	// Crossing the keyspace max replication lag always changes
+	// the healthy list.
+	if IsReplicationLagTooHigh(o) != IsReplicationLagTooHigh(n) {
+		return false
+	}
+
 	// Skip replag filter when replag remains in the low rep lag range,
 	// which should be the case majority of the time.
 	lowRepLag := lowReplicationLag.Seconds()
@@ -231,6 +231,56 @@ func TestFilterByReplicationLagOneTabletMin(t *testing.T) {
 	testSetMinNumTablets(2)
 }
 
+func TestFilterByReplicationLagKeyspaceMax(t *testing.T) {
+	if err := keyspaceMaxReplicationLag.Set("ks1:10s"); err != nil {
+		t.Fatal(err)
+	}
+	defer keyspaceMaxReplicationLag.Set("")
+
+	newStats := func(uid uint32, keyspace string, lag uint32) *TabletStats {
+		return &TabletStats{
+			Tablet:  topo.NewTablet(uid, "cell", fmt.Sprintf("host%v", uid)),
+			Target:  &querypb.Target{Keyspace: keyspace},
+			Serving: true,
+			Stats:   &querypb.RealtimeStats{SecondsBehindMaster: lag},
+		}
+	}
+
+	// The tablets over the keyspace threshold are dropped,
+	// even below discovery_low_replication_lag.
+	ts1 := newStats(1, "ks1", 5)
+	ts2 := newStats(2, "ks1", 20)
+	got := FilterByReplicationLag([]*TabletStats{ts1, ts2})
+	if len(got) != 1 || !got[0].DeepEqual(ts1) {
+		t.Errorf("FilterByReplicationLag([5s, 20s]) = %+v, want [5s]", got)
+	}
+
+	// Even if that leaves no tablet.
+	got = FilterByReplicationLag([]*TabletStats{ts2})
+	if len(got) != 0 {
+		t.Errorf("FilterByReplicationLag([20s]) = %+v, want []", got)
+	}
+
+	// Other keyspaces are not affected.
+	ts3 := newStats(3, "ks2", 20)
+	got = FilterByReplicationLag([]*TabletStats{ts3})
+	if len(got) != 1 || !got[0].DeepEqual(ts3) {
+		t.Errorf("FilterByReplicationLag([20s]) = %+v, want [20s]", got)
+	}
+
+	// Crossing the threshold is never a trivial update.
+	if TrivialStatsUpdate(ts1, ts2) {
+		t.Errorf("TrivialStatsUpdate(5s, 20s) = true, want false")
+	}
+
+	if err := keyspaceMaxReplicationLag.Set("ks1"); err == nil {
+		t.Errorf("Set(ks1) should have failed")
+	}
+	if err := keyspaceMaxReplicationLag.Set("ks1:10"); err == nil {
+		t.Errorf("Set(ks1:10) should have failed")
+	}
+}
+
 func TestTrivialStatsUpdate(t *testing.T) {
 	// Note the healthy threshold is set to 30s.
 	cases := []struct {
@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"compress/gzip"
+	"io"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// GzipCompressor is a gRPC compressor using the gzip algorithm.
+type GzipCompressor struct{}
+
+// Name is "gzip"
+func (g GzipCompressor) Name() string {
+	return "gzip"
+}
+
+// Compress wraps with a gzip.Writer
+func (g GzipCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+// Decompress wraps with a gzip.Reader
+func (g GzipCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+func init() {
+	encoding.RegisterCompressor(GzipCompressor{})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestGzipCompressor(t *testing.T) {
+	want := strings.Repeat("INSERT INTO `t` (`id`) VALUES (1);", 100)
+
+	var buf bytes.Buffer
+	w, err := GzipCompressor{}.Compress(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(want)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() >= len(want) {
+		t.Errorf("compressed size = %v, want less than %v", buf.Len(), len(want))
+	}
+
+	r, err := GzipCompressor{}.Decompress(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("Decompress() = %v, want %v", string(got), want)
+	}
+}
+
+func TestCompressionContext(t *testing.T) {
+	ctx := context.Background()
+	if got := CallOptions(ctx); len(got) != 0 {
+		t.Errorf("CallOptions() without compression = %v, want none", got)
+	}
+	if got := CallOptions(NewCompressionContext(ctx, "")); len(got) != 0 {
+		t.Errorf("CallOptions() with empty compression = %v, want none", got)
+	}
+	if got := CallOptions(NewCompressionContext(ctx, "gzip")); len(got) != 1 {
+		t.Errorf("CallOptions() with gzip = %v, want one option", got)
+	}
+
+	for _, name := range []string{"", "snappy", "gzip"} {
+		if !IsValidCompression(name) {
+			t.Errorf("IsValidCompression(%q) = false, want true", name)
+		}
+	}
+	if IsValidCompression("zstd") {
+		t.Errorf("IsValidCompression(\"zstd\") = true, want false")
+	}
+}
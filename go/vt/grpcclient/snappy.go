@@ -5,12 +5,13 @@ import (
 	"io"
 
 	"github.com/golang/snappy"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/encoding"
 )
 
 var (
-	compression = flag.String("grpc_compression", "", "how to compress gRPC, default: nothing, supported: snappy")
+	compression = flag.String("grpc_compression", "", "how to compress gRPC, default: nothing, supported: snappy, gzip")
 )
 
 // SnappyCompressor is a gRPC compressor using the Snappy algorithm.
@@ -32,14 +33,47 @@ func (s SnappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
 }
 
 func appendCompression(opts []grpc.DialOption) ([]grpc.DialOption, error) {
-	if *compression == "snappy" {
-		compression := grpc.WithDefaultCallOptions(grpc.UseCompressor("snappy"))
+	if *compression == "snappy" || *compression == "gzip" {
+		compression := grpc.WithDefaultCallOptions(grpc.UseCompressor(*compression))
 		opts = append(opts, compression)
 	}
 
 	return opts, nil
 }
 
+// compressionKey is the context key for the compressor name set by
+// NewCompressionContext.
+type compressionKey struct{}
+
+// NewCompressionContext returns a context which requests that the RPCs made
+// with it compress their messages with the compressor "name" ("snappy" or
+// "gzip"). It overrides --grpc_compression for a single caller, e.g. a
+// vtworker command which copies data over a slow link.
+// Only the clients which pass CallOptions(ctx) to their RPCs support this.
+// An empty name returns "ctx" unchanged.
+func NewCompressionContext(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, compressionKey{}, name)
+}
+
+// CallOptions returns the per-call options which were requested through the
+// context e.g. by NewCompressionContext.
+func CallOptions(ctx context.Context) []grpc.CallOption {
+	name, ok := ctx.Value(compressionKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(name)}
+}
+
+// IsValidCompression returns true if "name" is empty (no compression) or the
+// name of a registered compressor.
+func IsValidCompression(name string) bool {
+	return name == "" || encoding.GetCompressor(name) != nil
+}
+
 func init() {
 	encoding.RegisterCompressor(SnappyCompressor{})
 	RegisterGRPCDialOptions(appendCompression)
@@ -0,0 +1,191 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmutils
+
+import (
+	"sort"
+	"strings"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// SchemaDiffType is the kind of a SchemaDiff.
+type SchemaDiffType string
+
+const (
+	// SchemaDiffDatabase means the CREATE DATABASE statements differ.
+	SchemaDiffDatabase SchemaDiffType = "database_mismatch"
+	// SchemaDiffMissingTable means the table or view exists only on one side.
+	SchemaDiffMissingTable SchemaDiffType = "missing_table"
+	// SchemaDiffTableType means a table is a base table on one side and a
+	// view on the other side.
+	SchemaDiffTableType SchemaDiffType = "table_type_mismatch"
+	// SchemaDiffColumn means a column exists only on one side or its
+	// definition differs.
+	SchemaDiffColumn SchemaDiffType = "column_mismatch"
+	// SchemaDiffIndex means an index exists only on one side or its
+	// definition differs.
+	SchemaDiffIndex SchemaDiffType = "index_mismatch"
+	// SchemaDiffDefinition means the CREATE statements differ in anything
+	// else, e.g. the table options or the definition of a view.
+	SchemaDiffDefinition SchemaDiffType = "definition_mismatch"
+)
+
+// SchemaDiff is one difference between two schemas. It is meant to be
+// marshaled to JSON.
+type SchemaDiff struct {
+	Type SchemaDiffType `json:"type"`
+	// Table is empty for SchemaDiffDatabase.
+	Table string `json:"table,omitempty"`
+	// Name is the name of the column or index for SchemaDiffColumn and
+	// SchemaDiffIndex.
+	Name string `json:"name,omitempty"`
+	// Left and Right are the names of the compared schemas,
+	// e.g. tablet aliases.
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	// LeftValue and RightValue are the differing definitions. A value is
+	// empty if the table, column or index is missing on that side.
+	LeftValue  string `json:"left_value,omitempty"`
+	RightValue string `json:"right_value,omitempty"`
+}
+
+// StructuredDiffSchema diffs two schemas like DiffSchema. Instead of
+// free-text errors, it returns one SchemaDiff per difference, sorted by
+// table. Differences within a CREATE TABLE statement are reported per
+// column and index.
+func StructuredDiffSchema(leftName string, left *tabletmanagerdatapb.SchemaDefinition, rightName string, right *tabletmanagerdatapb.SchemaDefinition) []*SchemaDiff {
+	if left == nil {
+		left = &tabletmanagerdatapb.SchemaDefinition{}
+	}
+	if right == nil {
+		right = &tabletmanagerdatapb.SchemaDefinition{}
+	}
+	var diffs []*SchemaDiff
+	add := func(diffType SchemaDiffType, table, name, leftValue, rightValue string) {
+		diffs = append(diffs, &SchemaDiff{
+			Type:       diffType,
+			Table:      table,
+			Name:       name,
+			Left:       leftName,
+			Right:      rightName,
+			LeftValue:  leftValue,
+			RightValue: rightValue,
+		})
+	}
+
+	if left.DatabaseSchema != right.DatabaseSchema {
+		add(SchemaDiffDatabase, "", "", left.DatabaseSchema, right.DatabaseSchema)
+	}
+
+	leftTables := make(map[string]*tabletmanagerdatapb.TableDefinition)
+	for _, td := range left.TableDefinitions {
+		leftTables[td.Name] = td
+	}
+	rightTables := make(map[string]*tabletmanagerdatapb.TableDefinition)
+	for _, td := range right.TableDefinitions {
+		rightTables[td.Name] = td
+	}
+	for _, name := range sortedTableNames(leftTables, rightTables) {
+		l, r := leftTables[name], rightTables[name]
+		switch {
+		case l == nil:
+			add(SchemaDiffMissingTable, name, "", "", r.Schema)
+		case r == nil:
+			add(SchemaDiffMissingTable, name, "", l.Schema, "")
+		case l.Type != r.Type:
+			add(SchemaDiffTableType, name, "", l.Type, r.Type)
+		case l.Schema != r.Schema:
+			before := len(diffs)
+			lColumns, lIndexes := parseCreateTable(l.Schema)
+			rColumns, rIndexes := parseCreateTable(r.Schema)
+			for _, d := range diffDefinitions(lColumns, rColumns) {
+				add(SchemaDiffColumn, name, d[0], d[1], d[2])
+			}
+			for _, d := range diffDefinitions(lIndexes, rIndexes) {
+				add(SchemaDiffIndex, name, d[0], d[1], d[2])
+			}
+			if len(diffs) == before {
+				add(SchemaDiffDefinition, name, "", l.Schema, r.Schema)
+			}
+		}
+	}
+	return diffs
+}
+
+func sortedTableNames(left, right map[string]*tabletmanagerdatapb.TableDefinition) []string {
+	var names []string
+	for name := range left {
+		names = append(names, name)
+	}
+	for name := range right {
+		if _, ok := left[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseCreateTable returns the column and index definitions of a
+// CREATE TABLE statement as returned by SHOW CREATE TABLE, keyed by name.
+// Views and statements in another format have no definitions.
+func parseCreateTable(schema string) (columns, indexes map[string]string) {
+	columns = make(map[string]string)
+	indexes = make(map[string]string)
+	for _, line := range strings.Split(schema, "\n") {
+		line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+		switch {
+		case strings.HasPrefix(line, "`"):
+			if end := strings.Index(line[1:], "`"); end != -1 {
+				columns[line[1:end+1]] = line
+			}
+		case strings.HasPrefix(line, "PRIMARY KEY"):
+			indexes["PRIMARY"] = line
+		case strings.Contains(line, "KEY `"):
+			// KEY, UNIQUE KEY, FULLTEXT KEY and SPATIAL KEY.
+			start := strings.Index(line, "`") + 1
+			if end := strings.Index(line[start:], "`"); end != -1 {
+				indexes[line[start:start+end]] = line
+			}
+		}
+	}
+	return columns, indexes
+}
+
+// diffDefinitions returns the differing definitions as [name, left, right]
+// sorted by name.
+func diffDefinitions(left, right map[string]string) [][3]string {
+	var names []string
+	for name := range left {
+		names = append(names, name)
+	}
+	for name := range right {
+		if _, ok := left[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result [][3]string
+	for _, name := range names {
+		if l, r := left[name], right[name]; l != r {
+			result = append(result, [3]string{name, l, r})
+		}
+	}
+	return result
+}
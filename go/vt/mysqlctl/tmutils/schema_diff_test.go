@@ -0,0 +1,84 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tmutils
+
+import (
+	"reflect"
+	"testing"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestStructuredDiffSchema(t *testing.T) {
+	left := &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "CREATE DATABASE {{.DatabaseName}}",
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name: "t1",
+				Schema: "CREATE TABLE `t1` (\n" +
+					"  `id` bigint(20) NOT NULL,\n" +
+					"  `msg` varchar(64) DEFAULT NULL,\n" +
+					"  PRIMARY KEY (`id`),\n" +
+					"  KEY `msg_idx` (`msg`)\n" +
+					") ENGINE=InnoDB",
+				Type: TableBaseTable,
+			},
+			{Name: "t2", Schema: "CREATE TABLE `t2` (\n  `id` bigint(20) NOT NULL\n) ENGINE=InnoDB", Type: TableBaseTable},
+			{Name: "t3", Schema: "CREATE TABLE `t3` (\n  `id` bigint(20) NOT NULL\n) ENGINE=InnoDB", Type: TableBaseTable},
+			{Name: "v1", Schema: "CREATE VIEW `v1` AS SELECT 1", Type: TableView},
+		},
+	}
+	right := &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "CREATE DATABASE {{.DatabaseName}}",
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name: "t1",
+				Schema: "CREATE TABLE `t1` (\n" +
+					"  `id` bigint(20) NOT NULL,\n" +
+					"  `msg` varchar(128) DEFAULT NULL,\n" +
+					"  `c` int(11) DEFAULT NULL,\n" +
+					"  PRIMARY KEY (`id`)\n" +
+					") ENGINE=InnoDB",
+				Type: TableBaseTable,
+			},
+			{Name: "t2", Schema: "CREATE TABLE `t2` (\n  `id` bigint(20) NOT NULL\n) ENGINE=MyISAM", Type: TableBaseTable},
+			{Name: "v1", Schema: "CREATE TABLE `v1` (\n  `id` bigint(20) NOT NULL\n) ENGINE=InnoDB", Type: TableBaseTable},
+		},
+	}
+
+	diff := func(diffType SchemaDiffType, table, name, leftValue, rightValue string) *SchemaDiff {
+		return &SchemaDiff{Type: diffType, Table: table, Name: name, Left: "left", Right: "right", LeftValue: leftValue, RightValue: rightValue}
+	}
+	want := []*SchemaDiff{
+		diff(SchemaDiffColumn, "t1", "c", "", "`c` int(11) DEFAULT NULL"),
+		diff(SchemaDiffColumn, "t1", "msg", "`msg` varchar(64) DEFAULT NULL", "`msg` varchar(128) DEFAULT NULL"),
+		diff(SchemaDiffIndex, "t1", "msg_idx", "KEY `msg_idx` (`msg`)", ""),
+		diff(SchemaDiffDefinition, "t2", "", left.TableDefinitions[1].Schema, right.TableDefinitions[1].Schema),
+		diff(SchemaDiffMissingTable, "t3", "", left.TableDefinitions[2].Schema, ""),
+		diff(SchemaDiffTableType, "v1", "", TableView, TableBaseTable),
+	}
+	if got := StructuredDiffSchema("left", left, "right", right); !reflect.DeepEqual(got, want) {
+		for _, d := range got {
+			t.Logf("got: %+v", d)
+		}
+		t.Errorf("StructuredDiffSchema returned wrong diffs, want: %v", want)
+	}
+
+	if got := StructuredDiffSchema("left", left, "right", left); len(got) != 0 {
+		t.Errorf("StructuredDiffSchema of equal schemas must be empty: %v", got)
+	}
+}
@@ -65,7 +65,7 @@ func (x BinlogTransaction_Statement_Category) String() string {
 	return proto.EnumName(BinlogTransaction_Statement_Category_name, int32(x))
 }
 func (BinlogTransaction_Statement_Category) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{1, 0, 0}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{1, 0, 0}
 }
 
 // Charset is the per-statement charset info from a QUERY_EVENT binlog entry.
@@ -85,7 +85,7 @@ func (m *Charset) Reset()         { *m = Charset{} }
 func (m *Charset) String() string { return proto.CompactTextString(m) }
 func (*Charset) ProtoMessage()    {}
 func (*Charset) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{0}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{0}
 }
 func (m *Charset) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Charset.Unmarshal(m, b)
@@ -142,7 +142,7 @@ func (m *BinlogTransaction) Reset()         { *m = BinlogTransaction{} }
 func (m *BinlogTransaction) String() string { return proto.CompactTextString(m) }
 func (*BinlogTransaction) ProtoMessage()    {}
 func (*BinlogTransaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{1}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{1}
 }
 func (m *BinlogTransaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BinlogTransaction.Unmarshal(m, b)
@@ -192,7 +192,7 @@ func (m *BinlogTransaction_Statement) Reset()         { *m = BinlogTransaction_S
 func (m *BinlogTransaction_Statement) String() string { return proto.CompactTextString(m) }
 func (*BinlogTransaction_Statement) ProtoMessage()    {}
 func (*BinlogTransaction_Statement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{1, 0}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{1, 0}
 }
 func (m *BinlogTransaction_Statement) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BinlogTransaction_Statement.Unmarshal(m, b)
@@ -250,7 +250,7 @@ func (m *StreamKeyRangeRequest) Reset()         { *m = StreamKeyRangeRequest{} }
 func (m *StreamKeyRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*StreamKeyRangeRequest) ProtoMessage()    {}
 func (*StreamKeyRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{2}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{2}
 }
 func (m *StreamKeyRangeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamKeyRangeRequest.Unmarshal(m, b)
@@ -303,7 +303,7 @@ func (m *StreamKeyRangeResponse) Reset()         { *m = StreamKeyRangeResponse{}
 func (m *StreamKeyRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*StreamKeyRangeResponse) ProtoMessage()    {}
 func (*StreamKeyRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{3}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{3}
 }
 func (m *StreamKeyRangeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamKeyRangeResponse.Unmarshal(m, b)
@@ -347,7 +347,7 @@ func (m *StreamTablesRequest) Reset()         { *m = StreamTablesRequest{} }
 func (m *StreamTablesRequest) String() string { return proto.CompactTextString(m) }
 func (*StreamTablesRequest) ProtoMessage()    {}
 func (*StreamTablesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{4}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{4}
 }
 func (m *StreamTablesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamTablesRequest.Unmarshal(m, b)
@@ -400,7 +400,7 @@ func (m *StreamTablesResponse) Reset()         { *m = StreamTablesResponse{} }
 func (m *StreamTablesResponse) String() string { return proto.CompactTextString(m) }
 func (*StreamTablesResponse) ProtoMessage()    {}
 func (*StreamTablesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{5}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{5}
 }
 func (m *StreamTablesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamTablesResponse.Unmarshal(m, b)
@@ -440,7 +440,11 @@ type BinlogSource struct {
 	// key_range is set if the request is for a keyrange
 	KeyRange *topodata.KeyRange `protobuf:"bytes,4,opt,name=key_range,json=keyRange" json:"key_range,omitempty"`
 	// tables is set if the request is for a list of tables
-	Tables               []string `protobuf:"bytes,5,rep,name=tables" json:"tables,omitempty"`
+	Tables []string `protobuf:"bytes,5,rep,name=tables" json:"tables,omitempty"`
+	// catch_up_position is set if the stream replays events which were
+	// already (partially) copied by an online clone. Until the player
+	// reaches this position, it ignores duplicate key errors.
+	CatchUpPosition      string   `protobuf:"bytes,6,opt,name=catch_up_position,json=catchUpPosition" json:"catch_up_position,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -450,7 +454,7 @@ func (m *BinlogSource) Reset()         { *m = BinlogSource{} }
 func (m *BinlogSource) String() string { return proto.CompactTextString(m) }
 func (*BinlogSource) ProtoMessage()    {}
 func (*BinlogSource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_binlogdata_ac14f15f6b19a931, []int{6}
+	return fileDescriptor_binlogdata_af9050c1d68df22c, []int{6}
 }
 func (m *BinlogSource) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BinlogSource.Unmarshal(m, b)
@@ -505,6 +509,13 @@ func (m *BinlogSource) GetTables() []string {
 	return nil
 }
 
+func (m *BinlogSource) GetCatchUpPosition() string {
+	if m != nil {
+		return m.CatchUpPosition
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Charset)(nil), "binlogdata.Charset")
 	proto.RegisterType((*BinlogTransaction)(nil), "binlogdata.BinlogTransaction")
@@ -517,48 +528,49 @@ func init() {
 	proto.RegisterEnum("binlogdata.BinlogTransaction_Statement_Category", BinlogTransaction_Statement_Category_name, BinlogTransaction_Statement_Category_value)
 }
 
-func init() { proto.RegisterFile("binlogdata.proto", fileDescriptor_binlogdata_ac14f15f6b19a931) }
-
-var fileDescriptor_binlogdata_ac14f15f6b19a931 = []byte{
-	// 640 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xcd, 0x6e, 0xda, 0x4a,
-	0x14, 0xbe, 0xc6, 0x40, 0xec, 0xe3, 0xdc, 0x64, 0x98, 0xfc, 0x08, 0x21, 0x5d, 0x09, 0xb1, 0x09,
-	0x77, 0x71, 0xcd, 0x95, 0xab, 0x3e, 0x40, 0x8c, 0xad, 0x88, 0xc4, 0x90, 0x68, 0x70, 0x36, 0xd9,
-	0x58, 0xc6, 0x99, 0x12, 0x04, 0xf1, 0x38, 0x9e, 0x09, 0xaa, 0x9f, 0xa3, 0x4f, 0xd1, 0xb7, 0xe8,
-	0xaa, 0x6f, 0xd2, 0xf7, 0xa8, 0x3c, 0x36, 0x86, 0xa4, 0x52, 0x9b, 0x2e, 0xba, 0x3b, 0xdf, 0x99,
-	0xef, 0x9c, 0x39, 0xdf, 0x37, 0x47, 0x03, 0x68, 0xb6, 0x88, 0x57, 0x6c, 0x7e, 0x1f, 0x8a, 0xd0,
-	0x4c, 0x52, 0x26, 0x18, 0x86, 0x6d, 0xa6, 0x63, 0x3c, 0x3d, 0xd3, 0x34, 0x2b, 0x0e, 0x3a, 0x07,
-	0x82, 0x25, 0x6c, 0x4b, 0xec, 0x8d, 0x61, 0x6f, 0xf8, 0x10, 0xa6, 0x9c, 0x0a, 0x7c, 0x0a, 0xcd,
-	0x68, 0xb5, 0xa0, 0xb1, 0x68, 0x2b, 0x5d, 0xa5, 0xdf, 0x20, 0x25, 0xc2, 0x18, 0xea, 0x11, 0x8b,
-	0xe3, 0x76, 0x4d, 0x66, 0x65, 0x9c, 0x73, 0x39, 0x4d, 0xd7, 0x34, 0x6d, 0xab, 0x05, 0xb7, 0x40,
-	0xbd, 0x6f, 0x2a, 0xb4, 0x6c, 0x79, 0xb5, 0x9f, 0x86, 0x31, 0x0f, 0x23, 0xb1, 0x60, 0x31, 0xbe,
-	0x00, 0xe0, 0x22, 0x14, 0xf4, 0x91, 0xc6, 0x82, 0xb7, 0x95, 0xae, 0xda, 0x37, 0xac, 0x33, 0x73,
-	0x67, 0xe8, 0x1f, 0x4a, 0xcc, 0xe9, 0x86, 0x4f, 0x76, 0x4a, 0xb1, 0x05, 0x06, 0x5d, 0xd3, 0x58,
-	0x04, 0x82, 0x2d, 0x69, 0xdc, 0xae, 0x77, 0x95, 0xbe, 0x61, 0xb5, 0xcc, 0x42, 0xa0, 0x9b, 0x9f,
-	0xf8, 0xf9, 0x01, 0x01, 0x5a, 0xc5, 0x9d, 0xaf, 0x35, 0xd0, 0xab, 0x6e, 0xd8, 0x03, 0x2d, 0x0a,
-	0x05, 0x9d, 0xb3, 0x34, 0x93, 0x32, 0x0f, 0xac, 0xff, 0xdf, 0x38, 0x88, 0x39, 0x2c, 0xeb, 0x48,
-	0xd5, 0x01, 0xff, 0x07, 0x7b, 0x51, 0xe1, 0x9e, 0x74, 0xc7, 0xb0, 0x8e, 0x76, 0x9b, 0x95, 0xc6,
-	0x92, 0x0d, 0x07, 0x23, 0x50, 0xf9, 0xd3, 0x4a, 0x5a, 0xb6, 0x4f, 0xf2, 0xb0, 0xf7, 0x59, 0x01,
-	0x6d, 0xd3, 0x17, 0x1f, 0xc1, 0xa1, 0xed, 0x05, 0xb7, 0x13, 0xe2, 0x0e, 0xaf, 0x2f, 0x26, 0xa3,
-	0x3b, 0xd7, 0x41, 0x7f, 0xe1, 0x7d, 0xd0, 0x6c, 0x2f, 0xb0, 0xdd, 0x8b, 0xd1, 0x04, 0x29, 0xf8,
-	0x6f, 0xd0, 0x6d, 0x2f, 0x18, 0x5e, 0x8f, 0xc7, 0x23, 0x1f, 0xd5, 0xf0, 0x21, 0x18, 0xb6, 0x17,
-	0x90, 0x6b, 0xcf, 0xb3, 0xcf, 0x87, 0x57, 0x48, 0xc5, 0x27, 0xd0, 0xb2, 0xbd, 0xc0, 0x19, 0x7b,
-	0x81, 0xe3, 0xde, 0x10, 0x77, 0x78, 0xee, 0xbb, 0x0e, 0xaa, 0x63, 0x80, 0x66, 0x9e, 0x76, 0x3c,
-	0xd4, 0x28, 0xe3, 0xa9, 0xeb, 0xa3, 0x66, 0xd9, 0x6e, 0x34, 0x99, 0xba, 0xc4, 0x47, 0x7b, 0x25,
-	0xbc, 0xbd, 0x71, 0xce, 0x7d, 0x17, 0x69, 0x25, 0x74, 0x5c, 0xcf, 0xf5, 0x5d, 0xa4, 0x5f, 0xd6,
-	0xb5, 0x1a, 0x52, 0x2f, 0xeb, 0x9a, 0x8a, 0xea, 0xbd, 0x4f, 0x0a, 0x9c, 0x4c, 0x45, 0x4a, 0xc3,
-	0xc7, 0x2b, 0x9a, 0x91, 0x30, 0x9e, 0x53, 0x42, 0x9f, 0x9e, 0x29, 0x17, 0xb8, 0x03, 0x5a, 0xc2,
-	0xf8, 0x22, 0xf7, 0x4e, 0x1a, 0xac, 0x93, 0x0a, 0xe3, 0x01, 0xe8, 0x4b, 0x9a, 0x05, 0x69, 0xce,
-	0x2f, 0x0d, 0xc3, 0x66, 0xb5, 0x90, 0x55, 0x27, 0x6d, 0x59, 0x46, 0xbb, 0xfe, 0xaa, 0xbf, 0xf6,
-	0xb7, 0xf7, 0x01, 0x4e, 0x5f, 0x0f, 0xc5, 0x13, 0x16, 0x73, 0x8a, 0x3d, 0xc0, 0x45, 0x61, 0x20,
-	0xb6, 0x6f, 0x2b, 0xe7, 0x33, 0xac, 0x7f, 0x7e, 0xba, 0x00, 0xa4, 0x35, 0x7b, 0x9d, 0xea, 0x7d,
-	0x84, 0xa3, 0xe2, 0x1e, 0x3f, 0x9c, 0xad, 0x28, 0x7f, 0x8b, 0xf4, 0x53, 0x68, 0x0a, 0x49, 0x6e,
-	0xd7, 0xba, 0x6a, 0x5f, 0x27, 0x25, 0xfa, 0x5d, 0x85, 0xf7, 0x70, 0xfc, 0xf2, 0xe6, 0x3f, 0xa2,
-	0xef, 0x8b, 0x02, 0xfb, 0x05, 0x71, 0xca, 0x9e, 0xd3, 0x88, 0xe6, 0xca, 0x96, 0x34, 0xe3, 0x49,
-	0x18, 0xd1, 0x8d, 0xb2, 0x0d, 0xc6, 0xc7, 0xd0, 0xe0, 0x0f, 0x61, 0x7a, 0x2f, 0x1f, 0x54, 0x27,
-	0x05, 0xc0, 0xef, 0xc1, 0x90, 0x0a, 0x45, 0x20, 0xb2, 0x84, 0x4a, 0x6d, 0x07, 0xd6, 0xf1, 0xf6,
-	0xb1, 0xe5, 0xfc, 0xc2, 0xcf, 0x12, 0x4a, 0x40, 0x54, 0xf1, 0xcb, 0x0d, 0xa9, 0xbf, 0x61, 0x43,
-	0xb6, 0xbe, 0x36, 0x76, 0x7d, 0xb5, 0xff, 0xbd, 0x3b, 0x5b, 0x2f, 0x04, 0xe5, 0xdc, 0x5c, 0xb0,
-	0x41, 0x11, 0x0d, 0xe6, 0x6c, 0xb0, 0x16, 0x03, 0xf9, 0xef, 0x0d, 0xb6, 0x96, 0xcc, 0x9a, 0x32,
-	0xf3, 0xee, 0x7b, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd6, 0x08, 0xae, 0x13, 0x46, 0x05, 0x00, 0x00,
+func init() { proto.RegisterFile("binlogdata.proto", fileDescriptor_binlogdata_af9050c1d68df22c) }
+
+var fileDescriptor_binlogdata_af9050c1d68df22c = []byte{
+	// 643 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb5, 0x54, 0xdb, 0x6e, 0xda, 0x40,
+	0x10, 0x2d, 0x98, 0x8b, 0x19, 0xa7, 0x89, 0xd9, 0x5c, 0x84, 0x90, 0x2a, 0x55, 0xbc, 0x24, 0xad,
+	0x54, 0x53, 0xb9, 0xea, 0x07, 0x04, 0xb0, 0x22, 0x1a, 0x43, 0xa2, 0xc5, 0x79, 0xc9, 0x8b, 0x65,
+	0x9c, 0x2d, 0x41, 0x21, 0x5e, 0xc7, 0xbb, 0x89, 0xca, 0x77, 0xf4, 0x2b, 0xfa, 0x23, 0xfd, 0x93,
+	0xbe, 0xf5, 0x23, 0x3a, 0x5e, 0x1b, 0x43, 0x52, 0xa9, 0x4d, 0x1f, 0xfa, 0x00, 0x9a, 0x33, 0x97,
+	0xb3, 0x33, 0x67, 0x76, 0x0d, 0xe6, 0x74, 0x1e, 0x2d, 0xf8, 0xec, 0x2a, 0x90, 0x81, 0x15, 0x27,
+	0x5c, 0x72, 0x02, 0x6b, 0x4f, 0xdb, 0xb8, 0xbb, 0x67, 0xc9, 0x32, 0x0b, 0xb4, 0xb7, 0x25, 0x8f,
+	0xf9, 0x3a, 0xb1, 0x33, 0x82, 0x7a, 0xff, 0x3a, 0x48, 0x04, 0x93, 0xe4, 0x00, 0x6a, 0xe1, 0x62,
+	0xce, 0x22, 0xd9, 0x2a, 0xbd, 0x2e, 0x1d, 0x55, 0x69, 0x8e, 0x08, 0x81, 0x4a, 0xc8, 0xa3, 0xa8,
+	0x55, 0x56, 0x5e, 0x65, 0xa7, 0xb9, 0x82, 0x25, 0x0f, 0x2c, 0x69, 0x69, 0x59, 0x6e, 0x86, 0x3a,
+	0x3f, 0x34, 0x68, 0xf6, 0xd4, 0xd1, 0x5e, 0x12, 0x44, 0x22, 0x08, 0xe5, 0x9c, 0x47, 0xe4, 0x04,
+	0x40, 0xc8, 0x40, 0xb2, 0x5b, 0xa4, 0x13, 0xc8, 0xae, 0x1d, 0x19, 0xf6, 0xa1, 0xb5, 0xd1, 0xf4,
+	0x6f, 0x25, 0xd6, 0x64, 0x95, 0x4f, 0x37, 0x4a, 0x89, 0x0d, 0x06, 0x7b, 0x40, 0xcb, 0x97, 0xfc,
+	0x86, 0x45, 0xad, 0x0a, 0x9e, 0x6d, 0xd8, 0x4d, 0x2b, 0x1b, 0xd0, 0x49, 0x23, 0x5e, 0x1a, 0xa0,
+	0xc0, 0x0a, 0xbb, 0xfd, 0xbd, 0x0c, 0x8d, 0x82, 0x8d, 0xb8, 0xa0, 0x87, 0x68, 0xcf, 0x78, 0xb2,
+	0x54, 0x63, 0x6e, 0xdb, 0xef, 0x9f, 0xd9, 0x88, 0xd5, 0xcf, 0xeb, 0x68, 0xc1, 0x40, 0xde, 0x41,
+	0x3d, 0xcc, 0xd4, 0x53, 0xea, 0x18, 0xf6, 0xee, 0x26, 0x59, 0x2e, 0x2c, 0x5d, 0xe5, 0x10, 0x13,
+	0x34, 0x71, 0xb7, 0x50, 0x92, 0x6d, 0xd1, 0xd4, 0xec, 0x7c, 0x2b, 0x81, 0xbe, 0xe2, 0x25, 0xbb,
+	0xb0, 0xd3, 0x73, 0xfd, 0x8b, 0x31, 0x75, 0xfa, 0x67, 0x27, 0xe3, 0xe1, 0xa5, 0x33, 0x30, 0x5f,
+	0x90, 0x2d, 0xd0, 0xd1, 0xd9, 0x73, 0x4e, 0x86, 0x63, 0xb3, 0x44, 0x5e, 0x42, 0x03, 0x51, 0xff,
+	0x6c, 0x34, 0x1a, 0x7a, 0x66, 0x99, 0xec, 0x80, 0x81, 0x90, 0x9e, 0xb9, 0x6e, 0xef, 0xb8, 0x7f,
+	0x6a, 0x6a, 0x64, 0x1f, 0xe5, 0x77, 0xfd, 0xc1, 0x08, 0x7f, 0xce, 0x39, 0xf2, 0x1c, 0x7b, 0x48,
+	0x52, 0x21, 0x00, 0xb5, 0xd4, 0x3d, 0x70, 0xcd, 0x6a, 0x6e, 0x4f, 0x1c, 0xcf, 0xac, 0xe5, 0x74,
+	0xc3, 0xf1, 0xc4, 0xa1, 0x9e, 0x59, 0xcf, 0xe1, 0xc5, 0xf9, 0x00, 0xcb, 0x4c, 0x3d, 0x87, 0x03,
+	0xc7, 0x75, 0x10, 0x36, 0x3e, 0x55, 0xf4, 0xb2, 0xa9, 0xe1, 0xbf, 0x66, 0x56, 0x3a, 0x5f, 0x4b,
+	0xb0, 0x3f, 0x91, 0x09, 0x0b, 0x6e, 0x4f, 0xd9, 0x92, 0x06, 0xd1, 0x8c, 0x51, 0x86, 0x5b, 0x10,
+	0x92, 0xb4, 0x41, 0x8f, 0xb9, 0x98, 0xa7, 0xda, 0x29, 0x81, 0x1b, 0xb4, 0xc0, 0xa4, 0x0b, 0x8d,
+	0x1b, 0xb6, 0xf4, 0x93, 0x34, 0x3f, 0x17, 0x8c, 0x58, 0xc5, 0x85, 0x2c, 0x98, 0xf4, 0x9b, 0xdc,
+	0xda, 0xd4, 0x57, 0xfb, 0xbb, 0xbe, 0x9d, 0xcf, 0x70, 0xf0, 0xb4, 0x29, 0x11, 0xf3, 0x48, 0x30,
+	0x5c, 0x3b, 0xc9, 0x0a, 0x7d, 0xb9, 0xde, 0xad, 0xea, 0xcf, 0xb0, 0x5f, 0xfd, 0xf1, 0x02, 0xd0,
+	0xe6, 0xf4, 0xa9, 0xab, 0xf3, 0x05, 0x76, 0xb3, 0x73, 0xbc, 0x60, 0xba, 0x60, 0xe2, 0x39, 0xa3,
+	0xe3, 0x83, 0x91, 0x2a, 0x19, 0xe7, 0xd6, 0x30, 0x92, 0xa3, 0x7f, 0x9d, 0xf0, 0x0a, 0xf6, 0x1e,
+	0x9f, 0xfc, 0x5f, 0xe6, 0xfb, 0x59, 0x82, 0xad, 0x2c, 0x71, 0xc2, 0xef, 0x93, 0x90, 0xa5, 0x93,
+	0xe1, 0x4e, 0x44, 0x1c, 0x84, 0x6c, 0x35, 0xd9, 0x0a, 0x93, 0x3d, 0xa8, 0x0a, 0xec, 0xee, 0x4a,
+	0x2d, 0xb4, 0x41, 0x33, 0x40, 0x3e, 0x82, 0xa1, 0x26, 0xc4, 0xa7, 0xba, 0x8c, 0x99, 0x9a, 0x6d,
+	0xdb, 0xde, 0x5b, 0x2f, 0x5b, 0xf5, 0x2f, 0x3d, 0x8c, 0x51, 0x90, 0x85, 0xfd, 0xf8, 0x86, 0x54,
+	0x9e, 0x71, 0x43, 0xd6, 0xba, 0x56, 0x1f, 0xe9, 0xfa, 0x16, 0x9a, 0xf8, 0x4a, 0xc3, 0x6b, 0xff,
+	0x3e, 0xf6, 0x8b, 0xa5, 0xd4, 0x54, 0x87, 0x3b, 0x2a, 0x70, 0x11, 0x9f, 0xe7, 0xee, 0xde, 0x9b,
+	0xcb, 0xc3, 0x87, 0xb9, 0x64, 0x42, 0x58, 0x73, 0xde, 0xcd, 0xac, 0xee, 0x0c, 0x2d, 0xd9, 0x55,
+	0xdf, 0xc8, 0xee, 0x5a, 0xbe, 0x69, 0x4d, 0x79, 0x3e, 0xfc, 0x02, 0x0a, 0xff, 0x07, 0x49, 0x72,
+	0x05, 0x00, 0x00,
 }
@@ -411,6 +411,10 @@ const (
 	ExecuteOptions_READ_COMMITTED   ExecuteOptions_TransactionIsolation = 2
 	ExecuteOptions_READ_UNCOMMITTED ExecuteOptions_TransactionIsolation = 3
 	ExecuteOptions_SERIALIZABLE     ExecuteOptions_TransactionIsolation = 4
+	// This is not an "official" transaction level but it will do a
+	// START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY.
+	// It is the only transaction which may be started on non-master tablets.
+	ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY ExecuteOptions_TransactionIsolation = 5
 )
 
 var ExecuteOptions_TransactionIsolation_name = map[int32]string{
@@ -419,13 +423,15 @@ var ExecuteOptions_TransactionIsolation_name = map[int32]string{
 	2: "READ_COMMITTED",
 	3: "READ_UNCOMMITTED",
 	4: "SERIALIZABLE",
+	5: "CONSISTENT_SNAPSHOT_READ_ONLY",
 }
 var ExecuteOptions_TransactionIsolation_value = map[string]int32{
-	"DEFAULT":          0,
-	"REPEATABLE_READ":  1,
-	"READ_COMMITTED":   2,
-	"READ_UNCOMMITTED": 3,
-	"SERIALIZABLE":     4,
+	"DEFAULT":                       0,
+	"REPEATABLE_READ":               1,
+	"READ_COMMITTED":                2,
+	"READ_UNCOMMITTED":              3,
+	"SERIALIZABLE":                  4,
+	"CONSISTENT_SNAPSHOT_READ_ONLY": 5,
 }
 
 func (x ExecuteOptions_TransactionIsolation) String() string {
@@ -4167,207 +4173,206 @@ func init() {
 func init() { proto.RegisterFile("query.proto", fileDescriptor_query_9111254583ad7475) }
 
 var fileDescriptor_query_9111254583ad7475 = []byte{
-	// 3231 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0x4b, 0x73, 0x1b, 0xc7,
-	0x76, 0xd6, 0xe0, 0x41, 0x02, 0x07, 0x04, 0xd8, 0x6c, 0x90, 0x12, 0x44, 0xf9, 0xda, 0xcc, 0xdc,
-	0xab, 0x7b, 0x19, 0xde, 0x1b, 0x4a, 0xa6, 0x64, 0x45, 0xb1, 0x1d, 0x47, 0x43, 0x70, 0x28, 0xc3,
-	0xc2, 0x4b, 0x8d, 0x81, 0x64, 0xa9, 0x5c, 0x35, 0x35, 0x04, 0x5a, 0xe0, 0x14, 0x07, 0x33, 0xd0,
-	0xcc, 0x80, 0x14, 0x77, 0x4a, 0x1c, 0xe7, 0xfd, 0x70, 0x9e, 0x8e, 0x93, 0x8a, 0x93, 0xaa, 0xec,
-	0xf3, 0x1b, 0x52, 0xf9, 0x01, 0xd9, 0x65, 0x91, 0x64, 0x91, 0x45, 0x2a, 0x95, 0x45, 0xaa, 0x5c,
-	0x59, 0x65, 0x91, 0x45, 0x2a, 0xd5, 0x8f, 0x19, 0x0c, 0x48, 0xe8, 0x61, 0xe5, 0x6e, 0x28, 0x7b,
-	0xd7, 0x7d, 0xce, 0xe9, 0xc7, 0xf7, 0x9d, 0x33, 0xa7, 0x7b, 0xba, 0x1b, 0x0a, 0x8f, 0xc7, 0xd4,
-	0x3f, 0xde, 0x1c, 0xf9, 0x5e, 0xe8, 0xe1, 0x2c, 0xaf, 0xac, 0x96, 0x42, 0x6f, 0xe4, 0xf5, 0xad,
-	0xd0, 0x12, 0xe2, 0xd5, 0xc2, 0x61, 0xe8, 0x8f, 0x7a, 0xa2, 0xa2, 0x7e, 0xa6, 0xc0, 0x9c, 0x61,
-	0xf9, 0x03, 0x1a, 0xe2, 0x55, 0xc8, 0x1d, 0xd0, 0xe3, 0x60, 0x64, 0xf5, 0x68, 0x45, 0x59, 0x53,
-	0xd6, 0xf3, 0x24, 0xae, 0xe3, 0x65, 0xc8, 0x06, 0xfb, 0x96, 0xdf, 0xaf, 0xa4, 0xb8, 0x42, 0x54,
-	0xf0, 0x3b, 0x50, 0x08, 0xad, 0x3d, 0x87, 0x86, 0x66, 0x78, 0x3c, 0xa2, 0x95, 0xf4, 0x9a, 0xb2,
-	0x5e, 0xda, 0x5a, 0xde, 0x8c, 0xc7, 0x33, 0xb8, 0xd2, 0x38, 0x1e, 0x51, 0x02, 0x61, 0x5c, 0xc6,
-	0x18, 0x32, 0x3d, 0xea, 0x38, 0x95, 0x0c, 0xef, 0x8b, 0x97, 0xd5, 0x1d, 0x28, 0xdd, 0x33, 0x6e,
-	0x5b, 0x21, 0xad, 0x5a, 0x8e, 0x43, 0xfd, 0xda, 0x0e, 0x9b, 0xce, 0x38, 0xa0, 0xbe, 0x6b, 0x0d,
-	0xe3, 0xe9, 0x44, 0x75, 0x7c, 0x1e, 0xe6, 0x06, 0xbe, 0x37, 0x1e, 0x05, 0x95, 0xd4, 0x5a, 0x7a,
-	0x3d, 0x4f, 0x64, 0x4d, 0xfd, 0x04, 0x40, 0x3f, 0xa4, 0x6e, 0x68, 0x78, 0x07, 0xd4, 0xc5, 0x6f,
-	0x40, 0x3e, 0xb4, 0x87, 0x34, 0x08, 0xad, 0xe1, 0x88, 0x77, 0x91, 0x26, 0x13, 0xc1, 0x33, 0x20,
-	0xad, 0x42, 0x6e, 0xe4, 0x05, 0x76, 0x68, 0x7b, 0x2e, 0xc7, 0x93, 0x27, 0x71, 0x5d, 0xfd, 0x00,
-	0xb2, 0xf7, 0x2c, 0x67, 0x4c, 0xf1, 0x5b, 0x90, 0xe1, 0x80, 0x15, 0x0e, 0xb8, 0xb0, 0x29, 0x48,
-	0xe7, 0x38, 0xb9, 0x82, 0xf5, 0x7d, 0xc8, 0x2c, 0x79, 0xdf, 0x0b, 0x44, 0x54, 0xd4, 0x03, 0x58,
-	0xd8, 0xb6, 0xdd, 0xfe, 0x3d, 0xcb, 0xb7, 0x19, 0x19, 0xaf, 0xd8, 0x0d, 0xfe, 0x01, 0xcc, 0xf1,
-	0x42, 0x50, 0x49, 0xaf, 0xa5, 0xd7, 0x0b, 0x5b, 0x0b, 0xb2, 0x21, 0x9f, 0x1b, 0x91, 0x3a, 0xf5,
-	0xef, 0x15, 0x80, 0x6d, 0x6f, 0xec, 0xf6, 0xef, 0x32, 0x25, 0x46, 0x90, 0x0e, 0x1e, 0x3b, 0x92,
-	0x48, 0x56, 0xc4, 0x77, 0xa0, 0xb4, 0x67, 0xbb, 0x7d, 0xf3, 0x50, 0x4e, 0x47, 0x70, 0x59, 0xd8,
-	0xfa, 0x81, 0xec, 0x6e, 0xd2, 0x78, 0x33, 0x39, 0xeb, 0x40, 0x77, 0x43, 0xff, 0x98, 0x14, 0xf7,
-	0x92, 0xb2, 0xd5, 0x2e, 0xe0, 0xd3, 0x46, 0x6c, 0xd0, 0x03, 0x7a, 0x1c, 0x0d, 0x7a, 0x40, 0x8f,
-	0xf1, 0xcf, 0x26, 0x11, 0x15, 0xb6, 0xca, 0xd1, 0x58, 0x89, 0xb6, 0x12, 0xe6, 0xbb, 0xa9, 0x9b,
-	0x8a, 0xfa, 0x9f, 0x59, 0x28, 0xe9, 0x4f, 0x68, 0x6f, 0x1c, 0xd2, 0xd6, 0x88, 0xf9, 0x20, 0xc0,
-	0x9b, 0x50, 0xb6, 0xdd, 0x9e, 0x33, 0xee, 0x53, 0x93, 0x32, 0x57, 0x9b, 0x21, 0xf3, 0x35, 0xef,
-	0x2f, 0x47, 0x96, 0xa4, 0x2a, 0x11, 0x04, 0x1a, 0x94, 0x7b, 0xde, 0x70, 0x64, 0xf9, 0xd3, 0xf6,
-	0x69, 0x3e, 0xfe, 0x92, 0x1c, 0x7f, 0x62, 0x4f, 0x96, 0xa4, 0x75, 0xa2, 0x8b, 0x06, 0x2c, 0xca,
-	0x7e, 0xfb, 0xe6, 0x23, 0x9b, 0x3a, 0xfd, 0x80, 0x87, 0x6e, 0x29, 0xa6, 0x6a, 0x7a, 0x8a, 0x9b,
-	0x35, 0x69, 0xbc, 0xcb, 0x6d, 0x49, 0xc9, 0x9e, 0xaa, 0xe3, 0x0d, 0x58, 0xea, 0x39, 0x36, 0x9b,
-	0xca, 0x23, 0x46, 0xb1, 0xe9, 0x7b, 0x47, 0x41, 0x25, 0xcb, 0xe7, 0xbf, 0x28, 0x14, 0xbb, 0x4c,
-	0x4e, 0xbc, 0xa3, 0x00, 0xbf, 0x0b, 0xb9, 0x23, 0xcf, 0x3f, 0x70, 0x3c, 0xab, 0x5f, 0x99, 0xe3,
-	0x63, 0xbe, 0x39, 0x7b, 0xcc, 0xfb, 0xd2, 0x8a, 0xc4, 0xf6, 0x78, 0x1d, 0x50, 0xf0, 0xd8, 0x31,
-	0x03, 0xea, 0xd0, 0x5e, 0x68, 0x3a, 0xf6, 0xd0, 0x0e, 0x2b, 0x39, 0xfe, 0x15, 0x94, 0x82, 0xc7,
-	0x4e, 0x87, 0x8b, 0xeb, 0x4c, 0x8a, 0x4d, 0x58, 0x09, 0x7d, 0xcb, 0x0d, 0xac, 0x1e, 0xeb, 0xcc,
-	0xb4, 0x03, 0xcf, 0xb1, 0xf8, 0x17, 0x90, 0xe7, 0x43, 0x6e, 0xcc, 0x1e, 0xd2, 0x98, 0x34, 0xa9,
-	0x45, 0x2d, 0xc8, 0x72, 0x38, 0x43, 0x8a, 0xdf, 0x86, 0x95, 0xe0, 0xc0, 0x1e, 0x99, 0xbc, 0x1f,
-	0x73, 0xe4, 0x58, 0xae, 0xd9, 0xb3, 0x7a, 0xfb, 0xb4, 0x02, 0x1c, 0x36, 0x66, 0x4a, 0x1e, 0x6a,
-	0x6d, 0xc7, 0x72, 0xab, 0x4c, 0xa3, 0xbe, 0x07, 0xa5, 0x69, 0x1e, 0xf1, 0x12, 0x14, 0x8d, 0x07,
-	0x6d, 0xdd, 0xd4, 0x9a, 0x3b, 0x66, 0x53, 0x6b, 0xe8, 0xe8, 0x1c, 0x2e, 0x42, 0x9e, 0x8b, 0x5a,
-	0xcd, 0xfa, 0x03, 0xa4, 0xe0, 0x79, 0x48, 0x6b, 0xf5, 0x3a, 0x4a, 0xa9, 0x37, 0x21, 0x17, 0x11,
-	0x82, 0x17, 0xa1, 0xd0, 0x6d, 0x76, 0xda, 0x7a, 0xb5, 0xb6, 0x5b, 0xd3, 0x77, 0xd0, 0x39, 0x9c,
-	0x83, 0x4c, 0xab, 0x6e, 0xb4, 0x91, 0x22, 0x4a, 0x5a, 0x1b, 0xa5, 0x58, 0xcb, 0x9d, 0x6d, 0x0d,
-	0xa5, 0xd5, 0x10, 0x96, 0x67, 0xe1, 0xc2, 0x05, 0x98, 0xdf, 0xd1, 0x77, 0xb5, 0x6e, 0xdd, 0x40,
-	0xe7, 0x70, 0x19, 0x16, 0x89, 0xde, 0xd6, 0x35, 0x43, 0xdb, 0xae, 0xeb, 0x26, 0xd1, 0xb5, 0x1d,
-	0xa4, 0x60, 0x0c, 0x25, 0x56, 0x32, 0xab, 0xad, 0x46, 0xa3, 0x66, 0x18, 0xfa, 0x0e, 0x4a, 0xe1,
-	0x65, 0x40, 0x5c, 0xd6, 0x6d, 0x4e, 0xa4, 0x69, 0x8c, 0x60, 0xa1, 0xa3, 0x93, 0x9a, 0x56, 0xaf,
-	0x3d, 0x64, 0x1d, 0xa0, 0xcc, 0x47, 0x99, 0x9c, 0x82, 0x52, 0xea, 0x17, 0x29, 0xc8, 0x72, 0xac,
-	0x2c, 0x43, 0x26, 0xf2, 0x1e, 0x2f, 0xc7, 0xd9, 0x22, 0xf5, 0x9c, 0x6c, 0xc1, 0x93, 0xac, 0xcc,
-	0x5b, 0xa2, 0x82, 0x2f, 0x41, 0xde, 0xf3, 0x07, 0xa6, 0xd0, 0x88, 0x8c, 0x9b, 0xf3, 0xfc, 0x01,
-	0x4f, 0xcd, 0x2c, 0xdb, 0xb1, 0x44, 0xbd, 0x67, 0x05, 0x94, 0x47, 0x60, 0x9e, 0xc4, 0x75, 0x7c,
-	0x11, 0x98, 0x9d, 0xc9, 0xe7, 0x31, 0xc7, 0x75, 0xf3, 0x9e, 0x3f, 0x68, 0xb2, 0xa9, 0x7c, 0x1f,
-	0x8a, 0x3d, 0xcf, 0x19, 0x0f, 0x5d, 0xd3, 0xa1, 0xee, 0x20, 0xdc, 0xaf, 0xcc, 0xaf, 0x29, 0xeb,
-	0x45, 0xb2, 0x20, 0x84, 0x75, 0x2e, 0xc3, 0x15, 0x98, 0xef, 0xed, 0x5b, 0x7e, 0x40, 0x45, 0xd4,
-	0x15, 0x49, 0x54, 0xe5, 0xa3, 0xd2, 0x9e, 0x3d, 0xb4, 0x9c, 0x80, 0x47, 0x58, 0x91, 0xc4, 0x75,
-	0x06, 0xe2, 0x91, 0x63, 0x0d, 0x02, 0x1e, 0x19, 0x45, 0x22, 0x2a, 0xea, 0xcf, 0x43, 0x9a, 0x78,
-	0x47, 0xac, 0x4b, 0x31, 0x60, 0x50, 0x51, 0xd6, 0xd2, 0xeb, 0x98, 0x44, 0x55, 0xb6, 0x20, 0xc8,
-	0x9c, 0x28, 0x52, 0x65, 0x94, 0x05, 0x3f, 0x81, 0x05, 0x42, 0x83, 0xb1, 0x13, 0xea, 0x4f, 0x42,
-	0xdf, 0x0a, 0xf0, 0x16, 0x14, 0x92, 0x59, 0x40, 0x79, 0x56, 0x16, 0x00, 0x3a, 0xf9, 0xfc, 0x2b,
-	0x30, 0xff, 0xc8, 0xa7, 0xc1, 0x3e, 0xf5, 0x65, 0x96, 0x89, 0xaa, 0x2c, 0xc7, 0x16, 0x78, 0xd8,
-	0x8a, 0x31, 0x58, 0x66, 0x96, 0xf9, 0x41, 0x99, 0xca, 0xcc, 0xdc, 0xa9, 0x44, 0xea, 0x18, 0x7b,
-	0xec, 0x93, 0x37, 0xad, 0x47, 0x8f, 0x68, 0x2f, 0xa4, 0x62, 0x01, 0xca, 0x90, 0x05, 0x26, 0xd4,
-	0xa4, 0x8c, 0xb9, 0xcd, 0x76, 0x03, 0xea, 0x87, 0xa6, 0xdd, 0xe7, 0x0e, 0xcd, 0x90, 0x9c, 0x10,
-	0xd4, 0xfa, 0xf8, 0x4d, 0xc8, 0xf0, 0xa4, 0x91, 0xe1, 0xa3, 0x80, 0x1c, 0x85, 0x78, 0x47, 0x84,
-	0xcb, 0xf1, 0x8f, 0x61, 0x8e, 0x72, 0xbc, 0xdc, 0xa9, 0x93, 0x34, 0x9b, 0xa4, 0x82, 0x48, 0x13,
-	0xf5, 0x7d, 0x58, 0xe0, 0x18, 0xee, 0x5b, 0xbe, 0x6b, 0xbb, 0x03, 0xbe, 0x3a, 0x7b, 0x7d, 0x11,
-	0x7b, 0x45, 0xc2, 0xcb, 0x8c, 0x82, 0x21, 0x0d, 0x02, 0x6b, 0x40, 0xe5, 0x6a, 0x19, 0x55, 0xd5,
-	0xbf, 0x4e, 0x43, 0xa1, 0x13, 0xfa, 0xd4, 0x1a, 0x72, 0xf6, 0xf0, 0xfb, 0x00, 0x41, 0x68, 0x85,
-	0x74, 0x48, 0xdd, 0x30, 0xa2, 0xe1, 0x0d, 0x39, 0x7c, 0xc2, 0x6e, 0xb3, 0x13, 0x19, 0x91, 0x84,
-	0xfd, 0x49, 0xf7, 0xa4, 0x5e, 0xc2, 0x3d, 0xab, 0x5f, 0xa5, 0x20, 0x1f, 0xf7, 0x86, 0x35, 0xc8,
-	0xf5, 0xac, 0x90, 0x0e, 0x3c, 0xff, 0x58, 0xae, 0xab, 0x97, 0x9f, 0x37, 0xfa, 0x66, 0x55, 0x1a,
-	0x93, 0xb8, 0x19, 0xfe, 0x1e, 0x88, 0xcd, 0x8a, 0x08, 0x7d, 0x81, 0x37, 0xcf, 0x25, 0x3c, 0xf8,
-	0xdf, 0x05, 0x3c, 0xf2, 0xed, 0xa1, 0xe5, 0x1f, 0x9b, 0x07, 0xf4, 0x38, 0x5a, 0x10, 0xd2, 0x33,
-	0x1c, 0x8e, 0xa4, 0xdd, 0x1d, 0x7a, 0x2c, 0x53, 0xd8, 0xcd, 0xe9, 0xb6, 0x32, 0x64, 0x4f, 0xbb,
-	0x31, 0xd1, 0x92, 0xaf, 0xea, 0x41, 0xb4, 0x7e, 0x67, 0x79, 0x74, 0xb3, 0xa2, 0xfa, 0x23, 0xc8,
-	0x45, 0x93, 0xc7, 0x79, 0xc8, 0xea, 0xbe, 0xef, 0xf9, 0xe8, 0x1c, 0xcf, 0x64, 0x8d, 0xba, 0x48,
-	0x86, 0x3b, 0x3b, 0x2c, 0x19, 0xfe, 0x5d, 0x2a, 0x5e, 0x44, 0x09, 0x7d, 0x3c, 0xa6, 0x41, 0x88,
-	0x7f, 0x09, 0xca, 0x94, 0x47, 0x9a, 0x7d, 0x48, 0xcd, 0x1e, 0xdf, 0x71, 0xb1, 0x38, 0x13, 0x9f,
-	0xc3, 0xe2, 0xa6, 0xd8, 0x20, 0x46, 0x3b, 0x31, 0xb2, 0x14, 0xdb, 0x4a, 0x51, 0x1f, 0xeb, 0x50,
-	0xb6, 0x87, 0x43, 0xda, 0xb7, 0xad, 0x30, 0xd9, 0x81, 0x70, 0xd8, 0x4a, 0xb4, 0x21, 0x99, 0xda,
-	0xd0, 0x91, 0xa5, 0xb8, 0x45, 0xdc, 0xcd, 0x65, 0x98, 0x0b, 0xf9, 0xe6, 0x53, 0xae, 0xc7, 0xc5,
-	0x28, 0xab, 0x71, 0x21, 0x91, 0x4a, 0xfc, 0x23, 0x10, 0x5b, 0x59, 0x9e, 0xbf, 0x26, 0x01, 0x31,
-	0xd9, 0xa1, 0x10, 0xa1, 0xc7, 0x97, 0xa1, 0x34, 0xb5, 0x90, 0xf5, 0x39, 0x61, 0x69, 0x52, 0x4c,
-	0xae, 0x4a, 0x7d, 0x7c, 0x05, 0xe6, 0x3d, 0xb1, 0x88, 0xf1, 0xcc, 0x36, 0x99, 0xf1, 0xf4, 0x0a,
-	0x47, 0x22, 0x2b, 0xf5, 0x17, 0x61, 0x31, 0x66, 0x30, 0x18, 0x79, 0x6e, 0x40, 0xf1, 0x06, 0xcc,
-	0xf9, 0xfc, 0x73, 0x92, 0xac, 0x61, 0xd9, 0x45, 0x22, 0x1f, 0x10, 0x69, 0xa1, 0xf6, 0x61, 0x51,
-	0x48, 0xee, 0xdb, 0xe1, 0x3e, 0x77, 0x14, 0xbe, 0x0c, 0x59, 0xca, 0x0a, 0x27, 0x38, 0x27, 0xed,
-	0x2a, 0xd7, 0x13, 0xa1, 0x4d, 0x8c, 0x92, 0x7a, 0xe1, 0x28, 0xff, 0x95, 0x82, 0xb2, 0x9c, 0xe5,
-	0xb6, 0x15, 0xf6, 0xf6, 0xcf, 0xa8, 0xb3, 0x7f, 0x0c, 0xf3, 0x4c, 0x6e, 0xc7, 0x1f, 0xc6, 0x0c,
-	0x77, 0x47, 0x16, 0xcc, 0xe1, 0x56, 0x60, 0x26, 0xbc, 0x2b, 0x37, 0x52, 0x45, 0x2b, 0x48, 0x2c,
-	0xe3, 0x33, 0xe2, 0x62, 0xee, 0x05, 0x71, 0x31, 0xff, 0x52, 0x71, 0xb1, 0x03, 0xcb, 0xd3, 0x8c,
-	0xcb, 0xe0, 0xf8, 0x09, 0xcc, 0x0b, 0xa7, 0x44, 0x29, 0x70, 0x96, 0xdf, 0x22, 0x13, 0xf5, 0xaf,
-	0x52, 0xb0, 0x2c, 0xb3, 0xd3, 0xb7, 0xe3, 0x33, 0x4d, 0xf0, 0x9c, 0x7d, 0x29, 0x9e, 0xab, 0xb0,
-	0x72, 0x82, 0xa0, 0x57, 0xf8, 0x0a, 0xbf, 0x56, 0x60, 0x61, 0x9b, 0x0e, 0x6c, 0xf7, 0x8c, 0xd2,
-	0x9b, 0x60, 0x2d, 0xf3, 0x52, 0xac, 0xdd, 0x80, 0xa2, 0xc4, 0x2b, 0xd9, 0x3a, 0xfd, 0x19, 0x28,
-	0x33, 0x3e, 0x03, 0xf5, 0xdf, 0x15, 0x28, 0x56, 0xbd, 0xe1, 0xd0, 0x0e, 0xcf, 0x28, 0x53, 0xa7,
-	0x71, 0x66, 0x66, 0xe1, 0x44, 0x50, 0x8a, 0x60, 0x0a, 0x82, 0xd4, 0xff, 0x50, 0x60, 0x91, 0x78,
-	0x8e, 0xb3, 0x67, 0xf5, 0x0e, 0x5e, 0x6f, 0xec, 0x18, 0xd0, 0x04, 0xa8, 0x44, 0xff, 0x3f, 0x0a,
-	0x94, 0xda, 0x3e, 0x65, 0x7f, 0xbf, 0xaf, 0x35, 0x78, 0xb6, 0xc5, 0xed, 0x87, 0x72, 0x73, 0x90,
-	0x27, 0xbc, 0xac, 0x2e, 0xc1, 0x62, 0x8c, 0x5d, 0xf2, 0xf1, 0xcf, 0x0a, 0xac, 0x88, 0x00, 0x91,
-	0x9a, 0xfe, 0x19, 0xa5, 0x25, 0xc2, 0x9b, 0x49, 0xe0, 0xad, 0xc0, 0xf9, 0x93, 0xd8, 0x24, 0xec,
-	0x4f, 0x53, 0x70, 0x21, 0x8a, 0x8d, 0x33, 0x0e, 0xfc, 0xff, 0x11, 0x0f, 0xab, 0x50, 0x39, 0x4d,
-	0x82, 0x64, 0xe8, 0xf3, 0x14, 0x54, 0xaa, 0x3e, 0xb5, 0x42, 0x9a, 0xd8, 0x64, 0xbc, 0x3e, 0xb1,
-	0x81, 0xdf, 0x86, 0x85, 0x91, 0xe5, 0x87, 0x76, 0xcf, 0x1e, 0x59, 0xec, 0x37, 0x2e, 0xcb, 0xf7,
-	0x30, 0x27, 0x3a, 0x98, 0x32, 0x51, 0x2f, 0xc1, 0xc5, 0x19, 0x8c, 0x48, 0xbe, 0xfe, 0x57, 0x01,
-	0xdc, 0x09, 0x2d, 0x3f, 0xfc, 0x16, 0xac, 0x2a, 0x33, 0x83, 0x69, 0x05, 0xca, 0x53, 0xf8, 0x93,
-	0xbc, 0xd0, 0xf0, 0x5b, 0xb1, 0xe2, 0x3c, 0x93, 0x97, 0x24, 0x7e, 0xc9, 0xcb, 0xbf, 0x2a, 0xb0,
-	0x5a, 0xf5, 0xc4, 0xe9, 0xdf, 0x6b, 0xf9, 0x85, 0xa9, 0xdf, 0x83, 0x4b, 0x33, 0x01, 0x4a, 0x02,
-	0xfe, 0x45, 0x81, 0xf3, 0x84, 0x5a, 0xfd, 0xd7, 0x13, 0xfc, 0x5d, 0xb8, 0x70, 0x0a, 0x9c, 0xdc,
-	0xa1, 0xde, 0x80, 0xdc, 0x90, 0x86, 0x56, 0xdf, 0x0a, 0x2d, 0x09, 0x69, 0x35, 0xea, 0x77, 0x62,
-	0xdd, 0x90, 0x16, 0x24, 0xb6, 0x55, 0xbf, 0x4a, 0x41, 0x99, 0xef, 0x75, 0xbf, 0xfb, 0x83, 0x9a,
-	0xfd, 0x2f, 0xf0, 0xb9, 0x02, 0xcb, 0xd3, 0x04, 0xc5, 0xff, 0x04, 0x3f, 0xed, 0x83, 0x88, 0x19,
-	0x09, 0x21, 0x3d, 0x6b, 0x0b, 0xfa, 0x0f, 0x29, 0xa8, 0x24, 0xa7, 0xf4, 0xdd, 0xa1, 0xc5, 0xf4,
-	0xa1, 0xc5, 0x37, 0x3e, 0xa5, 0xfa, 0x42, 0x81, 0x8b, 0x33, 0x08, 0xfd, 0x66, 0x8e, 0x4e, 0x1c,
-	0x5d, 0xa4, 0x5e, 0x78, 0x74, 0xf1, 0xb2, 0xae, 0xfe, 0x27, 0x05, 0x96, 0x1b, 0xe2, 0xc4, 0x58,
-	0xfc, 0xc7, 0x9f, 0xdd, 0x6c, 0xc6, 0x0f, 0x85, 0x33, 0x93, 0x7b, 0x19, 0xb5, 0x0a, 0x2b, 0x27,
-	0xa0, 0xbd, 0xc2, 0xd9, 0xc4, 0x7f, 0x2b, 0xb0, 0x24, 0x7b, 0xd1, 0xce, 0xec, 0x46, 0x60, 0x06,
-	0x3b, 0xf8, 0x4d, 0x48, 0xdb, 0xfd, 0x68, 0x07, 0x39, 0x7d, 0x53, 0xcd, 0x14, 0xea, 0x2d, 0xc0,
-	0x49, 0xdc, 0xaf, 0x40, 0xdd, 0x3f, 0xa6, 0x61, 0xa9, 0x33, 0x72, 0xec, 0x50, 0x2a, 0x5f, 0xef,
-	0xc4, 0xff, 0x33, 0xb0, 0x10, 0x30, 0xb0, 0xa6, 0xb8, 0x6b, 0xe3, 0xc4, 0xe6, 0x49, 0x81, 0xcb,
-	0xaa, 0x5c, 0x84, 0xdf, 0x82, 0x42, 0x64, 0x32, 0x76, 0x43, 0x79, 0xd2, 0x09, 0xd2, 0x62, 0xec,
-	0x86, 0xf8, 0x3a, 0x5c, 0x70, 0xc7, 0x43, 0x7e, 0xef, 0x6c, 0x8e, 0xa8, 0x1f, 0xdd, 0xca, 0x5a,
-	0x7e, 0x74, 0x3f, 0x5c, 0x76, 0xc7, 0x43, 0xe2, 0x1d, 0x05, 0x6d, 0xea, 0x8b, 0x5b, 0x59, 0xcb,
-	0x0f, 0xf1, 0x2d, 0xc8, 0x5b, 0xce, 0xc0, 0xf3, 0xed, 0x70, 0x7f, 0x28, 0x2f, 0x86, 0xd5, 0xe8,
-	0x6a, 0xe5, 0x24, 0xfd, 0x9b, 0x5a, 0x64, 0x49, 0x26, 0x8d, 0xd4, 0x9f, 0x40, 0x3e, 0x96, 0x63,
-	0x04, 0x0b, 0xfa, 0xdd, 0xae, 0x56, 0x37, 0x3b, 0xed, 0x7a, 0xcd, 0xe8, 0x88, 0xcb, 0xdc, 0xdd,
-	0x6e, 0xbd, 0x6e, 0x76, 0xaa, 0x5a, 0x13, 0x29, 0x2a, 0x01, 0xe0, 0x5d, 0xf2, 0xce, 0x27, 0x04,
-	0x29, 0x2f, 0x20, 0xe8, 0x12, 0xe4, 0x7d, 0xef, 0x48, 0x62, 0x4f, 0x71, 0x38, 0x39, 0xdf, 0x3b,
-	0xe2, 0xc8, 0x55, 0x0d, 0x70, 0x72, 0xae, 0x32, 0xda, 0x12, 0xc9, 0x5b, 0x99, 0x4a, 0xde, 0x93,
-	0xf1, 0xe3, 0xe4, 0x2d, 0xb6, 0xf2, 0xec, 0x3b, 0xff, 0x90, 0x5a, 0x4e, 0x18, 0xad, 0x57, 0xea,
-	0xdf, 0xa4, 0xa0, 0x48, 0x98, 0xc4, 0x1e, 0xd2, 0x4e, 0x68, 0x85, 0x01, 0xf3, 0xd4, 0x3e, 0x37,
-	0x31, 0x27, 0x69, 0x37, 0x4f, 0x0a, 0x42, 0x26, 0x2e, 0x01, 0xb6, 0x60, 0x25, 0xa0, 0x3d, 0xcf,
-	0xed, 0x07, 0xe6, 0x1e, 0xdd, 0xb7, 0xdd, 0xbe, 0x39, 0xb4, 0x82, 0x50, 0xde, 0x33, 0x16, 0x49,
-	0x59, 0x2a, 0xb7, 0xb9, 0xae, 0xc1, 0x55, 0xf8, 0x2a, 0x2c, 0xef, 0xd9, 0xae, 0xe3, 0x0d, 0xcc,
-	0x91, 0x63, 0x1d, 0x53, 0x3f, 0x90, 0x50, 0x59, 0x78, 0x65, 0x09, 0x16, 0xba, 0xb6, 0x50, 0x09,
-	0x77, 0x3f, 0x84, 0x8d, 0x99, 0xa3, 0x98, 0x8f, 0x6c, 0x27, 0xa4, 0x3e, 0xed, 0x9b, 0x3e, 0x1d,
-	0x39, 0x76, 0x4f, 0x5c, 0xf9, 0x8b, 0xbd, 0xfb, 0x0f, 0x67, 0x0c, 0xbd, 0x2b, 0xcd, 0xc9, 0xc4,
-	0x9a, 0xb1, 0xdd, 0x1b, 0x8d, 0xcd, 0x31, 0xbf, 0x1a, 0x64, 0xab, 0x98, 0x42, 0x72, 0xbd, 0xd1,
-	0xb8, 0xcb, 0xea, 0x18, 0x41, 0xfa, 0xf1, 0x48, 0x2c, 0x5e, 0x0a, 0x61, 0x45, 0xf5, 0x6b, 0x05,
-	0x4a, 0xda, 0x60, 0xe0, 0xd3, 0x81, 0x15, 0x4a, 0x9a, 0xae, 0xc2, 0xb2, 0xa0, 0xe4, 0xd8, 0x94,
-	0x6f, 0x89, 0x04, 0x1e, 0x45, 0xe0, 0x91, 0x3a, 0xf1, 0x92, 0x28, 0x0a, 0xdf, 0xf3, 0x63, 0x77,
-	0x66, 0x9b, 0x14, 0x6f, 0xb3, 0x1c, 0x6b, 0x93, 0xad, 0x7e, 0x01, 0x2e, 0xce, 0x66, 0x61, 0x68,
-	0x8b, 0xd7, 0x20, 0x45, 0x72, 0x7e, 0x06, 0xe8, 0x86, 0xed, 0x3e, 0xa7, 0xa9, 0xf5, 0x84, 0xf3,
-	0xf5, 0x8c, 0xa6, 0xd6, 0x13, 0xf5, 0xdf, 0xe2, 0xa3, 0xfd, 0x28, 0x5c, 0xe2, 0xd5, 0x38, 0xca,
-	0x0b, 0xca, 0xf3, 0xf2, 0x42, 0x05, 0xe6, 0x03, 0xea, 0x1f, 0xda, 0xee, 0x20, 0xba, 0x7b, 0x96,
-	0x55, 0xdc, 0x81, 0x1f, 0x4a, 0xec, 0xf4, 0x49, 0x48, 0x7d, 0xd7, 0x72, 0x9c, 0x63, 0x53, 0x1c,
-	0x54, 0xb8, 0x21, 0xed, 0x9b, 0x93, 0x97, 0x4f, 0x62, 0x45, 0xfe, 0xbe, 0xb0, 0xd6, 0x63, 0x63,
-	0x12, 0xdb, 0x1a, 0xf1, 0x9b, 0xa8, 0xf7, 0xa0, 0xe4, 0xcb, 0x20, 0x36, 0x03, 0xe6, 0x1e, 0x99,
-	0x8f, 0x96, 0xe3, 0x0b, 0xe4, 0x44, 0x84, 0x93, 0xa2, 0x3f, 0x15, 0xf0, 0x1f, 0xc0, 0xa2, 0x15,
-	0xf9, 0x56, 0xb6, 0x9e, 0xde, 0xb7, 0x4c, 0x7b, 0x9e, 0x94, 0xac, 0xe9, 0x48, 0xb8, 0x09, 0x0b,
-	0x12, 0x91, 0xe5, 0xd8, 0xd6, 0x64, 0x63, 0x7b, 0xe2, 0x39, 0x99, 0xc6, 0x94, 0x44, 0x3e, 0x3c,
-	0xe3, 0x15, 0xf6, 0x1f, 0x5d, 0xee, 0x8e, 0xfa, 0xbc, 0xa7, 0x33, 0xbc, 0xbb, 0x48, 0xbe, 0x3d,
-	0xcb, 0x4c, 0xbf, 0x3d, 0x9b, 0x7e, 0xcb, 0x96, 0x3d, 0xf1, 0x96, 0x4d, 0xbd, 0x05, 0xcb, 0xd3,
-	0xf8, 0x65, 0x94, 0xad, 0x43, 0x96, 0xdf, 0x94, 0x9f, 0x58, 0x46, 0x13, 0x57, 0xe1, 0x44, 0x18,
-	0xa8, 0x7f, 0xab, 0x40, 0x79, 0xc6, 0x2f, 0x56, 0xfc, 0xff, 0xa6, 0x24, 0x8e, 0x87, 0x7e, 0x0e,
-	0xb2, 0xfc, 0xce, 0x5e, 0x3e, 0x45, 0xb9, 0x70, 0xfa, 0x0f, 0x8d, 0xdf, 0xaf, 0x13, 0x61, 0xc5,
-	0x12, 0x21, 0x0f, 0xa8, 0x1e, 0x3f, 0x1f, 0x8a, 0x76, 0x88, 0x05, 0x26, 0x13, 0x47, 0x46, 0xa7,
-	0x0f, 0x9c, 0x32, 0x2f, 0x3c, 0x70, 0xda, 0xf8, 0xc3, 0x34, 0xe4, 0x1b, 0xc7, 0x9d, 0xc7, 0xce,
-	0xae, 0x63, 0x0d, 0xf8, 0x05, 0x78, 0xa3, 0x6d, 0x3c, 0x40, 0xe7, 0xf0, 0x12, 0x14, 0x9b, 0x2d,
-	0xc3, 0x6c, 0xb2, 0xa5, 0x64, 0xb7, 0xae, 0xdd, 0x46, 0x0a, 0x5b, 0x6b, 0xda, 0xa4, 0x66, 0xde,
-	0xd1, 0x1f, 0x08, 0x49, 0x0a, 0x97, 0x61, 0xb1, 0xdb, 0xac, 0xdd, 0xed, 0xea, 0x13, 0x61, 0x06,
-	0xaf, 0xc0, 0x52, 0xa3, 0x5b, 0x37, 0x6a, 0xed, 0x7a, 0x42, 0x9c, 0x63, 0xeb, 0xd2, 0x76, 0xbd,
-	0xb5, 0x2d, 0xaa, 0x88, 0xf5, 0xdf, 0x6d, 0x76, 0x6a, 0xb7, 0x9b, 0xfa, 0x8e, 0x10, 0xad, 0x31,
-	0xd1, 0x43, 0x9d, 0xb4, 0x76, 0x6b, 0xd1, 0x90, 0xb7, 0x30, 0x82, 0xc2, 0x76, 0xad, 0xa9, 0x11,
-	0xd9, 0xcb, 0x53, 0x05, 0x97, 0x20, 0xaf, 0x37, 0xbb, 0x0d, 0x59, 0x4f, 0xe1, 0x0a, 0x94, 0xb5,
-	0xae, 0xd1, 0x32, 0x6b, 0xcd, 0x2a, 0xd1, 0x1b, 0x7a, 0xd3, 0x90, 0x9a, 0x0c, 0x2e, 0x43, 0xc9,
-	0xa8, 0x35, 0xf4, 0x8e, 0xa1, 0x35, 0xda, 0x52, 0xc8, 0x66, 0x91, 0xeb, 0xe8, 0x91, 0x0d, 0xc2,
-	0xab, 0xb0, 0xd2, 0x6c, 0x99, 0xf2, 0x49, 0x92, 0x79, 0x4f, 0xab, 0x77, 0x75, 0xa9, 0x5b, 0xc3,
-	0x17, 0x00, 0xb7, 0x9a, 0x66, 0xb7, 0xbd, 0xa3, 0x19, 0xba, 0xd9, 0x6c, 0xdd, 0x97, 0x8a, 0x5b,
-	0xb8, 0x04, 0xb9, 0xc9, 0x0c, 0x9e, 0x32, 0x16, 0x8a, 0x6d, 0x8d, 0x18, 0x13, 0xb0, 0x4f, 0x9f,
-	0x32, 0xb2, 0xe0, 0x36, 0x69, 0x75, 0xdb, 0x13, 0xb3, 0x25, 0x28, 0x48, 0xb2, 0xa4, 0x28, 0xc3,
-	0x44, 0xdb, 0xb5, 0x66, 0x35, 0x9e, 0xdf, 0xd3, 0xdc, 0x6a, 0x0a, 0x29, 0x1b, 0x07, 0x90, 0xe1,
-	0xee, 0xc8, 0x41, 0xa6, 0xd9, 0x6a, 0xea, 0xe8, 0x1c, 0x5e, 0x04, 0xa8, 0x75, 0x6a, 0x4d, 0x43,
-	0xbf, 0x4d, 0xb4, 0x3a, 0x83, 0xcd, 0x05, 0x11, 0x81, 0x0c, 0xed, 0x02, 0xcc, 0xd7, 0x3a, 0xbb,
-	0xf5, 0x96, 0x66, 0x48, 0x98, 0xb5, 0xce, 0xdd, 0x6e, 0xcb, 0x60, 0x4a, 0x84, 0x0b, 0x30, 0x57,
-	0xeb, 0x18, 0xfa, 0xc7, 0x06, 0xc3, 0xc5, 0x75, 0x82, 0x55, 0xf4, 0xf4, 0xd6, 0xc6, 0x97, 0x69,
-	0xc8, 0xf0, 0xf7, 0xa4, 0x45, 0xc8, 0x73, 0x6f, 0x1b, 0x0f, 0xda, 0x6c, 0xc8, 0x3c, 0x64, 0x6a,
-	0x4d, 0xe3, 0x26, 0xfa, 0xe5, 0x14, 0x06, 0xc8, 0x76, 0x79, 0xf9, 0x57, 0xe6, 0x58, 0xb9, 0xd6,
-	0x34, 0xde, 0xbe, 0x81, 0x3e, 0x4d, 0xb1, 0x6e, 0xbb, 0xa2, 0xf2, 0xab, 0x91, 0x62, 0xeb, 0x3a,
-	0xfa, 0x2c, 0x56, 0x6c, 0x5d, 0x47, 0xbf, 0x16, 0x29, 0xae, 0x6d, 0xa1, 0x5f, 0x8f, 0x15, 0xd7,
-	0xb6, 0xd0, 0x6f, 0x44, 0x8a, 0x1b, 0xd7, 0xd1, 0x6f, 0xc6, 0x8a, 0x1b, 0xd7, 0xd1, 0x6f, 0xcd,
-	0x31, 0x2c, 0x1c, 0xc9, 0xb5, 0x2d, 0xf4, 0xdb, 0xb9, 0xb8, 0x76, 0xe3, 0x3a, 0xfa, 0x9d, 0x1c,
-	0xf3, 0x7f, 0xec, 0x55, 0xf4, 0xbb, 0x88, 0x4d, 0x93, 0x39, 0x08, 0xfd, 0x1e, 0x2f, 0x32, 0x15,
-	0xfa, 0x7d, 0xc4, 0x30, 0x32, 0x29, 0xaf, 0x7e, 0xce, 0x35, 0x0f, 0x74, 0x8d, 0xa0, 0x3f, 0x98,
-	0x13, 0x2f, 0xd0, 0xaa, 0xb5, 0x86, 0x56, 0x47, 0x98, 0xb7, 0x60, 0xac, 0xfc, 0xd1, 0x55, 0x56,
-	0x64, 0xe1, 0x89, 0xfe, 0xb8, 0xcd, 0x06, 0xbc, 0xa7, 0x91, 0xea, 0x87, 0x1a, 0x41, 0x7f, 0x72,
-	0x95, 0x0d, 0x78, 0x4f, 0x23, 0x92, 0xaf, 0x3f, 0x6d, 0x33, 0x43, 0xae, 0xfa, 0xe2, 0x2a, 0x9b,
-	0xb4, 0x94, 0xff, 0x59, 0x1b, 0xe7, 0x20, 0xbd, 0x5d, 0x33, 0xd0, 0x97, 0x7c, 0x34, 0x16, 0xa2,
-	0xe8, 0xcf, 0x11, 0x13, 0x76, 0x74, 0x03, 0xfd, 0x05, 0x13, 0x66, 0x8d, 0x6e, 0xbb, 0xae, 0xa3,
-	0x37, 0xd8, 0xe4, 0x6e, 0xeb, 0xad, 0x86, 0x6e, 0x90, 0x07, 0xe8, 0x2f, 0xb9, 0xf9, 0x47, 0x9d,
-	0x56, 0x13, 0x7d, 0x85, 0x70, 0x09, 0x40, 0xff, 0xb8, 0x4d, 0xf4, 0x4e, 0xa7, 0xd6, 0x6a, 0xa2,
-	0xb7, 0x36, 0x76, 0x01, 0x9d, 0x4c, 0x07, 0x0c, 0x40, 0xb7, 0x79, 0xa7, 0xd9, 0xba, 0xdf, 0x44,
-	0xe7, 0x58, 0xa5, 0x4d, 0xf4, 0xb6, 0x46, 0x74, 0xa4, 0x60, 0x80, 0x39, 0xf1, 0x3e, 0x0e, 0xa5,
-	0xf0, 0x02, 0xe4, 0x48, 0xab, 0x5e, 0xdf, 0xd6, 0xaa, 0x77, 0x50, 0x7a, 0xfb, 0x1d, 0x58, 0xb4,
-	0xbd, 0xcd, 0x43, 0x3b, 0xa4, 0x41, 0x20, 0x5e, 0x2c, 0x3f, 0x54, 0x65, 0xcd, 0xf6, 0xae, 0x88,
-	0xd2, 0x95, 0x81, 0x77, 0xe5, 0x30, 0xbc, 0xc2, 0xb5, 0x57, 0x78, 0xc6, 0xd8, 0x9b, 0xe3, 0x95,
-	0x6b, 0xff, 0x17, 0x00, 0x00, 0xff, 0xff, 0xda, 0x0b, 0x47, 0xfb, 0x0f, 0x2d, 0x00, 0x00,
+	// 3216 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0xed, 0x1a, 0x4b, 0x73, 0x1c, 0x47,
+	0x99, 0xd9, 0x97, 0x56, 0xdf, 0x6a, 0x57, 0xa3, 0x96, 0x64, 0xcb, 0xca, 0x7b, 0xf2, 0x32, 0x4e,
+	0x90, 0x1d, 0x25, 0x31, 0x26, 0x09, 0xc1, 0xa3, 0xd5, 0xc8, 0xd9, 0x78, 0x5f, 0xee, 0x9d, 0xb5,
+	0x63, 0x57, 0xaa, 0xa6, 0x46, 0xbb, 0xe3, 0xd5, 0x94, 0x67, 0x1f, 0x9e, 0x19, 0x39, 0xd6, 0xcd,
+	0x10, 0xc2, 0x1b, 0x12, 0x9e, 0x26, 0x50, 0x04, 0xaa, 0xb8, 0xf3, 0x1b, 0x28, 0x7e, 0x00, 0x37,
+	0x0e, 0xc0, 0x81, 0x03, 0x45, 0x71, 0xa3, 0x38, 0x71, 0xe0, 0x40, 0xf1, 0xf5, 0x63, 0x66, 0x67,
+	0xe5, 0xf5, 0x23, 0x86, 0x8b, 0x9c, 0x9c, 0xa6, 0xbf, 0x47, 0xf7, 0xd7, 0xdf, 0xa3, 0xbf, 0xaf,
+	0xa7, 0xbb, 0xa1, 0x70, 0x75, 0xd7, 0xf1, 0xf7, 0xd6, 0x46, 0xfe, 0x30, 0x1c, 0x92, 0x2c, 0x07,
+	0x56, 0x4b, 0xe1, 0x70, 0x34, 0xec, 0xda, 0xa1, 0x2d, 0xd0, 0xab, 0x85, 0x6b, 0xa1, 0x3f, 0xea,
+	0x08, 0x40, 0x7b, 0x4f, 0x81, 0x9c, 0x69, 0xfb, 0x3d, 0x27, 0x24, 0xab, 0x90, 0xbf, 0xe2, 0xec,
+	0x05, 0x23, 0xbb, 0xe3, 0xac, 0x28, 0x8f, 0x2b, 0x47, 0x67, 0x69, 0x0c, 0x93, 0x25, 0xc8, 0x06,
+	0x3b, 0xb6, 0xdf, 0x5d, 0x49, 0x71, 0x82, 0x00, 0xc8, 0xcb, 0x50, 0x08, 0xed, 0x6d, 0xcf, 0x09,
+	0xad, 0x70, 0x6f, 0xe4, 0xac, 0xa4, 0x91, 0x56, 0x5a, 0x5f, 0x5a, 0x8b, 0xe5, 0x99, 0x9c, 0x68,
+	0x22, 0x8d, 0x42, 0x18, 0xb7, 0x09, 0x81, 0x4c, 0xc7, 0xf1, 0xbc, 0x95, 0x0c, 0x1f, 0x8b, 0xb7,
+	0xb5, 0x4d, 0x28, 0x9d, 0x37, 0xcf, 0xd8, 0xa1, 0x53, 0xb6, 0x3d, 0xcf, 0xf1, 0x2b, 0x9b, 0x6c,
+	0x3a, 0xbb, 0x81, 0xe3, 0x0f, 0xec, 0x7e, 0x3c, 0x9d, 0x08, 0x26, 0x87, 0x20, 0xd7, 0xf3, 0x87,
+	0xbb, 0xa3, 0x00, 0xe7, 0x93, 0x46, 0x8a, 0x84, 0xb4, 0xb7, 0x01, 0x8c, 0x6b, 0xce, 0x20, 0x34,
+	0x87, 0x57, 0x9c, 0x01, 0x79, 0x18, 0x66, 0x43, 0xb7, 0xef, 0x04, 0xa1, 0xdd, 0x1f, 0xf1, 0x21,
+	0xd2, 0x74, 0x8c, 0xb8, 0x8d, 0x4a, 0x28, 0x75, 0x34, 0x0c, 0xdc, 0xd0, 0x1d, 0x0e, 0xb8, 0x3e,
+	0x28, 0x35, 0x82, 0xb5, 0xd7, 0x21, 0x7b, 0xde, 0xf6, 0x76, 0x1d, 0xf2, 0x18, 0x64, 0xb8, 0xc2,
+	0x0a, 0x57, 0xb8, 0xb0, 0x26, 0x8c, 0xce, 0xf5, 0xe4, 0x04, 0x36, 0xf6, 0x35, 0xc6, 0xc9, 0xc7,
+	0x9e, 0xa3, 0x02, 0xd0, 0xae, 0xc0, 0xdc, 0x86, 0x3b, 0xe8, 0x9e, 0xb7, 0x7d, 0x97, 0x19, 0xe3,
+	0x3e, 0x87, 0x21, 0x4f, 0x41, 0x8e, 0x37, 0x02, 0x9c, 0x60, 0xfa, 0x68, 0x61, 0x7d, 0x4e, 0x76,
+	0xe4, 0x73, 0xa3, 0x92, 0xa6, 0xfd, 0x4e, 0x01, 0xd8, 0x18, 0xee, 0x0e, 0xba, 0xe7, 0x18, 0x91,
+	0xa8, 0x90, 0x0e, 0xae, 0x7a, 0xd2, 0x90, 0xac, 0x49, 0xce, 0x42, 0x69, 0x1b, 0x67, 0x63, 0x5d,
+	0x93, 0xd3, 0x11, 0xb6, 0x2c, 0xac, 0x3f, 0x25, 0x87, 0x1b, 0x77, 0x5e, 0x4b, 0xce, 0x3a, 0x30,
+	0x06, 0xa1, 0xbf, 0x47, 0x8b, 0xdb, 0x49, 0xdc, 0x6a, 0x1b, 0xc8, 0xad, 0x4c, 0x4c, 0x28, 0x46,
+	0x50, 0x24, 0x14, 0x9b, 0xe4, 0xb3, 0x49, 0x8d, 0x0a, 0xeb, 0x8b, 0x91, 0xac, 0x44, 0x5f, 0xa9,
+	0xe6, 0x2b, 0xa9, 0x53, 0x8a, 0xf6, 0x7e, 0x0e, 0x4a, 0xc6, 0x75, 0xa7, 0xb3, 0x1b, 0x3a, 0x8d,
+	0x11, 0xf3, 0x41, 0x40, 0xd6, 0x60, 0xd1, 0x1d, 0x74, 0xbc, 0xdd, 0xae, 0x63, 0x39, 0xcc, 0xd5,
+	0x56, 0xc8, 0x7c, 0xcd, 0xc7, 0xcb, 0xd3, 0x05, 0x49, 0x4a, 0x04, 0x81, 0x0e, 0x8b, 0x9d, 0x61,
+	0x7f, 0x64, 0xfb, 0x93, 0xfc, 0x69, 0x2e, 0x7f, 0x41, 0xca, 0x1f, 0xf3, 0xd3, 0x05, 0xc9, 0x9d,
+	0x18, 0xa2, 0x06, 0xf3, 0x72, 0xdc, 0xae, 0x75, 0xd9, 0x75, 0xbc, 0x6e, 0xc0, 0x43, 0xb7, 0x14,
+	0x9b, 0x6a, 0x72, 0x8a, 0x6b, 0x15, 0xc9, 0xbc, 0xc5, 0x79, 0x69, 0xc9, 0x9d, 0x80, 0xc9, 0x31,
+	0x58, 0xe8, 0x78, 0x2e, 0x9b, 0xca, 0x65, 0x66, 0x62, 0xcb, 0x1f, 0xbe, 0x13, 0xac, 0x64, 0xf9,
+	0xfc, 0xe7, 0x05, 0x61, 0x8b, 0xe1, 0x29, 0xa2, 0xc9, 0x2b, 0x90, 0x7f, 0x67, 0xe8, 0x5f, 0xf1,
+	0x86, 0x76, 0x77, 0x25, 0xc7, 0x65, 0x3e, 0x3a, 0x5d, 0xe6, 0x05, 0xc9, 0x45, 0x63, 0x7e, 0x72,
+	0x14, 0x54, 0xf4, 0xb3, 0x15, 0x38, 0x9e, 0xd3, 0x09, 0x2d, 0xcf, 0xed, 0xbb, 0xe1, 0x4a, 0x9e,
+	0xaf, 0x82, 0x12, 0xe2, 0x5b, 0x1c, 0x5d, 0x65, 0x58, 0x62, 0xc1, 0x72, 0xe8, 0xdb, 0x83, 0xc0,
+	0xee, 0xb0, 0xc1, 0x2c, 0x37, 0x18, 0x7a, 0x36, 0x5f, 0x01, 0xb3, 0x5c, 0xe4, 0xb1, 0xe9, 0x22,
+	0xcd, 0x71, 0x97, 0x4a, 0xd4, 0x83, 0x2e, 0x85, 0x53, 0xb0, 0xe4, 0x05, 0x58, 0x0e, 0xae, 0xb8,
+	0x23, 0x8b, 0x8f, 0x63, 0x8d, 0x3c, 0x7b, 0x60, 0x75, 0xec, 0xce, 0x8e, 0xb3, 0x02, 0x5c, 0x6d,
+	0xc2, 0x88, 0x3c, 0xd4, 0x9a, 0x48, 0x2a, 0x33, 0x8a, 0xf6, 0x2a, 0x94, 0x26, 0xed, 0x48, 0x16,
+	0xa0, 0x68, 0x5e, 0x6c, 0x1a, 0x96, 0x5e, 0xdf, 0xb4, 0xea, 0x7a, 0xcd, 0x50, 0x3f, 0x43, 0x8a,
+	0x30, 0xcb, 0x51, 0x8d, 0x7a, 0xf5, 0xa2, 0xaa, 0x90, 0x19, 0x48, 0xeb, 0xd5, 0xaa, 0x9a, 0xd2,
+	0x4e, 0x41, 0x3e, 0x32, 0x08, 0x99, 0x87, 0x42, 0xbb, 0xde, 0x6a, 0x1a, 0xe5, 0xca, 0x56, 0xc5,
+	0xd8, 0xc4, 0x4e, 0x79, 0xc8, 0x34, 0xaa, 0x66, 0x13, 0xf9, 0x79, 0x4b, 0x6f, 0xaa, 0x29, 0xd6,
+	0x73, 0x73, 0x43, 0x57, 0xd3, 0xda, 0x4d, 0x05, 0x96, 0xa6, 0x29, 0x46, 0x0a, 0x30, 0xb3, 0x69,
+	0x6c, 0xe9, 0xed, 0xaa, 0x89, 0x43, 0x2c, 0xc2, 0x3c, 0x35, 0x9a, 0x86, 0x6e, 0xea, 0x1b, 0x55,
+	0xc3, 0xa2, 0x86, 0xbe, 0x89, 0xa3, 0x11, 0x28, 0xb1, 0x96, 0x55, 0x6e, 0xd4, 0x6a, 0x15, 0xd3,
+	0x44, 0x59, 0x29, 0x5c, 0xc1, 0x2a, 0xc7, 0xb5, 0xeb, 0x63, 0x6c, 0x1a, 0xd7, 0xc5, 0x5c, 0xcb,
+	0xa0, 0x15, 0xbd, 0x5a, 0xb9, 0xc4, 0x06, 0x50, 0x33, 0xe4, 0x09, 0x78, 0xa4, 0xdc, 0xa8, 0xb7,
+	0x2a, 0x2d, 0xd3, 0xa8, 0x9b, 0x56, 0xab, 0xae, 0x37, 0x5b, 0x6f, 0x34, 0x4c, 0x3e, 0xb2, 0x50,
+	0x2e, 0xfb, 0x66, 0x26, 0xaf, 0xa0, 0x66, 0x37, 0x53, 0x90, 0xe5, 0xf6, 0x60, 0x59, 0x34, 0x91,
+	0x1b, 0x79, 0x3b, 0xce, 0x28, 0xa9, 0x3b, 0x64, 0x14, 0x9e, 0x88, 0x65, 0x6e, 0x13, 0x00, 0x79,
+	0x08, 0x66, 0x87, 0x7e, 0xcf, 0x12, 0x14, 0x91, 0x95, 0xf3, 0x88, 0xe0, 0xe9, 0x9b, 0x65, 0x44,
+	0x96, 0xcc, 0xb7, 0xed, 0xc0, 0xe1, 0x51, 0x8a, 0xb4, 0x08, 0x26, 0x47, 0x80, 0xf1, 0x59, 0x7c,
+	0x1e, 0x39, 0x4e, 0x9b, 0x41, 0xb8, 0xce, 0xa6, 0xf2, 0x24, 0x14, 0x3b, 0x43, 0x6f, 0xb7, 0x3f,
+	0xb0, 0x3c, 0x67, 0xd0, 0x0b, 0x77, 0x56, 0x66, 0x90, 0x5e, 0xa4, 0x73, 0x02, 0x59, 0xe5, 0x38,
+	0xb2, 0x02, 0x33, 0x1d, 0x4c, 0xbb, 0x81, 0x23, 0x22, 0xb3, 0x48, 0x23, 0x90, 0x4b, 0x75, 0x3a,
+	0x6e, 0xdf, 0xf6, 0x02, 0x1e, 0x85, 0x45, 0x1a, 0xc3, 0x4c, 0x89, 0xcb, 0x9e, 0xdd, 0x0b, 0x78,
+	0xf4, 0x14, 0xa9, 0x00, 0xb4, 0xcf, 0x43, 0x1a, 0x97, 0x0c, 0x1b, 0x52, 0x08, 0x0c, 0xd0, 0x32,
+	0xe9, 0xa3, 0x84, 0x46, 0x20, 0x2b, 0x1a, 0x32, 0x6f, 0x8a, 0x74, 0x1a, 0x65, 0xca, 0xb7, 0x61,
+	0x8e, 0x3a, 0xc1, 0xae, 0x17, 0x1a, 0xd7, 0x31, 0x78, 0x03, 0xb2, 0x0e, 0x85, 0x64, 0xa6, 0x50,
+	0x6e, 0x97, 0x29, 0xc0, 0x19, 0xa7, 0x08, 0x94, 0x7a, 0xd9, 0x77, 0x82, 0x1d, 0xc7, 0x97, 0x99,
+	0x28, 0x02, 0x59, 0x1e, 0x2e, 0xf0, 0xd0, 0x16, 0x32, 0x58, 0xf6, 0x96, 0x39, 0x44, 0x99, 0xc8,
+	0xde, 0xdc, 0xa9, 0x54, 0xd2, 0x98, 0xf5, 0x58, 0x5a, 0xb0, 0xec, 0xcb, 0x97, 0x71, 0x95, 0x3a,
+	0xa2, 0x48, 0x65, 0xe8, 0x1c, 0x43, 0xea, 0x12, 0xc7, 0xdc, 0xe6, 0x0e, 0xb0, 0x24, 0x86, 0x96,
+	0xdb, 0xe5, 0x0e, 0xcd, 0xd0, 0xbc, 0x40, 0x54, 0xba, 0xe4, 0x51, 0xc8, 0xf0, 0xc4, 0x92, 0xe1,
+	0x52, 0x40, 0x4a, 0x41, 0x0b, 0x51, 0x8e, 0x27, 0xcf, 0x41, 0xce, 0xe1, 0xfa, 0x72, 0xa7, 0x8e,
+	0x53, 0x71, 0xd2, 0x14, 0x54, 0xb2, 0x68, 0xaf, 0xc1, 0x1c, 0xd7, 0xe1, 0x82, 0xed, 0x0f, 0xdc,
+	0x41, 0x8f, 0x57, 0xf0, 0x61, 0x57, 0xc4, 0x5e, 0x91, 0xf2, 0x36, 0x33, 0x01, 0x96, 0xd6, 0xc0,
+	0xee, 0x39, 0xb2, 0xa2, 0x46, 0xa0, 0xf6, 0xab, 0x34, 0x14, 0x5a, 0xa1, 0xef, 0xd8, 0x7d, 0x6e,
+	0x3d, 0xf2, 0x1a, 0x00, 0x96, 0xe0, 0xd0, 0xe9, 0x23, 0x10, 0x99, 0xe1, 0x61, 0x29, 0x3e, 0xc1,
+	0x87, 0x6d, 0xc9, 0x44, 0x13, 0xfc, 0xfb, 0xdd, 0x93, 0xba, 0x07, 0xf7, 0xac, 0x7e, 0x94, 0x82,
+	0xd9, 0x78, 0x34, 0x2c, 0x09, 0xf9, 0x0e, 0xb6, 0x7b, 0x43, 0x7f, 0x4f, 0xd6, 0xde, 0xa7, 0xef,
+	0x24, 0x7d, 0xad, 0x2c, 0x99, 0x69, 0xdc, 0x8d, 0x3c, 0x02, 0x62, 0x43, 0x23, 0x42, 0x5f, 0xe8,
+	0x3b, 0xcb, 0x31, 0x3c, 0xf8, 0x5f, 0x01, 0x32, 0xf2, 0x31, 0x58, 0x31, 0xd9, 0x61, 0xd5, 0x8b,
+	0x8a, 0x46, 0x7a, 0x8a, 0xc3, 0x55, 0xc9, 0x77, 0xd6, 0xd9, 0x93, 0x69, 0xee, 0xd4, 0x64, 0x5f,
+	0x19, 0xb2, 0xb7, 0xba, 0x31, 0xd1, 0x93, 0x57, 0xfe, 0x20, 0xaa, 0xf1, 0x59, 0x1e, 0xdd, 0xac,
+	0xa9, 0x3d, 0x0b, 0xf9, 0x68, 0xf2, 0x64, 0x16, 0xb2, 0x86, 0xef, 0x0f, 0x7d, 0x4c, 0x5f, 0x2c,
+	0xdb, 0xd5, 0xaa, 0x22, 0x61, 0x6e, 0x6e, 0xb2, 0x84, 0xf9, 0xdb, 0x54, 0x5c, 0x68, 0xa9, 0x83,
+	0x32, 0x82, 0x90, 0x7c, 0x09, 0x16, 0x1d, 0x1e, 0x69, 0xee, 0x35, 0x07, 0xb3, 0x35, 0xdb, 0x95,
+	0xb1, 0x38, 0x13, 0xcb, 0x61, 0x7e, 0x4d, 0x6c, 0x22, 0xa3, 0xdd, 0x1a, 0x5d, 0x88, 0x79, 0x25,
+	0xaa, 0x4b, 0x0c, 0xac, 0xd4, 0xfd, 0xbe, 0xd3, 0x75, 0x71, 0x06, 0x89, 0x01, 0x84, 0xc3, 0x96,
+	0xa3, 0x4d, 0xcb, 0xc4, 0xa6, 0x0f, 0x0b, 0x78, 0xd4, 0x23, 0x1e, 0xe6, 0x69, 0xc8, 0x85, 0x7c,
+	0x83, 0x2a, 0x6b, 0x76, 0x31, 0xca, 0x6a, 0x1c, 0x49, 0x25, 0x91, 0x3c, 0x0b, 0x62, 0xbb, 0xcb,
+	0xf3, 0xd7, 0x38, 0x20, 0xc6, 0xbb, 0x18, 0x2a, 0xe8, 0x38, 0x5e, 0x69, 0xa2, 0xd8, 0x75, 0xb9,
+	0xc1, 0xd2, 0xb4, 0x98, 0xac, 0x5c, 0x5d, 0x72, 0x1c, 0x66, 0x86, 0xa2, 0xd0, 0xf1, 0xcc, 0x36,
+	0x9e, 0xf1, 0x64, 0x15, 0xa4, 0x11, 0x97, 0xf6, 0x45, 0x98, 0x8f, 0x2d, 0x18, 0x8c, 0x10, 0xe3,
+	0x60, 0xa5, 0xcf, 0xf9, 0x7c, 0x39, 0x49, 0xab, 0x11, 0x39, 0x44, 0x22, 0x1f, 0x50, 0xc9, 0xa1,
+	0x75, 0xb1, 0xa4, 0xf0, 0xd6, 0x05, 0x37, 0xdc, 0xe1, 0x8e, 0xc2, 0x99, 0x66, 0x1d, 0xd6, 0xd8,
+	0x67, 0x73, 0xda, 0x2c, 0x73, 0x3a, 0x15, 0xd4, 0x84, 0x94, 0xd4, 0x5d, 0xa5, 0xfc, 0x33, 0x05,
+	0x8b, 0x72, 0x96, 0x1b, 0x76, 0xd8, 0xd9, 0x39, 0xa0, 0xce, 0x7e, 0x0e, 0x66, 0x18, 0xde, 0x8d,
+	0x17, 0xc6, 0x14, 0x77, 0x47, 0x1c, 0xcc, 0xe1, 0x76, 0x60, 0x25, 0xbc, 0x2b, 0x37, 0x5b, 0x45,
+	0x3b, 0x48, 0x54, 0xfa, 0x29, 0x71, 0x91, 0xbb, 0x4b, 0x5c, 0xcc, 0xdc, 0x53, 0x5c, 0x6c, 0xc2,
+	0xd2, 0xa4, 0xc5, 0x65, 0x70, 0x3c, 0x0f, 0x33, 0xc2, 0x29, 0x51, 0x0a, 0x9c, 0xe6, 0xb7, 0x88,
+	0x45, 0xfb, 0x65, 0x0a, 0x96, 0x64, 0x76, 0xfa, 0x64, 0x2c, 0xd3, 0x84, 0x9d, 0xb3, 0xf7, 0x64,
+	0xe7, 0x32, 0x2c, 0xef, 0x33, 0xd0, 0x7d, 0xac, 0xc2, 0x7f, 0x28, 0xf8, 0x8f, 0xe6, 0xf4, 0xdc,
+	0xc1, 0x01, 0x35, 0x6f, 0xc2, 0x6a, 0x99, 0x7b, 0xb2, 0xda, 0x49, 0x28, 0x4a, 0x7d, 0xa5, 0xb5,
+	0x6e, 0x5d, 0x06, 0xca, 0x94, 0x65, 0xa0, 0xfd, 0x4d, 0x81, 0x62, 0x79, 0xd8, 0xc7, 0xbf, 0x87,
+	0x03, 0x6a, 0xa9, 0x5b, 0xf5, 0xcc, 0x4c, 0xd3, 0x53, 0x85, 0x52, 0xa4, 0xa6, 0x30, 0x90, 0xf6,
+	0x77, 0x05, 0x33, 0xf5, 0xd0, 0xf3, 0xb6, 0xed, 0xce, 0x95, 0x07, 0x5b, 0x77, 0x82, 0x3f, 0x2f,
+	0xb1, 0xa2, 0x52, 0xfb, 0x7f, 0x2b, 0x50, 0x6a, 0xfa, 0x0e, 0xfb, 0x43, 0x7e, 0xa0, 0x95, 0x67,
+	0x5b, 0xdc, 0x6e, 0x28, 0x37, 0x07, 0xf8, 0x7b, 0xc5, 0xda, 0xda, 0x02, 0xcc, 0xc7, 0xba, 0x4b,
+	0x7b, 0xfc, 0x49, 0x81, 0x65, 0x11, 0x20, 0x92, 0xd2, 0x3d, 0xa0, 0x66, 0x89, 0xf4, 0xcd, 0x24,
+	0xf4, 0x5d, 0x81, 0x43, 0xfb, 0x75, 0x93, 0x6a, 0xbf, 0x9b, 0x82, 0xc3, 0x51, 0x6c, 0x1c, 0x70,
+	0xc5, 0xff, 0x87, 0x78, 0x58, 0x85, 0x95, 0x5b, 0x8d, 0x20, 0x2d, 0xf4, 0x41, 0x0a, 0x56, 0xca,
+	0x58, 0x8e, 0x42, 0x27, 0xb1, 0xc9, 0x78, 0x70, 0x62, 0x83, 0xbc, 0x00, 0x73, 0xa8, 0x70, 0xe8,
+	0x76, 0xdc, 0x91, 0xcd, 0x7e, 0xe3, 0xb2, 0x7c, 0x0f, 0xb3, 0x6f, 0x80, 0x09, 0x16, 0xed, 0x21,
+	0x38, 0x32, 0xc5, 0x22, 0xd2, 0x5e, 0xff, 0x51, 0x80, 0xe0, 0x2f, 0x97, 0x1f, 0x7e, 0x02, 0xaa,
+	0xca, 0xd4, 0x60, 0x5a, 0x86, 0xc5, 0x09, 0xfd, 0x93, 0x76, 0x41, 0x09, 0x9f, 0x84, 0x8a, 0x73,
+	0x5b, 0xbb, 0x24, 0xf5, 0x97, 0x76, 0xf9, 0x8b, 0x02, 0xab, 0xe5, 0xa1, 0x38, 0x21, 0x7c, 0x20,
+	0x57, 0x98, 0xf6, 0x08, 0x3c, 0x34, 0x55, 0x41, 0x69, 0x80, 0x3f, 0x2b, 0x70, 0x88, 0x3a, 0x76,
+	0xf7, 0xc1, 0x54, 0xfe, 0x1c, 0xd6, 0x97, 0xfd, 0xca, 0xc9, 0x1d, 0xea, 0x49, 0xc8, 0xf7, 0x9d,
+	0xd0, 0x66, 0x87, 0x90, 0x52, 0xa5, 0xd5, 0x68, 0xdc, 0x31, 0x77, 0x4d, 0x72, 0xd0, 0x98, 0x57,
+	0xfb, 0x08, 0xff, 0x7d, 0xf9, 0x5e, 0xf7, 0xd3, 0x3f, 0xa8, 0xe9, 0xff, 0x02, 0x1f, 0x28, 0xb0,
+	0x34, 0x69, 0xa0, 0xf8, 0x9f, 0xe0, 0xff, 0x7d, 0x10, 0x31, 0x25, 0x21, 0xa4, 0xa7, 0x6d, 0x41,
+	0x7f, 0x8f, 0x55, 0x34, 0x39, 0xa5, 0x4f, 0x0f, 0x2d, 0x26, 0x0f, 0x2d, 0x3e, 0xf6, 0x29, 0xd5,
+	0x4d, 0x05, 0x8e, 0x4c, 0x31, 0xe8, 0xc7, 0x73, 0x74, 0xe2, 0xe8, 0x22, 0x75, 0xd7, 0xa3, 0x8b,
+	0x7b, 0x75, 0xf5, 0x1f, 0x31, 0xfa, 0x6a, 0xe2, 0xc4, 0x58, 0xfc, 0xc7, 0x1f, 0xdc, 0x6c, 0xc6,
+	0x0f, 0x85, 0x33, 0xe3, 0x7b, 0x19, 0x76, 0x36, 0xb1, 0x4f, 0xb5, 0xfb, 0x38, 0x9b, 0xf8, 0x97,
+	0x02, 0x0b, 0x72, 0x14, 0xfd, 0xc0, 0x6e, 0x04, 0xa6, 0x58, 0x87, 0x3c, 0x0a, 0x69, 0xb7, 0x1b,
+	0xed, 0x20, 0x27, 0x6f, 0xb3, 0x19, 0x41, 0x3b, 0x0d, 0x24, 0xa9, 0xf7, 0x7d, 0x98, 0xee, 0x0f,
+	0x69, 0x58, 0x68, 0x8d, 0x3c, 0x37, 0x94, 0xc4, 0x07, 0x3b, 0xf1, 0x3f, 0x01, 0x73, 0x01, 0x53,
+	0xd6, 0x12, 0x77, 0x6d, 0xdc, 0xb0, 0xb3, 0xb4, 0xc0, 0x71, 0x65, 0x8e, 0x22, 0x8f, 0x41, 0x21,
+	0x62, 0xd9, 0x1d, 0x84, 0xf2, 0xa4, 0x13, 0x24, 0x07, 0x62, 0xc8, 0x4b, 0x70, 0x78, 0xb0, 0xdb,
+	0xe7, 0x77, 0xd3, 0xd6, 0x08, 0xd5, 0x92, 0x37, 0xb7, 0xb8, 0x3f, 0x95, 0x77, 0xc8, 0x8b, 0x48,
+	0x66, 0x57, 0xd4, 0x4d, 0xc7, 0x17, 0x37, 0xb7, 0x48, 0x22, 0xa7, 0x61, 0xd6, 0xf6, 0x7a, 0x43,
+	0xdf, 0x0d, 0x77, 0xfa, 0xf2, 0xf2, 0x58, 0x8b, 0xae, 0x56, 0xf6, 0x9b, 0x7f, 0x4d, 0x8f, 0x38,
+	0xe9, 0xb8, 0x93, 0xf6, 0x3c, 0xcc, 0xc6, 0x78, 0x76, 0x4f, 0x6a, 0x9c, 0x6b, 0xeb, 0x55, 0xab,
+	0xd5, 0xac, 0x56, 0xcc, 0x96, 0xb8, 0xf0, 0xdd, 0x6a, 0x57, 0x11, 0x51, 0xd6, 0xeb, 0xaa, 0xa2,
+	0x51, 0x00, 0x3e, 0x24, 0x1f, 0x7c, 0x6c, 0x20, 0xe5, 0x2e, 0x06, 0x7a, 0x08, 0x66, 0x51, 0x31,
+	0xa9, 0x7b, 0x8a, 0xab, 0x93, 0x47, 0x04, 0xd7, 0x5c, 0xd3, 0x71, 0xbf, 0x9d, 0x98, 0xab, 0x8c,
+	0xb6, 0x44, 0xf2, 0x56, 0x26, 0x92, 0xf7, 0x58, 0x7e, 0x9c, 0xbc, 0xc5, 0x56, 0x9e, 0xad, 0xf3,
+	0x37, 0x1c, 0xdb, 0x0b, 0xa3, 0x7a, 0xa5, 0xfd, 0x3a, 0x05, 0x45, 0xca, 0x30, 0x6e, 0xdf, 0x61,
+	0xb7, 0x4b, 0x01, 0xf3, 0xd4, 0x0e, 0x67, 0xb1, 0xc6, 0x69, 0x17, 0x3d, 0x25, 0x70, 0xe2, 0x12,
+	0x60, 0x1d, 0x96, 0x03, 0xa7, 0x33, 0x1c, 0x74, 0x03, 0x6b, 0xdb, 0xd9, 0x61, 0x0f, 0x36, 0xfa,
+	0x76, 0x10, 0xca, 0x7b, 0xc6, 0x22, 0x5d, 0x94, 0xc4, 0x0d, 0x4e, 0xab, 0x71, 0x12, 0x39, 0x01,
+	0x4b, 0xdb, 0xee, 0xc0, 0x1b, 0xf6, 0xd8, 0x55, 0xfb, 0x9e, 0xe3, 0x07, 0x52, 0x55, 0x16, 0x5e,
+	0x59, 0x4a, 0x04, 0xad, 0x29, 0x48, 0xc2, 0xdd, 0x97, 0xe0, 0xd8, 0x54, 0x29, 0xd6, 0x65, 0xd7,
+	0xc3, 0x8f, 0xd3, 0xb5, 0xf0, 0xff, 0xd6, 0x73, 0x3b, 0xe2, 0x59, 0x80, 0xd8, 0xbb, 0x3f, 0x33,
+	0x45, 0xf4, 0x96, 0x64, 0xa7, 0x63, 0x6e, 0x66, 0xed, 0xce, 0x68, 0xd7, 0xda, 0xe5, 0x57, 0x83,
+	0xac, 0x8a, 0x29, 0x34, 0x8f, 0x88, 0x36, 0x83, 0xd9, 0x9d, 0xd5, 0xd5, 0x91, 0x28, 0x5e, 0x0a,
+	0x65, 0x4d, 0x76, 0x04, 0x5b, 0xd2, 0x7b, 0x3d, 0xdf, 0xe9, 0xe1, 0x1a, 0x11, 0x66, 0x42, 0x7d,
+	0x84, 0x49, 0xf6, 0x2c, 0xf9, 0xde, 0x48, 0xe8, 0xa3, 0x08, 0x7d, 0x24, 0x4d, 0xbc, 0x36, 0x8a,
+	0xc2, 0xf7, 0xd0, 0xee, 0x60, 0x6a, 0x9f, 0x14, 0xef, 0xb3, 0x14, 0x53, 0x93, 0xbd, 0xbe, 0x00,
+	0x47, 0xa6, 0x5b, 0xa1, 0xef, 0x8a, 0x17, 0x23, 0x45, 0x7a, 0x68, 0x8a, 0xd2, 0x35, 0x77, 0x70,
+	0x87, 0xae, 0xf6, 0x75, 0x6e, 0xaf, 0xdb, 0x74, 0xb5, 0xaf, 0x6b, 0x7f, 0x8d, 0x8f, 0xf6, 0xa3,
+	0x70, 0x89, 0xab, 0x71, 0x94, 0x17, 0x94, 0x3b, 0xe5, 0x85, 0x15, 0x98, 0x09, 0x1c, 0xff, 0x9a,
+	0x3b, 0xe8, 0x45, 0x77, 0xcf, 0x12, 0x24, 0x2d, 0x78, 0x46, 0xea, 0xee, 0x5c, 0x0f, 0xd9, 0xd3,
+	0x29, 0xcf, 0xdb, 0xb3, 0xc4, 0x41, 0xc5, 0x20, 0x44, 0x9f, 0x8e, 0x5f, 0x47, 0x89, 0x8a, 0xfc,
+	0xa4, 0xe0, 0x36, 0x62, 0x66, 0x1a, 0xf3, 0x9a, 0xf1, 0xbb, 0xa9, 0x57, 0xa1, 0xe4, 0xcb, 0x20,
+	0xb6, 0xd8, 0xb5, 0x6c, 0x74, 0xd2, 0xbc, 0x14, 0x5f, 0x20, 0x27, 0x22, 0x9c, 0x16, 0xfd, 0x89,
+	0x80, 0x7f, 0x1d, 0xe6, 0xed, 0xc8, 0xb7, 0xb2, 0xf7, 0xe4, 0xbe, 0x65, 0xd2, 0xf3, 0xb4, 0x64,
+	0x4f, 0x46, 0xc2, 0x29, 0x98, 0x93, 0x1a, 0xd9, 0x9e, 0x6b, 0x8f, 0x37, 0xb6, 0xfb, 0x9e, 0x9c,
+	0xe9, 0x8c, 0x48, 0xe5, 0xe3, 0x34, 0x0e, 0xb0, 0xff, 0xe8, 0xc5, 0xf6, 0xa8, 0xcb, 0x47, 0x3a,
+	0xc0, 0xbb, 0x8b, 0xe4, 0xfb, 0xb4, 0xcc, 0xe4, 0xfb, 0xb4, 0xc9, 0xf7, 0x6e, 0xd9, 0x7d, 0xef,
+	0xdd, 0xb0, 0x8a, 0x2e, 0x4d, 0xea, 0x2f, 0xa3, 0xec, 0x28, 0xee, 0xf9, 0xd8, 0x85, 0xf7, 0xbe,
+	0x32, 0x9a, 0xb8, 0x0a, 0xa7, 0x82, 0x41, 0xfb, 0x0d, 0x9a, 0x70, 0xca, 0x2f, 0x56, 0xfc, 0xff,
+	0xa6, 0x24, 0x8e, 0x87, 0x3e, 0x07, 0x59, 0x7e, 0x67, 0x2f, 0x9f, 0xa2, 0x1c, 0xbe, 0xf5, 0x0f,
+	0x8d, 0xdf, 0xaf, 0x53, 0xc1, 0xc5, 0x12, 0x21, 0x0f, 0xa8, 0x0e, 0x3f, 0x1f, 0x8a, 0x76, 0x88,
+	0x05, 0x86, 0x13, 0x47, 0x46, 0xb7, 0x1e, 0x38, 0x65, 0xee, 0x7a, 0xe0, 0x74, 0xec, 0x07, 0x69,
+	0x98, 0xad, 0xed, 0xb5, 0xae, 0x7a, 0x5b, 0x9e, 0xdd, 0xe3, 0x17, 0xe0, 0xb5, 0xa6, 0x79, 0x11,
+	0xcb, 0xc8, 0x02, 0x14, 0xeb, 0x0d, 0xd3, 0xaa, 0xb3, 0x52, 0xb2, 0x55, 0xd5, 0xcf, 0xa8, 0x0a,
+	0xab, 0x35, 0x4d, 0x5a, 0xb1, 0xce, 0x1a, 0x17, 0x05, 0x26, 0xc5, 0x1e, 0xf9, 0xb4, 0xeb, 0x95,
+	0x73, 0x6d, 0x63, 0x8c, 0xcc, 0x90, 0x65, 0xdc, 0x83, 0xb5, 0xab, 0x66, 0xa5, 0x59, 0x4d, 0xa0,
+	0xf3, 0xac, 0x2e, 0x6d, 0x54, 0x1b, 0x1b, 0x02, 0x54, 0xd9, 0xf8, 0xed, 0x7a, 0xab, 0x72, 0xa6,
+	0x6e, 0x6c, 0x0a, 0xd4, 0xe3, 0x0c, 0x75, 0xc9, 0xa0, 0x8d, 0xad, 0x4a, 0x24, 0xf2, 0x34, 0x8a,
+	0x2c, 0x6c, 0x54, 0xea, 0x3a, 0x95, 0xa3, 0xdc, 0x50, 0x48, 0x09, 0x66, 0x8d, 0x7a, 0xbb, 0x26,
+	0xe1, 0x14, 0x2e, 0xed, 0x45, 0xbd, 0x6d, 0x36, 0xac, 0x4a, 0xbd, 0x4c, 0x8d, 0x1a, 0x7b, 0x1a,
+	0x24, 0x28, 0x19, 0x9c, 0x5c, 0xc9, 0xac, 0xd4, 0x8c, 0x96, 0xa9, 0xd7, 0x9a, 0x12, 0xc9, 0x66,
+	0x91, 0x6f, 0x19, 0x11, 0x8f, 0x8a, 0xb1, 0xb2, 0x5c, 0x6f, 0x58, 0xf2, 0xd5, 0x92, 0x75, 0x5e,
+	0xaf, 0xa2, 0x2a, 0x82, 0xf6, 0x38, 0x39, 0x0c, 0xa4, 0x51, 0xb7, 0xda, 0xcd, 0x4d, 0xdd, 0x34,
+	0xac, 0x7a, 0xe3, 0x82, 0x24, 0x9c, 0xc6, 0x29, 0xe4, 0xc7, 0x33, 0xb8, 0xc1, 0xac, 0x50, 0x6c,
+	0xea, 0xd4, 0x1c, 0x2b, 0x7b, 0xe3, 0x06, 0x33, 0x16, 0x9c, 0xa1, 0x8d, 0x76, 0x73, 0xcc, 0xb6,
+	0xc0, 0x5e, 0x59, 0x71, 0x63, 0x49, 0x54, 0x86, 0xa1, 0x50, 0xbd, 0x72, 0x3c, 0xbf, 0x1b, 0xf9,
+	0xd5, 0x94, 0xaa, 0x1c, 0xbb, 0x02, 0x19, 0xee, 0x8e, 0x3c, 0x64, 0xea, 0x8d, 0x3a, 0x7b, 0xc5,
+	0x35, 0x0f, 0x50, 0x69, 0x55, 0xea, 0xa6, 0x71, 0x86, 0xea, 0x55, 0xa6, 0x36, 0x47, 0x44, 0x06,
+	0x64, 0xda, 0xce, 0xc1, 0x4c, 0xa5, 0xb5, 0x55, 0x6d, 0xe8, 0xa6, 0x54, 0xb3, 0xd2, 0x3a, 0xd7,
+	0x6e, 0xb0, 0xc7, 0x54, 0xa8, 0x66, 0x01, 0x72, 0xec, 0xdd, 0xd4, 0x5b, 0x26, 0xd3, 0x8b, 0xd3,
+	0x84, 0x55, 0x51, 0x9b, 0x63, 0x1f, 0xa6, 0x21, 0xc3, 0xdf, 0x9c, 0xa2, 0x83, 0xb8, 0xb7, 0xd9,
+	0x73, 0x31, 0x14, 0x39, 0x0b, 0x19, 0x14, 0x78, 0x4a, 0xfd, 0x72, 0x8a, 0x00, 0x64, 0xdb, 0xbc,
+	0xfd, 0x95, 0x1c, 0x6b, 0x63, 0xf3, 0x85, 0x93, 0xea, 0xbb, 0x29, 0x36, 0x6c, 0x5b, 0x00, 0x5f,
+	0x8d, 0x08, 0xeb, 0x2f, 0xa9, 0xef, 0xc5, 0x04, 0x04, 0xbe, 0x16, 0x11, 0x5e, 0x5c, 0x57, 0xbf,
+	0x1e, 0x13, 0x10, 0xf8, 0x46, 0x44, 0x38, 0xf9, 0x92, 0xfa, 0xcd, 0x98, 0x80, 0xc0, 0xb7, 0x72,
+	0x4c, 0x17, 0xae, 0x09, 0xb2, 0x7d, 0x3b, 0x1f, 0x43, 0x48, 0xfb, 0x4e, 0x9e, 0xf9, 0x3f, 0xf6,
+	0xaa, 0xfa, 0x5d, 0x95, 0x4d, 0x93, 0x39, 0x48, 0xfd, 0x1e, 0x6f, 0x32, 0x92, 0xfa, 0xbe, 0xca,
+	0x74, 0x64, 0x58, 0x0e, 0x7e, 0xc0, 0x29, 0x17, 0x0d, 0x9d, 0xaa, 0xdf, 0xcf, 0x89, 0x47, 0x6a,
+	0xe5, 0x4a, 0x0d, 0xcd, 0x48, 0x78, 0x0f, 0x66, 0x95, 0x1f, 0x9e, 0x60, 0x4d, 0x16, 0x9e, 0xea,
+	0x8f, 0x9a, 0x4c, 0xe0, 0x79, 0x9d, 0x96, 0xdf, 0xc0, 0x0e, 0x3f, 0x3e, 0xc1, 0x04, 0x22, 0x24,
+	0xed, 0xf5, 0x93, 0x26, 0x63, 0xe4, 0xa4, 0x9b, 0x27, 0xd8, 0xa4, 0x25, 0xfe, 0xa7, 0x4d, 0x74,
+	0x56, 0x7a, 0xa3, 0x62, 0xaa, 0x1f, 0x72, 0x69, 0x2c, 0x44, 0xd5, 0x9f, 0xa9, 0x0c, 0x89, 0xe1,
+	0xa6, 0xfe, 0x9c, 0x21, 0xb3, 0x66, 0x1b, 0x97, 0x84, 0xfa, 0x30, 0x9b, 0xdc, 0x19, 0xa3, 0x51,
+	0x33, 0x4c, 0xec, 0xf8, 0x0b, 0xce, 0xfe, 0x66, 0xab, 0x51, 0x57, 0x3f, 0x52, 0x51, 0x16, 0x18,
+	0x6f, 0x35, 0xa9, 0xd1, 0x6a, 0x55, 0x10, 0xf1, 0xd8, 0xb1, 0x2d, 0x50, 0xf7, 0xa7, 0x03, 0xa6,
+	0x40, 0xbb, 0x7e, 0x16, 0xe3, 0xb1, 0x8e, 0x4e, 0x42, 0x00, 0xd9, 0x31, 0xfa, 0x0c, 0x5c, 0x9f,
+	0x00, 0x39, 0xf1, 0x84, 0x0e, 0x57, 0xe6, 0x1c, 0xe4, 0x69, 0xa3, 0x5a, 0xdd, 0xd0, 0xcb, 0x67,
+	0xd5, 0xf4, 0xc6, 0xcb, 0x30, 0xef, 0x0e, 0xd7, 0xae, 0xb9, 0x21, 0xfe, 0x23, 0x88, 0x57, 0xcd,
+	0x97, 0x34, 0x09, 0xb9, 0xc3, 0xe3, 0xa2, 0x75, 0xbc, 0x87, 0xad, 0xf0, 0x38, 0xa7, 0x1e, 0xe7,
+	0x19, 0x63, 0x3b, 0xc7, 0x81, 0x17, 0xff, 0x0b, 0xfd, 0xa1, 0x83, 0x96, 0x33, 0x2d, 0x00, 0x00,
 }
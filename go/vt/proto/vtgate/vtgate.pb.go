@@ -88,10 +88,15 @@ type Session struct {
 	// transaction_mode specifies the current transaction mode.
 	TransactionMode TransactionMode `protobuf:"varint,7,opt,name=transaction_mode,json=transactionMode,enum=vtgate.TransactionMode" json:"transaction_mode,omitempty"`
 	// warnings contains non-fatal warnings from the previous query
-	Warnings             []*query.QueryWarning `protobuf:"bytes,8,rep,name=warnings" json:"warnings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Warnings []*query.QueryWarning `protobuf:"bytes,8,rep,name=warnings" json:"warnings,omitempty"`
+	// post_sessions keep track of the per-shard transactions that must
+	// be committed after the ones of shard_sessions, like the lookup row
+	// deletes of a consistent lookup vindex. They are separate
+	// transactions, even on a shard that is also in shard_sessions.
+	PostSessions         []*Session_ShardSession `protobuf:"bytes,9,rep,name=post_sessions,json=postSessions" json:"post_sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *Session) Reset()         { *m = Session{} }
@@ -174,6 +179,13 @@ func (m *Session) GetWarnings() []*query.QueryWarning {
 	return nil
 }
 
+func (m *Session) GetPostSessions() []*Session_ShardSession {
+	if m != nil {
+		return m.PostSessions
+	}
+	return nil
+}
+
 type Session_ShardSession struct {
 	Target               *query.Target `protobuf:"bytes,1,opt,name=target" json:"target,omitempty"`
 	TransactionId        int64         `protobuf:"varint,2,opt,name=transaction_id,json=transactionId" json:"transaction_id,omitempty"`
@@ -1765,10 +1777,15 @@ type StreamExecuteResponse struct {
 	// result contains the result data.
 	// The first value contains only Fields information.
 	// The next values contain the actual rows, a few values per result.
-	Result               *query.QueryResult `protobuf:"bytes,1,opt,name=result" json:"result,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Result *query.QueryResult `protobuf:"bytes,1,opt,name=result" json:"result,omitempty"`
+	// keep_alive is set on the responses vtgate sends when no result was
+	// sent for -stream_keep_alive_interval, so idle streams are not dropped
+	// by proxies or timeouts. Such responses have no result. They are only
+	// sent after the first result, which has the Fields.
+	KeepAlive            bool     `protobuf:"varint,2,opt,name=keep_alive,json=keepAlive" json:"keep_alive,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StreamExecuteResponse) Reset()         { *m = StreamExecuteResponse{} }
@@ -1802,6 +1819,13 @@ func (m *StreamExecuteResponse) GetResult() *query.QueryResult {
 	return nil
 }
 
+func (m *StreamExecuteResponse) GetKeepAlive() bool {
+	if m != nil {
+		return m.KeepAlive
+	}
+	return false
+}
+
 // StreamExecuteShardsRequest is the payload to StreamExecuteShards.
 type StreamExecuteShardsRequest struct {
 	// caller_id identifies the caller. This is the effective caller ID,
@@ -3465,123 +3489,125 @@ func init() {
 func init() { proto.RegisterFile("vtgate.proto", fileDescriptor_vtgate_071b9c990aff35bf) }
 
 var fileDescriptor_vtgate_071b9c990aff35bf = []byte{
-	// 1883 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0x4f, 0x8f, 0x23, 0x47,
-	0x15, 0xa7, 0xbb, 0xfd, 0xf7, 0xf9, 0xef, 0xd6, 0x78, 0x77, 0x1d, 0x67, 0xd8, 0x99, 0x74, 0x18,
-	0xc5, 0x49, 0x56, 0x36, 0x71, 0x20, 0x20, 0x84, 0x04, 0x19, 0xef, 0x10, 0x59, 0xd9, 0xd9, 0x0c,
-	0x65, 0x6f, 0x16, 0x10, 0x51, 0xab, 0xc7, 0x2e, 0x79, 0x1b, 0xdb, 0xdd, 0x4e, 0x57, 0xd9, 0xcb,
-	0x70, 0x40, 0xf9, 0x06, 0x11, 0x07, 0x24, 0x14, 0x21, 0x21, 0x24, 0x24, 0x4e, 0x5c, 0x91, 0x80,
-	0x0b, 0x37, 0x8e, 0x88, 0x13, 0x07, 0x6e, 0x7c, 0x01, 0x24, 0x3e, 0x41, 0xd4, 0x55, 0xd5, 0x7f,
-	0xdc, 0x33, 0xf6, 0x78, 0x3c, 0x3b, 0x2b, 0xef, 0xc5, 0xea, 0x7a, 0x55, 0xf5, 0xea, 0xbd, 0xdf,
-	0xfb, 0xd5, 0xab, 0xd7, 0xd5, 0x86, 0xfc, 0x9c, 0x0d, 0x4d, 0x46, 0x1a, 0x53, 0xd7, 0x61, 0x0e,
-	0x4a, 0x89, 0x56, 0x2d, 0xf7, 0xe9, 0x8c, 0xb8, 0x67, 0x42, 0x58, 0x2b, 0x32, 0x67, 0xea, 0x0c,
-	0x4c, 0x66, 0xca, 0x76, 0x6e, 0xce, 0xdc, 0x69, 0x5f, 0x34, 0xf4, 0xff, 0x68, 0x90, 0xee, 0x12,
-	0x4a, 0x2d, 0xc7, 0x46, 0x07, 0x50, 0xb4, 0x6c, 0x83, 0xb9, 0xa6, 0x4d, 0xcd, 0x3e, 0xb3, 0x1c,
-	0xbb, 0xaa, 0xec, 0x2b, 0xf5, 0x0c, 0x2e, 0x58, 0x76, 0x2f, 0x14, 0xa2, 0x36, 0x14, 0xe9, 0x53,
-	0xd3, 0x1d, 0x18, 0x54, 0xcc, 0xa3, 0x55, 0x75, 0x5f, 0xab, 0xe7, 0x5a, 0xbb, 0x0d, 0x69, 0x8b,
-	0xd4, 0xd7, 0xe8, 0x7a, 0xa3, 0x64, 0x03, 0x17, 0x68, 0xa4, 0x45, 0xd1, 0xab, 0x90, 0xa5, 0x96,
-	0x3d, 0x1c, 0x13, 0x63, 0x70, 0x5a, 0xd5, 0xf8, 0x32, 0x19, 0x21, 0x78, 0x70, 0x8a, 0xee, 0x01,
-	0x98, 0x33, 0xe6, 0xf4, 0x9d, 0xc9, 0xc4, 0x62, 0xd5, 0x04, 0xef, 0x8d, 0x48, 0xd0, 0xeb, 0x50,
-	0x60, 0xa6, 0x3b, 0x24, 0xcc, 0xa0, 0xcc, 0xb5, 0xec, 0x61, 0x35, 0xb9, 0xaf, 0xd4, 0xb3, 0x38,
-	0x2f, 0x84, 0x5d, 0x2e, 0x43, 0x4d, 0x48, 0x3b, 0x53, 0xc6, 0xed, 0x4b, 0xed, 0x2b, 0xf5, 0x5c,
-	0xeb, 0x76, 0x43, 0xa0, 0x72, 0xf4, 0x73, 0xd2, 0x9f, 0x31, 0xf2, 0x91, 0xe8, 0xc4, 0xfe, 0x28,
-	0x74, 0x08, 0xe5, 0x88, 0xef, 0xc6, 0xc4, 0x19, 0x90, 0x6a, 0x7a, 0x5f, 0xa9, 0x17, 0x5b, 0x77,
-	0x7d, 0xcf, 0x22, 0x30, 0x1c, 0x3b, 0x03, 0x82, 0x4b, 0x6c, 0x51, 0x80, 0x9a, 0x90, 0x79, 0x66,
-	0xba, 0xb6, 0x65, 0x0f, 0x69, 0x35, 0xc3, 0x51, 0xd9, 0x91, 0xab, 0xfe, 0xd0, 0xfb, 0x7d, 0x22,
-	0xfa, 0x70, 0x30, 0xa8, 0xf6, 0x53, 0xc8, 0x47, 0x61, 0x42, 0x07, 0x90, 0x12, 0x5e, 0x70, 0xec,
-	0x73, 0xad, 0x82, 0x9c, 0xde, 0xe3, 0x42, 0x2c, 0x3b, 0xbd, 0x50, 0x45, 0x6d, 0xb5, 0x06, 0x55,
-	0x75, 0x5f, 0xa9, 0x6b, 0xb8, 0x10, 0x91, 0x76, 0x06, 0xfa, 0x3f, 0x55, 0x28, 0x4a, 0x77, 0x31,
-	0xf9, 0x74, 0x46, 0x28, 0x43, 0xf7, 0x21, 0xdb, 0x37, 0xc7, 0x63, 0xe2, 0x7a, 0x93, 0xc4, 0x1a,
-	0xa5, 0x86, 0x60, 0x44, 0x9b, 0xcb, 0x3b, 0x0f, 0x70, 0x46, 0x8c, 0xe8, 0x0c, 0xd0, 0x9b, 0x90,
-	0x96, 0x51, 0xe6, 0x0b, 0x88, 0xb1, 0xd1, 0x20, 0x63, 0xbf, 0x1f, 0xbd, 0x01, 0x49, 0x6e, 0x2a,
-	0x8f, 0x66, 0xae, 0x75, 0x4b, 0x1a, 0x7e, 0xe8, 0xcc, 0xec, 0x01, 0x77, 0x1e, 0x8b, 0x7e, 0xf4,
-	0x4d, 0xc8, 0x31, 0xf3, 0x74, 0x4c, 0x98, 0xc1, 0xce, 0xa6, 0x84, 0x87, 0xb7, 0xd8, 0xaa, 0x34,
-	0x02, 0x96, 0xf6, 0x78, 0x67, 0xef, 0x6c, 0x4a, 0x30, 0xb0, 0xe0, 0x19, 0xdd, 0x07, 0x64, 0x3b,
-	0xcc, 0x88, 0x31, 0x34, 0xc9, 0xc9, 0x51, 0xb6, 0x1d, 0xd6, 0x59, 0x20, 0xe9, 0x01, 0x14, 0x47,
-	0xe4, 0x8c, 0x4e, 0xcd, 0x3e, 0x31, 0x38, 0xf3, 0x38, 0x09, 0xb2, 0xb8, 0xe0, 0x4b, 0x39, 0xea,
-	0x51, 0x92, 0xa4, 0xd7, 0x21, 0x89, 0xfe, 0xb9, 0x02, 0xa5, 0x00, 0x51, 0x3a, 0x75, 0x6c, 0x4a,
-	0xd0, 0x01, 0x24, 0x89, 0xeb, 0x3a, 0x6e, 0x0c, 0x4e, 0x7c, 0xd2, 0x3e, 0xf2, 0xc4, 0x58, 0xf4,
-	0x5e, 0x05, 0xcb, 0xb7, 0x20, 0xe5, 0x12, 0x3a, 0x1b, 0x33, 0x09, 0x26, 0x8a, 0x92, 0x08, 0xf3,
-	0x1e, 0x2c, 0x47, 0xe8, 0xff, 0x55, 0xa1, 0x22, 0x2d, 0xe2, 0x3e, 0xd1, 0xed, 0x89, 0x74, 0x0d,
-	0x32, 0x3e, 0xdc, 0x3c, 0xcc, 0x59, 0x1c, 0xb4, 0xd1, 0x1d, 0x48, 0xf1, 0xb8, 0xd0, 0x6a, 0x72,
-	0x5f, 0xab, 0x67, 0xb1, 0x6c, 0xc5, 0xd9, 0x91, 0xba, 0x16, 0x3b, 0xd2, 0x4b, 0xd8, 0x11, 0x09,
-	0x7b, 0x66, 0xad, 0xb0, 0xff, 0x5a, 0x81, 0xdb, 0x31, 0x90, 0xb7, 0x22, 0xf8, 0xff, 0x57, 0xe1,
-	0x15, 0x69, 0xd7, 0x87, 0x12, 0xd9, 0xce, 0xcb, 0xc2, 0x80, 0xd7, 0x20, 0x1f, 0x6c, 0x51, 0x4b,
-	0xf2, 0x20, 0x8f, 0x73, 0xa3, 0xd0, 0x8f, 0x2d, 0x25, 0xc3, 0x17, 0x0a, 0xd4, 0x2e, 0x02, 0x7d,
-	0x2b, 0x18, 0xf1, 0x99, 0x06, 0x77, 0x43, 0xe3, 0xb0, 0x69, 0x0f, 0xc9, 0x4b, 0xc2, 0x87, 0x77,
-	0x00, 0x46, 0xe4, 0xcc, 0x70, 0xb9, 0xc9, 0x9c, 0x0d, 0x9e, 0xa7, 0x41, 0xac, 0x7d, 0x6f, 0x70,
-	0x76, 0xe4, 0xfb, 0xb5, 0xa5, 0xfc, 0xf8, 0x8d, 0x02, 0xd5, 0xf3, 0x21, 0xd8, 0x0a, 0x76, 0xfc,
-	0x25, 0x11, 0xb0, 0xe3, 0xc8, 0x66, 0x16, 0x3b, 0x7b, 0x69, 0xb2, 0xc5, 0x7d, 0x40, 0x84, 0x5b,
-	0x6c, 0xf4, 0x9d, 0xf1, 0x6c, 0x62, 0x1b, 0xb6, 0x39, 0x21, 0xb2, 0xf0, 0x2b, 0x8b, 0x9e, 0x36,
-	0xef, 0x78, 0x64, 0x4e, 0x08, 0xfa, 0x11, 0xec, 0xc8, 0xd1, 0x0b, 0x29, 0x26, 0xc5, 0x49, 0x55,
-	0xf7, 0x2d, 0x5d, 0x82, 0x44, 0xc3, 0x17, 0xe0, 0x5b, 0x42, 0xc9, 0x87, 0xcb, 0x53, 0x52, 0xfa,
-	0x5a, 0x94, 0xcb, 0x5c, 0x4e, 0xb9, 0xec, 0x3a, 0x94, 0xab, 0x9d, 0x42, 0xc6, 0x37, 0x1a, 0xed,
-	0x41, 0x82, 0x9b, 0xa6, 0x70, 0xd3, 0x72, 0x7e, 0x01, 0xe9, 0x59, 0xc4, 0x3b, 0x50, 0x05, 0x92,
-	0x73, 0x73, 0x3c, 0x23, 0x3c, 0x70, 0x79, 0x2c, 0x1a, 0x68, 0x0f, 0x72, 0x11, 0xac, 0x78, 0xac,
-	0xf2, 0x18, 0xc2, 0x6c, 0x1c, 0xa5, 0x75, 0x04, 0xb1, 0xad, 0xa0, 0xf5, 0xbf, 0x54, 0xd8, 0x91,
-	0xa6, 0x1d, 0x9a, 0xac, 0xff, 0xf4, 0xc6, 0x29, 0xfd, 0x36, 0xa4, 0x3d, 0x6b, 0x2c, 0x42, 0xab,
-	0x1a, 0xe7, 0xd4, 0x05, 0xa4, 0xf6, 0x47, 0x6c, 0x5a, 0xf0, 0x1e, 0x40, 0xd1, 0xa4, 0x17, 0x14,
-	0xbb, 0x05, 0x93, 0xbe, 0x88, 0x4a, 0xf7, 0x0b, 0x25, 0xa8, 0x2b, 0x25, 0xa6, 0x37, 0x16, 0xea,
-	0xaf, 0x43, 0x5a, 0x04, 0xd2, 0x47, 0xf3, 0x8e, 0xb4, 0x4d, 0x84, 0xf9, 0x89, 0xc5, 0x9e, 0x0a,
-	0xd5, 0xfe, 0x30, 0xdd, 0x86, 0x12, 0x47, 0x9a, 0xfb, 0xc6, 0xe1, 0x0e, 0xb3, 0x8c, 0x72, 0x85,
-	0x2c, 0xa3, 0x2e, 0xad, 0x4a, 0xb5, 0x68, 0x55, 0xaa, 0xff, 0x39, 0xac, 0xb3, 0x38, 0x18, 0x2f,
-	0xa8, 0xd2, 0x7e, 0x27, 0x4e, 0xb3, 0xe0, 0x4d, 0x34, 0xe6, 0xfd, 0x8b, 0x22, 0xdb, 0x55, 0x5f,
-	0xaa, 0xf5, 0xdf, 0x86, 0xb5, 0xd2, 0x02, 0x70, 0x37, 0xc6, 0xa5, 0xfb, 0x71, 0x2e, 0x5d, 0x94,
-	0x37, 0x02, 0x1e, 0xfd, 0x12, 0x2a, 0x1c, 0xc9, 0x30, 0xc3, 0x3f, 0x47, 0x32, 0xc5, 0x0b, 0x5c,
-	0xed, 0x5c, 0x81, 0xab, 0xff, 0x5d, 0x85, 0x7b, 0x51, 0x78, 0x5e, 0x64, 0x11, 0xff, 0x5e, 0x9c,
-	0x5c, 0xbb, 0x0b, 0xe4, 0x8a, 0x41, 0xb2, 0xb5, 0x0c, 0xfb, 0xbd, 0x02, 0x7b, 0x4b, 0x21, 0xdc,
-	0x12, 0x9a, 0xfd, 0x51, 0x85, 0x4a, 0x97, 0xb9, 0xc4, 0x9c, 0x5c, 0xeb, 0x36, 0x26, 0x60, 0xa5,
-	0x7a, 0xb5, 0x2b, 0x16, 0x6d, 0xfd, 0x10, 0xc5, 0x8e, 0x92, 0xc4, 0x25, 0x47, 0x49, 0x72, 0xad,
-	0x9b, 0xb5, 0x08, 0xae, 0xa9, 0xd5, 0xb8, 0xea, 0x6d, 0xb8, 0x1d, 0x03, 0x4a, 0x86, 0x30, 0x2c,
-	0x07, 0x94, 0x4b, 0xcb, 0x81, 0xcf, 0x55, 0xa8, 0x2d, 0x68, 0xb9, 0x4e, 0xba, 0x5e, 0x1b, 0xf4,
-	0x68, 0x2a, 0xd0, 0x96, 0x9e, 0x2b, 0x89, 0x55, 0xb7, 0x1d, 0xc9, 0x35, 0x03, 0x75, 0xe5, 0x4d,
-	0xd2, 0x81, 0x57, 0x2f, 0x04, 0x64, 0x03, 0x70, 0x7f, 0xa7, 0xc2, 0xde, 0x82, 0xae, 0x6b, 0xe7,
-	0xac, 0xe7, 0x82, 0x70, 0x3c, 0xd9, 0x26, 0x2e, 0xbd, 0x4d, 0xb8, 0x31, 0xb0, 0x1f, 0xc1, 0xfe,
-	0x72, 0x80, 0x36, 0x40, 0xfc, 0x4f, 0x2a, 0x7c, 0x35, 0xae, 0xf0, 0x3a, 0x2f, 0xf6, 0xcf, 0x05,
-	0xef, 0xc5, 0xb7, 0xf5, 0xc4, 0x06, 0x6f, 0xeb, 0x37, 0x86, 0xff, 0x43, 0xb8, 0xb7, 0x0c, 0xae,
-	0x0d, 0xd0, 0xff, 0x31, 0xe4, 0x0f, 0xc9, 0xd0, 0xb2, 0x37, 0xc3, 0x7a, 0xe1, 0x3b, 0x87, 0xba,
-	0xf8, 0x9d, 0x43, 0xff, 0x0e, 0x14, 0xa4, 0x6a, 0x69, 0x57, 0x24, 0x51, 0x2a, 0x97, 0x24, 0xca,
-	0xcf, 0x14, 0x28, 0xb4, 0xf9, 0xe7, 0x90, 0x1b, 0x2f, 0x14, 0xee, 0x40, 0xca, 0x64, 0xce, 0xc4,
-	0xea, 0xcb, 0x0f, 0x35, 0xb2, 0xa5, 0x97, 0xa1, 0xe8, 0x5b, 0x20, 0xec, 0xd7, 0x7f, 0x06, 0x25,
-	0xec, 0x8c, 0xc7, 0xa7, 0x66, 0x7f, 0x74, 0xd3, 0x56, 0xe9, 0x08, 0xca, 0xe1, 0x5a, 0x72, 0xfd,
-	0x4f, 0xe0, 0x15, 0x4c, 0xa8, 0x33, 0x9e, 0x93, 0x48, 0x49, 0xb1, 0x99, 0x25, 0x08, 0x12, 0x03,
-	0x26, 0xbf, 0xab, 0x64, 0x31, 0x7f, 0xd6, 0xff, 0xa6, 0x40, 0xe5, 0x98, 0x50, 0x6a, 0x0e, 0x89,
-	0x20, 0xd8, 0x66, 0xaa, 0x57, 0xd5, 0x8c, 0x15, 0x48, 0x8a, 0x93, 0x57, 0xec, 0x37, 0xd1, 0x40,
-	0x4d, 0xc8, 0x06, 0x9b, 0x8d, 0x9f, 0xc9, 0x17, 0xef, 0xb5, 0x8c, 0xbf, 0xd7, 0x3c, 0xeb, 0x23,
-	0xf7, 0x23, 0xfc, 0x59, 0xff, 0x95, 0x02, 0xb7, 0xa4, 0xf5, 0xef, 0x6f, 0x1a, 0x9f, 0x55, 0xa6,
-	0xfb, 0x6b, 0x6a, 0xe1, 0x9a, 0xe8, 0x1e, 0x68, 0x7e, 0x32, 0xce, 0xb5, 0xf2, 0x72, 0x97, 0x7d,
-	0x6c, 0x8e, 0x67, 0x04, 0x7b, 0x1d, 0xfa, 0x31, 0xe4, 0x3b, 0x91, 0x4a, 0x13, 0xed, 0x82, 0x1a,
-	0x98, 0xb1, 0x38, 0x5c, 0xb5, 0x06, 0xf1, 0x2b, 0x0a, 0xf5, 0xdc, 0x15, 0xc5, 0x5f, 0x15, 0xd8,
-	0x0d, 0x5d, 0xbc, 0xf6, 0xc1, 0x74, 0x55, 0x6f, 0xbf, 0x0b, 0x25, 0x6b, 0x60, 0x9c, 0x3b, 0x86,
-	0x72, 0xad, 0x8a, 0xcf, 0xe2, 0xa8, 0xb3, 0xb8, 0x60, 0x45, 0x5a, 0x54, 0xdf, 0x85, 0xda, 0x45,
-	0xe4, 0x95, 0xd4, 0xfe, 0x9f, 0x0a, 0xb7, 0xba, 0xd3, 0xb1, 0xc5, 0x64, 0x8e, 0x7a, 0xde, 0xfe,
-	0xac, 0x7d, 0x49, 0xf7, 0x1a, 0xe4, 0xa9, 0x67, 0x87, 0xbc, 0x87, 0x93, 0x05, 0x4d, 0x8e, 0xcb,
-	0xc4, 0x0d, 0x9c, 0x17, 0x27, 0x7f, 0xc8, 0xcc, 0x66, 0x9c, 0x84, 0x1a, 0x06, 0x39, 0x62, 0x66,
-	0x33, 0xf4, 0x0d, 0xb8, 0x6b, 0xcf, 0x26, 0x86, 0xeb, 0x3c, 0xa3, 0xc6, 0x94, 0xb8, 0x06, 0xd7,
-	0x6c, 0x4c, 0x4d, 0x97, 0xf1, 0x14, 0xaf, 0xe1, 0x1d, 0x7b, 0x36, 0xc1, 0xce, 0x33, 0x7a, 0x42,
-	0x5c, 0xbe, 0xf8, 0x89, 0xe9, 0x32, 0xf4, 0x7d, 0xc8, 0x9a, 0xe3, 0xa1, 0xe3, 0x5a, 0xec, 0xe9,
-	0x44, 0x5e, 0xbc, 0xe9, 0xd2, 0xcc, 0x73, 0xc8, 0x34, 0xde, 0xf7, 0x47, 0xe2, 0x70, 0x12, 0x7a,
-	0x1b, 0xd0, 0x8c, 0x12, 0x43, 0x18, 0x27, 0x16, 0x9d, 0xb7, 0xe4, 0x2d, 0x5c, 0x69, 0x46, 0x49,
-	0xa8, 0xe6, 0xe3, 0x96, 0xfe, 0x0f, 0x0d, 0x50, 0x54, 0xaf, 0xcc, 0xd1, 0xdf, 0x82, 0x14, 0x9f,
-	0x4f, 0xab, 0x0a, 0x8f, 0xed, 0x5e, 0x90, 0xa1, 0xce, 0x8d, 0x6d, 0x78, 0x66, 0x63, 0x39, 0xbc,
-	0xf6, 0x09, 0xe4, 0xfd, 0x9d, 0xca, 0xdd, 0x89, 0x46, 0x43, 0x59, 0x79, 0xba, 0xaa, 0x6b, 0x9c,
-	0xae, 0xb5, 0xef, 0x41, 0x96, 0x57, 0x75, 0x97, 0xea, 0x0e, 0x6b, 0x51, 0x35, 0x5a, 0x8b, 0xd6,
-	0xfe, 0xad, 0x40, 0x82, 0x4f, 0x5e, 0xfb, 0xe5, 0xf7, 0x98, 0xbf, 0x2f, 0x08, 0x2b, 0x45, 0xf4,
-	0x44, 0xd2, 0x7e, 0x63, 0x05, 0x24, 0x51, 0x08, 0x70, 0x7e, 0x14, 0x05, 0xa4, 0x0d, 0x20, 0xfe,
-	0x58, 0xc0, 0x55, 0x09, 0x1e, 0x7e, 0x6d, 0x85, 0xaa, 0xc0, 0x5d, 0x9c, 0xa5, 0x81, 0xe7, 0x08,
-	0x12, 0xd4, 0xfa, 0x85, 0xc8, 0x92, 0x1a, 0xe6, 0xcf, 0xfa, 0xbb, 0x70, 0xfb, 0x03, 0xc2, 0xba,
-	0xee, 0xdc, 0xdf, 0x6e, 0xfe, 0xf6, 0x59, 0x01, 0x93, 0x8e, 0xe1, 0x4e, 0x7c, 0x92, 0x64, 0xc0,
-	0xb7, 0x21, 0x4f, 0xdd, 0xb9, 0xb1, 0x30, 0xd3, 0xab, 0x4a, 0x82, 0xf0, 0x44, 0x27, 0xe5, 0x68,
-	0xd8, 0xd0, 0xff, 0xa0, 0xc2, 0xce, 0xe3, 0xe9, 0xc0, 0x64, 0xdb, 0x7e, 0x7e, 0x6c, 0x58, 0xaa,
-	0xed, 0x42, 0x96, 0x59, 0x13, 0x42, 0x99, 0x39, 0x99, 0xca, 0x9d, 0x1c, 0x0a, 0x3c, 0x5e, 0x91,
-	0x39, 0xb1, 0x99, 0xbc, 0x80, 0xf4, 0x79, 0x75, 0xe4, 0xc9, 0x7a, 0xce, 0x88, 0xd8, 0x58, 0xf4,
-	0xeb, 0x23, 0xa8, 0x2c, 0xa2, 0x24, 0x81, 0xaf, 0xfb, 0x0a, 0x16, 0xab, 0x36, 0x59, 0xec, 0x79,
-	0x3d, 0x52, 0x03, 0x7a, 0x13, 0xca, 0x5e, 0xf9, 0x36, 0x21, 0x46, 0x68, 0x8f, 0xf8, 0x87, 0x44,
-	0x49, 0xc8, 0x7b, 0xbe, 0xf8, 0xad, 0x07, 0x50, 0x8a, 0xfd, 0xad, 0x03, 0x95, 0x20, 0xf7, 0xf8,
-	0x51, 0xf7, 0xe4, 0xa8, 0xdd, 0xf9, 0x41, 0xe7, 0xe8, 0x41, 0xf9, 0x2b, 0x08, 0x20, 0xd5, 0xed,
-	0x3c, 0xfa, 0xe0, 0xe1, 0x51, 0x59, 0x41, 0x59, 0x48, 0x1e, 0x3f, 0x7e, 0xd8, 0xeb, 0x94, 0x55,
-	0xef, 0xb1, 0xf7, 0xe4, 0xa3, 0x93, 0x76, 0x59, 0x3b, 0x7c, 0x0f, 0x4a, 0x96, 0xd3, 0x98, 0x5b,
-	0x8c, 0x50, 0x2a, 0xfe, 0x5a, 0xf3, 0x93, 0xd7, 0x65, 0xcb, 0x72, 0x9a, 0xe2, 0xa9, 0x39, 0x74,
-	0x9a, 0x73, 0xd6, 0xe4, 0xbd, 0x4d, 0x41, 0xeb, 0xd3, 0x14, 0x6f, 0xbd, 0xfb, 0x65, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x52, 0xf0, 0x0f, 0xdd, 0xc8, 0x23, 0x00, 0x00,
+	// 1912 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0xcd, 0x8f, 0x23, 0x47,
+	0x15, 0xa7, 0xbb, 0xfd, 0xf9, 0xfc, 0xb9, 0x35, 0xde, 0x5d, 0xc7, 0x99, 0xec, 0x38, 0x1d, 0x46,
+	0x71, 0x92, 0x95, 0x87, 0x38, 0x10, 0x10, 0x42, 0x82, 0x9d, 0xd9, 0x21, 0xb2, 0xb2, 0xb3, 0x19,
+	0x6a, 0x66, 0xb3, 0x80, 0x88, 0x5a, 0x3d, 0x76, 0xc9, 0xdb, 0xd8, 0xee, 0xee, 0x74, 0x95, 0xbd,
+	0x0c, 0x07, 0x94, 0xff, 0x20, 0xe2, 0x80, 0x84, 0x56, 0x08, 0x84, 0x84, 0xc4, 0x89, 0x2b, 0x12,
+	0x70, 0xe1, 0xc6, 0x11, 0x71, 0xe2, 0xce, 0x3f, 0x80, 0xc4, 0x5f, 0x80, 0xba, 0xaa, 0xfa, 0x73,
+	0xbe, 0x3c, 0x9e, 0x99, 0x95, 0xf7, 0x62, 0x75, 0xbd, 0x57, 0x1f, 0xef, 0xfd, 0xde, 0xaf, 0x5e,
+	0xbd, 0xae, 0x36, 0x94, 0xe7, 0x6c, 0x64, 0x32, 0xd2, 0x75, 0x3d, 0x87, 0x39, 0x28, 0x27, 0x5a,
+	0xad, 0xd2, 0xe7, 0x33, 0xe2, 0x1d, 0x0b, 0x61, 0xab, 0xca, 0x1c, 0xd7, 0x19, 0x9a, 0xcc, 0x94,
+	0xed, 0xd2, 0x9c, 0x79, 0xee, 0x40, 0x34, 0xf4, 0xdf, 0x66, 0x20, 0x7f, 0x40, 0x28, 0xb5, 0x1c,
+	0x1b, 0x6d, 0x42, 0xd5, 0xb2, 0x0d, 0xe6, 0x99, 0x36, 0x35, 0x07, 0xcc, 0x72, 0xec, 0xa6, 0xd2,
+	0x56, 0x3a, 0x05, 0x5c, 0xb1, 0xec, 0xc3, 0x48, 0x88, 0x76, 0xa0, 0x4a, 0x9f, 0x99, 0xde, 0xd0,
+	0xa0, 0x62, 0x1c, 0x6d, 0xaa, 0x6d, 0xad, 0x53, 0xea, 0xad, 0x77, 0xa5, 0x2d, 0x72, 0xbe, 0xee,
+	0x81, 0xdf, 0x4b, 0x36, 0x70, 0x85, 0xc6, 0x5a, 0x14, 0xbd, 0x0e, 0x45, 0x6a, 0xd9, 0xa3, 0x09,
+	0x31, 0x86, 0x47, 0x4d, 0x8d, 0x2f, 0x53, 0x10, 0x82, 0x87, 0x47, 0xe8, 0x1e, 0x80, 0x39, 0x63,
+	0xce, 0xc0, 0x99, 0x4e, 0x2d, 0xd6, 0xcc, 0x70, 0x6d, 0x4c, 0x82, 0xde, 0x82, 0x0a, 0x33, 0xbd,
+	0x11, 0x61, 0x06, 0x65, 0x9e, 0x65, 0x8f, 0x9a, 0xd9, 0xb6, 0xd2, 0x29, 0xe2, 0xb2, 0x10, 0x1e,
+	0x70, 0x19, 0xda, 0x82, 0xbc, 0xe3, 0x32, 0x6e, 0x5f, 0xae, 0xad, 0x74, 0x4a, 0xbd, 0xdb, 0x5d,
+	0x81, 0xca, 0xee, 0xcf, 0xc8, 0x60, 0xc6, 0xc8, 0x27, 0x42, 0x89, 0x83, 0x5e, 0x68, 0x1b, 0xea,
+	0x31, 0xdf, 0x8d, 0xa9, 0x33, 0x24, 0xcd, 0x7c, 0x5b, 0xe9, 0x54, 0x7b, 0x77, 0x03, 0xcf, 0x62,
+	0x30, 0xec, 0x39, 0x43, 0x82, 0x6b, 0x2c, 0x29, 0x40, 0x5b, 0x50, 0x78, 0x6e, 0x7a, 0xb6, 0x65,
+	0x8f, 0x68, 0xb3, 0xc0, 0x51, 0x59, 0x93, 0xab, 0xfe, 0xc0, 0xff, 0x7d, 0x2a, 0x74, 0x38, 0xec,
+	0x84, 0x1e, 0x40, 0xc5, 0x75, 0x28, 0x8b, 0xb0, 0x2c, 0x2e, 0x80, 0x65, 0xd9, 0x1f, 0x22, 0x1b,
+	0xb4, 0xf5, 0x13, 0x28, 0xc7, 0xb5, 0x68, 0x13, 0x72, 0x02, 0x08, 0x1e, 0xbe, 0x52, 0xaf, 0x22,
+	0x2d, 0x38, 0xe4, 0x42, 0x2c, 0x95, 0x7e, 0xb4, 0xe3, 0xee, 0x5a, 0xc3, 0xa6, 0xda, 0x56, 0x3a,
+	0x1a, 0xae, 0xc4, 0xa4, 0xfd, 0xa1, 0xfe, 0x4f, 0x15, 0xaa, 0x12, 0x31, 0x4c, 0x3e, 0x9f, 0x11,
+	0xca, 0xd0, 0x7d, 0x28, 0x0e, 0xcc, 0xc9, 0x84, 0x78, 0xfe, 0x20, 0xb1, 0x46, 0xad, 0x2b, 0x48,
+	0xb5, 0xc3, 0xe5, 0xfd, 0x87, 0xb8, 0x20, 0x7a, 0xf4, 0x87, 0xe8, 0x1d, 0xc8, 0x4b, 0xe7, 0x9a,
+	0x6a, 0xd8, 0x37, 0xee, 0x1b, 0x0e, 0xf4, 0xe8, 0x6d, 0xc8, 0x72, 0x53, 0x39, 0x21, 0x4a, 0xbd,
+	0x5b, 0xd2, 0xf0, 0x6d, 0x67, 0x66, 0x0f, 0x39, 0x7e, 0x58, 0xe8, 0xd1, 0x37, 0xa0, 0xc4, 0xcc,
+	0xa3, 0x09, 0x61, 0x06, 0x3b, 0x76, 0x09, 0x67, 0x48, 0xb5, 0xd7, 0xe8, 0x86, 0x44, 0x3f, 0xe4,
+	0xca, 0xc3, 0x63, 0x97, 0x60, 0x60, 0xe1, 0x33, 0xba, 0x0f, 0xc8, 0x76, 0x98, 0x91, 0x22, 0x79,
+	0x96, 0xf3, 0xab, 0x6e, 0x3b, 0xac, 0x9f, 0xe0, 0xf9, 0x26, 0x54, 0xc7, 0xe4, 0x98, 0xba, 0xe6,
+	0x80, 0x18, 0x9c, 0xbc, 0x9c, 0x47, 0x45, 0x5c, 0x09, 0xa4, 0x1c, 0xf5, 0x38, 0xcf, 0xf2, 0x8b,
+	0xf0, 0x4c, 0xff, 0x52, 0x81, 0x5a, 0x88, 0x28, 0x75, 0x1d, 0x9b, 0x12, 0xb4, 0x09, 0x59, 0xe2,
+	0x79, 0x8e, 0x97, 0x82, 0x13, 0xef, 0xef, 0xec, 0xfa, 0x62, 0x2c, 0xb4, 0x97, 0xc1, 0xf2, 0x5d,
+	0xc8, 0x79, 0x84, 0xce, 0x26, 0x4c, 0x82, 0x89, 0xe2, 0x3c, 0xc4, 0x5c, 0x83, 0x65, 0x0f, 0xfd,
+	0x3f, 0x2a, 0x34, 0xa4, 0x45, 0xdc, 0x27, 0xba, 0x3a, 0x91, 0x6e, 0x41, 0x21, 0x80, 0x9b, 0x87,
+	0xb9, 0x88, 0xc3, 0x36, 0xba, 0x03, 0x39, 0x1e, 0x17, 0xda, 0xcc, 0xb6, 0xb5, 0x4e, 0x11, 0xcb,
+	0x56, 0x9a, 0x1d, 0xb9, 0x2b, 0xb1, 0x23, 0x7f, 0x06, 0x3b, 0x62, 0x61, 0x2f, 0x2c, 0x14, 0xf6,
+	0x5f, 0x29, 0x70, 0x3b, 0x05, 0xf2, 0x4a, 0x04, 0xff, 0x7f, 0x2a, 0xbc, 0x26, 0xed, 0xfa, 0x58,
+	0x22, 0xdb, 0x7f, 0x55, 0x18, 0xf0, 0x26, 0x94, 0xc3, 0x2d, 0x6a, 0x49, 0x1e, 0x94, 0x71, 0x69,
+	0x1c, 0xf9, 0xb1, 0xa2, 0x64, 0x78, 0xa1, 0x40, 0xeb, 0x34, 0xd0, 0x57, 0x82, 0x11, 0x5f, 0x68,
+	0x70, 0x37, 0x32, 0x0e, 0x9b, 0xf6, 0x88, 0xbc, 0x22, 0x7c, 0x78, 0x1f, 0x60, 0x4c, 0x8e, 0x0d,
+	0x8f, 0x9b, 0xcc, 0xd9, 0xe0, 0x7b, 0x1a, 0xc6, 0x3a, 0xf0, 0x06, 0x17, 0xc7, 0xf2, 0x69, 0x55,
+	0xf9, 0xf1, 0x6b, 0x05, 0x9a, 0x27, 0x43, 0xb0, 0x12, 0xec, 0xf8, 0x4b, 0x26, 0x64, 0xc7, 0xae,
+	0xcd, 0x2c, 0x76, 0xfc, 0xca, 0x64, 0x8b, 0xfb, 0x80, 0x08, 0xb7, 0xd8, 0x18, 0x38, 0x93, 0xd9,
+	0xd4, 0x36, 0x6c, 0x73, 0x4a, 0x64, 0xed, 0x58, 0x17, 0x9a, 0x1d, 0xae, 0x78, 0x6c, 0x4e, 0x09,
+	0xfa, 0x21, 0xac, 0xc9, 0xde, 0x89, 0x14, 0x93, 0xe3, 0xa4, 0xea, 0x04, 0x96, 0x9e, 0x81, 0x44,
+	0x37, 0x10, 0xe0, 0x5b, 0x62, 0x92, 0x8f, 0xcf, 0x4e, 0x49, 0xf9, 0x2b, 0x51, 0xae, 0x70, 0x31,
+	0xe5, 0x8a, 0x8b, 0x50, 0xae, 0x75, 0x04, 0x85, 0xc0, 0x68, 0xb4, 0x01, 0x19, 0x6e, 0x9a, 0xc2,
+	0x4d, 0x2b, 0x05, 0x05, 0xa4, 0x6f, 0x11, 0x57, 0xa0, 0x06, 0x64, 0xe7, 0xe6, 0x64, 0x46, 0x78,
+	0xe0, 0xca, 0x58, 0x34, 0xd0, 0x06, 0x94, 0x62, 0x58, 0xf1, 0x58, 0x95, 0x31, 0x44, 0xd9, 0x38,
+	0x4e, 0xeb, 0x18, 0x62, 0x2b, 0x41, 0xeb, 0x7f, 0xa9, 0xb0, 0x26, 0x4d, 0xdb, 0x36, 0xd9, 0xe0,
+	0xd9, 0x8d, 0x53, 0xfa, 0x3d, 0xc8, 0xfb, 0xd6, 0x58, 0x84, 0x36, 0xb5, 0xb6, 0x76, 0x3a, 0xa9,
+	0x83, 0x1e, 0xcb, 0x16, 0xbc, 0x9b, 0x50, 0x35, 0xe9, 0x29, 0xc5, 0x6e, 0xc5, 0xa4, 0x2f, 0xa3,
+	0xd2, 0x7d, 0xa1, 0x40, 0x23, 0x89, 0xe9, 0x8d, 0x85, 0xfa, 0x6b, 0x90, 0x17, 0x81, 0x0c, 0xd0,
+	0xbc, 0x23, 0x6d, 0x13, 0x61, 0x7e, 0x6a, 0xb1, 0x67, 0x62, 0xea, 0xa0, 0x9b, 0x6e, 0x43, 0x8d,
+	0x23, 0xcd, 0x7d, 0xe3, 0x70, 0x47, 0x59, 0x46, 0xb9, 0x44, 0x96, 0x51, 0xcf, 0xac, 0x4a, 0xb5,
+	0x78, 0x55, 0xaa, 0xff, 0x39, 0xaa, 0xb3, 0x38, 0x18, 0x2f, 0xa9, 0xd2, 0x7e, 0x3f, 0x4d, 0xb3,
+	0xf0, 0x65, 0x36, 0xe5, 0xfd, 0xcb, 0x22, 0xdb, 0x65, 0xdf, 0xcb, 0xf5, 0xdf, 0x44, 0xb5, 0x52,
+	0x02, 0xb8, 0x1b, 0xe3, 0xd2, 0xfd, 0x34, 0x97, 0x4e, 0xcb, 0x1b, 0x21, 0x8f, 0x7e, 0x01, 0x0d,
+	0x8e, 0x64, 0x94, 0xe1, 0xaf, 0x91, 0x4c, 0xe9, 0x02, 0x57, 0x3b, 0x51, 0xe0, 0xea, 0x7f, 0x57,
+	0xe1, 0x5e, 0x1c, 0x9e, 0x97, 0x59, 0xc4, 0x7f, 0x98, 0x26, 0xd7, 0x7a, 0x82, 0x5c, 0x29, 0x48,
+	0x56, 0x96, 0x61, 0xbf, 0x57, 0x60, 0xe3, 0x4c, 0x08, 0x57, 0x84, 0x66, 0x7f, 0x54, 0xa1, 0x71,
+	0xc0, 0x3c, 0x62, 0x4e, 0xaf, 0x74, 0x1b, 0x13, 0xb2, 0x52, 0xbd, 0xdc, 0x15, 0x8b, 0xb6, 0x78,
+	0x88, 0x52, 0x47, 0x49, 0xe6, 0x82, 0xa3, 0x24, 0xbb, 0xd0, 0xe5, 0x5c, 0x0c, 0xd7, 0xdc, 0xf9,
+	0xb8, 0xea, 0x47, 0x70, 0x3b, 0x05, 0x94, 0x0c, 0x61, 0x54, 0x0e, 0x28, 0x17, 0x95, 0x03, 0xe8,
+	0x0d, 0xff, 0x4d, 0x82, 0xb8, 0x86, 0x39, 0xb1, 0xe6, 0x62, 0x5b, 0x16, 0xfc, 0xb7, 0x06, 0xe2,
+	0x3e, 0xf0, 0x05, 0xfa, 0x97, 0x2a, 0xb4, 0x12, 0x8b, 0x5c, 0x25, 0x9b, 0x2f, 0x1c, 0x93, 0x78,
+	0xa6, 0xd0, 0xce, 0x3c, 0x76, 0x32, 0xe7, 0x5d, 0x86, 0x64, 0x17, 0x8c, 0xe3, 0xa5, 0xf7, 0x50,
+	0x1f, 0x5e, 0x3f, 0x15, 0x90, 0xcb, 0x63, 0xaf, 0xff, 0x4e, 0x85, 0x8d, 0xc4, 0x5c, 0x57, 0x4e,
+	0x69, 0xd7, 0x82, 0x70, 0x3a, 0x17, 0x67, 0x2e, 0xbc, 0x6c, 0xb8, 0x31, 0xb0, 0x1f, 0x43, 0xfb,
+	0x6c, 0x80, 0x96, 0x40, 0xfc, 0x4f, 0x2a, 0xbc, 0x91, 0x9e, 0xf0, 0x2a, 0xef, 0xfd, 0xd7, 0x82,
+	0x77, 0xf2, 0x65, 0x3e, 0xb3, 0xc4, 0xcb, 0xfc, 0x8d, 0xe1, 0xff, 0x08, 0xee, 0x9d, 0x05, 0xd7,
+	0x12, 0xe8, 0xff, 0x08, 0xca, 0xdb, 0x64, 0x64, 0xd9, 0xcb, 0x61, 0x9d, 0xf8, 0x92, 0xa2, 0x26,
+	0xbf, 0xa4, 0xe8, 0xdf, 0x86, 0x8a, 0x9c, 0x5a, 0xda, 0x15, 0xcb, 0xa3, 0xca, 0x05, 0x79, 0xf4,
+	0x0b, 0x05, 0x2a, 0x3b, 0xfc, 0x83, 0xcb, 0x8d, 0xd7, 0x11, 0x77, 0x20, 0x67, 0x32, 0x67, 0x6a,
+	0x0d, 0xe4, 0xa7, 0x20, 0xd9, 0xd2, 0xeb, 0x50, 0x0d, 0x2c, 0x10, 0xf6, 0xeb, 0x3f, 0x85, 0x1a,
+	0x76, 0x26, 0x93, 0x23, 0x73, 0x30, 0xbe, 0x69, 0xab, 0x74, 0x04, 0xf5, 0x68, 0x2d, 0xb9, 0xfe,
+	0x67, 0xf0, 0x1a, 0x26, 0xd4, 0x99, 0xcc, 0x49, 0xac, 0xe2, 0x58, 0xce, 0x12, 0x04, 0x99, 0x21,
+	0x93, 0x9f, 0x5d, 0x8a, 0x98, 0x3f, 0xeb, 0x7f, 0x53, 0xa0, 0xb1, 0x47, 0x28, 0x35, 0x47, 0x44,
+	0x10, 0x6c, 0xb9, 0xa9, 0xcf, 0x2b, 0x29, 0x1b, 0x90, 0x15, 0x07, 0xb3, 0xd8, 0x6f, 0xa2, 0x81,
+	0xb6, 0xa0, 0x18, 0x6e, 0xb6, 0x66, 0x46, 0x52, 0xf6, 0xe4, 0x5e, 0x2b, 0x04, 0x7b, 0xcd, 0xb7,
+	0x3e, 0x76, 0x7d, 0xc2, 0x9f, 0xf5, 0x5f, 0x2a, 0x70, 0x4b, 0x5a, 0xff, 0x60, 0x30, 0xbe, 0x7e,
+	0xd3, 0x83, 0x35, 0xb5, 0x68, 0x4d, 0x74, 0x0f, 0xb4, 0x20, 0x19, 0x97, 0x7a, 0x65, 0xb9, 0xcb,
+	0x3e, 0xf5, 0xaf, 0x23, 0xb0, 0xaf, 0xd0, 0xf7, 0xa0, 0xdc, 0x8f, 0x15, 0xa2, 0x68, 0x1d, 0xd4,
+	0xd0, 0x8c, 0x64, 0x77, 0xd5, 0x1a, 0xa6, 0x6f, 0x30, 0xd4, 0x13, 0x37, 0x18, 0x7f, 0x55, 0x60,
+	0x3d, 0x72, 0xf1, 0xca, 0x07, 0xd3, 0x65, 0xbd, 0xfd, 0x0e, 0xd4, 0xac, 0xa1, 0x71, 0xe2, 0x18,
+	0x2a, 0xf5, 0x1a, 0x01, 0x8b, 0xe3, 0xce, 0xe2, 0x8a, 0x15, 0x6b, 0x51, 0x7d, 0x1d, 0x5a, 0xa7,
+	0x91, 0x57, 0x52, 0xfb, 0xbf, 0x2a, 0xdc, 0x3a, 0x70, 0x27, 0x16, 0x93, 0x39, 0xea, 0xba, 0xfd,
+	0x59, 0xf8, 0x0e, 0xef, 0x4d, 0x28, 0x53, 0xdf, 0x0e, 0x79, 0x4d, 0x27, 0x0b, 0x9a, 0x12, 0x97,
+	0x89, 0x0b, 0x3a, 0x3f, 0x4e, 0x41, 0x97, 0x99, 0xcd, 0x38, 0x09, 0x35, 0x0c, 0xb2, 0xc7, 0xcc,
+	0x66, 0xe8, 0xeb, 0x70, 0xd7, 0x9e, 0x4d, 0x0d, 0xcf, 0x79, 0x4e, 0x0d, 0x97, 0x78, 0x06, 0x9f,
+	0xd9, 0x70, 0x4d, 0x8f, 0xf1, 0x14, 0xaf, 0xe1, 0x35, 0x7b, 0x36, 0xc5, 0xce, 0x73, 0xba, 0x4f,
+	0x3c, 0xbe, 0xf8, 0xbe, 0xe9, 0x31, 0xf4, 0x3d, 0x28, 0x9a, 0x93, 0x91, 0xe3, 0x59, 0xec, 0xd9,
+	0x54, 0xde, 0xcb, 0xe9, 0xd2, 0xcc, 0x13, 0xc8, 0x74, 0x1f, 0x04, 0x3d, 0x71, 0x34, 0x08, 0xbd,
+	0x07, 0x68, 0x46, 0x89, 0x21, 0x8c, 0x13, 0x8b, 0xce, 0x7b, 0xf2, 0x92, 0xae, 0x36, 0xa3, 0x24,
+	0x9a, 0xe6, 0xd3, 0x9e, 0xfe, 0x0f, 0x0d, 0x50, 0x7c, 0x5e, 0x99, 0xa3, 0xbf, 0x09, 0x39, 0x3e,
+	0x9e, 0x36, 0x15, 0x1e, 0xdb, 0x8d, 0x30, 0x43, 0x9d, 0xe8, 0xdb, 0xf5, 0xcd, 0xc6, 0xb2, 0x7b,
+	0xeb, 0x33, 0x28, 0x07, 0x3b, 0x95, 0xbb, 0x13, 0x8f, 0x86, 0x72, 0xee, 0xe9, 0xaa, 0x2e, 0x70,
+	0xba, 0xb6, 0xbe, 0x0b, 0x45, 0x5e, 0xd5, 0x5d, 0x38, 0x77, 0x54, 0x8b, 0xaa, 0xf1, 0x5a, 0xb4,
+	0xf5, 0x6f, 0x05, 0x32, 0x7c, 0xf0, 0xc2, 0xef, 0xc6, 0x7b, 0x50, 0x0d, 0xad, 0x14, 0xd1, 0x13,
+	0x49, 0xfb, 0xed, 0x73, 0x20, 0x89, 0x43, 0x80, 0xcb, 0xe3, 0x58, 0x0b, 0xed, 0x00, 0x88, 0xbf,
+	0x2e, 0xf0, 0xa9, 0x04, 0x0f, 0xbf, 0x7a, 0xce, 0x54, 0xa1, 0xbb, 0xb8, 0x48, 0x43, 0xcf, 0x11,
+	0x64, 0xa8, 0xf5, 0x73, 0x91, 0x25, 0x35, 0xcc, 0x9f, 0xf5, 0x0f, 0xe0, 0xf6, 0x47, 0x84, 0x1d,
+	0x78, 0xf3, 0x60, 0xbb, 0x05, 0xdb, 0xe7, 0x1c, 0x98, 0x74, 0x0c, 0x77, 0xd2, 0x83, 0x24, 0x03,
+	0xbe, 0x05, 0x65, 0xea, 0xcd, 0x8d, 0xc4, 0x48, 0xbf, 0x2a, 0x09, 0xc3, 0x13, 0x1f, 0x54, 0xa2,
+	0x51, 0x43, 0xff, 0x83, 0x0a, 0x6b, 0x4f, 0xdc, 0xa1, 0xc9, 0x56, 0xfd, 0xfc, 0x58, 0xb2, 0x54,
+	0x5b, 0x87, 0x22, 0xb3, 0xa6, 0x84, 0x32, 0x73, 0xea, 0xca, 0x9d, 0x1c, 0x09, 0x7c, 0x5e, 0x91,
+	0x39, 0xb1, 0x59, 0x33, 0x9f, 0xe0, 0xd5, 0xae, 0x2f, 0x3b, 0x74, 0xc6, 0xc4, 0xc6, 0x42, 0xaf,
+	0x8f, 0xa1, 0x91, 0x44, 0x49, 0x02, 0xdf, 0x09, 0x26, 0x48, 0x56, 0x6d, 0xb2, 0xd8, 0xf3, 0x35,
+	0x72, 0x06, 0xf4, 0x0e, 0xd4, 0x3d, 0x42, 0x67, 0x53, 0x62, 0x44, 0xf6, 0x88, 0x3f, 0x50, 0xd4,
+	0x84, 0xfc, 0x30, 0x10, 0xbf, 0xfb, 0x10, 0x6a, 0xa9, 0x3f, 0x8e, 0xa0, 0x1a, 0x94, 0x9e, 0x3c,
+	0x3e, 0xd8, 0xdf, 0xdd, 0xe9, 0x7f, 0xbf, 0xbf, 0xfb, 0xb0, 0xfe, 0x15, 0x04, 0x90, 0x3b, 0xe8,
+	0x3f, 0xfe, 0xe8, 0xd1, 0x6e, 0x5d, 0x41, 0x45, 0xc8, 0xee, 0x3d, 0x79, 0x74, 0xd8, 0xaf, 0xab,
+	0xfe, 0xe3, 0xe1, 0xd3, 0x4f, 0xf6, 0x77, 0xea, 0xda, 0xf6, 0x87, 0x50, 0xb3, 0x9c, 0xee, 0xdc,
+	0x62, 0x84, 0x52, 0xf1, 0xe7, 0x9d, 0x1f, 0xbf, 0x25, 0x5b, 0x96, 0xb3, 0x25, 0x9e, 0xb6, 0x46,
+	0xce, 0xd6, 0x9c, 0x6d, 0x71, 0xed, 0x96, 0xa0, 0xf5, 0x51, 0x8e, 0xb7, 0x3e, 0xf8, 0xff, 0x00,
+	0x0a, 0xbe, 0x42, 0x25, 0x2a, 0x24, 0x00, 0x00,
 }
@@ -31,7 +31,7 @@ func (m *ExecuteVtworkerCommandRequest) Reset()         { *m = ExecuteVtworkerCo
 func (m *ExecuteVtworkerCommandRequest) String() string { return proto.CompactTextString(m) }
 func (*ExecuteVtworkerCommandRequest) ProtoMessage()    {}
 func (*ExecuteVtworkerCommandRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_vtworkerdata_9c94bc714251542b, []int{0}
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{0}
 }
 func (m *ExecuteVtworkerCommandRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecuteVtworkerCommandRequest.Unmarshal(m, b)
@@ -70,7 +70,7 @@ func (m *ExecuteVtworkerCommandResponse) Reset()         { *m = ExecuteVtworkerC
 func (m *ExecuteVtworkerCommandResponse) String() string { return proto.CompactTextString(m) }
 func (*ExecuteVtworkerCommandResponse) ProtoMessage()    {}
 func (*ExecuteVtworkerCommandResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_vtworkerdata_9c94bc714251542b, []int{1}
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{1}
 }
 func (m *ExecuteVtworkerCommandResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecuteVtworkerCommandResponse.Unmarshal(m, b)
@@ -97,24 +97,362 @@ func (m *ExecuteVtworkerCommandResponse) GetEvent() *logutil.Event {
 	return nil
 }
 
+// GetStatusRequest is the payload for GetStatus.
+type GetStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{2}
+}
+func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
+}
+func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusRequest.Merge(dst, src)
+}
+func (m *GetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusRequest.Size(m)
+}
+func (m *GetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
+
+// GetStatusResponse is returned by GetStatus.
+type GetStatusResponse struct {
+	// worker is the type of the current worker e.g. "SplitDiffWorker".
+	// It is empty if no worker ran since the last reset.
+	Worker string `protobuf:"bytes,1,opt,name=worker" json:"worker,omitempty"`
+	// state is the state of the current worker e.g. "diff".
+	State string `protobuf:"bytes,2,opt,name=state" json:"state,omitempty"`
+	// status is the human-readable status of the current worker.
+	Status string `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	// done is true if the current worker has finished.
+	Done bool `protobuf:"varint,4,opt,name=done" json:"done,omitempty"`
+	// error is set if the current worker has finished with an error.
+	Error                string   `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusResponse) Reset()         { *m = GetStatusResponse{} }
+func (m *GetStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatusResponse) ProtoMessage()    {}
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{3}
+}
+func (m *GetStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusResponse.Unmarshal(m, b)
+}
+func (m *GetStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusResponse.Merge(dst, src)
+}
+func (m *GetStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStatusResponse.Size(m)
+}
+func (m *GetStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusResponse proto.InternalMessageInfo
+
+func (m *GetStatusResponse) GetWorker() string {
+	if m != nil {
+		return m.Worker
+	}
+	return ""
+}
+
+func (m *GetStatusResponse) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *GetStatusResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *GetStatusResponse) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+func (m *GetStatusResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// CancelRequest is the payload for Cancel.
+type CancelRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelRequest) Reset()         { *m = CancelRequest{} }
+func (m *CancelRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelRequest) ProtoMessage()    {}
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{4}
+}
+func (m *CancelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelRequest.Unmarshal(m, b)
+}
+func (m *CancelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelRequest.Marshal(b, m, deterministic)
+}
+func (dst *CancelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelRequest.Merge(dst, src)
+}
+func (m *CancelRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelRequest.Size(m)
+}
+func (m *CancelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelRequest proto.InternalMessageInfo
+
+// CancelResponse is returned by Cancel.
+type CancelResponse struct {
+	// canceled is true if a running worker was canceled.
+	Canceled             bool     `protobuf:"varint,1,opt,name=canceled" json:"canceled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelResponse) Reset()         { *m = CancelResponse{} }
+func (m *CancelResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelResponse) ProtoMessage()    {}
+func (*CancelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{5}
+}
+func (m *CancelResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelResponse.Unmarshal(m, b)
+}
+func (m *CancelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelResponse.Marshal(b, m, deterministic)
+}
+func (dst *CancelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelResponse.Merge(dst, src)
+}
+func (m *CancelResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelResponse.Size(m)
+}
+func (m *CancelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelResponse proto.InternalMessageInfo
+
+func (m *CancelResponse) GetCanceled() bool {
+	if m != nil {
+		return m.Canceled
+	}
+	return false
+}
+
+// PauseRequest is the payload for Pause.
+type PauseRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseRequest) Reset()         { *m = PauseRequest{} }
+func (m *PauseRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseRequest) ProtoMessage()    {}
+func (*PauseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{6}
+}
+func (m *PauseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseRequest.Unmarshal(m, b)
+}
+func (m *PauseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseRequest.Marshal(b, m, deterministic)
+}
+func (dst *PauseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseRequest.Merge(dst, src)
+}
+func (m *PauseRequest) XXX_Size() int {
+	return xxx_messageInfo_PauseRequest.Size(m)
+}
+func (m *PauseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseRequest proto.InternalMessageInfo
+
+// PauseResponse is returned by Pause.
+type PauseResponse struct {
+	// paused is true if a running worker was paused.
+	Paused               bool     `protobuf:"varint,1,opt,name=paused" json:"paused,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseResponse) Reset()         { *m = PauseResponse{} }
+func (m *PauseResponse) String() string { return proto.CompactTextString(m) }
+func (*PauseResponse) ProtoMessage()    {}
+func (*PauseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{7}
+}
+func (m *PauseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseResponse.Unmarshal(m, b)
+}
+func (m *PauseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseResponse.Marshal(b, m, deterministic)
+}
+func (dst *PauseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseResponse.Merge(dst, src)
+}
+func (m *PauseResponse) XXX_Size() int {
+	return xxx_messageInfo_PauseResponse.Size(m)
+}
+func (m *PauseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseResponse proto.InternalMessageInfo
+
+func (m *PauseResponse) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+// ResumeRequest is the payload for Resume.
+type ResumeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeRequest) Reset()         { *m = ResumeRequest{} }
+func (m *ResumeRequest) String() string { return proto.CompactTextString(m) }
+func (*ResumeRequest) ProtoMessage()    {}
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{8}
+}
+func (m *ResumeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeRequest.Unmarshal(m, b)
+}
+func (m *ResumeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeRequest.Marshal(b, m, deterministic)
+}
+func (dst *ResumeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeRequest.Merge(dst, src)
+}
+func (m *ResumeRequest) XXX_Size() int {
+	return xxx_messageInfo_ResumeRequest.Size(m)
+}
+func (m *ResumeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeRequest proto.InternalMessageInfo
+
+// ResumeResponse is returned by Resume.
+type ResumeResponse struct {
+	// resumed is true if a paused worker was resumed.
+	Resumed              bool     `protobuf:"varint,1,opt,name=resumed" json:"resumed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeResponse) Reset()         { *m = ResumeResponse{} }
+func (m *ResumeResponse) String() string { return proto.CompactTextString(m) }
+func (*ResumeResponse) ProtoMessage()    {}
+func (*ResumeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_vtworkerdata_131d04e58c8f5c98, []int{9}
+}
+func (m *ResumeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeResponse.Unmarshal(m, b)
+}
+func (m *ResumeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeResponse.Marshal(b, m, deterministic)
+}
+func (dst *ResumeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeResponse.Merge(dst, src)
+}
+func (m *ResumeResponse) XXX_Size() int {
+	return xxx_messageInfo_ResumeResponse.Size(m)
+}
+func (m *ResumeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeResponse proto.InternalMessageInfo
+
+func (m *ResumeResponse) GetResumed() bool {
+	if m != nil {
+		return m.Resumed
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*ExecuteVtworkerCommandRequest)(nil), "vtworkerdata.ExecuteVtworkerCommandRequest")
 	proto.RegisterType((*ExecuteVtworkerCommandResponse)(nil), "vtworkerdata.ExecuteVtworkerCommandResponse")
+	proto.RegisterType((*GetStatusRequest)(nil), "vtworkerdata.GetStatusRequest")
+	proto.RegisterType((*GetStatusResponse)(nil), "vtworkerdata.GetStatusResponse")
+	proto.RegisterType((*CancelRequest)(nil), "vtworkerdata.CancelRequest")
+	proto.RegisterType((*CancelResponse)(nil), "vtworkerdata.CancelResponse")
+	proto.RegisterType((*PauseRequest)(nil), "vtworkerdata.PauseRequest")
+	proto.RegisterType((*PauseResponse)(nil), "vtworkerdata.PauseResponse")
+	proto.RegisterType((*ResumeRequest)(nil), "vtworkerdata.ResumeRequest")
+	proto.RegisterType((*ResumeResponse)(nil), "vtworkerdata.ResumeResponse")
 }
 
-func init() { proto.RegisterFile("vtworkerdata.proto", fileDescriptor_vtworkerdata_9c94bc714251542b) }
-
-var fileDescriptor_vtworkerdata_9c94bc714251542b = []byte{
-	// 175 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2a, 0x2b, 0x29, 0xcf,
-	0x2f, 0xca, 0x4e, 0x2d, 0x4a, 0x49, 0x2c, 0x49, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2,
-	0x41, 0x16, 0x93, 0xe2, 0xcd, 0xc9, 0x4f, 0x2f, 0x2d, 0xc9, 0xcc, 0x81, 0x48, 0x2a, 0x19, 0x73,
-	0xc9, 0xba, 0x56, 0xa4, 0x26, 0x97, 0x96, 0xa4, 0x86, 0x41, 0x55, 0x39, 0xe7, 0xe7, 0xe6, 0x26,
-	0xe6, 0xa5, 0x04, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97, 0x08, 0x09, 0x71, 0xb1, 0x24, 0x16, 0xa5,
-	0x17, 0x4b, 0x30, 0x2a, 0x30, 0x6b, 0x70, 0x06, 0x81, 0xd9, 0x4a, 0x6e, 0x5c, 0x72, 0xb8, 0x34,
-	0x15, 0x17, 0xe4, 0xe7, 0x15, 0xa7, 0x0a, 0xa9, 0x70, 0xb1, 0xa6, 0x96, 0xa5, 0xe6, 0x95, 0x48,
-	0x30, 0x2a, 0x30, 0x6a, 0x70, 0x1b, 0xf1, 0xe9, 0xc1, 0x6c, 0x75, 0x05, 0x89, 0x06, 0x41, 0x24,
-	0x9d, 0xb4, 0xa3, 0x34, 0xcb, 0x32, 0x4b, 0x52, 0x8b, 0x8b, 0xf5, 0x32, 0xf3, 0xf5, 0x21, 0x2c,
-	0xfd, 0xf4, 0x7c, 0xfd, 0xb2, 0x12, 0x7d, 0xb0, 0xe3, 0xf4, 0x91, 0x1d, 0x9e, 0xc4, 0x06, 0x16,
-	0x33, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0xcf, 0x82, 0xc8, 0x11, 0xe3, 0x00, 0x00, 0x00,
+func init() { proto.RegisterFile("vtworkerdata.proto", fileDescriptor_vtworkerdata_131d04e58c8f5c98) }
+
+var fileDescriptor_vtworkerdata_131d04e58c8f5c98 = []byte{
+	// 316 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x75, 0x92, 0x5d, 0x4b, 0xc3, 0x30,
+	0x14, 0x86, 0xa9, 0x5b, 0x67, 0x77, 0x5c, 0xab, 0x06, 0x91, 0x30, 0x50, 0x24, 0x08, 0xce, 0x0f,
+	0x5a, 0x70, 0xff, 0xc0, 0x31, 0xbd, 0x95, 0x0a, 0x5e, 0x78, 0x17, 0xd7, 0xc3, 0x28, 0x6e, 0xcd,
+	0x4c, 0xd2, 0xea, 0xbd, 0x7f, 0xdc, 0x36, 0x1f, 0x73, 0x37, 0xde, 0x9d, 0xf7, 0xc9, 0xfb, 0x9e,
+	0x73, 0x42, 0x02, 0xa4, 0xd1, 0x5f, 0x42, 0x7e, 0xa0, 0x2c, 0xb8, 0xe6, 0xe9, 0x46, 0x0a, 0x2d,
+	0xc8, 0x68, 0x97, 0x8d, 0xe3, 0x95, 0x58, 0xd6, 0xba, 0x5c, 0xd9, 0x43, 0x36, 0x85, 0xb3, 0xf9,
+	0x37, 0x2e, 0x6a, 0x8d, 0xaf, 0xce, 0x35, 0x13, 0xeb, 0x35, 0xaf, 0x8a, 0x1c, 0x3f, 0x6b, 0x54,
+	0x9a, 0x10, 0xe8, 0x73, 0xb9, 0x54, 0x34, 0xb8, 0xe8, 0x4d, 0x86, 0xb9, 0xa9, 0xd9, 0x23, 0x9c,
+	0xff, 0x17, 0x52, 0x1b, 0x51, 0x29, 0x24, 0x97, 0x10, 0x62, 0x83, 0x95, 0x6e, 0x63, 0xc1, 0xe4,
+	0xe0, 0x3e, 0x49, 0xfd, 0xd4, 0x79, 0x47, 0x73, 0x7b, 0xc8, 0x08, 0x1c, 0x3d, 0xa1, 0x7e, 0xd1,
+	0x5c, 0xd7, 0xca, 0xcd, 0x63, 0x3f, 0x01, 0x1c, 0xef, 0x40, 0xd7, 0xef, 0x14, 0x06, 0x76, 0x90,
+	0x69, 0x38, 0xcc, 0x9d, 0x22, 0x27, 0x10, 0xaa, 0xd6, 0x89, 0x74, 0xcf, 0x60, 0x2b, 0x3a, 0xb7,
+	0x32, 0x79, 0xda, 0xb3, 0x6e, 0xab, 0xba, 0xbb, 0x14, 0xa2, 0x42, 0xda, 0x6f, 0x69, 0x94, 0x9b,
+	0xba, 0xeb, 0x80, 0x52, 0x0a, 0x49, 0x43, 0xdb, 0xc1, 0x08, 0x76, 0x08, 0xf1, 0x8c, 0x57, 0x0b,
+	0x5c, 0xf9, 0xb5, 0xee, 0x20, 0xf1, 0xc0, 0xad, 0x34, 0x86, 0x68, 0x61, 0x08, 0x16, 0x66, 0xa9,
+	0x28, 0xdf, 0x6a, 0x96, 0xc0, 0xe8, 0x99, 0xd7, 0x0a, 0x7d, 0xfa, 0x0a, 0x62, 0xa7, 0xff, 0xee,
+	0xb3, 0xe9, 0x80, 0x8f, 0x3a, 0xd5, 0xcd, 0x6d, 0x3d, 0xf5, 0x7a, 0x9b, 0xbc, 0x81, 0xc4, 0x03,
+	0x17, 0xa5, 0xb0, 0x2f, 0x0d, 0xf1, 0x59, 0x2f, 0x1f, 0x6e, 0xdf, 0xae, 0x9b, 0x52, 0xa3, 0x52,
+	0x69, 0x29, 0x32, 0x5b, 0x65, 0xcb, 0xb6, 0xd2, 0x99, 0x79, 0xeb, 0x6c, 0xf7, 0x1f, 0xbc, 0x0f,
+	0x0c, 0x9b, 0xfe, 0x02, 0x9e, 0xdc, 0xf5, 0xa6, 0x32, 0x02, 0x00, 0x00,
 }
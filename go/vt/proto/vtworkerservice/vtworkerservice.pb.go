@@ -38,6 +38,15 @@ type VtworkerClient interface {
 	// ExecuteVtworkerCommand allows to run a vtworker command by specifying the
 	// same arguments as on the command line.
 	ExecuteVtworkerCommand(ctx context.Context, in *vtworkerdata.ExecuteVtworkerCommandRequest, opts ...grpc.CallOption) (Vtworker_ExecuteVtworkerCommandClient, error)
+	// GetStatus returns the status of the current or last vtworker command.
+	GetStatus(ctx context.Context, in *vtworkerdata.GetStatusRequest, opts ...grpc.CallOption) (*vtworkerdata.GetStatusResponse, error)
+	// Cancel cancels the currently running vtworker command.
+	Cancel(ctx context.Context, in *vtworkerdata.CancelRequest, opts ...grpc.CallOption) (*vtworkerdata.CancelResponse, error)
+	// Pause pauses the currently running vtworker command. The command stops
+	// issuing new queries but keeps its state until it is resumed.
+	Pause(ctx context.Context, in *vtworkerdata.PauseRequest, opts ...grpc.CallOption) (*vtworkerdata.PauseResponse, error)
+	// Resume resumes the currently paused vtworker command.
+	Resume(ctx context.Context, in *vtworkerdata.ResumeRequest, opts ...grpc.CallOption) (*vtworkerdata.ResumeResponse, error)
 }
 
 type vtworkerClient struct {
@@ -80,12 +89,57 @@ func (x *vtworkerExecuteVtworkerCommandClient) Recv() (*vtworkerdata.ExecuteVtwo
 	return m, nil
 }
 
+func (c *vtworkerClient) GetStatus(ctx context.Context, in *vtworkerdata.GetStatusRequest, opts ...grpc.CallOption) (*vtworkerdata.GetStatusResponse, error) {
+	out := new(vtworkerdata.GetStatusResponse)
+	err := grpc.Invoke(ctx, "/vtworkerservice.Vtworker/GetStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vtworkerClient) Cancel(ctx context.Context, in *vtworkerdata.CancelRequest, opts ...grpc.CallOption) (*vtworkerdata.CancelResponse, error) {
+	out := new(vtworkerdata.CancelResponse)
+	err := grpc.Invoke(ctx, "/vtworkerservice.Vtworker/Cancel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vtworkerClient) Pause(ctx context.Context, in *vtworkerdata.PauseRequest, opts ...grpc.CallOption) (*vtworkerdata.PauseResponse, error) {
+	out := new(vtworkerdata.PauseResponse)
+	err := grpc.Invoke(ctx, "/vtworkerservice.Vtworker/Pause", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vtworkerClient) Resume(ctx context.Context, in *vtworkerdata.ResumeRequest, opts ...grpc.CallOption) (*vtworkerdata.ResumeResponse, error) {
+	out := new(vtworkerdata.ResumeResponse)
+	err := grpc.Invoke(ctx, "/vtworkerservice.Vtworker/Resume", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Vtworker service
 
 type VtworkerServer interface {
 	// ExecuteVtworkerCommand allows to run a vtworker command by specifying the
 	// same arguments as on the command line.
 	ExecuteVtworkerCommand(*vtworkerdata.ExecuteVtworkerCommandRequest, Vtworker_ExecuteVtworkerCommandServer) error
+	// GetStatus returns the status of the current or last vtworker command.
+	GetStatus(context.Context, *vtworkerdata.GetStatusRequest) (*vtworkerdata.GetStatusResponse, error)
+	// Cancel cancels the currently running vtworker command.
+	Cancel(context.Context, *vtworkerdata.CancelRequest) (*vtworkerdata.CancelResponse, error)
+	// Pause pauses the currently running vtworker command. The command stops
+	// issuing new queries but keeps its state until it is resumed.
+	Pause(context.Context, *vtworkerdata.PauseRequest) (*vtworkerdata.PauseResponse, error)
+	// Resume resumes the currently paused vtworker command.
+	Resume(context.Context, *vtworkerdata.ResumeRequest) (*vtworkerdata.ResumeResponse, error)
 }
 
 func RegisterVtworkerServer(s *grpc.Server, srv VtworkerServer) {
@@ -113,10 +167,99 @@ func (x *vtworkerExecuteVtworkerCommandServer) Send(m *vtworkerdata.ExecuteVtwor
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Vtworker_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(vtworkerdata.GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VtworkerServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vtworkerservice.Vtworker/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VtworkerServer).GetStatus(ctx, req.(*vtworkerdata.GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vtworker_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(vtworkerdata.CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VtworkerServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vtworkerservice.Vtworker/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VtworkerServer).Cancel(ctx, req.(*vtworkerdata.CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vtworker_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(vtworkerdata.PauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VtworkerServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vtworkerservice.Vtworker/Pause",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VtworkerServer).Pause(ctx, req.(*vtworkerdata.PauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Vtworker_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(vtworkerdata.ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VtworkerServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vtworkerservice.Vtworker/Resume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VtworkerServer).Resume(ctx, req.(*vtworkerdata.ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Vtworker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "vtworkerservice.Vtworker",
 	HandlerType: (*VtworkerServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _Vtworker_GetStatus_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Vtworker_Cancel_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _Vtworker_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _Vtworker_Resume_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "ExecuteVtworkerCommand",
@@ -128,19 +271,24 @@ var _Vtworker_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("vtworkerservice.proto", fileDescriptor_vtworkerservice_0d36cb3c7cfddd2b)
-}
-
-var fileDescriptor_vtworkerservice_0d36cb3c7cfddd2b = []byte{
-	// 151 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2d, 0x2b, 0x29, 0xcf,
-	0x2f, 0xca, 0x4e, 0x2d, 0x2a, 0x4e, 0x2d, 0x2a, 0xcb, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0xe2, 0x47, 0x13, 0x96, 0x12, 0x82, 0x09, 0xa4, 0x24, 0x96, 0x24, 0x42, 0x14, 0x19,
-	0x35, 0x33, 0x72, 0x71, 0x84, 0x41, 0x85, 0x85, 0xca, 0xb9, 0xc4, 0x5c, 0x2b, 0x52, 0x93, 0x4b,
-	0x4b, 0x52, 0x61, 0x42, 0xce, 0xf9, 0xb9, 0xb9, 0x89, 0x79, 0x29, 0x42, 0xda, 0x7a, 0x28, 0x7a,
-	0xb1, 0xab, 0x0a, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d, 0x2e, 0x91, 0xd2, 0x21, 0x4e, 0x71, 0x71, 0x41,
-	0x7e, 0x5e, 0x71, 0xaa, 0x12, 0x83, 0x01, 0xa3, 0x93, 0x5e, 0x94, 0x4e, 0x59, 0x66, 0x49, 0x6a,
-	0x71, 0xb1, 0x5e, 0x66, 0xbe, 0x3e, 0x84, 0xa5, 0x9f, 0x9e, 0xaf, 0x5f, 0x56, 0xa2, 0x0f, 0x76,
-	0xa5, 0x3e, 0x9a, 0x4f, 0x92, 0xd8, 0xc0, 0xc2, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1c,
-	0x01, 0x4d, 0x17, 0xfa, 0x00, 0x00, 0x00,
+	proto.RegisterFile("vtworkerservice.proto", fileDescriptor_vtworkerservice_1bcb245f87b4e848)
+}
+
+var fileDescriptor_vtworkerservice_1bcb245f87b4e848 = []byte{
+	// 228 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x91, 0x41, 0x0b, 0x82, 0x40,
+	0x10, 0x85, 0x8b, 0x28, 0x6a, 0x2f, 0xc1, 0x40, 0x1d, 0x34, 0x0a, 0xba, 0x16, 0x1a, 0xf5, 0x0f,
+	0x8a, 0xe8, 0x16, 0x51, 0xd0, 0xa1, 0xdb, 0xa6, 0x43, 0x48, 0xe9, 0x9a, 0x3b, 0x6b, 0xfd, 0xa1,
+	0xfe, 0x67, 0xa2, 0xad, 0xa4, 0x18, 0x74, 0x9b, 0xf9, 0xde, 0x9b, 0xc7, 0x83, 0x61, 0xbd, 0x98,
+	0x1e, 0x22, 0xba, 0x62, 0x24, 0x31, 0x8a, 0x3d, 0x07, 0xad, 0x30, 0x12, 0x24, 0xa0, 0x5b, 0xc2,
+	0x06, 0x68, 0xe0, 0x72, 0xe2, 0x99, 0x69, 0xfe, 0x6a, 0xb0, 0xf6, 0xf1, 0x83, 0xe1, 0xc1, 0xfa,
+	0xeb, 0x27, 0x3a, 0x8a, 0x50, 0xa3, 0x95, 0xf0, 0x7d, 0x1e, 0xb8, 0x30, 0xb1, 0x0a, 0xb7, 0xd5,
+	0xae, 0x3d, 0xde, 0x15, 0x4a, 0x32, 0xa6, 0xff, 0x99, 0x65, 0x28, 0x02, 0x89, 0xe3, 0xda, 0xac,
+	0x0e, 0x5b, 0xd6, 0xd9, 0x20, 0x1d, 0x88, 0x93, 0x92, 0x30, 0x2c, 0x9e, 0xe7, 0x82, 0x8e, 0x1f,
+	0xfd, 0xd4, 0x75, 0x22, 0xac, 0x59, 0x6b, 0xc5, 0x03, 0x07, 0x6f, 0x60, 0x16, 0xcd, 0x19, 0xd5,
+	0x49, 0x83, 0x6a, 0x31, 0x8f, 0x59, 0xb2, 0xe6, 0x8e, 0x2b, 0x89, 0x60, 0x14, 0x8d, 0x29, 0xd4,
+	0x21, 0x66, 0xa5, 0xf6, 0x5d, 0x25, 0xd9, 0x94, 0x8f, 0xe5, 0x2a, 0x19, 0xfd, 0x51, 0x45, 0x8b,
+	0x3a, 0x66, 0x69, 0x9d, 0xa6, 0xb1, 0x47, 0x28, 0xa5, 0xe5, 0x09, 0x3b, 0x9b, 0xec, 0x4b, 0x32,
+	0x91, 0x9d, 0xfe, 0xd1, 0x2e, 0xfd, 0xfa, 0xdc, 0x4a, 0xf1, 0xe2, 0x0d, 0x82, 0x0c, 0x84, 0xf3,
+	0x1c, 0x02, 0x00, 0x00,
 }
@@ -20,7 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -245,6 +247,15 @@ type fakeTabletManagerClient struct {
 	EnableExecuteFetchAsDbaError bool
 	preflightSchemas             map[string]*tabletmanagerdatapb.SchemaChangeResult
 	schemaDefinitions            map[string]*tabletmanagerdatapb.SchemaDefinition
+
+	// ExecuteFetchAsDbaDelay slows down each ExecuteFetchAsDba call.
+	ExecuteFetchAsDbaDelay time.Duration
+	// mu protects the fields below.
+	mu sync.Mutex
+	// runningExecuteFetchAsDba is the number of running ExecuteFetchAsDba
+	// calls, maxRunningExecuteFetchAsDba is its maximum.
+	runningExecuteFetchAsDba    int
+	maxRunningExecuteFetchAsDba int
 }
 
 func (client *fakeTabletManagerClient) AddSchemaChange(sql string, schemaResult *tabletmanagerdatapb.SchemaChangeResult) {
@@ -281,6 +292,18 @@ func (client *fakeTabletManagerClient) ExecuteFetchAsDba(ctx context.Context, ta
 	if client.EnableExecuteFetchAsDbaError {
 		return nil, fmt.Errorf("ExecuteFetchAsDba occur an unknown error")
 	}
+	client.mu.Lock()
+	client.runningExecuteFetchAsDba++
+	if client.runningExecuteFetchAsDba > client.maxRunningExecuteFetchAsDba {
+		client.maxRunningExecuteFetchAsDba = client.runningExecuteFetchAsDba
+	}
+	client.mu.Unlock()
+	defer func() {
+		client.mu.Lock()
+		client.runningExecuteFetchAsDba--
+		client.mu.Unlock()
+	}()
+	time.Sleep(client.ExecuteFetchAsDbaDelay)
 	return client.TabletManagerClient.ExecuteFetchAsDba(ctx, tablet, usePool, query, maxRows, disableBinlogs, reloadSchema)
 }
 
@@ -1296,7 +1296,8 @@ func (shardSwap *shardSchemaSwap) reparentFromMaster(masterTablet *topodatapb.Ta
 			shardSwap.shardName,
 			nil,                /* masterElectTabletAlias */
 			masterTablet.Alias, /* avoidMasterAlias */
-			*reparentTimeout)
+			*reparentTimeout,
+			nil /* policy */)
 		if err != nil {
 			return err
 		}
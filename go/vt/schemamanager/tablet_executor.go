@@ -41,6 +41,8 @@ type TabletExecutor struct {
 	allowBigSchemaChange bool
 	keyspace             string
 	waitSlaveTimeout     time.Duration
+	maxConcurrentShards  int
+	shardDelay           time.Duration
 }
 
 // NewTabletExecutor creates a new TabletExecutor instance
@@ -65,6 +67,19 @@ func (exec *TabletExecutor) DisallowBigSchemaChange() {
 	exec.allowBigSchemaChange = false
 }
 
+// SetMaxConcurrentShards limits on how many shards a schema change is
+// applied at the same time. Zero or less means no limit.
+func (exec *TabletExecutor) SetMaxConcurrentShards(maxConcurrentShards int) {
+	exec.maxConcurrentShards = maxConcurrentShards
+}
+
+// SetShardDelay sets the time to wait between starting the schema change on
+// one shard and starting it on the next shard. This spreads out the
+// replication stalls caused by the schema change.
+func (exec *TabletExecutor) SetShardDelay(shardDelay time.Duration) {
+	exec.shardDelay = shardDelay
+}
+
 // Open opens a connection to the master for every shard.
 func (exec *TabletExecutor) Open(ctx context.Context, keyspace string) error {
 	if !exec.isClosed {
@@ -247,13 +262,38 @@ func (exec *TabletExecutor) Execute(ctx context.Context, sqls []string) *Execute
 func (exec *TabletExecutor) executeOnAllTablets(ctx context.Context, execResult *ExecuteResult, sql string) {
 	var wg sync.WaitGroup
 	numOfMasterTablets := len(exec.tablets)
-	wg.Add(numOfMasterTablets)
 	errChan := make(chan ShardWithError, numOfMasterTablets)
 	successChan := make(chan ShardResult, numOfMasterTablets)
-	for _, tablet := range exec.tablets {
+	maxConcurrentShards := exec.maxConcurrentShards
+	if maxConcurrentShards <= 0 || maxConcurrentShards > numOfMasterTablets {
+		maxConcurrentShards = numOfMasterTablets
+	}
+	shardSema := sync2.NewSemaphore(maxConcurrentShards, 0)
+	finished := sync2.NewAtomicInt64(0)
+	for i, tablet := range exec.tablets {
+		shardSema.Acquire()
+		if i > 0 && exec.shardDelay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(exec.shardDelay):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			shardSema.Release()
+			// Don't start the schema change on the remaining shards.
+			for _, tablet := range exec.tablets[i:] {
+				errChan <- ShardWithError{Shard: tablet.Shard, Err: fmt.Sprintf("schema change not started: %v", err)}
+			}
+			break
+		}
+
+		exec.wr.Logger().Infof("Applying schema change on shard %v (%v/%v)", tablet.Shard, i+1, numOfMasterTablets)
+		wg.Add(1)
 		go func(tablet *topodatapb.Tablet) {
 			defer wg.Done()
+			defer shardSema.Release()
 			exec.executeOneTablet(ctx, tablet, sql, errChan, successChan)
+			exec.wr.Logger().Infof("Schema change on shard %v finished, %v of %v shards done", tablet.Shard, finished.Add(1), numOfMasterTablets)
 		}(tablet)
 	}
 	wg.Wait()
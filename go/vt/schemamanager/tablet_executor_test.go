@@ -187,3 +187,55 @@ func TestTabletExecutorExecute_PreflightWithoutChangesIsAnError(t *testing.T) {
 		t.Fatalf("execute should fail, ddl does not introduce any table schema change")
 	}
 }
+
+func TestTabletExecutorExecuteMaxConcurrentShards(t *testing.T) {
+	sql := "create table test_table (pk int)"
+	fakeTmc := newFakeTabletManagerClient()
+	fakeTmc.AddSchemaChange(sql, &tabletmanagerdatapb.SchemaChangeResult{
+		BeforeSchema: &tabletmanagerdatapb.SchemaDefinition{},
+		AfterSchema: &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:   "test_table",
+					Schema: sql,
+					Type:   tmutils.TableBaseTable,
+				},
+			},
+		},
+	})
+	fakeTmc.ExecuteFetchAsDbaDelay = 10 * time.Millisecond
+	wr := wrangler.New(logutil.NewConsoleLogger(), newFakeTopo(t), fakeTmc)
+	executor := NewTabletExecutor(wr, testWaitSlaveTimeout)
+	executor.SetMaxConcurrentShards(2)
+	executor.SetShardDelay(20 * time.Millisecond)
+
+	ctx := context.Background()
+	if err := executor.Open(ctx, "test_keyspace"); err != nil {
+		t.Fatalf("executor.Open failed: %v", err)
+	}
+	defer executor.Close()
+
+	start := time.Now()
+	result := executor.Execute(ctx, []string{sql})
+	if result.ExecutorErr != "" || len(result.FailedShards) != 0 {
+		t.Fatalf("Execute failed: %v %v", result.ExecutorErr, result.FailedShards)
+	}
+	if len(result.SuccessShards) != 3 {
+		t.Errorf("Execute succeeded on %v shards, want 3", len(result.SuccessShards))
+	}
+	// The second and the third shard are started after the delay.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Execute took %v, want at least 40ms", elapsed)
+	}
+
+	// Without the delay, the limit applies.
+	executor.SetShardDelay(0)
+	fakeTmc.ExecuteFetchAsDbaDelay = 50 * time.Millisecond
+	result = executor.Execute(ctx, []string{sql})
+	if result.ExecutorErr != "" || len(result.FailedShards) != 0 {
+		t.Fatalf("Execute failed: %v %v", result.ExecutorErr, result.FailedShards)
+	}
+	if got := fakeTmc.maxRunningExecuteFetchAsDba; got != 2 {
+		t.Errorf("the change ran on %v shards at the same time, want 2", got)
+	}
+}
@@ -87,6 +87,14 @@ var (
 	// This is the minimum amount of time a client should wait before sending a keepalive ping.
 	GRPCKeepAliveEnforcementPolicyMinTime = flag.Duration("grpc_server_keepalive_enforcement_policy_min_time", 5*time.Minute, "grpc server minimum keepalive time")
 
+	// GRPCKeepAliveTime is the idle time after which the server pings the client.
+	// This keeps long streaming queries that don't return rows for a while alive
+	// through proxies, and detects dead clients.
+	GRPCKeepAliveTime = flag.Duration("grpc_server_keepalive_time", 0, "After a duration of this time if the server doesn't see any activity it pings the client to see if the transport is still alive. 0 means the grpc default (2h).")
+
+	// GRPCKeepAliveTimeout is how long the server waits for a ping ack before closing the connection.
+	GRPCKeepAliveTimeout = flag.Duration("grpc_server_keepalive_timeout", 0, "After having pinged for keepalive check, the server waits for a duration of Timeout and if no activity is seen even after that the connection is closed. 0 means the grpc default (20s).")
+
 	authPlugin Authenticator
 )
 
@@ -155,6 +163,8 @@ func createGRPCServer() {
 	if GRPCMaxConnectionAge != nil {
 		ka := keepalive.ServerParameters{
 			MaxConnectionAge: *GRPCMaxConnectionAge,
+			Time:             *GRPCKeepAliveTime,
+			Timeout:          *GRPCKeepAliveTimeout,
 		}
 		if GRPCMaxConnectionAgeGrace != nil {
 			ka.MaxConnectionAgeGrace = *GRPCMaxConnectionAgeGrace
@@ -27,6 +27,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/log"
 )
 
@@ -62,7 +63,9 @@ func (sa *StaticAuthPlugin) Authenticate(ctx context.Context, fullMethod string)
 		password := md["password"][0]
 		for _, authEntry := range sa.entries {
 			if username == authEntry.Username && password == authEntry.Password {
-				return ctx, nil
+				// Remember the authenticated user as the immediate caller,
+				// so it can be used for authorization.
+				return callerid.NewContext(ctx, callerid.EffectiveCallerIDFromContext(ctx), callerid.NewImmediateCallerID(username)), nil
 			}
 		}
 		return nil, grpc.Errorf(codes.PermissionDenied, "auth failure: caller %q provided invalid credentials", username)
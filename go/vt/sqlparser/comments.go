@@ -32,6 +32,10 @@ const (
 	DirectiveQueryTimeout = "QUERY_TIMEOUT_MS"
 	// DirectiveScatterErrorsAsWarnings enables partial success scatter select queries
 	DirectiveScatterErrorsAsWarnings = "SCATTER_ERRORS_AS_WARNINGS"
+	// DirectiveIgnoreScatterLimits lets a query bypass the vtgate scatter limits.
+	DirectiveIgnoreScatterLimits = "IGNORE_SCATTER_LIMITS"
+	// DirectiveTabletType sends a select to the given tablet type instead of the session one.
+	DirectiveTabletType = "TABLET_TYPE"
 )
 
 func isNonSpace(r rune) bool {
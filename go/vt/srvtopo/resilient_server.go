@@ -211,7 +211,7 @@ func NewResilientServer(base *topo.Server, counterPrefix string) *ResilientServe
 		log.Fatalf("srv_topo_cache_refresh must be less than or equal to srv_topo_cache_ttl")
 	}
 
-	return &ResilientServer{
+	server := &ResilientServer{
 		topoServer:   base,
 		cacheTTL:     *srvTopoCacheTTL,
 		cacheRefresh: *srvTopoCacheRefresh,
@@ -220,6 +220,31 @@ func NewResilientServer(base *topo.Server, counterPrefix string) *ResilientServe
 		srvKeyspaceNamesCache: make(map[string]*srvKeyspaceNamesEntry),
 		srvKeyspaceCache:      make(map[string]*srvKeyspaceEntry),
 	}
+	stats.NewGaugesFuncWithMultiLabels(
+		counterPrefix+"SrvKeyspaceStaleness",
+		"Resilient srvtopo server seconds since the cached SrvKeyspace was last known to be valid, 0 while its watch is running",
+		[]string{"Cell", "Keyspace"},
+		server.srvKeyspaceStaleness)
+	return server
+}
+
+// srvKeyspaceStaleness returns, for each cached SrvKeyspace, the number of
+// seconds we have been serving it without a running watch, i.e. since the
+// topo server last returned it.
+func (server *ResilientServer) srvKeyspaceStaleness() map[string]int64 {
+	server.mutex.RLock()
+	defer server.mutex.RUnlock()
+	result := make(map[string]int64, len(server.srvKeyspaceCache))
+	for key, entry := range server.srvKeyspaceCache {
+		entry.mutex.RLock()
+		if entry.value == nil || entry.watchState == watchStateRunning {
+			result[key] = 0
+		} else {
+			result[key] = int64(time.Since(entry.lastValueTime).Seconds())
+		}
+		entry.mutex.RUnlock()
+	}
+	return result
 }
 
 // GetTopoServer returns the topo.Server that backs the resilient server.
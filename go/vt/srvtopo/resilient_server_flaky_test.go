@@ -360,6 +360,47 @@ func TestSrvKeyspaceCachedError(t *testing.T) {
 	}
 }
 
+// TestSrvKeyspaceStaleness tests the staleness of the cached SrvKeyspace
+// is reported when the watch fails.
+func TestSrvKeyspaceStaleness(t *testing.T) {
+	ts, factory := memorytopo.NewServerAndFactory("test_cell")
+	ctx := context.Background()
+	rs := NewResilientServer(ts, "TestSrvKeyspaceStaleness")
+	key := "test_cell.test_ks"
+
+	ts.UpdateSrvKeyspace(ctx, "test_cell", "test_ks", &topodatapb.SrvKeyspace{})
+	if _, err := rs.GetSrvKeyspace(ctx, "test_cell", "test_ks"); err != nil {
+		t.Fatalf("GetSrvKeyspace failed: %v", err)
+	}
+	if got := rs.srvKeyspaceStaleness(); got[key] != 0 {
+		t.Errorf("staleness while watching = %v, want 0", got)
+	}
+
+	// Break the watch, the cached value becomes stale.
+	factory.SetError(fmt.Errorf("test topo error"))
+	defer factory.SetError(nil)
+	entry := rs.getSrvKeyspaceEntry("test_cell", "test_ks")
+	expiry := time.Now().Add(5 * time.Second)
+	for {
+		entry.mutex.Lock()
+		running := entry.watchState == watchStateRunning
+		if !running {
+			entry.lastValueTime = time.Now().Add(-10 * time.Second)
+		}
+		entry.mutex.Unlock()
+		if !running {
+			break
+		}
+		if time.Now().After(expiry) {
+			t.Fatalf("timed out waiting for the watch to fail")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := rs.srvKeyspaceStaleness(); got[key] != 10 {
+		t.Errorf("staleness after watch error = %v, want 10", got)
+	}
+}
+
 // TestGetSrvKeyspaceCreated will test we properly get the initial
 // value if the SrvKeyspace already exists.
 func TestGetSrvKeyspaceCreated(t *testing.T) {
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestBuildTablePermissions(t *testing.T) {
+	tcases := []struct {
+		input  string
+		output []string
+	}{{
+		input:  "select * from ks.t1 join t2",
+		output: []string{"ks.t1:READER", "t2:READER"},
+	}, {
+		input:  "insert into ks.t1 select * from t2",
+		output: []string{"ks.t1:WRITER", "t2:READER"},
+	}, {
+		input:  "stream * from t",
+		output: []string{"t:READER"},
+	}, {
+		input:  "drop table ks.t",
+		output: []string{"ks.t:ADMIN"},
+	}, {
+		input:  "use ks",
+		output: nil,
+	}, {
+		input:  "create database ks",
+		output: nil,
+	}}
+	for _, tcase := range tcases {
+		stmt, err := sqlparser.Parse(tcase.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, perm := range BuildTablePermissions(stmt) {
+			got = append(got, fmt.Sprintf("%s:%s", sqlparser.String(perm.Table), perm.Role.Name()))
+		}
+		if !reflect.DeepEqual(got, tcase.output) {
+			t.Errorf("BuildTablePermissions(%s): %v, want %v", tcase.input, got, tcase.output)
+		}
+	}
+}
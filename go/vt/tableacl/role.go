@@ -7,7 +7,7 @@ You may obtain a copy of the License at
 
     http://www.apache.org/licenses/LICENSE-2.0
 
-Unless required by applicable law or agreedto in writing, software
+Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
 WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
@@ -16,42 +16,24 @@ limitations under the License.
 
 package tableacl
 
-import "strings"
+import "vitess.io/vitess/go/vt/tableacl/acl"
 
-// Role defines the level of access on a table
-type Role int
+// Role defines the level of access on a table. It is defined in the acl
+// package, so vtgate can use it without the tableacl dependencies.
+type Role = acl.Role
 
 const (
 	// READER can run SELECT statements
-	READER Role = iota
+	READER = acl.READER
 	// WRITER can run SELECT, INSERT & UPDATE statements
-	WRITER
+	WRITER = acl.WRITER
 	// ADMIN can run any statements including DDLs
-	ADMIN
+	ADMIN = acl.ADMIN
 	// NumRoles is number of Roles defined
-	NumRoles
+	NumRoles = acl.NumRoles
 )
 
-var roleNames = []string{
-	"READER",
-	"WRITER",
-	"ADMIN",
-}
-
-// Name returns the name of a role
-func (r Role) Name() string {
-	if r < READER || r > ADMIN {
-		return ""
-	}
-	return roleNames[r]
-}
-
 // RoleByName returns the Role corresponding to a name
 func RoleByName(s string) (Role, bool) {
-	for i, v := range roleNames {
-		if v == strings.ToUpper(s) {
-			return Role(i), true
-		}
-	}
-	return NumRoles, false
+	return acl.RoleByName(s)
 }
@@ -173,3 +173,24 @@ func (ts *Server) GetKnownCells(ctx context.Context) ([]string, error) {
 	}
 	return DirEntriesToStringArray(entries), nil
 }
+
+// GetCellsInRegion returns the cells of a region. A cell without a region
+// is its own region (see GetRegionByCell), so the cell named region is
+// returned even if it doesn't have its Region field set.
+func (ts *Server) GetCellsInRegion(ctx context.Context, region string) ([]string, error) {
+	names, err := ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var cells []string
+	for _, cell := range names {
+		ci, err := ts.GetCellInfo(ctx, cell, false /*strongRead*/)
+		if err != nil {
+			return nil, err
+		}
+		if ci.Region == region || (ci.Region == "" && cell == region) {
+			cells = append(cells, cell)
+		}
+	}
+	return cells, nil
+}
@@ -108,6 +108,23 @@ type Conn interface {
 	// Returns ErrInterrupted if ctx is canceled.
 	Lock(ctx context.Context, dirPath, contents string) (LockDescriptor, error)
 
+	// ReadLock returns the contents of the lock currently held
+	// on the given directory, as passed to Lock by its holder,
+	// and an opaque identifier of that holder for BreakLock.
+	// Returns ErrNoNode if the directory is not locked.
+	ReadLock(ctx context.Context, dirPath string) (contents string, holder string, err error)
+
+	// BreakLock forcibly releases the lock held by holder on the
+	// given directory, so the next waiter (if any) can get it.
+	// holder is returned by ReadLock: if the lock was released
+	// and taken again since then, the new holder keeps it.
+	// It is meant to clean up locks left behind by processes
+	// that did not release them. The holder's LockDescriptor
+	// will then fail Check (where the implementation supports it)
+	// and Unlock.
+	// Returns ErrNoNode if holder doesn't hold the lock.
+	BreakLock(ctx context.Context, dirPath, holder string) error
+
 	//
 	// Watches
 	//
@@ -271,7 +288,8 @@ type WatchData struct {
 }
 
 // MasterParticipation is the object returned by NewMasterParticipation.
-// Sample usage:
+// Server.NewMasterParticipation and RunAsMaster implement the usual
+// pattern. Sample usage:
 //
 // mp := server.NewMasterParticipation("vtctld", "hostname:8080")
 // job := NewJob()
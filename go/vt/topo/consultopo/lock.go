@@ -146,3 +146,43 @@ func (s *Server) unlock(ctx context.Context, lockPath string) error {
 
 	return unlockErr
 }
+
+// lockHolder returns the lock file for the given directory,
+// if it is currently held by a session.
+func (s *Server) lockHolder(ctx context.Context, dirPath string) (*api.KVPair, error) {
+	lockPath := path.Join(s.root, dirPath, locksFilename)
+	pair, _, err := s.kv.Get(lockPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil || pair.Session == "" {
+		return nil, topo.NewError(topo.NoNode, lockPath)
+	}
+	return pair, nil
+}
+
+// ReadLock is part of the topo.Conn interface.
+// The holder is the id of the session that holds the lock.
+func (s *Server) ReadLock(ctx context.Context, dirPath string) (string, string, error) {
+	pair, err := s.lockHolder(ctx, dirPath)
+	if err != nil {
+		return "", "", err
+	}
+	return string(pair.Value), pair.Session, nil
+}
+
+// BreakLock is part of the topo.Conn interface.
+// We destroy the session of the holder, which releases the lock,
+// and closes the holder's lost channel. Each lock has its own
+// session, so nobody else loses their lock.
+func (s *Server) BreakLock(ctx context.Context, dirPath, holder string) error {
+	pair, err := s.lockHolder(ctx, dirPath)
+	if err != nil {
+		return err
+	}
+	if pair.Session != holder {
+		return topo.NewError(topo.NoNode, path.Join(dirPath, locksFilename))
+	}
+	_, err = s.client.Session().Destroy(holder, nil)
+	return err
+}
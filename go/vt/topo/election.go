@@ -0,0 +1,64 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// This file contains the master election helpers. The election itself
+// is implemented by each Conn (see MasterParticipation), on top of the
+// locking primitives of the topology service. These helpers let a
+// component run highly-available, with exactly one active instance per
+// cell (or one overall, in the global cell).
+
+// NewMasterParticipation returns a MasterParticipation for the election
+// called name in the topology service of the cell. Use GlobalCell for an
+// election across all cells. id identifies this process, and is returned
+// by GetCurrentMasterID to the other participants, usually to redirect
+// requests to the master.
+func (ts *Server) NewMasterParticipation(ctx context.Context, cell, name, id string) (MasterParticipation, error) {
+	conn, err := ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewMasterParticipation(name, id)
+}
+
+// RunAsMaster participates in the election of mp, and calls run every
+// time this process becomes the master. The context given to run is
+// canceled when the mastership is lost, and run is expected to return
+// then. If waiting for the mastership fails, RunAsMaster waits for
+// retryDelay before trying again. It returns once mp.Stop() was called.
+func RunAsMaster(mp MasterParticipation, retryDelay time.Duration, run func(ctx context.Context)) {
+	for {
+		ctx, err := mp.WaitForMastership()
+		switch {
+		case err == nil:
+			run(ctx)
+		case IsErrType(err, Interrupted):
+			return
+		default:
+			log.Errorf("Got error while waiting for master, will retry in %v: %v", retryDelay, err)
+			time.Sleep(retryDelay)
+		}
+	}
+}
@@ -194,3 +194,55 @@ func (ld *etcdLockDescriptor) Unlock(ctx context.Context) error {
 	}
 	return nil
 }
+
+// lockHolder returns the key of the current holder of the lock in the
+// given locks directory, i.e. the oldest key in there.
+// Errors returned are converted to topo errors.
+func (s *Server) lockHolder(ctx context.Context, nodePath string) (*mvccpb.KeyValue, error) {
+	resp, err := s.cli.Get(ctx, nodePath+"/", clientv3.WithFirstCreate()...)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	return resp.Kvs[0], nil
+}
+
+// ReadLock is part of the topo.Conn interface.
+// The holder is the key of its lock file.
+func (s *Server) ReadLock(ctx context.Context, dirPath string) (string, string, error) {
+	kv, err := s.lockHolder(ctx, path.Join(s.root, dirPath, locksPath))
+	if err != nil {
+		return "", "", err
+	}
+	return string(kv.Value), string(kv.Key), nil
+}
+
+// BreakLock is part of the topo.Conn interface.
+// We revoke the lease of the holder, which deletes its lock file
+// and makes its Check fail. The lease is only used by that lock
+// file, so nobody else loses their lock.
+func (s *Server) BreakLock(ctx context.Context, dirPath, holder string) error {
+	nodePath := path.Join(s.root, dirPath, locksPath)
+	if path.Dir(holder) != nodePath {
+		return topo.NewError(topo.NoNode, holder)
+	}
+	resp, err := s.cli.Get(ctx, holder)
+	if err != nil {
+		return convertError(err, holder)
+	}
+	if len(resp.Kvs) == 0 {
+		return topo.NewError(topo.NoNode, holder)
+	}
+	kv := resp.Kvs[0]
+	if kv.Lease == 0 {
+		// Should not happen, but we can still delete the file.
+		_, err := s.cli.Delete(ctx, holder)
+		return convertError(err, holder)
+	}
+	if _, err := s.cli.Revoke(ctx, clientv3.LeaseID(kv.Lease)); err != nil {
+		return convertError(err, nodePath)
+	}
+	return nil
+}
@@ -17,13 +17,16 @@ limitations under the License.
 /*
 Package etcd2topo implements topo.Server with etcd as the backend.
 
-We expect the following behavior from the etcd client library:
+It uses the etcd v3 API (clientv3):
 
-  - Get and Delete return ErrorCodeKeyNotFound if the node doesn't exist.
-  - Create returns ErrorCodeNodeExist if the node already exists.
-  - Intermediate directories are always created automatically if necessary.
-  - Set returns ErrorCodeKeyNotFound if the node doesn't exist already.
-  - It returns ErrorCodeTestFailed if the provided version index doesn't match.
+  - Files are keys, and their version is the ModRevision of the key.
+    Conditional creations, updates and deletes are transactions
+    comparing the Version or ModRevision of the key.
+  - Directories don't exist, they are listed with a prefix Get.
+  - Locks and master elections use ephemeral keys attached to a lease,
+    kept alive while they are held (see the -topo_etcd_lease_ttl flag).
+  - Watches use the native watch streams of etcd, starting at the
+    revision of the initial read so no change is missed.
 
 We follow these conventions within this package:
 
@@ -41,7 +44,7 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 )
 
-// Factory is the consul topo.Factory implementation.
+// Factory is the etcd2 topo.Factory implementation.
 type Factory struct{}
 
 // HasGlobalReadOnlyCell is part of the topo.Factory interface.
@@ -0,0 +1,191 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// This file contains the backup and restore of a whole topology, for
+// disaster recovery of the topology service itself. A backup contains
+// every file of the global cell and of each cell, as stored by the
+// topo.Conn, so it does not depend on the topo implementation. Ephemeral
+// files (locks and master elections) are not backed up.
+
+// BackupVersion is the version of the backup format written by
+// WriteBackup. ReadBackup refuses other versions.
+const BackupVersion = 1
+
+// Backup is the content of a topology backup.
+type Backup struct {
+	// Version is the format version, BackupVersion.
+	Version int
+
+	// Time is when the backup was taken.
+	Time time.Time
+
+	// Cells has the files of the global cell first, then of
+	// the other cells.
+	Cells []*CellBackup
+}
+
+// CellBackup has the files of one cell.
+type CellBackup struct {
+	Cell  string
+	Files []*BackupFile
+}
+
+// BackupFile is one file of the topology.
+type BackupFile struct {
+	// Path is relative to the root of the cell.
+	Path string
+
+	// Contents is usually a binary proto, and is base64-encoded
+	// in the JSON representation.
+	Contents []byte
+}
+
+// BackupTopo reads all the files of the topology.
+func BackupTopo(ctx context.Context, ts *topo.Server) (*Backup, error) {
+	cells, err := ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("GetCellInfoNames: %v", err)
+	}
+
+	b := &Backup{
+		Version: BackupVersion,
+		Time:    time.Now(),
+	}
+	for _, cell := range append([]string{topo.GlobalCell}, cells...) {
+		conn, err := ts.ConnForCell(ctx, cell)
+		if err != nil {
+			return nil, fmt.Errorf("ConnForCell(%v): %v", cell, err)
+		}
+		cb := &CellBackup{Cell: cell}
+		if err := backupDir(ctx, conn, "/", cb); err != nil {
+			return nil, fmt.Errorf("cannot backup cell %v: %v", cell, err)
+		}
+		b.Cells = append(b.Cells, cb)
+	}
+	return b, nil
+}
+
+// backupDir adds the files of a directory, recursively, to the cell backup.
+func backupDir(ctx context.Context, conn topo.Conn, dirPath string, cb *CellBackup) error {
+	entries, err := conn.ListDir(ctx, dirPath, true /*full*/)
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		// The cell is empty.
+		return nil
+	default:
+		return fmt.Errorf("ListDir(%v): %v", dirPath, err)
+	}
+
+	for _, e := range entries {
+		if e.Ephemeral {
+			continue
+		}
+		p := path.Join(dirPath, e.Name)
+		if e.Type == topo.TypeDirectory {
+			if err := backupDir(ctx, conn, p, cb); err != nil {
+				return err
+			}
+			continue
+		}
+		contents, _, err := conn.Get(ctx, p)
+		if err != nil {
+			if topo.IsErrType(err, topo.NoNode) {
+				// Deleted since it was listed.
+				continue
+			}
+			return fmt.Errorf("Get(%v): %v", p, err)
+		}
+		cb.Files = append(cb.Files, &BackupFile{
+			Path:     p,
+			Contents: contents,
+		})
+	}
+	return nil
+}
+
+// RestoreTopo creates all the files of the backup in the topology,
+// which is expected to be empty. The global cell is restored first,
+// so the cells are then reached with their restored CellInfo. A CellInfo
+// that already exists in the topology is kept, so cells can be pointed
+// to new servers with AddCellInfo before the restore. Any other existing
+// file is an error, and is not overwritten.
+func RestoreTopo(ctx context.Context, ts *topo.Server, b *Backup) error {
+	rec := concurrency.AllErrorRecorder{}
+	for _, cb := range b.Cells {
+		conn, err := ts.ConnForCell(ctx, cb.Cell)
+		if err != nil {
+			rec.RecordError(fmt.Errorf("ConnForCell(%v): %v", cb.Cell, err))
+			continue
+		}
+		for _, f := range cb.Files {
+			_, err := conn.Create(ctx, f.Path, f.Contents)
+			switch {
+			case err == nil:
+			case topo.IsErrType(err, topo.NodeExists) && cb.Cell == topo.GlobalCell && isCellInfoPath(f.Path):
+				log.Warningf("CellInfo %v already exists, keeping it", f.Path)
+			default:
+				rec.RecordError(fmt.Errorf("Create(%v, %v): %v", cb.Cell, f.Path, err))
+			}
+		}
+	}
+	return rec.Error()
+}
+
+// isCellInfoPath returns true for the path of a CellInfo file in the
+// global cell.
+func isCellInfoPath(filePath string) bool {
+	dir, file := path.Split(filePath)
+	return file == topo.CellInfoFile && path.Dir(path.Clean(dir)) == path.Join("/", topo.CellsPath)
+}
+
+// WriteBackup writes the backup as JSON.
+func WriteBackup(w io.Writer, b *Backup) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadBackup reads a backup written by WriteBackup.
+func ReadBackup(r io.Reader) (*Backup, error) {
+	b := &Backup{}
+	if err := json.NewDecoder(r).Decode(b); err != nil {
+		return nil, fmt.Errorf("cannot parse backup: %v", err)
+	}
+	if b.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %v, expected %v", b.Version, BackupVersion)
+	}
+	return b, nil
+}
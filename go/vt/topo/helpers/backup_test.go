@@ -0,0 +1,127 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	fromTS, toTS := createSetup(ctx, t)
+	if err := fromTS.UpdateSrvKeyspace(ctx, "test_cell", "test_keyspace", &topodatapb.SrvKeyspace{ShardingColumnName: "id"}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+
+	// Locks are not part of the backup.
+	lockCtx, unlock, err := fromTS.LockKeyspace(ctx, "test_keyspace", "backup test")
+	if err != nil {
+		t.Fatalf("LockKeyspace failed: %v", err)
+	}
+	b, err := BackupTopo(lockCtx, fromTS)
+	var unlockErr error
+	unlock(&unlockErr)
+	if err != nil {
+		t.Fatalf("BackupTopo failed: %v", err)
+	}
+
+	// Write and read it back.
+	buf := &bytes.Buffer{}
+	if err := WriteBackup(buf, b); err != nil {
+		t.Fatalf("WriteBackup failed: %v", err)
+	}
+	b, err = ReadBackup(buf)
+	if err != nil {
+		t.Fatalf("ReadBackup failed: %v", err)
+	}
+	if len(b.Cells) != 2 || b.Cells[0].Cell != topo.GlobalCell || b.Cells[1].Cell != "test_cell" {
+		t.Fatalf("unexpected cells in backup: %+v", b.Cells)
+	}
+
+	// Restore it. The CellInfo of toTS already exists, and is kept.
+	if err := RestoreTopo(ctx, toTS, b); err != nil {
+		t.Fatalf("RestoreTopo failed: %v", err)
+	}
+	si, err := toTS.GetShard(ctx, "test_keyspace", "0")
+	if err != nil {
+		t.Fatalf("GetShard failed: %v", err)
+	}
+	if len(si.Cells) != 1 || si.Cells[0] != "test_cell" {
+		t.Errorf("unexpected restored shard: %v", si.Shard)
+	}
+	for _, uid := range []uint32{123, 234} {
+		alias := &topodatapb.TabletAlias{Cell: "test_cell", Uid: uid}
+		want, err := fromTS.GetTablet(ctx, alias)
+		if err != nil {
+			t.Fatalf("GetTablet failed: %v", err)
+		}
+		got, err := toTS.GetTablet(ctx, alias)
+		if err != nil {
+			t.Fatalf("GetTablet of restored tablet failed: %v", err)
+		}
+		if !proto.Equal(got.Tablet, want.Tablet) {
+			t.Errorf("restored tablet: got %v, want %v", got.Tablet, want.Tablet)
+		}
+	}
+	srvKeyspace, err := toTS.GetSrvKeyspace(ctx, "test_cell", "test_keyspace")
+	if err != nil || srvKeyspace.ShardingColumnName != "id" {
+		t.Errorf("GetSrvKeyspace of restored keyspace = %v, %v", srvKeyspace, err)
+	}
+
+	// The lock was released, the restored keyspace can be locked.
+	_, unlock, err = toTS.LockKeyspace(ctx, "test_keyspace", "restore test")
+	if err != nil {
+		t.Fatalf("LockKeyspace of restored keyspace failed: %v", err)
+	}
+	unlock(&unlockErr)
+
+	// Restoring again fails, existing files are not overwritten.
+	if err := RestoreTopo(ctx, toTS, b); err == nil || !strings.Contains(err.Error(), "Keyspace") {
+		t.Errorf("second RestoreTopo returned %v, want a Keyspace error", err)
+	}
+}
+
+func TestReadBackupVersion(t *testing.T) {
+	if _, err := ReadBackup(strings.NewReader(`{"Version": 2}`)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("ReadBackup of version 2 returned %v", err)
+	}
+	if _, err := ReadBackup(strings.NewReader(`not json`)); err == nil {
+		t.Errorf("ReadBackup of invalid data should have failed")
+	}
+}
+
+func TestIsCellInfoPath(t *testing.T) {
+	for p, want := range map[string]bool{
+		"/cells/cell1/CellInfo":        true,
+		"/keyspaces/ks/Keyspace":       false,
+		"/cells/cell1/other/CellInfo":  false,
+		"/keyspaces/cells/ks/CellInfo": false,
+	} {
+		if got := isCellInfoPath(p); got != want {
+			t.Errorf("isCellInfoPath(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
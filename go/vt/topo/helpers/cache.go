@@ -0,0 +1,271 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// Possible labels of cacheCounts.
+const (
+	cacheHit       = "Hit"
+	cacheMiss      = "Miss"
+	cacheCoalesced = "Coalesced"
+	cacheThrottled = "Throttled"
+)
+
+// cacheCounts is exported in /debug/vars as TopoCacheCounts.
+var cacheCounts = stats.NewCountersWithSingleLabel("TopoCacheCounts", "Topo cache operations", "type", cacheHit, cacheMiss, cacheCoalesced, cacheThrottled)
+
+// CacheConfig configures a topo cache.
+type CacheConfig struct {
+	// TTLs maps the file names of the record types to cache, like
+	// topo.TabletFile or topo.SrvKeyspaceFile, to how long they are
+	// cached. The other records are always read from the topo.
+	TTLs map[string]time.Duration
+
+	// MaxConcurrentReads limits the number of concurrent reads sent to
+	// each cell of the underlying topo. 0 means no limit.
+	MaxConcurrentReads int
+}
+
+// ParseCacheTTLs parses the TTLs of a CacheConfig, from a map of record
+// types to durations, as given by a flagutil.StringMapValue flag.
+func ParseCacheTTLs(values map[string]string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for fileName, value := range values {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TTL for %v: %v", fileName, err)
+		}
+		ttls[fileName] = ttl
+	}
+	return ttls, nil
+}
+
+// CacheFactory is an implementation of topo.Factory that caches the
+// records read from an underlying topo.Server. It is meant for processes
+// that mostly read the topology, so a lot of them restarting at the same
+// time doesn't overload the topo service:
+// - records are cached for the TTL of their type.
+// - concurrent reads of the same record are coalesced into one read.
+// - the number of concurrent reads can be limited.
+// Writes, locks and watches go directly to the underlying topo. A write
+// from this process invalidates the cached record, but writes from other
+// processes are only seen once the TTL has elapsed.
+type CacheFactory struct {
+	ts     *topo.Server
+	config CacheConfig
+}
+
+// HasGlobalReadOnlyCell is part of the topo.Factory interface.
+func (f *CacheFactory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+// Create is part of the topo.Factory interface.
+func (f *CacheFactory) Create(cell, serverAddr, root string) (topo.Conn, error) {
+	conn, err := f.ts.ConnForCell(context.Background(), cell)
+	if err != nil {
+		return nil, err
+	}
+	c := &CacheConn{
+		conn:     conn,
+		ttls:     f.config.TTLs,
+		entries:  make(map[string]*cacheEntry),
+		inflight: make(map[string]*cacheRead),
+	}
+	if f.config.MaxConcurrentReads > 0 {
+		c.readSlots = make(chan struct{}, f.config.MaxConcurrentReads)
+	}
+	return c, nil
+}
+
+// NewCache returns a new topo.Server object. It uses a CacheFactory.
+func NewCache(ts *topo.Server, config CacheConfig) (*topo.Server, error) {
+	f := &CacheFactory{
+		ts:     ts,
+		config: config,
+	}
+	return topo.NewWithFactory(f, "" /*serverAddress*/, "" /*root*/)
+}
+
+// cacheEntry is a cached record.
+type cacheEntry struct {
+	contents   []byte
+	version    topo.Version
+	expiration time.Time
+}
+
+// cacheRead is a read of the underlying topo in progress. The readers of
+// the same record wait for done to be closed, then use the result.
+type cacheRead struct {
+	done     chan struct{}
+	contents []byte
+	version  topo.Version
+	err      error
+}
+
+// CacheConn implements the topo.Conn interface.
+type CacheConn struct {
+	conn topo.Conn
+	ttls map[string]time.Duration
+
+	// readSlots limits the concurrent reads if not nil.
+	readSlots chan struct{}
+
+	// mu protects the fields below.
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	inflight map[string]*cacheRead
+	// generation is incremented by each write, so a read started
+	// before a write doesn't cache its outdated result.
+	generation int64
+}
+
+// invalidate removes a record from the cache, after it was changed.
+func (c *CacheConn) invalidate(filePath string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, filePath)
+	c.generation++
+}
+
+// Close is part of the topo.Conn interface.
+func (c *CacheConn) Close() {
+	c.conn.Close()
+}
+
+// ListDir is part of the topo.Conn interface.
+func (c *CacheConn) ListDir(ctx context.Context, dirPath string, full bool) ([]topo.DirEntry, error) {
+	return c.conn.ListDir(ctx, dirPath, full)
+}
+
+// Create is part of the topo.Conn interface.
+func (c *CacheConn) Create(ctx context.Context, filePath string, contents []byte) (topo.Version, error) {
+	defer c.invalidate(filePath)
+	return c.conn.Create(ctx, filePath, contents)
+}
+
+// Update is part of the topo.Conn interface.
+func (c *CacheConn) Update(ctx context.Context, filePath string, contents []byte, version topo.Version) (topo.Version, error) {
+	defer c.invalidate(filePath)
+	return c.conn.Update(ctx, filePath, contents, version)
+}
+
+// Get is part of the topo.Conn interface.
+func (c *CacheConn) Get(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	ttl, ok := c.ttls[path.Base(filePath)]
+	if !ok {
+		return c.read(ctx, filePath)
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[filePath]; ok && time.Now().Before(e.expiration) {
+		c.mu.Unlock()
+		cacheCounts.Add(cacheHit, 1)
+		return e.contents, e.version, nil
+	}
+	if r, ok := c.inflight[filePath]; ok {
+		c.mu.Unlock()
+		cacheCounts.Add(cacheCoalesced, 1)
+		select {
+		case <-r.done:
+			return r.contents, r.version, r.err
+		case <-ctx.Done():
+			return nil, nil, topo.NewError(topo.Timeout, filePath)
+		}
+	}
+	r := &cacheRead{done: make(chan struct{})}
+	c.inflight[filePath] = r
+	generation := c.generation
+	c.mu.Unlock()
+
+	cacheCounts.Add(cacheMiss, 1)
+	r.contents, r.version, r.err = c.read(ctx, filePath)
+
+	c.mu.Lock()
+	delete(c.inflight, filePath)
+	if r.err == nil && c.generation == generation {
+		c.entries[filePath] = &cacheEntry{
+			contents:   r.contents,
+			version:    r.version,
+			expiration: time.Now().Add(ttl),
+		}
+	}
+	c.mu.Unlock()
+	close(r.done)
+	return r.contents, r.version, r.err
+}
+
+// read reads a record from the underlying topo, waiting for a read slot
+// if the concurrent reads are limited.
+func (c *CacheConn) read(ctx context.Context, filePath string) ([]byte, topo.Version, error) {
+	if c.readSlots != nil {
+		select {
+		case c.readSlots <- struct{}{}:
+		default:
+			cacheCounts.Add(cacheThrottled, 1)
+			select {
+			case c.readSlots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, nil, topo.NewError(topo.Timeout, filePath)
+			}
+		}
+		defer func() { <-c.readSlots }()
+	}
+	return c.conn.Get(ctx, filePath)
+}
+
+// Delete is part of the topo.Conn interface.
+func (c *CacheConn) Delete(ctx context.Context, filePath string, version topo.Version) error {
+	defer c.invalidate(filePath)
+	return c.conn.Delete(ctx, filePath, version)
+}
+
+// Watch is part of the topo.Conn interface.
+func (c *CacheConn) Watch(ctx context.Context, filePath string) (*topo.WatchData, <-chan *topo.WatchData, topo.CancelFunc) {
+	return c.conn.Watch(ctx, filePath)
+}
+
+// Lock is part of the topo.Conn interface.
+func (c *CacheConn) Lock(ctx context.Context, dirPath, contents string) (topo.LockDescriptor, error) {
+	return c.conn.Lock(ctx, dirPath, contents)
+}
+
+// ReadLock is part of the topo.Conn interface.
+func (c *CacheConn) ReadLock(ctx context.Context, dirPath string) (string, string, error) {
+	return c.conn.ReadLock(ctx, dirPath)
+}
+
+// BreakLock is part of the topo.Conn interface.
+func (c *CacheConn) BreakLock(ctx context.Context, dirPath, holder string) error {
+	return c.conn.BreakLock(ctx, dirPath, holder)
+}
+
+// NewMasterParticipation is part of the topo.Conn interface.
+func (c *CacheConn) NewMasterParticipation(name, id string) (topo.MasterParticipation, error) {
+	return c.conn.NewMasterParticipation(name, id)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	cacheTS, err := NewCache(ts, CacheConfig{
+		TTLs: map[string]time.Duration{
+			topo.SrvKeyspaceFile: 100 * time.Millisecond,
+		},
+		MaxConcurrentReads: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	getSrvKeyspace := func() string {
+		t.Helper()
+		sk, err := cacheTS.GetSrvKeyspace(ctx, "cell1", "ks")
+		if err != nil {
+			t.Fatalf("GetSrvKeyspace failed: %v", err)
+		}
+		return sk.ShardingColumnName
+	}
+
+	// The first read is a miss, the second one a hit.
+	if err := ts.UpdateSrvKeyspace(ctx, "cell1", "ks", &topodatapb.SrvKeyspace{ShardingColumnName: "c1"}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+	hits := cacheCounts.Counts()[cacheHit]
+	misses := cacheCounts.Counts()[cacheMiss]
+	if got := getSrvKeyspace(); got != "c1" {
+		t.Errorf("first read: got %v, want c1", got)
+	}
+	if got := getSrvKeyspace(); got != "c1" {
+		t.Errorf("second read: got %v, want c1", got)
+	}
+	if got := cacheCounts.Counts()[cacheMiss] - misses; got != 1 {
+		t.Errorf("got %v misses, want 1", got)
+	}
+	if got := cacheCounts.Counts()[cacheHit] - hits; got != 1 {
+		t.Errorf("got %v hits, want 1", got)
+	}
+
+	// A change made by another process is only seen after the TTL.
+	if err := ts.UpdateSrvKeyspace(ctx, "cell1", "ks", &topodatapb.SrvKeyspace{ShardingColumnName: "c2"}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+	if got := getSrvKeyspace(); got != "c1" {
+		t.Errorf("read within the TTL: got %v, want c1", got)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if got := getSrvKeyspace(); got != "c2" {
+		t.Errorf("read after the TTL: got %v, want c2", got)
+	}
+
+	// A change made through the cache is seen right away.
+	if err := cacheTS.UpdateSrvKeyspace(ctx, "cell1", "ks", &topodatapb.SrvKeyspace{ShardingColumnName: "c3"}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+	if got := getSrvKeyspace(); got != "c3" {
+		t.Errorf("read after a write: got %v, want c3", got)
+	}
+
+	// The other record types are not cached.
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	if _, err := cacheTS.GetKeyspace(ctx, "ks"); err != nil {
+		t.Fatalf("GetKeyspace failed: %v", err)
+	}
+	if err := ts.DeleteKeyspace(ctx, "ks"); err != nil {
+		t.Fatalf("DeleteKeyspace failed: %v", err)
+	}
+	if _, err := cacheTS.GetKeyspace(ctx, "ks"); !topo.IsErrType(err, topo.NoNode) {
+		t.Errorf("GetKeyspace of a deleted keyspace returned %v, want NoNode", err)
+	}
+}
+
+func TestParseCacheTTLs(t *testing.T) {
+	got, err := ParseCacheTTLs(map[string]string{
+		topo.TabletFile:      "1s",
+		topo.SrvKeyspaceFile: "5m",
+	})
+	if err != nil {
+		t.Fatalf("ParseCacheTTLs failed: %v", err)
+	}
+	want := map[string]time.Duration{
+		topo.TabletFile:      time.Second,
+		topo.SrvKeyspaceFile: 5 * time.Minute,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCacheTTLs: got %v, want %v", got, want)
+	}
+
+	if _, err := ParseCacheTTLs(map[string]string{topo.TabletFile: "soon"}); err == nil {
+		t.Errorf("ParseCacheTTLs with an invalid duration should have failed")
+	}
+}
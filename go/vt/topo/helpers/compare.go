@@ -0,0 +1,214 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+// topoComparer compares the records of two topologies, and records the
+// differences.
+type topoComparer struct {
+	fromTS, toTS *topo.Server
+	rec          concurrency.AllErrorRecorder
+}
+
+// CompareTopos checks that the destination topo has the same keyspaces,
+// vschemas, shards, tablets, replication graphs and serving graphs as the
+// source topo. It is meant to validate a copy done by the Copy* functions,
+// and returns an error describing all the differences.
+func CompareTopos(ctx context.Context, fromTS, toTS *topo.Server) error {
+	c := &topoComparer{fromTS: fromTS, toTS: toTS}
+	c.compareKeyspaces(ctx)
+
+	cells, err := fromTS.GetKnownCells(ctx)
+	if err != nil {
+		return fmt.Errorf("fromTS.GetKnownCells: %v", err)
+	}
+	toCells, err := toTS.GetKnownCells(ctx)
+	if err != nil {
+		return fmt.Errorf("toTS.GetKnownCells: %v", err)
+	}
+	c.compareNames("cells", cells, toCells)
+	for _, cell := range cells {
+		c.compareTablets(ctx, cell)
+		c.compareServingGraph(ctx, cell)
+	}
+	return c.rec.Error()
+}
+
+// compareNames records the names of fromNames missing from toNames, and
+// the opposite.
+func (c *topoComparer) compareNames(what string, fromNames, toNames []string) {
+	toSet := make(map[string]bool)
+	for _, name := range toNames {
+		toSet[name] = true
+	}
+	for _, name := range fromNames {
+		if !toSet[name] {
+			c.rec.RecordError(fmt.Errorf("%v: %v is missing in the destination", what, name))
+		}
+		delete(toSet, name)
+	}
+	for name := range toSet {
+		c.rec.RecordError(fmt.Errorf("%v: %v only exists in the destination", what, name))
+	}
+}
+
+// compareRecords reads a record in both topologies, and records an error
+// if it differs. A record missing in both is not a difference.
+func (c *topoComparer) compareRecords(what string, get func(ts *topo.Server) (proto.Message, error)) {
+	from, fromErr := get(c.fromTS)
+	to, toErr := get(c.toTS)
+	fromMissing := topo.IsErrType(fromErr, topo.NoNode)
+	toMissing := topo.IsErrType(toErr, topo.NoNode)
+	switch {
+	case fromErr != nil && !fromMissing:
+		c.rec.RecordError(fmt.Errorf("%v: cannot read the source: %v", what, fromErr))
+	case toErr != nil && !toMissing:
+		c.rec.RecordError(fmt.Errorf("%v: cannot read the destination: %v", what, toErr))
+	case fromMissing && toMissing:
+	case fromMissing:
+		c.rec.RecordError(fmt.Errorf("%v: only exists in the destination", what))
+	case toMissing:
+		c.rec.RecordError(fmt.Errorf("%v: is missing in the destination", what))
+	case !proto.Equal(from, to):
+		c.rec.RecordError(fmt.Errorf("%v: differs, source %v, destination %v", what, from, to))
+	}
+}
+
+func (c *topoComparer) compareKeyspaces(ctx context.Context) {
+	keyspaces, err := c.fromTS.GetKeyspaces(ctx)
+	if err != nil {
+		c.rec.RecordError(fmt.Errorf("fromTS.GetKeyspaces: %v", err))
+		return
+	}
+	toKeyspaces, err := c.toTS.GetKeyspaces(ctx)
+	if err != nil {
+		c.rec.RecordError(fmt.Errorf("toTS.GetKeyspaces: %v", err))
+		return
+	}
+	c.compareNames("keyspaces", keyspaces, toKeyspaces)
+
+	for _, keyspace := range keyspaces {
+		c.compareRecords(fmt.Sprintf("keyspace %v", keyspace), func(ts *topo.Server) (proto.Message, error) {
+			ki, err := ts.GetKeyspace(ctx, keyspace)
+			if err != nil {
+				return nil, err
+			}
+			return ki.Keyspace, nil
+		})
+		c.compareRecords(fmt.Sprintf("vschema %v", keyspace), func(ts *topo.Server) (proto.Message, error) {
+			return ts.GetVSchema(ctx, keyspace)
+		})
+		c.compareShards(ctx, keyspace)
+	}
+}
+
+func (c *topoComparer) compareShards(ctx context.Context, keyspace string) {
+	shards, err := c.fromTS.GetShardNames(ctx, keyspace)
+	if err != nil {
+		c.rec.RecordError(fmt.Errorf("fromTS.GetShardNames(%v): %v", keyspace, err))
+		return
+	}
+	toShards, err := c.toTS.GetShardNames(ctx, keyspace)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		c.rec.RecordError(fmt.Errorf("toTS.GetShardNames(%v): %v", keyspace, err))
+		return
+	}
+	c.compareNames(fmt.Sprintf("shards of %v", keyspace), shards, toShards)
+
+	for _, shard := range shards {
+		c.compareRecords(fmt.Sprintf("shard %v/%v", keyspace, shard), func(ts *topo.Server) (proto.Message, error) {
+			si, err := ts.GetShard(ctx, keyspace, shard)
+			if err != nil {
+				return nil, err
+			}
+			return si.Shard, nil
+		})
+
+		si, err := c.fromTS.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			// Already recorded above.
+			continue
+		}
+		for _, cell := range si.Cells {
+			c.compareRecords(fmt.Sprintf("replication graph %v/%v in %v", keyspace, shard, cell), func(ts *topo.Server) (proto.Message, error) {
+				sri, err := ts.GetShardReplication(ctx, cell, keyspace, shard)
+				if err != nil {
+					return nil, err
+				}
+				return sri.ShardReplication, nil
+			})
+		}
+	}
+}
+
+func (c *topoComparer) compareTablets(ctx context.Context, cell string) {
+	tabletAliases, err := c.fromTS.GetTabletsByCell(ctx, cell)
+	if err != nil {
+		c.rec.RecordError(fmt.Errorf("fromTS.GetTabletsByCell(%v): %v", cell, err))
+		return
+	}
+	toTabletAliases, err := c.toTS.GetTabletsByCell(ctx, cell)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		c.rec.RecordError(fmt.Errorf("toTS.GetTabletsByCell(%v): %v", cell, err))
+		return
+	}
+	aliases := make([]string, len(tabletAliases))
+	for i, alias := range tabletAliases {
+		aliases[i] = topoproto.TabletAliasString(alias)
+	}
+	toAliases := make([]string, len(toTabletAliases))
+	for i, alias := range toTabletAliases {
+		toAliases[i] = topoproto.TabletAliasString(alias)
+	}
+	c.compareNames(fmt.Sprintf("tablets in %v", cell), aliases, toAliases)
+
+	for _, alias := range tabletAliases {
+		c.compareRecords(fmt.Sprintf("tablet %v", topoproto.TabletAliasString(alias)), func(ts *topo.Server) (proto.Message, error) {
+			ti, err := ts.GetTablet(ctx, alias)
+			if err != nil {
+				return nil, err
+			}
+			return ti.Tablet, nil
+		})
+	}
+}
+
+func (c *topoComparer) compareServingGraph(ctx context.Context, cell string) {
+	keyspaces, err := c.fromTS.GetSrvKeyspaceNames(ctx, cell)
+	if err != nil {
+		c.rec.RecordError(fmt.Errorf("fromTS.GetSrvKeyspaceNames(%v): %v", cell, err))
+		return
+	}
+	for _, keyspace := range keyspaces {
+		c.compareRecords(fmt.Sprintf("serving graph of %v in %v", keyspace, cell), func(ts *topo.Server) (proto.Message, error) {
+			return ts.GetSrvKeyspace(ctx, cell, keyspace)
+		})
+	}
+	c.compareRecords(fmt.Sprintf("serving vschema in %v", cell), func(ts *topo.Server) (proto.Message, error) {
+		return ts.GetSrvVSchema(ctx, cell)
+	})
+}
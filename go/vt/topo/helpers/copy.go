@@ -242,3 +242,57 @@ func CopyShardReplications(ctx context.Context, fromTS, toTS *topo.Server) {
 		log.Fatalf("copyShards failed: %v", rec.Error())
 	}
 }
+
+// CopyServingGraph will create the SrvKeyspace and SrvVSchema objects
+// in the destination topo.
+func CopyServingGraph(ctx context.Context, fromTS, toTS *topo.Server) {
+	cells, err := fromTS.GetKnownCells(ctx)
+	if err != nil {
+		log.Fatalf("fromTS.GetKnownCells: %v", err)
+	}
+
+	wg := sync.WaitGroup{}
+	rec := concurrency.AllErrorRecorder{}
+	for _, cell := range cells {
+		wg.Add(1)
+		go func(cell string) {
+			defer wg.Done()
+
+			keyspaces, err := fromTS.GetSrvKeyspaceNames(ctx, cell)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("GetSrvKeyspaceNames(%v): %v", cell, err))
+				return
+			}
+			for _, keyspace := range keyspaces {
+				srvKeyspace, err := fromTS.GetSrvKeyspace(ctx, cell, keyspace)
+				switch {
+				case err == nil:
+					if err := toTS.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace); err != nil {
+						rec.RecordError(fmt.Errorf("UpdateSrvKeyspace(%v, %v): %v", cell, keyspace, err))
+					}
+				case topo.IsErrType(err, topo.NoNode):
+					// The keyspace has tablets in this cell, but
+					// is not served there.
+				default:
+					rec.RecordError(fmt.Errorf("GetSrvKeyspace(%v, %v): %v", cell, keyspace, err))
+				}
+			}
+
+			srvVSchema, err := fromTS.GetSrvVSchema(ctx, cell)
+			switch {
+			case err == nil:
+				if err := toTS.UpdateSrvVSchema(ctx, cell, srvVSchema); err != nil {
+					rec.RecordError(fmt.Errorf("UpdateSrvVSchema(%v): %v", cell, err))
+				}
+			case topo.IsErrType(err, topo.NoNode):
+				// Nothing to do.
+			default:
+				rec.RecordError(fmt.Errorf("GetSrvVSchema(%v): %v", cell, err))
+			}
+		}(cell)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		log.Fatalf("copyServingGraph failed: %v", rec.Error())
+	}
+}
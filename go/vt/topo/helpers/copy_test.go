@@ -17,6 +17,7 @@ limitations under the License.
 package helpers
 
 import (
+	"strings"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -26,6 +27,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 )
 
 func createSetup(ctx context.Context, t *testing.T) (*topo.Server, *topo.Server) {
@@ -153,3 +155,46 @@ func TestBasic(t *testing.T) {
 	}
 	CopyTablets(ctx, fromTS, toTS)
 }
+
+func TestCopyServingGraphAndCompare(t *testing.T) {
+	ctx := context.Background()
+	fromTS, toTS := createSetup(ctx, t)
+	if err := fromTS.UpdateSrvKeyspace(ctx, "test_cell", "test_keyspace", &topodatapb.SrvKeyspace{ShardingColumnName: "id"}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+	if err := fromTS.UpdateSrvVSchema(ctx, "test_cell", &vschemapb.SrvVSchema{}); err != nil {
+		t.Fatalf("UpdateSrvVSchema failed: %v", err)
+	}
+
+	// Before the copy, everything is missing.
+	err := CompareTopos(ctx, fromTS, toTS)
+	if err == nil || !strings.Contains(err.Error(), "keyspaces: test_keyspace is missing in the destination") {
+		t.Errorf("CompareTopos before copy returned: %v", err)
+	}
+
+	CopyKeyspaces(ctx, fromTS, toTS)
+	CopyShards(ctx, fromTS, toTS)
+	CopyShardReplications(ctx, fromTS, toTS)
+	CopyTablets(ctx, fromTS, toTS)
+	CopyServingGraph(ctx, fromTS, toTS)
+
+	srvKeyspace, err := toTS.GetSrvKeyspace(ctx, "test_cell", "test_keyspace")
+	if err != nil || srvKeyspace.ShardingColumnName != "id" {
+		t.Errorf("GetSrvKeyspace of copied keyspace = %v, %v", srvKeyspace, err)
+	}
+	if err := CompareTopos(ctx, fromTS, toTS); err != nil {
+		t.Errorf("CompareTopos after copy failed: %v", err)
+	}
+
+	// A difference is detected.
+	if _, err := toTS.UpdateTabletFields(ctx, &topodatapb.TabletAlias{Cell: "test_cell", Uid: 234}, func(tablet *topodatapb.Tablet) error {
+		tablet.Type = topodatapb.TabletType_RDONLY
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateTabletFields failed: %v", err)
+	}
+	err = CompareTopos(ctx, fromTS, toTS)
+	if err == nil || !strings.Contains(err.Error(), "tablet test_cell-0000000234: differs") {
+		t.Errorf("CompareTopos after tablet change returned: %v", err)
+	}
+}
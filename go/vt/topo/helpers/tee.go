@@ -17,6 +17,9 @@ limitations under the License.
 package helpers
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
@@ -223,6 +226,61 @@ func (ld *teeTopoLockDescriptor) Unlock(ctx context.Context) error {
 	return ferr
 }
 
+// teeLockHolder identifies the holders of a lock on both sides.
+// An empty holder means that side is not locked.
+type teeLockHolder struct {
+	First  string
+	Second string
+}
+
+// ReadLock is part of the topo.Conn interface.
+// The contents come from the first side, and the holder identifies
+// the holders of both sides.
+func (c *TeeConn) ReadLock(ctx context.Context, dirPath string) (string, string, error) {
+	contents, fholder, err := c.lockFirst.ReadLock(ctx, dirPath)
+	if err != nil {
+		return "", "", err
+	}
+	_, sholder, err := c.lockSecond.ReadLock(ctx, dirPath)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return "", "", err
+	}
+	data, err := json.Marshal(&teeLockHolder{First: fholder, Second: sholder})
+	if err != nil {
+		return "", "", err
+	}
+	return contents, string(data), nil
+}
+
+// BreakLock is part of the topo.Conn interface.
+// It breaks the lock on both sides, as a crashed holder may have
+// only gotten one of them.
+func (c *TeeConn) BreakLock(ctx context.Context, dirPath, holder string) error {
+	h := &teeLockHolder{}
+	if err := json.Unmarshal([]byte(holder), h); err != nil {
+		return fmt.Errorf("bad lock holder %q: %v", holder, err)
+	}
+	serr := topo.NewError(topo.NoNode, dirPath)
+	if h.Second != "" {
+		serr = c.lockSecond.BreakLock(ctx, dirPath, h.Second)
+	}
+	ferr := c.lockFirst.BreakLock(ctx, dirPath, h.First)
+
+	if topo.IsErrType(serr, topo.NoNode) {
+		return ferr
+	}
+	if topo.IsErrType(ferr, topo.NoNode) {
+		return serr
+	}
+	if serr != nil {
+		if ferr != nil {
+			log.Warningf("First BreakLock(%v) failed: %v", dirPath, ferr)
+		}
+		return serr
+	}
+	return ferr
+}
+
 // NewMasterParticipation is part of the topo.Conn interface.
 func (c *TeeConn) NewMasterParticipation(name, id string) (topo.MasterParticipation, error) {
 	return c.primary.NewMasterParticipation(name, id)
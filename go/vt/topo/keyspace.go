@@ -261,6 +261,10 @@ func (ts *Server) DeleteKeyspace(ctx context.Context, keyspace string) error {
 	if err := ts.globalCell.Delete(ctx, keyspacePath, nil); err != nil {
 		return err
 	}
+	// Otherwise, the directory of the keyspace would not go away.
+	if err := ts.deleteLabels(ctx, pathForKeyspaceLabels(keyspace)); err != nil {
+		log.Warningf("cannot delete the labels of keyspace %v: %v", keyspace, err)
+	}
 	event.Dispatch(&events.KeyspaceChange{
 		KeyspaceName: keyspace,
 		Keyspace:     nil,
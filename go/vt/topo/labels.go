@@ -0,0 +1,144 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"golang.org/x/net/context"
+)
+
+// This file provides the utility methods to save / retrieve the labels
+// of keyspaces and shards. Labels are free-form key / value pairs set by
+// operators, like an owner, an environment or the state of a migration,
+// for external tools to read. Vitess itself doesn't use them. They are
+// stored next to the Keyspace and Shard records in the global cell, so
+// reading a keyspace or a shard doesn't cost an extra read.
+
+// LabelsFile is the name of the file in the directory of a keyspace or a
+// shard which has its labels.
+const LabelsFile = "Labels"
+
+func pathForKeyspaceLabels(keyspace string) string {
+	return path.Join(KeyspacesPath, keyspace, LabelsFile)
+}
+
+func pathForShardLabels(keyspace, shard string) string {
+	return path.Join(KeyspacesPath, keyspace, ShardsPath, shard, LabelsFile)
+}
+
+// GetKeyspaceLabels returns the labels of a keyspace. It returns an empty
+// map if the keyspace has no label.
+func (ts *Server) GetKeyspaceLabels(ctx context.Context, keyspace string) (map[string]string, error) {
+	labels, _, err := ts.getLabels(ctx, pathForKeyspaceLabels(keyspace))
+	return labels, err
+}
+
+// UpdateKeyspaceLabels calls update on the labels of the keyspace, and
+// saves them. It retries if they were changed concurrently. It returns a
+// NoNode error if the keyspace doesn't exist.
+func (ts *Server) UpdateKeyspaceLabels(ctx context.Context, keyspace string, update func(map[string]string) error) (map[string]string, error) {
+	if _, err := ts.GetKeyspace(ctx, keyspace); err != nil {
+		return nil, err
+	}
+	return ts.updateLabels(ctx, pathForKeyspaceLabels(keyspace), update)
+}
+
+// GetShardLabels returns the labels of a shard. It returns an empty map
+// if the shard has no label.
+func (ts *Server) GetShardLabels(ctx context.Context, keyspace, shard string) (map[string]string, error) {
+	labels, _, err := ts.getLabels(ctx, pathForShardLabels(keyspace, shard))
+	return labels, err
+}
+
+// UpdateShardLabels calls update on the labels of the shard, and saves
+// them. It retries if they were changed concurrently. It returns a NoNode
+// error if the shard doesn't exist.
+func (ts *Server) UpdateShardLabels(ctx context.Context, keyspace, shard string, update func(map[string]string) error) (map[string]string, error) {
+	if _, err := ts.GetShard(ctx, keyspace, shard); err != nil {
+		return nil, err
+	}
+	return ts.updateLabels(ctx, pathForShardLabels(keyspace, shard), update)
+}
+
+// deleteLabels removes a labels file. It does not return an error if
+// there is none.
+func (ts *Server) deleteLabels(ctx context.Context, filePath string) error {
+	err := ts.globalCell.Delete(ctx, filePath, nil)
+	if IsErrType(err, NoNode) {
+		return nil
+	}
+	return err
+}
+
+// getLabels reads a labels file. The version is nil if there is none.
+func (ts *Server) getLabels(ctx context.Context, filePath string) (map[string]string, Version, error) {
+	data, version, err := ts.globalCell.Get(ctx, filePath)
+	switch {
+	case err == nil:
+	case IsErrType(err, NoNode):
+		return make(map[string]string), nil, nil
+	default:
+		return nil, nil, err
+	}
+	labels := make(map[string]string)
+	if err := json.Unmarshal(data, &labels); err != nil {
+		return nil, nil, fmt.Errorf("bad labels data in %v: %v", filePath, err)
+	}
+	return labels, version, nil
+}
+
+// updateLabels is the read-modify-write loop of the Update*Labels
+// methods. A file without labels is deleted, so it doesn't keep the
+// directory of a deleted keyspace or shard around.
+func (ts *Server) updateLabels(ctx context.Context, filePath string, update func(map[string]string) error) (map[string]string, error) {
+	for {
+		labels, version, err := ts.getLabels(ctx, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := update(labels); err != nil {
+			if IsErrType(err, NoUpdateNeeded) {
+				return labels, nil
+			}
+			return nil, err
+		}
+
+		switch {
+		case len(labels) == 0 && version == nil:
+			return labels, nil
+		case len(labels) == 0:
+			err = ts.globalCell.Delete(ctx, filePath, version)
+		default:
+			data, mErr := json.MarshalIndent(labels, "", "  ")
+			if mErr != nil {
+				return nil, mErr
+			}
+			if version == nil {
+				_, err = ts.globalCell.Create(ctx, filePath, data)
+			} else {
+				_, err = ts.globalCell.Update(ctx, filePath, data, version)
+			}
+		}
+		// Retry if the labels were changed since we read them.
+		if !IsErrType(err, BadVersion) && !IsErrType(err, NodeExists) && !IsErrType(err, NoNode) {
+			return labels, err
+		}
+	}
+}
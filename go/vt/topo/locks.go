@@ -43,6 +43,11 @@ var (
 	// LockTimeout is the command line flag that introduces a shorter
 	// timeout for locking topology structures.
 	LockTimeout = flag.Duration("lock_timeout", DefaultLockTimeout, "timeout for acquiring topology locks")
+
+	// LockTTL is the command line flag for how long a lock is
+	// expected to be held at most. It is recorded in the lock, so
+	// locks left behind by crashed processes can be identified.
+	LockTTL = flag.Duration("lock_ttl", time.Hour, "expected maximum time a topology lock is held, after which it is considered stale and can be force-unlocked")
 )
 
 // Lock describes a long-running lock on a keyspace or a shard.
@@ -52,8 +57,13 @@ type Lock struct {
 	Action   string
 	HostName string
 	UserName string
+	PID      int
 	Time     string
 
+	// TTL is how long the lock is expected to be held at most,
+	// as a time.Duration string.
+	TTL string
+
 	// Status is the current status of the Lock.
 	Status string
 }
@@ -64,7 +74,9 @@ func newLock(action string) *Lock {
 		Action:   action,
 		HostName: "unknown",
 		UserName: "unknown",
+		PID:      os.Getpid(),
 		Time:     time.Now().Format(time.RFC3339),
+		TTL:      LockTTL.String(),
 		Status:   "Running",
 	}
 	if h, err := os.Hostname(); err == nil {
@@ -85,6 +97,21 @@ func (l *Lock) ToJSON() (string, error) {
 	return string(data), nil
 }
 
+// Expired returns true if the lock was taken more than its TTL ago
+// at the provided time. Locks with no or an invalid TTL, for instance
+// from older processes, never expire.
+func (l *Lock) Expired(now time.Time) bool {
+	start, err := time.Parse(time.RFC3339, l.Time)
+	if err != nil {
+		return false
+	}
+	ttl, err := time.ParseDuration(l.TTL)
+	if err != nil || ttl <= 0 {
+		return false
+	}
+	return now.After(start.Add(ttl))
+}
+
 // lockInfo is an individual info structure for a lock
 type lockInfo struct {
 	lockDescriptor LockDescriptor
@@ -393,3 +420,29 @@ func (l *Lock) unlockShard(ctx context.Context, ts *Server, keyspace, shard stri
 	}
 	return lockDescriptor.Unlock(ctx)
 }
+
+// GetShardLock returns the Lock currently held on a shard, and the
+// identifier of its holder for ForceUnlockShard.
+// Returns ErrNoNode if the shard is not locked.
+func (ts *Server) GetShardLock(ctx context.Context, keyspace, shard string) (*Lock, string, error) {
+	shardPath := path.Join(KeyspacesPath, keyspace, ShardsPath, shard)
+	contents, holder, err := ts.globalCell.ReadLock(ctx, shardPath)
+	if err != nil {
+		return nil, "", err
+	}
+	l := &Lock{}
+	if err := json.Unmarshal([]byte(contents), l); err != nil {
+		return nil, "", fmt.Errorf("bad lock contents for shard %v/%v: %v", keyspace, shard, err)
+	}
+	return l, holder, nil
+}
+
+// ForceUnlockShard breaks the lock held on a shard by holder, as
+// returned by GetShardLock. It is meant to clean up locks left behind
+// by crashed processes, and should only be used when the holder
+// is known to be gone.
+// Returns ErrNoNode if holder doesn't hold the lock any more.
+func (ts *Server) ForceUnlockShard(ctx context.Context, keyspace, shard, holder string) error {
+	shardPath := path.Join(KeyspacesPath, keyspace, ShardsPath, shard)
+	return ts.globalCell.BreakLock(ctx, shardPath, holder)
+}
@@ -18,6 +18,7 @@ package memorytopo
 
 import (
 	"fmt"
+	"strconv"
 
 	"golang.org/x/net/context"
 
@@ -39,6 +40,10 @@ func convertError(err error, nodePath string) error {
 type memoryTopoLockDescriptor struct {
 	c       *Conn
 	dirPath string
+
+	// lock is the channel we put in the node when we got the lock.
+	// If it is not there any more, the lock was broken.
+	lock chan struct{}
 }
 
 // Lock is part of the topo.Conn interface.
@@ -71,28 +76,75 @@ func (c *Conn) Lock(ctx context.Context, dirPath, contents string) (topo.LockDes
 		}
 
 		// Noone has the lock, grab it.
-		n.lock = make(chan struct{})
+		l := make(chan struct{})
+		n.lock = l
 		n.lockContents = contents
+		n.lockID = c.factory.getNextVersion()
 		c.factory.mu.Unlock()
 		return &memoryTopoLockDescriptor{
 			c:       c,
 			dirPath: dirPath,
+			lock:    l,
 		}, nil
 	}
 }
 
 // Check is part of the topo.LockDescriptor interface.
-// We can never lose a lock in this implementation.
+// We can only lose a lock in this implementation if it is broken.
 func (ld *memoryTopoLockDescriptor) Check(ctx context.Context) error {
+	ld.c.factory.mu.Lock()
+	defer ld.c.factory.mu.Unlock()
+
+	n := ld.c.factory.nodeByPath(ld.c.cell, ld.dirPath)
+	if n == nil || n.lock != ld.lock {
+		return fmt.Errorf("lock on %v was lost", ld.dirPath)
+	}
 	return nil
 }
 
 // Unlock is part of the topo.LockDescriptor interface.
 func (ld *memoryTopoLockDescriptor) Unlock(ctx context.Context) error {
-	return ld.c.unlock(ctx, ld.dirPath)
+	return ld.c.unlock(ctx, ld.dirPath, ld.lock)
+}
+
+// ReadLock is part of the topo.Conn interface.
+// The holder is the id we gave to the lock when it was taken.
+func (c *Conn) ReadLock(ctx context.Context, dirPath string) (string, string, error) {
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+
+	if c.factory.err != nil {
+		return "", "", c.factory.err
+	}
+
+	n := c.factory.nodeByPath(c.cell, dirPath)
+	if n == nil || n.lock == nil {
+		return "", "", topo.NewError(topo.NoNode, dirPath)
+	}
+	return n.lockContents, strconv.FormatUint(n.lockID, 10), nil
 }
 
-func (c *Conn) unlock(ctx context.Context, dirPath string) error {
+// BreakLock is part of the topo.Conn interface.
+func (c *Conn) BreakLock(ctx context.Context, dirPath, holder string) error {
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+
+	if c.factory.err != nil {
+		return c.factory.err
+	}
+
+	n := c.factory.nodeByPath(c.cell, dirPath)
+	if n == nil || n.lock == nil || strconv.FormatUint(n.lockID, 10) != holder {
+		return topo.NewError(topo.NoNode, dirPath)
+	}
+	close(n.lock)
+	n.lock = nil
+	n.lockContents = ""
+	return nil
+}
+
+// unlock releases the lock on dirPath, if it is still the provided one.
+func (c *Conn) unlock(ctx context.Context, dirPath string, l chan struct{}) error {
 	c.factory.mu.Lock()
 	defer c.factory.mu.Unlock()
 
@@ -103,6 +155,9 @@ func (c *Conn) unlock(ctx context.Context, dirPath string) error {
 	if n.lock == nil {
 		return fmt.Errorf("node %v is not locked", dirPath)
 	}
+	if n.lock != l {
+		return fmt.Errorf("lock on node %v was broken", dirPath)
+	}
 	close(n.lock)
 	n.lock = nil
 	n.lockContents = ""
@@ -146,6 +146,9 @@ type node struct {
 	// For regular locks, it has the contents that was passed in.
 	// For master election, it has the id of the election leader.
 	lockContents string
+
+	// lockID identifies the current holder of the lock.
+	lockID uint64
 }
 
 func (n *node) isDirectory() bool {
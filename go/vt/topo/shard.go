@@ -349,6 +349,13 @@ func (ts *Server) DeleteShard(ctx context.Context, keyspace, shard string) error
 	if err := ts.globalCell.Delete(ctx, shardPath, nil); err != nil {
 		return err
 	}
+	// Otherwise, the directory of the shard would not go away.
+	if err := ts.DeleteShardDiffVerification(ctx, keyspace, shard); err != nil {
+		log.Warningf("cannot delete the diff verification of shard %v/%v: %v", keyspace, shard, err)
+	}
+	if err := ts.deleteLabels(ctx, pathForShardLabels(keyspace, shard)); err != nil {
+		log.Warningf("cannot delete the labels of shard %v/%v: %v", keyspace, shard, err)
+	}
 	event.Dispatch(&events.ShardChange{
 		KeyspaceName: keyspace,
 		ShardName:    shard,
@@ -382,6 +389,13 @@ func (si *ShardInfo) UpdateSourceBlacklistedTables(ctx context.Context, tabletTy
 	if err := CheckKeyspaceLocked(ctx, si.keyspace); err != nil {
 		return err
 	}
+	return si.SetSourceBlacklistedTables(tabletType, cells, remove, tables)
+}
+
+// SetSourceBlacklistedTables is UpdateSourceBlacklistedTables without the
+// check of the keyspace lock. It can be used on a copy of the shard record
+// which is not written, e.g. to compute the changes of a dry run.
+func (si *ShardInfo) SetSourceBlacklistedTables(tabletType topodatapb.TabletType, cells []string, remove bool, tables []string) error {
 	tc := si.GetTabletControl(tabletType)
 	if tc == nil {
 		// handle the case where the TabletControl object is new
@@ -430,6 +444,13 @@ func (si *ShardInfo) UpdateDisableQueryService(ctx context.Context, tabletType t
 	if err := CheckKeyspaceLocked(ctx, si.keyspace); err != nil {
 		return err
 	}
+	return si.SetDisableQueryService(tabletType, cells, disableQueryService)
+}
+
+// SetDisableQueryService is UpdateDisableQueryService without the check of
+// the keyspace lock. It can be used on a copy of the shard record which is
+// not written, e.g. to compute the changes of a dry run.
+func (si *ShardInfo) SetDisableQueryService(tabletType topodatapb.TabletType, cells []string, disableQueryService bool) error {
 	tc := si.GetTabletControl(tabletType)
 	if tc == nil {
 		// handle the case where the TabletControl object is new
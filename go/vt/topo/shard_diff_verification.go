@@ -0,0 +1,89 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// This file provides the utility methods to save / retrieve the result of
+// the last successful diff of a destination shard. The record is stored
+// next to the Shard record in the global cell.
+
+// ShardDiffVerificationFile is the name of the file in the directory of a
+// shard which has the ShardDiffVerification.
+const ShardDiffVerificationFile = "DiffVerification"
+
+func pathForShardDiffVerification(keyspace, shard string) string {
+	return path.Join(KeyspacesPath, keyspace, ShardsPath, shard, ShardDiffVerificationFile)
+}
+
+// ShardDiffVerification describes the last diff (e.g. vtworker SplitDiff)
+// which found no differences between a destination shard and its source.
+// MigrateServedTypes and MigrateServedFrom warn when traffic is migrated
+// to a shard without one.
+type ShardDiffVerification struct {
+	// Command is the name of the command which ran the diff.
+	Command string
+	// Source is the "keyspace/shard" the shard was compared against.
+	Source string
+	// Position is the replication position of the destination master at
+	// which the destination shard was compared.
+	Position string
+	// Time is set when the diff finished.
+	Time time.Time
+}
+
+// UpdateShardDiffVerification stores "verification" as the last successful
+// diff of the shard. It overwrites any previous one.
+func (ts *Server) UpdateShardDiffVerification(ctx context.Context, keyspace, shard string, verification *ShardDiffVerification) error {
+	data, err := json.MarshalIndent(verification, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, pathForShardDiffVerification(keyspace, shard), data, nil)
+	return err
+}
+
+// GetShardDiffVerification returns the last successful diff of the shard.
+// It returns a NoNode error if the shard was never verified.
+func (ts *Server) GetShardDiffVerification(ctx context.Context, keyspace, shard string) (*ShardDiffVerification, error) {
+	data, _, err := ts.globalCell.Get(ctx, pathForShardDiffVerification(keyspace, shard))
+	if err != nil {
+		return nil, err
+	}
+	verification := &ShardDiffVerification{}
+	if err := json.Unmarshal(data, verification); err != nil {
+		return nil, fmt.Errorf("GetShardDiffVerification(%v,%v): bad data: %v", keyspace, shard, err)
+	}
+	return verification, nil
+}
+
+// DeleteShardDiffVerification removes the last successful diff of the
+// shard. It does not return an error if there is none.
+func (ts *Server) DeleteShardDiffVerification(ctx context.Context, keyspace, shard string) error {
+	err := ts.globalCell.Delete(ctx, pathForShardDiffVerification(keyspace, shard), nil)
+	if IsErrType(err, NoNode) {
+		return nil
+	}
+	return err
+}
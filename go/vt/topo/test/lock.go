@@ -53,6 +53,9 @@ func checkLock(t *testing.T, ts *topo.Server) {
 
 	t.Log("===      checkLockUnblocks")
 	checkLockUnblocks(ctx, t, conn)
+
+	t.Log("===      checkLockBreak")
+	checkLockBreak(ctx, t, conn)
 }
 
 func checkLockTimeout(ctx context.Context, t *testing.T, conn topo.Conn) {
@@ -173,3 +176,57 @@ func checkLockUnblocks(ctx context.Context, t *testing.T, conn topo.Conn) {
 		t.Fatalf("unlocking timed out")
 	}
 }
+
+// checkLockBreak makes sure we can read and break a lock held by
+// someone else, and take it again afterwards. Breaking it again
+// with the old holder must not break the new lock.
+func checkLockBreak(ctx context.Context, t *testing.T, conn topo.Conn) {
+	keyspacePath := path.Join(topo.KeyspacesPath, "test_keyspace")
+
+	// Nothing to read or break if not locked.
+	if _, _, err := conn.ReadLock(ctx, keyspacePath); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("ReadLock(not locked): %v", err)
+	}
+
+	lockDescriptor, err := conn.Lock(ctx, keyspacePath, "holder")
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	contents, holder, err := conn.ReadLock(ctx, keyspacePath)
+	if err != nil || contents != "holder" {
+		t.Fatalf("ReadLock: got (%v, %v), want (holder, nil)", contents, err)
+	}
+
+	if err := conn.BreakLock(ctx, keyspacePath, holder); err != nil {
+		t.Fatalf("BreakLock: %v", err)
+	}
+	if _, _, err := conn.ReadLock(ctx, keyspacePath); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("ReadLock(broken): %v", err)
+	}
+	if err := conn.BreakLock(ctx, keyspacePath, holder); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("BreakLock(broken): %v", err)
+	}
+
+	// The holder doesn't know yet, and will try to unlock. This may
+	// or may not fail depending on the implementation.
+	lockDescriptor.Unlock(ctx)
+
+	// We can get the lock again.
+	fastCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	lockDescriptor, err = conn.Lock(fastCtx, keyspacePath, "again")
+	if err != nil {
+		t.Fatalf("Lock(again): %v", err)
+	}
+
+	// The old holder can't be used to break the new lock.
+	if err := conn.BreakLock(ctx, keyspacePath, holder); !topo.IsErrType(err, topo.NoNode) {
+		t.Fatalf("BreakLock(old holder): %v", err)
+	}
+	if contents, _, err := conn.ReadLock(ctx, keyspacePath); err != nil || contents != "again" {
+		t.Fatalf("ReadLock(again): got (%v, %v), want (again, nil)", contents, err)
+	}
+	if err := lockDescriptor.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock(again): %v", err)
+	}
+}
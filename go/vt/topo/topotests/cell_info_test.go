@@ -18,6 +18,8 @@ package topotests
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -107,3 +109,33 @@ func TestCellInfo(t *testing.T) {
 		t.Fatalf("GetCellInfo(non-existing cell) failed: %v", err)
 	}
 }
+
+func TestGetCellsInRegion(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1", "cell2", "cell3", "region2")
+	for cell, region := range map[string]string{"cell1": "region1", "cell2": "region1"} {
+		if err := ts.UpdateCellInfoFields(ctx, cell, func(ci *topodatapb.CellInfo) error {
+			ci.Region = region
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateCellInfoFields(%v) failed: %v", cell, err)
+		}
+	}
+
+	for region, want := range map[string][]string{
+		"region1": {"cell1", "cell2"},
+		// A cell without a region is its own region.
+		"cell3":   {"cell3"},
+		"region2": {"region2"},
+		"unknown": nil,
+	} {
+		cells, err := ts.GetCellsInRegion(ctx, region)
+		if err != nil {
+			t.Fatalf("GetCellsInRegion(%v) failed: %v", region, err)
+		}
+		sort.Strings(cells)
+		if !reflect.DeepEqual(cells, want) {
+			t.Errorf("GetCellsInRegion(%v) = %v, want %v", region, cells, want)
+		}
+	}
+}
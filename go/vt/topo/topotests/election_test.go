@@ -0,0 +1,136 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// masterRecorder records which participants are running as master.
+type masterRecorder struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func (r *masterRecorder) run(id string) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		r.mu.Lock()
+		r.running[id] = true
+		r.mu.Unlock()
+
+		<-ctx.Done()
+
+		r.mu.Lock()
+		delete(r.running, id)
+		r.mu.Unlock()
+	}
+}
+
+// waitForMaster waits until id is the only participant running.
+func (r *masterRecorder) waitForMaster(t *testing.T, id string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r.mu.Lock()
+		done := len(r.running) == 1 && r.running[id]
+		r.mu.Unlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %v to be the master", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunAsMaster(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1", "cell2")
+	r := &masterRecorder{running: make(map[string]bool)}
+
+	// Start two participants in cell1.
+	wg := sync.WaitGroup{}
+	mps := make(map[string]topo.MasterParticipation)
+	for _, id := range []string{"id1", "id2"} {
+		mp, err := ts.NewMasterParticipation(ctx, "cell1", "test", id)
+		if err != nil {
+			t.Fatalf("NewMasterParticipation(%v) failed: %v", id, err)
+		}
+		mps[id] = mp
+		wg.Add(1)
+		go func(id string, mp topo.MasterParticipation) {
+			defer wg.Done()
+			topo.RunAsMaster(mp, 10*time.Millisecond, r.run(id))
+		}(id, mp)
+
+		if id == "id1" {
+			// Make sure id1 wins the election.
+			r.waitForMaster(t, "id1")
+		}
+	}
+	if id, err := mps["id2"].GetCurrentMasterID(ctx); err != nil || id != "id1" {
+		t.Errorf("GetCurrentMasterID: got %v, %v, want id1", id, err)
+	}
+
+	// id2 doesn't run while id1 is the master.
+	time.Sleep(50 * time.Millisecond)
+	r.mu.Lock()
+	if len(r.running) != 1 {
+		t.Errorf("got masters %v, want only id1", r.running)
+	}
+	r.mu.Unlock()
+
+	// Stopping the master hands over to the other participant.
+	mps["id1"].Stop()
+	r.waitForMaster(t, "id2")
+
+	// The elections of different cells are independent.
+	mp3, err := ts.NewMasterParticipation(ctx, "cell2", "test", "id3")
+	if err != nil {
+		t.Fatalf("NewMasterParticipation(id3) failed: %v", err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		topo.RunAsMaster(mp3, 10*time.Millisecond, r.run("id3"))
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r.mu.Lock()
+		done := r.running["id2"] && r.running["id3"]
+		r.mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for id2 and id3 to be the masters")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// RunAsMaster returns once the participants are stopped.
+	mps["id2"].Stop()
+	mp3.Stop()
+	wg.Wait()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestKeyspaceAndShardLabels(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	set := func(changes map[string]string) func(map[string]string) error {
+		return func(labels map[string]string) error {
+			for k, v := range changes {
+				labels[k] = v
+			}
+			return nil
+		}
+	}
+
+	// Labels can only be set on existing keyspaces and shards.
+	if _, err := ts.UpdateKeyspaceLabels(ctx, "ks", set(map[string]string{"owner": "team1"})); !topo.IsErrType(err, topo.NoNode) {
+		t.Errorf("UpdateKeyspaceLabels on a missing keyspace returned %v, want NoNode", err)
+	}
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	if err := ts.CreateShard(ctx, "ks", "0"); err != nil {
+		t.Fatalf("CreateShard failed: %v", err)
+	}
+
+	// No labels to start with.
+	labels, err := ts.GetKeyspaceLabels(ctx, "ks")
+	if err != nil || len(labels) != 0 {
+		t.Errorf("GetKeyspaceLabels: got %v, %v, want no labels", labels, err)
+	}
+
+	// Set and read back labels.
+	if _, err := ts.UpdateKeyspaceLabels(ctx, "ks", set(map[string]string{"owner": "team1", "env": "prod"})); err != nil {
+		t.Fatalf("UpdateKeyspaceLabels failed: %v", err)
+	}
+	if _, err := ts.UpdateShardLabels(ctx, "ks", "0", set(map[string]string{"migration": "split_started"})); err != nil {
+		t.Fatalf("UpdateShardLabels failed: %v", err)
+	}
+	labels, err = ts.GetKeyspaceLabels(ctx, "ks")
+	if want := map[string]string{"owner": "team1", "env": "prod"}; err != nil || !reflect.DeepEqual(labels, want) {
+		t.Errorf("GetKeyspaceLabels: got %v, %v, want %v", labels, err, want)
+	}
+	labels, err = ts.GetShardLabels(ctx, "ks", "0")
+	if want := map[string]string{"migration": "split_started"}; err != nil || !reflect.DeepEqual(labels, want) {
+		t.Errorf("GetShardLabels: got %v, %v, want %v", labels, err, want)
+	}
+
+	// The labels don't show up as shards or keyspaces.
+	shards, err := ts.GetShardNames(ctx, "ks")
+	if err != nil || !reflect.DeepEqual(shards, []string{"0"}) {
+		t.Errorf("GetShardNames: got %v, %v", shards, err)
+	}
+
+	// The labels go away with the shard and the keyspace.
+	if err := ts.DeleteShard(ctx, "ks", "0"); err != nil {
+		t.Fatalf("DeleteShard failed: %v", err)
+	}
+	if err := ts.DeleteKeyspace(ctx, "ks"); err != nil {
+		t.Fatalf("DeleteKeyspace failed: %v", err)
+	}
+	keyspaces, err := ts.GetKeyspaces(ctx)
+	if err != nil || len(keyspaces) != 0 {
+		t.Errorf("GetKeyspaces after deletion: got %v, %v, want none", keyspaces, err)
+	}
+}
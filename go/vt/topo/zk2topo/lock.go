@@ -19,6 +19,7 @@ package zk2topo
 import (
 	"fmt"
 	"path"
+	"sort"
 
 	"github.com/samuel/go-zookeeper/zk"
 	"golang.org/x/net/context"
@@ -107,3 +108,46 @@ func (ld *zkLockDescriptor) Check(ctx context.Context) error {
 func (ld *zkLockDescriptor) Unlock(ctx context.Context) error {
 	return ld.zs.Delete(ctx, ld.nodePath, nil)
 }
+
+// lockHolder returns the full path of the current holder of the lock on
+// the given directory, i.e. the first sequential node in its locks directory.
+func (zs *Server) lockHolder(ctx context.Context, dirPath string) (string, error) {
+	locksDir := path.Join(zs.root, dirPath, locksPath)
+	children, _, err := zs.conn.Children(ctx, locksDir)
+	if err != nil {
+		return "", convertError(err, locksDir)
+	}
+	if len(children) == 0 {
+		return "", topo.NewError(topo.NoNode, locksDir)
+	}
+	sort.Strings(children)
+	return path.Join(locksDir, children[0]), nil
+}
+
+// ReadLock is part of the topo.Conn interface.
+// The holder is the path of its ephemeral node.
+func (zs *Server) ReadLock(ctx context.Context, dirPath string) (string, string, error) {
+	nodePath, err := zs.lockHolder(ctx, dirPath)
+	if err != nil {
+		return "", "", err
+	}
+	data, _, err := zs.conn.Get(ctx, nodePath)
+	if err != nil {
+		return "", "", convertError(err, nodePath)
+	}
+	return string(data), nodePath, nil
+}
+
+// BreakLock is part of the topo.Conn interface.
+// We delete the ephemeral node of the holder, the next waiter
+// in the queue will then get the lock. The sequential node names
+// are never reused, so this can't delete a newer lock.
+func (zs *Server) BreakLock(ctx context.Context, dirPath, holder string) error {
+	if path.Dir(holder) != path.Join(zs.root, dirPath, locksPath) {
+		return topo.NewError(topo.NoNode, holder)
+	}
+	if err := zs.conn.Delete(ctx, holder, -1); err != nil {
+		return convertError(err, holder)
+	}
+	return nil
+}
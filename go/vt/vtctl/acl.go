@@ -0,0 +1,154 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/callerid"
+)
+
+// This file contains the access control for destructive vtctl commands.
+//
+// The ACL file is a JSON object mapping an action group to the list of
+// users allowed to run the commands of that group, for instance:
+//   {
+//     "reparent": ["alice", "bob"],
+//     "delete": ["alice"],
+//     "schema": ["*"]
+//   }
+// "*" allows any caller. When an ACL file is configured, a group that is
+// not listed cannot be run by anybody. Commands that are not part of any
+// group are never restricted.
+
+var aclFile = flag.String("vtctl_acl_file", "", "JSON file mapping the destructive vtctl action groups (reparent, delete, schema) to the users allowed to run them. If empty, no restriction is applied.")
+
+const (
+	// ActionGroupReparent is for the commands that change the master of a shard.
+	ActionGroupReparent = "reparent"
+	// ActionGroupDelete is for the commands that delete topology objects.
+	ActionGroupDelete = "delete"
+	// ActionGroupSchema is for the commands that change schemas and vschemas.
+	ActionGroupSchema = "schema"
+
+	// anyUser in an ACL list allows every caller.
+	anyUser = "*"
+)
+
+// actionGroups maps the lower case name of the restricted commands to
+// their action group.
+var actionGroups = map[string]string{
+	"initshardmaster":            ActionGroupReparent,
+	"plannedreparentshard":       ActionGroupReparent,
+	"emergencyreparentshard":     ActionGroupReparent,
+	"tabletexternallyreparented": ActionGroupReparent,
+
+	"deletetablet":           ActionGroupDelete,
+	"deleteshard":            ActionGroupDelete,
+	"deletekeyspace":         ActionGroupDelete,
+	"removeshardcell":        ActionGroupDelete,
+	"removekeyspacecell":     ActionGroupDelete,
+	"shardreplicationremove": ActionGroupDelete,
+	"sourcesharddelete":      ActionGroupDelete,
+	"updateshardrecord":      ActionGroupDelete,
+	"forceunlockshard":       ActionGroupDelete,
+	"deletesrvvschema":       ActionGroupDelete,
+	"deletecellinfo":         ActionGroupDelete,
+
+	"applyschema":       ActionGroupSchema,
+	"applyschemaonline": ActionGroupSchema,
+	"copyschemashard":   ActionGroupSchema,
+	"applyvschema":      ActionGroupSchema,
+}
+
+var (
+	aclMu     sync.Mutex
+	aclLoaded bool
+	aclGroups map[string][]string
+	aclErr    error
+)
+
+// loadACL reads the ACL file the first time it is needed.
+func loadACL() (map[string][]string, error) {
+	aclMu.Lock()
+	defer aclMu.Unlock()
+	if !aclLoaded {
+		aclGroups, aclErr = readACL(*aclFile)
+		aclLoaded = true
+	}
+	return aclGroups, aclErr
+}
+
+// readACL parses an ACL file. It returns a nil map if no file is given.
+func readACL(filename string) (map[string][]string, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read vtctl_acl_file: %v", err)
+	}
+	groups := make(map[string][]string)
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("cannot parse vtctl_acl_file %v: %v", filename, err)
+	}
+	return groups, nil
+}
+
+// CallerUsername returns the authenticated user of the context, stored
+// as its immediate caller id. It returns an empty string if the caller
+// is unknown. The effective caller id is not used, as it is provided by
+// the client and is not authenticated.
+func CallerUsername(ctx context.Context) string {
+	return callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx))
+}
+
+// CheckActionAccess returns an error if the caller of the context is not
+// allowed to run the given command.
+func CheckActionAccess(ctx context.Context, action string) error {
+	groups, err := loadACL()
+	if err != nil {
+		return err
+	}
+	return checkActionAccess(groups, CallerUsername(ctx), action)
+}
+
+func checkActionAccess(groups map[string][]string, username, action string) error {
+	if groups == nil {
+		return nil
+	}
+	group, ok := actionGroups[strings.ToLower(action)]
+	if !ok {
+		return nil
+	}
+	for _, user := range groups[group] {
+		if user == anyUser || (username != "" && user == username) {
+			return nil
+		}
+	}
+	if username == "" {
+		return fmt.Errorf("access denied: %v requires an authenticated user allowed for %v actions", action, group)
+	}
+	return fmt.Errorf("access denied: user %q is not allowed to run %v actions like %v", username, group, action)
+}
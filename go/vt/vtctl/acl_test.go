@@ -0,0 +1,152 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/callerid"
+)
+
+func TestCheckActionAccess(t *testing.T) {
+	groups := map[string][]string{
+		ActionGroupReparent: {"alice", "bob"},
+		ActionGroupSchema:   {"*"},
+	}
+	testcases := []struct {
+		username string
+		action   string
+		allowed  bool
+	}{
+		// Unrestricted commands.
+		{"", "GetTablet", true},
+		{"carol", "ListAllTablets", true},
+		// Reparents are limited to alice and bob.
+		{"alice", "PlannedReparentShard", true},
+		{"bob", "emergencyreparentshard", true},
+		{"carol", "PlannedReparentShard", false},
+		{"", "InitShardMaster", false},
+		// Deletes are not listed, so nobody can run them.
+		{"alice", "DeleteShard", false},
+		// Anybody can change schemas.
+		{"", "ApplySchema", true},
+		{"carol", "ApplyVSchema", true},
+	}
+	for _, tc := range testcases {
+		err := checkActionAccess(groups, tc.username, tc.action)
+		if got := err == nil; got != tc.allowed {
+			t.Errorf("checkActionAccess(%q, %q) = %v, want allowed=%v", tc.username, tc.action, err, tc.allowed)
+		}
+	}
+
+	// Without an ACL file, everything is allowed.
+	if err := checkActionAccess(nil, "", "DeleteKeyspace"); err != nil {
+		t.Errorf("checkActionAccess without ACLs failed: %v", err)
+	}
+}
+
+func TestReadACL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vtctl_acl_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if groups, err := readACL(""); err != nil || groups != nil {
+		t.Errorf("readACL(\"\") = %v, %v, want nil, nil", groups, err)
+	}
+
+	filename := path.Join(dir, "acl.json")
+	if err := ioutil.WriteFile(filename, []byte(`{"delete": ["alice"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	groups, err := readACL(filename)
+	if err != nil {
+		t.Fatalf("readACL failed: %v", err)
+	}
+	if want := map[string][]string{"delete": {"alice"}}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("readACL = %v, want %v", groups, want)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte(`["alice"]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readACL(filename); err == nil {
+		t.Errorf("readACL should have failed on an invalid file")
+	}
+}
+
+func TestCallerUsername(t *testing.T) {
+	ctx := context.Background()
+	if got := CallerUsername(ctx); got != "" {
+		t.Errorf("CallerUsername() = %q, want empty", got)
+	}
+	// The effective caller id is not authenticated, and is ignored.
+	ctx = callerid.NewContext(ctx, callerid.NewEffectiveCallerID("mallory", "", ""), nil)
+	if got := CallerUsername(ctx); got != "" {
+		t.Errorf("CallerUsername() = %q, want empty", got)
+	}
+	ctx = callerid.NewContext(ctx, nil, callerid.NewImmediateCallerID("alice"))
+	if got := CallerUsername(ctx); got != "alice" {
+		t.Errorf("CallerUsername() = %q, want alice", got)
+	}
+}
+
+func TestWriteAuditEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	start := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []*AuditEntry{{
+		Time:     start,
+		User:     "alice",
+		Command:  "DeleteShard",
+		Args:     []string{"-recursive", "ks/0"},
+		Outcome:  AuditOutcomeSuccess,
+		Duration: time.Second,
+	}, {
+		Time:    start,
+		Command: "DeleteKeyspace",
+		Args:    []string{"ks"},
+		Outcome: AuditOutcomeDenied,
+		Error:   "access denied",
+	}}
+	for _, entry := range entries {
+		if err := writeAuditEntry(buf, entry); err != nil {
+			t.Fatalf("writeAuditEntry failed: %v", err)
+		}
+	}
+
+	// The log has one JSON object per line.
+	dec := json.NewDecoder(buf)
+	for _, want := range entries {
+		got := &AuditEntry{}
+		if err := dec.Decode(got); err != nil {
+			t.Fatalf("cannot decode audit entry: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("audit entry: got %+v, want %+v", got, want)
+		}
+	}
+}
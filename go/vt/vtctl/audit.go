@@ -0,0 +1,123 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+)
+
+// This file contains the audit log of the vtctl commands. Every command
+// run through RunCommand, including the ones denied by the ACLs, is
+// appended to the log as one JSON object per line.
+
+var auditLogFile = flag.String("vtctl_audit_log", "", "If set, every vtctl command is appended to this file, with its parameters and outcome, as one JSON object per line.")
+
+// AuditEntry is one entry of the audit log.
+type AuditEntry struct {
+	Time     time.Time
+	User     string
+	Command  string
+	Args     []string
+	Outcome  string
+	Error    string `json:",omitempty"`
+	Duration time.Duration
+}
+
+// Possible values for AuditEntry.Outcome.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeDenied  = "denied"
+)
+
+var (
+	auditMu     sync.Mutex
+	auditOpened bool
+	auditWriter io.Writer
+)
+
+// openAuditLog opens the audit log the first time it is needed. It returns
+// nil if no audit log is configured, or if it cannot be opened.
+// auditMu must be held.
+func openAuditLog() io.Writer {
+	if !auditOpened {
+		auditOpened = true
+		if *auditLogFile != "" {
+			f, err := os.OpenFile(*auditLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				log.Errorf("cannot open vtctl_audit_log %v, commands will not be audited: %v", *auditLogFile, err)
+			} else {
+				auditWriter = f
+			}
+		}
+	}
+	return auditWriter
+}
+
+// AuditCommand appends a command and its outcome to the audit log, if
+// one is configured. The outcome is a failure if err is set, unless
+// denied is true.
+func AuditCommand(ctx context.Context, args []string, start time.Time, denied bool, err error) {
+	entry := &AuditEntry{
+		Time:     start,
+		User:     CallerUsername(ctx),
+		Outcome:  AuditOutcomeSuccess,
+		Duration: time.Since(start),
+	}
+	if len(args) > 0 {
+		entry.Command = args[0]
+		entry.Args = args[1:]
+	}
+	switch {
+	case denied:
+		entry.Outcome = AuditOutcomeDenied
+	case err != nil:
+		entry.Outcome = AuditOutcomeFailure
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	w := openAuditLog()
+	if w == nil {
+		return
+	}
+	if err := writeAuditEntry(w, entry); err != nil {
+		log.Errorf("cannot write to vtctl_audit_log: %v", err)
+	}
+}
+
+// writeAuditEntry writes one entry as a line of JSON.
+func writeAuditEntry(w io.Writer, entry *AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -171,6 +171,13 @@ func commandListBackups(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	if err != nil {
 		return err
 	}
+	if jsonOutput(ctx) {
+		names := make([]string, len(bhs))
+		for i, bh := range bhs {
+			names[i] = bh.Name()
+		}
+		return printJSON(wr.Logger(), names)
+	}
 	for _, bh := range bhs {
 		wr.Logger().Printf("%v\n", bh.Name())
 	}
@@ -0,0 +1,140 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+// This file contains the support for running a batch of commands
+// in a single process.
+
+// BatchCommand is one command of a batch.
+type BatchCommand struct {
+	// Line is the line number of the command in the batch.
+	Line int
+	// Args are the command name and its arguments.
+	Args []string
+}
+
+// ParseBatch reads a batch of commands, one per line. Empty lines and
+// lines starting with '#' are ignored. Arguments are separated by
+// whitespace, and can be quoted with single or double quotes. Within
+// double quotes, and outside of quotes, a backslash escapes the next
+// character.
+func ParseBatch(r io.Reader) ([]BatchCommand, error) {
+	var result []BatchCommand
+	scanner := bufio.NewScanner(r)
+	// Commands can have long arguments, like SQL statements.
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		args, err := splitBatchLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+		result = append(result, BatchCommand{
+			Line: line,
+			Args: args,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// splitBatchLine splits a line into arguments, honoring quotes and escapes.
+func splitBatchLine(text string) ([]string, error) {
+	var args []string
+	var current []rune
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, c := range text {
+		switch {
+		case escaped:
+			current = append(current, c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current = append(current, c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, string(current))
+				current = current[:0]
+				inArg = false
+			}
+		default:
+			current = append(current, c)
+			inArg = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, string(current))
+	}
+	return args, nil
+}
+
+// RunBatch runs a batch of commands in order with RunCommand, sharing the
+// same Wrangler and its topology connections. It stops at the first failed
+// command, unless continueOnError is set, in which case it runs them all.
+// It returns an error if any command failed.
+func RunBatch(ctx context.Context, wr *wrangler.Wrangler, commands []BatchCommand, continueOnError bool) error {
+	var failed []string
+	for _, cmd := range commands {
+		wr.Logger().Infof("Running line %v: %v", cmd.Line, strings.Join(cmd.Args, " "))
+		if err := RunCommand(ctx, wr, cmd.Args); err != nil {
+			wr.Logger().Errorf("Line %v failed: %v", cmd.Line, err)
+			if !continueOnError {
+				return fmt.Errorf("line %v: %v failed: %v", cmd.Line, cmd.Args[0], err)
+			}
+			failed = append(failed, fmt.Sprintf("%v", cmd.Line))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%v of %v commands failed, on line(s) %v", len(failed), len(commands), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatch(t *testing.T) {
+	input := `
+# Create the keyspace.
+CreateKeyspace test_keyspace
+
+	CreateShard   test_keyspace/0
+ExecuteFetchAsDba -json cell1-0000000100 "select 'a b', \"c\" from t"
+ApplySchema -sql 'create table t (id int) comment "x\y"' test_keyspace
+SetKeyspaceShardingInfo test_keyspace "" a\ b
+`
+	got, err := ParseBatch(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseBatch failed: %v", err)
+	}
+	want := []BatchCommand{
+		{Line: 3, Args: []string{"CreateKeyspace", "test_keyspace"}},
+		{Line: 5, Args: []string{"CreateShard", "test_keyspace/0"}},
+		{Line: 6, Args: []string{"ExecuteFetchAsDba", "-json", "cell1-0000000100", `select 'a b', "c" from t`}},
+		{Line: 7, Args: []string{"ApplySchema", "-sql", `create table t (id int) comment "x\y"`, "test_keyspace"}},
+		{Line: 8, Args: []string{"SetKeyspaceShardingInfo", "test_keyspace", "", "a b"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBatch:\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestParseBatchErrors(t *testing.T) {
+	for _, input := range []string{
+		"CreateKeyspace\nCreateShard 'test_keyspace/0",
+		`CreateShard "test_keyspace/0`,
+		`CreateShard test_keyspace/0\`,
+	} {
+		if _, err := ParseBatch(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseBatch(%q) should have failed", input)
+		}
+	}
+}
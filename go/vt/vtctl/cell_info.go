@@ -140,6 +140,9 @@ func commandGetCellInfoNames(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	if err != nil {
 		return err
 	}
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), names)
+	}
 	wr.Logger().Printf("%v\n", strings.Join(names, "\n"))
 	return nil
 }
@@ -0,0 +1,166 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+// This file contains the commands to manage the labels of keyspaces and
+// shards.
+
+func init() {
+	addCommand("Keyspaces", command{
+		"GetKeyspaceLabels",
+		commandGetKeyspaceLabels,
+		"<keyspace>",
+		"Outputs a JSON structure that contains the labels of the keyspace."})
+	addCommand("Keyspaces", command{
+		"SetKeyspaceLabels",
+		commandSetKeyspaceLabels,
+		"<keyspace> <key:value,...>",
+		"Sets labels of the keyspace. A label with an empty value is removed. Labels are free-form, for instance owner:team1,env:prod. Vitess doesn't use them, they are meant for external tools."})
+
+	addCommand("Shards", command{
+		"GetShardLabels",
+		commandGetShardLabels,
+		"<keyspace/shard>",
+		"Outputs a JSON structure that contains the labels of the shard."})
+	addCommand("Shards", command{
+		"SetShardLabels",
+		commandSetShardLabels,
+		"<keyspace/shard> <key:value,...>",
+		"Sets labels of the shard. A label with an empty value is removed. Labels are free-form, for instance migration:split_started. Vitess doesn't use them, they are meant for external tools."})
+}
+
+// parseLabels parses a key:value,... list of label changes.
+func parseLabels(arg string) (map[string]string, error) {
+	changes := make(map[string]string)
+	for _, pair := range strings.Split(arg, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key:value", pair)
+		}
+		changes[parts[0]] = parts[1]
+	}
+	return changes, nil
+}
+
+// setLabels returns an update function for the Update*Labels methods,
+// which applies the changes. Empty values remove labels.
+func setLabels(changes map[string]string) func(map[string]string) error {
+	return func(labels map[string]string) error {
+		for k, v := range changes {
+			if v == "" {
+				delete(labels, k)
+			} else {
+				labels[k] = v
+			}
+		}
+		return nil
+	}
+}
+
+func commandGetKeyspaceLabels(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace> argument is required for the GetKeyspaceLabels command")
+	}
+
+	keyspace := subFlags.Arg(0)
+	if _, err := wr.TopoServer().GetKeyspace(ctx, keyspace); err != nil {
+		return err
+	}
+	labels, err := wr.TopoServer().GetKeyspaceLabels(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), labels)
+}
+
+func commandSetKeyspaceLabels(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace> and <key:value,...> arguments are required for the SetKeyspaceLabels command")
+	}
+
+	changes, err := parseLabels(subFlags.Arg(1))
+	if err != nil {
+		return err
+	}
+	labels, err := wr.TopoServer().UpdateKeyspaceLabels(ctx, subFlags.Arg(0), setLabels(changes))
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), labels)
+}
+
+func commandGetShardLabels(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the GetShardLabels command")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if _, err := wr.TopoServer().GetShard(ctx, keyspace, shard); err != nil {
+		return err
+	}
+	labels, err := wr.TopoServer().GetShardLabels(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), labels)
+}
+
+func commandSetShardLabels(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace/shard> and <key:value,...> arguments are required for the SetShardLabels command")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	changes, err := parseLabels(subFlags.Arg(1))
+	if err != nil {
+		return err
+	}
+	labels, err := wr.TopoServer().UpdateShardLabels(ctx, keyspace, shard, setLabels(changes))
+	if err != nil {
+		return err
+	}
+	return printJSON(wr.Logger(), labels)
+}
@@ -168,7 +168,7 @@ func commandVtGateExecute(ctx context.Context, wr *wrangler.Wrangler, subFlags *
 	bindVariables := newBindvars(subFlags)
 	targetString := subFlags.String("target", "", "keyspace:shard@tablet_type")
 	options := subFlags.String("options", "", "execute options values as a text encoded proto of the ExecuteOptions structure")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -215,7 +215,7 @@ func commandVtGateExecuteShards(ctx context.Context, wr *wrangler.Wrangler, subF
 	keyspace := subFlags.String("keyspace", "", "keyspace to send query to")
 	shardsStr := subFlags.String("shards", "", "comma-separated list of shards to send query to")
 	options := subFlags.String("options", "", "execute options values as a text encoded proto of the ExecuteOptions structure")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -269,7 +269,7 @@ func commandVtGateExecuteKeyspaceIds(ctx context.Context, wr *wrangler.Wrangler,
 	keyspace := subFlags.String("keyspace", "", "keyspace to send query to")
 	keyspaceIDsStr := subFlags.String("keyspace_ids", "", "comma-separated list of keyspace ids (in hex) that will map into shards to send query to")
 	options := subFlags.String("options", "", "execute options values as a text encoded proto of the ExecuteOptions structure")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -399,7 +399,7 @@ func commandVtTabletExecute(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	transactionID := subFlags.Int("transaction_id", 0, "transaction id to use, if inside a transaction.")
 	bindVariables := newBindvars(subFlags)
 	options := subFlags.String("options", "", "execute options values as a text encoded proto of the ExecuteOptions structure")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -44,13 +44,13 @@ func init() {
 	addCommand("Shards", command{
 		"PlannedReparentShard",
 		commandPlannedReparentShard,
-		"-keyspace_shard=<keyspace/shard> [-new_master=<tablet alias>] [-avoid_master=<tablet alias>]",
-		"Reparents the shard to the new master, or away from old master. Both old and new master need to be up and running."})
+		"-keyspace_shard=<keyspace/shard> [-new_master=<tablet alias>] [-avoid_master=<tablet alias>] [-new_master_policy=<name>[:<argument>]]",
+		"Reparents the shard to the new master, or away from old master. Both old and new master need to be up and running. If -new_master is not set, -new_master_policy chooses the new master among the REPLICA tablets."})
 	addCommand("Shards", command{
 		"EmergencyReparentShard",
 		commandEmergencyReparentShard,
-		"-keyspace_shard=<keyspace/shard> -new_master=<tablet alias>",
-		"Reparents the shard to the new master. Assumes the old master is dead and not responsding."})
+		"[-backfill_from_most_advanced] -keyspace_shard=<keyspace/shard> -new_master=<tablet alias>",
+		"Reparents the shard to the new master. Assumes the old master is dead and not responsding. If another slave has more transactions than the new master, the command fails unless -backfill_from_most_advanced is set. Then the new master first replicates the missing transactions from the binlogs of the most advanced slave. This requires GTIDs and log_slave_updates."})
 }
 
 func commandReparentTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -104,6 +104,7 @@ func commandPlannedReparentShard(ctx context.Context, wr *wrangler.Wrangler, sub
 	keyspaceShard := subFlags.String("keyspace_shard", "", "keyspace/shard of the shard that needs to be reparented")
 	newMaster := subFlags.String("new_master", "", "alias of a tablet that should be the new master")
 	avoidMaster := subFlags.String("avoid_master", "", "alias of a tablet that should not be the master, i.e. reparent to any other tablet if this one is the master")
+	newMasterPolicy := subFlags.String("new_master_policy", "", "policy which chooses the new master if -new_master is not set e.g. \"most_caught_up\", \"cell:cell1,cell2\" or \"tag:durability=high\". Defaults to the value of -master_candidate_policy")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -135,7 +136,11 @@ func commandPlannedReparentShard(ctx context.Context, wr *wrangler.Wrangler, sub
 			return err
 		}
 	}
-	return wr.PlannedReparentShard(ctx, keyspace, shard, newMasterAlias, avoidMasterAlias, *waitSlaveTimeout)
+	policy, err := wrangler.NewMasterCandidatePolicy(*newMasterPolicy)
+	if err != nil {
+		return err
+	}
+	return wr.PlannedReparentShard(ctx, keyspace, shard, newMasterAlias, avoidMasterAlias, *waitSlaveTimeout, policy)
 }
 
 func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -146,6 +151,7 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 	waitSlaveTimeout := subFlags.Duration("wait_slave_timeout", 30*time.Second, "time to wait for slaves to catch up in reparenting")
 	keyspaceShard := subFlags.String("keyspace_shard", "", "keyspace/shard of the shard that needs to be reparented")
 	newMaster := subFlags.String("new_master", "", "alias of a tablet that should be the new master")
+	backfillFromMostAdvanced := subFlags.Bool("backfill_from_most_advanced", false, "if another slave has more transactions than the new master, replicate the missing transactions from its binlogs before promoting the new master")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -168,5 +174,5 @@ func commandEmergencyReparentShard(ctx context.Context, wr *wrangler.Wrangler, s
 	if err != nil {
 		return err
 	}
-	return wr.EmergencyReparentShard(ctx, keyspace, shard, tabletAlias, *waitSlaveTimeout)
+	return wr.EmergencyReparentShard(ctx, keyspace, shard, tabletAlias, *waitSlaveTimeout, *backfillFromMostAdvanced)
 }
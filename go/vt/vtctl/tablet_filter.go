@@ -0,0 +1,294 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/flagutil"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file contains the filters of the tablet listing commands
+// (ListAllTablets, ListShardTablets and ListTablets). The type, cell and
+// tags filters only use the topology. The status and lag filters query
+// the health of each tablet, and add it to the output.
+
+// Possible values for the -status flag.
+const (
+	tabletStatusHealthy   = "healthy"
+	tabletStatusUnhealthy = "unhealthy"
+)
+
+// tabletFilterFlags are the flags of the tablet listing commands.
+type tabletFilterFlags struct {
+	tabletType    *string
+	cells         *string
+	tags          flagutil.StringMapValue
+	status        *string
+	minLag        *time.Duration
+	health        *bool
+	healthTimeout *time.Duration
+}
+
+func addTabletFilterFlags(subFlags *flag.FlagSet) *tabletFilterFlags {
+	f := &tabletFilterFlags{
+		tabletType:    subFlags.String("tablet_type", "", "Only lists the tablets of this type"),
+		cells:         subFlags.String("cell", "", "Only lists the tablets in this comma-separated list of cells"),
+		status:        subFlags.String("status", "", "Only lists the tablets with this health status: healthy or unhealthy. Unreachable tablets are unhealthy. Implies -health"),
+		minLag:        subFlags.Duration("min_lag", 0, "Only lists the tablets with at least this replication lag. Implies -health"),
+		health:        subFlags.Bool("health", false, "Queries the health of the tablets, and adds their status, replication lag in seconds, last health check time and health error to the output"),
+		healthTimeout: subFlags.Duration("health_timeout", 5*time.Second, "Maximum time to wait for the health of a tablet, after which it is considered unhealthy"),
+	}
+	subFlags.Var(&f.tags, "tags", "Only lists the tablets with all these comma-separated key:value tags")
+	return f
+}
+
+// tabletFilter selects the tablets to list.
+type tabletFilter struct {
+	// tabletType is UNKNOWN to list all types.
+	tabletType topodatapb.TabletType
+	cells      map[string]bool
+	tags       map[string]string
+	status     string
+	minLag     time.Duration
+	health     bool
+	// healthTimeout is the timeout of each health query.
+	healthTimeout time.Duration
+}
+
+// filter returns the tabletFilter described by the parsed flags.
+func (f *tabletFilterFlags) filter() (*tabletFilter, error) {
+	filter := &tabletFilter{
+		tags:          f.tags,
+		status:        *f.status,
+		minLag:        *f.minLag,
+		health:        *f.health || *f.status != "" || *f.minLag > 0,
+		healthTimeout: *f.healthTimeout,
+	}
+	if *f.tabletType != "" {
+		tabletType, err := topoproto.ParseTabletType(*f.tabletType)
+		if err != nil {
+			return nil, err
+		}
+		filter.tabletType = tabletType
+	}
+	if *f.cells != "" {
+		filter.cells = make(map[string]bool)
+		for _, cell := range strings.Split(*f.cells, ",") {
+			filter.cells[cell] = true
+		}
+	}
+	switch filter.status {
+	case "", tabletStatusHealthy, tabletStatusUnhealthy:
+	default:
+		return nil, fmt.Errorf("invalid -status %q, must be %v or %v", filter.status, tabletStatusHealthy, tabletStatusUnhealthy)
+	}
+	return filter, nil
+}
+
+// matchesTablet returns true if the tablet record passes the filters
+// that don't need its health.
+func (f *tabletFilter) matchesTablet(tablet *topodatapb.Tablet) bool {
+	if f.tabletType != topodatapb.TabletType_UNKNOWN && tablet.Type != f.tabletType {
+		return false
+	}
+	if f.cells != nil && !f.cells[tablet.Alias.Cell] {
+		return false
+	}
+	for k, v := range f.tags {
+		if tv, ok := tablet.Tags[k]; !ok || tv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesHealth returns true if the tablet health passes the filters.
+func (f *tabletFilter) matchesHealth(lt *listedTablet) bool {
+	switch f.status {
+	case tabletStatusHealthy:
+		if lt.unhealthy() {
+			return false
+		}
+	case tabletStatusUnhealthy:
+		if !lt.unhealthy() {
+			return false
+		}
+	}
+	if f.minLag > 0 {
+		// A tablet without health has an unknown lag, which may be
+		// above the minimum.
+		lag, ok := lt.replicationLag()
+		if ok && lag < f.minLag {
+			return false
+		}
+	}
+	return true
+}
+
+// listedTablet is a tablet listed by the tablet listing commands, with
+// its health if it was queried.
+type listedTablet struct {
+	Tablet          *topodatapb.Tablet
+	Health          *querypb.StreamHealthResponse `json:",omitempty"`
+	HealthError     string                        `json:",omitempty"`
+	LastHealthCheck time.Time
+
+	ti *topo.TabletInfo
+}
+
+// unhealthy returns true if the tablet could not be reached, is not
+// serving, or reports a health error.
+func (lt *listedTablet) unhealthy() bool {
+	if lt.Health == nil {
+		return true
+	}
+	if !lt.Health.Serving {
+		return true
+	}
+	return lt.Health.RealtimeStats == nil || lt.Health.RealtimeStats.HealthError != ""
+}
+
+// replicationLag returns the replication lag of the tablet, and false
+// if it is not known.
+func (lt *listedTablet) replicationLag() (time.Duration, bool) {
+	if lt.Health == nil || lt.Health.RealtimeStats == nil {
+		return 0, false
+	}
+	return time.Duration(lt.Health.RealtimeStats.SecondsBehindMaster) * time.Second, true
+}
+
+// healthAwkable returns the health columns of the awk-friendly output.
+func (lt *listedTablet) healthAwkable() string {
+	status := tabletStatusHealthy
+	if lt.unhealthy() {
+		status = tabletStatusUnhealthy
+	}
+	lag := "<err>"
+	if l, ok := lt.replicationLag(); ok {
+		lag = fmt.Sprintf("%v", int64(l/time.Second))
+	}
+	lastCheck := "<err>"
+	if !lt.LastHealthCheck.IsZero() {
+		lastCheck = lt.LastHealthCheck.UTC().Format(time.RFC3339)
+	}
+	healthError := lt.HealthError
+	if healthError == "" && lt.Health != nil && lt.Health.RealtimeStats != nil {
+		healthError = lt.Health.RealtimeStats.HealthError
+	}
+	return fmt.Sprintf("%v %v %v %q", status, lag, lastCheck, healthError)
+}
+
+// getTabletHealth returns the first health message streamed by a tablet.
+func getTabletHealth(ctx context.Context, tablet *topodatapb.Tablet) (*querypb.StreamHealthResponse, error) {
+	conn, err := tabletconn.GetDialer()(tablet, grpcclient.FailFast(true))
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to tablet %v: %v", topoproto.TabletAliasString(tablet.Alias), err)
+	}
+	defer conn.Close(ctx)
+
+	var health *querypb.StreamHealthResponse
+	if err := conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		health = shr
+		return io.EOF
+	}); err != nil {
+		return nil, err
+	}
+	if health == nil {
+		return nil, fmt.Errorf("health stream of tablet %v ended early", topoproto.TabletAliasString(tablet.Alias))
+	}
+	return health, nil
+}
+
+// filterTablets returns the tablets that pass the filter, in the same
+// order. The health of the tablets is queried in parallel if needed.
+func filterTablets(ctx context.Context, tablets []*topo.TabletInfo, filter *tabletFilter) []*listedTablet {
+	var listed []*listedTablet
+	for _, ti := range tablets {
+		if filter.matchesTablet(ti.Tablet) {
+			listed = append(listed, &listedTablet{Tablet: ti.Tablet, ti: ti})
+		}
+	}
+	if !filter.health {
+		return listed
+	}
+
+	wg := sync.WaitGroup{}
+	for _, lt := range listed {
+		wg.Add(1)
+		go func(lt *listedTablet) {
+			defer wg.Done()
+			healthCtx, cancel := context.WithTimeout(ctx, filter.healthTimeout)
+			defer cancel()
+			health, err := getTabletHealth(healthCtx, lt.Tablet)
+			if err != nil {
+				lt.HealthError = err.Error()
+				return
+			}
+			lt.Health = health
+			lt.LastHealthCheck = time.Now()
+		}(lt)
+	}
+	wg.Wait()
+
+	result := make([]*listedTablet, 0, len(listed))
+	for _, lt := range listed {
+		if filter.matchesHealth(lt) {
+			result = append(result, lt)
+		}
+	}
+	return result
+}
+
+// printTablets filters the tablets and prints them, in JSON or in an
+// awk-friendly way. If the health was queried, it is part of the output.
+func printTablets(ctx context.Context, wr *wrangler.Wrangler, tablets []*topo.TabletInfo, filter *tabletFilter) error {
+	listed := filterTablets(ctx, tablets, filter)
+	if jsonOutput(ctx) {
+		if filter.health {
+			return printJSON(wr.Logger(), listed)
+		}
+		result := make([]*topodatapb.Tablet, len(listed))
+		for i, lt := range listed {
+			result[i] = lt.Tablet
+		}
+		return printJSON(wr.Logger(), result)
+	}
+	for _, lt := range listed {
+		if filter.health {
+			wr.Logger().Printf("%v %v\n", fmtTabletAwkable(lt.ti), lt.healthAwkable())
+		} else {
+			wr.Logger().Printf("%v\n", fmtTabletAwkable(lt.ti))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctl
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func parseTabletFilter(t *testing.T, args ...string) *tabletFilter {
+	subFlags := flag.NewFlagSet("ListAllTablets", flag.ContinueOnError)
+	filterFlags := addTabletFilterFlags(subFlags)
+	if err := subFlags.Parse(args); err != nil {
+		t.Fatalf("Parse(%v) failed: %v", args, err)
+	}
+	filter, err := filterFlags.filter()
+	if err != nil {
+		t.Fatalf("filter(%v) failed: %v", args, err)
+	}
+	return filter
+}
+
+func TestTabletFilterFlags(t *testing.T) {
+	filter := parseTabletFilter(t)
+	if filter.health {
+		t.Errorf("the health should not be queried without health flags")
+	}
+	filter = parseTabletFilter(t, "-min_lag", "30s")
+	if !filter.health || filter.minLag != 30*time.Second {
+		t.Errorf("-min_lag should query the health: %+v", filter)
+	}
+	filter = parseTabletFilter(t, "-status", "unhealthy")
+	if !filter.health || filter.status != tabletStatusUnhealthy {
+		t.Errorf("-status should query the health: %+v", filter)
+	}
+
+	for _, args := range [][]string{
+		{"-status", "sick"},
+		{"-tablet_type", "unknown_type"},
+	} {
+		subFlags := flag.NewFlagSet("ListAllTablets", flag.ContinueOnError)
+		filterFlags := addTabletFilterFlags(subFlags)
+		if err := subFlags.Parse(args); err != nil {
+			t.Fatalf("Parse(%v) failed: %v", args, err)
+		}
+		if _, err := filterFlags.filter(); err == nil {
+			t.Errorf("filter(%v) should have failed", args)
+		}
+	}
+}
+
+func TestTabletFilterMatchesTablet(t *testing.T) {
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 1},
+		Type:  topodatapb.TabletType_REPLICA,
+		Tags:  map[string]string{"rack": "r1", "pool": "ssd"},
+	}
+	testcases := []struct {
+		args []string
+		want bool
+	}{
+		{nil, true},
+		{[]string{"-tablet_type", "replica"}, true},
+		{[]string{"-tablet_type", "rdonly"}, false},
+		{[]string{"-cell", "cell2,cell1"}, true},
+		{[]string{"-cell", "cell2"}, false},
+		{[]string{"-tags", "rack:r1"}, true},
+		{[]string{"-tags", "rack:r1,pool:hdd"}, false},
+		{[]string{"-tags", "zone:z1"}, false},
+	}
+	for _, tc := range testcases {
+		filter := parseTabletFilter(t, tc.args...)
+		if got := filter.matchesTablet(tablet); got != tc.want {
+			t.Errorf("matchesTablet with %v = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestTabletFilterMatchesHealth(t *testing.T) {
+	healthy := &listedTablet{
+		Health: &querypb.StreamHealthResponse{
+			Serving:       true,
+			RealtimeStats: &querypb.RealtimeStats{SecondsBehindMaster: 2},
+		},
+	}
+	lagging := &listedTablet{
+		Health: &querypb.StreamHealthResponse{
+			Serving:       true,
+			RealtimeStats: &querypb.RealtimeStats{SecondsBehindMaster: 120},
+		},
+	}
+	notServing := &listedTablet{
+		Health: &querypb.StreamHealthResponse{
+			RealtimeStats: &querypb.RealtimeStats{},
+		},
+	}
+	broken := &listedTablet{
+		Health: &querypb.StreamHealthResponse{
+			Serving:       true,
+			RealtimeStats: &querypb.RealtimeStats{HealthError: "replication stopped"},
+		},
+	}
+	unreachable := &listedTablet{
+		HealthError: "cannot connect",
+	}
+
+	testcases := []struct {
+		args []string
+		lt   *listedTablet
+		want bool
+	}{
+		{[]string{"-status", "healthy"}, healthy, true},
+		{[]string{"-status", "healthy"}, notServing, false},
+		{[]string{"-status", "unhealthy"}, healthy, false},
+		{[]string{"-status", "unhealthy"}, notServing, true},
+		{[]string{"-status", "unhealthy"}, broken, true},
+		{[]string{"-status", "unhealthy"}, unreachable, true},
+		{[]string{"-min_lag", "1m"}, healthy, false},
+		{[]string{"-min_lag", "1m"}, lagging, true},
+		// The lag of an unreachable tablet is unknown.
+		{[]string{"-min_lag", "1m"}, unreachable, true},
+	}
+	for _, tc := range testcases {
+		filter := parseTabletFilter(t, tc.args...)
+		if got := filter.matchesHealth(tc.lt); got != tc.want {
+			t.Errorf("matchesHealth(%+v) with %v = %v, want %v", tc.lt, tc.args, got, tc.want)
+		}
+	}
+
+	if got, want := broken.healthAwkable(), `unhealthy 0 <err> "replication stopped"`; got != want {
+		t.Errorf("healthAwkable() = %q, want %q", got, want)
+	}
+	if got, want := unreachable.healthAwkable(), `unhealthy <err> <err> "cannot connect"`; got != want {
+		t.Errorf("healthAwkable() = %q, want %q", got, want)
+	}
+}
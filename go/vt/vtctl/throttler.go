@@ -96,6 +96,9 @@ func commandThrottlerMaxRates(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if err != nil {
 		return fmt.Errorf("failed to get the throttler rate from server '%v': %v", *server, err)
 	}
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), rates)
+	}
 
 	if len(rates) == 0 {
 		wr.Logger().Printf("There are no active throttlers on server '%v'.\n", *server)
@@ -186,6 +189,9 @@ func commandGetThrottlerConfiguration(ctx context.Context, wr *wrangler.Wrangler
 	if err != nil {
 		return fmt.Errorf("failed to get the throttler configuration from server '%v': %v", *server, err)
 	}
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), configurations)
+	}
 
 	if len(configurations) == 0 {
 		wr.Logger().Printf("There are no active throttlers on server '%v'.\n", *server)
@@ -116,10 +116,12 @@ import (
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
@@ -186,6 +188,12 @@ var commands = []commandGroup{
 				"[-dry-run] <tablet alias> <tablet type>",
 				"Changes the db type for the specified tablet, if possible. This command is used primarily to arrange replicas, and it will not convert a master.\n" +
 					"NOTE: This command automatically updates the serving graph.\n"},
+			{"DrainTablet", commandDrainTablet,
+				"[-max_replication_lag <duration>] <tablet alias>",
+				"Takes a REPLICA or RDONLY tablet out of serving for maintenance. The tablet is changed to DRAINED, which removes it from the healthcheck of vtgate, and it stops its query service after the in-flight queries have finished. If -max_replication_lag is set, the command waits until the replication lag of the tablet is at most this value."},
+			{"UndrainTablet", commandUndrainTablet,
+				"[-max_replication_lag <duration>] <tablet alias> [<tablet type>]",
+				"Makes a tablet which was drained with DrainTablet serve again. Without <tablet type>, the type before DrainTablet is restored. If -max_replication_lag is set, the tablet serves only after its replication lag is at most this value."},
 			{"Ping", commandPing,
 				"<tablet alias>",
 				"Checks that the specified tablet is awake and responding to RPCs. This command can be blocked by other in-flight operations."},
@@ -208,6 +216,10 @@ var commands = []commandGroup{
 				"<tablet alias> <hook name> [<param1=value1> <param2=value2> ...]",
 				"Runs the specified hook on the given tablet. A hook is a script that resides in the $VTROOT/vthook directory. You can put any script into that directory and use this command to run that script.\n" +
 					"For this command, the param=value arguments are parameters that the command passes to the specified hook."},
+			{"ExecuteHookOnTablets", commandExecuteHookOnTablets,
+				"[-concurrency=10] [-shards=<shard>,<shard>,...] [-tablet_types=<type>,<type>,...] <keyspace> <hook name> [<param1=value1> <param2=value2> ...]",
+				"Runs the specified hook on all tablets of the keyspace which are in one of the given shards and have one of the given tablet types. Without -shards or -tablet_types, all shards or all tablet types are included. At most -concurrency hooks run at the same time.\n" +
+					"The stdout and stderr of each tablet are printed with the tablet alias as prefix. The command fails if the hook fails on any tablet, and lists those tablets."},
 			{"ExecuteFetchAsApp", commandExecuteFetchAsApp,
 				"[-max_rows=10000] [-json] [-use_pool] <tablet alias> <sql command>",
 				"Runs the given SQL command as a App on the remote tablet."},
@@ -217,6 +229,9 @@ var commands = []commandGroup{
 			{"VReplicationExec", commandVReplicationExec,
 				"[-json] <tablet alias> <sql command>",
 				"Runs the given VReplication command on the remote tablet."},
+			{"CleanupOrphanedWorkerActions", commandCleanupOrphanedWorkerActions,
+				"[-dry-run] [-keyspace_shard=<keyspace/shard>] [<id> ...]",
+				"Runs the clean-up actions (e.g. restoring the tablet type or restarting replication) which a vtworker stored in the topology but could not run because it was killed. Without <id>, the actions of all vtworker commands are run. Use -dry-run to only list them. The actions of a vtworker which still responds to HTTP requests are skipped."},
 		},
 	},
 	{
@@ -227,6 +242,9 @@ var commands = []commandGroup{
 			{"GetShard", commandGetShard,
 				"<keyspace/shard>",
 				"Outputs a JSON structure that contains information about the Shard."},
+			{"GetShardDiffVerification", commandGetShardDiffVerification,
+				"<keyspace/shard>",
+				"Outputs a JSON structure that describes the last diff (e.g. vtworker SplitDiff) which verified the shard: the command, the source shard, the replication position and the time."},
 			{"TabletExternallyReparented", commandTabletExternallyReparented,
 				"<tablet alias>",
 				"Changes metadata in the topology server to acknowledge a shard master change performed by an external tool. See the Reparenting guide for more information:" +
@@ -238,8 +256,8 @@ var commands = []commandGroup{
 				"<keyspace/shard>",
 				"Shows the replication status of each slave machine in the shard graph. In this case, the status refers to the replication lag between the master vttablet and the slave vttablet. In Vitess, data is always written to the master vttablet first and then replicated to all slave vttablets. Output is sorted by tablet type, then replication position. Use ctrl-C to interrupt command and see partial result if needed."},
 			{"ListShardTablets", commandListShardTablets,
-				"<keyspace/shard>",
-				"Lists all tablets in the specified shard."},
+				"[-tablet_type <tablet type>] [-cell <cell1,cell2,...>] [-tags <key:value,...>] [-status <healthy|unhealthy>] [-min_lag <duration>] [-health] <keyspace/shard>",
+				"Lists all tablets in the specified shard, in an awk-friendly way. The tablets can be filtered by type, cell, tags, health status and replication lag. With -health, or a health filter, the health status, replication lag, last health check time and health error of each tablet are added to the output."},
 			{"SetShardServedTypes", commandSetShardServedTypes,
 				"[--cells=c1,c2,...] [--remove] <keyspace/shard> <served tablet type>",
 				"Add or remove served type to/from a shard. This is meant as an emergency function. It does not rebuild any serving graph i.e. does not run 'RebuildKeyspaceGraph'."},
@@ -266,13 +284,19 @@ var commands = []commandGroup{
 				"Walks through a ShardReplication object and fixes the first error that it encounters."},
 			{"WaitForFilteredReplication", commandWaitForFilteredReplication,
 				"[-max_delay <max_delay, default 30s>] <keyspace/shard>",
-				"Blocks until the specified shard has caught up with the filtered replication of its source shard."},
+				"Blocks until the specified shard has caught up with the filtered replication of its source shard. Logs the delay and the position of each filtered replication stream while it waits."},
 			{"RemoveShardCell", commandRemoveShardCell,
 				"[-force] [-recursive] <keyspace/shard> <cell>",
 				"Removes the cell from the shard's Cells list."},
 			{"DeleteShard", commandDeleteShard,
 				"[-recursive] [-even_if_serving] <keyspace/shard> ...",
-				"Deletes the specified shard(s). In recursive mode, it also deletes all tablets belonging to the shard. Otherwise, there must be no tablets left in the shard."},
+				"Deletes the specified shard(s), their replication graph, and their references in the SrvKeyspace of all cells. In recursive mode, it also deletes all tablets belonging to the shard. Otherwise, there must be no tablets left in the shard. Unless -even_if_serving is specified, serving shards, or shards with serving tablets, are not deleted."},
+			{"ListShardLocks", commandListShardLocks,
+				"<keyspace/shard> ...",
+				"Lists the locks currently held on the specified shard(s), with their action, owner, start time and TTL. Locks that have outlived their TTL are marked as stale."},
+			{"ForceUnlockShard", commandForceUnlockShard,
+				"[-force] <keyspace/shard>",
+				"Breaks the lock currently held on the specified shard, for instance when it was left behind by a crashed process. Without -force, only locks that have outlived their TTL can be broken."},
 		},
 	},
 	{
@@ -281,8 +305,8 @@ var commands = []commandGroup{
 				"[-sharding_column_name=name] [-sharding_column_type=type] [-served_from=tablettype1:ks1,tablettype2,ks2,...] [-force] <keyspace name>",
 				"Creates the specified keyspace."},
 			{"DeleteKeyspace", commandDeleteKeyspace,
-				"[-recursive] <keyspace>",
-				"Deletes the specified keyspace. In recursive mode, it also recursively deletes all shards in the keyspace. Otherwise, there must be no shards left in the keyspace."},
+				"[-recursive] [-even_if_serving] <keyspace>",
+				"Deletes the specified keyspace. In recursive mode, it also recursively deletes all shards in the keyspace, unless one of them is serving or has serving tablets. Otherwise, there must be no shards left in the keyspace."},
 			{"RemoveKeyspaceCell", commandRemoveKeyspaceCell,
 				"[-force] [-recursive] <keyspace> <cell>",
 				"Removes the cell from the Cells list for all shards in the keyspace, and the SrvKeyspace for that keyspace in that cell."},
@@ -305,11 +329,11 @@ var commands = []commandGroup{
 				"[-ping-tablets] <keyspace name>",
 				"Validates that all nodes reachable from the specified keyspace are consistent."},
 			{"MigrateServedTypes", commandMigrateServedTypes,
-				"[-cells=c1,c2,...] [-reverse] [-skip-refresh-state] <keyspace/shard> <served tablet type>",
+				"[-cells=c1,c2,...] [-reverse] [-skip-refresh-state] [-dry_run] <keyspace/shard> <served tablet type>",
 				"Migrates a serving type from the source shard to the shards that it replicates to. This command also rebuilds the serving graph. The <keyspace/shard> argument can specify any of the shards involved in the migration."},
 			{"MigrateServedFrom", commandMigrateServedFrom,
-				"[-cells=c1,c2,...] [-reverse] <destination keyspace/shard> <served tablet type>",
-				"Makes the <destination keyspace/shard> serve the given type. This command also rebuilds the serving graph."},
+				"[-cells=c1,c2,...] [-reverse] [-dry_run] <destination keyspace/shard> <served tablet type>",
+				"Makes the <destination keyspace/shard> serve the given type. This command also rebuilds the serving graph. The master cannot be migrated back with -reverse."},
 			{"CancelResharding", commandCancelResharding,
 				"<keyspace/shard>",
 				"Permanently cancels a resharding in progress. All resharding related metadata will be deleted."},
@@ -331,12 +355,15 @@ var commands = []commandGroup{
 			{"Validate", commandValidate,
 				"[-ping-tablets]",
 				"Validates that all nodes reachable from the global replication graph and that all tablets in all discoverable cells are consistent."},
+			{"TopoValidate", commandTopoValidate,
+				"[-repair]",
+				"Cross-checks the tablet records, replication graphs and serving graphs of all cells, without contacting the tablets, and reports orphan records and dangling references. With -repair, the safe inconsistencies are fixed: missing replication graph entries and shard cells are added, dangling replication graph entries and orphan serving graphs are removed, and outdated serving graphs are rebuilt. Orphan tablets are only reported."},
 			{"ListAllTablets", commandListAllTablets,
-				"<cell name>",
-				"Lists all tablets in an awk-friendly way."},
+				"[-tablet_type <tablet type>] [-cell <cell1,cell2,...>] [-tags <key:value,...>] [-status <healthy|unhealthy>] [-min_lag <duration>] [-health] <cell name>",
+				"Lists all tablets in an awk-friendly way. The filters are the same as ListShardTablets."},
 			{"ListTablets", commandListTablets,
-				"<tablet alias> ...",
-				"Lists specified tablets in an awk-friendly way."},
+				"[-tablet_type <tablet type>] [-cell <cell1,cell2,...>] [-tags <key:value,...>] [-status <healthy|unhealthy>] [-min_lag <duration>] [-health] <tablet alias> ...",
+				"Lists specified tablets in an awk-friendly way. The filters are the same as ListShardTablets."},
 			{"Panic", commandPanic,
 				"",
 				"HIDDEN Triggers a panic on the server side, to test the handling."},
@@ -360,21 +387,25 @@ var commands = []commandGroup{
 				"[-exclude_tables=''] [-include-views] <keyspace/shard>",
 				"Validates that the master schema matches all of the slaves."},
 			{"ValidateSchemaKeyspace", commandValidateSchemaKeyspace,
-				"[-exclude_tables=''] [-include-views] <keyspace name>",
-				"Validates that the master schema from shard 0 matches the schema on all of the other tablets in the keyspace."},
+				"[-exclude_tables=''] [-include-views] [-json] <keyspace name>",
+				"Validates that the master schema from shard 0 matches the schema on all of the other tablets in the keyspace. With -json, the differences are printed as a JSON list with one entry per table and column, index or definition which differs."},
 			{"ApplySchema", commandApplySchema,
-				"[-allow_long_unavailability] [-wait_slave_timeout=10s] {-sql=<sql> || -sql-file=<filename>} <keyspace>",
-				"Applies the schema change to the specified keyspace on every master, running in parallel on all shards. The changes are then propagated to slaves via replication. If -allow_long_unavailability is set, schema changes affecting a large number of rows (and possibly incurring a longer period of unavailability) will not be rejected."},
+				"[-allow_long_unavailability] [-wait_slave_timeout=10s] [-max_concurrent_shards=0] [-shard_delay=0] {-sql=<sql> || -sql-file=<filename>} <keyspace>",
+				"Applies the schema change to the specified keyspace on every master, running in parallel on all shards. The changes are then propagated to slaves via replication. If -allow_long_unavailability is set, schema changes affecting a large number of rows (and possibly incurring a longer period of unavailability) will not be rejected.\n" +
+					"To avoid replication stalls on all shards at the same time, -max_concurrent_shards limits on how many shards the change runs at once, and -shard_delay waits between starting the change on one shard and the next."},
+			{"ApplySchemaOnline", commandApplySchemaOnline,
+				"[-chunk_size=1000] [-chunk_sleep=0] [-max_replication_lag=0] [-keep_old_table] -alter=<alter specification> <keyspace> <table>",
+				"Changes the schema of a table on all masters of the keyspace without locking the table for the duration of the change. The change is applied to an empty copy of the table which is kept up to date with triggers while the rows are copied in chunks. After the copy has finished on all shards, the tables are swapped. If the change fails before that, it is rolled back on all shards. -alter is the part of the ALTER TABLE statement after the table name e.g. \"ADD COLUMN c INT\". It must not change the primary key."},
 			{"CopySchemaShard", commandCopySchemaShard,
-				"[-tables=<table1>,<table2>,...] [-exclude_tables=<table1>,<table2>,...] [-include-views] [-wait_slave_timeout=10s] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
+				"[-tables=<table1>,<table2>,...] [-exclude_tables=<table1>,<table2>,...] [-include-views] [-include-triggers] [-wait_slave_timeout=10s] [-wait_replicas_timeout=<duration>] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
 				"Copies the schema from a source shard's master (or a specific tablet) to a destination shard. The schema is applied directly on the master of the destination shard, and it is propagated to the replicas through binlogs."},
 
 			{"ValidateVersionShard", commandValidateVersionShard,
 				"<keyspace/shard>",
 				"Validates that the master version matches all of the slaves."},
 			{"ValidateVersionKeyspace", commandValidateVersionKeyspace,
-				"<keyspace name>",
-				"Validates that the master version from shard 0 matches all of the other tablets in the keyspace."},
+				"[-concurrency=10] <keyspace name>",
+				"Validates that the master version from shard 0 matches all of the other tablets in the keyspace. The tablets of all shards and cells are checked in parallel, and the number of differing tablets is displayed per cell and shard."},
 
 			{"GetPermissions", commandGetPermissions,
 				"<tablet alias>",
@@ -383,15 +414,15 @@ var commands = []commandGroup{
 				"<keyspace/shard>",
 				"Validates that the master permissions match all the slaves."},
 			{"ValidatePermissionsKeyspace", commandValidatePermissionsKeyspace,
-				"<keyspace name>",
-				"Validates that the master permissions from shard 0 match those of all of the other tablets in the keyspace."},
+				"[-concurrency=10] <keyspace name>",
+				"Validates that the master permissions from shard 0 match those of all of the other tablets in the keyspace. The tablets of all shards and cells are checked in parallel, and the number of differing tablets is displayed per cell and shard."},
 
 			{"GetVSchema", commandGetVSchema,
 				"<keyspace>",
 				"Displays the VTGate routing schema."},
 			{"ApplyVSchema", commandApplyVSchema,
-				"{-vschema=<vschema> || -vschema_file=<vschema file>} [-cells=c1,c2,...] [-skip_rebuild] <keyspace>",
-				"Applies the VTGate routing schema to the provided keyspace. Shows the result after application."},
+				"{-vschema=<vschema> || -vschema_file=<vschema file>} [-cells=c1,c2,...] [-skip_rebuild] [-dry_run] <keyspace>",
+				"Applies the VTGate routing schema to the provided keyspace. Shows the result after application. With -dry_run, only validates and shows the routing schema."},
 			{"RebuildVSchemaGraph", commandRebuildVSchemaGraph,
 				"[-cells=c1,c2,...]",
 				"Rebuilds the cell-specific SrvVSchema from the global VSchema objects in the provided cells (or all cells if none provided)."},
@@ -474,39 +505,37 @@ func fmtTabletAwkable(ti *topo.TabletInfo) string {
 	return fmt.Sprintf("%v %v %v %v %v %v %v", topoproto.TabletAliasString(ti.Alias), keyspace, shard, topoproto.TabletTypeLString(ti.Type), ti.Addr(), ti.MysqlAddr(), fmtMapAwkable(ti.Tags))
 }
 
-func listTabletsByShard(ctx context.Context, wr *wrangler.Wrangler, keyspace, shard string) error {
+func listTabletsByShard(ctx context.Context, wr *wrangler.Wrangler, keyspace, shard string, filter *tabletFilter) error {
 	tabletAliases, err := wr.TopoServer().FindAllTabletAliasesInShard(ctx, keyspace, shard)
 	if err != nil {
 		return err
 	}
-	return dumpTablets(ctx, wr, tabletAliases)
+	return dumpTablets(ctx, wr, tabletAliases, filter)
 }
 
-func dumpAllTablets(ctx context.Context, wr *wrangler.Wrangler, cell string) error {
+func dumpAllTablets(ctx context.Context, wr *wrangler.Wrangler, cell string, filter *tabletFilter) error {
 	tablets, err := topotools.GetAllTablets(ctx, wr.TopoServer(), cell)
 	if err != nil {
 		return err
 	}
-	for _, ti := range tablets {
-		wr.Logger().Printf("%v\n", fmtTabletAwkable(ti))
-	}
-	return nil
+	return printTablets(ctx, wr, tablets, filter)
 }
 
-func dumpTablets(ctx context.Context, wr *wrangler.Wrangler, tabletAliases []*topodatapb.TabletAlias) error {
+func dumpTablets(ctx context.Context, wr *wrangler.Wrangler, tabletAliases []*topodatapb.TabletAlias, filter *tabletFilter) error {
 	tabletMap, err := wr.TopoServer().GetTabletMap(ctx, tabletAliases)
 	if err != nil {
 		return err
 	}
+	tablets := make([]*topo.TabletInfo, 0, len(tabletAliases))
 	for _, tabletAlias := range tabletAliases {
 		ti, ok := tabletMap[topoproto.TabletAliasString(tabletAlias)]
 		if !ok {
 			log.Warningf("failed to load tablet %v", tabletAlias)
-		} else {
-			wr.Logger().Printf("%v\n", fmtTabletAwkable(ti))
+			continue
 		}
+		tablets = append(tablets, ti)
 	}
-	return nil
+	return printTablets(ctx, wr, tablets, filter)
 }
 
 // getFileParam returns a string containing either flag is not "",
@@ -869,6 +898,14 @@ func commandChangeSlaveType(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		if !topo.IsTrivialTypeChange(ti.Type, newType) {
 			return fmt.Errorf("invalid type transition %v: %v -> %v", tabletAlias, ti.Type, newType)
 		}
+		if jsonOutput(ctx) {
+			before := proto.Clone(ti.Tablet).(*topodatapb.Tablet)
+			ti.Type = newType
+			return printJSON(wr.Logger(), map[string]*topodatapb.Tablet{
+				"Before": before,
+				"After":  ti.Tablet,
+			})
+		}
 		wr.Logger().Printf("- %v\n", fmtTabletAwkable(ti))
 		ti.Type = newType
 		wr.Logger().Printf("+ %v\n", fmtTabletAwkable(ti))
@@ -877,6 +914,44 @@ func commandChangeSlaveType(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	return wr.ChangeSlaveType(ctx, tabletAlias, newType)
 }
 
+func commandDrainTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	maxReplicationLag := subFlags.Duration("max_replication_lag", 0, "If set, waits until the replication lag of the drained tablet is at most this value")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <tablet alias> argument is required for the DrainTablet command")
+	}
+
+	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return wr.DrainTablet(ctx, tabletAlias, *maxReplicationLag)
+}
+
+func commandUndrainTablet(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	maxReplicationLag := subFlags.Duration("max_replication_lag", 0, "If set, the tablet serves again only after its replication lag is at most this value")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 && subFlags.NArg() != 2 {
+		return fmt.Errorf("the <tablet alias> argument is required for the UndrainTablet command")
+	}
+
+	tabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	tabletType := topodatapb.TabletType_UNKNOWN
+	if subFlags.NArg() == 2 {
+		if tabletType, err = parseTabletType(subFlags.Arg(1), []topodatapb.TabletType{topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}); err != nil {
+			return err
+		}
+	}
+	return wr.UndrainTablet(ctx, tabletAlias, tabletType, *maxReplicationLag)
+}
+
 func commandPing(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1032,7 +1107,7 @@ func commandSleep(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.Fla
 func commandExecuteFetchAsApp(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	maxRows := subFlags.Int("max_rows", 10000, "Specifies the maximum number of rows to allow in fetch")
 	usePool := subFlags.Bool("use_pool", false, "Use connection from pool")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1062,7 +1137,7 @@ func commandExecuteFetchAsDba(ctx context.Context, wr *wrangler.Wrangler, subFla
 	maxRows := subFlags.Int("max_rows", 10000, "Specifies the maximum number of rows to allow in fetch")
 	disableBinlogs := subFlags.Bool("disable_binlogs", false, "Disables writing to binlogs during the query")
 	reloadSchema := subFlags.Bool("reload_schema", false, "Indicates whether the tablet schema will be reloaded after executing the SQL command. The default value is <code>false</code>, which indicates that the tablet schema will not be reloaded.")
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1089,7 +1164,7 @@ func commandExecuteFetchAsDba(ctx context.Context, wr *wrangler.Wrangler, subFla
 }
 
 func commandVReplicationExec(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
-	json := subFlags.Bool("json", false, "Output JSON instead of human-readable table")
+	json := subFlags.Bool("json", jsonOutput(ctx), "Output JSON instead of human-readable table")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1115,6 +1190,23 @@ func commandVReplicationExec(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	return nil
 }
 
+func commandCleanupOrphanedWorkerActions(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	dryRun := subFlags.Bool("dry-run", false, "Lists the orphaned actions without running them")
+	keyspaceShard := subFlags.String("keyspace_shard", "", "If set, only the actions of vtworker commands which ran on or affected a tablet of this <keyspace/shard> are run")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	var keyspace, shard string
+	if *keyspaceShard != "" {
+		var err error
+		keyspace, shard, err = topoproto.ParseKeyspaceShard(*keyspaceShard)
+		if err != nil {
+			return err
+		}
+	}
+	return wr.CleanupOrphanedWorkerActions(ctx, subFlags.Args(), keyspace, shard, *dryRun)
+}
+
 func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1135,6 +1227,34 @@ func commandExecuteHook(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	return printJSON(wr.Logger(), hr)
 }
 
+func commandExecuteHookOnTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	concurrency := subFlags.Int("concurrency", 10, "How many tablets to run the hook on in parallel")
+	shardsStr := subFlags.String("shards", "", "Specifies a comma-separated list of shards to run the hook on. All shards if empty")
+	tabletTypesStr := subFlags.String("tablet_types", "", "Specifies a comma-separated list of tablet types to run the hook on. All tablet types if empty")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() < 2 {
+		return fmt.Errorf("the <keyspace> and <hook name> arguments are required for the ExecuteHookOnTablets command")
+	}
+
+	var shards []string
+	if *shardsStr != "" {
+		shards = strings.Split(*shardsStr, ",")
+	}
+	var tabletTypes []topodatapb.TabletType
+	if *tabletTypesStr != "" {
+		var err error
+		tabletTypes, err = topoproto.ParseTabletTypes(*tabletTypesStr)
+		if err != nil {
+			return err
+		}
+	}
+	hook := &hk.Hook{Name: subFlags.Arg(1), Parameters: subFlags.Args()[2:]}
+	sema := sync2.NewSemaphore(*concurrency, 0)
+	return wr.ExecuteHookOnTablets(ctx, subFlags.Arg(0), shards, tabletTypes, hook, sema)
+}
+
 func commandCreateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	force := subFlags.Bool("force", false, "Proceeds with the command even if the keyspace already exists")
 	parent := subFlags.Bool("parent", false, "Creates the parent keyspace if it doesn't already exist")
@@ -1183,6 +1303,28 @@ func commandGetShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.
 	return printJSON(wr.Logger(), shardInfo.Shard)
 }
 
+func commandGetShardDiffVerification(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the GetShardDiffVerification command")
+	}
+
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	verification, err := wr.TopoServer().GetShardDiffVerification(ctx, keyspace, shard)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("shard %v/%v was never verified by a diff", keyspace, shard)
+		}
+		return err
+	}
+	return printJSON(wr.Logger(), verification)
+}
+
 func commandTabletExternallyReparented(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -1215,7 +1357,7 @@ func commandValidateShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *
 	if err != nil {
 		return err
 	}
-	return wr.ValidateShard(ctx, keyspace, shard, *pingTablets)
+	return printValidationResult(ctx, wr, wr.ValidateShard(ctx, keyspace, shard, *pingTablets))
 }
 
 func commandShardReplicationPositions(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -1234,6 +1376,22 @@ func commandShardReplicationPositions(ctx context.Context, wr *wrangler.Wrangler
 		return err
 	}
 
+	if jsonOutput(ctx) {
+		// Status is null for the tablets we couldn't get it from.
+		type tabletPosition struct {
+			Tablet *topodatapb.Tablet
+			Status *replicationdatapb.Status
+		}
+		result := make([]tabletPosition, 0, len(tablets))
+		for _, rt := range sortReplicatingTablets(tablets, stats) {
+			result = append(result, tabletPosition{
+				Tablet: rt.TabletInfo.Tablet,
+				Status: rt.Status,
+			})
+		}
+		return printJSON(wr.Logger(), result)
+	}
+
 	lines := make([]string, 0, 24)
 	for _, rt := range sortReplicatingTablets(tablets, stats) {
 		status := rt.Status
@@ -1251,6 +1409,7 @@ func commandShardReplicationPositions(ctx context.Context, wr *wrangler.Wrangler
 }
 
 func commandListShardTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	filterFlags := addTabletFilterFlags(subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1261,7 +1420,11 @@ func commandListShardTablets(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	if err != nil {
 		return err
 	}
-	return listTabletsByShard(ctx, wr, keyspace, shard)
+	filter, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
+	return listTabletsByShard(ctx, wr, keyspace, shard, filter)
 }
 
 func commandSetShardServedTypes(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -1466,7 +1629,7 @@ func commandRemoveShardCell(ctx context.Context, wr *wrangler.Wrangler, subFlags
 
 func commandDeleteShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	recursive := subFlags.Bool("recursive", false, "Also delete all tablets belonging to the shard.")
-	evenIfServing := subFlags.Bool("even_if_serving", false, "Remove the shard even if it is serving. Use with caution.")
+	evenIfServing := subFlags.Bool("even_if_serving", false, "Remove the shard even if it is serving, or has serving tablets. Use with caution.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1492,6 +1655,72 @@ func commandDeleteShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	return nil
 }
 
+func commandListShardLocks(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() == 0 {
+		return fmt.Errorf("the <keyspace/shard> argument must be used to identify at least one keyspace and shard when calling the ListShardLocks command")
+	}
+
+	keyspaceShards, err := shardParamsToKeyspaceShards(ctx, wr, subFlags.Args())
+	if err != nil {
+		return err
+	}
+	type shardLock struct {
+		Keyspace string
+		Shard    string
+		Lock     *topo.Lock
+		Stale    bool
+	}
+	var result []shardLock
+	now := time.Now()
+	for _, ks := range keyspaceShards {
+		l, _, err := wr.TopoServer().GetShardLock(ctx, ks.Keyspace, ks.Shard)
+		switch {
+		case err == nil:
+			result = append(result, shardLock{
+				Keyspace: ks.Keyspace,
+				Shard:    ks.Shard,
+				Lock:     l,
+				Stale:    l.Expired(now),
+			})
+		case topo.IsErrType(err, topo.NoNode):
+			// not locked
+		default:
+			return err
+		}
+	}
+
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), result)
+	}
+	for _, sl := range result {
+		stale := ""
+		if sl.Stale {
+			stale = " stale"
+		}
+		l := sl.Lock
+		wr.Logger().Printf("%v/%v %v %v@%v pid:%v since:%v ttl:%v%v\n", sl.Keyspace, sl.Shard, l.Action, l.UserName, l.HostName, l.PID, l.Time, l.TTL, stale)
+	}
+	return nil
+}
+
+func commandForceUnlockShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	force := subFlags.Bool("force", false, "Breaks the lock even if it has not outlived its TTL. Only use it if the holder is known to be gone.")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <keyspace/shard> argument is required for the ForceUnlockShard command")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return wr.ForceUnlockShard(ctx, keyspace, shard, *force)
+}
+
 func commandCreateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	shardingColumnName := subFlags.String("sharding_column_name", "", "Specifies the column to use for sharding operations")
 	shardingColumnType := subFlags.String("sharding_column_type", "", "Specifies the type of the column to use for sharding operations")
@@ -1536,6 +1765,7 @@ func commandCreateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 
 func commandDeleteKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	recursive := subFlags.Bool("recursive", false, "Also recursively delete all shards in the keyspace.")
+	evenIfServing := subFlags.Bool("even_if_serving", false, "Remove the shards even if they are serving, or have serving tablets. Use with caution.")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1543,7 +1773,7 @@ func commandDeleteKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags
 		return fmt.Errorf("must specify the <keyspace> argument for DeleteKeyspace")
 	}
 
-	return wr.DeleteKeyspace(ctx, subFlags.Arg(0), *recursive)
+	return wr.DeleteKeyspace(ctx, subFlags.Arg(0), *recursive, *evenIfServing)
 }
 
 func commandRemoveKeyspaceCell(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -1581,6 +1811,9 @@ func commandGetKeyspaces(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 	if err != nil {
 		return err
 	}
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), keyspaces)
+	}
 	wr.Logger().Printf("%v\n", strings.Join(keyspaces, "\n"))
 	return nil
 }
@@ -1676,15 +1909,16 @@ func commandValidateKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlag
 	}
 
 	keyspace := subFlags.Arg(0)
-	return wr.ValidateKeyspace(ctx, keyspace, *pingTablets)
+	return printValidationResult(ctx, wr, wr.ValidateKeyspace(ctx, keyspace, *pingTablets))
 }
 
 func commandMigrateServedTypes(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	cellsStr := subFlags.String("cells", "", "Specifies a comma-separated list of cells to update")
-	reverse := subFlags.Bool("reverse", false, "Moves the served tablet type backward instead of forward. Use in case of trouble")
+	reverse := subFlags.Bool("reverse", false, "Moves the served tablet type backward instead of forward. Use in case of trouble. For master, requires that the master was migrated with -reverse_replication or that its reverse replication streams exist")
 	skipReFreshState := subFlags.Bool("skip-refresh-state", false, "Skips refreshing the state of the source tablets after the migration, meaning that the refresh will need to be done manually, replica and rdonly only)")
 	filteredReplicationWaitTime := subFlags.Duration("filtered_replication_wait_time", 30*time.Second, "Specifies the maximum time to wait, in seconds, for filtered replication to catch up on master migrations")
 	reverseReplication := subFlags.Bool("reverse_replication", false, "For master migration, enabling this flag reverses replication which allows you to rollback")
+	dryRun := subFlags.Bool("dry_run", false, "Only logs which Shard and SrvKeyspace records would be rewritten and which tablets would be refreshed, without changing anything")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1707,13 +1941,17 @@ func commandMigrateServedTypes(ctx context.Context, wr *wrangler.Wrangler, subFl
 	if *cellsStr != "" {
 		cells = strings.Split(*cellsStr, ",")
 	}
+	if *dryRun {
+		return wr.MigrateServedTypesDryRun(ctx, keyspace, shard, cells, servedType, *reverse, *skipReFreshState, *reverseReplication)
+	}
 	return wr.MigrateServedTypes(ctx, keyspace, shard, cells, servedType, *reverse, *skipReFreshState, *filteredReplicationWaitTime, *reverseReplication)
 }
 
 func commandMigrateServedFrom(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
-	reverse := subFlags.Bool("reverse", false, "Moves the served tablet type backward instead of forward. Use in case of trouble")
+	reverse := subFlags.Bool("reverse", false, "Moves the served tablet type backward instead of forward. Use in case of trouble. Not supported for the master")
 	cellsStr := subFlags.String("cells", "", "Specifies a comma-separated list of cells to update")
 	filteredReplicationWaitTime := subFlags.Duration("filtered_replication_wait_time", 30*time.Second, "Specifies the maximum time to wait, in seconds, for filtered replication to catch up on master migrations")
+	dryRun := subFlags.Bool("dry_run", false, "Only logs which Keyspace, Shard and SrvKeyspace records would be rewritten, which blacklisted tables would change and which tablets would be refreshed, without changing anything")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1733,6 +1971,9 @@ func commandMigrateServedFrom(ctx context.Context, wr *wrangler.Wrangler, subFla
 	if *cellsStr != "" {
 		cells = strings.Split(*cellsStr, ",")
 	}
+	if *dryRun {
+		return wr.MigrateServedFromDryRun(ctx, keyspace, shard, servedType, cells, *reverse)
+	}
 	return wr.MigrateServedFrom(ctx, keyspace, shard, servedType, cells, *reverse, *filteredReplicationWaitTime)
 }
 
@@ -1791,39 +2032,59 @@ func commandValidate(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.
 	if subFlags.NArg() != 0 {
 		log.Warningf("action Validate doesn't take any parameter any more")
 	}
-	return wr.Validate(ctx, *pingTablets)
+	return printValidationResult(ctx, wr, wr.Validate(ctx, *pingTablets))
+}
+
+func commandTopoValidate(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	repair := subFlags.Bool("repair", false, "Repairs the safe inconsistencies")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 0 {
+		return fmt.Errorf("the TopoValidate command does not take any parameter")
+	}
+	return printValidationResult(ctx, wr, wr.TopoValidate(ctx, *repair))
 }
 
 func commandListAllTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	filterFlags := addTabletFilterFlags(subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() != 1 {
 		return fmt.Errorf("the <cell name> argument is required for the ListAllTablets command")
 	}
+	filter, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
 
 	cell := subFlags.Arg(0)
-	return dumpAllTablets(ctx, wr, cell)
+	return dumpAllTablets(ctx, wr, cell, filter)
 }
 
 func commandListTablets(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	filterFlags := addTabletFilterFlags(subFlags)
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
 	if subFlags.NArg() == 0 {
 		return fmt.Errorf("the <tablet alias> argument is required for the ListTablets command")
 	}
+	filter, err := filterFlags.filter()
+	if err != nil {
+		return err
+	}
 
 	paths := subFlags.Args()
 	aliases := make([]*topodatapb.TabletAlias, len(paths))
-	var err error
 	for i, path := range paths {
 		aliases[i], err = topoproto.ParseTabletAlias(path)
 		if err != nil {
 			return err
 		}
 	}
-	return dumpTablets(ctx, wr, aliases)
+	return dumpTablets(ctx, wr, aliases, filter)
 }
 
 func commandGetSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -1855,6 +2116,13 @@ func commandGetSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag
 		return err
 	}
 	if *tableNamesOnly {
+		if jsonOutput(ctx) {
+			names := make([]string, len(sd.TableDefinitions))
+			for i, td := range sd.TableDefinitions {
+				names[i] = td.Name
+			}
+			return printJSON(wr.Logger(), names)
+		}
 		for _, td := range sd.TableDefinitions {
 			wr.Logger().Printf("%v\n", td.Name)
 		}
@@ -1926,12 +2194,13 @@ func commandValidateSchemaShard(ctx context.Context, wr *wrangler.Wrangler, subF
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
-	return wr.ValidateSchemaShard(ctx, keyspace, shard, excludeTableArray, *includeViews)
+	return printValidationResult(ctx, wr, wr.ValidateSchemaShard(ctx, keyspace, shard, excludeTableArray, *includeViews))
 }
 
 func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	excludeTables := subFlags.String("exclude_tables", "", "Specifies a comma-separated list of tables to exclude. Each is either an exact match, or a regular expression of the form /regexp/")
 	includeViews := subFlags.Bool("include-views", false, "Includes views in the validation")
+	outputJSON := subFlags.Bool("json", jsonOutput(ctx), "Prints the differences as JSON")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1944,7 +2213,28 @@ func commandValidateSchemaKeyspace(ctx context.Context, wr *wrangler.Wrangler, s
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
-	return wr.ValidateSchemaKeyspace(ctx, keyspace, excludeTableArray, *includeViews)
+	if !*outputJSON {
+		return wr.ValidateSchemaKeyspace(ctx, keyspace, excludeTableArray, *includeViews)
+	}
+
+	diffs, err := wr.ValidateSchemaKeyspaceDiffs(ctx, keyspace, excludeTableArray, *includeViews)
+	if err != nil && diffs == nil {
+		return err
+	}
+	if diffs == nil {
+		// Print an empty list instead of null.
+		diffs = []*tmutils.SchemaDiff{}
+	}
+	if printErr := printJSON(wr.Logger(), diffs); printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("found %v schema differences in keyspace %v", len(diffs), keyspace)
+	}
+	return nil
 }
 
 func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -1952,6 +2242,8 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	sql := subFlags.String("sql", "", "A list of semicolon-delimited SQL commands")
 	sqlFile := subFlags.String("sql-file", "", "Identifies the file that contains the SQL commands")
 	waitSlaveTimeout := subFlags.Duration("wait_slave_timeout", wrangler.DefaultWaitSlaveTimeout, "The amount of time to wait for slaves to receive the schema change via replication.")
+	maxConcurrentShards := subFlags.Int("max_concurrent_shards", 0, "The maximum number of shards on which the schema change runs at the same time. 0 means all shards")
+	shardDelay := subFlags.Duration("shard_delay", 0, "The time to wait between starting the schema change on one shard and starting it on the next shard")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -1969,6 +2261,8 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	if *allowLongUnavailability {
 		executor.AllowBigSchemaChange()
 	}
+	executor.SetMaxConcurrentShards(*maxConcurrentShards)
+	executor.SetShardDelay(*shardDelay)
 	return schemamanager.Run(
 		ctx,
 		schemamanager.NewPlainController(change, keyspace),
@@ -1976,11 +2270,32 @@ func commandApplySchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *fl
 	)
 }
 
+func commandApplySchemaOnline(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	alter := subFlags.String("alter", "", "The change of the table, e.g. \"ADD COLUMN c INT\"")
+	chunkSize := subFlags.Int("chunk_size", wrangler.DefaultOnlineSchemaChangeChunkSize, "The number of rows which are copied with one statement")
+	chunkSleep := subFlags.Duration("chunk_sleep", 0, "The time to wait after each chunk")
+	maxReplicationLag := subFlags.Duration("max_replication_lag", 0, "If set, the copy waits after each chunk until the replication lag of all replicas is at most this value")
+	keepOldTable := subFlags.Bool("keep_old_table", false, "If set, the original table is kept as _<table>_old")
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 2 {
+		return fmt.Errorf("the <keyspace> and <table> arguments are required for the ApplySchemaOnline command")
+	}
+	if *alter == "" {
+		return fmt.Errorf("the -alter flag is required for the ApplySchemaOnline command")
+	}
+
+	return wr.ApplySchemaOnline(ctx, subFlags.Arg(0), subFlags.Arg(1), *alter, *chunkSize, *chunkSleep, *maxReplicationLag, !*keepOldTable)
+}
+
 func commandCopySchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	tables := subFlags.String("tables", "", "Specifies a comma-separated list of tables to copy. Each is either an exact match, or a regular expression of the form /regexp/")
 	excludeTables := subFlags.String("exclude_tables", "", "Specifies a comma-separated list of tables to exclude. Each is either an exact match, or a regular expression of the form /regexp/")
 	includeViews := subFlags.Bool("include-views", true, "Includes views in the output")
+	includeTriggers := subFlags.Bool("include-triggers", false, "Also copies the triggers of the copied tables")
 	waitSlaveTimeout := subFlags.Duration("wait_slave_timeout", 10*time.Second, "The amount of time to wait for slaves to receive the schema change via replication.")
+	waitReplicasTimeout := subFlags.Duration("wait_replicas_timeout", 0, "If set, the command fails unless all REPLICA and RDONLY tablets of the destination shard replicated the schema within this time. Otherwise, waiting for them is best-effort (see -wait_slave_timeout).")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2003,11 +2318,11 @@ func commandCopySchemaShard(ctx context.Context, wr *wrangler.Wrangler, subFlags
 
 	sourceKeyspace, sourceShard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
 	if err == nil {
-		return wr.CopySchemaShardFromShard(ctx, tableArray, excludeTableArray, *includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, *waitSlaveTimeout)
+		return wr.CopySchemaShardFromShard(ctx, tableArray, excludeTableArray, *includeViews, *includeTriggers, sourceKeyspace, sourceShard, destKeyspace, destShard, *waitSlaveTimeout, *waitReplicasTimeout)
 	}
 	sourceTabletAlias, err := topoproto.ParseTabletAlias(subFlags.Arg(0))
 	if err == nil {
-		return wr.CopySchemaShard(ctx, sourceTabletAlias, tableArray, excludeTableArray, *includeViews, destKeyspace, destShard, *waitSlaveTimeout)
+		return wr.CopySchemaShard(ctx, sourceTabletAlias, tableArray, excludeTableArray, *includeViews, *includeTriggers, destKeyspace, destShard, *waitSlaveTimeout, *waitReplicasTimeout)
 	}
 	return err
 }
@@ -2024,10 +2339,11 @@ func commandValidateVersionShard(ctx context.Context, wr *wrangler.Wrangler, sub
 	if err != nil {
 		return err
 	}
-	return wr.ValidateVersionShard(ctx, keyspace, shard)
+	return printValidationResult(ctx, wr, wr.ValidateVersionShard(ctx, keyspace, shard))
 }
 
 func commandValidateVersionKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	concurrency := subFlags.Int("concurrency", 10, "How many tablets to check in parallel")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2036,7 +2352,8 @@ func commandValidateVersionKeyspace(ctx context.Context, wr *wrangler.Wrangler,
 	}
 
 	keyspace := subFlags.Arg(0)
-	return wr.ValidateVersionKeyspace(ctx, keyspace)
+	sema := sync2.NewSemaphore(*concurrency, 0)
+	return printValidationResult(ctx, wr, wr.ValidateVersionKeyspace(ctx, keyspace, sema))
 }
 
 func commandGetPermissions(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -2069,10 +2386,11 @@ func commandValidatePermissionsShard(ctx context.Context, wr *wrangler.Wrangler,
 	if err != nil {
 		return err
 	}
-	return wr.ValidatePermissionsShard(ctx, keyspace, shard)
+	return printValidationResult(ctx, wr, wr.ValidatePermissionsShard(ctx, keyspace, shard))
 }
 
 func commandValidatePermissionsKeyspace(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	concurrency := subFlags.Int("concurrency", 10, "How many tablets to check in parallel")
 	if err := subFlags.Parse(args); err != nil {
 		return err
 	}
@@ -2081,7 +2399,8 @@ func commandValidatePermissionsKeyspace(ctx context.Context, wr *wrangler.Wrangl
 	}
 
 	keyspace := subFlags.Arg(0)
-	return wr.ValidatePermissionsKeyspace(ctx, keyspace)
+	sema := sync2.NewSemaphore(*concurrency, 0)
+	return printValidationResult(ctx, wr, wr.ValidatePermissionsKeyspace(ctx, keyspace, sema))
 }
 
 func commandGetVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
@@ -2123,6 +2442,7 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 	vschema := subFlags.String("vschema", "", "Identifies the VTGate routing schema")
 	vschemaFile := subFlags.String("vschema_file", "", "Identifies the VTGate routing schema file")
 	skipRebuild := subFlags.Bool("skip_rebuild", false, "If set, do no rebuild the SrvSchema objects.")
+	dryRun := subFlags.Bool("dry_run", false, "If set, only validate and display the VSchema, do not save it.")
 	var cells flagutil.StringListValue
 	subFlags.Var(&cells, "cells", "If specified, limits the rebuild to the cells, after upload. Ignored if skipRebuild is set.")
 
@@ -2151,6 +2471,17 @@ func commandApplyVSchema(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 		return err
 	}
 	keyspace := subFlags.Arg(0)
+	if *dryRun {
+		if err := vindexes.ValidateKeyspace(&vs); err != nil {
+			return err
+		}
+		b, err := json2.MarshalIndentPB(&vs, "  ")
+		if err != nil {
+			return err
+		}
+		wr.Logger().Printf("Dry run: VSchema for keyspace %v is valid, but was not saved:\n%s\n", keyspace, b)
+		return nil
+	}
 	if err := wr.TopoServer().SaveVSchema(ctx, keyspace, &vs); err != nil {
 		return err
 	}
@@ -2181,6 +2512,9 @@ func commandGetSrvKeyspaceNames(ctx context.Context, wr *wrangler.Wrangler, subF
 	if err != nil {
 		return err
 	}
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), srvKeyspaceNames)
+	}
 	for _, ks := range srvKeyspaceNames {
 		wr.Logger().Printf("%v\n", ks)
 	}
@@ -2325,6 +2659,46 @@ func sortReplicatingTablets(tablets []*topo.TabletInfo, stats []*replicationdata
 	return rtablets
 }
 
+// jsonOutputKeyType is the type of the context key for the JSON output mode.
+type jsonOutputKeyType int
+
+var jsonOutputKey jsonOutputKeyType
+
+// WithJSONOutput returns a context in which commands print their results
+// as JSON instead of human-readable text. RunCommand also sets it when
+// the command is preceded by a -json argument.
+func WithJSONOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, jsonOutputKey, true)
+}
+
+// jsonOutput returns true if commands should print their results as JSON.
+func jsonOutput(ctx context.Context) bool {
+	v, _ := ctx.Value(jsonOutputKey).(bool)
+	return v
+}
+
+// validationResult is printed by the Validate* commands in JSON output mode.
+type validationResult struct {
+	Valid bool
+	Error string `json:",omitempty"`
+}
+
+// printValidationResult prints the result of a validation in JSON output
+// mode, and returns the validation error.
+func printValidationResult(ctx context.Context, wr *wrangler.Wrangler, err error) error {
+	if !jsonOutput(ctx) {
+		return err
+	}
+	result := validationResult{Valid: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if printErr := printJSON(wr.Logger(), result); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
 // printJSON will print the JSON version of the structure to the logger.
 func printJSON(logger logutil.Logger, val interface{}) error {
 	data, err := MarshalJSON(val)
@@ -2382,7 +2756,8 @@ func MarshalJSON(obj interface{}) (data []byte, err error) {
 
 // RunCommand will execute the command using the provided wrangler.
 // It will return the actionPath to wait on for long remote actions if
-// applicable.
+// applicable. The command is checked against the ACLs of -vtctl_acl_file
+// and recorded in -vtctl_audit_log.
 func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error {
 	if len(args) == 0 {
 		wr.Logger().Printf("No command specified. Please see the list below:\n\n")
@@ -2390,6 +2765,14 @@ func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error
 		return fmt.Errorf("no command was specified")
 	}
 
+	if args[0] == "-json" || args[0] == "--json" {
+		ctx = WithJSONOutput(ctx)
+		args = args[1:]
+		if len(args) == 0 {
+			return fmt.Errorf("no command was specified after -json")
+		}
+	}
+
 	action := args[0]
 	actionLowerCase := strings.ToLower(action)
 	for _, group := range commands {
@@ -2402,7 +2785,14 @@ func RunCommand(ctx context.Context, wr *wrangler.Wrangler, args []string) error
 					wr.Logger().Printf("%s\n\n", cmd.help)
 					subFlags.PrintDefaults()
 				}
-				return cmd.method(ctx, wr, subFlags, args[1:])
+				start := time.Now()
+				if err := CheckActionAccess(ctx, cmd.name); err != nil {
+					AuditCommand(ctx, args, start, true, err)
+					return err
+				}
+				err := cmd.method(ctx, wr, subFlags, args[1:])
+				AuditCommand(ctx, args, start, false, err)
+				return err
 			}
 		}
 	}
@@ -23,6 +23,7 @@ import (
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/vt/workflow"
+	"vitess.io/vitess/go/vt/workflow/resharding"
 	"vitess.io/vitess/go/vt/wrangler"
 )
 
@@ -54,6 +55,21 @@ func init() {
 		commandWorkflowStop,
 		"<uuid>",
 		"Stops the workflow."})
+	addCommand(workflowsGroupName, command{
+		"WorkflowPause",
+		commandWorkflowPause,
+		"<uuid>",
+		"Pauses the running workflow. It can then be resumed from its last checkpoint with WorkflowResume."})
+	addCommand(workflowsGroupName, command{
+		"WorkflowResume",
+		commandWorkflowResume,
+		"<uuid>",
+		"Resumes the paused workflow from its last checkpoint."})
+	addCommand(workflowsGroupName, command{
+		"WorkflowRetry",
+		commandWorkflowRetry,
+		"<uuid>",
+		"Restarts the failed or stopped workflow from its last checkpoint."})
 	addCommand(workflowsGroupName, command{
 		"WorkflowDelete",
 		commandWorkflowDelete,
@@ -64,6 +80,11 @@ func init() {
 		commandWorkflowWait,
 		"<uuid>",
 		"Waits for the workflow to finish."})
+	addCommand(workflowsGroupName, command{
+		"WorkflowStatus",
+		commandWorkflowStatus,
+		"<uuid>",
+		"Displays the state of the workflow as stored in the topology. For a horizontal_resharding workflow, the state of each task of each phase is displayed as well."})
 
 	addCommand(workflowsGroupName, command{
 		"WorkflowTree",
@@ -95,7 +116,13 @@ func commandWorkflowCreate(ctx context.Context, wr *wrangler.Wrangler, subFlags
 	if err != nil {
 		return err
 	}
-	wr.Logger().Printf("uuid: %v\n", uuid)
+	if jsonOutput(ctx) {
+		if err := printJSON(wr.Logger(), map[string]string{"uuid": uuid}); err != nil {
+			return err
+		}
+	} else {
+		wr.Logger().Printf("uuid: %v\n", uuid)
+	}
 
 	if !*skipStart {
 		return WorkflowManager.Start(ctx, uuid)
@@ -133,6 +160,51 @@ func commandWorkflowStop(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 	return WorkflowManager.Stop(ctx, uuid)
 }
 
+func commandWorkflowPause(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if WorkflowManager == nil {
+		return fmt.Errorf("no workflow.Manager registered")
+	}
+
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <uuid> argument is required for the WorkflowPause command")
+	}
+	uuid := subFlags.Arg(0)
+	return WorkflowManager.Pause(ctx, uuid)
+}
+
+func commandWorkflowResume(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if WorkflowManager == nil {
+		return fmt.Errorf("no workflow.Manager registered")
+	}
+
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <uuid> argument is required for the WorkflowResume command")
+	}
+	uuid := subFlags.Arg(0)
+	return WorkflowManager.Resume(ctx, uuid)
+}
+
+func commandWorkflowRetry(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if WorkflowManager == nil {
+		return fmt.Errorf("no workflow.Manager registered")
+	}
+
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <uuid> argument is required for the WorkflowRetry command")
+	}
+	uuid := subFlags.Arg(0)
+	return WorkflowManager.Retry(ctx, uuid)
+}
+
 func commandWorkflowDelete(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	if WorkflowManager == nil {
 		return fmt.Errorf("no workflow.Manager registered")
@@ -163,6 +235,40 @@ func commandWorkflowWait(ctx context.Context, wr *wrangler.Wrangler, subFlags *f
 	return WorkflowManager.Wait(ctx, uuid)
 }
 
+func commandWorkflowStatus(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
+	if err := subFlags.Parse(args); err != nil {
+		return err
+	}
+	if subFlags.NArg() != 1 {
+		return fmt.Errorf("the <uuid> argument is required for the WorkflowStatus command")
+	}
+	uuid := subFlags.Arg(0)
+
+	// The checkpoint is read from the topology. Therefore, no running
+	// workflow.Manager is required.
+	wi, err := wr.TopoServer().GetWorkflow(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if jsonOutput(ctx) {
+		return printJSON(wr.Logger(), wi.Workflow)
+	}
+	wr.Logger().Printf("Workflow %v (%v): %v\n", wi.Uuid, wi.FactoryName, wi.Name)
+	wr.Logger().Printf("State: %v\n", wi.State)
+	if wi.Error != "" {
+		wr.Logger().Printf("Error: %v\n", wi.Error)
+	}
+	if !resharding.IsHorizontalReshardingWorkflow(wi.Workflow) {
+		return nil
+	}
+	status, err := resharding.Status(wi.Workflow)
+	if err != nil {
+		return err
+	}
+	wr.Logger().Printf("%v", status)
+	return nil
+}
+
 func commandWorkflowTree(ctx context.Context, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) error {
 	if WorkflowManager == nil {
 		return fmt.Errorf("no workflow.Manager registered")
@@ -24,6 +24,7 @@ import (
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -35,8 +36,22 @@ import (
 
 var (
 	actionTimeout = flag.Duration("action_timeout", wrangler.DefaultActionTimeout, "time to wait for an action before resorting to force")
+	userHeader    = flag.String("vtctld_user_header", "", "HTTP header containing the authenticated user, set by a trusted authenticating proxy in front of vtctld. It is used for the vtctl ACLs and audit log.")
 )
 
+// callerContext returns a context with the authenticated user of the
+// HTTP request as its immediate caller, if -vtctld_user_header is set.
+func callerContext(ctx context.Context, r *http.Request) context.Context {
+	if *userHeader == "" {
+		return ctx
+	}
+	username := r.Header.Get(*userHeader)
+	if username == "" {
+		return ctx
+	}
+	return callerid.NewContext(ctx, callerid.EffectiveCallerIDFromContext(ctx), callerid.NewImmediateCallerID(username))
+}
+
 // ActionResult contains the result of an action. If Error, the action failed.
 type ActionResult struct {
 	Name       string
@@ -115,7 +130,7 @@ func (ar *ActionRepository) ApplyKeyspaceAction(ctx context.Context, actionName,
 		return result
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, *actionTimeout)
+	ctx, cancel := context.WithTimeout(callerContext(ctx, r), *actionTimeout)
 	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
 	output, err := action(ctx, wr, keyspace, r)
 	cancel()
@@ -142,7 +157,7 @@ func (ar *ActionRepository) ApplyShardAction(ctx context.Context, actionName, ke
 		return result
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, *actionTimeout)
+	ctx, cancel := context.WithTimeout(callerContext(ctx, r), *actionTimeout)
 	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
 	output, err := action(ctx, wr, keyspace, shard, r)
 	cancel()
@@ -176,7 +191,7 @@ func (ar *ActionRepository) ApplyTabletAction(ctx context.Context, actionName st
 	}
 
 	// run the action
-	ctx, cancel := context.WithTimeout(ctx, *actionTimeout)
+	ctx, cancel := context.WithTimeout(callerContext(ctx, r), *actionTimeout)
 	wr := wrangler.New(logutil.NewConsoleLogger(), ar.ts, tmclient.NewTabletManagerClient())
 	output, err := action.method(ctx, wr, tabletAlias, r)
 	cancel()
@@ -251,6 +251,16 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository, re
 		return si.Shard, err
 	})
 
+	// Diff verification of a shard. "Not found" means the shard was never
+	// verified.
+	handleCollection("shard_diff_verifications", func(r *http.Request) (interface{}, error) {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(getItemPath(r.URL.Path))
+		if err != nil {
+			return nil, err
+		}
+		return ts.GetShardDiffVerification(ctx, keyspace, shard)
+	})
+
 	// SrvKeyspace
 	handleCollection("srv_keyspace", func(r *http.Request) (interface{}, error) {
 		keyspacePath := getItemPath(r.URL.Path)
@@ -454,6 +464,21 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository, re
 		return tabletStat, nil
 	})
 
+	// Live health of all the tablets of a shard.
+	handleCollection("shard_health", func(r *http.Request) (interface{}, error) {
+		shardPath := getItemPath(r.URL.Path)
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(shardPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shard_health path: %q  expected path: /shard_health/<keyspace>/<shard>", shardPath)
+		}
+
+		if realtimeStats == nil {
+			return nil, fmt.Errorf("realtimeStats not initialized")
+		}
+
+		return realtimeStats.shardHealth(keyspace, shard), nil
+	})
+
 	handleCollection("topology_info", func(r *http.Request) (interface{}, error) {
 		targetPath := getItemPath(r.URL.Path)
 
@@ -500,7 +525,7 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository, re
 		logstream := logutil.NewMemoryLogger()
 
 		wr := wrangler.New(logstream, ts, tmClient)
-		err := vtctl.RunCommand(r.Context(), wr, args)
+		err := vtctl.RunCommand(callerContext(r.Context(), r), wr, args)
 		if err != nil {
 			resp.Error = err.Error()
 		}
@@ -514,6 +539,9 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository, re
 		return nil
 	})
 
+	// Vtctl commands running in the background, with their logs.
+	initVtctlActions(newVtctlActionManager(ts, tmClient))
+
 	// Schema Change
 	handleAPI("schema/apply", func(w http.ResponseWriter, r *http.Request) error {
 		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
@@ -536,13 +564,29 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository, re
 		})
 		wr := wrangler.New(logger, ts, tmClient)
 
+		// This is the same as ApplySchema, so it follows the same ACLs
+		// and is audited the same way.
+		auditArgs := []string{"ApplySchema", "-sql", req.SQL, req.Keyspace}
+		callerCtx := callerContext(r.Context(), r)
+		start := time.Now()
+		if err := vtctl.CheckActionAccess(callerCtx, "ApplySchema"); err != nil {
+			vtctl.AuditCommand(callerCtx, auditArgs, start, true, err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return nil
+		}
+
 		executor := schemamanager.NewTabletExecutor(
 			wr, time.Duration(req.SlaveTimeoutSeconds)*time.Second)
 
-		return schemamanager.Run(ctx,
+		err := schemamanager.Run(ctx,
 			schemamanager.NewUIController(req.SQL, req.Keyspace, w), executor)
+		vtctl.AuditCommand(callerCtx, auditArgs, start, false, err)
+		return err
 	})
 
+	// vtworker commands
+	newVtworkerAPI(ctx).init()
+
 	// Features
 	handleAPI("features", func(w http.ResponseWriter, r *http.Request) error {
 		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
@@ -556,6 +600,8 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository, re
 		resp["showTopologyCRUD"] = *showTopologyCRUD
 		resp["showWorkflows"] = *workflowManagerInit
 		resp["workflows"] = workflow.AvailableFactories()
+		resp["showVtworker"] = *vtworkerAddr != ""
+		resp["vtworkerAddr"] = *vtworkerAddr
 		data, err := json.MarshalIndent(resp, "", "  ")
 		if err != nil {
 			return fmt.Errorf("json error: %v", err)
@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -62,6 +63,12 @@ func TestAPI(t *testing.T) {
 		si.Shard.ServedTypes = nil
 		return nil
 	})
+	ts.UpdateShardDiffVerification(ctx, "ks1", "-80", &topo.ShardDiffVerification{
+		Command:  "SplitDiff",
+		Source:   "ks1/0",
+		Position: "MariaDB/12-34-5678",
+		Time:     time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
 
 	tablet1 := topodatapb.Tablet{
 		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
@@ -151,6 +158,13 @@ func TestAPI(t *testing.T) {
 				"tablet_controls": []
 			}`},
 		{"GET", "shards/ks1/-DEAD", "", "404 page not found"},
+		{"GET", "shard_diff_verifications/ks1/-80", "", `{
+				"Command": "SplitDiff",
+				"Source": "ks1/0",
+				"Position": "MariaDB/12-34-5678",
+				"Time": "2018-01-02T03:04:05Z"
+			}`},
+		{"GET", "shard_diff_verifications/ks1/80-", "", "404 page not found"},
 		{"POST", "shards/ks1/-80?action=TestShardAction", "", `{
 				"Name": "TestShardAction",
 				"Parameters": "ks1/-80",
@@ -17,18 +17,27 @@ limitations under the License.
 package vtctld
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"path"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/golang/protobuf/jsonpb"
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/vtctl"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // backendExplorer is a class that uses the Backend interface of a
@@ -118,6 +127,122 @@ func (ex *backendExplorer) HandlePath(nodePath string, r *http.Request) *Result
 	return result
 }
 
+// topoTreeShard is one shard of the keyspace → shard → tablet tree.
+type topoTreeShard struct {
+	Name        string
+	MasterAlias string
+	Tablets     []*topoTreeTablet
+	// Error is set if the tablets of the shard couldn't all be read.
+	Error string
+}
+
+// topoTreeTablet is one tablet of the keyspace → shard → tablet tree.
+type topoTreeTablet struct {
+	Alias    string
+	Type     string
+	Hostname string
+}
+
+// topoTree returns the shards of a keyspace, with their tablets.
+func topoTree(ctx context.Context, ts *topo.Server, keyspace string) ([]*topoTreeShard, error) {
+	shards, err := ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+
+	result := make([]*topoTreeShard, 0, len(shards))
+	for _, shard := range shards {
+		node := &topoTreeShard{
+			Name:    shard,
+			Tablets: []*topoTreeTablet{},
+		}
+		result = append(result, node)
+
+		si, err := ts.GetShard(ctx, keyspace, shard)
+		if err != nil {
+			node.Error = err.Error()
+			continue
+		}
+		if si.HasMaster() {
+			node.MasterAlias = topoproto.TabletAliasString(si.MasterAlias)
+		}
+
+		tabletMap, err := ts.GetTabletMapForShard(ctx, keyspace, shard)
+		if err != nil {
+			// Display what we could read.
+			node.Error = err.Error()
+		}
+		for _, ti := range tabletMap {
+			node.Tablets = append(node.Tablets, &topoTreeTablet{
+				Alias:    topoproto.TabletAliasString(ti.Alias),
+				Type:     topoproto.TabletTypeLString(ti.Type),
+				Hostname: ti.Hostname,
+			})
+		}
+		sort.Slice(node.Tablets, func(i, j int) bool {
+			return node.Tablets[i].Alias < node.Tablets[j].Alias
+		})
+	}
+	return result, nil
+}
+
+// shardRecord is a shard record with its version, as returned by
+// the shard_record API. The version is sent back with an edit, so
+// concurrent changes are not overwritten.
+type shardRecord struct {
+	Version string
+	// Shard is the jsonpb encoding of the topodatapb.Shard.
+	Shard json.RawMessage
+}
+
+// getShardRecord returns the shard record and its version.
+func getShardRecord(ctx context.Context, ts *topo.Server, keyspace, shard string) (*shardRecord, error) {
+	si, err := ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	data, err := vtctl.MarshalJSON(si.Shard)
+	if err != nil {
+		return nil, err
+	}
+	return &shardRecord{
+		Version: si.Version().String(),
+		Shard:   data,
+	}, nil
+}
+
+// updateShardRecord replaces the editable fields of a shard record,
+// SourceShards and TabletControls, with the ones of the provided record.
+// It fails if the shard record is not at the provided version anymore.
+// It takes the keyspace lock, as required to change these fields, and
+// rebuilds the SrvKeyspace so the serving graph reflects the new record.
+func updateShardRecord(ctx context.Context, ts *topo.Server, keyspace, shard string, record *shardRecord) (err error) {
+	update := &topodatapb.Shard{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(record.Shard), update); err != nil {
+		return fmt.Errorf("cannot parse shard record: %v", err)
+	}
+
+	ctx, unlock, lockErr := ts.LockKeyspace(ctx, keyspace, "UpdateShardRecord")
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock(&err)
+
+	_, err = ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+		if version := si.Version().String(); version != record.Version {
+			return fmt.Errorf("shard %v/%v was changed since it was read (version %v instead of %v), reload it and try again", keyspace, shard, version, record.Version)
+		}
+		si.SourceShards = update.SourceShards
+		si.TabletControls = update.TabletControls
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return topotools.RebuildKeyspaceLocked(ctx, logutil.NewConsoleLogger(), ts, keyspace, nil)
+}
+
 // handleExplorerRedirect returns the redirect target URL.
 func handleExplorerRedirect(ctx context.Context, ts *topo.Server, r *http.Request) (string, error) {
 	keyspace := r.FormValue("keyspace")
@@ -172,6 +297,52 @@ func initExplorer(ts *topo.Server) {
 		return be.HandlePath(path.Clean("/"+getItemPath(r.URL.Path)), r), nil
 	})
 
+	// Keyspace → shard → tablet tree, one keyspace at a time.
+	handleCollection("topo_tree", func(r *http.Request) (interface{}, error) {
+		keyspace := getItemPath(r.URL.Path)
+		if keyspace == "" || strings.Contains(keyspace, "/") {
+			return nil, fmt.Errorf("invalid topo_tree path: %q  expected path: /topo_tree/<keyspace>", keyspace)
+		}
+		return topoTree(r.Context(), ts, keyspace)
+	})
+
+	// Shard records, with the editable fields.
+	handleCollection("shard_record", func(r *http.Request) (interface{}, error) {
+		shardPath := getItemPath(r.URL.Path)
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(shardPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shard_record path: %q  expected path: /shard_record/<keyspace>/<shard>", shardPath)
+		}
+
+		switch r.Method {
+		case "GET":
+			return getShardRecord(r.Context(), ts, keyspace, shard)
+		case "POST":
+			if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+				return nil, errors.New("access denied")
+			}
+			record := &shardRecord{}
+			if err := unmarshalRequest(r, record); err != nil {
+				return nil, fmt.Errorf("can't unmarshal request: %v", err)
+			}
+			ctx := callerContext(r.Context(), r)
+			start := time.Now()
+			auditArgs := []string{"UpdateShardRecord", shardPath, string(record.Shard)}
+			if err := vtctl.CheckActionAccess(ctx, "UpdateShardRecord"); err != nil {
+				vtctl.AuditCommand(ctx, auditArgs, start, true, err)
+				return nil, err
+			}
+			err := updateShardRecord(ctx, ts, keyspace, shard, record)
+			vtctl.AuditCommand(ctx, auditArgs, start, false, err)
+			if err != nil {
+				return nil, err
+			}
+			return getShardRecord(r.Context(), ts, keyspace, shard)
+		default:
+			return nil, fmt.Errorf("unsupported HTTP method: %v", r.Method)
+		}
+	})
+
 	// Redirects for explorers.
 	http.HandleFunc("/explorers/redirect", func(w http.ResponseWriter, r *http.Request) {
 		if err := r.ParseForm(); err != nil {
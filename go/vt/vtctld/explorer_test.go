@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -215,3 +216,132 @@ func TestHandleBadPath(t *testing.T) {
 		t.Errorf("HandlePath(%q) = %v, want %v", input, got, want)
 	}
 }
+
+func TestTopoTree(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1", "cell2")
+	if err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace error: %v", err)
+	}
+	for _, shard := range []string{"80-", "-80"} {
+		if err := ts.CreateShard(ctx, "test_keyspace", shard); err != nil {
+			t.Fatalf("CreateShard error: %v", err)
+		}
+	}
+	tablets := []*topodatapb.Tablet{{
+		Alias:    &topodatapb.TabletAlias{Cell: "cell2", Uid: 200},
+		Hostname: "host2",
+		Keyspace: "test_keyspace",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_REPLICA,
+	}, {
+		Alias:    &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+		Hostname: "host1",
+		Keyspace: "test_keyspace",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_MASTER,
+	}}
+	for _, tablet := range tablets {
+		if err := ts.CreateTablet(ctx, tablet); err != nil {
+			t.Fatalf("CreateTablet error: %v", err)
+		}
+	}
+	if _, err := ts.UpdateShardFields(ctx, "test_keyspace", "-80", func(si *topo.ShardInfo) error {
+		si.Cells = []string{"cell1", "cell2"}
+		si.MasterAlias = tablets[1].Alias
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardFields error: %v", err)
+	}
+
+	got, err := topoTree(ctx, ts, "test_keyspace")
+	if err != nil {
+		t.Fatalf("topoTree failed: %v", err)
+	}
+	want := []*topoTreeShard{{
+		Name:        "-80",
+		MasterAlias: "cell1-0000000100",
+		Tablets: []*topoTreeTablet{
+			{Alias: "cell1-0000000100", Type: "master", Hostname: "host1"},
+			{Alias: "cell2-0000000200", Type: "replica", Hostname: "host2"},
+		},
+	}, {
+		Name:    "80-",
+		Tablets: []*topoTreeTablet{},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("topoTree() = %v, want %v", got, want)
+	}
+
+	if _, err := topoTree(ctx, ts, "unknown_keyspace"); err == nil {
+		t.Errorf("topoTree(unknown_keyspace) should have failed")
+	}
+}
+
+func TestUpdateShardRecord(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	if err := ts.CreateKeyspace(ctx, "test_keyspace", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace error: %v", err)
+	}
+	for _, shard := range []string{"-80", "80-"} {
+		if err := ts.CreateShard(ctx, "test_keyspace", shard); err != nil {
+			t.Fatalf("CreateShard error: %v", err)
+		}
+		if _, err := ts.UpdateShardFields(ctx, "test_keyspace", shard, func(si *topo.ShardInfo) error {
+			si.Cells = []string{"cell1"}
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateShardFields error: %v", err)
+		}
+	}
+
+	record, err := getShardRecord(ctx, ts, "test_keyspace", "-80")
+	if err != nil {
+		t.Fatalf("getShardRecord failed: %v", err)
+	}
+	oldVersion := record.Version
+
+	// Only SourceShards and TabletControls are changed.
+	record.Shard = []byte(`{
+  "master_alias": {"cell": "cell1", "uid": 1},
+  "source_shards": [{"uid": 1, "keyspace": "source_keyspace", "shard": "0"}],
+  "tablet_controls": [{"tablet_type": 3, "disable_query_service": true}]
+}`)
+	if err := updateShardRecord(ctx, ts, "test_keyspace", "-80", record); err != nil {
+		t.Fatalf("updateShardRecord failed: %v", err)
+	}
+	si, err := ts.GetShard(ctx, "test_keyspace", "-80")
+	if err != nil {
+		t.Fatalf("GetShard failed: %v", err)
+	}
+	if si.HasMaster() {
+		t.Errorf("MasterAlias was changed: %v", si.MasterAlias)
+	}
+	wantSourceShards := []*topodatapb.Shard_SourceShard{{Uid: 1, Keyspace: "source_keyspace", Shard: "0"}}
+	if !reflect.DeepEqual(si.SourceShards, wantSourceShards) {
+		t.Errorf("SourceShards = %v, want %v", si.SourceShards, wantSourceShards)
+	}
+	wantTabletControls := []*topodatapb.Shard_TabletControl{{TabletType: topodatapb.TabletType_RDONLY, DisableQueryService: true}}
+	if !reflect.DeepEqual(si.TabletControls, wantTabletControls) {
+		t.Errorf("TabletControls = %v, want %v", si.TabletControls, wantTabletControls)
+	}
+
+	// The SrvKeyspace was rebuilt.
+	if _, err := ts.GetSrvKeyspace(ctx, "cell1", "test_keyspace"); err != nil {
+		t.Errorf("GetSrvKeyspace failed: %v", err)
+	}
+
+	// Editing with the old version fails, the record was changed since.
+	record.Version = oldVersion
+	record.Shard = []byte(`{}`)
+	if err := updateShardRecord(ctx, ts, "test_keyspace", "-80", record); err == nil || !strings.Contains(err.Error(), "was changed since it was read") {
+		t.Errorf("updateShardRecord with an old version returned %v", err)
+	}
+
+	// Invalid records are rejected.
+	record.Shard = []byte(`{"source_shards": 12}`)
+	if err := updateShardRecord(ctx, ts, "test_keyspace", "-80", record); err == nil {
+		t.Errorf("updateShardRecord with an invalid record should have failed")
+	}
+}
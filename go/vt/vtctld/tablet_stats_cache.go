@@ -519,6 +519,85 @@ func (c *tabletStatsCache) tabletStats(tabletAlias *topodatapb.TabletAlias) (dis
 	return *ts, nil
 }
 
+// shardTabletHealth is the live health of one tablet of a shard,
+// as displayed in the shard view.
+type shardTabletHealth struct {
+	Alias    *topodatapb.TabletAlias
+	Hostname string
+	Type     string
+	Serving  bool
+	// Health is one of "healthy", "degraded" or "unhealthy".
+	Health string
+	// HealthError is the health error reported by the tablet.
+	HealthError string
+	// LastError is the last error of the health check stream.
+	LastError string
+	// ReplicationLag is in seconds.
+	ReplicationLag uint32
+	Qps            float64
+}
+
+// healthNames maps the values returned by health to their names.
+var healthNames = map[float64]string{
+	tabletHealthy:   "healthy",
+	tabletDegraded:  "degraded",
+	tabletUnhealthy: "unhealthy",
+}
+
+// shardHealth returns the live health of all the tablets of a shard,
+// in all cells. They are sorted by cell, type and uid.
+func (c *tabletStatsCache) shardHealth(keyspace, shard string) []*shardTabletHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stats []*discovery.TabletStats
+	for _, types := range c.statuses[keyspace][shard] {
+		for _, tablets := range types {
+			stats = append(stats, tablets...)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i].Tablet, stats[j].Tablet
+		if a.Alias.Cell != b.Alias.Cell {
+			return a.Alias.Cell < b.Alias.Cell
+		}
+		if ai, bi := tabletTypeIndex(a.Type), tabletTypeIndex(b.Type); ai != bi {
+			return ai < bi
+		}
+		return a.Alias.Uid < b.Alias.Uid
+	})
+
+	result := make([]*shardTabletHealth, 0, len(stats))
+	for _, stat := range stats {
+		h := &shardTabletHealth{
+			Alias:          stat.Tablet.Alias,
+			Hostname:       stat.Tablet.Hostname,
+			Type:           topoproto.TabletTypeLString(stat.Tablet.Type),
+			Serving:        stat.Serving,
+			Health:         healthNames[health(stat)],
+			HealthError:    stat.Stats.HealthError,
+			ReplicationLag: stat.Stats.SecondsBehindMaster,
+			Qps:            stat.Stats.Qps,
+		}
+		if stat.LastError != nil {
+			h.LastError = stat.LastError.Error()
+		}
+		result = append(result, h)
+	}
+	return result
+}
+
+// tabletTypeIndex orders the tablet types like availableTabletTypes,
+// and puts all the other types after them.
+func tabletTypeIndex(tabletType topodatapb.TabletType) int {
+	for i, t := range availableTabletTypes {
+		if t == tabletType {
+			return i
+		}
+	}
+	return len(availableTabletTypes) + int(tabletType)
+}
+
 func health(stat *discovery.TabletStats) float64 {
 	// The tablet is unhealthy if there is an health error.
 	if stat.Stats.HealthError != "" {
@@ -426,6 +426,72 @@ func TestTopologyInfo(t *testing.T) {
 	}
 }
 
+func TestShardHealth(t *testing.T) {
+	ts1 := tabletStats("ks1", "cell2", "-80", topodatapb.TabletType_REPLICA, 20)
+	ts2 := tabletStats("ks1", "cell1", "-80", topodatapb.TabletType_RDONLY, 30)
+	ts3 := tabletStats("ks1", "cell1", "-80", topodatapb.TabletType_MASTER, 10)
+	ts4 := tabletStats("ks1", "cell1", "-80", topodatapb.TabletType_SPARE, 5)
+	ts5 := tabletStats("ks1", "cell1", "-80", topodatapb.TabletType_REPLICA, 100)
+	ts6 := tabletStats("ks1", "cell1", "80-", topodatapb.TabletType_MASTER, 40)
+	ts1.Stats.Qps = 12.5
+	ts2.Stats.HealthError = "mysqld is down"
+	ts4.Serving = false
+
+	tabletStatsCache := newTabletStatsCache()
+	for _, ts := range []*discovery.TabletStats{ts1, ts2, ts3, ts4, ts5, ts6} {
+		tabletStatsCache.StatsUpdate(ts)
+	}
+
+	// Tablets are sorted by cell, type and uid.
+	want := []*shardTabletHealth{{
+		Alias:          ts3.Tablet.Alias,
+		Type:           "master",
+		Serving:        true,
+		Health:         "healthy",
+		ReplicationLag: 10,
+	}, {
+		Alias:          ts5.Tablet.Alias,
+		Type:           "replica",
+		Serving:        true,
+		Health:         "degraded",
+		ReplicationLag: 100,
+	}, {
+		Alias:          ts2.Tablet.Alias,
+		Type:           "rdonly",
+		Serving:        true,
+		Health:         "unhealthy",
+		HealthError:    "mysqld is down",
+		ReplicationLag: 30,
+	}, {
+		Alias:          ts4.Tablet.Alias,
+		Type:           "spare",
+		Health:         "degraded",
+		ReplicationLag: 5,
+	}, {
+		Alias:          ts1.Tablet.Alias,
+		Type:           "replica",
+		Serving:        true,
+		Health:         "healthy",
+		ReplicationLag: 20,
+		Qps:            12.5,
+	}}
+	got := tabletStatsCache.shardHealth("ks1", "-80")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("shardHealth(ks1/-80):")
+		for _, h := range got {
+			t.Errorf("  got:  %+v", h)
+		}
+		for _, h := range want {
+			t.Errorf("  want: %+v", h)
+		}
+	}
+
+	// An unknown shard has no tablets.
+	if got := tabletStatsCache.shardHealth("ks2", "0"); len(got) != 0 {
+		t.Errorf("shardHealth(ks2/0) = %v, want empty", got)
+	}
+}
+
 // tabletStats will create a discovery.TabletStats object.
 func tabletStats(keyspace, cell, shard string, tabletType topodatapb.TabletType, uid uint32) *discovery.TabletStats {
 	target := &querypb.Target{
@@ -0,0 +1,368 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+// This file implements the asynchronous execution of vtctl commands.
+// Commands like ApplySchema or ValidateKeyspace can take minutes, so
+// vtctld runs them in the background, and their log is streamed to
+// the browser over a WebSocket while they run. The log of the most
+// recent commands is kept, so it can be looked at after they are done.
+
+var (
+	vtctlActionTimeout = flag.Duration("vtctl_action_timeout", time.Hour, "maximum duration of a vtctl command started asynchronously from the vtctld web interface")
+	vtctlActionHistory = flag.Int("vtctl_action_history", 100, "number of finished asynchronous vtctl commands for which vtctld keeps the log")
+)
+
+// vtctlAction is a vtctl command running, or run, in the background.
+type vtctlAction struct {
+	id    int64
+	args  []string
+	user  string
+	start time.Time
+
+	// mu protects the fields below.
+	mu     sync.Mutex
+	events []*logutilpb.Event
+	done   bool
+	end    time.Time
+	err    error
+	// changed is closed and replaced every time an event is added
+	// or the command finishes, to wake up the streams.
+	changed chan struct{}
+}
+
+// vtctlActionStatus is the JSON representation of a vtctlAction.
+type vtctlActionStatus struct {
+	ID     int64
+	Args   []string
+	User   string
+	Start  time.Time
+	End    time.Time
+	Done   bool
+	Error  string   `json:",omitempty"`
+	Output []string `json:",omitempty"`
+}
+
+// vtctlActionMessage is sent on the WebSocket of a vtctlAction: one per
+// log line, and a last one when the command is done.
+type vtctlActionMessage struct {
+	Line  string `json:",omitempty"`
+	Done  bool   `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+func (a *vtctlAction) addEvent(ev *logutilpb.Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, ev)
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+func (a *vtctlAction) finish(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.done = true
+	a.end = time.Now()
+	a.err = err
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// eventsSince returns the events after the first n ones, a channel
+// closed on the next change, and whether the command is done.
+func (a *vtctlAction) eventsSince(n int) ([]*logutilpb.Event, <-chan struct{}, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.events[n:], a.changed, a.done
+}
+
+// status returns the status of the action. The log is only included if
+// withOutput is set.
+func (a *vtctlAction) status(withOutput bool) *vtctlActionStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := &vtctlActionStatus{
+		ID:    a.id,
+		Args:  a.args,
+		User:  a.user,
+		Start: a.start,
+		End:   a.end,
+		Done:  a.done,
+	}
+	if a.err != nil {
+		s.Error = a.err.Error()
+	}
+	if withOutput {
+		for _, ev := range a.events {
+			s.Output = append(s.Output, logutil.EventString(ev))
+		}
+	}
+	return s
+}
+
+// vtctlActionManager runs and keeps track of the vtctlActions.
+type vtctlActionManager struct {
+	ts       *topo.Server
+	tmClient tmclient.TabletManagerClient
+
+	// mu protects the fields below.
+	mu      sync.Mutex
+	nextID  int64
+	actions map[int64]*vtctlAction
+}
+
+func newVtctlActionManager(ts *topo.Server, tmClient tmclient.TabletManagerClient) *vtctlActionManager {
+	return &vtctlActionManager{
+		ts:       ts,
+		tmClient: tmClient,
+		nextID:   1,
+		actions:  make(map[int64]*vtctlAction),
+	}
+}
+
+// start runs a vtctl command in the background, and returns its action.
+// ctx is only used for the caller id, the command outlives the request.
+func (m *vtctlActionManager) start(ctx context.Context, args []string) (*vtctlAction, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no command was specified")
+	}
+
+	m.mu.Lock()
+	a := &vtctlAction{
+		id:      m.nextID,
+		args:    args,
+		user:    vtctl.CallerUsername(ctx),
+		start:   time.Now(),
+		changed: make(chan struct{}),
+	}
+	m.nextID++
+	m.actions[a.id] = a
+	m.pruneLocked()
+	m.mu.Unlock()
+
+	wr := wrangler.New(logutil.NewCallbackLogger(a.addEvent), m.ts, m.tmClient)
+	cmdCtx, cancel := context.WithTimeout(context.Background(), *vtctlActionTimeout)
+	cmdCtx = callerContextFrom(cmdCtx, ctx)
+	go func() {
+		defer cancel()
+		a.finish(vtctl.RunCommand(cmdCtx, wr, args))
+	}()
+	return a, nil
+}
+
+// pruneLocked removes the oldest finished actions above the history size.
+// m.mu must be held.
+func (m *vtctlActionManager) pruneLocked() {
+	var finished []int64
+	for id, a := range m.actions {
+		a.mu.Lock()
+		if a.done {
+			finished = append(finished, id)
+		}
+		a.mu.Unlock()
+	}
+	if len(finished) <= *vtctlActionHistory {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i] < finished[j] })
+	for _, id := range finished[:len(finished)-*vtctlActionHistory] {
+		delete(m.actions, id)
+	}
+}
+
+func (m *vtctlActionManager) get(id int64) (*vtctlAction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.actions[id]
+	if !ok {
+		return nil, topo.NewError(topo.NoNode, fmt.Sprintf("vtctl action %v", id))
+	}
+	return a, nil
+}
+
+// list returns the status of all actions, most recent first.
+func (m *vtctlActionManager) list() []*vtctlActionStatus {
+	m.mu.Lock()
+	actions := make([]*vtctlAction, 0, len(m.actions))
+	for _, a := range m.actions {
+		actions = append(actions, a)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(actions, func(i, j int) bool { return actions[i].id > actions[j].id })
+	result := make([]*vtctlActionStatus, 0, len(actions))
+	for _, a := range actions {
+		result = append(result, a.status(false))
+	}
+	return result
+}
+
+// stream sends the log of an action to send, as it is produced, until
+// the command is done, the context is canceled, or send fails.
+func (a *vtctlAction) stream(ctx context.Context, send func(*vtctlActionMessage) error) error {
+	n := 0
+	for {
+		events, changed, done := a.eventsSince(n)
+		for _, ev := range events {
+			if err := send(&vtctlActionMessage{Line: logutil.EventString(ev)}); err != nil {
+				return err
+			}
+		}
+		n += len(events)
+		if done {
+			s := a.status(false)
+			return send(&vtctlActionMessage{Done: true, Error: s.Error})
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// callerContextFrom copies the immediate caller id of from into ctx.
+func callerContextFrom(ctx, from context.Context) context.Context {
+	username := vtctl.CallerUsername(from)
+	if username == "" {
+		return ctx
+	}
+	return callerid.NewContext(ctx, nil, callerid.NewImmediateCallerID(username))
+}
+
+// initVtctlActions registers the API of the asynchronous vtctl commands:
+//
+//	POST vtctl_actions/ with the JSON list of arguments starts a command.
+//	GET vtctl_actions/ lists the commands.
+//	GET vtctl_actions/<id> returns a command with its log.
+//	vtctl_action_stream/<id> is a WebSocket streaming the log of a command.
+func initVtctlActions(m *vtctlActionManager) {
+	handleCollection("vtctl_actions", func(r *http.Request) (interface{}, error) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			return nil, errors.New("access denied")
+		}
+		itemPath := getItemPath(r.URL.Path)
+
+		switch r.Method {
+		case "POST":
+			if itemPath != "" {
+				return nil, errors.New("a POST request cannot have an id in the URL")
+			}
+			var args []string
+			if err := unmarshalRequest(r, &args); err != nil {
+				return nil, fmt.Errorf("can't unmarshal request: %v", err)
+			}
+			a, err := m.start(callerContext(r.Context(), r), args)
+			if err != nil {
+				return nil, err
+			}
+			return a.status(false), nil
+		case "GET":
+			if itemPath == "" {
+				return m.list(), nil
+			}
+			id, err := strconv.ParseInt(itemPath, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vtctl action id %q: %v", itemPath, err)
+			}
+			a, err := m.get(id)
+			if err != nil {
+				return nil, err
+			}
+			return a.status(true), nil
+		default:
+			return nil, fmt.Errorf("unsupported HTTP method: %v", r.Method)
+		}
+	})
+
+	upgrader := websocket.Upgrader{}
+	http.HandleFunc(apiPrefix+"vtctl_action_stream/", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+		itemPath := getItemPath(r.URL.Path)
+		id, err := strconv.ParseInt(strings.TrimSuffix(itemPath, "/"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid vtctl action id %q: %v", itemPath, err), http.StatusBadRequest)
+			return
+		}
+		a, err := m.get(id)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Errorf("upgrade error: %v", err)
+			return
+		}
+		defer c.Close()
+
+		// Stop streaming when the browser goes away. We don't expect
+		// any message from it.
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			for {
+				if _, _, err := c.ReadMessage(); err != nil {
+					cancel()
+					return
+				}
+			}
+		}()
+
+		if err := a.stream(ctx, func(msg *vtctlActionMessage) error {
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			return c.WriteMessage(websocket.TextMessage, data)
+		}); err != nil && err != context.Canceled {
+			log.Warningf("streaming vtctl action %v failed: %v", id, err)
+		}
+	})
+}
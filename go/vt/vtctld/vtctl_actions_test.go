@@ -0,0 +1,134 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// waitForAction streams the log of an action until it is done.
+func waitForAction(t *testing.T, a *vtctlAction) []*vtctlActionMessage {
+	var messages []*vtctlActionMessage
+	if err := a.stream(context.Background(), func(msg *vtctlActionMessage) error {
+		messages = append(messages, msg)
+		return nil
+	}); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	return messages
+}
+
+func TestVtctlActions(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	for _, keyspace := range []string{"ks1", "ks2"} {
+		if err := ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}); err != nil {
+			t.Fatalf("CreateKeyspace failed: %v", err)
+		}
+	}
+	m := newVtctlActionManager(ts, nil)
+
+	// A successful command streams its output, then Done.
+	a1, err := m.start(ctx, []string{"GetKeyspaces"})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	want := []*vtctlActionMessage{
+		{Line: "ks1\nks2\n"},
+		{Done: true},
+	}
+	if got := waitForAction(t, a1); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyspaces messages: got %v, want %v", got, want)
+	}
+
+	// Streaming again replays the whole log.
+	if got := waitForAction(t, a1); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetKeyspaces replayed messages: got %v, want %v", got, want)
+	}
+
+	// The log is kept in the status.
+	status := a1.status(true)
+	if !status.Done || status.Error != "" || !reflect.DeepEqual(status.Output, []string{"ks1\nks2\n"}) {
+		t.Errorf("GetKeyspaces status: %+v", status)
+	}
+
+	// A failed command ends with its error.
+	a2, err := m.start(ctx, []string{"GetKeyspace", "unknown_keyspace"})
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	messages := waitForAction(t, a2)
+	last := messages[len(messages)-1]
+	if !last.Done || !strings.Contains(last.Error, "node doesn't exist") {
+		t.Errorf("GetKeyspace unknown_keyspace last message: %+v", last)
+	}
+
+	// Commands are listed most recent first.
+	var ids []int64
+	for _, s := range m.list() {
+		ids = append(ids, s.ID)
+	}
+	if want := []int64{a2.id, a1.id}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("list: got ids %v, want %v", ids, want)
+	}
+
+	if _, err := m.get(12345); !topo.IsErrType(err, topo.NoNode) {
+		t.Errorf("get(unknown) returned %v, want NoNode", err)
+	}
+	if _, err := m.start(ctx, nil); err == nil {
+		t.Errorf("start without a command should have failed")
+	}
+}
+
+func TestVtctlActionsHistory(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	m := newVtctlActionManager(ts, nil)
+
+	oldHistory := *vtctlActionHistory
+	*vtctlActionHistory = 2
+	defer func() { *vtctlActionHistory = oldHistory }()
+
+	var actions []*vtctlAction
+	for i := 0; i < 4; i++ {
+		a, err := m.start(ctx, []string{"GetKeyspaces"})
+		if err != nil {
+			t.Fatalf("start failed: %v", err)
+		}
+		waitForAction(t, a)
+		actions = append(actions, a)
+	}
+
+	// The three first actions were finished when the last one was
+	// started, so only the two most recent of them are kept.
+	var ids []int64
+	for _, s := range m.list() {
+		ids = append(ids, s.ID)
+	}
+	if want := []int64{actions[3].id, actions[2].id, actions[1].id}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("list: got ids %v, want %v", ids, want)
+	}
+}
@@ -29,7 +29,10 @@ import (
 	"vitess.io/vitess/go/vt/log"
 
 	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl"
 	"vitess.io/vitess/go/vt/wrangler"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -65,12 +68,12 @@ func InitVtctld(ts *topo.Server) {
 
 	actionRepo.RegisterKeyspaceAction("ValidateVersionKeyspace",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace string, r *http.Request) (string, error) {
-			return "", wr.ValidateVersionKeyspace(ctx, keyspace)
+			return "", wr.ValidateVersionKeyspace(ctx, keyspace, sync2.NewSemaphore(10, 0))
 		})
 
 	actionRepo.RegisterKeyspaceAction("ValidatePermissionsKeyspace",
 		func(ctx context.Context, wr *wrangler.Wrangler, keyspace string, r *http.Request) (string, error) {
-			return "", wr.ValidatePermissionsKeyspace(ctx, keyspace)
+			return "", wr.ValidatePermissionsKeyspace(ctx, keyspace, sync2.NewSemaphore(10, 0))
 		})
 
 	// shard actions
@@ -115,7 +118,8 @@ func InitVtctld(ts *topo.Server) {
 
 	actionRepo.RegisterTabletAction("DeleteTablet", acl.ADMIN,
 		func(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias, r *http.Request) (string, error) {
-			return "", wr.DeleteTablet(ctx, tabletAlias, false)
+			// Go through vtctl, so the ACLs and audit log apply.
+			return "", vtctl.RunCommand(ctx, wr, []string{"DeleteTablet", topoproto.TabletAliasString(tabletAlias)})
 		})
 
 	actionRepo.RegisterTabletAction("ReloadSchema", acl.ADMIN,
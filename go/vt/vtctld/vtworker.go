@@ -0,0 +1,277 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/flagutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/worker/vtworkerclient"
+
+	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
+)
+
+// This file implements the part of the REST API which lets the vtctld web
+// interface launch and monitor vtworker commands e.g. SplitClone or SplitDiff.
+
+var (
+	vtworkerAddr = flag.String("vtctld_vtworker_addr", "", "If set, the vtctld UI shows actions to run vtworker commands (e.g. SplitClone and SplitDiff) on the vtworker with this RPC address (host:port).")
+	// vtworkerAllowedAddrs has the addresses of the vtworkers which may be
+	// used in addition to --vtctld_vtworker_addr. Requests for any other
+	// address are rejected such that vtctld cannot be used to connect to
+	// arbitrary hosts.
+	vtworkerAllowedAddrs flagutil.StringListValue
+)
+
+func init() {
+	flag.Var(&vtworkerAllowedAddrs, "vtctld_vtworker_allowed_addrs", "comma separated list of vtworker RPC addresses (host:port) which may be used by the vtworker actions of the vtctld UI in addition to --vtctld_vtworker_addr")
+}
+
+// vtworkerCommand is a vtworker command which was launched by this vtctld.
+type vtworkerCommand struct {
+	args   []string
+	logger *logutil.MemoryLogger
+
+	// mu guards all fields below.
+	mu      sync.Mutex
+	running bool
+	err     error
+}
+
+// vtworkerAPI runs vtworker commands in the background. The RPC which
+// executes a command blocks until the command has finished. Therefore, the
+// UI does not wait for it and polls the status of the vtworker instead.
+type vtworkerAPI struct {
+	// ctx is the context of vtctld. Commands are not canceled when the HTTP
+	// request which launched them returns.
+	ctx context.Context
+
+	// mu guards commands.
+	mu sync.Mutex
+	// commands has the last command per vtworker address.
+	commands map[string]*vtworkerCommand
+}
+
+func newVtworkerAPI(ctx context.Context) *vtworkerAPI {
+	return &vtworkerAPI{
+		ctx:      ctx,
+		commands: make(map[string]*vtworkerCommand),
+	}
+}
+
+// vtworkerRequest is the body of all POST requests.
+type vtworkerRequest struct {
+	// Server is the RPC address of the vtworker.
+	// If empty, --vtctld_vtworker_addr is used. Otherwise, it must be
+	// --vtctld_vtworker_addr or listed in --vtctld_vtworker_allowed_addrs.
+	Server string
+	// Args is the vtworker command and its flags. Only used by "execute".
+	Args []string
+}
+
+// vtworkerStatus is the response of the "status" request. It combines the
+// status reported by the vtworker with the output of the command which this
+// vtctld has captured.
+type vtworkerStatus struct {
+	Server string
+
+	// Fields reported by the vtworker.
+	Worker string
+	State  string
+	Status string
+	Done   bool
+	Error  string
+
+	// Fields of the last command launched by this vtctld.
+	Args         []string
+	Running      bool
+	CommandError string
+	Output       string
+}
+
+func (api *vtworkerAPI) init() {
+	handleAPI("vtworker/execute", api.execute)
+	handleAPI("vtworker/status", api.status)
+	handleAPI("vtworker/cancel", api.control(vtworkerclient.Client.Cancel))
+	handleAPI("vtworker/pause", api.control(vtworkerclient.Client.Pause))
+	handleAPI("vtworker/resume", api.control(vtworkerclient.Client.Resume))
+}
+
+// server returns the vtworker address which should be used for a request.
+// It fails if "server" is neither --vtctld_vtworker_addr nor allowed by
+// --vtctld_vtworker_allowed_addrs.
+func (api *vtworkerAPI) server(server string) (string, error) {
+	if server == "" {
+		if *vtworkerAddr == "" {
+			return "", errors.New("no vtworker address specified and --vtctld_vtworker_addr is not set")
+		}
+		return *vtworkerAddr, nil
+	}
+	if server == *vtworkerAddr {
+		return server, nil
+	}
+	for _, addr := range vtworkerAllowedAddrs {
+		if server == addr {
+			return server, nil
+		}
+	}
+	return "", fmt.Errorf("vtworker address %v is not allowed: it must be --vtctld_vtworker_addr or listed in --vtctld_vtworker_allowed_addrs", server)
+}
+
+func (api *vtworkerAPI) execute(w http.ResponseWriter, r *http.Request) error {
+	if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+		return nil
+	}
+	var req vtworkerRequest
+	if err := unmarshalRequest(r, &req); err != nil {
+		return fmt.Errorf("can't unmarshal request: %v", err)
+	}
+	server, err := api.server(req.Server)
+	if err != nil {
+		return err
+	}
+	if len(req.Args) == 0 {
+		return errors.New("no vtworker command specified")
+	}
+
+	api.mu.Lock()
+	if cmd, ok := api.commands[server]; ok {
+		cmd.mu.Lock()
+		running := cmd.running
+		cmd.mu.Unlock()
+		if running {
+			api.mu.Unlock()
+			return fmt.Errorf("vtworker %v is still running the command: %v", server, cmd.args)
+		}
+	}
+	cmd := &vtworkerCommand{
+		args:    req.Args,
+		logger:  logutil.NewMemoryLogger(),
+		running: true,
+	}
+	api.commands[server] = cmd
+	api.mu.Unlock()
+
+	go func() {
+		log.Infof("running vtworker command on %v: %v", server, cmd.args)
+		err := vtworkerclient.RunCommandAndWait(api.ctx, server, cmd.args, func(e *logutilpb.Event) {
+			logutil.LogEvent(cmd.logger, e)
+		})
+		if err != nil {
+			log.Warningf("vtworker command on %v failed: %v: %v", server, cmd.args, err)
+		}
+		cmd.mu.Lock()
+		cmd.running = false
+		cmd.err = err
+		cmd.mu.Unlock()
+	}()
+
+	return writeJSON(w, struct{ Server string }{server})
+}
+
+func (api *vtworkerAPI) status(w http.ResponseWriter, r *http.Request) error {
+	if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+		return nil
+	}
+	server, err := api.server(r.FormValue("server"))
+	if err != nil {
+		return err
+	}
+
+	resp := vtworkerStatus{Server: server}
+	api.mu.Lock()
+	cmd := api.commands[server]
+	api.mu.Unlock()
+	if cmd != nil {
+		cmd.mu.Lock()
+		resp.Args = cmd.args
+		resp.Running = cmd.running
+		if cmd.err != nil {
+			resp.CommandError = cmd.err.Error()
+		}
+		cmd.mu.Unlock()
+		resp.Output = cmd.logger.String()
+	}
+
+	client, err := vtworkerclient.New(server)
+	if err != nil {
+		return fmt.Errorf("cannot dial to vtworker %v: %v", server, err)
+	}
+	defer client.Close()
+	status, err := client.GetStatus(r.Context())
+	if err != nil {
+		return fmt.Errorf("cannot get the status of vtworker %v: %v", server, err)
+	}
+	resp.Worker = status.Worker
+	resp.State = status.State
+	resp.Status = status.Status
+	resp.Done = status.Done
+	resp.Error = status.Error
+
+	return writeJSON(w, resp)
+}
+
+// control returns a handler which calls "f" e.g. Cancel on the vtworker.
+func (api *vtworkerAPI) control(f func(vtworkerclient.Client, context.Context) (bool, error)) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			http.Error(w, "403 Forbidden", http.StatusForbidden)
+			return nil
+		}
+		var req vtworkerRequest
+		if err := unmarshalRequest(r, &req); err != nil {
+			return fmt.Errorf("can't unmarshal request: %v", err)
+		}
+		server, err := api.server(req.Server)
+		if err != nil {
+			return err
+		}
+
+		client, err := vtworkerclient.New(server)
+		if err != nil {
+			return fmt.Errorf("cannot dial to vtworker %v: %v", server, err)
+		}
+		defer client.Close()
+		ok, err := f(client, r.Context())
+		if err != nil {
+			return fmt.Errorf("vtworker %v: %v", server, err)
+		}
+		return writeJSON(w, struct{ Result bool }{ok})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json error: %v", err)
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	w.Write(data)
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/worker/fakevtworkerclient"
+	"vitess.io/vitess/go/vt/worker/vtworkerclient"
+)
+
+func TestVtworkerAPI(t *testing.T) {
+	fake := fakevtworkerclient.NewFakeVtworkerClient()
+	vtworkerclient.RegisterFactory("fake", fake.FakeVtworkerClientFactory)
+	defer vtworkerclient.UnregisterFactoryForTest("fake")
+	flag.Set("vtworker_client_protocol", "fake")
+	defer flag.Set("vtworker_client_protocol", "grpc")
+
+	args := []string{"SplitDiff", "ks/-80"}
+	fake.RegisterResultForAddr("vtworker1:15033", args, "diff done", nil)
+
+	api := newVtworkerAPI(context.Background())
+
+	// No server.
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/vtworker/execute", strings.NewReader(`{"Args": ["SplitDiff", "ks/-80"]}`))
+	if err := api.execute(w, r); err == nil || !strings.Contains(err.Error(), "--vtctld_vtworker_addr is not set") {
+		t.Errorf("execute() without a server must fail, got: %v", err)
+	}
+
+	// Only allowed servers may be used.
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/api/vtworker/execute", strings.NewReader(`{"Server": "vtworker1:15033", "Args": ["SplitDiff", "ks/-80"]}`))
+	if err := api.execute(w, r); err == nil || !strings.Contains(err.Error(), "is not allowed") {
+		t.Errorf("execute() with a server which is not allowed must fail, got: %v", err)
+	}
+	flag.Set("vtctld_vtworker_allowed_addrs", "vtworker1:15033")
+	defer func() { vtworkerAllowedAddrs = nil }()
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/api/vtworker/execute", strings.NewReader(`{"Server": "vtworker1:15033", "Args": ["SplitDiff", "ks/-80"]}`))
+	if err := api.execute(w, r); err != nil {
+		t.Fatalf("execute() failed: %v", err)
+	}
+
+	// The command runs in the background. Poll until it's done.
+	var status vtworkerStatus
+	for {
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest("GET", "/api/vtworker/status?server=vtworker1:15033", nil)
+		if err := api.status(w, r); err != nil {
+			t.Fatalf("status() failed: %v", err)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+			t.Fatal(err)
+		}
+		if !status.Running {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status.CommandError != "" {
+		t.Errorf("command failed: %v", status.CommandError)
+	}
+	if got, want := strings.Join(status.Args, " "), "SplitDiff ks/-80"; got != want {
+		t.Errorf("wrong command in status: got = %v, want = %v", got, want)
+	}
+	if !strings.Contains(status.Output, "diff done") {
+		t.Errorf("command output missing in status: %v", status.Output)
+	}
+	if len(fake.RegisteredCommands()) != 0 {
+		t.Errorf("not all registered commands were run: %v", fake.RegisteredCommands())
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/api/vtworker/cancel", strings.NewReader(`{"Server": "vtworker1:15033"}`))
+	if err := api.control(vtworkerclient.Client.Cancel)(w, r); err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	if got, want := compactJSON(w.Body.Bytes()), `{"Result":false}`; got != want {
+		t.Errorf("cancel returned: got = %v, want = %v", got, want)
+	}
+}
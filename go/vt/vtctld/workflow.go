@@ -99,14 +99,8 @@ func runWorkflowManagerElection(ts *topo.Server) {
 	// We use servenv.ListeningURL which is only populated during Run,
 	// so we have to start this with OnRun.
 	servenv.OnRun(func() {
-		ctx := context.Background()
-		conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
-		if err != nil {
-			log.Errorf("Cannot get global cell topo connection, disabling workflow manager: %v", err)
-			return
-		}
-
-		mp, err = conn.NewMasterParticipation("vtctld", servenv.ListeningURL.Host)
+		var err error
+		mp, err = ts.NewMasterParticipation(context.Background(), topo.GlobalCell, "vtctld", servenv.ListeningURL.Host)
 		if err != nil {
 			log.Errorf("Cannot start MasterParticipation, disabling workflow manager: %v", err)
 			return
@@ -119,24 +113,13 @@ func runWorkflowManagerElection(ts *topo.Server) {
 			return mp.GetCurrentMasterID(ctx)
 		})
 
-		go func() {
-			for {
-				ctx, err := mp.WaitForMastership()
-				switch {
-				case err == nil:
-					vtctl.WorkflowManager.Run(ctx)
-				case topo.IsErrType(err, topo.Interrupted):
-					return
-				default:
-					log.Errorf("Got error while waiting for master, will retry in 5s: %v", err)
-					time.Sleep(5 * time.Second)
-				}
-			}
-		}()
+		go topo.RunAsMaster(mp, 5*time.Second, vtctl.WorkflowManager.Run)
 	})
 
 	// When we get killed, clean up.
 	servenv.OnTermSync(func() {
-		mp.Stop()
+		if mp != nil {
+			mp.Stop()
+		}
 	})
 }
@@ -254,6 +254,11 @@ func causedByFailover(err error) bool {
 		// (which it does to avoid logging the original query including any PII).
 		strings.Contains(err.Error(), "(errno 1290) (sqlstate HY000) during query:"):
 		return true
+	// The old master was already demoted by a reparent, but the
+	// healthcheck still considers it the master.
+	case strings.Contains(err.Error(), "invalid tablet type: MASTER") ||
+		strings.Contains(err.Error(), "transactional statement disallowed on non-master tablet"):
+		return true
 	// MariaDB flavor.
 	case strings.Contains(err.Error(), "The MariaDB server is running with the --read-only option so it cannot execute this statement (errno 1290) (sqlstate HY000)"):
 		return true
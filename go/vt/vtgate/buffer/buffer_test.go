@@ -939,3 +939,30 @@ func checkVariables(t *testing.T) {
 		}
 	}
 }
+
+func TestCausedByFailover(t *testing.T) {
+	testcases := []struct {
+		err  error
+		want bool
+	}{{
+		err:  failoverErr,
+		want: true,
+	}, {
+		err:  nonFailoverErr,
+		want: false,
+	}, {
+		err:  vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "vttablet: rpc error: code = 9 desc = invalid tablet type: MASTER, want: REPLICA or []"),
+		want: true,
+	}, {
+		err:  vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "vttablet: rpc error: code = 9 desc = transactional statement disallowed on non-master tablet: REPLICA"),
+		want: true,
+	}, {
+		err:  vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "vttablet: rpc error: code = 3 desc = invalid tablet type: MASTER, want: REPLICA or []"),
+		want: false,
+	}}
+	for _, tcase := range testcases {
+		if got := causedByFailover(tcase.err); got != tcase.want {
+			t.Errorf("causedByFailover(%v) = %v, want %v", tcase.err, got, tcase.want)
+		}
+	}
+}
@@ -30,8 +30,10 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // noopVCursor is used to build other vcursors.
@@ -46,6 +48,10 @@ func (t noopVCursor) SetContextTimeout(timeout time.Duration) context.CancelFunc
 	return func() {}
 }
 
+func (t noopVCursor) SetTabletType(tabletType topodatapb.TabletType) error {
+	return nil
+}
+
 func (t noopVCursor) RecordWarning(warning *querypb.QueryWarning) {
 }
 
@@ -57,7 +63,11 @@ func (t noopVCursor) ExecuteAutocommit(method string, query string, bindvars map
 	panic("unimplemented")
 }
 
-func (t noopVCursor) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, autocommit bool) (*sqltypes.Result, []error) {
+func (t noopVCursor) ExecutePostCommit(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error) {
+	panic("unimplemented")
+}
+
+func (t noopVCursor) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, autocommit bool, limiter *ResultLimiter) (*sqltypes.Result, []error) {
 	panic("unimplemented")
 }
 
@@ -65,6 +75,10 @@ func (t noopVCursor) AutocommitApproval() bool {
 	panic("unimplemented")
 }
 
+func (t noopVCursor) ScatterLimits() (maxShards, maxRows, maxBytes int) {
+	return 0, 0, 0
+}
+
 func (t noopVCursor) ExecuteStandalone(query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
 	panic("unimplemented")
 }
@@ -97,6 +111,9 @@ type loggingVCursor struct {
 	// multi-shard queries
 	multiShardErrs []error
 
+	// Optional limits returned by ScatterLimits.
+	maxShards, maxRows, maxBytes int
+
 	log []string
 }
 
@@ -108,6 +125,11 @@ func (f *loggingVCursor) SetContextTimeout(timeout time.Duration) context.Cancel
 	return func() {}
 }
 
+func (f *loggingVCursor) SetTabletType(tabletType topodatapb.TabletType) error {
+	f.log = append(f.log, fmt.Sprintf("SetTabletType %v", topoproto.TabletTypeLString(tabletType)))
+	return nil
+}
+
 func (f *loggingVCursor) RecordWarning(warning *querypb.QueryWarning) {
 	f.warnings = append(f.warnings, warning)
 }
@@ -117,12 +139,15 @@ func (f *loggingVCursor) Execute(method string, query string, bindvars map[strin
 	return f.nextResult()
 }
 
-func (f *loggingVCursor) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, canAutocommit bool) (*sqltypes.Result, []error) {
+func (f *loggingVCursor) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, canAutocommit bool, limiter *ResultLimiter) (*sqltypes.Result, []error) {
 	f.log = append(f.log, fmt.Sprintf("ExecuteMultiShard %v%v %v", printResolvedShardQueries(rss, queries), isDML, canAutocommit))
 	res, err := f.nextResult()
 	if err != nil {
 		return nil, []error{err}
 	}
+	if err := limiter.Add(res); err != nil {
+		return nil, []error{err}
+	}
 
 	return res, f.multiShardErrs
 }
@@ -131,6 +156,10 @@ func (f *loggingVCursor) AutocommitApproval() bool {
 	return true
 }
 
+func (f *loggingVCursor) ScatterLimits() (maxShards, maxRows, maxBytes int) {
+	return f.maxShards, f.maxRows, f.maxBytes
+}
+
 func (f *loggingVCursor) ExecuteStandalone(query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
 	f.log = append(f.log, fmt.Sprintf("ExecuteStandalone %s %v %s %s", query, printBindVars(bindvars), rs.Target.Keyspace, rs.Target.Shard))
 	return f.nextResult()
@@ -220,7 +220,7 @@ func (ins *Insert) execInsertSharded(vcursor VCursor, bindVars map[string]*query
 	}
 
 	autocommit := (len(rss) == 1 || ins.MultiShardAutocommit) && vcursor.AutocommitApproval()
-	result, errs := vcursor.ExecuteMultiShard(rss, queries, true /* isDML */, autocommit)
+	result, errs := vcursor.ExecuteMultiShard(rss, queries, true /* isDML */, autocommit, nil)
 	if errs != nil {
 		return nil, vterrors.Wrap(vterrors.Aggregate(errs), "execInsertSharded")
 	}
@@ -259,15 +259,16 @@ func (ins *Insert) processGenerate(vcursor VCursor, bindVars map[string]*querypb
 			return 0, vterrors.Wrap(err, "processGenerate")
 		}
 		if len(rss) != 1 {
-			return 0, vterrors.Wrapf(err, "processGenerate len(rss)=%v", len(rss))
+			return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "processGenerate len(rss)=%v", len(rss))
 		}
 		bindVars := map[string]*querypb.BindVariable{"n": sqltypes.Int64BindVariable(count)}
 		qr, err := vcursor.ExecuteStandalone(ins.Generate.Query, bindVars, rss[0])
 		if err != nil {
 			return 0, err
 		}
-		// If no rows are returned, it's an internal error, and the code
-		// must panic, which will be caught and reported.
+		if len(qr.Rows) != 1 || len(qr.Rows[0]) == 0 {
+			return 0, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "processGenerate: unexpected result from sequence: %v", qr.Rows)
+		}
 		insertID, err = sqltypes.ToInt64(qr.Rows[0][0])
 		if err != nil {
 			return 0, err
@@ -119,6 +119,40 @@ func TestInsertUnshardedGenerate(t *testing.T) {
 	expectResult(t, "Execute", result, &sqltypes.Result{InsertID: 4})
 }
 
+func TestInsertGenerateNoRows(t *testing.T) {
+	ins := &Insert{
+		Opcode: InsertUnsharded,
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		Query: "dummy_insert",
+		Generate: &Generate{
+			Keyspace: &vindexes.Keyspace{
+				Name:    "ks2",
+				Sharded: false,
+			},
+			Query: "dummy_generate",
+			Values: sqltypes.PlanValue{
+				Values: []sqltypes.PlanValue{
+					{Value: sqltypes.NULL},
+				},
+			},
+		},
+	}
+
+	vc := &loggingVCursor{
+		shards:  []string{"0"},
+		results: []*sqltypes.Result{{}},
+	}
+	_, err := ins.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	expectError(t, "Execute", err, "execInsertUnsharded: processGenerate: unexpected result from sequence: []")
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks2 [] Destinations:DestinationAnyShard()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"1"  ks2 0`,
+	})
+}
+
 func TestInsertShardedSimple(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
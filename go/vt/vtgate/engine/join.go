@@ -18,6 +18,8 @@ package engine
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"vitess.io/vitess/go/sqltypes"
 
@@ -67,13 +69,23 @@ func (jn *Join) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariab
 		result.Fields = joinFields(lresult.Fields, rresult.Fields, jn.Cols)
 		return result, nil
 	}
+	// Rows of the LHS often share the same join values, for instance
+	// when joining on a low-cardinality column. The RHS is executed only
+	// once per distinct set of join values.
+	varCols := jn.varCols()
+	rresults := make(map[string]*sqltypes.Result)
 	for _, lrow := range lresult.Rows {
-		for k, col := range jn.Vars {
-			joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
-		}
-		rresult, err := jn.Right.Execute(vcursor, combineVars(bindVars, joinVars), wantfields)
-		if err != nil {
-			return nil, err
+		key := joinVarsKey(lrow, varCols)
+		rresult, ok := rresults[key]
+		if !ok {
+			for k, col := range jn.Vars {
+				joinVars[k] = sqltypes.ValueBindVariable(lrow[col])
+			}
+			rresult, err = jn.Right.Execute(vcursor, combineVars(bindVars, joinVars), wantfields)
+			if err != nil {
+				return nil, err
+			}
+			rresults[key] = rresult
 		}
 		if wantfields {
 			wantfields = false
@@ -92,6 +104,35 @@ func (jn *Join) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariab
 	return result, nil
 }
 
+// varCols returns the LHS columns of the join vars, in a stable order.
+func (jn *Join) varCols() []int {
+	cols := make([]int, 0, len(jn.Vars))
+	for _, col := range jn.Vars {
+		cols = append(cols, col)
+	}
+	sort.Ints(cols)
+	return cols
+}
+
+// joinVarsKey returns a key that identifies the values of cols in lrow.
+func joinVarsKey(lrow []sqltypes.Value, cols []int) string {
+	var key []byte
+	for _, col := range cols {
+		v := lrow[col]
+		if v.IsNull() {
+			key = append(key, 'N')
+			continue
+		}
+		raw := v.Raw()
+		key = strconv.AppendInt(key, int64(v.Type()), 10)
+		key = append(key, ':')
+		key = strconv.AppendInt(key, int64(len(raw)), 10)
+		key = append(key, ':')
+		key = append(key, raw...)
+	}
+	return string(key)
+}
+
 // StreamExecute performs a streaming exec.
 func (jn *Join) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	joinVars := make(map[string]*querypb.BindVariable)
@@ -178,6 +178,74 @@ func TestJoinExecuteNoResult(t *testing.T) {
 	expectResult(t, "jn.Execute", r, wantResult)
 }
 
+func TestJoinExecuteDuplicateVars(t *testing.T) {
+	leftPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields(
+					"col1|col2",
+					"int64|varchar",
+				),
+				"1|a",
+				"2|b",
+				"3|a",
+				"4|null",
+				"5|null",
+			),
+		},
+	}
+	rightFields := sqltypes.MakeTestFields(
+		"col3",
+		"varchar",
+	)
+	rightPrim := &fakePrimitive{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(
+				rightFields,
+				"aa",
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+			),
+			sqltypes.MakeTestResult(
+				rightFields,
+				"nn",
+			),
+		},
+	}
+
+	// The RHS is executed once per distinct join value.
+	jn := &Join{
+		Opcode: LeftJoin,
+		Left:   leftPrim,
+		Right:  rightPrim,
+		Cols:   []int{-1, 1},
+		Vars: map[string]int{
+			"bv": 1,
+		},
+	}
+	r, err := jn.Execute(nil, map[string]*querypb.BindVariable{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rightPrim.ExpectLog(t, []string{
+		`Execute bv: type:VARCHAR value:"a"  true`,
+		`Execute bv: type:VARCHAR value:"b"  false`,
+		`Execute bv:  false`,
+	})
+	expectResult(t, "jn.Execute", r, sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"col1|col3",
+			"int64|varchar",
+		),
+		"1|aa",
+		"2|null",
+		"3|aa",
+		"4|nn",
+		"5|nn",
+	))
+}
+
 func TestJoinExecuteErrors(t *testing.T) {
 	// Error on left query
 	leftPrim := &fakePrimitive{
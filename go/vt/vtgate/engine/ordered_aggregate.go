@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
@@ -45,6 +46,11 @@ type OrderedAggregate struct {
 	// from the result received. If 0, no truncation happens.
 	TruncateColumnCount int `json:",omitempty"`
 
+	// Having specifies the conditions of the HAVING clause that
+	// are evaluated on the aggregated rows. Rows that don't
+	// satisfy all the conditions are dropped.
+	Having []HavingParams `json:",omitempty"`
+
 	// Input is the primitive that will feed into this Primitive.
 	Input Primitive
 }
@@ -56,6 +62,16 @@ type AggregateParams struct {
 	Col    int
 }
 
+// HavingParams specify a condition of the HAVING clause:
+// the value of column Col is compared to Value using Operator,
+// which is one of the sqlparser comparison operators
+// (=, !=, <, <=, >, >=).
+type HavingParams struct {
+	Col      int
+	Operator string
+	Value    sqltypes.PlanValue
+}
+
 // AggregateOpcode is the aggregation Opcode.
 type AggregateOpcode int
 
@@ -135,11 +151,15 @@ func (oa *OrderedAggregate) execute(vcursor VCursor, bindVars map[string]*queryp
 			}
 			continue
 		}
-		out.Rows = append(out.Rows, current)
+		if err := oa.appendRow(out, current, bindVars); err != nil {
+			return nil, err
+		}
 		current = row
 	}
 	if current != nil {
-		out.Rows = append(out.Rows, current)
+		if err := oa.appendRow(out, current, bindVars); err != nil {
+			return nil, err
+		}
 	}
 	out.RowsAffected = uint64(len(out.Rows))
 	return out, nil
@@ -153,6 +173,13 @@ func (oa *OrderedAggregate) StreamExecute(vcursor VCursor, bindVars map[string]*
 	cb := func(qr *sqltypes.Result) error {
 		return callback(qr.Truncate(oa.TruncateColumnCount))
 	}
+	send := func(row []sqltypes.Value) error {
+		match, err := oa.matchHaving(row, bindVars)
+		if err != nil || !match {
+			return err
+		}
+		return cb(&sqltypes.Result{Rows: [][]sqltypes.Value{row}})
+	}
 
 	err := oa.Input.StreamExecute(vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
 		if len(qr.Fields) != 0 {
@@ -180,7 +207,7 @@ func (oa *OrderedAggregate) StreamExecute(vcursor VCursor, bindVars map[string]*
 				}
 				continue
 			}
-			if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{current}}); err != nil {
+			if err := send(current); err != nil {
 				return err
 			}
 			current = row
@@ -192,7 +219,7 @@ func (oa *OrderedAggregate) StreamExecute(vcursor VCursor, bindVars map[string]*
 	}
 
 	if current != nil {
-		if err := cb(&sqltypes.Result{Rows: [][]sqltypes.Value{current}}); err != nil {
+		if err := send(current); err != nil {
 			return err
 		}
 	}
@@ -208,6 +235,58 @@ func (oa *OrderedAggregate) GetFields(vcursor VCursor, bindVars map[string]*quer
 	return qr.Truncate(oa.TruncateColumnCount), nil
 }
 
+// appendRow appends row to out if it satisfies the HAVING conditions.
+func (oa *OrderedAggregate) appendRow(out *sqltypes.Result, row []sqltypes.Value, bindVars map[string]*querypb.BindVariable) error {
+	match, err := oa.matchHaving(row, bindVars)
+	if err != nil {
+		return err
+	}
+	if match {
+		out.Rows = append(out.Rows, row)
+	}
+	return nil
+}
+
+// matchHaving returns true if the aggregated row satisfies all the
+// HAVING conditions. Like in MySQL, a comparison with NULL is never
+// satisfied.
+func (oa *OrderedAggregate) matchHaving(row []sqltypes.Value, bindVars map[string]*querypb.BindVariable) (bool, error) {
+	for _, having := range oa.Having {
+		value, err := having.Value.ResolveValue(bindVars)
+		if err != nil {
+			return false, err
+		}
+		if row[having.Col].IsNull() || value.IsNull() {
+			return false, nil
+		}
+		cmp, err := sqltypes.NullsafeCompare(row[having.Col], value)
+		if err != nil {
+			return false, err
+		}
+		var match bool
+		switch having.Operator {
+		case sqlparser.EqualStr:
+			match = cmp == 0
+		case sqlparser.NotEqualStr:
+			match = cmp != 0
+		case sqlparser.LessThanStr:
+			match = cmp < 0
+		case sqlparser.LessEqualStr:
+			match = cmp <= 0
+		case sqlparser.GreaterThanStr:
+			match = cmp > 0
+		case sqlparser.GreaterEqualStr:
+			match = cmp >= 0
+		default:
+			return false, fmt.Errorf("BUG: unexpected operator: %v", having.Operator)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (oa *OrderedAggregate) keysEqual(row1, row2 []sqltypes.Value) (bool, error) {
 	for _, key := range oa.Keys {
 		cmp, err := sqltypes.NullsafeCompare(row1[key], row2[key])
@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
 func TestOrderedAggregateExecute(t *testing.T) {
@@ -156,6 +158,84 @@ func TestOrderedAggregateStreamExecute(t *testing.T) {
 	}
 }
 
+func TestOrderedAggregateHaving(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"col|count(*)",
+		"varbinary|decimal",
+	)
+	fp := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(
+			fields,
+			"a|1",
+			"a|1",
+			"b|2",
+			"c|3",
+			"c|4",
+			"d|null",
+		)},
+	}
+
+	oa := &OrderedAggregate{
+		Aggregates: []AggregateParams{{
+			Opcode: AggregateCount,
+			Col:    1,
+		}},
+		Keys: []int{0},
+		Having: []HavingParams{{
+			Col:      1,
+			Operator: ">=",
+			Value:    sqltypes.PlanValue{Value: sqltypes.NewInt64(2)},
+		}, {
+			Col:      1,
+			Operator: "!=",
+			Value:    sqltypes.PlanValue{Key: "v"},
+		}},
+		Input: fp,
+	}
+
+	bv := map[string]*querypb.BindVariable{
+		"v": sqltypes.Int64BindVariable(7),
+	}
+	result, err := oa.Execute(nil, bv, false)
+	if err != nil {
+		t.Error(err)
+	}
+
+	wantResult := sqltypes.MakeTestResult(
+		fields,
+		"a|2",
+		"b|2",
+	)
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("oa.Execute:\n%v, want\n%v", result, wantResult)
+	}
+
+	fp.rewind()
+	var results []*sqltypes.Result
+	err = oa.StreamExecute(nil, bv, false, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	wantResults := sqltypes.MakeTestStreamingResults(
+		fields,
+		"a|2",
+		"---",
+		"b|2",
+	)
+	if !reflect.DeepEqual(results, wantResults) {
+		t.Errorf("oa.StreamExecute:\n%s, want\n%s", sqltypes.PrintResults(results), sqltypes.PrintResults(wantResults))
+	}
+
+	// A missing bind variable is an error.
+	fp.rewind()
+	_, err = oa.Execute(nil, nil, false)
+	expectError(t, "oa.Execute", err, "missing bind var v")
+}
+
 func TestOrderedAggregateStreamExecuteTruncate(t *testing.T) {
 	fp := &fakePrimitive{
 		results: []*sqltypes.Result{sqltypes.MakeTestResult(
@@ -25,8 +25,10 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/tableacl/acl"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // SeqVarName is a reserved bind var name for sequence values.
@@ -46,16 +48,28 @@ type VCursor interface {
 	// SetContextTimeout updates the context and sets a timeout.
 	SetContextTimeout(timeout time.Duration) context.CancelFunc
 
+	// SetTabletType changes the tablet type the query is sent to.
+	SetTabletType(tabletType topodatapb.TabletType) error
+
 	// RecordWarning stores the given warning in the current session
 	RecordWarning(warning *querypb.QueryWarning)
 
 	// V3 functions.
 	Execute(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error)
 	ExecuteAutocommit(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error)
+	ExecutePostCommit(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error)
 	AutocommitApproval() bool
 
+	// ScatterLimits returns the maximum number of shards a query can
+	// be sent to, and the maximum number of rows and bytes a query can
+	// return. 0 means no limit.
+	ScatterLimits() (maxShards, maxRows, maxBytes int)
+
 	// Shard-level functions.
-	ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, canAutocommit bool) (*sqltypes.Result, []error)
+	// ExecuteMultiShard merges the results of all the shards. If limiter
+	// is not nil, it fails as soon as the merged result goes over its
+	// limits.
+	ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, canAutocommit bool, limiter *ResultLimiter) (*sqltypes.Result, []error)
 	ExecuteStandalone(query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error)
 	StreamExecuteMulti(query string, rss []*srvtopo.ResolvedShard, bindVars []map[string]*querypb.BindVariable, callback func(reply *sqltypes.Result) error) error
 
@@ -75,6 +89,9 @@ type Plan struct {
 	// Instructions contains the instructions needed to
 	// fulfil the query.
 	Instructions Primitive `json:",omitempty"`
+	// Permissions are the roles the query needs on its tables. vtgate
+	// checks them against its table ACL, if one is configured.
+	Permissions []acl.TablePermission `json:"-"`
 	// Mutex to protect the stats
 	mu sync.Mutex
 	// Count of times this plan was executed
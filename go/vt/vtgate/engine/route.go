@@ -28,10 +28,13 @@ import (
 	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var _ Primitive = (*Route)(nil)
@@ -74,8 +77,16 @@ type Route struct {
 	// QueryTimeout contains the optional timeout (in milliseconds) to apply to this query
 	QueryTimeout int
 
+	// TabletType contains the optional tablet type to send this query to,
+	// instead of the one of the session. UNKNOWN means no override.
+	TabletType topodatapb.TabletType
+
 	// ScatterErrorsAsWarnings is true if results should be returned even if some shards have an error
 	ScatterErrorsAsWarnings bool
+
+	// IgnoreScatterLimits is true if the query is exempt from the
+	// limits returned by VCursor.ScatterLimits.
+	IgnoreScatterLimits bool
 }
 
 // OrderbyParams specifies the parameters for ordering.
@@ -92,6 +103,10 @@ func (route *Route) MarshalJSON() ([]byte, error) {
 	if route.Vindex != nil {
 		vindexName = route.Vindex.String()
 	}
+	var tabletType string
+	if route.TabletType != topodatapb.TabletType_UNKNOWN {
+		tabletType = topoproto.TabletTypeLString(route.TabletType)
+	}
 	marshalRoute := struct {
 		Opcode                  RouteOpcode
 		Keyspace                *vindexes.Keyspace   `json:",omitempty"`
@@ -102,7 +117,9 @@ func (route *Route) MarshalJSON() ([]byte, error) {
 		OrderBy                 []OrderbyParams      `json:",omitempty"`
 		TruncateColumnCount     int                  `json:",omitempty"`
 		QueryTimeout            int                  `json:",omitempty"`
+		TabletType              string               `json:",omitempty"`
 		ScatterErrorsAsWarnings bool                 `json:",omitempty"`
+		IgnoreScatterLimits     bool                 `json:",omitempty"`
 	}{
 		Opcode:                  route.Opcode,
 		Keyspace:                route.Keyspace,
@@ -113,7 +130,9 @@ func (route *Route) MarshalJSON() ([]byte, error) {
 		OrderBy:                 route.OrderBy,
 		TruncateColumnCount:     route.TruncateColumnCount,
 		QueryTimeout:            route.QueryTimeout,
+		TabletType:              tabletType,
 		ScatterErrorsAsWarnings: route.ScatterErrorsAsWarnings,
+		IgnoreScatterLimits:     route.IgnoreScatterLimits,
 	}
 	return jsonutil.MarshalNoEscape(marshalRoute)
 }
@@ -180,6 +199,11 @@ func (route *Route) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVa
 		cancel := vcursor.SetContextTimeout(time.Duration(route.QueryTimeout) * time.Millisecond)
 		defer cancel()
 	}
+	if route.TabletType != topodatapb.TabletType_UNKNOWN {
+		if err := vcursor.SetTabletType(route.TabletType); err != nil {
+			return nil, err
+		}
+	}
 	qr, err := route.execute(vcursor, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -219,8 +243,16 @@ func (route *Route) execute(vcursor VCursor, bindVars map[string]*querypb.BindVa
 		return &sqltypes.Result{}, nil
 	}
 
+	if err := route.checkShardLimit(vcursor, rss); err != nil {
+		return nil, err
+	}
+
 	queries := getQueries(route.Query, bvs)
-	result, errs := vcursor.ExecuteMultiShard(rss, queries, false /* isDML */, false /* autocommit */)
+	limiter := route.resultLimiter(vcursor)
+	result, errs := vcursor.ExecuteMultiShard(rss, queries, false /* isDML */, false /* autocommit */, limiter)
+	if err := limiter.Err(); err != nil {
+		return nil, err
+	}
 
 	if errs != nil {
 		if route.ScatterErrorsAsWarnings {
@@ -253,6 +285,11 @@ func (route *Route) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.
 		cancel := vcursor.SetContextTimeout(time.Duration(route.QueryTimeout) * time.Millisecond)
 		defer cancel()
 	}
+	if route.TabletType != topodatapb.TabletType_UNKNOWN {
+		if err := vcursor.SetTabletType(route.TabletType); err != nil {
+			return err
+		}
+	}
 	switch route.Opcode {
 	case SelectUnsharded, SelectScatter:
 		rss, bvs, err = route.paramsAllShards(vcursor, bindVars)
@@ -279,6 +316,10 @@ func (route *Route) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.
 		return nil
 	}
 
+	if err := route.checkShardLimit(vcursor, rss); err != nil {
+		return err
+	}
+
 	if len(route.OrderBy) == 0 {
 		return vcursor.StreamExecuteMulti(route.Query, rss, bvs, func(qr *sqltypes.Result) error {
 			return callback(qr.Truncate(route.TruncateColumnCount))
@@ -290,6 +331,76 @@ func (route *Route) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.
 	})
 }
 
+// checkShardLimit returns an error if the query would be sent to
+// more shards than allowed.
+func (route *Route) checkShardLimit(vcursor VCursor, rss []*srvtopo.ResolvedShard) error {
+	if maxShards, _, _ := vcursor.ScatterLimits(); maxShards > 0 && !route.IgnoreScatterLimits && len(rss) > maxShards {
+		return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "query would be sent to %d shards, more than the allowed limit of %d", len(rss), maxShards)
+	}
+	return nil
+}
+
+// resultLimiter returns the limiter for the results of the route, or
+// nil if they are not limited. It's only used by Execute, which buffers
+// the results: StreamExecute sends them on as they come. The limits apply
+// to each execution of the route. For instance, the right side of a join
+// is executed once per row of the left side, and each of these results
+// is counted separately.
+func (route *Route) resultLimiter(vcursor VCursor) *ResultLimiter {
+	if route.IgnoreScatterLimits {
+		return nil
+	}
+	_, maxRows, maxBytes := vcursor.ScatterLimits()
+	return NewResultLimiter(maxRows, maxBytes)
+}
+
+// ResultLimiter counts the rows and bytes returned by a query, and fails
+// as soon as they go over the limits. It's not thread safe. A nil
+// ResultLimiter has no limits.
+type ResultLimiter struct {
+	maxRows, maxBytes int
+	rows, bytes       int
+	err               error
+}
+
+// NewResultLimiter returns a ResultLimiter, or nil if there is no
+// limit. 0 means no limit.
+func NewResultLimiter(maxRows, maxBytes int) *ResultLimiter {
+	if maxRows <= 0 && maxBytes <= 0 {
+		return nil
+	}
+	return &ResultLimiter{maxRows: maxRows, maxBytes: maxBytes}
+}
+
+// Add counts the rows of qr. It returns an error if the total goes
+// over the limits. The error is also returned by all the next calls.
+func (rl *ResultLimiter) Add(qr *sqltypes.Result) error {
+	if rl == nil || rl.err != nil {
+		return rl.Err()
+	}
+	rl.rows += len(qr.Rows)
+	for _, row := range qr.Rows {
+		for _, v := range row {
+			rl.bytes += len(v.Raw())
+		}
+	}
+	switch {
+	case rl.maxRows > 0 && rl.rows > rl.maxRows:
+		rl.err = vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "in-memory row count exceeded allowed limit of %d", rl.maxRows)
+	case rl.maxBytes > 0 && rl.bytes > rl.maxBytes:
+		rl.err = vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "in-memory result size exceeded allowed limit of %d bytes", rl.maxBytes)
+	}
+	return rl.err
+}
+
+// Err returns the error of the first Add that went over the limits.
+func (rl *ResultLimiter) Err() error {
+	if rl == nil {
+		return nil
+	}
+	return rl.err
+}
+
 // GetFields fetches the field info.
 func (route *Route) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	rss, _, err := vcursor.ResolveDestinations(route.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
@@ -450,7 +561,7 @@ func execShard(vcursor VCursor, query string, bindVars map[string]*querypb.BindV
 			Sql:           query,
 			BindVariables: bindVars,
 		},
-	}, isDML, autocommit)
+	}, isDML, autocommit, nil)
 	return result, vterrors.Aggregate(errs)
 }
 
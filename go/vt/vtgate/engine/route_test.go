@@ -23,6 +23,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -108,6 +109,122 @@ func TestSelectScatter(t *testing.T) {
 	expectResult(t, "sel.StreamExecute", result, defaultSelectResult)
 }
 
+func TestSelectTabletType(t *testing.T) {
+	sel := &Route{
+		Opcode: SelectScatter,
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		Query:      "dummy_select",
+		FieldQuery: "dummy_select_field",
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+
+	vc := &loggingVCursor{
+		shards:  []string{"-20", "20-"},
+		results: []*sqltypes.Result{defaultSelectResult},
+	}
+	result, err := sel.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vc.ExpectLog(t, []string{
+		`SetTabletType replica`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_select {} ks.20-: dummy_select {} false false`,
+	})
+	expectResult(t, "sel.Execute", result, defaultSelectResult)
+
+	vc.Rewind()
+	result, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vc.ExpectLog(t, []string{
+		`SetTabletType replica`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`StreamExecuteMulti dummy_select ks.-20: {} ks.20-: {} `,
+	})
+	expectResult(t, "sel.StreamExecute", result, defaultSelectResult)
+}
+
+func TestSelectScatterLimits(t *testing.T) {
+	sel := &Route{
+		Opcode: SelectScatter,
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		Query:      "dummy_select",
+		FieldQuery: "dummy_select_field",
+	}
+
+	// Too many shards.
+	vc := &loggingVCursor{
+		shards:    []string{"-20", "20-40", "40-"},
+		results:   []*sqltypes.Result{defaultSelectResult},
+		maxShards: 2,
+	}
+	_, err := sel.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	expectError(t, "sel.Execute", err, "query would be sent to 3 shards, more than the allowed limit of 2")
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+	})
+
+	vc.Rewind()
+	_, err = wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false)
+	expectError(t, "sel.StreamExecute", err, "query would be sent to 3 shards, more than the allowed limit of 2")
+
+	// Too many rows.
+	result := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields(
+			"id",
+			"int64",
+		),
+		"1",
+		"2",
+		"3",
+	)
+	vc = &loggingVCursor{
+		shards:  []string{"-20", "20-"},
+		results: []*sqltypes.Result{result},
+		maxRows: 2,
+	}
+	_, err = sel.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	expectError(t, "sel.Execute", err, "in-memory row count exceeded allowed limit of 2")
+
+	// Streaming results are not held in memory and are not limited.
+	vc.Rewind()
+	if _, err := wrapStreamExecute(sel, vc, map[string]*querypb.BindVariable{}, false); err != nil {
+		t.Errorf("sel.StreamExecute: %v, want nil", err)
+	}
+
+	// Too many bytes.
+	vc = &loggingVCursor{
+		shards:   []string{"-20", "20-"},
+		results:  []*sqltypes.Result{result},
+		maxBytes: 2,
+	}
+	_, err = sel.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	expectError(t, "sel.Execute", err, "in-memory result size exceeded allowed limit of 2 bytes")
+
+	// The limits can be ignored.
+	sel.IgnoreScatterLimits = true
+	vc = &loggingVCursor{
+		shards:    []string{"-20", "20-40", "40-"},
+		results:   []*sqltypes.Result{result},
+		maxShards: 2,
+		maxRows:   2,
+		maxBytes:  2,
+	}
+	got, err := sel.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectResult(t, "sel.Execute", got, result)
+}
+
 func TestSelectEqualUnique(t *testing.T) {
 	vindex, _ := vindexes.NewHash("", nil)
 	sel := &Route{
@@ -45,6 +45,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder"
+	"vitess.io/vitess/go/vt/vtgate/queryacl"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 
@@ -61,6 +62,9 @@ var (
 
 	queriesProcessed = stats.NewCountersWithSingleLabel("QueriesProcessed", "Queries processed at vtgate by plan type", "Plan")
 	queriesRouted    = stats.NewCountersWithSingleLabel("QueriesRouted", "Queries routed from vtgate to vttablet by plan type", "Plan")
+
+	planCacheHits   = stats.NewCounter("QueryPlanCacheHits", "Query plan cache hits")
+	planCacheMisses = stats.NewCounter("QueryPlanCacheMisses", "Query plan cache misses")
 )
 
 func init() {
@@ -137,6 +141,10 @@ func (e *Executor) Execute(ctx context.Context, method string, safeSession *Safe
 }
 
 func (e *Executor) execute(ctx context.Context, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable, logStats *LogStats) (*sqltypes.Result, error) {
+	destKeyspace, destTabletType, dest, err := e.ParseDestinationTarget(safeSession.TargetString)
+	if err != nil {
+		return nil, err
+	}
 	// Start an implicit transaction if necessary.
 	// TODO(sougou): deprecate legacyMode after all users are migrated out.
 	if !e.legacyAutocommit && !safeSession.Autocommit && !safeSession.InTransaction() {
@@ -145,11 +153,6 @@ func (e *Executor) execute(ctx context.Context, safeSession *SafeSession, sql st
 		}
 	}
 
-	destKeyspace, destTabletType, dest, err := e.ParseDestinationTarget(safeSession.TargetString)
-	if err != nil {
-		return nil, err
-	}
-
 	if safeSession.InTransaction() && destTabletType != topodatapb.TabletType_MASTER {
 		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "transactions are supported only for master tablet types, current type: %v", destTabletType)
 	}
@@ -261,15 +264,20 @@ func (e *Executor) handleExec(ctx context.Context, safeSession *SafeSession, sql
 
 		execStart := time.Now()
 		sql = sqlannotation.AnnotateIfDML(sql, nil)
-		if e.normalize {
+		if e.normalize || queryacl.Enabled() {
 			query, comments := sqlparser.SplitMarginComments(sql)
 			stmt, err := sqlparser.Parse(query)
 			if err != nil {
 				return nil, err
 			}
-			sqlparser.Normalize(stmt, bindVars, "vtg")
-			normalized := sqlparser.String(stmt)
-			sql = comments.Leading + normalized + comments.Trailing
+			if err := queryacl.Authorized(callerid.ImmediateCallerIDFromContext(ctx), stmt, destKeyspace); err != nil {
+				return nil, err
+			}
+			if e.normalize {
+				sqlparser.Normalize(stmt, bindVars, "vtg")
+				normalized := sqlparser.String(stmt)
+				sql = comments.Leading + normalized + comments.Trailing
+			}
 		}
 		logStats.PlanTime = execStart.Sub(logStats.StartTime)
 		logStats.SQL = sql
@@ -298,6 +306,10 @@ func (e *Executor) handleExec(ctx context.Context, safeSession *SafeSession, sql
 		logStats.Error = err
 		return nil, err
 	}
+	if err := checkPlanACL(ctx, plan, destKeyspace); err != nil {
+		logStats.Error = err
+		return nil, err
+	}
 
 	qr, err := plan.Instructions.Execute(vcursor, bindVars, true)
 
@@ -332,7 +344,15 @@ func (e *Executor) handleDDL(ctx context.Context, safeSession *SafeSession, sql
 	// Parse the statement to handle vindex operations
 	// If the statement failed to be properly parsed, fall through anyway
 	// to broadcast the ddl to all shards.
-	stmt, _ := sqlparser.Parse(sql)
+	stmt, err := sqlparser.Parse(sql)
+	if queryacl.Enabled() {
+		if err != nil {
+			return nil, err
+		}
+		if err := queryacl.Authorized(callerid.ImmediateCallerIDFromContext(ctx), stmt, destKeyspace); err != nil {
+			return nil, err
+		}
+	}
 	ddl, ok := stmt.(*sqlparser.DDL)
 	if ok {
 		execStart := time.Now()
@@ -528,7 +548,7 @@ func (e *Executor) handleBegin(ctx context.Context, safeSession *SafeSession, sq
 func (e *Executor) handleCommit(ctx context.Context, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable, logStats *LogStats) (*sqltypes.Result, error) {
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
-	logStats.ShardQueries = uint32(len(safeSession.ShardSessions))
+	logStats.ShardQueries = uint32(len(safeSession.ShardSessions) + len(safeSession.PostSessions))
 	queriesProcessed.Add("Commit", 1)
 	queriesRouted.Add("Commit", int64(logStats.ShardQueries))
 	err := e.txConn.Commit(ctx, safeSession)
@@ -539,7 +559,7 @@ func (e *Executor) handleCommit(ctx context.Context, safeSession *SafeSession, s
 func (e *Executor) handleRollback(ctx context.Context, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable, logStats *LogStats) (*sqltypes.Result, error) {
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
-	logStats.ShardQueries = uint32(len(safeSession.ShardSessions))
+	logStats.ShardQueries = uint32(len(safeSession.ShardSessions) + len(safeSession.PostSessions))
 	queriesProcessed.Add("Rollback", 1)
 	queriesRouted.Add("Rollback", int64(logStats.ShardQueries))
 	err := e.txConn.Rollback(ctx, safeSession)
@@ -852,10 +872,21 @@ func (e *Executor) handleShow(ctx context.Context, safeSession *SafeSession, sql
 			Rows:         rows,
 			RowsAffected: 2,
 		}, nil
-	case sqlparser.KeywordString(sqlparser.TABLES):
+	case sqlparser.KeywordString(sqlparser.TABLES), sqlparser.KeywordString(sqlparser.COLUMNS), sqlparser.KeywordString(sqlparser.FIELDS):
+		// The database name is a keyspace name, which the tablet
+		// doesn't know about: route to that keyspace instead.
 		if show.ShowTablesOpt != nil && show.ShowTablesOpt.DbName != "" {
+			if show.ShowTablesOpt.DbName != destKeyspace {
+				destKeyspace, dest = show.ShowTablesOpt.DbName, nil
+			}
 			show.ShowTablesOpt.DbName = ""
 		}
+		if !show.OnTable.Qualifier.IsEmpty() {
+			if keyspace := show.OnTable.Qualifier.String(); keyspace != destKeyspace {
+				destKeyspace, dest = keyspace, nil
+			}
+			show.OnTable.Qualifier = sqlparser.NewTableIdent("")
+		}
 		sql = sqlparser.String(show)
 	case sqlparser.KeywordString(sqlparser.DATABASES), sqlparser.KeywordString(sqlparser.VITESS_KEYSPACES):
 		keyspaces, err := e.resolver.resolver.GetAllKeyspaces(ctx)
@@ -1162,6 +1193,10 @@ func (e *Executor) StreamExecute(ctx context.Context, method string, safeSession
 		logStats.Error = err
 		return err
 	}
+	if err := checkPlanACL(ctx, plan, target.Keyspace); err != nil {
+		logStats.Error = err
+		return err
+	}
 
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
@@ -1226,6 +1261,10 @@ func (e *Executor) handleMessageStream(ctx context.Context, safeSession *SafeSes
 		logStats.Error = err
 		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unrecognized STREAM statement: %v", sql)
 	}
+	if err := queryacl.Authorized(callerid.ImmediateCallerIDFromContext(ctx), stmt, target.Keyspace); err != nil {
+		logStats.Error = err
+		return err
+	}
 
 	// TODO: Add support for destination target in streamed queries
 	table, _, _, _, err := vcursor.FindTable(streamStmt.Table)
@@ -1373,6 +1412,7 @@ func (e *Executor) getPlan(vcursor *vcursorImpl, sql string, comments sqlparser.
 		key = keyspace + ":" + sql
 	}
 	if result, ok := e.plans.Get(key); ok {
+		planCacheHits.Add(1)
 		return result.(*engine.Plan), nil
 	}
 	stmt, err := sqlparser.Parse(sql)
@@ -1380,6 +1420,7 @@ func (e *Executor) getPlan(vcursor *vcursorImpl, sql string, comments sqlparser.
 		return nil, err
 	}
 	if !e.normalize {
+		planCacheMisses.Add(1)
 		plan, err := planbuilder.BuildFromStmt(sql, stmt, vcursor)
 		if err != nil {
 			return nil, err
@@ -1404,8 +1445,10 @@ func (e *Executor) getPlan(vcursor *vcursorImpl, sql string, comments sqlparser.
 		normkey = keyspace + ":" + normalized
 	}
 	if result, ok := e.plans.Get(normkey); ok {
+		planCacheHits.Add(1)
 		return result.(*engine.Plan), nil
 	}
+	planCacheMisses.Add(1)
 	plan, err := planbuilder.BuildFromStmt(normalized, stmt, vcursor)
 	if err != nil {
 		return nil, err
@@ -1416,6 +1459,27 @@ func (e *Executor) getPlan(vcursor *vcursorImpl, sql string, comments sqlparser.
 	return plan, nil
 }
 
+// checkPlanACL returns an error if the caller is not allowed to access
+// the tables of a plan. keyspace is the keyspace of the unqualified
+// tables. It's a no-op if no table ACL is configured.
+func checkPlanACL(ctx context.Context, plan *engine.Plan, keyspace string) error {
+	return queryacl.AuthorizedTables(callerid.ImmediateCallerIDFromContext(ctx), plan.Permissions, keyspace)
+}
+
+// checkTableACL is like checkPlanACL, for the legacy entry points that
+// send the query to the tablets without parsing it. The query is only
+// parsed if a table ACL is configured.
+func checkTableACL(ctx context.Context, sql, keyspace string) error {
+	if !queryacl.Enabled() {
+		return nil
+	}
+	stmt, err := sqlparser.Parse(sql)
+	if err != nil {
+		return err
+	}
+	return queryacl.Authorized(callerid.ImmediateCallerIDFromContext(ctx), stmt, keyspace)
+}
+
 // skipQueryPlanCache extracts SkipQueryPlanCache from session
 func skipQueryPlanCache(safeSession *SafeSession) bool {
 	if safeSession == nil || safeSession.Options == nil {
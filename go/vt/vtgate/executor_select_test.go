@@ -783,6 +783,25 @@ func TestSelectScatter(t *testing.T) {
 	testQueryLog(t, logChan, "TestExecute", "SELECT", wantQueries[0].Sql, 8)
 }
 
+func TestSelectTabletTypeInTransaction(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@master", InTransaction: true})
+
+	_, err := executor.Execute(context.Background(), "TestExecute", session, "select /*vt+ TABLET_TYPE=replica */ id from user", nil)
+	want := "cannot change the tablet type to replica in a transaction"
+	if err == nil || err.Error() != want {
+		t.Errorf("Execute: %v, want %s", err, want)
+	}
+	if sbc1.Queries != nil {
+		t.Errorf("sbc1.Queries: %+v, want nil", sbc1.Queries)
+	}
+
+	// The session tablet type is always allowed.
+	if _, err := executor.Execute(context.Background(), "TestExecute", session, "select /*vt+ TABLET_TYPE=master */ id from user", nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestSelectScatterPartial(t *testing.T) {
 	// Special setup: Don't use createExecutorEnv.
 	cell := "aa"
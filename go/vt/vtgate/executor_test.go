@@ -31,10 +31,12 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/queryacl"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
@@ -989,6 +991,93 @@ func TestExecutorComment(t *testing.T) {
 	}
 }
 
+func TestExecutorShowQualified(t *testing.T) {
+	executor, sbc1, _, sbclookup := createExecutorEnv()
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "TestExecutor"})
+
+	queries := []struct {
+		in, out string
+	}{
+		{"show tables from TestUnsharded", "show tables"},
+		{"show full tables from TestUnsharded like 'a%'", "show full tables like 'a%'"},
+		{"show columns from t from TestUnsharded", "show columns from t"},
+		{"show full fields from TestUnsharded.t", "show full fields from t"},
+	}
+	var wantQueries []*querypb.BoundQuery
+	for _, query := range queries {
+		if _, err := executor.Execute(context.Background(), "TestExecute", session, query.in, nil); err != nil {
+			t.Errorf("%v: %v", query.in, err)
+		}
+		wantQueries = append(wantQueries, &querypb.BoundQuery{
+			Sql:           query.out,
+			BindVariables: map[string]*querypb.BindVariable{},
+		})
+	}
+	testQueries(t, "sbclookup", sbclookup, wantQueries)
+	testQueries(t, "sbc1", sbc1, nil)
+
+	// Without a qualifier, the session keyspace is used.
+	if _, err := executor.Execute(context.Background(), "TestExecute", session, "show columns from t", nil); err != nil {
+		t.Error(err)
+	}
+	testQueries(t, "sbc1", sbc1, []*querypb.BoundQuery{{
+		Sql:           "show columns from t",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}})
+}
+
+func TestExecutorTableACL(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	err := queryacl.Set(&tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			TableNamesOrPrefixes: []string{"user"},
+			Readers:              []string{"reader"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queryacl.Set(nil)
+
+	ctx := callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("reader"))
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@master"})
+	if _, err := executor.Execute(ctx, "TestExecute", session, "select id from user where id = 1", nil); err != nil {
+		t.Error(err)
+	}
+	if got := len(sbc1.Queries); got != 1 {
+		t.Errorf("len(sbc1.Queries): %d, want 1", got)
+	}
+
+	sbc1.Queries = nil
+	_, err = executor.Execute(ctx, "TestExecute", session, "delete from user where id = 1", nil)
+	want := `table acl error: "reader" [] does not have the WRITER role on table "user"`
+	if err == nil || err.Error() != want {
+		t.Errorf("Execute: %v, want %s", err, want)
+	}
+	err = executor.StreamExecute(ctx, "TestExecute", session, "select id from music", nil, querypb.Target{TabletType: topodatapb.TabletType_MASTER}, func(*sqltypes.Result) error { return nil })
+	want = `table acl error: "reader" [] does not have the READER role on table "music"`
+	if err == nil || err.Error() != want {
+		t.Errorf("StreamExecute: %v, want %s", err, want)
+	}
+
+	// The plan of the first query is cached, and it's still checked.
+	otherCtx := callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("other"))
+	_, err = executor.Execute(otherCtx, "TestExecute", session, "select id from user where id = 1", nil)
+	want = `table acl error: "other" [] does not have the READER role on table "user"`
+	if err == nil || err.Error() != want {
+		t.Errorf("Execute: %v, want %s", err, want)
+	}
+
+	// Queries with a shard target don't have a plan.
+	shardSession := NewSafeSession(&vtgatepb.Session{TargetString: "TestExecutor/-20@master"})
+	_, err = executor.Execute(ctx, "TestExecute", shardSession, "update user set a = 1", nil)
+	want = `table acl error: "reader" [] does not have the WRITER role on table "user"`
+	if err == nil || err.Error() != want {
+		t.Errorf("Execute: %v, want %s", err, want)
+	}
+	testQueries(t, "sbc1", sbc1, nil)
+}
+
 func TestExecutorOther(t *testing.T) {
 	executor, sbc1, sbc2, sbclookup := createExecutorEnv()
 
@@ -1797,6 +1886,7 @@ func TestVSchemaStats(t *testing.T) {
 
 func TestGetPlanUnnormalized(t *testing.T) {
 	r, _, _, _ := createExecutorEnv()
+	hits, misses := planCacheHits.Get(), planCacheMisses.Get()
 	emptyvc := newVCursorImpl(context.Background(), nil, "", 0, makeComments(""), r, nil)
 	unshardedvc := newVCursorImpl(context.Background(), nil, KsTestUnsharded, 0, makeComments(""), r, nil)
 
@@ -1857,6 +1947,12 @@ func TestGetPlanUnnormalized(t *testing.T) {
 	if logStats4.SQL != wantSQL {
 		t.Errorf("logstats sql want \"%s\" got \"%s\"", wantSQL, logStats4.SQL)
 	}
+	if got := planCacheHits.Get() - hits; got != 2 {
+		t.Errorf("planCacheHits: %d, want 2", got)
+	}
+	if got := planCacheMisses.Get() - misses; got != 2 {
+		t.Errorf("planCacheMisses: %d, want 2", got)
+	}
 }
 
 func TestGetPlanCacheUnnormalized(t *testing.T) {
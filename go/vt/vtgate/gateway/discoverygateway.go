@@ -45,15 +45,21 @@ import (
 
 var (
 	cellsToWatch        = flag.String("cells_to_watch", "", "comma-separated list of cells for watching tablets")
+	watchLocalRegion    = flag.Bool("cells_to_watch_local_region", false, "also watch the tablets of all the cells in the region of the local cell, so replicas in other cells of the region can serve traffic as local ones")
 	tabletFilters       flagutil.StringListValue
 	refreshInterval     = flag.Duration("tablet_refresh_interval", 1*time.Minute, "tablet refresh interval")
 	refreshKnownTablets = flag.Bool("tablet_refresh_known_tablets", true, "tablet refresh reloads the tablet address/port map from topo in case it changes")
 	topoReadConcurrency = flag.Int("topo_read_concurrency", 32, "concurrent topo reads")
+	selectionPolicy     = flag.String("tablet_selection_policy", selectionPolicyRandom, "how to pick a tablet among the healthy ones, local cell tablets first: 'random', 'lowest_lag' (prefers the tablets with the lowest replication lag) or 'least_loaded' (picks the tablet with the lower QPS of two random ones)")
 	allowedTabletTypes  []topodatapb.TabletType
 )
 
 const (
 	gatewayImplementationDiscovery = "discoverygateway"
+
+	selectionPolicyRandom      = "random"
+	selectionPolicyLowestLag   = "lowest_lag"
+	selectionPolicyLeastLoaded = "least_loaded"
 )
 
 func init() {
@@ -85,6 +91,12 @@ type discoveryGateway struct {
 }
 
 func createDiscoveryGateway(hc discovery.HealthCheck, serv srvtopo.Server, cell string, retryCount int) Gateway {
+	switch *selectionPolicy {
+	case selectionPolicyRandom, selectionPolicyLowestLag, selectionPolicyLeastLoaded:
+	default:
+		log.Exitf("Invalid tablet_selection_policy parameter: %v", *selectionPolicy)
+	}
+
 	var topoServer *topo.Server
 	if serv != nil {
 		topoServer = serv.GetTopoServer()
@@ -104,11 +116,9 @@ func createDiscoveryGateway(hc discovery.HealthCheck, serv srvtopo.Server, cell
 	// We set sendDownEvents=true because it's required by TabletStatsCache.
 	hc.SetListener(dg, true /* sendDownEvents */)
 
-	log.Infof("loading tablets for cells: %v", *cellsToWatch)
-	for _, c := range strings.Split(*cellsToWatch, ",") {
-		if c == "" {
-			continue
-		}
+	cells := watchedCells(topoServer, cell)
+	log.Infof("loading tablets for cells: %v", strings.Join(cells, ","))
+	for _, c := range cells {
 		var tr discovery.TabletRecorder = dg.hc
 		if len(tabletFilters) > 0 {
 			fbs, err := discovery.NewFilterByShard(dg.hc, tabletFilters)
@@ -125,6 +135,34 @@ func createDiscoveryGateway(hc discovery.HealthCheck, serv srvtopo.Server, cell
 	return dg
 }
 
+// watchedCells returns the cells to watch tablets in: the ones from
+// -cells_to_watch and, with -cells_to_watch_local_region, the cells in
+// the region of the local cell.
+func watchedCells(topoServer *topo.Server, localCell string) []string {
+	var cells []string
+	seen := make(map[string]bool)
+	add := func(c string) {
+		if c != "" && !seen[c] {
+			seen[c] = true
+			cells = append(cells, c)
+		}
+	}
+	for _, c := range strings.Split(*cellsToWatch, ",") {
+		add(c)
+	}
+	if *watchLocalRegion && topoServer != nil {
+		ctx := context.Background()
+		regionCells, err := topoServer.GetCellsInRegion(ctx, topo.GetRegionByCell(ctx, topoServer, localCell))
+		if err != nil {
+			log.Exitf("Cannot get the cells of the region of %v: %v", localCell, err)
+		}
+		for _, c := range regionCells {
+			add(c)
+		}
+	}
+	return cells
+}
+
 // RegisterStats registers the stats to export the lag since the last refresh
 // and the checksum of the topology
 func (dg *discoveryGateway) RegisterStats() {
@@ -288,6 +326,7 @@ func (dg *discoveryGateway) withRetry(ctx context.Context, target *querypb.Targe
 			break
 		}
 		shuffleTablets(dg.localCell, tablets)
+		orderTablets(*selectionPolicy, dg.localCell, tablets)
 
 		// skip tablets we tried before
 		var ts *discovery.TabletStats
@@ -365,6 +404,31 @@ func shuffleTablets(cell string, tablets []discovery.TabletStats) {
 	}
 }
 
+// orderTablets applies the selection policy to tablets shuffled by
+// shuffleTablets. The same cell tablets stay in the front, and the
+// shuffling breaks the ties.
+func orderTablets(policy, cell string, tablets []discovery.TabletStats) {
+	sameCellMax := 0
+	for sameCellMax < len(tablets) && tablets[sameCellMax].Tablet.Alias.Cell == cell {
+		sameCellMax++
+	}
+	for _, group := range [][]discovery.TabletStats{tablets[:sameCellMax], tablets[sameCellMax:]} {
+		switch policy {
+		case selectionPolicyLowestLag:
+			sort.SliceStable(group, func(i, j int) bool {
+				return group[i].Stats.GetSecondsBehindMaster() < group[j].Stats.GetSecondsBehindMaster()
+			})
+		case selectionPolicyLeastLoaded:
+			// Comparing two random tablets, instead of picking the least
+			// loaded one, avoids sending all the traffic to the same
+			// tablet until its QPS is next reported.
+			if len(group) > 1 && group[1].Stats.GetQps() < group[0].Stats.GetQps() {
+				group[0], group[1] = group[1], group[0]
+			}
+		}
+	}
+}
+
 func nextTablet(cell string, tablets []discovery.TabletStats, offset, length int, sameCell bool) int {
 	for ; offset < length; offset++ {
 		if (tablets[offset].Tablet.Alias.Cell == cell) == sameCell {
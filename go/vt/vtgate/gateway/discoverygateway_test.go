@@ -18,6 +18,7 @@ package gateway
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -206,6 +207,58 @@ func TestShuffleTablets(t *testing.T) {
 	}
 }
 
+func TestOrderTablets(t *testing.T) {
+	newTabletStats := func(key, cell string, lag uint32, qps float64) discovery.TabletStats {
+		return discovery.TabletStats{
+			Key:     key,
+			Tablet:  topo.NewTablet(10, cell, key),
+			Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
+			Up:      true,
+			Serving: true,
+			Stats:   &querypb.RealtimeStats{SecondsBehindMaster: lag, Qps: qps},
+		}
+	}
+	keys := func(tablets []discovery.TabletStats) string {
+		var result []string
+		for _, ts := range tablets {
+			result = append(result, ts.Key)
+		}
+		return strings.Join(result, ",")
+	}
+	tablets := func() []discovery.TabletStats {
+		return []discovery.TabletStats{
+			newTabletStats("t1", "cell1", 10, 100),
+			newTabletStats("t2", "cell1", 2, 10),
+			newTabletStats("t3", "cell1", 5, 50),
+			newTabletStats("t4", "cell2", 3, 100),
+			newTabletStats("t5", "cell2", 1, 10),
+		}
+	}
+
+	testcases := []struct {
+		policy string
+		want   string
+	}{{
+		policy: selectionPolicyRandom,
+		want:   "t1,t2,t3,t4,t5",
+	}, {
+		// The same cell tablets stay in the front.
+		policy: selectionPolicyLowestLag,
+		want:   "t2,t3,t1,t5,t4",
+	}, {
+		// Only the first two tablets of each cell are compared.
+		policy: selectionPolicyLeastLoaded,
+		want:   "t2,t1,t3,t5,t4",
+	}}
+	for _, tcase := range testcases {
+		got := tablets()
+		orderTablets(tcase.policy, "cell1", got)
+		if keys(got) != tcase.want {
+			t.Errorf("orderTablets(%v) = %v, want %v", tcase.policy, keys(got), tcase.want)
+		}
+	}
+}
+
 func TestDiscoveryGatewayGetTabletsWithRegion(t *testing.T) {
 	keyspace := "ks"
 	shard := "0"
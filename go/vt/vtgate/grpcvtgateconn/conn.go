@@ -126,11 +126,17 @@ func (conn *vtgateConn) StreamExecute(ctx context.Context, session *vtgatepb.Ses
 	}
 	return &streamExecuteAdapter{
 		recv: func() (*querypb.QueryResult, error) {
-			ser, err := stream.Recv()
-			if err != nil {
-				return nil, err
+			for {
+				ser, err := stream.Recv()
+				if err != nil {
+					return nil, err
+				}
+				// Keep-alives only keep the stream busy.
+				if ser.KeepAlive {
+					continue
+				}
+				return ser.Result, nil
 			}
-			return ser.Result, nil
 		},
 	}, nil
 }
@@ -23,14 +23,20 @@ import (
 	"net"
 	"os"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vtgate/grpcvtgateservice"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconntest"
+	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 )
 
 // TestGRPCVTGateConn makes sure the grpc service works
@@ -154,3 +160,94 @@ func TestGRPCVTGateConnAuth(t *testing.T) {
 	// and clean up again
 	client.Close()
 }
+
+// slowStreamService returns its fields right away, and its row only
+// after a while, so keep-alives are sent in between.
+type slowStreamService struct {
+	vtgateservice.VTGateService
+}
+
+func (s *slowStreamService) StreamExecute(ctx context.Context, session *vtgatepb.Session, sql string, bindVariables map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
+	if err := callback(&sqltypes.Result{Fields: sqltypes.MakeTestFields("id", "int64")}); err != nil {
+		return err
+	}
+	time.Sleep(100 * time.Millisecond)
+	return callback(&sqltypes.Result{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(1)}}})
+}
+
+// TestGRPCVTGateConnKeepAlive makes sure keep-alives are sent on idle
+// streams, and skipped by the client.
+func TestGRPCVTGateConnKeepAlive(t *testing.T) {
+	flag.Set("stream_keep_alive_interval", "10ms")
+	defer flag.Set("stream_keep_alive_interval", "0")
+
+	service := &slowStreamService{VTGateService: vtgateconntest.CreateFakeServer(t)}
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("Cannot listen: %v", err)
+	}
+	server := grpc.NewServer()
+	grpcvtgateservice.RegisterForTest(server, service)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx := context.Background()
+	client, err := dial(ctx, listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer client.Close()
+
+	// The raw stream has keep-alives between the fields and the row.
+	stream, err := client.(*vtgateConn).c.StreamExecute(ctx, &vtgatepb.StreamExecuteRequest{
+		Query: &querypb.BoundQuery{Sql: "select id from t"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var responses []*vtgatepb.StreamExecuteResponse
+	for {
+		ser, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, ser)
+	}
+	if len(responses) < 3 {
+		t.Fatalf("got %d responses, want at least 3: %v", len(responses), responses)
+	}
+	if responses[0].KeepAlive || len(responses[0].Result.Fields) != 1 {
+		t.Errorf("first response: %v, want the fields", responses[0])
+	}
+	for _, ser := range responses[1 : len(responses)-1] {
+		if !ser.KeepAlive || ser.Result != nil {
+			t.Errorf("response: %v, want a keep-alive", ser)
+		}
+	}
+	if last := responses[len(responses)-1]; last.KeepAlive || len(last.Result.Rows) != 1 {
+		t.Errorf("last response: %v, want the row", last)
+	}
+
+	// The client only returns the results.
+	rs, err := client.StreamExecute(ctx, nil, "select id from t", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []*sqltypes.Result
+	for {
+		qr, err := rs.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, qr)
+	}
+	if len(results) != 2 || len(results[1].Rows) != 1 {
+		t.Errorf("results: %v, want the fields and one row", results)
+	}
+}
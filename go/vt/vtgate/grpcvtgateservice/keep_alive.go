@@ -0,0 +1,112 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcvtgateservice
+
+import (
+	"flag"
+	"sync"
+	"time"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtgateservicepb "vitess.io/vitess/go/vt/proto/vtgateservice"
+)
+
+var streamKeepAliveInterval = flag.Duration("stream_keep_alive_interval", 0, "If set, StreamExecute sends a keep-alive response to the client when no result was sent for this long. The client must handle the keep_alive field of StreamExecuteResponse. 0 disables keep-alives.")
+
+// keepAliveStream sends the results of a StreamExecute, and a keep-alive
+// response when no result was sent for the keep-alive interval.
+// Keep-alives are only sent after the first result, because clients
+// read the Fields from it.
+type keepAliveStream struct {
+	stream vtgateservicepb.Vitess_StreamExecuteServer
+
+	// done is closed by stop, and stopped is closed when the keep-alive
+	// goroutine is gone, since Send must not be called after the handler
+	// returned.
+	done    chan struct{}
+	stopped chan struct{}
+
+	// mu serializes the calls to Send, which is not safe to call
+	// concurrently, and protects the fields below.
+	mu       sync.Mutex
+	started  bool
+	lastSend time.Time
+}
+
+// newKeepAliveStream returns a keepAliveStream. It sends keep-alives
+// until stop is called, unless interval is 0.
+func newKeepAliveStream(stream vtgateservicepb.Vitess_StreamExecuteServer, interval time.Duration) *keepAliveStream {
+	s := &keepAliveStream{
+		stream:  stream,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if interval == 0 {
+		close(s.stopped)
+		return s
+	}
+	go s.keepAlive(interval)
+	return s
+}
+
+// send sends a result.
+func (s *keepAliveStream) send(result *querypb.QueryResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = true
+	s.lastSend = time.Now()
+	return s.stream.Send(&vtgatepb.StreamExecuteResponse{
+		Result: result,
+	})
+}
+
+// stop stops sending keep-alives, and waits until none is being sent.
+func (s *keepAliveStream) stop() {
+	close(s.done)
+	<-s.stopped
+}
+
+func (s *keepAliveStream) keepAlive(interval time.Duration) {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.stream.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		var err error
+		if s.started && time.Since(s.lastSend) >= interval {
+			s.lastSend = time.Now()
+			err = s.stream.Send(&vtgatepb.StreamExecuteResponse{
+				KeepAlive: true,
+			})
+		}
+		s.mu.Unlock()
+		if err != nil {
+			// The stream is broken, the next send will fail too.
+			return
+		}
+	}
+}
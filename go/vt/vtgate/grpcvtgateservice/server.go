@@ -167,12 +167,10 @@ func (vtg *VTGate) StreamExecute(request *vtgatepb.StreamExecuteRequest, stream
 	if session.Options == nil {
 		session.Options = request.Options
 	}
+	kas := newKeepAliveStream(stream, *streamKeepAliveInterval)
+	defer kas.stop()
 	vtgErr := vtg.server.StreamExecute(ctx, session, request.Query.Sql, request.Query.BindVariables, func(value *sqltypes.Result) error {
-		// Send is not safe to call concurrently, but vtgate
-		// guarantees that it's not.
-		return stream.Send(&vtgatepb.StreamExecuteResponse{
-			Result: sqltypes.ResultToProto3(value),
-		})
+		return kas.send(sqltypes.ResultToProto3(value))
 	})
 	return vterrors.ToGRPC(vtgErr)
 }
@@ -22,6 +22,7 @@ import (
 
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl/acl"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
@@ -131,7 +132,8 @@ func Build(query string, vschema ContextVSchema) (*engine.Plan, error) {
 func BuildFromStmt(query string, stmt sqlparser.Statement, vschema ContextVSchema) (*engine.Plan, error) {
 	var err error
 	plan := &engine.Plan{
-		Original: query,
+		Original:    query,
+		Permissions: acl.BuildTablePermissions(stmt),
 	}
 	switch stmt := stmt.(type) {
 	case *sqlparser.Select:
@@ -58,6 +58,11 @@ type orderedAggregate struct {
 	order         int
 	input         *route
 	eaggr         *engine.OrderedAggregate
+
+	// aggregates maps the aggregate expressions of the select list
+	// to their column number. It's used to resolve aggregates
+	// referenced by the HAVING clause.
+	aggregates map[string]int
 }
 
 // checkAggregates analyzes the select expression for aggregates. If it determines
@@ -126,9 +131,10 @@ func (pb *primitiveBuilder) checkAggregates(sel *sqlparser.Select) (groupByHandl
 
 	// We need an aggregator primitive.
 	oa := &orderedAggregate{
-		order: rb.Order() + 1,
-		input: rb,
-		eaggr: &engine.OrderedAggregate{},
+		order:      rb.Order() + 1,
+		input:      rb,
+		eaggr:      &engine.OrderedAggregate{},
+		aggregates: make(map[string]int),
 	}
 	pb.bldr = oa
 	return oa, nil
@@ -258,8 +264,104 @@ func (oa *orderedAggregate) ResultColumns() []*resultColumn {
 }
 
 // PushFilter satisfies the builder interface.
-func (oa *orderedAggregate) PushFilter(_ *primitiveBuilder, _ sqlparser.Expr, whereType string, _ builder) error {
-	return errors.New("unsupported: filtering on results of aggregates")
+// HAVING conditions that don't reference aggregates are pushed down
+// to the route, because all the rows of a group share the same values
+// for the group by columns. Conditions on aggregates can only be
+// evaluated after the final aggregation. oa supports simple
+// comparisons between an aggregate of the select list and a value,
+// like 'having count(*) > 10' or 'having c >= :v'.
+func (oa *orderedAggregate) PushFilter(pb *primitiveBuilder, filter sqlparser.Expr, whereType string, origin builder) error {
+	if whereType != sqlparser.HavingStr {
+		return errors.New("unsupported: filtering on results of aggregates")
+	}
+	if !nodeHasAggregates(filter) && !oa.referencesAggregate(filter) {
+		return oa.input.PushFilter(pb, filter, whereType, origin)
+	}
+
+	comparison, ok := filter.(*sqlparser.ComparisonExpr)
+	if !ok {
+		return errors.New("unsupported: in scatter query: complex having clause on aggregates")
+	}
+	operator, left, right := comparison.Operator, comparison.Left, comparison.Right
+	if sqlparser.IsValue(left) {
+		// Rewrite 'value op aggregate' as 'aggregate op value'.
+		reversed, ok := reversedOperators[operator]
+		if !ok {
+			return fmt.Errorf("unsupported: in scatter query: having clause operator %s", operator)
+		}
+		operator, left, right = reversed, right, left
+	}
+	if _, ok := reversedOperators[operator]; !ok {
+		return fmt.Errorf("unsupported: in scatter query: having clause operator %s", operator)
+	}
+	colnum, ok := oa.aggregateColumn(left)
+	if !ok {
+		return errors.New("unsupported: in scatter query: having clause must reference an aggregate of the select list")
+	}
+	if !sqlparser.IsValue(right) {
+		return errors.New("unsupported: in scatter query: aggregates in having clause can only be compared to values")
+	}
+	value, err := sqlparser.NewPlanValue(right)
+	if err != nil {
+		return err
+	}
+	oa.eaggr.Having = append(oa.eaggr.Having, engine.HavingParams{
+		Col:      colnum,
+		Operator: operator,
+		Value:    value,
+	})
+	return nil
+}
+
+// reversedOperators maps the comparison operators oa can evaluate
+// to the operator to use when the operands are swapped.
+var reversedOperators = map[string]string{
+	sqlparser.EqualStr:        sqlparser.EqualStr,
+	sqlparser.NotEqualStr:     sqlparser.NotEqualStr,
+	sqlparser.LessThanStr:     sqlparser.GreaterThanStr,
+	sqlparser.LessEqualStr:    sqlparser.GreaterEqualStr,
+	sqlparser.GreaterThanStr:  sqlparser.LessThanStr,
+	sqlparser.GreaterEqualStr: sqlparser.LessEqualStr,
+}
+
+// referencesAggregate returns true if the expression references
+// an aggregate supplied by oa through its alias.
+func (oa *orderedAggregate) referencesAggregate(expr sqlparser.Expr) bool {
+	found := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			if c, ok := node.Metadata.(*column); ok && c.Origin() == oa {
+				found = true
+				return false, errors.New("unused error")
+			}
+		case *sqlparser.Subquery:
+			return false, nil
+		}
+		return true, nil
+	}, expr)
+	return found
+}
+
+// aggregateColumn returns the column number of an aggregate of the
+// select list, referenced either directly or through its alias.
+func (oa *orderedAggregate) aggregateColumn(expr sqlparser.Expr) (int, bool) {
+	switch node := expr.(type) {
+	case *sqlparser.ColName:
+		c, ok := node.Metadata.(*column)
+		if !ok || c.Origin() != oa {
+			return 0, false
+		}
+		for i, rc := range oa.resultColumns {
+			if rc.column == c {
+				return i, true
+			}
+		}
+	case *sqlparser.FuncExpr:
+		colnum, ok := oa.aggregates[sqlparser.String(node)]
+		return colnum, ok
+	}
+	return 0, false
 }
 
 // PushSelect satisfies the builder interface.
@@ -289,6 +391,7 @@ func (oa *orderedAggregate) PushSelect(expr *sqlparser.AliasedExpr, origin build
 			// from the expression we pushed down.
 			rc := &resultColumn{alias: innerRC.alias, column: &column{origin: oa}}
 			oa.resultColumns = append(oa.resultColumns, rc)
+			oa.aggregates[sqlparser.String(inner)] = len(oa.resultColumns) - 1
 			return rc, len(oa.resultColumns) - 1, nil
 		}
 	}
@@ -21,7 +21,10 @@ import (
 	"fmt"
 
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 // buildSelectPlan is the new function to build a Select plan.
@@ -76,9 +79,17 @@ func (pb *primitiveBuilder) processSelect(sel *sqlparser.Select, outer *symtab)
 		return err
 	}
 
+	directives := sqlparser.ExtractCommentDirectives(sel.Comments)
+	// TABLET_TYPE is recorded on the route. It cannot be applied to
+	// a plan which runs more than one route.
+	_, hasTabletType := directives[sqlparser.DirectiveTabletType]
 	if rb, ok := pb.bldr.(*route); ok {
-		directives := sqlparser.ExtractCommentDirectives(sel.Comments)
 		rb.ERoute.QueryTimeout = queryTimeout(directives)
+		tabletType, err := tabletTypeDirective(directives)
+		if err != nil {
+			return err
+		}
+		rb.ERoute.TabletType = tabletType
 		if rb.ERoute.TargetDestination != nil {
 			return errors.New("unsupported: SELECT with a target destination")
 		}
@@ -86,6 +97,11 @@ func (pb *primitiveBuilder) processSelect(sel *sqlparser.Select, outer *symtab)
 		if directives.IsSet(sqlparser.DirectiveScatterErrorsAsWarnings) {
 			rb.ERoute.ScatterErrorsAsWarnings = true
 		}
+		if directives.IsSet(sqlparser.DirectiveIgnoreScatterLimits) {
+			rb.ERoute.IgnoreScatterLimits = true
+		}
+	} else if hasTabletType {
+		return errTabletTypeJoinOrSubquery
 	}
 
 	// Set the outer symtab after processing of FROM clause.
@@ -95,6 +111,9 @@ func (pb *primitiveBuilder) processSelect(sel *sqlparser.Select, outer *symtab)
 		if err := pb.pushFilter(sel.Where.Expr, sqlparser.WhereStr); err != nil {
 			return err
 		}
+		if hasTabletType && pb.hasPullout() {
+			return errTabletTypeJoinOrSubquery
+		}
 	}
 	grouper, err := pb.checkAggregates(sel)
 	if err != nil {
@@ -103,10 +122,16 @@ func (pb *primitiveBuilder) processSelect(sel *sqlparser.Select, outer *symtab)
 	if err := pb.pushSelectExprs(sel, grouper); err != nil {
 		return err
 	}
+	if hasTabletType && pb.hasPullout() {
+		return errTabletTypeJoinOrSubquery
+	}
 	if sel.Having != nil {
 		if err := pb.pushFilter(sel.Having.Expr, sqlparser.HavingStr); err != nil {
 			return err
 		}
+		if hasTabletType && pb.hasPullout() {
+			return errTabletTypeJoinOrSubquery
+		}
 	}
 	if err := pb.pushOrderBy(sel.OrderBy); err != nil {
 		return err
@@ -160,6 +185,13 @@ func reorderBySubquery(filters []sqlparser.Expr) {
 	}
 }
 
+// hasPullout returns true if the last pushed expression added a pullout
+// subquery, which executes its own route.
+func (pb *primitiveBuilder) hasPullout() bool {
+	_, ok := pb.bldr.(*pulloutSubquery)
+	return ok
+}
+
 // addPullouts adds the pullout subqueries to the primitiveBuilder.
 func (pb *primitiveBuilder) addPullouts(pullouts []*pulloutSubquery) {
 	for _, pullout := range pullouts {
@@ -339,3 +371,23 @@ func queryTimeout(d sqlparser.CommentDirectives) int {
 	}
 	return 0
 }
+
+var errTabletTypeJoinOrSubquery = errors.New("unsupported: TABLET_TYPE directive on a cross-shard join or subquery")
+
+// tabletTypeDirective returns DirectiveTabletType value if set, otherwise returns UNKNOWN.
+func tabletTypeDirective(d sqlparser.CommentDirectives) (topodatapb.TabletType, error) {
+	val, ok := d[sqlparser.DirectiveTabletType]
+	if !ok {
+		return topodatapb.TabletType_UNKNOWN, nil
+	}
+
+	strVal, ok := val.(string)
+	if !ok {
+		return topodatapb.TabletType_UNKNOWN, fmt.Errorf("invalid %s directive: %v", sqlparser.DirectiveTabletType, val)
+	}
+	tabletType, err := topoproto.ParseTabletType(strVal)
+	if err != nil {
+		return topodatapb.TabletType_UNKNOWN, fmt.Errorf("invalid %s directive: %v", sqlparser.DirectiveTabletType, err)
+	}
+	return tabletType, nil
+}
@@ -22,6 +22,8 @@ import (
 
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 func buildUnionPlan(union *sqlparser.Union, vschema ContextVSchema) (primitive engine.Primitive, err error) {
@@ -80,6 +82,11 @@ func unionRouteMerge(union *sqlparser.Union, left, right builder) (builder, *sym
 	if !ok {
 		return nil, nil, errors.New("unsupported construct: SELECT of UNION is non-trivial")
 	}
+	// The merged route uses the left ERoute: A TABLET_TYPE directive
+	// of either side would be applied to the other side as well.
+	if lroute.ERoute.TabletType != topodatapb.TabletType_UNKNOWN || rroute.ERoute.TabletType != topodatapb.TabletType_UNKNOWN {
+		return nil, nil, errors.New("unsupported: TABLET_TYPE directive on a UNION")
+	}
 	if err := lroute.UnionCanMerge(rroute); err != nil {
 		return nil, nil, err
 	}
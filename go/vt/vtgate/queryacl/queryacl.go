@@ -0,0 +1,317 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package queryacl enforces table ACLs in vtgate, before queries are
+// sent to the tablets. The rules use the same config format as the
+// vttablet table ACLs, and are checked against the immediate caller id.
+//
+// Table names in the rules can be qualified with a keyspace, like
+// "ks.t". Such a rule only applies to the table of that keyspace, and
+// takes precedence over the rules for the unqualified name.
+package queryacl
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/tableacl/acl"
+	"vitess.io/vitess/go/vt/tableacl/simpleacl"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	// ConfigFile is the file the table ACL is loaded from.
+	ConfigFile = flag.String("vtgate_table_acl_config", "", "path to a table ACL config file, in the vttablet table ACL format (json or binary proto). If set, vtgate checks the tables of every query against it, and reloads it on SIGHUP.")
+
+	// TopoPath is the file of the global topo cell the table ACL is
+	// loaded from.
+	TopoPath = flag.String("vtgate_table_acl_topo_path", "", "path of a table ACL config in the global topo cell, in the vttablet table ACL format (json or binary proto). If set, vtgate checks the tables of every query against it, and watches it for changes. Can't be used with -vtgate_table_acl_config.")
+
+	// DryRun makes the table ACL only count the denied queries, and let
+	// them through.
+	DryRun = flag.Bool("vtgate_table_acl_dry_run", false, "If set, vtgate counts the queries the table ACL would deny in VtgateTableACLPseudoDenied, and lets them through.")
+
+	// ExemptACL is the list of users that bypass the table ACL.
+	ExemptACL = flag.String("vtgate_table_acl_exempt_acl", "", "comma separated list of users or groups that are exempt from the vtgate table ACL checks.")
+
+	deniedCounts       = stats.NewCountersWithMultiLabels("VtgateTableACLDenied", "Queries denied by the vtgate table ACL", []string{"Table", "Role", "User"})
+	pseudoDeniedCounts = stats.NewCountersWithMultiLabels("VtgateTableACLPseudoDenied", "Queries the vtgate table ACL would deny in dry run mode", []string{"Table", "Role", "User"})
+	exemptCount        = stats.NewCounter("VtgateTableACLExemptCount", "Queries that bypassed the vtgate table ACL because the caller is exempt")
+
+	mu sync.RWMutex
+	// groups is nil when the table ACL is disabled.
+	groups []*tableGroup
+	// exemptACL is nil if no user is exempt.
+	exemptACL acl.ACL
+)
+
+// tableGroup holds the ACLs of a list of tables.
+type tableGroup struct {
+	// tableNamesOrPrefixes are table names, or prefixes ending with %.
+	tableNamesOrPrefixes []string
+	acls                 [acl.NumRoles]acl.ACL
+}
+
+// Init loads the table ACL from ConfigFile or TopoPath, if set. The
+// file is reloaded every time the process receives SIGHUP, and the topo
+// path is watched for changes.
+func Init(ts *topo.Server) {
+	if *ConfigFile == "" && *TopoPath == "" {
+		return
+	}
+	if *ConfigFile != "" && *TopoPath != "" {
+		log.Exitf("Only one of -vtgate_table_acl_config and -vtgate_table_acl_topo_path can be set")
+	}
+	if *ExemptACL != "" {
+		if err := SetExemptACL(strings.Split(*ExemptACL, ",")); err != nil {
+			log.Exitf("Cannot build the vtgate table ACL exempt list: %v", err)
+		}
+	}
+	if *TopoPath != "" {
+		if err := WatchTopo(context.Background(), ts, *TopoPath); err != nil {
+			log.Exitf("Cannot load the vtgate table ACL from the topo: %v", err)
+		}
+		return
+	}
+	if err := Load(*ConfigFile); err != nil {
+		log.Exitf("Cannot load the vtgate table ACL: %v", err)
+	}
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	go func() {
+		for range sigChan {
+			if err := Load(*ConfigFile); err != nil {
+				log.Errorf("Cannot reload the vtgate table ACL, keeping the current one: %v", err)
+			}
+		}
+	}()
+}
+
+// Load reads a table ACL config from a json or binary proto file, and
+// makes it the current one.
+func Load(configFile string) error {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	return loadData(configFile, data)
+}
+
+// loadData parses a json or binary proto table ACL config, and makes
+// it the current one. name is where data comes from, for the errors.
+func loadData(name string, data []byte) error {
+	config := &tableaclpb.Config{}
+	if err := proto.Unmarshal(data, config); err != nil {
+		if jsonErr := json2.Unmarshal(data, config); jsonErr != nil {
+			return fmt.Errorf("cannot parse %v as a proto (%v) or json (%v) file", name, err, jsonErr)
+		}
+	}
+	return Set(config)
+}
+
+// Set makes config the current table ACL. A nil config disables
+// the checks.
+func Set(config *tableaclpb.Config) error {
+	if config == nil {
+		mu.Lock()
+		groups = nil
+		mu.Unlock()
+		return nil
+	}
+
+	factory := &simpleacl.Factory{}
+	newGroups := make([]*tableGroup, 0, len(config.TableGroups))
+	for _, group := range config.TableGroups {
+		tg := &tableGroup{tableNamesOrPrefixes: group.TableNamesOrPrefixes}
+		for role, entries := range [][]string{group.Readers, group.Writers, group.Admins} {
+			a, err := factory.New(entries)
+			if err != nil {
+				return fmt.Errorf("invalid table group %v: %v", group.Name, err)
+			}
+			tg.acls[role] = a
+		}
+		newGroups = append(newGroups, tg)
+	}
+
+	mu.Lock()
+	groups = newGroups
+	mu.Unlock()
+	return nil
+}
+
+// SetExemptACL sets the users and groups that bypass the table ACL.
+// An empty list exempts nobody.
+func SetExemptACL(entries []string) error {
+	var a acl.ACL
+	if len(entries) != 0 {
+		var err error
+		if a, err = (&simpleacl.Factory{}).New(entries); err != nil {
+			return err
+		}
+	}
+	mu.Lock()
+	exemptACL = a
+	mu.Unlock()
+	return nil
+}
+
+// Enabled returns true if a table ACL is loaded.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return groups != nil
+}
+
+// Authorized returns an error if the caller is not allowed to run stmt.
+// keyspace is the keyspace of the tables that are not qualified in the
+// statement, it can be empty. A table that is in no table group can't
+// be accessed by anyone, except for the dual pseudo-table.
+func Authorized(caller *querypb.VTGateCallerID, stmt sqlparser.Statement, keyspace string) error {
+	if !Enabled() {
+		return nil
+	}
+	return AuthorizedTables(caller, acl.BuildTablePermissions(stmt), keyspace)
+}
+
+// AuthorizedTables is like Authorized, for the table permissions of a
+// statement that were already built with acl.BuildTablePermissions,
+// like the ones of a cached plan.
+func AuthorizedTables(caller *querypb.VTGateCallerID, tables []acl.TablePermission, keyspace string) error {
+	mu.RLock()
+	defer mu.RUnlock()
+	if groups == nil {
+		return nil
+	}
+	permissions := buildPermissions(tables)
+	if len(permissions) == 0 {
+		return nil
+	}
+	if caller == nil {
+		if *DryRun {
+			return nil
+		}
+		return vterrors.New(vtrpcpb.Code_UNAUTHENTICATED, "table acl error: missing caller id")
+	}
+	if exemptACL != nil && exemptACL.IsMember(caller) {
+		exemptCount.Add(1)
+		return nil
+	}
+	for _, perm := range permissions {
+		qualifier := perm.qualifier
+		if qualifier == "" {
+			qualifier = keyspace
+		}
+		if a := findACL(qualifier, perm.tableName, perm.role); a != nil && a.IsMember(caller) {
+			continue
+		}
+		statsKey := []string{perm.String(), perm.role.Name(), caller.Username}
+		if *DryRun {
+			pseudoDeniedCounts.Add(statsKey, 1)
+			continue
+		}
+		deniedCounts.Add(statsKey, 1)
+		return vterrors.Errorf(vtrpcpb.Code_PERMISSION_DENIED, "table acl error: %q %v does not have the %v role on table %q", caller.Username, caller.Groups, perm.role.Name(), perm.String())
+	}
+	return nil
+}
+
+// findACL returns the ACL of role for the table. The rules for
+// "qualifier.tableName" take precedence over the ones for tableName.
+func findACL(qualifier, tableName string, role acl.Role) acl.ACL {
+	if qualifier != "" {
+		if tg := findGroup(qualifier+"."+tableName, true); tg != nil {
+			return tg.acls[role]
+		}
+	}
+	if tg := findGroup(tableName, false); tg != nil {
+		return tg.acls[role]
+	}
+	return nil
+}
+
+// findGroup returns the table group of a table name. An exact table name
+// takes precedence over the longest matching prefix. If qualified is
+// set, only the qualified names and prefixes of the rules are
+// considered, otherwise only the unqualified ones are.
+func findGroup(tableName string, qualified bool) *tableGroup {
+	var match *tableGroup
+	matchLen := -1
+	for _, tg := range groups {
+		for _, nameOrPrefix := range tg.tableNamesOrPrefixes {
+			if strings.Contains(nameOrPrefix, ".") != qualified {
+				continue
+			}
+			if nameOrPrefix == tableName {
+				return tg
+			}
+			if prefix := strings.TrimSuffix(nameOrPrefix, "%"); prefix != nameOrPrefix && strings.HasPrefix(tableName, prefix) && len(prefix) > matchLen {
+				match, matchLen = tg, len(prefix)
+			}
+		}
+	}
+	return match
+}
+
+// permission is the role a statement needs on a table.
+type permission struct {
+	// qualifier is the keyspace of the table, if it's qualified in
+	// the statement.
+	qualifier string
+	tableName string
+	role      acl.Role
+}
+
+func (p permission) String() string {
+	if p.qualifier == "" {
+		return p.tableName
+	}
+	return p.qualifier + "." + p.tableName
+}
+
+// buildPermissions converts the table permissions of a statement. The
+// dual pseudo-table doesn't need any permission.
+func buildPermissions(tables []acl.TablePermission) []permission {
+	var permissions []permission
+	for _, perm := range tables {
+		if perm.Table.Qualifier.IsEmpty() && strings.EqualFold(perm.Table.Name.String(), "dual") {
+			continue
+		}
+		permissions = append(permissions, permission{
+			qualifier: perm.Table.Qualifier.String(),
+			tableName: perm.Table.Name.String(),
+			role:      perm.Role,
+		})
+	}
+	return permissions
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryacl
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
+)
+
+func TestAuthorized(t *testing.T) {
+	defer Set(nil)
+
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "orders",
+			TableNamesOrPrefixes: []string{"orders", "order_%"},
+			Readers:              []string{"reader", "writer"},
+			Writers:              []string{"writer"},
+			Admins:               []string{"admins"},
+		}, {
+			Name:                 "order_secrets",
+			TableNamesOrPrefixes: []string{"order_secrets"},
+			Readers:              []string{"admins"},
+		}},
+	}
+	if err := Set(config); err != nil {
+		t.Fatal(err)
+	}
+	if !Enabled() {
+		t.Errorf("Enabled(): false, want true")
+	}
+
+	reader := &querypb.VTGateCallerID{Username: "reader"}
+	writer := &querypb.VTGateCallerID{Username: "writer"}
+	admin := &querypb.VTGateCallerID{Username: "dba", Groups: []string{"admins"}}
+	testcases := []struct {
+		caller *querypb.VTGateCallerID
+		query  string
+		err    string
+	}{{
+		caller: reader,
+		query:  "select * from orders join order_items on orders.id = order_items.order_id",
+	}, {
+		caller: reader,
+		query:  "select * from orders where id in (select order_id from order_secrets)",
+		err:    `table acl error: "reader" [] does not have the READER role on table "order_secrets"`,
+	}, {
+		caller: reader,
+		query:  "insert into orders(id) values (1)",
+		err:    `table acl error: "reader" [] does not have the WRITER role on table "orders"`,
+	}, {
+		caller: writer,
+		query:  "update order_items set a = 1 where id = 1",
+	}, {
+		caller: writer,
+		query:  "select * from customers",
+		err:    `table acl error: "writer" [] does not have the READER role on table "customers"`,
+	}, {
+		caller: writer,
+		query:  "alter table orders add column a int",
+		err:    `table acl error: "writer" [] does not have the ADMIN role on table "orders"`,
+	}, {
+		caller: admin,
+		query:  "alter table orders add column a int",
+	}, {
+		caller: admin,
+		query:  "select * from order_secrets",
+	}, {
+		caller: reader,
+		query:  "show tables",
+	}, {
+		caller: writer,
+		query:  "select 1 from dual",
+	}, {
+		caller: writer,
+		query:  "select @@version",
+	}, {
+		caller: writer,
+		query:  "select * from dual join customers",
+		err:    `table acl error: "writer" [] does not have the READER role on table "customers"`,
+	}, {
+		caller: nil,
+		query:  "select * from orders",
+		err:    "table acl error: missing caller id",
+	}}
+	for _, tcase := range testcases {
+		stmt, err := sqlparser.Parse(tcase.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Authorized(tcase.caller, stmt, "")
+		if tcase.err == "" {
+			if err != nil {
+				t.Errorf("Authorized(%v, %s): %v, want nil", tcase.caller, tcase.query, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tcase.err {
+			t.Errorf("Authorized(%v, %s): %v, want %s", tcase.caller, tcase.query, err, tcase.err)
+		}
+	}
+
+	// Everything is allowed once the ACL is removed.
+	if err := Set(nil); err != nil {
+		t.Fatal(err)
+	}
+	stmt, _ := sqlparser.Parse("select * from customers")
+	if err := Authorized(reader, stmt, ""); err != nil {
+		t.Errorf("Authorized without ACL: %v, want nil", err)
+	}
+}
+
+func TestAuthorizedQualified(t *testing.T) {
+	defer Set(nil)
+
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			Name:                 "users",
+			TableNamesOrPrefixes: []string{"users"},
+			Readers:              []string{"reader"},
+		}, {
+			Name:                 "secure_users",
+			TableNamesOrPrefixes: []string{"secure.users", "secure.log_%"},
+			Readers:              []string{"admin"},
+		}},
+	}
+	if err := Set(config); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := &querypb.VTGateCallerID{Username: "reader"}
+	testcases := []struct {
+		query    string
+		keyspace string
+		err      string
+	}{{
+		query: "select * from users",
+	}, {
+		query:    "select * from users",
+		keyspace: "main",
+	}, {
+		query: "select * from main.users",
+	}, {
+		query: "select * from secure.users",
+		err:   `table acl error: "reader" [] does not have the READER role on table "secure.users"`,
+	}, {
+		query:    "select * from users",
+		keyspace: "secure",
+		err:      `table acl error: "reader" [] does not have the READER role on table "users"`,
+	}, {
+		query: "select * from secure.log_1",
+		err:   `table acl error: "reader" [] does not have the READER role on table "secure.log_1"`,
+	}}
+	for _, tcase := range testcases {
+		stmt, err := sqlparser.Parse(tcase.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Authorized(reader, stmt, tcase.keyspace)
+		if tcase.err == "" {
+			if err != nil {
+				t.Errorf("Authorized(%s, %s): %v, want nil", tcase.query, tcase.keyspace, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tcase.err {
+			t.Errorf("Authorized(%s, %s): %v, want %s", tcase.query, tcase.keyspace, err, tcase.err)
+		}
+	}
+}
+
+func TestDryRunAndExempt(t *testing.T) {
+	defer Set(nil)
+	defer SetExemptACL(nil)
+	defer func(saved bool) {
+		*DryRun = saved
+	}(*DryRun)
+
+	config := &tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			TableNamesOrPrefixes: []string{"t1"},
+			Readers:              []string{"user1"},
+		}},
+	}
+	if err := Set(config); err != nil {
+		t.Fatal(err)
+	}
+	stmt, _ := sqlparser.Parse("select * from t1")
+	user2 := &querypb.VTGateCallerID{Username: "user2"}
+
+	if err := SetExemptACL([]string{"user2"}); err != nil {
+		t.Fatal(err)
+	}
+	exempt := exemptCount.Get()
+	if err := Authorized(user2, stmt, ""); err != nil {
+		t.Errorf("Authorized(exempt user2): %v, want nil", err)
+	}
+	if got, want := exemptCount.Get(), exempt+1; got != want {
+		t.Errorf("exemptCount: %d, want %d", got, want)
+	}
+	if err := SetExemptACL(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	*DryRun = true
+	key := "t1.READER.user2"
+	pseudoDenied := pseudoDeniedCounts.Counts()[key]
+	if err := Authorized(user2, stmt, ""); err != nil {
+		t.Errorf("Authorized(user2) in dry run: %v, want nil", err)
+	}
+	if got, want := pseudoDeniedCounts.Counts()[key], pseudoDenied+1; got != want {
+		t.Errorf("pseudoDeniedCounts[%s]: %d, want %d", key, got, want)
+	}
+
+	*DryRun = false
+	if err := Authorized(user2, stmt, ""); err == nil {
+		t.Errorf("Authorized(user2): nil, want error")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	defer Set(nil)
+
+	f, err := ioutil.TempFile("", "queryacl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{"table_groups": [{"table_names_or_prefixes": ["t1"], "readers": ["user1"]}]}`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if err := Load(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	stmt, _ := sqlparser.Parse("select * from t1")
+	if err := Authorized(&querypb.VTGateCallerID{Username: "user1"}, stmt, ""); err != nil {
+		t.Errorf("Authorized(user1): %v, want nil", err)
+	}
+	if err := Authorized(&querypb.VTGateCallerID{Username: "user2"}, stmt, ""); err == nil {
+		t.Errorf("Authorized(user2): nil, want error")
+	}
+
+	if err := Load("/nonexistent"); err == nil {
+		t.Errorf("Load(/nonexistent): nil, want error")
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryacl
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// watchTopoRetryDelay is how long to wait before watching the topo
+// path again, after the watch failed.
+var watchTopoRetryDelay = 5 * time.Second
+
+// WatchTopo loads the table ACL from filePath in the global topo cell,
+// and then applies every change to it in the background. The current
+// rules are kept if the file can't be read or parsed later on.
+func WatchTopo(ctx context.Context, ts *topo.Server, filePath string) error {
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("topo file %v", filePath)
+	current, changes, _ := conn.Watch(ctx, filePath)
+	if current.Err != nil {
+		return current.Err
+	}
+	if err := loadData(name, current.Contents); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			for wd := range changes {
+				if wd.Err != nil {
+					// The channel is closed right after an error.
+					log.Warningf("Error watching the vtgate table ACL in %v (will wait %v before retrying): %v", name, watchTopoRetryDelay, wd.Err)
+					break
+				}
+				if err := loadData(name, wd.Contents); err != nil {
+					log.Errorf("Cannot reload the vtgate table ACL, keeping the current one: %v", err)
+				}
+			}
+
+			// Sleep a bit before watching again.
+			for {
+				time.Sleep(watchTopoRetryDelay)
+				current, changes, _ = conn.Watch(ctx, filePath)
+				if current.Err == nil {
+					break
+				}
+				log.Warningf("Error watching the vtgate table ACL in %v (will wait %v before retrying): %v", name, watchTopoRetryDelay, current.Err)
+			}
+			if err := loadData(name, current.Contents); err != nil {
+				log.Errorf("Cannot reload the vtgate table ACL, keeping the current one: %v", err)
+			}
+		}
+	}()
+	return nil
+}
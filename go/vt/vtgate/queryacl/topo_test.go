@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryacl
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestWatchTopo(t *testing.T) {
+	defer Set(nil)
+
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WatchTopo(ctx, ts, "vtgate_table_acl"); err == nil {
+		t.Errorf("WatchTopo(missing file): nil, want error")
+	}
+	version, err := conn.Create(ctx, "vtgate_table_acl", []byte(`{"table_groups": [{"table_names_or_prefixes": ["t1"], "readers": ["user1"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WatchTopo(ctx, ts, "vtgate_table_acl"); err != nil {
+		t.Fatal(err)
+	}
+	stmt, _ := sqlparser.Parse("select * from t1")
+	user1 := &querypb.VTGateCallerID{Username: "user1"}
+	user2 := &querypb.VTGateCallerID{Username: "user2"}
+	if err := Authorized(user1, stmt, ""); err != nil {
+		t.Errorf("Authorized(user1): %v, want nil", err)
+	}
+	if err := Authorized(user2, stmt, ""); err == nil {
+		t.Errorf("Authorized(user2): nil, want error")
+	}
+
+	// An invalid config is skipped, and the next valid one is applied.
+	if version, err = conn.Update(ctx, "vtgate_table_acl", []byte("invalid"), version); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Update(ctx, "vtgate_table_acl", []byte(`{"table_groups": [{"table_names_or_prefixes": ["t1"], "readers": ["user2"]}]}`), version); err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.Now().Add(10 * time.Second)
+	for Authorized(user2, stmt, "") != nil {
+		if time.Now().After(timeout) {
+			t.Fatalf("timed out waiting for the new table ACL")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := Authorized(user1, stmt, ""); err == nil {
+		t.Errorf("Authorized(user1): nil, want error")
+	}
+}
@@ -36,6 +36,8 @@ type SafeSession struct {
 	mu              sync.Mutex
 	mustRollback    bool
 	autocommitState autocommitState
+	// postCommit makes Find and Append use the PostSessions.
+	postCommit bool
 	*vtgatepb.Session
 }
 
@@ -74,6 +76,7 @@ func NewAutocommitSession(sessn *vtgatepb.Session) *SafeSession {
 	newSession := proto.Clone(sessn).(*vtgatepb.Session)
 	newSession.InTransaction = false
 	newSession.ShardSessions = nil
+	newSession.PostSessions = nil
 	newSession.Autocommit = true
 	return NewSafeSession(newSession)
 }
@@ -130,7 +133,11 @@ func (session *SafeSession) Find(keyspace, shard string, tabletType topodatapb.T
 	}
 	session.mu.Lock()
 	defer session.mu.Unlock()
-	for _, shardSession := range session.ShardSessions {
+	shardSessions := session.ShardSessions
+	if session.postCommit {
+		shardSessions = session.PostSessions
+	}
+	for _, shardSession := range shardSessions {
 		if keyspace == shardSession.Target.Keyspace && tabletType == shardSession.Target.TabletType && shard == shardSession.Target.Shard {
 			return shardSession.TransactionId
 		}
@@ -148,14 +155,31 @@ func (session *SafeSession) Append(shardSession *vtgatepb.Session_ShardSession,
 	}
 
 	// Always append, in order for rollback to succeed.
-	session.ShardSessions = append(session.ShardSessions, shardSession)
-	if session.isSingleDB(txMode) && len(session.ShardSessions) > 1 {
+	if session.postCommit {
+		session.PostSessions = append(session.PostSessions, shardSession)
+	} else {
+		session.ShardSessions = append(session.ShardSessions, shardSession)
+	}
+	if session.isSingleDB(txMode) && len(session.ShardSessions)+len(session.PostSessions) > 1 {
 		session.mustRollback = true
+		if len(session.PostSessions) != 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "multi-db transaction attempted: %v, post commit: %v", session.ShardSessions, session.PostSessions)
+		}
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "multi-db transaction attempted: %v", session.ShardSessions)
 	}
 	return nil
 }
 
+// SetPostCommit makes the transactions started from now on part of
+// the PostSessions if true, which are committed after the other ones.
+// It's used to run the queries of a vindex that must only be committed
+// if the rows of its owner are.
+func (session *SafeSession) SetPostCommit(postCommit bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.postCommit = postCommit
+}
+
 func (session *SafeSession) isSingleDB(txMode vtgatepb.TransactionMode) bool {
 	return session.SingleDb ||
 		session.TransactionMode == vtgatepb.TransactionMode_SINGLE ||
@@ -209,4 +233,6 @@ func (session *SafeSession) Reset() {
 	session.Session.InTransaction = false
 	session.SingleDb = false
 	session.ShardSessions = nil
+	session.PostSessions = nil
+	session.postCommit = false
 }
@@ -33,6 +33,7 @@ import (
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/gateway"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -178,12 +179,18 @@ func (stc *ScatterConn) ExecuteMultiShard(
 	session *SafeSession,
 	notInTransaction bool,
 	autocommit bool,
+	limiter *engine.ResultLimiter,
 ) (qr *sqltypes.Result, errs []error) {
 
-	// mu protects qr
+	// mu protects qr and limiter
 	var mu sync.Mutex
 	qr = new(sqltypes.Result)
 
+	// Once the limiter fails, the queries still running on the other
+	// shards are canceled.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	allErrors := stc.multiGoTransaction(
 		ctx,
 		"Execute",
@@ -215,6 +222,10 @@ func (stc *ScatterConn) ExecuteMultiShard(
 
 			mu.Lock()
 			defer mu.Unlock()
+			if err := limiter.Add(innerqr); err != nil {
+				cancel()
+				return transactionID, err
+			}
 			qr.AppendResult(innerqr)
 			return transactionID, nil
 		})
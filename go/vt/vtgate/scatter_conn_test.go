@@ -30,6 +30,7 @@ import (
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/gateway"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -68,7 +69,7 @@ func TestScatterConnExecuteMulti(t *testing.T) {
 			}
 		}
 
-		qr, errs := sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false)
+		qr, errs := sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false, nil)
 		return qr, vterrors.Aggregate(errs)
 	})
 }
@@ -285,7 +286,7 @@ func TestMultiExecs(t *testing.T) {
 		},
 	}
 
-	_, _ = sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false)
+	_, _ = sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false, nil)
 	if len(sbc0.Queries) == 0 || len(sbc1.Queries) == 0 {
 		t.Fatalf("didn't get expected query")
 	}
@@ -339,6 +340,59 @@ func TestMultiExecs(t *testing.T) {
 	}
 }
 
+func TestScatterConnExecuteMultiShardLimits(t *testing.T) {
+	ks := "TestScatterConnExecuteMultiShardLimits"
+	createSandbox(ks)
+	hc := discovery.NewFakeHealthCheck()
+	sc := newTestScatterConn(hc, new(sandboxTopo), "aa")
+	var rss []*srvtopo.ResolvedShard
+	var queries []*querypb.BoundQuery
+	for _, shard := range []string{"0", "1", "2"} {
+		sbc := hc.AddTestTablet("aa", shard, 1, ks, shard, topodatapb.TabletType_REPLICA, true, 1, nil)
+		rss = append(rss, &srvtopo.ResolvedShard{
+			Target: &querypb.Target{
+				Keyspace:   ks,
+				Shard:      shard,
+				TabletType: topodatapb.TabletType_REPLICA,
+			},
+			QueryService: sbc,
+		})
+		queries = append(queries, &querypb.BoundQuery{Sql: "query"})
+	}
+
+	// Each shard returns one row: the query fails when the second
+	// result is merged, and the others are never buffered.
+	limiter := engine.NewResultLimiter(1, 0)
+	qr, errs := sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false, limiter)
+	want := "in-memory row count exceeded allowed limit of 1"
+	if err := limiter.Err(); err == nil || err.Error() != want {
+		t.Errorf("limiter.Err(): %v, want %s", err, want)
+	}
+	if err := vterrors.Aggregate(errs); vterrors.Code(err) != vtrpcpb.Code_RESOURCE_EXHAUSTED {
+		t.Errorf("ExecuteMultiShard: %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if len(qr.Rows) != 1 {
+		t.Errorf("buffered rows: %d, want 1", len(qr.Rows))
+	}
+
+	// The byte limit works the same way. A row is 4 bytes: "1" and "foo".
+	limiter = engine.NewResultLimiter(0, 6)
+	_, _ = sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false, limiter)
+	want = "in-memory result size exceeded allowed limit of 6 bytes"
+	if err := limiter.Err(); err == nil || err.Error() != want {
+		t.Errorf("limiter.Err(): %v, want %s", err, want)
+	}
+
+	// No limit.
+	qr, errs = sc.ExecuteMultiShard(context.Background(), rss, queries, topodatapb.TabletType_REPLICA, nil, false, false, engine.NewResultLimiter(0, 0))
+	if errs != nil {
+		t.Errorf("ExecuteMultiShard: %v, want no error", errs)
+	}
+	if len(qr.Rows) != 3 {
+		t.Errorf("rows: %d, want 3", len(qr.Rows))
+	}
+}
+
 func TestScatterConnStreamExecuteSendError(t *testing.T) {
 	createSandbox("TestScatterConnStreamExecuteSendError")
 	hc := discovery.NewFakeHealthCheck()
@@ -21,6 +21,7 @@ import (
 
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/dtids"
 	"vitess.io/vitess/go/vt/log"
@@ -32,6 +33,16 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
+var (
+	// commitCounts counts the multi-shard commits by mode: Normal or TwoPC.
+	commitCounts = stats.NewCountersWithSingleLabel("VtgateMultiShardCommits", "Vtgate multi-shard commits by commit mode", "Mode")
+
+	// twoPCErrors counts the failed 2PC commits by the step that failed.
+	// Failures after CreateTransaction can leave unresolved transactions
+	// behind, to be resolved later by the tablets' transaction watchers.
+	twoPCErrors = stats.NewCountersWithSingleLabel("VtgateTwoPCErrors", "Vtgate 2PC commit errors by step", "Step")
+)
+
 // TxConn is used for executing transactional requests.
 type TxConn struct {
 	gateway gateway.Gateway
@@ -88,16 +99,37 @@ func (txc *TxConn) Commit(ctx context.Context, session *SafeSession) error {
 	case vtgatepb.TransactionMode_UNSPECIFIED:
 		twopc = (txc.mode == vtgatepb.TransactionMode_TWOPC)
 	}
+	var err error
 	if twopc {
-		return txc.commit2PC(ctx, session)
+		err = txc.commit2PC(ctx, session)
+	} else {
+		err = txc.commitNormal(ctx, session)
+	}
+
+	// The post commit sessions are only committed if the others were,
+	// and rolled back otherwise.
+	if err != nil {
+		txc.runSessions(session.PostSessions, func(s *vtgatepb.Session_ShardSession) error {
+			return txc.gateway.Rollback(ctx, s.Target, s.TransactionId)
+		})
+		return err
 	}
-	return txc.commitNormal(ctx, session)
+	return txc.commitSessions(ctx, session.PostSessions)
 }
 
 func (txc *TxConn) commitNormal(ctx context.Context, session *SafeSession) error {
+	if len(session.ShardSessions) > 1 {
+		commitCounts.Add("Normal", 1)
+	}
+	return txc.commitSessions(ctx, session.ShardSessions)
+}
+
+// commitSessions commits the shard sessions one by one, in order. If a
+// commit fails, the remaining ones are rolled back.
+func (txc *TxConn) commitSessions(ctx context.Context, shardSessions []*vtgatepb.Session_ShardSession) error {
 	var err error
 	committing := true
-	for _, shardSession := range session.ShardSessions {
+	for _, shardSession := range shardSessions {
 		if !committing {
 			txc.gateway.Rollback(ctx, shardSession.Target, shardSession.TransactionId)
 			continue
@@ -115,6 +147,7 @@ func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 		return txc.commitNormal(ctx, session)
 	}
 
+	commitCounts.Add("TwoPC", 1)
 	participants := make([]*querypb.Target, 0, len(session.ShardSessions)-1)
 	for _, s := range session.ShardSessions[1:] {
 		participants = append(participants, s.Target)
@@ -123,6 +156,7 @@ func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 	dtid := dtids.New(mmShard)
 	err := txc.gateway.CreateTransaction(ctx, mmShard.Target, dtid, participants)
 	if err != nil {
+		twoPCErrors.Add("CreateTransaction", 1)
 		// Normal rollback is safe because nothing was prepared yet.
 		txc.Rollback(ctx, session)
 		return err
@@ -132,6 +166,7 @@ func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 		return txc.gateway.Prepare(ctx, s.Target, s.TransactionId, dtid)
 	})
 	if err != nil {
+		twoPCErrors.Add("Prepare", 1)
 		// TODO(sougou): Perform a more fine-grained cleanup
 		// including unprepared transactions.
 		if resumeErr := txc.Resolve(ctx, dtid); resumeErr != nil {
@@ -143,6 +178,7 @@ func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 
 	err = txc.gateway.StartCommit(ctx, mmShard.Target, mmShard.TransactionId, dtid)
 	if err != nil {
+		twoPCErrors.Add("StartCommit", 1)
 		return err
 	}
 
@@ -150,10 +186,15 @@ func (txc *TxConn) commit2PC(ctx context.Context, session *SafeSession) error {
 		return txc.gateway.CommitPrepared(ctx, s.Target, dtid)
 	})
 	if err != nil {
+		twoPCErrors.Add("CommitPrepared", 1)
 		return err
 	}
 
-	return txc.gateway.ConcludeTransaction(ctx, mmShard.Target, dtid)
+	if err := txc.gateway.ConcludeTransaction(ctx, mmShard.Target, dtid); err != nil {
+		twoPCErrors.Add("ConcludeTransaction", 1)
+		return err
+	}
+	return nil
 }
 
 // Rollback rolls back the current transaction. There are no retries on this operation.
@@ -163,7 +204,8 @@ func (txc *TxConn) Rollback(ctx context.Context, session *SafeSession) error {
 	}
 	defer session.Reset()
 
-	return txc.runSessions(session.ShardSessions, func(s *vtgatepb.Session_ShardSession) error {
+	shardSessions := append(append([]*vtgatepb.Session_ShardSession{}, session.ShardSessions...), session.PostSessions...)
+	return txc.runSessions(shardSessions, func(s *vtgatepb.Session_ShardSession) error {
 		return txc.gateway.Rollback(ctx, s.Target, s.TransactionId)
 	})
 }
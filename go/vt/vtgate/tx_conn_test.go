@@ -162,6 +162,85 @@ func TestTxConnCommitSuccess(t *testing.T) {
 	}
 }
 
+func TestTxConnCommitPostSessions(t *testing.T) {
+	sc, sbc0, sbc1, rss0, rss1, _ := newTestTxConnEnv(t, "TestTxConn")
+	sc.txConn.mode = vtgatepb.TransactionMode_MULTI
+
+	session := NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	sc.Execute(context.Background(), "query1", nil, rss0, topodatapb.TabletType_MASTER, session, false, nil)
+	session.SetPostCommit(true)
+	sc.Execute(context.Background(), "query1", nil, rss1, topodatapb.TabletType_MASTER, session, false, nil)
+	session.SetPostCommit(false)
+	wantSession := vtgatepb.Session{
+		InTransaction: true,
+		ShardSessions: []*vtgatepb.Session_ShardSession{{
+			Target: &querypb.Target{
+				Keyspace:   "TestTxConn",
+				Shard:      "0",
+				TabletType: topodatapb.TabletType_MASTER,
+			},
+			TransactionId: 1,
+		}},
+		PostSessions: []*vtgatepb.Session_ShardSession{{
+			Target: &querypb.Target{
+				Keyspace:   "TestTxConn",
+				Shard:      "1",
+				TabletType: topodatapb.TabletType_MASTER,
+			},
+			TransactionId: 1,
+		}},
+	}
+	if !proto.Equal(session.Session, &wantSession) {
+		t.Errorf("Session:\n%+v, want\n%+v", *session.Session, wantSession)
+	}
+
+	if err := sc.txConn.Commit(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	wantSession = vtgatepb.Session{}
+	if !proto.Equal(session.Session, &wantSession) {
+		t.Errorf("Session:\n%+v, want\n%+v", *session.Session, wantSession)
+	}
+	if commitCount := sbc0.CommitCount.Get(); commitCount != 1 {
+		t.Errorf("sbc0.CommitCount: %d, want 1", commitCount)
+	}
+	if commitCount := sbc1.CommitCount.Get(); commitCount != 1 {
+		t.Errorf("sbc1.CommitCount: %d, want 1", commitCount)
+	}
+}
+
+func TestTxConnCommitPostSessionsFailure(t *testing.T) {
+	sc, sbc0, sbc1, rss0, rss1, _ := newTestTxConnEnv(t, "TestTxConn")
+	sc.txConn.mode = vtgatepb.TransactionMode_MULTI
+
+	session := NewSafeSession(&vtgatepb.Session{InTransaction: true})
+	sc.Execute(context.Background(), "query1", nil, rss0, topodatapb.TabletType_MASTER, session, false, nil)
+	session.SetPostCommit(true)
+	sc.Execute(context.Background(), "query1", nil, rss1, topodatapb.TabletType_MASTER, session, false, nil)
+	session.SetPostCommit(false)
+
+	// The post sessions must not be committed if the owner commit fails.
+	sbc0.MustFailCodes[vtrpcpb.Code_INVALID_ARGUMENT] = 1
+	err := sc.txConn.Commit(context.Background(), session)
+	want := "INVALID_ARGUMENT error"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Commit: %v, want %s", err, want)
+	}
+	wantSession := vtgatepb.Session{}
+	if !proto.Equal(session.Session, &wantSession) {
+		t.Errorf("Session:\n%+v, want\n%+v", *session.Session, wantSession)
+	}
+	if commitCount := sbc0.CommitCount.Get(); commitCount != 1 {
+		t.Errorf("sbc0.CommitCount: %d, want 1", commitCount)
+	}
+	if commitCount := sbc1.CommitCount.Get(); commitCount != 0 {
+		t.Errorf("sbc1.CommitCount: %d, want 0", commitCount)
+	}
+	if rollbackCount := sbc1.RollbackCount.Get(); rollbackCount != 1 {
+		t.Errorf("sbc1.RollbackCount: %d, want 1", rollbackCount)
+	}
+}
+
 func TestTxConnCommit2PC(t *testing.T) {
 	sc, sbc0, sbc1, rss0, _, rss01 := newTestTxConnEnv(t, "TestTxConnCommit2PC")
 
@@ -169,9 +248,13 @@ func TestTxConnCommit2PC(t *testing.T) {
 	sc.Execute(context.Background(), "query1", nil, rss0, topodatapb.TabletType_MASTER, session, false, nil)
 	sc.Execute(context.Background(), "query1", nil, rss01, topodatapb.TabletType_MASTER, session, false, nil)
 	session.TransactionMode = vtgatepb.TransactionMode_TWOPC
+	commits := commitCounts.Counts()["TwoPC"]
 	if err := sc.txConn.Commit(context.Background(), session); err != nil {
 		t.Error(err)
 	}
+	if c := commitCounts.Counts()["TwoPC"] - commits; c != 1 {
+		t.Errorf("commitCounts[TwoPC]: %d, want 1", c)
+	}
 	if c := sbc0.CreateTransactionCount.Get(); c != 1 {
 		t.Errorf("sbc0.CreateTransactionCount: %d, want 1", c)
 	}
@@ -248,11 +331,15 @@ func TestTxConnCommit2PCPrepareFail(t *testing.T) {
 
 	sbc1.MustFailPrepare = 1
 	session.TransactionMode = vtgatepb.TransactionMode_TWOPC
+	prepareErrors := twoPCErrors.Counts()["Prepare"]
 	err := sc.txConn.Commit(context.Background(), session)
 	want := "error: err"
 	if err == nil || !strings.Contains(err.Error(), want) {
 		t.Errorf("Commit: %v, must contain %s", err, want)
 	}
+	if c := twoPCErrors.Counts()["Prepare"] - prepareErrors; c != 1 {
+		t.Errorf("twoPCErrors[Prepare]: %d, want 1", c)
+	}
 	if c := sbc0.CreateTransactionCount.Get(); c != 1 {
 		t.Errorf("sbc0.CreateTransactionCount: %d, want 1", c)
 	}
@@ -26,6 +26,7 @@ import (
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -81,6 +82,19 @@ func (vc *vcursorImpl) SetContextTimeout(timeout time.Duration) context.CancelFu
 	return cancel
 }
 
+// SetTabletType is part of the engine.VCursor interface.
+// The tablet type can't be changed in a transaction.
+func (vc *vcursorImpl) SetTabletType(tabletType topodatapb.TabletType) error {
+	if tabletType == vc.tabletType {
+		return nil
+	}
+	if vc.safeSession.InTransaction() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot change the tablet type to %v in a transaction", topoproto.TabletTypeLString(tabletType))
+	}
+	vc.tabletType = tabletType
+	return nil
+}
+
 // RecordWarning stores the given warning in the current session
 func (vc *vcursorImpl) RecordWarning(warning *querypb.QueryWarning) {
 	vc.safeSession.RecordWarning(warning)
@@ -156,10 +170,19 @@ func (vc *vcursorImpl) ExecuteAutocommit(method string, query string, BindVars m
 	return qr, err
 }
 
+// ExecutePostCommit performs a V3 level execution of the query in the
+// post commit transactions of the session, which are committed after the
+// other ones.
+func (vc *vcursorImpl) ExecutePostCommit(method string, query string, BindVars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error) {
+	vc.safeSession.SetPostCommit(true)
+	defer vc.safeSession.SetPostCommit(false)
+	return vc.Execute(method, query, BindVars, isDML)
+}
+
 // ExecuteMultiShard is part of the engine.VCursor interface.
-func (vc *vcursorImpl) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, autocommit bool) (*sqltypes.Result, []error) {
+func (vc *vcursorImpl) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, isDML, autocommit bool, limiter *engine.ResultLimiter) (*sqltypes.Result, []error) {
 	atomic.AddUint32(&vc.logStats.ShardQueries, uint32(len(queries)))
-	qr, errs := vc.executor.scatterConn.ExecuteMultiShard(vc.ctx, rss, commentedShardQueries(queries, vc.marginComments), vc.tabletType, vc.safeSession, false, autocommit)
+	qr, errs := vc.executor.scatterConn.ExecuteMultiShard(vc.ctx, rss, commentedShardQueries(queries, vc.marginComments), vc.tabletType, vc.safeSession, false, autocommit, limiter)
 
 	if errs == nil {
 		vc.hasPartialDML = true
@@ -167,6 +190,11 @@ func (vc *vcursorImpl) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries [
 	return qr, errs
 }
 
+// ScatterLimits is part of the engine.VCursor interface.
+func (vc *vcursorImpl) ScatterLimits() (maxShards, maxRows, maxBytes int) {
+	return *maxScatterShards, *maxMemoryRows, *maxMemoryBytes
+}
+
 // AutocommitApproval is part of the engine.VCursor interface.
 func (vc *vcursorImpl) AutocommitApproval() bool {
 	return vc.safeSession.AutocommitApproval()
@@ -183,7 +211,7 @@ func (vc *vcursorImpl) ExecuteStandalone(query string, bindVars map[string]*quer
 	}
 	// The autocommit flag is always set to false because we currently don't
 	// execute DMLs through ExecuteStandalone.
-	qr, errs := vc.executor.scatterConn.ExecuteMultiShard(vc.ctx, rss, bqs, vc.tabletType, NewAutocommitSession(vc.safeSession.Session), false, false /* autocommit */)
+	qr, errs := vc.executor.scatterConn.ExecuteMultiShard(vc.ctx, rss, bqs, vc.tabletType, NewAutocommitSession(vc.safeSession.Session), false, false /* autocommit */, nil)
 	return qr, vterrors.Aggregate(errs)
 }
 
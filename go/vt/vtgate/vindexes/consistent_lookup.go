@@ -0,0 +1,137 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"vitess.io/vitess/go/sqltypes"
+)
+
+var (
+	_ Vindex = (*ConsistentLookup)(nil)
+	_ Lookup = (*ConsistentLookup)(nil)
+	_ Vindex = (*ConsistentLookupUnique)(nil)
+	_ Lookup = (*ConsistentLookupUnique)(nil)
+)
+
+func init() {
+	Register("consistent_lookup", NewConsistentLookup)
+	Register("consistent_lookup_unique", NewConsistentLookupUnique)
+}
+
+// ConsistentLookup is a non-unique lookup vindex that never misses
+// a committed row, even though the lookup table and the owner table
+// live in different shards and are not updated atomically.
+//
+// The lookup rows are created in autocommit mode, before the owner
+// row gets inserted. They are deleted in a post commit transaction,
+// which is only committed after the transaction that deletes the owner
+// row. If a commit fails, the lookup table is left with extra rows.
+// Such stale rows only cause an extra shard to be queried: the query
+// sent to that shard still filters on the actual column value.
+type ConsistentLookup struct {
+	LookupNonUnique
+}
+
+// NewConsistentLookup creates a ConsistentLookup vindex.
+// The supplied map has the following required fields:
+//   table: name of the backing table. It can be qualified by the keyspace.
+//   from: list of columns in the table that have the 'from' values of the lookup vindex.
+//   to: The 'to' column name of the table.
+//
+// The following fields are optional:
+//   write_only: in this mode, Map functions return the full keyrange causing a full scatter.
+func NewConsistentLookup(name string, m map[string]string) (Vindex, error) {
+	cl := &ConsistentLookup{LookupNonUnique{name: name}}
+
+	var err error
+	cl.writeOnly, err = boolFromMap(m, "write_only")
+	if err != nil {
+		return nil, err
+	}
+
+	// Creates are upserts, because the lookup rows of failed
+	// transactions can still be there.
+	if err := cl.lkp.Init(m, true /* autocommit */, true /* upsert */); err != nil {
+		return nil, err
+	}
+	return cl, nil
+}
+
+// Delete deletes the entry from the vindex table. The delete is only
+// committed after the current transaction, which deletes the owner row.
+func (cl *ConsistentLookup) Delete(vcursor VCursor, rowsColValues [][]sqltypes.Value, ksid []byte) error {
+	return cl.lkp.deleteRows(vcursor, rowsColValues, sqltypes.MakeTrusted(sqltypes.VarBinary, ksid), true /* postCommit */)
+}
+
+// Update updates the entry in the vindex table.
+func (cl *ConsistentLookup) Update(vcursor VCursor, oldValues []sqltypes.Value, ksid []byte, newValues []sqltypes.Value) error {
+	if err := cl.Delete(vcursor, [][]sqltypes.Value{oldValues}, ksid); err != nil {
+		return err
+	}
+	return cl.Create(vcursor, [][]sqltypes.Value{newValues}, [][]byte{ksid}, false /* ignoreMode */)
+}
+
+//====================================================================
+
+// ConsistentLookupUnique is the unique variant of ConsistentLookup.
+//
+// Its lookup rows are also created in autocommit mode and deleted in a
+// post commit transaction. Unlike ConsistentLookup, creates are plain
+// inserts: an upsert could silently move the value of a live row to
+// another keyspace id. So the row left behind by a failed transaction
+// must be removed before its value can be used again.
+type ConsistentLookupUnique struct {
+	LookupUnique
+}
+
+// NewConsistentLookupUnique creates a ConsistentLookupUnique vindex.
+// The supplied map has the following required fields:
+//   table: name of the backing table. It can be qualified by the keyspace.
+//   from: list of columns in the table that have the 'from' values of the lookup vindex.
+//   to: The 'to' column name of the table.
+//
+// The following fields are optional:
+//   write_only: in this mode, Map functions return the full keyrange causing a full scatter.
+func NewConsistentLookupUnique(name string, m map[string]string) (Vindex, error) {
+	clu := &ConsistentLookupUnique{LookupUnique{name: name}}
+
+	var err error
+	clu.writeOnly, err = boolFromMap(m, "write_only")
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't allow upserts for unique vindexes.
+	if err := clu.lkp.Init(m, true /* autocommit */, false /* upsert */); err != nil {
+		return nil, err
+	}
+	return clu, nil
+}
+
+// Delete deletes the entry from the vindex table. The delete is only
+// committed after the current transaction, which deletes the owner row.
+func (clu *ConsistentLookupUnique) Delete(vcursor VCursor, rowsColValues [][]sqltypes.Value, ksid []byte) error {
+	return clu.lkp.deleteRows(vcursor, rowsColValues, sqltypes.MakeTrusted(sqltypes.VarBinary, ksid), true /* postCommit */)
+}
+
+// Update updates the entry in the vindex table.
+func (clu *ConsistentLookupUnique) Update(vcursor VCursor, oldValues []sqltypes.Value, ksid []byte, newValues []sqltypes.Value) error {
+	if err := clu.Delete(vcursor, [][]sqltypes.Value{oldValues}, ksid); err != nil {
+		return err
+	}
+	return clu.Create(vcursor, [][]sqltypes.Value{newValues}, [][]byte{ksid}, false /* ignoreMode */)
+}
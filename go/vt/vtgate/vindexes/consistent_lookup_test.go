@@ -0,0 +1,298 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func createConsistentLookup(t *testing.T) Vindex {
+	t.Helper()
+	return createConsistentLookupVindex(t, "consistent_lookup")
+}
+
+func createConsistentLookupUnique(t *testing.T) Vindex {
+	t.Helper()
+	return createConsistentLookupVindex(t, "consistent_lookup_unique")
+}
+
+func createConsistentLookupVindex(t *testing.T, name string) Vindex {
+	t.Helper()
+	cl, err := CreateVindex(name, name, map[string]string{
+		"table": "t",
+		"from":  "fromc",
+		"to":    "toc",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cl
+}
+
+func TestConsistentLookupInfo(t *testing.T) {
+	cl := createConsistentLookup(t)
+	if cl.String() != "consistent_lookup" {
+		t.Errorf("String(): %s, want consistent_lookup", cl.String())
+	}
+	if cl.Cost() != 20 {
+		t.Errorf("Cost(): %d, want 20", cl.Cost())
+	}
+	if cl.IsUnique() {
+		t.Errorf("IsUnique(): true, want false")
+	}
+
+	_, err := CreateVindex("consistent_lookup", "consistent_lookup", map[string]string{
+		"table":      "t",
+		"from":       "fromc",
+		"to":         "toc",
+		"write_only": "invalid",
+	})
+	want := "write_only value must be 'true' or 'false': 'invalid'"
+	if err == nil || err.Error() != want {
+		t.Errorf("Create(bad_scatter): %v, want %s", err, want)
+	}
+}
+
+func TestConsistentLookupMap(t *testing.T) {
+	cl := createConsistentLookup(t)
+	vc := &vcursor{numRows: 2}
+
+	got, err := cl.Map(vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+	if err != nil {
+		t.Error(err)
+	}
+	want := []key.Destination{
+		key.DestinationKeyspaceIDs([][]byte{
+			[]byte("1"),
+			[]byte("2"),
+		}),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map(): %#v, want %+v", got, want)
+	}
+	// Lookups read the committed rows.
+	if vc.autocommits != 1 {
+		t.Errorf("autocommits: %d, want 1", vc.autocommits)
+	}
+}
+
+func TestConsistentLookupCreate(t *testing.T) {
+	cl := createConsistentLookup(t)
+	vc := &vcursor{}
+
+	err := cl.(Lookup).Create(vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, [][]byte{[]byte("test")}, false /* ignoreMode */)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Creates are autocommitted upserts.
+	wantqueries := []*querypb.BoundQuery{{
+		Sql: "insert into t(fromc, toc) values(:fromc0, :toc0) on duplicate key update fromc=values(fromc), toc=values(toc)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc0": sqltypes.Int64BindVariable(1),
+			"toc0":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}}
+	if !reflect.DeepEqual(vc.queries, wantqueries) {
+		t.Errorf("lookup.Create queries:\n%v, want\n%v", vc.queries, wantqueries)
+	}
+	if vc.autocommits != 1 {
+		t.Errorf("autocommits: %d, want 1", vc.autocommits)
+	}
+}
+
+func TestConsistentLookupDeleteUpdate(t *testing.T) {
+	cl := createConsistentLookup(t)
+	vc := &vcursor{}
+
+	// Deletes are committed after the transaction.
+	err := cl.(Lookup).Delete(vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, []byte("test"))
+	if err != nil {
+		t.Error(err)
+	}
+	wantqueries := []*querypb.BoundQuery{{
+		Sql: "delete from t where fromc = :fromc and toc = :toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc": sqltypes.Int64BindVariable(1),
+			"toc":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}}
+	if !reflect.DeepEqual(vc.queries, wantqueries) {
+		t.Errorf("lookup.Delete queries:\n%v, want\n%v", vc.queries, wantqueries)
+	}
+	if vc.autocommits != 0 {
+		t.Errorf("autocommits: %d, want 0", vc.autocommits)
+	}
+	if vc.postcommits != 1 {
+		t.Errorf("postcommits: %d, want 1", vc.postcommits)
+	}
+
+	vc = &vcursor{}
+	err = cl.(Lookup).Update(vc, []sqltypes.Value{sqltypes.NewInt64(1)}, []byte("test"), []sqltypes.Value{sqltypes.NewInt64(2)})
+	if err != nil {
+		t.Error(err)
+	}
+	wantqueries = []*querypb.BoundQuery{{
+		Sql: "delete from t where fromc = :fromc and toc = :toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc": sqltypes.Int64BindVariable(1),
+			"toc":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}, {
+		Sql: "insert into t(fromc, toc) values(:fromc0, :toc0) on duplicate key update fromc=values(fromc), toc=values(toc)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc0": sqltypes.Int64BindVariable(2),
+			"toc0":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}}
+	if !reflect.DeepEqual(vc.queries, wantqueries) {
+		t.Errorf("lookup.Update queries:\n%v, want\n%v", vc.queries, wantqueries)
+	}
+	if vc.autocommits != 1 {
+		t.Errorf("autocommits: %d, want 1", vc.autocommits)
+	}
+	if vc.postcommits != 1 {
+		t.Errorf("postcommits: %d, want 1", vc.postcommits)
+	}
+}
+
+func TestConsistentLookupUniqueInfo(t *testing.T) {
+	clu := createConsistentLookupUnique(t)
+	if clu.String() != "consistent_lookup_unique" {
+		t.Errorf("String(): %s, want consistent_lookup_unique", clu.String())
+	}
+	if clu.Cost() != 10 {
+		t.Errorf("Cost(): %d, want 10", clu.Cost())
+	}
+	if !clu.IsUnique() {
+		t.Errorf("IsUnique(): false, want true")
+	}
+
+	_, err := CreateVindex("consistent_lookup_unique", "consistent_lookup_unique", map[string]string{
+		"table":      "t",
+		"from":       "fromc",
+		"to":         "toc",
+		"write_only": "invalid",
+	})
+	want := "write_only value must be 'true' or 'false': 'invalid'"
+	if err == nil || err.Error() != want {
+		t.Errorf("Create(bad_scatter): %v, want %s", err, want)
+	}
+}
+
+func TestConsistentLookupUniqueMap(t *testing.T) {
+	clu := createConsistentLookupUnique(t)
+	vc := &vcursor{numRows: 1}
+
+	got, err := clu.Map(vc, []sqltypes.Value{sqltypes.NewInt64(1)})
+	if err != nil {
+		t.Error(err)
+	}
+	want := []key.Destination{
+		key.DestinationKeyspaceID([]byte("1")),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map(): %#v, want %+v", got, want)
+	}
+	// Lookups read the committed rows.
+	if vc.autocommits != 1 {
+		t.Errorf("autocommits: %d, want 1", vc.autocommits)
+	}
+}
+
+func TestConsistentLookupUniqueCreate(t *testing.T) {
+	clu := createConsistentLookupUnique(t)
+	vc := &vcursor{}
+
+	err := clu.(Lookup).Create(vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, [][]byte{[]byte("test")}, false /* ignoreMode */)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Creates are autocommitted, but they are not upserts.
+	wantqueries := []*querypb.BoundQuery{{
+		Sql: "insert into t(fromc, toc) values(:fromc0, :toc0)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc0": sqltypes.Int64BindVariable(1),
+			"toc0":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}}
+	if !reflect.DeepEqual(vc.queries, wantqueries) {
+		t.Errorf("lookup.Create queries:\n%v, want\n%v", vc.queries, wantqueries)
+	}
+	if vc.autocommits != 1 {
+		t.Errorf("autocommits: %d, want 1", vc.autocommits)
+	}
+}
+
+func TestConsistentLookupUniqueDeleteUpdate(t *testing.T) {
+	clu := createConsistentLookupUnique(t)
+	vc := &vcursor{}
+
+	// Deletes are committed after the transaction.
+	err := clu.(Lookup).Delete(vc, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, []byte("test"))
+	if err != nil {
+		t.Error(err)
+	}
+	wantqueries := []*querypb.BoundQuery{{
+		Sql: "delete from t where fromc = :fromc and toc = :toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc": sqltypes.Int64BindVariable(1),
+			"toc":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}}
+	if !reflect.DeepEqual(vc.queries, wantqueries) {
+		t.Errorf("lookup.Delete queries:\n%v, want\n%v", vc.queries, wantqueries)
+	}
+	if vc.postcommits != 1 {
+		t.Errorf("postcommits: %d, want 1", vc.postcommits)
+	}
+
+	vc = &vcursor{}
+	err = clu.(Lookup).Update(vc, []sqltypes.Value{sqltypes.NewInt64(1)}, []byte("test"), []sqltypes.Value{sqltypes.NewInt64(2)})
+	if err != nil {
+		t.Error(err)
+	}
+	wantqueries = []*querypb.BoundQuery{{
+		Sql: "delete from t where fromc = :fromc and toc = :toc",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc": sqltypes.Int64BindVariable(1),
+			"toc":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}, {
+		Sql: "insert into t(fromc, toc) values(:fromc0, :toc0)",
+		BindVariables: map[string]*querypb.BindVariable{
+			"fromc0": sqltypes.Int64BindVariable(2),
+			"toc0":   sqltypes.BytesBindVariable([]byte("test")),
+		},
+	}}
+	if !reflect.DeepEqual(vc.queries, wantqueries) {
+		t.Errorf("lookup.Update queries:\n%v, want\n%v", vc.queries, wantqueries)
+	}
+	if vc.autocommits != 1 {
+		t.Errorf("autocommits: %d, want 1", vc.autocommits)
+	}
+	if vc.postcommits != 1 {
+		t.Errorf("postcommits: %d, want 1", vc.postcommits)
+	}
+}
@@ -190,6 +190,13 @@ func (lkp *lookupInternal) Delete(vcursor VCursor, rowsColValues [][]sqltypes.Va
 	if lkp.Autocommit {
 		return nil
 	}
+	return lkp.deleteRows(vcursor, rowsColValues, value, false /* postCommit */)
+}
+
+// deleteRows deletes the association between ids and value as part
+// of the current transaction. If postCommit is set, the deletes are
+// only committed after the current transaction.
+func (lkp *lookupInternal) deleteRows(vcursor VCursor, rowsColValues [][]sqltypes.Value, value sqltypes.Value, postCommit bool) error {
 	if len(rowsColValues) == 0 {
 		// This code is unreachable. It's just a failsafe.
 		return nil
@@ -205,7 +212,12 @@ func (lkp *lookupInternal) Delete(vcursor VCursor, rowsColValues [][]sqltypes.Va
 			bindVars[lkp.FromColumns[colIdx]] = sqltypes.ValueBindVariable(columnValue)
 		}
 		bindVars[lkp.To] = sqltypes.ValueBindVariable(value)
-		_, err := vcursor.Execute("VindexDelete", lkp.del, bindVars, true /* isDML */)
+		var err error
+		if postCommit {
+			_, err = vcursor.ExecutePostCommit("VindexDelete", lkp.del, bindVars, true /* isDML */)
+		} else {
+			_, err = vcursor.Execute("VindexDelete", lkp.del, bindVars, true /* isDML */)
+		}
 		if err != nil {
 			return fmt.Errorf("lookup.Delete: %v", err)
 		}
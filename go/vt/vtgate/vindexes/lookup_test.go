@@ -39,6 +39,7 @@ type vcursor struct {
 	result      *sqltypes.Result
 	queries     []*querypb.BoundQuery
 	autocommits int
+	postcommits int
 }
 
 func (vc *vcursor) Execute(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error) {
@@ -50,6 +51,11 @@ func (vc *vcursor) ExecuteAutocommit(method string, query string, bindvars map[s
 	return vc.execute(method, query, bindvars, isDML)
 }
 
+func (vc *vcursor) ExecutePostCommit(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error) {
+	vc.postcommits++
+	return vc.execute(method, query, bindvars, isDML)
+}
+
 func (vc *vcursor) execute(method string, query string, bindvars map[string]*querypb.BindVariable, isDML bool) (*sqltypes.Result, error) {
 	vc.queries = append(vc.queries, &querypb.BoundQuery{
 		Sql:           query,
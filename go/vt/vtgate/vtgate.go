@@ -45,6 +45,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 
 	"vitess.io/vitess/go/vt/vtgate/gateway"
+	"vitess.io/vitess/go/vt/vtgate/queryacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -63,6 +64,9 @@ var (
 	enableForwarding    = flag.Bool("enable_forwarding", false, "if specified, this process will also expose a QueryService interface that allows other vtgates to talk through this vtgate to the underlying tablets.")
 	l2vtgateAddrs       flagutil.StringListValue
 	disableLocalGateway = flag.Bool("disable_local_gateway", false, "if specified, this process will not route any queries to local tablets in the local cell")
+	maxScatterShards    = flag.Int("max_scatter_shards", 0, "the maximum number of shards a single query can be sent to. Queries that target more shards fail, unless they have the IGNORE_SCATTER_LIMITS comment directive. 0 means no limit.")
+	maxMemoryRows       = flag.Int("max_memory_rows", 0, "the maximum number of rows a route of a non-streaming query can buffer from the tablets. The limit applies to each execution of a route, e.g. separately to each execution of the right side of a join. Queries fail as soon as they go over it, unless they have the IGNORE_SCATTER_LIMITS comment directive. Streaming queries are not limited. 0 means no limit.")
+	maxMemoryBytes      = flag.Int("max_memory_bytes", 0, "the maximum size in bytes of the rows a route of a non-streaming query can buffer from the tablets. The limit applies to each execution of a route, e.g. separately to each execution of the right side of a join. Queries fail as soon as they go over it, unless they have the IGNORE_SCATTER_LIMITS comment directive. Streaming queries are not limited. 0 means no limit.")
 )
 
 func getTxMode() vtgatepb.TransactionMode {
@@ -158,6 +162,9 @@ func Init(ctx context.Context, hc discovery.HealthCheck, serv srvtopo.Server, ce
 	// catch the initial load stats.
 	vschemaCounters = stats.NewCountersWithSingleLabel("VtgateVSchemaCounts", "Vtgate vschema counts", "changes")
 
+	// Load the table ACL, if any, before serving queries.
+	queryacl.Init(serv.GetTopoServer())
+
 	// Build objects from low to high level.
 	// Start with the gateway. If we can't reach the topology service,
 	// we can't go on much further, so we log.Fatal out.
@@ -419,6 +426,9 @@ func (vtg *VTGate) ExecuteShards(ctx context.Context, sql string, bindVariables
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	sql = sqlannotation.AnnotateIfDML(sql, nil)
 
@@ -468,6 +478,9 @@ func (vtg *VTGate) ExecuteKeyspaceIds(ctx context.Context, sql string, bindVaria
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	sql = sqlannotation.AnnotateIfDML(sql, keyspaceIds)
 	if sqlparser.IsDML(sql) && len(keyspaceIds) > 1 {
@@ -510,6 +523,9 @@ func (vtg *VTGate) ExecuteKeyRanges(ctx context.Context, sql string, bindVariabl
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	sql = sqlannotation.AnnotateIfDML(sql, nil)
 
@@ -548,6 +564,9 @@ func (vtg *VTGate) ExecuteEntityIds(ctx context.Context, sql string, bindVariabl
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	sql = sqlannotation.AnnotateIfDML(sql, nil)
 
@@ -588,6 +607,9 @@ func (vtg *VTGate) ExecuteBatchShards(ctx context.Context, queries []*vtgatepb.B
 			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 			goto handleError
 		}
+		if err = checkTableACL(ctx, query.Query.Sql, query.Keyspace); err != nil {
+			goto handleError
+		}
 	}
 
 	annotateBoundShardQueriesAsUnfriendly(queries)
@@ -637,6 +659,9 @@ func (vtg *VTGate) ExecuteBatchKeyspaceIds(ctx context.Context, queries []*vtgat
 			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 			goto handleError
 		}
+		if err = checkTableACL(ctx, query.Query.Sql, query.Keyspace); err != nil {
+			goto handleError
+		}
 	}
 
 	annotateBoundKeyspaceIDQueries(queries)
@@ -691,6 +716,9 @@ func (vtg *VTGate) StreamExecuteKeyspaceIds(ctx context.Context, sql string, bin
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	err = vtg.resolver.StreamExecute(
 		ctx,
@@ -740,6 +768,9 @@ func (vtg *VTGate) StreamExecuteKeyRanges(ctx context.Context, sql string, bindV
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	err = vtg.resolver.StreamExecute(
 		ctx,
@@ -784,6 +815,9 @@ func (vtg *VTGate) StreamExecuteShards(ctx context.Context, sql string, bindVari
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 		goto handleError
 	}
+	if err = checkTableACL(ctx, sql, keyspace); err != nil {
+		goto handleError
+	}
 
 	err = vtg.resolver.StreamExecute(
 		ctx,
@@ -886,6 +920,9 @@ func (vtg *VTGate) SplitQuery(
 	if bvErr := sqltypes.ValidateBindVariables(bindVariables); bvErr != nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
 	}
+	if err := checkTableACL(ctx, sql, keyspace); err != nil {
+		return nil, err
+	}
 
 	// TODO(erez): Add validation of SplitQuery parameters.
 	rss, srvKeyspace, err := vtg.resolver.resolver.GetAllShards(ctx, keyspace, topodatapb.TabletType_RDONLY)
@@ -30,12 +30,15 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/queryacl"
 	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tableaclpb "vitess.io/vitess/go/vt/proto/tableacl"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -1130,6 +1133,57 @@ func TestVTGateStreamExecuteShards(t *testing.T) {
 	}
 }
 
+func TestVTGateTableACL(t *testing.T) {
+	ks := "TestVTGateTableACL"
+	shard := "0"
+	createSandbox(ks)
+	hcVTGateTest.Reset()
+	sbc := hcVTGateTest.AddTestTablet("aa", "1.1.1.1", 1001, ks, shard, topodatapb.TabletType_MASTER, true, 1, nil)
+	err := queryacl.Set(&tableaclpb.Config{
+		TableGroups: []*tableaclpb.TableGroupSpec{{
+			TableNamesOrPrefixes: []string{ks + ".t1"},
+			Readers:              []string{"reader"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queryacl.Set(nil)
+	ctx := callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("reader"))
+
+	if _, err := rpcVTGate.ExecuteShards(ctx, "select * from t1", nil, ks, []string{shard}, topodatapb.TabletType_MASTER, nil, false, nil); err != nil {
+		t.Errorf("ExecuteShards(t1): %v, want nil", err)
+	}
+	if execCount := sbc.ExecCount.Get(); execCount != 1 {
+		t.Errorf("want 1, got %v", execCount)
+	}
+
+	want := `table acl error: "reader" [] does not have the READER role on table "t2"`
+	_, err = rpcVTGate.ExecuteShards(ctx, "select * from t2", nil, ks, []string{shard}, topodatapb.TabletType_MASTER, nil, false, nil)
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecuteShards(t2): %v, want %s", err, want)
+	}
+	_, err = rpcVTGate.ExecuteBatchShards(ctx, []*vtgatepb.BoundShardQuery{{
+		Query:    &querypb.BoundQuery{Sql: "select * from t1"},
+		Keyspace: ks,
+		Shards:   []string{shard},
+	}, {
+		Query:    &querypb.BoundQuery{Sql: "select * from t2"},
+		Keyspace: ks,
+		Shards:   []string{shard},
+	}}, topodatapb.TabletType_MASTER, false, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ExecuteBatchShards: %v, want %s", err, want)
+	}
+	err = rpcVTGate.StreamExecuteShards(ctx, "select * from t2", nil, ks, []string{shard}, topodatapb.TabletType_MASTER, nil, func(*sqltypes.Result) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("StreamExecuteShards: %v, want %s", err, want)
+	}
+	if execCount := sbc.ExecCount.Get(); execCount != 1 {
+		t.Errorf("want 1, got %v", execCount)
+	}
+}
+
 func TestVTGateSplitQuerySharded(t *testing.T) {
 	keyspace := "TestVTGateSplitQuery"
 	keyranges, err := key.ParseShardingSpec(DefaultShardSpec)
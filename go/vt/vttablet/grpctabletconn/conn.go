@@ -23,11 +23,11 @@ import (
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
-	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/tabletaddr"
 	"vitess.io/vitess/go/vt/vttablet/tabletconn"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -62,11 +62,13 @@ type gRPCQueryClient struct {
 // DialTablet creates and initializes gRPCQueryClient.
 func DialTablet(tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
 	// create the RPC client
-	addr := ""
-	if grpcPort, ok := tablet.PortMap["grpc"]; ok {
-		addr = netutil.JoinHostPort(tablet.Hostname, grpcPort)
-	} else {
-		addr = tablet.Hostname
+	addr := tablet.Hostname
+	if _, ok := tablet.PortMap["grpc"]; ok {
+		var err error
+		addr, err = tabletaddr.Resolve(tablet, "grpc")
+		if err != nil {
+			return nil, err
+		}
 	}
 	opt, err := grpcclient.SecureDialOption(*cert, *key, *ca, *name)
 	if err != nil {
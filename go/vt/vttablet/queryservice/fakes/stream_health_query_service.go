@@ -112,6 +112,21 @@ func (q *StreamHealthQueryService) AddHealthResponseWithSecondsBehindMaster(repl
 	}
 }
 
+// AddHealthResponseWithFilteredReplicationDelay adds a faked health response
+// to the buffer channel. It reports one filtered replication stream which lags
+// behind by "delay" seconds.
+func (q *StreamHealthQueryService) AddHealthResponseWithFilteredReplicationDelay(delay uint32) {
+	q.healthResponses <- &querypb.StreamHealthResponse{
+		Target:  proto.Clone(&q.target).(*querypb.Target),
+		Serving: true,
+		RealtimeStats: &querypb.RealtimeStats{
+			SecondsBehindMaster:                    DefaultSecondsBehindMaster,
+			BinlogPlayersCount:                     1,
+			SecondsBehindMasterFilteredReplication: int64(delay),
+		},
+	}
+}
+
 // AddHealthResponseWithNotServing adds a faked health response to the
 // buffer channel. Only "Serving" is different in this message.
 func (q *StreamHealthQueryService) AddHealthResponseWithNotServing() {
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tabletaddr resolves the address to dial a tablet.
+//
+// By default, the address is the Hostname and the port of the tablet
+// record. In dynamic environments (Kubernetes, Mesos, ...), the port of
+// a tablet can change when it is rescheduled, and the tablet record is
+// then stale until the tablet restarts. Resolvers can instead look the
+// address up in a service discovery system, using the Hostname of the
+// tablet record as a stable service name.
+package tabletaddr
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strings"
+
+	"vitess.io/vitess/go/netutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	resolverName = flag.String("tablet_addr_resolver", "static", "how to resolve the address of a tablet port: 'static' uses the hostname and port of the tablet record, 'srv' looks up the DNS SRV record _<port name>._tcp.<hostname>")
+)
+
+// Resolver returns the host:port address to dial the port named
+// portName (like "grpc") of a tablet.
+type Resolver func(tablet *topodatapb.Tablet, portName string) (string, error)
+
+var resolvers = make(map[string]Resolver)
+
+func init() {
+	RegisterResolver("static", staticResolver)
+	RegisterResolver("srv", srvResolver)
+}
+
+// RegisterResolver is meant to be used by Resolver implementations
+// to self register.
+func RegisterResolver(name string, resolver Resolver) {
+	if _, ok := resolvers[name]; ok {
+		log.Fatalf("Resolver %s already exists", name)
+	}
+	resolvers[name] = resolver
+}
+
+// Resolve returns the address to dial the port named portName of the
+// tablet, using the resolver described by the command line flag.
+func Resolve(tablet *topodatapb.Tablet, portName string) (string, error) {
+	resolver, ok := resolvers[*resolverName]
+	if !ok {
+		return "", fmt.Errorf("no resolver registered for tablet address resolver %v", *resolverName)
+	}
+	return resolver(tablet, portName)
+}
+
+// staticResolver uses the address stored in the tablet record.
+func staticResolver(tablet *topodatapb.Tablet, portName string) (string, error) {
+	return netutil.JoinHostPort(tablet.Hostname, tablet.PortMap[portName]), nil
+}
+
+// srvResolver looks up the _<portName>._tcp.<hostname> SRV record,
+// as provided for instance by the Kubernetes and Consul DNS servers.
+func srvResolver(tablet *topodatapb.Tablet, portName string) (string, error) {
+	_, addrs, err := net.LookupSRV(portName, "tcp", tablet.Hostname)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve port %v of tablet %v: %v", portName, topoproto.TabletAliasString(tablet.Alias), err)
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("cannot resolve port %v of tablet %v: no SRV record for %v", portName, topoproto.TabletAliasString(tablet.Alias), tablet.Hostname)
+	}
+	// LookupSRV sorts the records by priority, and randomizes them
+	// by weight within a priority.
+	return netutil.JoinHostPort(strings.TrimSuffix(addrs[0].Target, "."), int32(addrs[0].Port)), nil
+}
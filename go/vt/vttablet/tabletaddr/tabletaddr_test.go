@@ -0,0 +1,58 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletaddr
+
+import (
+	"testing"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestResolve(t *testing.T) {
+	tablet := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{
+			Cell: "cell1",
+			Uid:  100,
+		},
+		Hostname: "vttablet-100",
+		PortMap: map[string]int32{
+			"grpc": 15991,
+		},
+	}
+
+	// The static resolver uses the tablet record.
+	addr, err := Resolve(tablet, "grpc")
+	if err != nil || addr != "vttablet-100:15991" {
+		t.Errorf("Resolve() = %v, %v, want vttablet-100:15991", addr, err)
+	}
+
+	// A custom resolver.
+	RegisterResolver("test", func(tablet *topodatapb.Tablet, portName string) (string, error) {
+		return tablet.Hostname + ".svc:" + portName, nil
+	})
+	defer func(name string) { *resolverName = name }(*resolverName)
+	*resolverName = "test"
+	addr, err = Resolve(tablet, "grpc")
+	if err != nil || addr != "vttablet-100.svc:grpc" {
+		t.Errorf("Resolve() with test resolver = %v, %v, want vttablet-100.svc:grpc", addr, err)
+	}
+
+	*resolverName = "unknown"
+	if _, err := Resolve(tablet, "grpc"); err == nil {
+		t.Errorf("Resolve() with unknown resolver should have failed")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/tb"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
@@ -177,12 +178,32 @@ func (ct *controller) runBlp(ctx context.Context) (err error) {
 		}
 
 		player := binlogplayer.NewBinlogPlayerTables(dbClient, tablet, tables, ct.id, ct.blpStats)
+		if err := ct.setCatchUpPosition(player); err != nil {
+			return err
+		}
 		return player.ApplyBinlogEvents(ctx)
 	}
 	player := binlogplayer.NewBinlogPlayerKeyRange(dbClient, tablet, ct.source.KeyRange, ct.id, ct.blpStats)
+	if err := ct.setCatchUpPosition(player); err != nil {
+		return err
+	}
 	return player.ApplyBinlogEvents(ctx)
 }
 
+// setCatchUpPosition passes the optional catch up position of the source
+// to the player.
+func (ct *controller) setCatchUpPosition(player *binlogplayer.BinlogPlayer) error {
+	if ct.source.CatchUpPosition == "" {
+		return nil
+	}
+	pos, err := mysql.DecodePosition(ct.source.CatchUpPosition)
+	if err != nil {
+		return vterrors.Wrap(err, "invalid catch_up_position")
+	}
+	player.SetCatchUpPosition(pos)
+	return nil
+}
+
 func (ct *controller) Stop() {
 	ct.cancel()
 	<-ct.done
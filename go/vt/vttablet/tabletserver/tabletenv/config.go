@@ -101,6 +101,7 @@ func init() {
 
 	flag.BoolVar(&Config.EnforceStrictTransTables, "enforce_strict_trans_tables", DefaultQsConfig.EnforceStrictTransTables, "If true, vttablet requires MySQL to run with STRICT_TRANS_TABLES on. It is recommended to not turn this flag off. Otherwise MySQL may alter your supplied values before saving them to the database.")
 	flag.BoolVar(&Config.EnableConsolidator, "enable-consolidator", DefaultQsConfig.EnableConsolidator, "This option enables the query consolidator.")
+	flag.BoolVar(&Config.EnableConsistentSnapshotReadOnly, "enable_consistent_snapshot_read_only", DefaultQsConfig.EnableConsistentSnapshotReadOnly, "If true, non-master tablets allow read-only transactions with a consistent snapshot (CONSISTENT_SNAPSHOT_READ_ONLY), e.g. for vtworker diffs with --use_consistent_snapshot. They count against -queryserver-config-transaction-cap.")
 }
 
 // Init must be called after flag.Parse, and before doing any other operations.
@@ -173,6 +174,8 @@ type TabletConfig struct {
 
 	EnforceStrictTransTables bool
 	EnableConsolidator       bool
+
+	EnableConsistentSnapshotReadOnly bool
 }
 
 // TransactionLimitConfig captures configuration of transaction pool slots
@@ -248,6 +251,8 @@ var DefaultQsConfig = TabletConfig{
 
 	EnforceStrictTransTables: true,
 	EnableConsolidator:       true,
+
+	EnableConsistentSnapshotReadOnly: false,
 }
 
 // defaultTxThrottlerConfig formats the default throttlerdata.Configuration
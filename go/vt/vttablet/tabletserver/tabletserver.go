@@ -139,6 +139,9 @@ type TabletServer struct {
 	BeginTimeout           sync2.AtomicDuration
 	TerseErrors            bool
 	enableHotRowProtection bool
+	// enableSnapshotReadOnly allows read-only transactions with
+	// a consistent snapshot on non-master tablets.
+	enableSnapshotReadOnly bool
 
 	// mu is used to access state. The lock should only be held
 	// for short periods. For longer periods, you have to transition
@@ -224,6 +227,7 @@ func NewTabletServer(config tabletenv.TabletConfig, topoServer *topo.Server, ali
 		BeginTimeout:           sync2.NewAtomicDuration(time.Duration(config.TxPoolTimeout * 1e9)),
 		TerseErrors:            config.TerseErrors,
 		enableHotRowProtection: config.EnableHotRowProtection || config.EnableHotRowProtectionDryRun,
+		enableSnapshotReadOnly: config.EnableConsistentSnapshotReadOnly,
 		checkMySQLThrottler:    sync2.NewSemaphore(1, 0),
 		streamHealthMap:        make(map[int]chan<- *querypb.StreamHealthResponse),
 		history:                history.New(10),
@@ -537,6 +541,11 @@ func (tsv *TabletServer) serveNewType() (err error) {
 		// be sure that the tx pool won't change after the wait.
 		tsv.beginRequests.Wait()
 		tsv.te.Close(true)
+		if tsv.enableSnapshotReadOnly {
+			// Read-only transactions with a consistent snapshot are
+			// still allowed, e.g. for the diffs of vtworker.
+			tsv.te.OpenReadOnly()
+		}
 		tsv.watcher.Open()
 		tsv.txThrottler.Close()
 
@@ -1026,7 +1035,7 @@ func (tsv *TabletServer) ExecuteBatch(ctx context.Context, target *querypb.Targe
 	// tsv.convertAndLogError. That's because the methods which returned "err",
 	// e.g. tsv.Execute(), already called that function and therefore already
 	// converted and logged the error.
-	if err = tsv.startRequest(ctx, target, false /* isBegin */, allowOnShutdown); err != nil {
+	if err = tsv.startRequest(ctx, target, options, false /* isBegin */, allowOnShutdown); err != nil {
 		return nil, err
 	}
 	defer tsv.endRequest(false)
@@ -1229,7 +1238,7 @@ func (tsv *TabletServer) PurgeMessages(ctx context.Context, target *querypb.Targ
 }
 
 func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, queryGenerator func() (string, map[string]*querypb.BindVariable, error)) (count int64, err error) {
-	if err = tsv.startRequest(ctx, target, true /* isBegin */, false /* allowOnShutdown */); err != nil {
+	if err = tsv.startRequest(ctx, target, nil /* options */, true /* isBegin */, false /* allowOnShutdown */); err != nil {
 		return 0, err
 	}
 	defer tsv.endRequest(true)
@@ -1340,7 +1349,7 @@ func (tsv *TabletServer) execRequest(
 	logStats.OriginalSQL = sql
 	logStats.BindVariables = bindVariables
 	defer tsv.handlePanicAndSendLogStats(sql, bindVariables, &err, logStats)
-	if err = tsv.startRequest(ctx, target, isBegin, allowOnShutdown); err != nil {
+	if err = tsv.startRequest(ctx, target, options, isBegin, allowOnShutdown); err != nil {
 		return err
 	}
 
@@ -1817,7 +1826,7 @@ func (tsv *TabletServer) UpdateStream(ctx context.Context, target *querypb.Targe
 	}
 
 	// Validate proper target is used.
-	if err = tsv.startRequest(ctx, target, false /* isBegin */, false /* allowOnShutdown */); err != nil {
+	if err = tsv.startRequest(ctx, target, nil /* options */, false /* isBegin */, false /* allowOnShutdown */); err != nil {
 		return err
 	}
 	defer tsv.endRequest(false)
@@ -1861,7 +1870,10 @@ func (tsv *TabletServer) Close(ctx context.Context) error {
 // to true, which increments an additional waitgroup.  During state
 // transitions, this waitgroup will be checked to make sure that no
 // such statements are in-flight while we resolve the tx pool.
-func (tsv *TabletServer) startRequest(ctx context.Context, target *querypb.Target, isBegin, allowOnShutdown bool) (err error) {
+// With -enable_consistent_snapshot_read_only, non-master tablets allow
+// begin requests whose options ask for a read-only transaction with a
+// consistent snapshot.
+func (tsv *TabletServer) startRequest(ctx context.Context, target *querypb.Target, options *querypb.ExecuteOptions, isBegin, allowOnShutdown bool) (err error) {
 	tsv.mu.Lock()
 	defer tsv.mu.Unlock()
 	if tsv.state == StateServing {
@@ -1880,7 +1892,7 @@ verifyTarget:
 			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid keyspace %v", target.Keyspace)
 		case target.Shard != tsv.target.Shard:
 			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid shard %v", target.Shard)
-		case isBegin && tsv.target.TabletType != topodatapb.TabletType_MASTER:
+		case isBegin && tsv.target.TabletType != topodatapb.TabletType_MASTER && !tsv.allowNonMasterBegin(options):
 			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "transactional statement disallowed on non-master tablet: %v", tsv.target.TabletType)
 		case target.TabletType != tsv.target.TabletType:
 			for _, otherType := range tsv.alsoAllow {
@@ -1912,6 +1924,12 @@ func (tsv *TabletServer) endRequest(isBegin bool) {
 	}
 }
 
+// allowNonMasterBegin returns true if a transaction with "options" may be
+// started on a non-master tablet.
+func (tsv *TabletServer) allowNonMasterBegin(options *querypb.ExecuteOptions) bool {
+	return tsv.enableSnapshotReadOnly && options.GetTransactionIsolation() == querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY
+}
+
 func (tsv *TabletServer) registerDebugHealthHandler() {
 	http.HandleFunc("/debug/health", func(w http.ResponseWriter, r *http.Request) {
 		if err := acl.CheckAccessHTTP(r, acl.MONITORING); err != nil {
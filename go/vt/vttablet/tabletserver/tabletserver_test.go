@@ -588,6 +588,16 @@ func TestTabletServerTarget(t *testing.T) {
 		t.Errorf("err: %v, must contain %s", err, want)
 	}
 
+	// Read-only transactions with a consistent snapshot need
+	// -enable_consistent_snapshot_read_only.
+	target2 = proto.Clone(&target1).(*querypb.Target)
+	target2.TabletType = topodatapb.TabletType_REPLICA
+	_, err = tsv.Begin(ctx, target2, &querypb.ExecuteOptions{TransactionIsolation: querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY})
+	want = "transactional statement disallowed on non-master tablet"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("err: %v, must contain %s", err, want)
+	}
+
 	// Disallow all if service is stopped.
 	tsv.StopService()
 	_, err = tsv.Execute(ctx, &target1, "select * from test_table limit 1000", nil, 0, nil)
@@ -597,6 +607,48 @@ func TestTabletServerTarget(t *testing.T) {
 	}
 }
 
+func TestTabletServerConsistentSnapshotReadOnly(t *testing.T) {
+	db := setUpTabletServerTest(t)
+	defer db.Close()
+	testUtils := newTestUtils()
+	config := testUtils.newQueryServiceConfig()
+	config.EnableConsistentSnapshotReadOnly = true
+	tsv := NewTabletServerWithNilTopoServer(config)
+	dbcfgs := testUtils.newDBConfigs(db)
+	target := querypb.Target{
+		Keyspace:   "test_keyspace",
+		Shard:      "test_shard",
+		TabletType: topodatapb.TabletType_REPLICA,
+	}
+	if err := tsv.StartService(target, dbcfgs); err != nil {
+		t.Fatalf("StartService failed: %v", err)
+	}
+	defer tsv.StopService()
+	ctx := context.Background()
+
+	// Regular transactions are still disallowed.
+	_, err := tsv.Begin(ctx, &target, nil)
+	want := "transactional statement disallowed on non-master tablet"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("err: %v, must contain %s", err, want)
+	}
+
+	// Read-only transactions with a consistent snapshot are allowed.
+	db.AddQuery("set transaction isolation level REPEATABLE READ", &sqltypes.Result{})
+	db.AddQuery("start transaction with consistent snapshot, read only", &sqltypes.Result{})
+	db.AddQuery("select * from test_table limit 1000", &sqltypes.Result{})
+	transactionID, err := tsv.Begin(ctx, &target, &querypb.ExecuteOptions{TransactionIsolation: querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY})
+	if err != nil {
+		t.Fatalf("Begin with consistent snapshot on replica failed: %v", err)
+	}
+	if _, err := tsv.Execute(ctx, &target, "select * from test_table limit 1000", nil, transactionID, nil); err != nil {
+		t.Error(err)
+	}
+	if err := tsv.Rollback(ctx, &target, transactionID); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestTabletServerStopWithPrepare(t *testing.T) {
 	// Reuse code from tx_executor_test.
 	_, tsv, db := newTestTxExecutor(t)
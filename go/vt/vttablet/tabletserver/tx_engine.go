@@ -44,6 +44,8 @@ type TxEngine struct {
 	coordinatorAddress   string
 	abandonAge           time.Duration
 	ticks                *timer.Timer
+	// readOnly is set if the engine was opened by OpenReadOnly.
+	readOnly bool
 
 	txPool       *TxPool
 	preparedPool *TxPreparedPool
@@ -124,7 +126,12 @@ func (te *TxEngine) Init() error {
 // all previously prepared transactions from the redo log.
 func (te *TxEngine) Open() {
 	if te.isOpen {
-		return
+		if !te.readOnly {
+			return
+		}
+		// Roll back the read-only transactions before 2pc resolves
+		// the prepared transactions.
+		te.Close(true)
 	}
 	te.txPool.Open(te.dbconfigs.AppWithDB(), te.dbconfigs.DbaWithDB(), te.dbconfigs.AppDebugWithDB())
 	if !te.twopcEnabled {
@@ -144,6 +151,19 @@ func (te *TxEngine) Open() {
 	te.isOpen = true
 }
 
+// OpenReadOnly opens only the transaction pool of the TxEngine.
+// It is used on non-master tablets which allow only read-only transactions
+// (see ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY). 2pc stays closed.
+// A subsequent Open rolls back all read-only transactions.
+func (te *TxEngine) OpenReadOnly() {
+	if te.isOpen {
+		return
+	}
+	te.txPool.Open(te.dbconfigs.AppWithDB(), te.dbconfigs.DbaWithDB(), te.dbconfigs.AppDebugWithDB())
+	te.isOpen = true
+	te.readOnly = true
+}
+
 // Close closes the TxEngine. If the immediate flag is on,
 // then all current transactions are immediately rolled back.
 // Otherwise, the function waits for all current transactions
@@ -201,6 +221,7 @@ func (te *TxEngine) Close(immediate bool) {
 	te.txPool.Close()
 	te.twoPC.Close()
 	te.isOpen = false
+	te.readOnly = false
 }
 
 // prepareFromRedo replays and prepares the transactions
@@ -134,3 +134,35 @@ func TestTxEngineClose(t *testing.T) {
 		t.Errorf("Close time: %v, must be over 0.1", diff)
 	}
 }
+
+func TestTxEngineOpenReadOnly(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+	testUtils := newTestUtils()
+	dbcfgs := testUtils.newDBConfigs(db)
+	ctx := context.Background()
+	config := tabletenv.DefaultQsConfig
+	config.TransactionCap = 10
+	te := NewTxEngine(nil, config)
+	te.InitDBConfig(dbcfgs)
+
+	te.OpenReadOnly()
+	if !te.isOpen || !te.readOnly {
+		t.Fatalf("OpenReadOnly: isOpen: %v, readOnly: %v, want true, true", te.isOpen, te.readOnly)
+	}
+	c, err := te.txPool.LocalBegin(ctx, &querypb.ExecuteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Recycle()
+
+	// Open must roll back the read-only transactions.
+	te.Open()
+	if !te.isOpen || te.readOnly {
+		t.Errorf("Open: isOpen: %v, readOnly: %v, want true, false", te.isOpen, te.readOnly)
+	}
+	if got := te.txPool.activePool.Size(); got != 0 {
+		t.Errorf("active transactions after Open: %v, want 0", got)
+	}
+	te.Close(true)
+}
@@ -59,10 +59,17 @@ var (
 	txStats = stats.NewTimings("Transactions", "Transaction stats", "operation")
 
 	txIsolations = map[querypb.ExecuteOptions_TransactionIsolation]string{
-		querypb.ExecuteOptions_REPEATABLE_READ:  "set transaction isolation level REPEATABLE READ",
-		querypb.ExecuteOptions_READ_COMMITTED:   "set transaction isolation level READ COMMITTED",
-		querypb.ExecuteOptions_READ_UNCOMMITTED: "set transaction isolation level READ UNCOMMITTED",
-		querypb.ExecuteOptions_SERIALIZABLE:     "set transaction isolation level SERIALIZABLE",
+		querypb.ExecuteOptions_REPEATABLE_READ:               "set transaction isolation level REPEATABLE READ",
+		querypb.ExecuteOptions_READ_COMMITTED:                "set transaction isolation level READ COMMITTED",
+		querypb.ExecuteOptions_READ_UNCOMMITTED:              "set transaction isolation level READ UNCOMMITTED",
+		querypb.ExecuteOptions_SERIALIZABLE:                  "set transaction isolation level SERIALIZABLE",
+		querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY: "set transaction isolation level REPEATABLE READ",
+	}
+
+	// txBegins maps isolation levels which need a different statement than
+	// "begin" to start the transaction.
+	txBegins = map[querypb.ExecuteOptions_TransactionIsolation]string{
+		querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY: "start transaction with consistent snapshot, read only",
 	}
 )
 
@@ -238,7 +245,11 @@ func (axp *TxPool) Begin(ctx context.Context, options *querypb.ExecuteOptions) (
 		}
 	}
 
-	if _, err := conn.Exec(ctx, "begin", 1, false); err != nil {
+	beginQuery := "begin"
+	if query, ok := txBegins[options.GetTransactionIsolation()]; ok {
+		beginQuery = query
+	}
+	if _, err := conn.Exec(ctx, beginQuery, 1, false); err != nil {
 		return 0, err
 	}
 
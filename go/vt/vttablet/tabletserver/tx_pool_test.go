@@ -263,6 +263,21 @@ func TestTxPoolTransactionIsolation(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	// A consistent snapshot is started with its own statement instead of "begin".
+	db.AddQuery("set transaction isolation level REPEATABLE READ", &sqltypes.Result{})
+	db.AddQuery("start transaction with consistent snapshot, read only", &sqltypes.Result{})
+	beginCount := db.GetQueryCalledNum("begin")
+	_, err = txPool.Begin(ctx, &querypb.ExecuteOptions{TransactionIsolation: querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db.GetQueryCalledNum("start transaction with consistent snapshot, read only"); got != 1 {
+		t.Errorf("consistent snapshot started %v times, want 1", got)
+	}
+	if got := db.GetQueryCalledNum("begin"); got != beginCount {
+		t.Errorf("begin called %v times, want %v", got, beginCount)
+	}
 }
 
 // TestTxPoolBeginWithPoolConnectionError_TransientErrno2006 tests the case
@@ -54,6 +54,7 @@ func (bw *BlockWorker) StatusAsHTML() template.HTML {
 		result += "<b>Success (unblocked)</b></br>\n"
 	}
 
+	result += bw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -69,13 +70,14 @@ func (bw *BlockWorker) StatusAsText() string {
 	case WorkerStateDone:
 		result += "Success (unblocked)\n"
 	}
+	result += bw.formatPanicText()
 	return result
 }
 
 // Run implements the Worker interface.
 func (bw *BlockWorker) Run(ctx context.Context) error {
-	resetVars()
-	err := bw.run(ctx)
+	bw.resetRunVars()
+	err := bw.runRecovered(ctx, bw.run)
 
 	bw.SetState(WorkerStateCleanUp)
 	if err != nil {
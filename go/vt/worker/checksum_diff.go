@@ -0,0 +1,218 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// chunkChecksum is the aggregated checksum of all rows within a chunk.
+type chunkChecksum struct {
+	rowCount string
+	checksum string
+}
+
+// chunkWhereClauses returns the WHERE clauses which limit a query to the
+// rows of "c".
+func chunkWhereClauses(td *tabletmanagerdatapb.TableDefinition, c chunk) []string {
+	var clauses []string
+	if !c.start.IsNull() {
+		var b bytes.Buffer
+		sqlescape.WriteEscapeID(&b, td.PrimaryKeyColumns[0])
+		b.WriteString(">=")
+		c.start.EncodeSQL(&b)
+		clauses = append(clauses, b.String())
+	}
+	if !c.end.IsNull() {
+		var b bytes.Buffer
+		sqlescape.WriteEscapeID(&b, td.PrimaryKeyColumns[0])
+		b.WriteString("<")
+		c.end.EncodeSQL(&b)
+		clauses = append(clauses, b.String())
+	}
+	return clauses
+}
+
+// whereClause joins "clauses" and an optional extra filter "where".
+func whereClause(clauses []string, where string) string {
+	if where != "" {
+		clauses = append(clauses, where)
+	}
+	if len(clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(clauses, " AND ")
+}
+
+// checksumQuery returns a query which computes the number of rows and the
+// checksum of all rows within chunk "c".
+// The checksum is the SUM of a 64-bit hash (the first half of the MD5) of
+// each row. Therefore, it does not depend on the order in which MySQL reads
+// the rows. Unlike an XOR, the SUM does not cancel out pairs of identical
+// rows. This matters for tables without a primary key which may have
+// duplicate rows.
+// Since CONCAT_WS() skips NULL values, the NULL-ness of each column is
+// included in the checksum as well.
+func checksumQuery(td *tabletmanagerdatapb.TableDefinition, c chunk, where string) string {
+	columns := escapeAll(orderedColumns(td))
+	isNulls := make([]string, len(columns))
+	for i, column := range columns {
+		isNulls[i] = "ISNULL(" + column + ")"
+	}
+	return fmt.Sprintf("SELECT COUNT(*), SUM(CAST(CONV(LEFT(MD5(CONCAT_WS('#', %v, CONCAT(%v))), 16), 16, 10) AS UNSIGNED)) FROM %v%v",
+		strings.Join(columns, ", "), strings.Join(isNulls, ", "), sqlescape.EscapeID(td.Name), whereClause(chunkWhereClauses(td, c), where))
+}
+
+// computeChunkChecksum runs the checksum query for chunk "c" on the tablet.
+func computeChunkChecksum(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, c chunk, where string) (*chunkChecksum, error) {
+	sql := checksumQuery(td, c, where)
+	reader, err := NewQueryResultReaderForTablet(ctx, wr.TopoServer(), tabletAlias, sql)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "tablet=%v table=%v chunk=%v: checksum query failed", topoproto.TabletAliasString(tabletAlias), td.Name, c)
+	}
+	defer reader.Close(ctx)
+
+	rowReader := NewRowReader(reader)
+	row, err := rowReader.Next()
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "tablet=%v table=%v chunk=%v: cannot read checksum", topoproto.TabletAliasString(tabletAlias), td.Name, c)
+	}
+	if row == nil || len(row) != 2 {
+		return nil, fmt.Errorf("tablet=%v table=%v chunk=%v: checksum query returned an unexpected result: %v", topoproto.TabletAliasString(tabletAlias), td.Name, c, row)
+	}
+	// Drain the stream to make sure that it did not fail at the end.
+	if _, err := rowReader.Drain(); err != nil {
+		return nil, vterrors.Wrapf(err, "tablet=%v table=%v chunk=%v: cannot read checksum", topoproto.TabletAliasString(tabletAlias), td.Name, c)
+	}
+	return &chunkChecksum{
+		rowCount: row[0].ToString(),
+		checksum: row[1].ToString(),
+	}, nil
+}
+
+// tableScanChunk returns a QueryResultReader which reads all rows of
+// chunk "c", ordered by Primary Key. The returned columns are ordered with
+// the Primary Key columns in front.
+// "comparison" is passed to diffColumns().
+func tableScanChunk(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, c chunk, where string, comparison comparisonOptions) (*QueryResultReader, error) {
+	columns, err := diffColumns(td, comparison)
+	if err != nil {
+		return nil, err
+	}
+	sql := fmt.Sprintf("SELECT %v FROM %v%v", strings.Join(columns, ", "), sqlescape.EscapeID(td.Name), whereClause(chunkWhereClauses(td, c), where))
+	if len(td.PrimaryKeyColumns) > 0 {
+		sql += fmt.Sprintf(" ORDER BY %v", strings.Join(escapeAll(td.PrimaryKeyColumns), ", "))
+	}
+	wr.Logger().Infof("SQL query for %v/%v: %v", topoproto.TabletAliasString(tabletAlias), td.Name, sql)
+	return NewQueryResultReaderForTablet(ctx, wr.TopoServer(), tabletAlias, sql)
+}
+
+// checksumDiffTable compares table "td" between the source and the
+// destination tablet. It splits the table into chunks and compares the
+// checksum of each chunk first. Only chunks with a different checksum are
+// compared row by row.
+// "sourceWhere" and "destinationWhere" are optional filters which are applied
+// to all queries on the respective tablet. "repairer" is optional as well.
+// "comparison" controls the row by row comparison.
+// "chunkCount" and "minRowsPerChunk" control the chunks (see generateChunks()).
+// While the worker "sw" is paused, no further chunks are compared.
+func checksumDiffTable(ctx context.Context, wr *wrangler.Wrangler, sw *StatusWorker, sourceAlias, destinationAlias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, sourceWhere, destinationWhere string, repairer *rowRepairer, results *diffResultsRecorder, comparison comparisonOptions, chunkCount, minRowsPerChunk int) (DiffReport, error) {
+	var report DiffReport
+	report.startingTime = time.Now()
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	sourceTablet, err := wr.TopoServer().GetTablet(shortCtx, sourceAlias)
+	cancel()
+	if err != nil {
+		return report, vterrors.Wrapf(err, "cannot read source tablet %v", topoproto.TabletAliasString(sourceAlias))
+	}
+	chunks, err := generateChunks(ctx, wr, sourceTablet.Tablet, td, chunkCount, minRowsPerChunk)
+	if err != nil {
+		return report, vterrors.Wrapf(err, "failed to split table %v into chunks", td.Name)
+	}
+
+	mismatchedChunks := 0
+	for _, c := range chunks {
+		if err := checkDone(ctx); err != nil {
+			return report, err
+		}
+		if err := sw.waitIfPaused(ctx); err != nil {
+			return report, err
+		}
+
+		sourceChecksum, err := computeChunkChecksum(ctx, wr, sourceAlias, td, c, sourceWhere)
+		if err != nil {
+			return report, err
+		}
+		destinationChecksum, err := computeChunkChecksum(ctx, wr, destinationAlias, td, c, destinationWhere)
+		if err != nil {
+			return report, err
+		}
+		if *sourceChecksum == *destinationChecksum {
+			continue
+		}
+
+		mismatchedChunks++
+		wr.Logger().Infof("table=%v chunk=%v: checksums differ (source: %v rows, checksum %v; destination: %v rows, checksum %v). Comparing all rows.",
+			td.Name, c, sourceChecksum.rowCount, sourceChecksum.checksum, destinationChecksum.rowCount, destinationChecksum.checksum)
+		chunkReport, err := diffChunk(ctx, wr, sourceAlias, destinationAlias, td, c, sourceWhere, destinationWhere, repairer, results, comparison)
+		if err != nil {
+			return report, err
+		}
+		report.merge(chunkReport)
+	}
+	wr.Logger().Infof("table=%v: %v out of %v chunks had different checksums and were compared row by row.", td.Name, mismatchedChunks, len(chunks))
+	report.ComputeQPS()
+	return report, nil
+}
+
+// diffChunk runs a row by row comparison of chunk "c".
+func diffChunk(ctx context.Context, wr *wrangler.Wrangler, sourceAlias, destinationAlias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, c chunk, sourceWhere, destinationWhere string, repairer *rowRepairer, results *diffResultsRecorder, comparison comparisonOptions) (DiffReport, error) {
+	sourceQueryResultReader, err := tableScanChunk(ctx, wr, sourceAlias, td, c, sourceWhere, comparison)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "tableScanChunk(source) failed")
+	}
+	defer sourceQueryResultReader.Close(ctx)
+
+	destinationQueryResultReader, err := tableScanChunk(ctx, wr, destinationAlias, td, c, destinationWhere, comparison)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "tableScanChunk(destination) failed")
+	}
+	defer destinationQueryResultReader.Close(ctx)
+
+	differ, err := NewRowDiffer(sourceQueryResultReader, destinationQueryResultReader, td)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "NewRowDiffer() failed")
+	}
+	differ.repairer = repairer
+	differ.results = results
+	differ.floatEpsilon = comparison.floatEpsilon
+	return differ.Go(wr.Logger())
+}
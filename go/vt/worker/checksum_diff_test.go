@@ -0,0 +1,62 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestChecksumQuery(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"msg", "id"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+
+	testcases := []struct {
+		desc  string
+		chunk chunk
+		where string
+		want  string
+	}{
+		{
+			desc:  "complete chunk",
+			chunk: completeChunk,
+			want:  "SELECT COUNT(*), SUM(CAST(CONV(LEFT(MD5(CONCAT_WS('#', `id`, `msg`, CONCAT(ISNULL(`id`), ISNULL(`msg`)))), 16), 16, 10) AS UNSIGNED)) FROM `t1`",
+		},
+		{
+			desc:  "chunk with start and end",
+			chunk: chunk{sqltypes.NewInt64(11), sqltypes.NewInt64(26), 2, 3},
+			want:  "SELECT COUNT(*), SUM(CAST(CONV(LEFT(MD5(CONCAT_WS('#', `id`, `msg`, CONCAT(ISNULL(`id`), ISNULL(`msg`)))), 16), 16, 10) AS UNSIGNED)) FROM `t1` WHERE `id`>=11 AND `id`<26",
+		},
+		{
+			desc:  "last chunk with key range filter",
+			chunk: chunk{sqltypes.NewInt64(26), sqltypes.NULL, 3, 3},
+			where: "`keyspace_id` < 9223372036854775808",
+			want:  "SELECT COUNT(*), SUM(CAST(CONV(LEFT(MD5(CONCAT_WS('#', `id`, `msg`, CONCAT(ISNULL(`id`), ISNULL(`msg`)))), 16), 16, 10) AS UNSIGNED)) FROM `t1` WHERE `id`>=26 AND `keyspace_id` < 9223372036854775808",
+		},
+	}
+	for _, tc := range testcases {
+		if got := checksumQuery(td, tc.chunk, tc.where); got != tc.want {
+			t.Errorf("%v: checksumQuery() = %v, want = %v", tc.desc, got, tc.want)
+		}
+	}
+}
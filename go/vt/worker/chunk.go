@@ -93,7 +93,7 @@ func generateChunks(ctx context.Context, wr *wrangler.Wrangler, tablet *topodata
 
 	// Get the MIN and MAX of the leading column of the primary key.
 	query := fmt.Sprintf("SELECT MIN(%v), MAX(%v) FROM %v.%v", sqlescape.EscapeID(td.PrimaryKeyColumns[0]), sqlescape.EscapeID(td.PrimaryKeyColumns[0]), sqlescape.EscapeID(topoproto.TabletDbName(tablet)), sqlescape.EscapeID(td.Name))
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	qr, err := wr.TabletManagerClient().ExecuteFetchAsApp(shortCtx, tablet, true, []byte(query), 1)
 	cancel()
 	if err != nil {
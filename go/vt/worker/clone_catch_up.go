@@ -0,0 +1,404 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file implements the --catch_up mode of the SplitClone and
+// VerticalSplitClone commands. It replaces the offline phase:
+//
+// Before the online phase starts, we record the replication position of each
+// source shard. After the online phase, filtered replication starts at these
+// positions and replays all changes which happened during the copy.
+// Because some of the changes were already copied, the binlog player ignores
+// duplicate key errors until it reaches the position of the source masters
+// at the end of the online phase.
+// Replaying a change twice is only safe for row-based binlogs with full row
+// images. Therefore, we verify this on all tablets of the source shards
+// before the online phase starts (see checkCatchUpBinlogFormat()).
+//
+// Finally, we compare the row counts of source and destination at the same
+// position. This requires to take only one RDONLY tablet per source shard
+// out of serving, and only for the duration of the row counts.
+
+// catchUpBinlogFormatQuery reads the binlog settings which are required by
+// --catch_up.
+const catchUpBinlogFormatQuery = "SELECT @@global.binlog_format, @@global.binlog_row_image"
+
+// checkCatchUpBinlogFormat verifies that all tablets of the source shards,
+// which filtered replication may stream from, write row-based binlogs with
+// full row images. Only then, replaying a change which is already included
+// in the copy has no effect: An UPDATE sets all columns to the values after
+// the change, a DELETE of a missing row is a no-op and a duplicate INSERT is
+// skipped by the binlog player. A statement-based event like
+// "UPDATE t SET c = c + 1" would be applied twice instead.
+func (scw *SplitCloneWorker) checkCatchUpBinlogFormat(ctx context.Context) error {
+	for _, si := range scw.sourceShards {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		tablets, err := scw.wr.TopoServer().GetTabletMapForShard(shortCtx, si.Keyspace(), si.ShardName())
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot read the tablets of source shard %v", topoproto.KeyspaceShardString(si.Keyspace(), si.ShardName()))
+		}
+		for _, ti := range tablets {
+			if !topo.IsRunningUpdateStream(ti.Type) {
+				continue
+			}
+			if err := checkRowBasedBinlogs(ctx, scw.wr, ti.Tablet); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRowBasedBinlogs returns an error if "tablet" does not write row-based
+// binlogs with full row images.
+func checkRowBasedBinlogs(ctx context.Context, wr *wrangler.Wrangler, tablet *topodatapb.Tablet) error {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	p3qr, err := wr.TabletManagerClient().ExecuteFetchAsApp(shortCtx, tablet, true /* usePool */, []byte(catchUpBinlogFormatQuery), 1 /* maxRows */)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read the binlog format of tablet %v", alias)
+	}
+	qr := sqltypes.Proto3ToResult(p3qr)
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 2 {
+		return fmt.Errorf("unexpected result while reading the binlog format of tablet %v: %v", alias, qr)
+	}
+	format, rowImage := qr.Rows[0][0].ToString(), qr.Rows[0][1].ToString()
+	if !strings.EqualFold(format, "ROW") || !strings.EqualFold(rowImage, "FULL") {
+		return fmt.Errorf("-catch_up requires binlog_format=ROW and binlog_row_image=FULL on all source tablets, but tablet %v has binlog_format=%v and binlog_row_image=%v. Use the offline clone instead", alias, format, rowImage)
+	}
+	return nil
+}
+
+// recordCatchUpStartPositions records the position of each source shard
+// before the online phase starts. The online phase reads from the healthy
+// RDONLY tablets. Therefore, we use the lowest position of all of them:
+// Replaying changes which are already included in the copy is fine, but
+// missing changes is not.
+func (scw *SplitCloneWorker) recordCatchUpStartPositions(ctx context.Context) error {
+	if _, ok := scw.checkpointer.catchUpStartPositions(); ok {
+		scw.wr.Logger().Infof("Using the catch up start positions of the previous run (see --resume).")
+		return nil
+	}
+	if scw.checkpointer.hasProgress() {
+		return errors.New("cannot resume the online clone with --catch_up because the previous run did not record the start positions. Restart the clone without --resume")
+	}
+
+	positions := make([]string, len(scw.sourceShards))
+	for i, si := range scw.sourceShards {
+		keyspaceAndShard := topoproto.KeyspaceShardString(si.Keyspace(), si.ShardName())
+		tablets := scw.tsc.GetHealthyTabletStats(si.Keyspace(), si.ShardName(), topodatapb.TabletType_RDONLY)
+		if len(tablets) == 0 {
+			return fmt.Errorf("no healthy RDONLY tablet in source shard (%v) available (required to record the catch up start position)", keyspaceAndShard)
+		}
+		var tabletPositions []string
+		for _, ts := range tablets {
+			shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+			status, err := scw.wr.TabletManagerClient().SlaveStatus(shortCtx, ts.Tablet)
+			cancel()
+			if err != nil {
+				return vterrors.Wrapf(err, "SlaveStatus for %v failed", topoproto.TabletAliasString(ts.Tablet.Alias))
+			}
+			tabletPositions = append(tabletPositions, status.Position)
+		}
+		pos, err := minReplicationPosition(tabletPositions)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot determine the catch up start position of source shard %v", keyspaceAndShard)
+		}
+		positions[i] = pos
+		scw.wr.Logger().Infof("Filtered replication from source shard %v will start at %v after the online clone.", keyspaceAndShard, pos)
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	return scw.checkpointer.setCatchUpStartPositions(shortCtx, positions)
+}
+
+// catchUp phase:
+// - record the current position of each source master. The online phase
+//   cannot have copied any newer data.
+// - start filtered replication at the positions which were recorded before
+//   the online phase
+// - wait until filtered replication has reached the source master positions
+
+func (scw *SplitCloneWorker) catchUpFilteredReplication(ctx context.Context) error {
+	scw.setState(WorkerStateCatchUp)
+	start := time.Now()
+	defer func() {
+		scw.setStateDuration(WorkerStateCatchUp, time.Now().Sub(start))
+	}()
+
+	startPositions, ok := scw.checkpointer.catchUpStartPositions()
+	if !ok {
+		return errors.New("no catch up start positions were recorded before the online clone")
+	}
+	if len(startPositions) != len(scw.sourceShards) {
+		return fmt.Errorf("the checkpoint has %v catch up start positions but there are %v source shards", len(startPositions), len(scw.sourceShards))
+	}
+
+	catchUpPositions := make([]string, len(scw.sourceShards))
+	for i, si := range scw.sourceShards {
+		master, err := scw.shardMaster(ctx, si.Keyspace(), si.ShardName())
+		if err != nil {
+			return err
+		}
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		catchUpPositions[i], err = scw.wr.TabletManagerClient().MasterPosition(shortCtx, master)
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "MasterPosition for %v failed", topoproto.TabletAliasString(master.Alias))
+		}
+	}
+	scw.setCatchUpPositions(catchUpPositions)
+
+	uids, err := scw.startFilteredReplication(ctx, startPositions, catchUpPositions)
+	if err != nil {
+		return err
+	}
+	scw.catchUpUIDs = uids
+
+	for i, si := range scw.destinationShards {
+		master, err := scw.shardMaster(ctx, si.Keyspace(), si.ShardName())
+		if err != nil {
+			return err
+		}
+		alias := topoproto.TabletAliasString(master.Alias)
+		for j, src := range scw.sourceShards {
+			scw.wr.Logger().Infof("Waiting for filtered replication from %v on %v to catch up to %v", topoproto.KeyspaceShardString(src.Keyspace(), src.ShardName()), alias, catchUpPositions[j])
+			waitCtx, cancel := context.WithTimeout(ctx, *retryDuration)
+			err := scw.wr.TabletManagerClient().VReplicationWaitForPos(waitCtx, master, int(uids[i][j]), catchUpPositions[j])
+			cancel()
+			if err != nil {
+				return vterrors.Wrapf(err, "VReplicationWaitForPos for %v until %v failed", alias, catchUpPositions[j])
+			}
+		}
+	}
+	return nil
+}
+
+// checkCatchUpRowCounts phase:
+// - stop filtered replication on all destination masters, get their
+//   current positions
+// - for each source shard, stop replication on a RDONLY tablet at a minimum
+//   of the highest filtered replication position
+// - let filtered replication run until the position of the stopped tablets
+// - compare the total row count of each table on the stopped source tablets
+//   and the destination masters
+// - restart filtered replication
+// The source RDONLY tablets get restarted during the clean up.
+
+func (scw *SplitCloneWorker) checkCatchUpRowCounts(ctx context.Context) error {
+	scw.setState(WorkerStateSyncReplication)
+
+	masters := make([]*topodatapb.Tablet, len(scw.destinationShards))
+	// positions has the filtered replication positions per source shard.
+	positions := make([][]string, len(scw.sourceShards))
+	for i, si := range scw.destinationShards {
+		master, err := scw.shardMaster(ctx, si.Keyspace(), si.ShardName())
+		if err != nil {
+			return err
+		}
+		masters[i] = master
+		alias := topoproto.TabletAliasString(master.Alias)
+		for j := range scw.sourceShards {
+			uid := scw.catchUpUIDs[i][j]
+			scw.wr.Logger().Infof("Stopping master binlog replication on %v", alias)
+			shortCtx, cancel := context.WithTimeout(ctx, stopBlpTimeoutFor(ctx))
+			_, err := scw.wr.TabletManagerClient().VReplicationExec(shortCtx, master, binlogplayer.StopVReplication(uid, "for the SplitClone catch up check"))
+			cancel()
+			if err != nil {
+				return vterrors.Wrapf(err, "VReplicationExec(stop) for %v failed", alias)
+			}
+			wrangler.RecordVReplicationAction(scw.cleaner, master, binlogplayer.StartVReplication(uid))
+
+			shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+			p3qr, err := scw.wr.TabletManagerClient().VReplicationExec(shortCtx, master, binlogplayer.ReadVReplicationPos(uid))
+			cancel()
+			if err != nil {
+				return vterrors.Wrapf(err, "VReplicationExec(read pos) for %v failed", alias)
+			}
+			qr := sqltypes.Proto3ToResult(p3qr)
+			if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 {
+				return fmt.Errorf("Unexpected result while reading position: %v", qr)
+			}
+			positions[j] = append(positions[j], qr.Rows[0][0].ToString())
+		}
+	}
+
+	sourceAliases := make([]*topodatapb.TabletAlias, len(scw.sourceShards))
+	stopPositions := make([]string, len(scw.sourceShards))
+	for j, si := range scw.sourceShards {
+		vreplicationPos, err := maxReplicationPosition(positions[j])
+		if err != nil {
+			return err
+		}
+		sourceAliases[j], err = FindWorkerTablet(ctx, scw.wr, scw.cleaner, scw.tsc, scw.cell, si.Keyspace(), si.ShardName(), scw.minHealthyRdonlyTablets, topodatapb.TabletType_RDONLY)
+		if err != nil {
+			return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", scw.cell, si.Keyspace(), si.ShardName())
+		}
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		sourceTablet, err := scw.wr.TopoServer().GetTablet(shortCtx, sourceAliases[j])
+		cancel()
+		if err != nil {
+			return err
+		}
+		scw.wr.Logger().Infof("Stopping slave %v at a minimum of %v", topoproto.TabletAliasString(sourceAliases[j]), vreplicationPos)
+		shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
+		stopPositions[j], err = scw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, sourceTablet.Tablet, vreplicationPos, stopSlaveMinimumTimeoutFor(ctx))
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot stop slave %v at right binlog position %v", topoproto.TabletAliasString(sourceAliases[j]), vreplicationPos)
+		}
+		// change the cleaner actions from ChangeSlaveType(rdonly)
+		// to StartSlave() + ChangeSlaveType(spare)
+		wrangler.RecordStartSlaveAction(scw.cleaner, sourceTablet.Tablet)
+	}
+
+	for i, master := range masters {
+		alias := topoproto.TabletAliasString(master.Alias)
+		for j := range scw.sourceShards {
+			uid := scw.catchUpUIDs[i][j]
+			scw.wr.Logger().Infof("Restarting master %v until it catches up to %v", alias, stopPositions[j])
+			shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+			_, err := scw.wr.TabletManagerClient().VReplicationExec(shortCtx, master, binlogplayer.StartVReplicationUntil(uid, stopPositions[j]))
+			if err != nil {
+				cancel()
+				return vterrors.Wrapf(err, "VReplication(start until) for %v until %v failed", alias, stopPositions[j])
+			}
+			err = scw.wr.TabletManagerClient().VReplicationWaitForPos(shortCtx, master, int(uid), stopPositions[j])
+			cancel()
+			if err != nil {
+				return vterrors.Wrapf(err, "VReplicationWaitForPos for %v until %v failed", alias, stopPositions[j])
+			}
+		}
+	}
+
+	if err := scw.compareRowCounts(ctx, sourceAliases, masters); err != nil {
+		return err
+	}
+
+	for i, master := range masters {
+		alias := topoproto.TabletAliasString(master.Alias)
+		for j := range scw.sourceShards {
+			scw.wr.Logger().Infof("Restarting filtered replication on master %v", alias)
+			shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+			_, err := scw.wr.TabletManagerClient().VReplicationExec(shortCtx, master, binlogplayer.StartVReplication(scw.catchUpUIDs[i][j]))
+			cancel()
+			if err != nil {
+				return vterrors.Wrapf(err, "VReplicationExec(start) failed for %v", alias)
+			}
+		}
+	}
+	return nil
+}
+
+// compareRowCounts compares the total row count of each table on the source
+// tablets with the total row count on the destination tablets. Both sides
+// must be at the same replication position.
+func (scw *SplitCloneWorker) compareRowCounts(ctx context.Context, sourceAliases []*topodatapb.TabletAlias, destinationMasters []*topodatapb.Tablet) error {
+	scw.setState(WorkerStateDiff)
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	sourceTablet, err := scw.wr.TopoServer().GetTablet(shortCtx, sourceAliases[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+	sourceSchemaDefinition, err := scw.getSourceSchema(ctx, sourceTablet.Tablet)
+	if err != nil {
+		return err
+	}
+
+	for _, td := range sourceSchemaDefinition.TableDefinitions {
+		var sourceCount, destinationCount uint64
+		for _, alias := range sourceAliases {
+			count, err := countRows(ctx, scw.wr, alias, td, "")
+			if err != nil {
+				return err
+			}
+			sourceCount += count
+		}
+		for _, master := range destinationMasters {
+			count, err := countRows(ctx, scw.wr, master.Alias, td, "")
+			if err != nil {
+				return err
+			}
+			destinationCount += count
+		}
+		if sourceCount != destinationCount {
+			return fmt.Errorf("table %v has a row count mismatch after the catch up: source has %v rows, destination has %v rows. Run the clone again with the offline phase to reconcile the difference", td.Name, sourceCount, destinationCount)
+		}
+		scw.wr.Logger().Infof("table=%v: row counts match after the catch up (%v rows).", td.Name, sourceCount)
+	}
+	return nil
+}
+
+// shardMaster returns the current master tablet of the shard.
+func (scw *SplitCloneWorker) shardMaster(ctx context.Context, keyspace, shard string) (*topodatapb.Tablet, error) {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	si, err := scw.wr.TopoServer().GetShard(shortCtx, keyspace, shard)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read shard %v/%v", keyspace, shard)
+	}
+	if !si.HasMaster() {
+		return nil, fmt.Errorf("shard %v/%v has no master", keyspace, shard)
+	}
+	ti, err := scw.wr.TopoServer().GetTablet(shortCtx, si.MasterAlias)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot get Tablet record for master %v", topoproto.TabletAliasString(si.MasterAlias))
+	}
+	return ti.Tablet, nil
+}
+
+func (scw *SplitCloneWorker) setCatchUpPositions(positions []string) {
+	scw.catchUpPositionsMu.Lock()
+	defer scw.catchUpPositionsMu.Unlock()
+
+	scw.catchUpPositions = positions
+}
+
+// formatCatchUpPositions returns a space separated list of the source
+// positions which filtered replication has to reach during the catch up.
+func (scw *SplitCloneWorker) formatCatchUpPositions() string {
+	scw.catchUpPositionsMu.Lock()
+	defer scw.catchUpPositionsMu.Unlock()
+
+	if len(scw.catchUpPositions) == 0 {
+		return "positions not determined yet"
+	}
+	return strings.Join(scw.catchUpPositions, " ")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/faketmclient"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// binlogFormatFakeTMC returns the given binlog settings for every tablet.
+type binlogFormatFakeTMC struct {
+	tmclient.TabletManagerClient
+
+	format, rowImage string
+}
+
+// ExecuteFetchAsApp is part of the tmclient.TabletManagerClient interface.
+func (f *binlogFormatFakeTMC) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, query []byte, maxRows int) (*querypb.QueryResult, error) {
+	if string(query) != catchUpBinlogFormatQuery {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	return sqltypes.ResultToProto3(&sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "@@global.binlog_format", Type: sqltypes.VarChar},
+			{Name: "@@global.binlog_row_image", Type: sqltypes.VarChar},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarChar(f.format),
+			sqltypes.NewVarChar(f.rowImage),
+		}},
+	}), nil
+}
+
+func TestCheckRowBasedBinlogs(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	tablet := &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}}
+
+	testcases := []struct {
+		format, rowImage string
+		wantErr          string
+	}{
+		{"ROW", "FULL", ""},
+		{"row", "full", ""},
+		{"STATEMENT", "FULL", "tablet cell1-0000000001 has binlog_format=STATEMENT and binlog_row_image=FULL"},
+		{"MIXED", "FULL", "tablet cell1-0000000001 has binlog_format=MIXED"},
+		{"ROW", "MINIMAL", "binlog_row_image=MINIMAL"},
+	}
+	for _, tc := range testcases {
+		tmc := &binlogFormatFakeTMC{
+			TabletManagerClient: faketmclient.NewFakeTabletManagerClient(),
+			format:              tc.format,
+			rowImage:            tc.rowImage,
+		}
+		wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmc)
+		err := checkRowBasedBinlogs(ctx, wr, tablet)
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%v/%v: checkRowBasedBinlogs() failed: %v", tc.format, tc.rowImage, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%v/%v: checkRowBasedBinlogs() = %v, want error containing: %v", tc.format, tc.rowImage, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// This file implements the checkpointing of the online phase of the
+// SplitClone and VerticalSplitClone commands.
+//
+// The progress is stored as JSON in the global topology. A later run with
+// --resume reuses the chunk boundaries of each table and skips all chunks
+// which were already copied.
+//
+// Only the online phase is checkpointed: The offline phase must read all
+// chunks at the same (stopped) replication position of the source tablets
+// and therefore always starts from scratch.
+//
+// A chunk is marked as done only after the destination masters committed all
+// of its writes. Skipping it in a later run is safe even without an offline
+// phase: Changes to the source after the copy are applied by the offline
+// phase or, with --catch_up, by filtered replication.
+//
+// With --catch_up, there is no offline phase. Instead, the checkpoint also
+// stores the source positions at which the online phase started. Filtered
+// replication must start from there, even if the online phase was resumed.
+
+const (
+	cloneCheckpointsPath      = "vtworker_checkpoints"
+	cloneCheckpointFile       = "Checkpoint"
+	cloneCheckpointTablesPath = "tables"
+)
+
+// cloneCheckpoint is the main checkpoint file of a clone run.
+type cloneCheckpoint struct {
+	// ChunkCount and MinRowsPerChunk are the values of the run which created
+	// the checkpoint. A resumed run must use the same values.
+	ChunkCount      int
+	MinRowsPerChunk int
+	// OnlineDone is true when the online phase finished for all tables.
+	OnlineDone bool
+	// CatchUpStartPositions has the replication position of each source shard
+	// before the online phase started. It is only set with --catch_up.
+	CatchUpStartPositions []string
+}
+
+// tableCheckpoint has the chunk boundaries and the progress of a table.
+// It is stored in a separate file per table to keep each file small.
+type tableCheckpoint struct {
+	Chunks []*chunkCheckpoint
+}
+
+// chunkCheckpoint is the serializable form of a chunk.
+type chunkCheckpoint struct {
+	StartType querypb.Type
+	Start     string
+	EndType   querypb.Type
+	End       string
+	Done      bool
+}
+
+func newChunkCheckpoint(c chunk) *chunkCheckpoint {
+	return &chunkCheckpoint{
+		StartType: c.start.Type(),
+		Start:     c.start.ToString(),
+		EndType:   c.end.Type(),
+		End:       c.end.ToString(),
+	}
+}
+
+func (cc *chunkCheckpoint) toChunk(number, total int) chunk {
+	return chunk{
+		start:  sqltypes.MakeTrusted(cc.StartType, []byte(cc.Start)),
+		end:    sqltypes.MakeTrusted(cc.EndType, []byte(cc.End)),
+		number: number,
+		total:  total,
+	}
+}
+
+// cloneCheckpointer reads and writes the checkpoint of one clone run.
+// It is safe to use it from multiple Go routines.
+type cloneCheckpointer struct {
+	conn   topo.Conn
+	dir    string
+	logger logutil.Logger
+
+	// mu guards all fields in the group below.
+	mu         sync.Mutex
+	checkpoint *cloneCheckpoint
+	tables     map[string]*tableCheckpoint
+}
+
+// newCloneCheckpointer returns a checkpointer for the clone of
+// "keyspace/shard". If "resume" is true, a previously saved checkpoint
+// will be loaded. Otherwise, any existing checkpoint will be discarded.
+func newCloneCheckpointer(ctx context.Context, ts *topo.Server, logger logutil.Logger, name, keyspace, shard string, chunkCount, minRowsPerChunk int, resume bool) (*cloneCheckpointer, error) {
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return nil, err
+	}
+	cc := &cloneCheckpointer{
+		conn:   conn,
+		dir:    path.Join(cloneCheckpointsPath, keyspace, shard, name),
+		logger: logger,
+		checkpoint: &cloneCheckpoint{
+			ChunkCount:      chunkCount,
+			MinRowsPerChunk: minRowsPerChunk,
+		},
+		tables: make(map[string]*tableCheckpoint),
+	}
+
+	if resume {
+		found, err := cc.load(ctx)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot load checkpoint from %v", cc.dir)
+		}
+		if found {
+			if cc.checkpoint.ChunkCount != chunkCount || cc.checkpoint.MinRowsPerChunk != minRowsPerChunk {
+				return nil, fmt.Errorf("cannot resume from checkpoint %v: it was created with --chunk_count=%v --min_rows_per_chunk=%v but this run uses --chunk_count=%v --min_rows_per_chunk=%v",
+					cc.dir, cc.checkpoint.ChunkCount, cc.checkpoint.MinRowsPerChunk, chunkCount, minRowsPerChunk)
+			}
+			logger.Infof("Resuming from checkpoint %v (%v tables with progress, online phase done: %v)", cc.dir, len(cc.tables), cc.checkpoint.OnlineDone)
+			return cc, nil
+		}
+		logger.Infof("No checkpoint found at %v. Starting from scratch.", cc.dir)
+	} else {
+		if err := cc.delete(ctx); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot delete previous checkpoint at %v", cc.dir)
+		}
+	}
+
+	if err := cc.saveCheckpoint(ctx); err != nil {
+		return nil, err
+	}
+	return cc, nil
+}
+
+// load reads the checkpoint. It returns false if there is none.
+func (cc *cloneCheckpointer) load(ctx context.Context) (bool, error) {
+	data, _, err := cc.conn.Get(ctx, path.Join(cc.dir, cloneCheckpointFile))
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(data, cc.checkpoint); err != nil {
+		return false, vterrors.Wrap(err, "cannot parse checkpoint")
+	}
+
+	entries, err := cc.conn.ListDir(ctx, path.Join(cc.dir, cloneCheckpointTablesPath), false /* full */)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return true, nil
+		}
+		return false, err
+	}
+	for _, e := range entries {
+		data, _, err := cc.conn.Get(ctx, path.Join(cc.dir, cloneCheckpointTablesPath, e.Name))
+		if err != nil {
+			return false, err
+		}
+		tc := &tableCheckpoint{}
+		if err := json.Unmarshal(data, tc); err != nil {
+			return false, vterrors.Wrapf(err, "cannot parse checkpoint of table %v", e.Name)
+		}
+		cc.tables[e.Name] = tc
+	}
+	return true, nil
+}
+
+// delete removes all files of the checkpoint.
+func (cc *cloneCheckpointer) delete(ctx context.Context) error {
+	tablesDir := path.Join(cc.dir, cloneCheckpointTablesPath)
+	entries, err := cc.conn.ListDir(ctx, tablesDir, false /* full */)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return err
+	}
+	for _, e := range entries {
+		if err := cc.conn.Delete(ctx, path.Join(tablesDir, e.Name), nil); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return err
+		}
+	}
+	if err := cc.conn.Delete(ctx, path.Join(cc.dir, cloneCheckpointFile), nil); err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return err
+	}
+	return nil
+}
+
+func (cc *cloneCheckpointer) saveCheckpoint(ctx context.Context) error {
+	data, err := json.MarshalIndent(cc.checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = cc.conn.Update(ctx, path.Join(cc.dir, cloneCheckpointFile), data, nil)
+	return err
+}
+
+// saveTableLocked writes the checkpoint of a table. mu must be held.
+func (cc *cloneCheckpointer) saveTableLocked(ctx context.Context, table string) error {
+	data, err := json.MarshalIndent(cc.tables[table], "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = cc.conn.Update(ctx, path.Join(cc.dir, cloneCheckpointTablesPath, table), data, nil)
+	return err
+}
+
+// chunks returns the chunks of a previous run for "table".
+// It returns false if there are none.
+func (cc *cloneCheckpointer) chunks(table string) ([]chunk, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	tc, ok := cc.tables[table]
+	if !ok || len(tc.Chunks) == 0 {
+		return nil, false
+	}
+	chunks := make([]chunk, len(tc.Chunks))
+	for i, c := range tc.Chunks {
+		chunks[i] = c.toChunk(i+1, len(tc.Chunks))
+	}
+	return chunks, true
+}
+
+// setChunks records the chunk boundaries of "table".
+func (cc *cloneCheckpointer) setChunks(ctx context.Context, table string, chunks []chunk) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	tc := &tableCheckpoint{
+		Chunks: make([]*chunkCheckpoint, len(chunks)),
+	}
+	for i, c := range chunks {
+		tc.Chunks[i] = newChunkCheckpoint(c)
+	}
+	cc.tables[table] = tc
+	return cc.saveTableLocked(ctx, table)
+}
+
+// isChunkDone returns true if the chunk was already copied.
+func (cc *cloneCheckpointer) isChunkDone(table string, c chunk) bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	tc, ok := cc.tables[table]
+	if !ok || c.number < 1 || c.number > len(tc.Chunks) {
+		return false
+	}
+	return tc.Chunks[c.number-1].Done
+}
+
+// markChunkDone records that the chunk was copied.
+func (cc *cloneCheckpointer) markChunkDone(ctx context.Context, table string, c chunk) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	tc, ok := cc.tables[table]
+	if !ok || c.number < 1 || c.number > len(tc.Chunks) {
+		return fmt.Errorf("no checkpoint for chunk %v of table %v", c, table)
+	}
+	tc.Chunks[c.number-1].Done = true
+	return cc.saveTableLocked(ctx, table)
+}
+
+// onlineDone returns true if the online phase was already completed.
+func (cc *cloneCheckpointer) onlineDone() bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.checkpoint.OnlineDone
+}
+
+// hasProgress returns true if a previous run already copied some data.
+func (cc *cloneCheckpointer) hasProgress() bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.checkpoint.OnlineDone || len(cc.tables) > 0
+}
+
+// catchUpStartPositions returns the source positions which were recorded
+// before the online phase. It returns false if there are none.
+func (cc *cloneCheckpointer) catchUpStartPositions() ([]string, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if len(cc.checkpoint.CatchUpStartPositions) == 0 {
+		return nil, false
+	}
+	return cc.checkpoint.CatchUpStartPositions, true
+}
+
+// setCatchUpStartPositions records the source positions before the online
+// phase starts.
+func (cc *cloneCheckpointer) setCatchUpStartPositions(ctx context.Context, positions []string) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.checkpoint.CatchUpStartPositions = positions
+	return cc.saveCheckpoint(ctx)
+}
+
+// markOnlineDone records that the online phase was completed.
+func (cc *cloneCheckpointer) markOnlineDone(ctx context.Context) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.checkpoint.OnlineDone = true
+	return cc.saveCheckpoint(ctx)
+}
+
+// chunkWrites tracks the write queries of one chunk until the destination
+// executed them. The online clone uses it to checkpoint a chunk only after
+// all of its writes were committed (see --resume).
+type chunkWrites struct {
+	mu      sync.Mutex
+	pending int
+	waiting bool
+	// drained is closed when pending drops to 0 while wait() is blocked.
+	drained chan struct{}
+}
+
+func newChunkWrites() *chunkWrites {
+	return &chunkWrites{
+		drained: make(chan struct{}),
+	}
+}
+
+// add must be called before a query of the chunk is sent to a queue.
+func (cw *chunkWrites) add() {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.pending++
+}
+
+// done must be called after a query of the chunk was executed.
+func (cw *chunkWrites) done() {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.pending--
+	if cw.pending == 0 && cw.waiting {
+		close(cw.drained)
+	}
+}
+
+// wait blocks until all queries of the chunk were executed or "ctx" is done.
+// It must be called after the last add().
+func (cw *chunkWrites) wait(ctx context.Context) error {
+	cw.mu.Lock()
+	if cw.pending == 0 {
+		cw.mu.Unlock()
+		return nil
+	}
+	cw.waiting = true
+	cw.mu.Unlock()
+
+	select {
+	case <-cw.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestCloneCheckpointResume(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	logger := logutil.NewMemoryLogger()
+
+	cc, err := newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 3, 10, false /* resume */)
+	if err != nil {
+		t.Fatalf("newCloneCheckpointer failed: %v", err)
+	}
+	chunks := []chunk{
+		{sqltypes.NULL, sqltypes.NewInt64(100), 1, 3},
+		{sqltypes.NewInt64(100), sqltypes.NewInt64(200), 2, 3},
+		{sqltypes.NewInt64(200), sqltypes.NULL, 3, 3},
+	}
+	if err := cc.setChunks(ctx, "table1", chunks); err != nil {
+		t.Fatalf("setChunks failed: %v", err)
+	}
+	if err := cc.markChunkDone(ctx, "table1", chunks[1]); err != nil {
+		t.Fatalf("markChunkDone failed: %v", err)
+	}
+
+	// A resumed run must see the same chunks and progress.
+	resumed, err := newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 3, 10, true /* resume */)
+	if err != nil {
+		t.Fatalf("newCloneCheckpointer with resume failed: %v", err)
+	}
+	got, ok := resumed.chunks("table1")
+	if !ok {
+		t.Fatalf("chunks() found no chunks for table1")
+	}
+	if !reflect.DeepEqual(got, chunks) {
+		t.Fatalf("chunks() = %v, want = %v", got, chunks)
+	}
+	for i, c := range got {
+		if got, want := resumed.isChunkDone("table1", c), i == 1; got != want {
+			t.Errorf("isChunkDone(%v) = %v, want = %v", c, got, want)
+		}
+	}
+	if resumed.onlineDone() {
+		t.Errorf("onlineDone() = true, want = false")
+	}
+
+	// Different chunk parameters must be rejected.
+	if _, err := newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 4, 10, true /* resume */); err == nil || !strings.Contains(err.Error(), "cannot resume from checkpoint") {
+		t.Fatalf("newCloneCheckpointer with different chunk_count should have failed: %v", err)
+	}
+
+	// A run without resume discards the checkpoint.
+	fresh, err := newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 3, 10, false /* resume */)
+	if err != nil {
+		t.Fatalf("newCloneCheckpointer failed: %v", err)
+	}
+	if _, ok := fresh.chunks("table1"); ok {
+		t.Fatalf("chunks() returned chunks of the discarded checkpoint")
+	}
+	resumed, err = newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 3, 10, true /* resume */)
+	if err != nil {
+		t.Fatalf("newCloneCheckpointer with resume failed: %v", err)
+	}
+	if _, ok := resumed.chunks("table1"); ok {
+		t.Fatalf("chunks() returned chunks of the discarded checkpoint")
+	}
+}
+
+func TestCloneCheckpointCatchUpStartPositions(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	logger := logutil.NewMemoryLogger()
+
+	cc, err := newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 3, 10, false /* resume */)
+	if err != nil {
+		t.Fatalf("newCloneCheckpointer failed: %v", err)
+	}
+	if _, ok := cc.catchUpStartPositions(); ok {
+		t.Fatalf("catchUpStartPositions() found positions in a new checkpoint")
+	}
+	if cc.hasProgress() {
+		t.Fatalf("hasProgress() = true for a new checkpoint, want = false")
+	}
+	positions := []string{"MariaDB/0-1-5", "MariaDB/0-2-7"}
+	if err := cc.setCatchUpStartPositions(ctx, positions); err != nil {
+		t.Fatalf("setCatchUpStartPositions failed: %v", err)
+	}
+	if err := cc.markOnlineDone(ctx); err != nil {
+		t.Fatalf("markOnlineDone failed: %v", err)
+	}
+
+	// A resumed run must start filtered replication at the same positions.
+	resumed, err := newCloneCheckpointer(ctx, ts, logger, "SplitClone", "ks", "0", 3, 10, true /* resume */)
+	if err != nil {
+		t.Fatalf("newCloneCheckpointer with resume failed: %v", err)
+	}
+	got, ok := resumed.catchUpStartPositions()
+	if !ok {
+		t.Fatalf("catchUpStartPositions() found no positions")
+	}
+	if !reflect.DeepEqual(got, positions) {
+		t.Errorf("catchUpStartPositions() = %v, want = %v", got, positions)
+	}
+	if !resumed.hasProgress() {
+		t.Errorf("hasProgress() = false, want = true")
+	}
+}
+
+func TestChunkWrites(t *testing.T) {
+	ctx := context.Background()
+
+	// A chunk without writes is done right away.
+	if err := newChunkWrites().wait(ctx); err != nil {
+		t.Fatalf("wait() without writes failed: %v", err)
+	}
+
+	cw := newChunkWrites()
+	cw.add()
+	cw.add()
+	cw.done()
+
+	// wait() must block while a write is pending.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := cw.wait(timeoutCtx); err == nil {
+		t.Fatal("wait() with a pending write must fail when the context is done")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cw.done()
+	}()
+	if err := cw.wait(ctx); err != nil {
+		t.Fatalf("wait() failed: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// tableCollationRegexp finds the default collation in the table options of
+// a CREATE TABLE statement e.g. "ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin".
+var tableCollationRegexp = regexp.MustCompile(`(?i)\bCOLLATE\s*=?\s*(\w+)`)
+
+// charsetRegexp matches the valid values of --normalize_charset.
+var charsetRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// pkCollators returns a collator for each primary key column of "td" which
+// MySQL compares case-insensitively. "fields" must be ordered like
+// orderedColumns(). The entries of all other columns are nil and their values
+// are compared byte by byte.
+// Without the collators, the diff would report rows which MySQL considers
+// equal and sorts next to each other (e.g. "a" and "A") as missing and
+// extraneous.
+func pkCollators(td *tabletmanagerdatapb.TableDefinition, fields []*querypb.Field) ([]*collate.Collator, error) {
+	var collators []*collate.Collator
+	var caseInsensitive map[string]bool
+	var collator *collate.Collator
+	for i, column := range td.PrimaryKeyColumns {
+		if i >= len(fields) || !sqltypes.IsText(fields[i].Type) {
+			continue
+		}
+		if caseInsensitive == nil {
+			var err error
+			if caseInsensitive, err = caseInsensitiveColumns(td); err != nil {
+				return nil, err
+			}
+		}
+		if !caseInsensitive[strings.ToLower(column)] {
+			continue
+		}
+		if collators == nil {
+			collators = make([]*collate.Collator, len(td.PrimaryKeyColumns))
+			// Like the MySQL *_general_ci and *_unicode_ci collations, the
+			// loose collator ignores case and accents.
+			collator = collate.New(language.Und, collate.Loose)
+		}
+		collators[i] = collator
+	}
+	return collators, nil
+}
+
+// caseInsensitiveColumns returns the lower-cased names of the text columns of
+// "td" whose declared collation is case-insensitive. A column without an
+// explicit collation uses the default collation of the table. If there is none
+// either, the default collation of the character set applies. In MySQL, it is
+// case-insensitive for all character sets except "binary".
+func caseInsensitiveColumns(td *tabletmanagerdatapb.TableDefinition) (map[string]bool, error) {
+	stmt, err := sqlparser.Parse(td.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the schema of table %v to find the collation of its columns: %v", td.Name, err)
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil {
+		return nil, fmt.Errorf("cannot find the column collations of table %v in its schema: %v", td.Name, td.Schema)
+	}
+
+	tableCollation := ""
+	if m := tableCollationRegexp.FindStringSubmatch(ddl.TableSpec.Options); m != nil {
+		tableCollation = m[1]
+	}
+	result := make(map[string]bool)
+	for _, col := range ddl.TableSpec.Columns {
+		if !isTextType(col.Type.SQLType()) {
+			continue
+		}
+		collation := col.Type.Collate
+		if collation == "" && col.Type.Charset == "" {
+			collation = tableCollation
+		}
+		if collation == "" {
+			result[col.Name.Lowered()] = !strings.EqualFold(col.Type.Charset, "binary")
+			continue
+		}
+		result[col.Name.Lowered()] = strings.HasSuffix(strings.ToLower(collation), "_ci")
+	}
+	return result, nil
+}
+
+// compareCollated compares two values of a column with a case-insensitive
+// collation. Like MySQL, it ignores trailing spaces.
+func compareCollated(collator *collate.Collator, left, right []byte) int {
+	return collator.Compare(bytes.TrimRight(left, " "), bytes.TrimRight(right, " "))
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestCaseInsensitiveColumns(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name: "t1",
+		Schema: "CREATE TABLE `t1` (\n" +
+			"  `id` bigint(20) NOT NULL,\n" +
+			"  `name` varchar(64) NOT NULL,\n" +
+			"  `code` char(8) COLLATE utf8_general_ci,\n" +
+			"  `msg` varchar(64) CHARACTER SET latin1,\n" +
+			"  `body` text,\n" +
+			"  `data` varbinary(64),\n" +
+			"  PRIMARY KEY (`name`)\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_bin",
+	}
+	got, err := caseInsensitiveColumns(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{
+		// The table default collation is case-sensitive.
+		"name": false,
+		"body": false,
+		// An explicit collation overrides the table default.
+		"code": true,
+		// The default collation of a character set is case-insensitive.
+		"msg": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("caseInsensitiveColumns() = %v, want = %v", got, want)
+	}
+
+	td.Schema = "CREATE TABLE `t1` (\n  `name` varchar(64) NOT NULL,\n  PRIMARY KEY (`name`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"
+	got, err = caseInsensitiveColumns(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]bool{"name": true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("caseInsensitiveColumns() = %v, want = %v", got, want)
+	}
+}
+
+func TestCompareRowsCollated(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Schema:            "CREATE TABLE `t1` (\n  `id` bigint(20) NOT NULL,\n  `name` varchar(64) NOT NULL,\n  PRIMARY KEY (`id`,`name`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8",
+		Columns:           []string{"id", "name"},
+		PrimaryKeyColumns: []string{"id", "name"},
+	}
+	fields := []*querypb.Field{
+		{Name: "id", Type: sqltypes.Int64},
+		{Name: "name", Type: sqltypes.VarChar},
+	}
+	collators, err := pkCollators(td, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collators) != 2 || collators[0] != nil || collators[1] == nil {
+		t.Fatalf("pkCollators() = %v, want a collator for the second column only", collators)
+	}
+
+	testcases := []struct {
+		left, right string
+		want        int
+	}{
+		{"a", "A", 0},
+		{"a ", "A", 0},
+		{"a", "B", -1},
+		{"b", "A", 1},
+	}
+	for _, tc := range testcases {
+		left := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar(tc.left)}
+		right := []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarChar(tc.right)}
+		got, err := compareRowsCollated(fields, collators, 2, left, right)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("compareRowsCollated(%q, %q) = %v, want = %v", tc.left, tc.right, got, tc.want)
+		}
+	}
+
+	// Binary columns are never compared by a collator.
+	fields[1].Type = sqltypes.VarBinary
+	if collators, err := pkCollators(td, fields); err != nil || collators != nil {
+		t.Errorf("pkCollators() for a binary column = %v, %v, want = nil, nil", collators, err)
+	}
+}
+
+func TestDiffColumnsNormalizeCharset(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Schema:            "CREATE TABLE `t1` (\n  `name` varchar(64) NOT NULL,\n  `msg` varchar(64),\n  `count` int(11),\n  `body` text,\n  PRIMARY KEY (`name`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1",
+		Columns:           []string{"name", "msg", "count", "body"},
+		PrimaryKeyColumns: []string{"name"},
+	}
+	got, err := diffColumns(td, comparisonOptions{hashColumnsLargerThan: 1024, normalizeCharset: "utf8mb4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"`name`",
+		"CONVERT(`msg` USING utf8mb4) AS `msg`",
+		"`count`",
+		"IF(LENGTH(CONVERT(`body` USING utf8mb4)) > 1024, CONCAT('md5:', MD5(CONVERT(`body` USING utf8mb4))), CONVERT(`body` USING utf8mb4)) AS `body`",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffColumns() = %v, want = %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+// hashedValuePrefix is prepended to the MD5 hash which replaces a large
+// value. See diffColumns().
+const hashedValuePrefix = "md5:"
+
+// diffColumns returns the SELECT expressions for the columns of "td" in the
+// order of orderedColumns(). The values are selected as they are unless
+// "comparison" requires to normalize or to hash them:
+// - If "normalizeCharset" is set, the values of CHAR, VARCHAR and TEXT
+// columns are converted to this character set.
+// - If "normalizeTimestamps" is true, the values of TIMESTAMP columns are
+// returned as UNIX timestamps which do not depend on the time_zone setting.
+// - If "hashColumnsLargerThan" is > 0, the values of BLOB and TEXT columns
+// which are larger than this many bytes are replaced by their MD5 hash.
+// The hash is computed by MySQL. Therefore, the large values are never
+// streamed to vtworker.
+// Primary key columns are never changed because the rows are ordered by them.
+func diffColumns(td *tabletmanagerdatapb.TableDefinition, comparison comparisonOptions) ([]string, error) {
+	columns := orderedColumns(td)
+	escaped := escapeAll(columns)
+	if comparison.hashColumnsLargerThan <= 0 && comparison.normalizeCharset == "" && !comparison.normalizeTimestamps {
+		return escaped, nil
+	}
+
+	types, err := columnTypes(td)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		if i < len(td.PrimaryKeyColumns) {
+			result[i] = escaped[i]
+			continue
+		}
+		expr := escaped[i]
+		typ := types[strings.ToLower(column)]
+		switch {
+		case comparison.normalizeCharset != "" && isTextType(typ):
+			expr = fmt.Sprintf("CONVERT(%v USING %v)", expr, comparison.normalizeCharset)
+		case comparison.normalizeTimestamps && typ == sqltypes.Timestamp:
+			expr = fmt.Sprintf("UNIX_TIMESTAMP(%v)", expr)
+		}
+		if comparison.hashColumnsLargerThan > 0 && (typ == sqltypes.Blob || typ == sqltypes.Text) {
+			expr = fmt.Sprintf("IF(LENGTH(%[1]v) > %[2]v, CONCAT('%[3]v', MD5(%[1]v)), %[1]v)", expr, comparison.hashColumnsLargerThan, hashedValuePrefix)
+		}
+		if expr != escaped[i] {
+			// Keep the original column name in the result.
+			expr += " AS " + escaped[i]
+		}
+		result[i] = expr
+	}
+	return result, nil
+}
+
+// columnTypes returns the types of the columns of "td" by their lower-cased
+// name. The types are taken from its CREATE TABLE statement.
+func columnTypes(td *tabletmanagerdatapb.TableDefinition) (map[string]querypb.Type, error) {
+	stmt, err := sqlparser.Parse(td.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the schema of table %v to find the types of its columns: %v", td.Name, err)
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil {
+		return nil, fmt.Errorf("cannot find the column types of table %v in its schema: %v", td.Name, td.Schema)
+	}
+	result := make(map[string]querypb.Type)
+	for _, col := range ddl.TableSpec.Columns {
+		result[col.Name.Lowered()] = col.Type.SQLType()
+	}
+	return result, nil
+}
+
+// isTextType returns true for the types of CHAR, VARCHAR and TEXT columns.
+func isTextType(typ querypb.Type) bool {
+	switch typ {
+	case sqltypes.Char, sqltypes.VarChar, sqltypes.Text:
+		return true
+	}
+	return false
+}
+
+// hasHashMismatch returns true if the rows have a different value in a
+// column where at least one side was replaced by its hash. "first" is the
+// index of the first different column as returned by RowsEqual().
+func hasHashMismatch(left, right []sqltypes.Value, first int) bool {
+	for i := first; i < len(left); i++ {
+		if bytes.Equal(left[i].Raw(), right[i].Raw()) {
+			continue
+		}
+		if isHashedValue(left[i]) || isHashedValue(right[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHashedValue returns true if "v" looks like a value which was replaced
+// by its hash in diffColumns().
+func isHashedValue(v sqltypes.Value) bool {
+	raw := v.Raw()
+	// An MD5 hash has 32 hex digits.
+	return len(raw) == len(hashedValuePrefix)+32 && bytes.HasPrefix(raw, []byte(hashedValuePrefix))
+}
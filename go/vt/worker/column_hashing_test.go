@@ -0,0 +1,99 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestDiffColumns(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Schema:            "CREATE TABLE `t1` (\n  `msg` varchar(64),\n  `id` bigint(20) NOT NULL,\n  `Data` mediumblob,\n  `body` text,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB",
+		Columns:           []string{"msg", "id", "Data", "body"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+
+	got, err := diffColumns(td, comparisonOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"`id`", "`msg`", "`Data`", "`body`"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffColumns(0) = %v, want = %v", got, want)
+	}
+
+	got, err = diffColumns(td, comparisonOptions{hashColumnsLargerThan: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"`id`",
+		"`msg`",
+		"IF(LENGTH(`Data`) > 1024, CONCAT('md5:', MD5(`Data`)), `Data`) AS `Data`",
+		"IF(LENGTH(`body`) > 1024, CONCAT('md5:', MD5(`body`)), `body`) AS `body`",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffColumns(1024) = %v, want = %v", got, want)
+	}
+
+	td.Schema = "invalid"
+	if _, err := diffColumns(td, comparisonOptions{hashColumnsLargerThan: 1024}); err == nil {
+		t.Error("diffColumns() must fail for a schema which cannot be parsed")
+	}
+}
+
+func TestHasHashMismatch(t *testing.T) {
+	hash1 := sqltypes.NewVarBinary("md5:0cc175b9c0f1b6a831c399e269772661")
+	hash2 := sqltypes.NewVarBinary("md5:92eb5ffee6ae2fec3ad71c777531578f")
+	testcases := []struct {
+		desc        string
+		left, right []sqltypes.Value
+		want        bool
+	}{{
+		desc:  "different hashes",
+		left:  []sqltypes.Value{sqltypes.NewInt64(1), hash1},
+		right: []sqltypes.Value{sqltypes.NewInt64(1), hash2},
+		want:  true,
+	}, {
+		desc:  "hash and small value",
+		left:  []sqltypes.Value{sqltypes.NewInt64(1), hash1},
+		right: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("a")},
+		want:  true,
+	}, {
+		desc:  "same hash, different other column",
+		left:  []sqltypes.Value{sqltypes.NewInt64(1), hash1, sqltypes.NewVarBinary("a")},
+		right: []sqltypes.Value{sqltypes.NewInt64(1), hash1, sqltypes.NewVarBinary("b")},
+		want:  false,
+	}, {
+		desc:  "no hashes",
+		left:  []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("md5:a")},
+		right: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("md5:b")},
+		want:  false,
+	}}
+	for _, tc := range testcases {
+		first := RowsEqual(tc.left, tc.right)
+		if got := hasHashMismatch(tc.left, tc.right, first); got != tc.want {
+			t.Errorf("%v: hasHashMismatch() = %v, want = %v", tc.desc, got, tc.want)
+		}
+	}
+}
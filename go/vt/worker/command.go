@@ -80,7 +80,10 @@ func AddCommand(groupName string, c Command) {
 	panic(fmt.Errorf("Trying to add to missing group %v", groupName))
 }
 
-func commandWorker(wi *Instance, wr *wrangler.Wrangler, args []string, cell string, runFromCli bool) (Worker, error) {
+// commandWorker creates the worker for the command in "args". It also returns
+// the remote action timeouts of the command. The worker must be run with a
+// context which carries them (see newRemoteActionTimeoutsContext()).
+func commandWorker(wi *Instance, wr *wrangler.Wrangler, args []string, cell string, runFromCli bool) (Worker, *remoteActionTimeouts, error) {
 	action := args[0]
 
 	actionLowerCase := strings.ToLower(action)
@@ -101,7 +104,11 @@ func commandWorker(wi *Instance, wr *wrangler.Wrangler, args []string, cell stri
 					wr.Logger().Printf("%s\n\n", cmd.Help)
 					subFlags.PrintDefaults()
 				}
-				return cmd.Method(wi, wr, subFlags, args[1:])
+				// All commands can override the timeouts of the remote actions.
+				timeouts := &remoteActionTimeouts{}
+				timeouts.addFlags(subFlags)
+				wrk, err := cmd.Method(wi, wr, subFlags, args[1:])
+				return wrk, timeouts, err
 			}
 		}
 	}
@@ -110,7 +117,7 @@ func commandWorker(wi *Instance, wr *wrangler.Wrangler, args []string, cell stri
 	} else {
 		PrintAllCommands(wr.Logger())
 	}
-	return nil, fmt.Errorf("unknown command: %v", action)
+	return nil, nil, fmt.Errorf("unknown command: %v", action)
 }
 
 // RunCommand executes the vtworker command specified by "args". Use WaitForCommand() to block on the returned done channel.
@@ -131,11 +138,11 @@ func (wi *Instance) RunCommand(ctx context.Context, args []string, wr *wrangler.
 	if wr == nil {
 		wr = wi.wr
 	}
-	wrk, err := commandWorker(wi, wr, args, wi.cell, runFromCli)
+	wrk, timeouts, err := commandWorker(wi, wr, args, wi.cell, runFromCli)
 	if err != nil {
 		return nil, nil, err
 	}
-	done, err := wi.setAndStartWorker(ctx, wrk, wr)
+	done, err := wi.setAndStartWorker(newRemoteActionTimeoutsContext(ctx, timeouts), args[0], wrk, wr)
 	if err != nil {
 		return nil, nil, vterrors.Wrap(err, "cannot set worker")
 	}
@@ -0,0 +1,75 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// comparisonOptions control how the diff workers select and compare the
+// values of a row. The zero value compares all values exactly as they are
+// stored.
+type comparisonOptions struct {
+	// hashColumnsLargerThan, normalizeCharset and normalizeTimestamps change
+	// the selected values. See diffColumns().
+	hashColumnsLargerThan int
+	normalizeCharset      string
+	normalizeTimestamps   bool
+	// ignoreColumns has the columns which are not compared by table name.
+	// See parseIgnoreColumns() and diffTableDefinition().
+	ignoreColumns map[string][]string
+	// floatEpsilon is the maximum absolute difference of two FLOAT or DOUBLE
+	// values which are still considered equal. See withinFloatEpsilon().
+	floatEpsilon float64
+}
+
+// withinFloatEpsilon returns true if the rows differ only in FLOAT and DOUBLE
+// values and no difference is larger than "epsilon". "first" is the index of
+// the first different column as returned by RowsEqual().
+// This way, rows whose floating point values went through a different
+// rounding on the source and the destination are not reported as different.
+func withinFloatEpsilon(fields []*querypb.Field, left, right []sqltypes.Value, first int, epsilon float64) bool {
+	for i := first; i < len(left); i++ {
+		if bytes.Equal(left[i].Raw(), right[i].Raw()) {
+			continue
+		}
+		if i >= len(fields) || (fields[i].Type != sqltypes.Float32 && fields[i].Type != sqltypes.Float64) {
+			return false
+		}
+		if left[i].IsNull() || right[i].IsNull() {
+			return false
+		}
+		l, err := strconv.ParseFloat(left[i].ToString(), 64)
+		if err != nil {
+			return false
+		}
+		r, err := strconv.ParseFloat(right[i].ToString(), 64)
+		if err != nil {
+			return false
+		}
+		if math.Abs(l-r) > epsilon {
+			return false
+		}
+	}
+	return true
+}
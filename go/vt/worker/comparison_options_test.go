@@ -0,0 +1,90 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestDiffColumnsNormalizeTimestamps(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Schema:            "CREATE TABLE `t1` (\n  `created` timestamp NOT NULL,\n  `updated` timestamp NULL,\n  `day` datetime,\n  PRIMARY KEY (`created`)\n) ENGINE=InnoDB",
+		Columns:           []string{"created", "updated", "day"},
+		PrimaryKeyColumns: []string{"created"},
+	}
+	got, err := diffColumns(td, comparisonOptions{normalizeTimestamps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"`created`", "UNIX_TIMESTAMP(`updated`) AS `updated`", "`day`"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffColumns() = %v, want = %v", got, want)
+	}
+}
+
+func TestWithinFloatEpsilon(t *testing.T) {
+	fields := []*querypb.Field{
+		{Name: "id", Type: sqltypes.Int64},
+		{Name: "price", Type: sqltypes.Float64},
+		{Name: "msg", Type: sqltypes.VarChar},
+	}
+	row := func(price, msg string) []sqltypes.Value {
+		return []sqltypes.Value{
+			sqltypes.NewInt64(1),
+			sqltypes.MakeTrusted(sqltypes.Float64, []byte(price)),
+			sqltypes.NewVarChar(msg),
+		}
+	}
+	testcases := []struct {
+		desc        string
+		left, right []sqltypes.Value
+		want        bool
+	}{{
+		desc:  "small float difference",
+		left:  row("0.30000000000000004", "a"),
+		right: row("0.3", "a"),
+		want:  true,
+	}, {
+		desc:  "large float difference",
+		left:  row("0.31", "a"),
+		right: row("0.3", "a"),
+		want:  false,
+	}, {
+		desc:  "small float difference and different text",
+		left:  row("0.30000000000000004", "a"),
+		right: row("0.3", "b"),
+		want:  false,
+	}, {
+		desc:  "NULL",
+		left:  []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NULL, sqltypes.NewVarChar("a")},
+		right: row("0", "a"),
+		want:  false,
+	}}
+	for _, tc := range testcases {
+		first := RowsEqual(tc.left, tc.right)
+		if got := withinFloatEpsilon(fields, tc.left, tc.right, first, 0.001); got != tc.want {
+			t.Errorf("%v: withinFloatEpsilon() = %v, want = %v", tc.desc, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// CompletionEventsPath is the directory in the global topology which has
+// one file for each finished worker if --completion_events_to_topo is set.
+const CompletionEventsPath = "vtworker_completions"
+
+var (
+	completionWebhookURL     = flag.String("completion_webhook_url", "", "If set, vtworker sends an HTTP POST request with a JSON description of the result (command, final state, error, duration, status summary) to this URL whenever a worker has finished.")
+	completionWebhookTimeout = flag.Duration("completion_webhook_timeout", 30*time.Second, "Timeout for the HTTP POST request to --completion_webhook_url.")
+	completionEventsToTopo   = flag.Bool("completion_events_to_topo", false, "If true, vtworker writes a JSON description of the result of every finished worker to the global topology under "+CompletionEventsPath+"/.")
+)
+
+// completionEvent describes a finished worker. It is sent to the webhook and
+// written to the topology as JSON.
+type completionEvent struct {
+	// ID is unique for each finished worker of this vtworker process. It is
+	// also the name of the file in the topology.
+	ID string
+	// JobID is set if the worker ran as job (see jobManager).
+	JobID int `json:",omitempty"`
+	// Command is the name of the vtworker command e.g. "SplitDiff".
+	Command string
+	// Owner is the URL of the vtworker process.
+	Owner string
+	// State is the final state of the worker ("done" or "error").
+	State string
+	// Error is set if the worker failed or was canceled.
+	Error string `json:",omitempty"`
+	// StartTime and EndTime are set when the worker started and
+	// finished.
+	StartTime       time.Time
+	EndTime         time.Time
+	DurationSeconds float64
+	// Summary is the final text status of the worker. For the diff workers
+	// it includes the per-table result.
+	Summary string
+}
+
+func newCompletionEvent(command string, jobID int, wrk Worker, startTime, endTime time.Time, err error) *completionEvent {
+	ev := &completionEvent{
+		ID:              fmt.Sprintf("%v_%v", command, endTime.UnixNano()),
+		JobID:           jobID,
+		Command:         command,
+		State:           string(wrk.State()),
+		StartTime:       startTime,
+		EndTime:         endTime,
+		DurationSeconds: endTime.Sub(startTime).Seconds(),
+		Summary:         wrk.StatusAsText(),
+	}
+	if jobID != 0 {
+		ev.ID = fmt.Sprintf("%v_job%v_%v", command, jobID, endTime.UnixNano())
+	}
+	if servenv.ListeningURL.Host != "" {
+		ev.Owner = servenv.ListeningURL.String()
+	}
+	if err != nil {
+		ev.Error = err.Error()
+		// A panic or a cancellation before the worker started leaves the
+		// worker in a non-final state.
+		ev.State = string(WorkerStateError)
+	}
+	return ev
+}
+
+// notifyCompletion publishes the result of a finished worker to the
+// --completion_webhook_url and the topology. This way, automation can
+// chain commands (e.g. SplitDiff and MigrateServedTypes) without polling
+// the vtworker status.
+// Failures are only logged because the result of the worker must not
+// depend on them.
+func notifyCompletion(ts *topo.Server, ev *completionEvent) {
+	if *completionWebhookURL == "" && !*completionEventsToTopo {
+		return
+	}
+
+	data, jsonErr := json.MarshalIndent(ev, "", "  ")
+	if jsonErr != nil {
+		log.Errorf("cannot marshal the completion event %v: %v", ev.ID, jsonErr)
+		return
+	}
+
+	if *completionWebhookURL != "" {
+		if err := postCompletionEvent(*completionWebhookURL, data); err != nil {
+			log.Warningf("cannot send the completion event %v to %v: %v", ev.ID, *completionWebhookURL, err)
+		}
+	}
+	if *completionEventsToTopo && ts != nil {
+		if err := writeCompletionEvent(ts, ev.ID, data); err != nil {
+			log.Warningf("cannot write the completion event %v to the topology: %v", ev.ID, err)
+		}
+	}
+}
+
+func postCompletionEvent(url string, data []byte) error {
+	client := &http.Client{Timeout: *completionWebhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected HTTP status: %v", resp.Status)
+	}
+	return nil
+}
+
+func writeCompletionEvent(ts *topo.Server, id string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), *remoteActionsTimeout)
+	defer cancel()
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Update(ctx, path.Join(CompletionEventsPath, id), data, nil)
+	return err
+}
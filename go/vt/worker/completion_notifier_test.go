@@ -0,0 +1,113 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestNotifyCompletion(t *testing.T) {
+	events := make(chan *completionEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ev := &completionEvent{}
+		if err := json.NewDecoder(r.Body).Decode(ev); err != nil {
+			t.Errorf("invalid completion event: %v", err)
+		}
+		events <- ev
+	}))
+	defer server.Close()
+
+	defer func(url string, toTopo bool) {
+		*completionWebhookURL = url
+		*completionEventsToTopo = toTopo
+	}(*completionWebhookURL, *completionEventsToTopo)
+	*completionWebhookURL = server.URL
+	*completionEventsToTopo = true
+
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wi := NewInstance(ts, "cell1", time.Second)
+	wrk, done, err := wi.RunCommand(ctx, []string{"Ping", "pong"}, nil /* wr */, false /* runFromCli */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wi.WaitForCommand(wrk, done); err != nil {
+		t.Fatal(err)
+	}
+
+	// The webhook is called before the command returns.
+	var ev *completionEvent
+	select {
+	case ev = <-events:
+	default:
+		t.Fatal("the webhook was not called")
+	}
+	if ev.Command != "Ping" || ev.State != string(WorkerStateDone) || ev.Error != "" {
+		t.Errorf("wrong completion event: %+v", ev)
+	}
+	if !strings.Contains(ev.Summary, "Logged message: 'pong'") {
+		t.Errorf("the summary must have the final status of the worker: %v", ev.Summary)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := conn.Get(ctx, path.Join(CompletionEventsPath, ev.ID))
+	if err != nil {
+		t.Fatalf("the completion event was not written to the topology: %v", err)
+	}
+	topoEv := &completionEvent{}
+	if err := json.Unmarshal(data, topoEv); err != nil {
+		t.Fatal(err)
+	}
+	if topoEv.ID != ev.ID || topoEv.State != ev.State {
+		t.Errorf("wrong completion event in the topology: got = %+v, want = %+v", topoEv, ev)
+	}
+}
+
+func TestNewCompletionEventError(t *testing.T) {
+	wrk, err := NewPingWorker(nil /* wr */, "pong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1000, 0)
+	ev := newCompletionEvent("Ping", 3, wrk, start, start.Add(90*time.Second), context.Canceled)
+	if got, want := ev.State, string(WorkerStateError); got != want {
+		t.Errorf("State = %v, want = %v", got, want)
+	}
+	if got, want := ev.Error, context.Canceled.Error(); got != want {
+		t.Errorf("Error = %v, want = %v", got, want)
+	}
+	if got, want := ev.DurationSeconds, 90.0; got != want {
+		t.Errorf("DurationSeconds = %v, want = %v", got, want)
+	}
+	if !strings.HasPrefix(ev.ID, "Ping_job3_") {
+		t.Errorf("ID = %v, want prefix Ping_job3_", ev.ID)
+	}
+}
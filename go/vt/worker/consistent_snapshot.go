@@ -0,0 +1,303 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/grpcclient"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// snapshotScanPageSize is the number of rows which are read by one query
+// within a consistent snapshot transaction. Unlike a streaming query, the
+// query must stay below the tablet's max result size.
+const snapshotScanPageSize = 1000
+
+// snapshotTransactionOptions are used to begin the transactions of a
+// consistentSnapshot. The DBA workload exempts them from the transaction
+// timeout of the tablet. CONSISTENT_SNAPSHOT_READ_ONLY creates the snapshot
+// when the transaction begins. It is the only isolation level which a
+// non-master tablet allows, and only with -enable_consistent_snapshot_read_only.
+var snapshotTransactionOptions = &querypb.ExecuteOptions{
+	Workload:             querypb.ExecuteOptions_DBA,
+	TransactionIsolation: querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY,
+}
+
+// consistentSnapshot is a set of transactions on a tablet which all read the
+// data as of the same replication position.
+// All transactions must be opened while replication on the tablet is stopped.
+// Afterwards, replication can be restarted and the diff reads from the
+// transactions instead of a frozen tablet.
+// There is one transaction per table which is diffed in parallel because a
+// transaction cannot run more than one query at a time.
+type consistentSnapshot struct {
+	alias  *topodatapb.TabletAlias
+	conn   queryservice.QueryService
+	target *querypb.Target
+	// transactionIDs has all transactions.
+	transactionIDs []int64
+	// idle has the transactions which are not used by a scan.
+	idle chan int64
+}
+
+// openConsistentSnapshot opens "count" transactions on the tablet "alias".
+// Replication on the tablet must be stopped during the call.
+// The transactions are held until close() and take "count" slots of the
+// transaction pool of the tablet (-queryserver-config-transaction-cap).
+func openConsistentSnapshot(ctx context.Context, wr *wrangler.Wrangler, alias *topodatapb.TabletAlias, count int) (*consistentSnapshot, error) {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	ti, err := wr.TopoServer().GetTablet(shortCtx, alias)
+	cancel()
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read tablet %v", topoproto.TabletAliasString(alias))
+	}
+
+	conn, err := tabletconn.GetDialer()(ti.Tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return nil, err
+	}
+	cs := &consistentSnapshot{
+		alias: alias,
+		conn:  conn,
+		target: &querypb.Target{
+			Keyspace:   ti.Tablet.Keyspace,
+			Shard:      ti.Tablet.Shard,
+			TabletType: ti.Tablet.Type,
+		},
+		idle: make(chan int64, count),
+	}
+	for i := 0; i < count; i++ {
+		if err := cs.begin(ctx); err != nil {
+			cs.close(ctx)
+			return nil, vterrors.Wrapf(err, "cannot open consistent snapshot on tablet %v", topoproto.TabletAliasString(alias))
+		}
+	}
+	return cs, nil
+}
+
+// begin opens one transaction which establishes its snapshot right away.
+func (cs *consistentSnapshot) begin(ctx context.Context) error {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	transactionID, err := cs.conn.Begin(shortCtx, cs.target, snapshotTransactionOptions)
+	if err != nil {
+		return err
+	}
+	cs.transactionIDs = append(cs.transactionIDs, transactionID)
+	cs.idle <- transactionID
+	return nil
+}
+
+// acquire returns an idle transaction. It blocks until one is available.
+func (cs *consistentSnapshot) acquire() int64 {
+	return <-cs.idle
+}
+
+// release returns a transaction which was returned by acquire().
+func (cs *consistentSnapshot) release(transactionID int64) {
+	cs.idle <- transactionID
+}
+
+// close rolls back all transactions and closes the connection to the tablet.
+// It is a no-op for a nil snapshot.
+func (cs *consistentSnapshot) close(ctx context.Context) error {
+	if cs == nil {
+		return nil
+	}
+	rec := &concurrency.AllErrorRecorder{}
+	for _, transactionID := range cs.transactionIDs {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		rec.RecordError(cs.conn.Rollback(shortCtx, cs.target, transactionID))
+		cancel()
+	}
+	rec.RecordError(cs.conn.Close(ctx))
+	return rec.Error()
+}
+
+// tableScan returns a ResultReader which reads all rows of "td" from an idle
+// transaction, ordered by primary key. The returned columns are ordered with
+// the primary key columns in front. "where" is an optional filter.
+// If "keyspaceSchema" is set, the rows are filtered by "keyRange" within
+// vtworker instead (v3 mode).
+// "comparison" is passed to diffColumns().
+// The reader must be closed to return the transaction to the snapshot.
+func (cs *consistentSnapshot) tableScan(ctx context.Context, td *tabletmanagerdatapb.TableDefinition, where string, keyRange *topodatapb.KeyRange, keyspaceSchema *vindexes.KeyspaceSchema, comparison comparisonOptions) (*snapshotResultReader, error) {
+	if len(td.PrimaryKeyColumns) == 0 {
+		return nil, fmt.Errorf("table %v has no primary key which is required for a diff with a consistent snapshot", td.Name)
+	}
+	columns, err := diffColumns(td, comparison)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionID := cs.acquire()
+	scanner := &snapshotScanner{
+		ctx:           ctx,
+		snapshot:      cs,
+		transactionID: transactionID,
+		td:            td,
+		columns:       columns,
+		where:         where,
+	}
+	// Read the first page to get the fields.
+	first, err := scanner.Recv()
+	if err != nil {
+		cs.release(transactionID)
+		return nil, vterrors.Wrapf(err, "cannot read table %v from consistent snapshot on tablet %v", td.Name, topoproto.TabletAliasString(cs.alias))
+	}
+	scanner.pending = first
+
+	var output sqltypes.ResultStream = scanner
+	if keyspaceSchema != nil {
+		keyResolver, err := newV3ResolverFromColumnList(keyspaceSchema, td.Name, orderedColumns(td))
+		if err != nil {
+			cs.release(transactionID)
+			return nil, vterrors.Wrapf(err, "cannot resolve v3 sharding keys for table %v", td.Name)
+		}
+		output = &v3KeyRangeFilter{
+			input:    scanner,
+			resolver: keyResolver.(*v3Resolver),
+			keyRange: keyRange,
+		}
+	}
+	return &snapshotResultReader{
+		output:        output,
+		fields:        first.Fields,
+		snapshot:      cs,
+		transactionID: transactionID,
+	}, nil
+}
+
+// snapshotResultReader implements the ResultReader interface for a scan
+// within a consistentSnapshot.
+type snapshotResultReader struct {
+	output        sqltypes.ResultStream
+	fields        []*querypb.Field
+	snapshot      *consistentSnapshot
+	transactionID int64
+}
+
+// Next is part of the ResultReader interface.
+func (r *snapshotResultReader) Next() (*sqltypes.Result, error) {
+	return r.output.Recv()
+}
+
+// Fields is part of the ResultReader interface.
+func (r *snapshotResultReader) Fields() []*querypb.Field {
+	return r.fields
+}
+
+// Close returns the transaction to the snapshot. The transaction stays open.
+func (r *snapshotResultReader) Close(ctx context.Context) error {
+	r.snapshot.release(r.transactionID)
+	return nil
+}
+
+// snapshotScanner reads a table page by page from a transaction.
+// It implements sqltypes.ResultStream.
+type snapshotScanner struct {
+	ctx           context.Context
+	snapshot      *consistentSnapshot
+	transactionID int64
+	td            *tabletmanagerdatapb.TableDefinition
+	columns       []string
+	where         string
+
+	// pending is the first page which was read to get the fields.
+	pending *sqltypes.Result
+	// lastPK has the primary key values of the last row read so far.
+	lastPK []sqltypes.Value
+	done   bool
+}
+
+// Recv is part of the sqltypes.ResultStream interface.
+func (s *snapshotScanner) Recv() (*sqltypes.Result, error) {
+	if s.pending != nil {
+		r := s.pending
+		s.pending = nil
+		return r, nil
+	}
+	if s.done {
+		return nil, io.EOF
+	}
+
+	sql := snapshotScanQuery(s.td, s.columns, s.where, s.lastPK)
+	r, err := s.snapshot.conn.Execute(s.ctx, s.snapshot.target, sql, nil, s.transactionID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanThrottlerForTablet(s.snapshot.alias).throttle(s.ctx, r); err != nil {
+		return nil, err
+	}
+	if len(r.Rows) < snapshotScanPageSize {
+		s.done = true
+	}
+	if len(r.Rows) > 0 {
+		lastRow := r.Rows[len(r.Rows)-1]
+		s.lastPK = lastRow[:len(s.td.PrimaryKeyColumns)]
+	}
+	return r, nil
+}
+
+// snapshotScanQuery returns the query for the page of rows after "lastPK".
+// If "lastPK" is nil, it returns the query for the first page.
+// "columns" are the SELECT expressions as returned by diffColumns().
+func snapshotScanQuery(td *tabletmanagerdatapb.TableDefinition, columns []string, where string, lastPK []sqltypes.Value) string {
+	var conditions []string
+	if lastPK != nil {
+		b := &bytes.Buffer{}
+		b.WriteString("(")
+		b.WriteString(strings.Join(escapeAll(td.PrimaryKeyColumns), ", "))
+		b.WriteString(")>(")
+		for i, v := range lastPK {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			v.EncodeSQL(b)
+		}
+		b.WriteString(")")
+		conditions = append(conditions, b.String())
+	}
+	if where != "" {
+		conditions = append(conditions, where)
+	}
+
+	sql := fmt.Sprintf("SELECT %v FROM %v", strings.Join(columns, ", "), sqlescape.EscapeID(td.Name))
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sql += fmt.Sprintf(" ORDER BY %v LIMIT %v", strings.Join(escapeAll(td.PrimaryKeyColumns), ", "), snapshotScanPageSize)
+	return sql
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/wrangler/testlib"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// snapshotTabletServer is a local QueryService implementation which allows
+// transactions on a non-master tablet only if they are read-only with a
+// consistent snapshot. This is the same check as in the vttablet
+// TabletServer.
+type snapshotTabletServer struct {
+	*fakes.StreamHealthQueryService
+	tabletType topodatapb.TabletType
+
+	mu           sync.Mutex
+	lastID       int64
+	transactions map[int64]bool
+}
+
+func (sq *snapshotTabletServer) Begin(ctx context.Context, target *querypb.Target, options *querypb.ExecuteOptions) (int64, error) {
+	if sq.tabletType != topodatapb.TabletType_MASTER && options.GetTransactionIsolation() != querypb.ExecuteOptions_CONSISTENT_SNAPSHOT_READ_ONLY {
+		return 0, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "transactional statement disallowed on non-master tablet: %v", sq.tabletType)
+	}
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	sq.lastID++
+	sq.transactions[sq.lastID] = true
+	return sq.lastID, nil
+}
+
+func (sq *snapshotTabletServer) Execute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID int64, options *querypb.ExecuteOptions) (*sqltypes.Result, error) {
+	sq.mu.Lock()
+	open := sq.transactions[transactionID]
+	sq.mu.Unlock()
+	if !open {
+		return nil, fmt.Errorf("transaction %v not found", transactionID)
+	}
+
+	// Return 1500 rows in total to test the paging.
+	start := 0
+	if strings.Contains(sql, "WHERE (`id`)>(999)") {
+		start = 1000
+	} else if strings.Contains(sql, "WHERE") {
+		return nil, fmt.Errorf("unexpected query: %v", sql)
+	}
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "id", Type: sqltypes.Int64},
+			{Name: "msg", Type: sqltypes.VarChar},
+		},
+	}
+	for i := start; i < start+snapshotScanPageSize && i < 1500; i++ {
+		result.Rows = append(result.Rows, []sqltypes.Value{
+			sqltypes.NewInt64(int64(i)),
+			sqltypes.NewVarBinary(fmt.Sprintf("Text for %v", i)),
+		})
+	}
+	return result, nil
+}
+
+func (sq *snapshotTabletServer) Rollback(ctx context.Context, target *querypb.Target, transactionID int64) error {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	if !sq.transactions[transactionID] {
+		return fmt.Errorf("transaction %v not found", transactionID)
+	}
+	delete(sq.transactions, transactionID)
+	return nil
+}
+
+// TestConsistentSnapshotOnDrainedTablet opens a consistent snapshot on a
+// DRAINED tablet which does not allow regular transactions.
+func TestConsistentSnapshotOnDrainedTablet(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	ctx := context.Background()
+	wi := NewInstance(ts, "cell1", time.Second)
+
+	rdonly := testlib.NewFakeTablet(t, wi.wr, "cell1", 1,
+		topodatapb.TabletType_DRAINED, nil, testlib.TabletKeyspaceShard(t, "ks", "-80"))
+	qs := &snapshotTabletServer{
+		StreamHealthQueryService: fakes.NewStreamHealthQueryService(rdonly.Target()),
+		tabletType:               topodatapb.TabletType_DRAINED,
+		transactions:             make(map[int64]bool),
+	}
+	grpcqueryservice.Register(rdonly.RPCServer, qs)
+	rdonly.StartActionLoop(t, wi.wr)
+	defer rdonly.StopActionLoop(t)
+
+	cs, err := openConsistentSnapshot(ctx, wi.wr, rdonly.Tablet.Alias, 2)
+	if err != nil {
+		t.Fatalf("openConsistentSnapshot() failed: %v", err)
+	}
+
+	// A regular transaction is rejected by the tablet.
+	if _, err := cs.conn.Begin(ctx, cs.target, &querypb.ExecuteOptions{TransactionIsolation: querypb.ExecuteOptions_REPEATABLE_READ}); err == nil || !strings.Contains(err.Error(), "disallowed on non-master tablet") {
+		t.Errorf("Begin(REPEATABLE_READ) on DRAINED tablet: %v, want error", err)
+	}
+
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "table1",
+		Columns:           []string{"id", "msg"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+	reader, err := cs.tableScan(ctx, td, "" /* where */, nil /* keyRange */, nil /* keyspaceSchema */, comparisonOptions{})
+	if err != nil {
+		t.Fatalf("tableScan() failed: %v", err)
+	}
+	rows := 0
+	for {
+		r, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		rows += len(r.Rows)
+	}
+	reader.Close(ctx)
+	if rows != 1500 {
+		t.Errorf("tableScan() returned %v rows, want 1500", rows)
+	}
+
+	if err := cs.close(ctx); err != nil {
+		t.Errorf("close() failed: %v", err)
+	}
+	if len(qs.transactions) != 0 {
+		t.Errorf("close() did not roll back all transactions: %v", qs.transactions)
+	}
+}
+
+func TestSnapshotScanQuery(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"msg", "id", "name"},
+		PrimaryKeyColumns: []string{"id", "name"},
+	}
+
+	testcases := []struct {
+		desc   string
+		where  string
+		lastPK []sqltypes.Value
+		want   string
+	}{
+		{
+			desc: "first page",
+			want: "SELECT `id`, `name`, `msg` FROM `t1` ORDER BY `id`, `name` LIMIT 1000",
+		},
+		{
+			desc:   "next page",
+			lastPK: []sqltypes.Value{sqltypes.NewInt64(11), sqltypes.NewVarBinary("a")},
+			want:   "SELECT `id`, `name`, `msg` FROM `t1` WHERE (`id`, `name`)>(11, 'a') ORDER BY `id`, `name` LIMIT 1000",
+		},
+		{
+			desc:   "next page with key range filter",
+			where:  "`keyspace_id` < 9223372036854775808",
+			lastPK: []sqltypes.Value{sqltypes.NewInt64(11), sqltypes.NewVarBinary("a")},
+			want:   "SELECT `id`, `name`, `msg` FROM `t1` WHERE (`id`, `name`)>(11, 'a') AND `keyspace_id` < 9223372036854775808 ORDER BY `id`, `name` LIMIT 1000",
+		},
+	}
+	for _, tc := range testcases {
+		if got := snapshotScanQuery(td, escapeAll(orderedColumns(td)), tc.where, tc.lastPK); got != tc.want {
+			t.Errorf("%v: snapshotScanQuery() = %v, want = %v", tc.desc, got, tc.want)
+		}
+	}
+}
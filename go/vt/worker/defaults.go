@@ -16,11 +16,25 @@ limitations under the License.
 
 package worker
 
-import "vitess.io/vitess/go/vt/throttler"
+import (
+	"time"
+
+	"vitess.io/vitess/go/vt/throttler"
+)
 
 const (
 	defaultOnline  = true
 	defaultOffline = true
+	// defaultResume is false because resuming from a checkpoint of an earlier
+	// run must be requested explicitly.
+	defaultResume = false
+	// defaultCatchUp is false because the offline clone is the default way to
+	// get an exact copy of the data.
+	defaultCatchUp = false
+	// defaultVerify is true because TableMigrate does not leave filtered
+	// replication or a follow-up diff behind which would catch an incomplete
+	// copy.
+	defaultVerify = true
 	// defaultChunkCount is the number of chunks in which each table should be
 	// divided. One chunk is processed by one chunk pipeline at a time.
 	// -source_reader_count defines the number of concurrent chunk pipelines.
@@ -38,6 +52,13 @@ const (
 	// defaultWriteQueryMaxSize caps the write queries which aggregate multiple
 	// rows. This limit prevents e.g. that MySQL will OOM.
 	defaultWriteQueryMaxSize = 1024 * 1024
+	// defaultWriteTransactionMaxRows disables merging write queries into
+	// larger transactions by default i.e. each write query is committed on
+	// its own.
+	defaultWriteTransactionMaxRows = 0
+	// defaultWriteTransactionMaxSize caps the transactions of merged write
+	// queries. It matches the default max_allowed_packet of MySQL 5.7.
+	defaultWriteTransactionMaxSize = 4 * 1024 * 1024
 	// defaultDestinationPackCount is deprecated in favor of the writeQueryMax*
 	// values and currently only used by VerticalSplitClone.
 	// defaultDestinationPackCount is the number of StreamExecute responses which
@@ -48,9 +69,38 @@ const (
 	// increase memory consumption in vtworker, vttablet and mysql.
 	defaultDestinationPackCount    = 10
 	defaultDestinationWriterCount  = 20
+	defaultDestinationQueueSize    = 0 // 2 * destination_writer_count
 	defaultMinHealthyRdonlyTablets = 2
 	defaultDestTabletType          = "RDONLY"
+	defaultTabletType              = "RDONLY"
 	defaultParallelDiffsCount      = 8
+	defaultDiffChunkCount          = 1
+	defaultTableRetryCount         = 2
+	defaultTableRetryBackoff       = 10 * time.Second
+	defaultSamplePercent           = 100.0
+	defaultIncludeViews            = false
+	defaultPKLessTablesUseFullRow  = false
+	defaultRowCountCheck           = false
+	defaultChecksumOnly            = false
+	defaultRepair                  = false
+	defaultRepairExecute           = false
+	defaultRepairMaxRows           = 100
+	defaultHashColumnsLargerThan   = 0
+	defaultNormalizeCharset        = ""
+	defaultIgnoreColumns           = ""
+	defaultNormalizeTimestamps     = false
+	defaultFloatEpsilon            = 0.0
+	defaultReportDir               = ""
+	defaultReportToTopo            = false
+	defaultDiffResultsDir          = ""
+	defaultDiffResultsToTable      = false
+	defaultUseConsistentSnapshot   = false
 	defaultMaxTPS                  = throttler.MaxRateModuleDisabled
 	defaultMaxReplicationLag       = throttler.ReplicationLagModuleDisabled
+	// defaultMaxWriteMBPerSecond is 0 which means that the bandwidth of the
+	// writes to the destination tablets is not limited.
+	defaultMaxWriteMBPerSecond = 0
+	// defaultCompression is empty which means that the writes to the
+	// destination tablets use the compression of --grpc_compression.
+	defaultCompression = ""
 )
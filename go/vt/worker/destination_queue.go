@@ -0,0 +1,89 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+// destinationQueue buffers the write queries for one destination shard of a
+// clone. Each destination shard has its own queue and its own pool of writer
+// Go routines (see executor.fetchLoop()).
+// The readers send the rows of a chunk to all destination shards. The queue
+// lets them continue while a single destination master is temporarily slow.
+// Only when its queue is full, the readers have to wait for it. This time is
+// tracked and shown in the status as backlog of the destination.
+type destinationQueue struct {
+	keyspace string
+	shard    string
+	ch       chan *writeQuery
+
+	// sent is the number of queries which were sent to the queue.
+	sent sync2.AtomicInt64
+	// blocked is the total time senders waited because the queue was full.
+	blocked sync2.AtomicDuration
+}
+
+func newDestinationQueue(keyspace, shard string, size int) *destinationQueue {
+	return &destinationQueue{
+		keyspace: keyspace,
+		shard:    shard,
+		ch:       make(chan *writeQuery, size),
+	}
+}
+
+// send blocks until "q" was added to the queue or "ctx" is done.
+func (dq *destinationQueue) send(ctx context.Context, q *writeQuery) error {
+	// Fast path: There's space in the queue.
+	select {
+	case dq.ch <- q:
+		dq.sent.Add(1)
+		return nil
+	default:
+	}
+
+	start := time.Now()
+	defer func() {
+		dq.blocked.Add(time.Since(start))
+	}()
+	select {
+	case dq.ch <- q:
+		dq.sent.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close must be called after the last send(). The writers stop once they
+// have processed all queued queries.
+func (dq *destinationQueue) close() {
+	close(dq.ch)
+}
+
+// format returns the current backlog of the queue e.g.
+// "ks/-80: 12/40 queries queued, 300 sent, senders blocked for 1.5s".
+func (dq *destinationQueue) format() string {
+	return fmt.Sprintf("%v: %v/%v queries queued, %v sent, senders blocked for %v",
+		topoproto.KeyspaceShardString(dq.keyspace, dq.shard), len(dq.ch), cap(dq.ch), dq.sent.Get(), dq.blocked.Get().Round(100*time.Millisecond))
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestDestinationQueue(t *testing.T) {
+	ctx := context.Background()
+	dq := newDestinationQueue("ks", "-80", 1)
+
+	if err := dq.send(ctx, &writeQuery{sql: "INSERT 1"}); err != nil {
+		t.Fatalf("send() to a queue with free space failed: %v", err)
+	}
+	if got, want := dq.format(), "ks/-80: 1/1 queries queued, 1 sent, senders blocked for 0s"; got != want {
+		t.Errorf("format() = %v, want = %v", got, want)
+	}
+
+	// The queue is full. A canceled send does not add the query.
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := dq.send(cancelCtx, &writeQuery{sql: "INSERT 2"}); err == nil {
+		t.Fatal("send() to a full queue must fail when the context is done")
+	}
+	if dq.sent.Get() != 1 || dq.blocked.Get() == 0 {
+		t.Errorf("sent = %v, blocked = %v, want 1 sent and a blocked time", dq.sent.Get(), dq.blocked.Get())
+	}
+
+	// The send continues once a writer took a query.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-dq.ch
+	}()
+	if err := dq.send(ctx, &writeQuery{sql: "INSERT 3"}); err != nil {
+		t.Fatalf("send() failed: %v", err)
+	}
+	dq.close()
+	var got []string
+	for q := range dq.ch {
+		got = append(got, q.sql)
+	}
+	if strings.Join(got, ",") != "INSERT 3" {
+		t.Errorf("queued queries = %v, want = [INSERT 3]", got)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// diffOptions has the flags which are shared by the SplitDiff,
+// VerticalSplitDiff and MultiSplitDiff workers. The workers embed it.
+// Flags which are not supported by a worker (e.g. --repair for
+// MultiSplitDiff) are left at their zero value.
+type diffOptions struct {
+	minHealthyRdonlyTablets int
+	sourceTabletType        topodatapb.TabletType
+	destinationTabletType   topodatapb.TabletType
+	// sourceTabletAlias and destinationTabletAlias are optional. If set, they
+	// are used instead of a random healthy tablet.
+	sourceTabletAlias      *topodatapb.TabletAlias
+	destinationTabletAlias *topodatapb.TabletAlias
+
+	parallelDiffsCount     int
+	chunkCount             int
+	minRowsPerChunk        int
+	tableRetryCount        int
+	tableRetryBackoff      time.Duration
+	where                  string
+	samplePercent          float64
+	includeViews           bool
+	pklessTablesUseFullRow bool
+	rowCountCheck          bool
+	checksumOnly           bool
+	repair                 bool
+	repairExecute          bool
+	repairMaxRows          int
+	useConsistentSnapshot  bool
+
+	// The following flags are converted into comparisonOptions by validate().
+	hashColumnsLargerThan int
+	normalizeCharset      string
+	ignoreColumns         string
+	floatEpsilon          float64
+	normalizeTimestamps   bool
+
+	// The following flags configure the diffReportWriter and the
+	// diffResultsWriter.
+	reportDir          string
+	reportToTopo       bool
+	diffResultsDir     string
+	diffResultsToTable bool
+}
+
+// validate checks the values and the combination of the flags and returns
+// the comparisonOptions which correspond to them.
+func (o *diffOptions) validate() (comparisonOptions, error) {
+	if o.minHealthyRdonlyTablets < 0 {
+		return comparisonOptions{}, fmt.Errorf("min_healthy_rdonly_tablets must be >= 0: %v", o.minHealthyRdonlyTablets)
+	}
+	if o.sourceTabletType != topodatapb.TabletType_RDONLY && o.sourceTabletType != topodatapb.TabletType_REPLICA {
+		return comparisonOptions{}, fmt.Errorf("tablet_type must be RDONLY or REPLICA: %v", o.sourceTabletType)
+	}
+	if o.destinationTabletType != topodatapb.TabletType_RDONLY && o.destinationTabletType != topodatapb.TabletType_REPLICA {
+		return comparisonOptions{}, fmt.Errorf("dest_tablet_type must be RDONLY or REPLICA: %v", o.destinationTabletType)
+	}
+	if o.parallelDiffsCount <= 0 {
+		return comparisonOptions{}, fmt.Errorf("parallel_diffs_count must be > 0: %v", o.parallelDiffsCount)
+	}
+	if o.chunkCount <= 0 {
+		return comparisonOptions{}, fmt.Errorf("chunk_count must be > 0: %v", o.chunkCount)
+	}
+	if o.minRowsPerChunk <= 0 {
+		return comparisonOptions{}, fmt.Errorf("min_rows_per_chunk must be > 0: %v", o.minRowsPerChunk)
+	}
+	if o.tableRetryCount < 0 {
+		return comparisonOptions{}, fmt.Errorf("table_retry_count must be >= 0: %v", o.tableRetryCount)
+	}
+	if o.samplePercent <= 0 || o.samplePercent > 100 {
+		return comparisonOptions{}, fmt.Errorf("sample_percent must be > 0 and <= 100: %v", o.samplePercent)
+	}
+	if o.repairExecute && !o.repair {
+		return comparisonOptions{}, errors.New("repair_execute requires repair")
+	}
+	if o.repair && o.repairMaxRows <= 0 {
+		return comparisonOptions{}, fmt.Errorf("repair_max_rows must be > 0: %v", o.repairMaxRows)
+	}
+	if o.hashColumnsLargerThan < 0 {
+		return comparisonOptions{}, fmt.Errorf("hash_columns_larger_than must be >= 0: %v", o.hashColumnsLargerThan)
+	}
+	if o.normalizeCharset != "" && !charsetRegexp.MatchString(o.normalizeCharset) {
+		return comparisonOptions{}, fmt.Errorf("normalize_charset must be the name of a character set: %v", o.normalizeCharset)
+	}
+	if o.floatEpsilon < 0 {
+		return comparisonOptions{}, fmt.Errorf("float_epsilon must be >= 0: %v", o.floatEpsilon)
+	}
+	ignoreColumnsByTable, err := parseIgnoreColumns(o.ignoreColumns)
+	if err != nil {
+		return comparisonOptions{}, err
+	}
+	if o.useConsistentSnapshot && o.checksumOnly {
+		return comparisonOptions{}, errors.New("use_consistent_snapshot cannot be combined with checksum_only")
+	}
+	if o.useConsistentSnapshot && o.rowCountCheck {
+		return comparisonOptions{}, errors.New("use_consistent_snapshot cannot be combined with row_count_check")
+	}
+	if o.repair && o.rowCountCheck {
+		return comparisonOptions{}, errors.New("repair cannot be combined with row_count_check")
+	}
+	if o.repair && o.hashColumnsLargerThan > 0 {
+		return comparisonOptions{}, errors.New("repair cannot be combined with hash_columns_larger_than")
+	}
+	if o.repair && o.normalizeCharset != "" {
+		return comparisonOptions{}, errors.New("repair cannot be combined with normalize_charset")
+	}
+	if o.repair && o.normalizeTimestamps {
+		return comparisonOptions{}, errors.New("repair cannot be combined with normalize_timestamps")
+	}
+	if o.repair && len(ignoreColumnsByTable) > 0 {
+		return comparisonOptions{}, errors.New("repair cannot be combined with ignore_columns")
+	}
+	if o.pklessTablesUseFullRow && o.repair {
+		return comparisonOptions{}, errors.New("repair cannot be combined with pkless_tables_use_full_row")
+	}
+	if o.pklessTablesUseFullRow && o.useConsistentSnapshot {
+		return comparisonOptions{}, errors.New("use_consistent_snapshot cannot be combined with pkless_tables_use_full_row")
+	}
+
+	return comparisonOptions{
+		hashColumnsLargerThan: o.hashColumnsLargerThan,
+		normalizeCharset:      o.normalizeCharset,
+		normalizeTimestamps:   o.normalizeTimestamps,
+		ignoreColumns:         ignoreColumnsByTable,
+		floatEpsilon:          o.floatEpsilon,
+	}, nil
+}
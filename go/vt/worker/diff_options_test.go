@@ -0,0 +1,84 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestDiffOptionsValidate(t *testing.T) {
+	valid := func() diffOptions {
+		return diffOptions{
+			sourceTabletType:      topodatapb.TabletType_RDONLY,
+			destinationTabletType: topodatapb.TabletType_REPLICA,
+			parallelDiffsCount:    1,
+			chunkCount:            1,
+			minRowsPerChunk:       1,
+			samplePercent:         100,
+			repairMaxRows:         1,
+			normalizeCharset:      "utf8mb4",
+			ignoreColumns:         "t1:c1",
+			floatEpsilon:          0.5,
+		}
+	}
+
+	opts := valid()
+	got, err := opts.validate()
+	if err != nil {
+		t.Fatalf("validate() failed: %v", err)
+	}
+	want := comparisonOptions{
+		normalizeCharset: "utf8mb4",
+		ignoreColumns:    map[string][]string{"t1": {"c1"}},
+		floatEpsilon:     0.5,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("validate() = %+v, want = %+v", got, want)
+	}
+
+	testcases := []struct {
+		desc    string
+		modify  func(o *diffOptions)
+		wantErr string
+	}{
+		{
+			desc:    "destination master",
+			modify:  func(o *diffOptions) { o.destinationTabletType = topodatapb.TabletType_MASTER },
+			wantErr: "dest_tablet_type must be RDONLY or REPLICA: MASTER",
+		},
+		{
+			desc:    "repair with a normalized charset",
+			modify:  func(o *diffOptions) { o.repair = true },
+			wantErr: "repair cannot be combined with normalize_charset",
+		},
+		{
+			desc:    "consistent snapshot with checksums",
+			modify:  func(o *diffOptions) { o.useConsistentSnapshot, o.checksumOnly = true, true },
+			wantErr: "use_consistent_snapshot cannot be combined with checksum_only",
+		},
+	}
+	for _, tc := range testcases {
+		opts := valid()
+		tc.modify(&opts)
+		if _, err := opts.validate(); err == nil || err.Error() != tc.wantErr {
+			t.Errorf("%v: validate() = %v, want = %v", tc.desc, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// rowRepairer collects the SQL statements which reconcile the differences
+// found by a RowDiffer. The left side of the diff is considered the source
+// of truth and the statements must be run on the right side.
+// To limit the damage in case of an unexpected large diff, it stops
+// collecting statements once "maxRows" rows have to be repaired.
+type rowRepairer struct {
+	maxRows  int
+	builders map[DiffType]QueryBuilder
+
+	// mu guards all fields in the group below.
+	mu         sync.Mutex
+	statements []string
+	rowCount   int
+}
+
+// newRowRepairer returns a rowRepairer for the table "td" in the database
+// "dbName". "td" must have the same column order as the rows which are
+// passed to add() i.e. the primary key columns must come first.
+func newRowRepairer(dbName string, td *tabletmanagerdatapb.TableDefinition, maxRows int) *rowRepairer {
+	return &rowRepairer{
+		maxRows: maxRows,
+		builders: map[DiffType]QueryBuilder{
+			DiffMissing:    NewInsertsQueryBuilder(dbName, td),
+			DiffNotEqual:   NewUpdatesQueryBuilder(dbName, td),
+			DiffExtraneous: NewDeletesQueryBuilder(dbName, td),
+		},
+	}
+}
+
+// add records a statement which reconciles "row".
+// For DiffMissing and DiffNotEqual, "row" must be the left row. For
+// DiffExtraneous, it must be the right row.
+func (r *rowRepairer) add(row []sqltypes.Value, typ DiffType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rowCount++
+	if r.rowCount > r.maxRows {
+		return
+	}
+
+	builder, ok := r.builders[typ]
+	if !ok {
+		panic(fmt.Sprintf("rowRepairer.add() called with wrong type: %v", typ))
+	}
+	var b bytes.Buffer
+	builder.WriteHead(&b)
+	builder.WriteRow(&b, row)
+	builder.WriteTail(&b)
+	r.statements = append(r.statements, b.String())
+}
+
+// limitExceeded returns true if more than "maxRows" rows must be repaired.
+func (r *rowRepairer) limitExceeded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rowCount > r.maxRows
+}
+
+// queries returns the list of collected statements.
+func (r *rowRepairer) queries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.statements...)
+}
+
+// newRowRepairerForTablet returns a rowRepairer for the database of the
+// tablet "alias".
+func newRowRepairerForTablet(ctx context.Context, wr *wrangler.Wrangler, alias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, maxRows int) (*rowRepairer, error) {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	ti, err := wr.TopoServer().GetTablet(shortCtx, alias)
+	cancel()
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read tablet %v", topoproto.TabletAliasString(alias))
+	}
+	return newRowRepairer(topoproto.TabletDbName(ti.Tablet), reorderColumnsPrimaryKeyFirst(td), maxRows), nil
+}
+
+// repairTable logs the statements collected by "repairer" and runs them on
+// the master tablet "masterAlias" if "execute" is true.
+// The statements reflect the state of the stopped tablets which were diffed.
+// Therefore, the caller must keep filtered replication on "masterAlias"
+// stopped at the diffed position until they were executed.
+// It returns an error if the differences could not be repaired.
+func repairTable(ctx context.Context, wr *wrangler.Wrangler, masterAlias *topodatapb.TabletAlias, tableName string, repairer *rowRepairer, execute bool) error {
+	if repairer.limitExceeded() {
+		return fmt.Errorf("table %v: not repairing the differences because more than %v rows are affected (see --repair_max_rows)", tableName, repairer.maxRows)
+	}
+
+	queries := repairer.queries()
+	for _, query := range queries {
+		wr.Logger().Infof("table=%v: repair statement: %v", tableName, query)
+	}
+	if !execute {
+		return fmt.Errorf("table %v: %v repair statements were generated but not executed (see --repair_execute)", tableName, len(queries))
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	ti, err := wr.TopoServer().GetTablet(shortCtx, masterAlias)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read master tablet %v", topoproto.TabletAliasString(masterAlias))
+	}
+	for _, query := range queries {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		_, err := wr.TabletManagerClient().ExecuteFetchAsApp(shortCtx, ti.Tablet, false /* usePool */, []byte(query), 0 /* maxRows */)
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "table %v: failed to run repair statement on master %v: %v", tableName, topoproto.TabletAliasString(masterAlias), query)
+		}
+	}
+	wr.Logger().Infof("table=%v: executed %v repair statements on master %v", tableName, len(queries), topoproto.TabletAliasString(masterAlias))
+	return nil
+}
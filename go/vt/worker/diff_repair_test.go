@@ -0,0 +1,59 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestRowRepairer(t *testing.T) {
+	td := reorderColumnsPrimaryKeyFirst(&tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"msg", "id"},
+		PrimaryKeyColumns: []string{"id"},
+	})
+	r := newRowRepairer("vt_ks", td, 3)
+
+	r.add([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("a")}, DiffMissing)
+	r.add([]sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarBinary("b")}, DiffNotEqual)
+	r.add([]sqltypes.Value{sqltypes.NewInt64(3), sqltypes.NewVarBinary("c")}, DiffExtraneous)
+	want := []string{
+		"INSERT INTO `vt_ks`.`t1` (`id`, `msg`) VALUES (1,'a')",
+		"UPDATE `vt_ks`.`t1` SET `msg`='b' WHERE `id`=2",
+		"DELETE FROM `vt_ks`.`t1` WHERE (`id`=3)",
+	}
+	if got := r.queries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("queries() = %v, want = %v", got, want)
+	}
+	if r.limitExceeded() {
+		t.Fatalf("limitExceeded() = true, want = false")
+	}
+
+	// One more row exceeds the limit and is not recorded.
+	r.add([]sqltypes.Value{sqltypes.NewInt64(4), sqltypes.NewVarBinary("d")}, DiffMissing)
+	if !r.limitExceeded() {
+		t.Fatalf("limitExceeded() = false, want = true")
+	}
+	if got := r.queries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("queries() = %v, want = %v", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// diffReportsPath is the directory in the global topology which has the
+// diff reports. The layout is the same as in the local directory:
+// <name>/<keyspace>/<shard>/<table>.json
+const diffReportsPath = "vtworker_diff_reports"
+
+// tableDiffReport is the machine-readable form of the diff result of a table.
+type tableDiffReport struct {
+	Command  string `json:"command"`
+	Keyspace string `json:"keyspace"`
+	Shard    string `json:"shard"`
+	Table    string `json:"table"`
+	// SamplePercent is set if only a sample of the rows was compared.
+	SamplePercent float64 `json:"sample_percent,omitempty"`
+
+	ProcessedRows  int `json:"processed_rows"`
+	MatchingRows   int `json:"matching_rows"`
+	MismatchedRows int `json:"mismatched_rows"`
+	ExtraRowsLeft  int `json:"extra_rows_left"`
+	ExtraRowsRight int `json:"extra_rows_right"`
+	// HashMismatchedRows is the number of mismatched rows which differ in a
+	// column which was compared by its hash.
+	HashMismatchedRows int `json:"hash_mismatched_rows,omitempty"`
+	// DifferentRows has the first different rows of each type.
+	DifferentRows []differentRowReport `json:"different_rows,omitempty"`
+	// Columns are the names of the columns of the rows in DifferentRows.
+	Columns []string `json:"columns,omitempty"`
+
+	StartTime       time.Time `json:"start_time"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	ProcessingQPS   int       `json:"processing_qps"`
+
+	// Error is set if the diff failed before it was complete.
+	Error string `json:"error,omitempty"`
+}
+
+// differentRowReport is the machine-readable form of a differentRow.
+type differentRowReport struct {
+	// Type is one of "missing", "not_equal" or "extraneous".
+	Type       string   `json:"type"`
+	PrimaryKey []string `json:"primary_key"`
+	// SourceRow is not set for "extraneous".
+	SourceRow []string `json:"source_row,omitempty"`
+	// DestinationRow is not set for "missing".
+	DestinationRow []string `json:"destination_row,omitempty"`
+}
+
+// diffTypeNames has the names of the DiffType values in reports.
+var diffTypeNames = map[DiffType]string{
+	DiffMissing:    "missing",
+	DiffNotEqual:   "not_equal",
+	DiffExtraneous: "extraneous",
+}
+
+func newTableDiffReport(command, keyspace, shard, table string, samplePercent float64, dr DiffReport, err error) *tableDiffReport {
+	r := &tableDiffReport{
+		Command:            command,
+		Keyspace:           keyspace,
+		Shard:              shard,
+		Table:              table,
+		ProcessedRows:      dr.processedRows,
+		MatchingRows:       dr.matchingRows,
+		MismatchedRows:     dr.mismatchedRows,
+		ExtraRowsLeft:      dr.extraRowsLeft,
+		ExtraRowsRight:     dr.extraRowsRight,
+		HashMismatchedRows: dr.hashMismatchedRows,
+		StartTime:          dr.startingTime,
+		DurationSeconds:    dr.duration.Seconds(),
+		ProcessingQPS:      dr.processingQPS,
+	}
+	if samplePercent < 100 {
+		r.SamplePercent = samplePercent
+	}
+	r.DifferentRows = newDifferentRowReports(dr.differentRows)
+	if r.DifferentRows != nil {
+		r.Columns = dr.columns
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// newDifferentRowReports returns the machine-readable form of "rows".
+func newDifferentRowReports(rows []differentRow) []differentRowReport {
+	var result []differentRowReport
+	for _, row := range rows {
+		result = append(result, differentRowReport{
+			Type:           diffTypeNames[row.diffType],
+			PrimaryKey:     valuesToStrings(row.primaryKey),
+			SourceRow:      valuesToStrings(row.sourceRow),
+			DestinationRow: valuesToStrings(row.destinationRow),
+		})
+	}
+	return result
+}
+
+// valuesToStrings returns the string representation of each value. It
+// returns nil for a nil row.
+func valuesToStrings(row []sqltypes.Value) []string {
+	if row == nil {
+		return nil
+	}
+	result := make([]string, len(row))
+	for i, v := range row {
+		result[i] = v.ToString()
+	}
+	return result
+}
+
+// diffReportWriter writes a tableDiffReport for each table to a local
+// directory and/or the global topology.
+type diffReportWriter struct {
+	command  string
+	keyspace string
+	shard    string
+	// dir is the local directory. Empty if disabled.
+	dir string
+	// ts is the topology server. nil if disabled.
+	ts *topo.Server
+	// samplePercent is the percentage of rows which were compared.
+	samplePercent float64
+}
+
+// newDiffReportWriter returns a diffReportWriter or nil if "dir" is empty and
+// "toTopo" is false.
+func newDiffReportWriter(ts *topo.Server, command, keyspace, shard, dir string, toTopo bool, samplePercent float64) *diffReportWriter {
+	if dir == "" && !toTopo {
+		return nil
+	}
+	w := &diffReportWriter{
+		command:  command,
+		keyspace: keyspace,
+		shard:    shard,
+		dir:      dir,
+
+		samplePercent: samplePercent,
+	}
+	if toTopo {
+		w.ts = ts
+	}
+	return w
+}
+
+// write saves the report of "table". It is a no-op for a nil writer.
+func (w *diffReportWriter) write(ctx context.Context, table string, dr DiffReport, diffErr error) error {
+	if w == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(newTableDiffReport(w.command, w.keyspace, w.shard, table, w.samplePercent, dr, diffErr), "", "  ")
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot encode diff report for table %v", table)
+	}
+
+	if w.dir != "" {
+		dir := filepath.Join(w.dir, w.command, w.keyspace, w.shard)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return vterrors.Wrapf(err, "cannot create diff report directory %v", dir)
+		}
+		file := filepath.Join(dir, table+".json")
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			return vterrors.Wrapf(err, "cannot write diff report %v", file)
+		}
+	}
+
+	if w.ts != nil {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		defer cancel()
+		conn, err := w.ts.ConnForCell(shortCtx, topo.GlobalCell)
+		if err != nil {
+			return err
+		}
+		file := path.Join(diffReportsPath, w.command, w.keyspace, w.shard, table+".json")
+		if _, err := conn.Update(shortCtx, file, data, nil); err != nil {
+			return vterrors.Wrapf(err, "cannot write diff report %v to the topology", file)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestDiffReportWriter(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	dir, err := ioutil.TempDir("", "diff_report_writer_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if w := newDiffReportWriter(ts, "VerticalSplitDiff", "ks", "0", "", false, 100); w != nil {
+		t.Fatalf("newDiffReportWriter() without a destination should return nil: %v", w)
+	}
+
+	w := newDiffReportWriter(ts, "VerticalSplitDiff", "ks", "0", dir, true, 10)
+	dr := DiffReport{
+		processedRows:  3,
+		matchingRows:   1,
+		mismatchedRows: 1,
+		extraRowsLeft:  1,
+		differentRows: []differentRow{
+			{
+				diffType:       DiffNotEqual,
+				primaryKey:     []sqltypes.Value{sqltypes.NewInt64(2)},
+				sourceRow:      []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarBinary("a")},
+				destinationRow: []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarBinary("b")},
+			},
+			{
+				diffType:   DiffMissing,
+				primaryKey: []sqltypes.Value{sqltypes.NewInt64(3)},
+				sourceRow:  []sqltypes.Value{sqltypes.NewInt64(3), sqltypes.NewVarBinary("c")},
+			},
+		},
+		columns: []string{"id", "msg"},
+	}
+	if err := w.write(ctx, "t1", dr, nil); err != nil {
+		t.Fatalf("write() failed: %v", err)
+	}
+
+	want := &tableDiffReport{
+		Command:        "VerticalSplitDiff",
+		Keyspace:       "ks",
+		Shard:          "0",
+		Table:          "t1",
+		SamplePercent:  10,
+		ProcessedRows:  3,
+		MatchingRows:   1,
+		MismatchedRows: 1,
+		ExtraRowsLeft:  1,
+		DifferentRows: []differentRowReport{
+			{Type: "not_equal", PrimaryKey: []string{"2"}, SourceRow: []string{"2", "a"}, DestinationRow: []string{"2", "b"}},
+			{Type: "missing", PrimaryKey: []string{"3"}, SourceRow: []string{"3", "c"}},
+		},
+		Columns: []string{"id", "msg"},
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "VerticalSplitDiff", "ks", "0", "t1.json"))
+	if err != nil {
+		t.Fatalf("cannot read local report: %v", err)
+	}
+	got := &tableDiffReport{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("cannot parse local report: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("local report = %+v, want = %+v", got, want)
+	}
+
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err = conn.Get(ctx, path.Join(diffReportsPath, "VerticalSplitDiff", "ks", "0", "t1.json"))
+	if err != nil {
+		t.Fatalf("cannot read report from topo: %v", err)
+	}
+	got = &tableDiffReport{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("cannot parse report from topo: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("topo report = %+v, want = %+v", got, want)
+	}
+}
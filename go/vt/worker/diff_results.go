@@ -0,0 +1,292 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// diffResultsBatchSize is the maximum number of rows which are inserted into
+// _vt.diff_results by a single statement.
+const diffResultsBatchSize = 100
+
+// createDiffResultsTable returns the statements which create the
+// _vt.diff_results table.
+// Each row is a different row of a diff run. "started" is the start time of
+// the run in seconds since the epoch and tells different runs apart.
+// "primary_key" is the JSON list of the primary key values.
+func createDiffResultsTable() []string {
+	return []string{
+		"CREATE DATABASE IF NOT EXISTS _vt",
+		`CREATE TABLE IF NOT EXISTS _vt.diff_results (
+  id BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+  command VARBINARY(100) NOT NULL,
+  keyspace VARBINARY(256) NOT NULL,
+  shard VARBINARY(256) NOT NULL,
+  table_name VARBINARY(256) NOT NULL,
+  started BIGINT(20) NOT NULL,
+  diff_type VARBINARY(20) NOT NULL,
+  primary_key BLOB NOT NULL,
+  PRIMARY KEY (id),
+  KEY run (keyspace, shard, table_name, started)
+) ENGINE=InnoDB`}
+}
+
+// diffResultsWriter streams the primary key and the DiffType of each
+// different row to a CSV file per table in a local directory and/or to the
+// _vt.diff_results table on the destination master. Unlike the diff report,
+// it records all different rows and not only the first ones.
+type diffResultsWriter struct {
+	wr       *wrangler.Wrangler
+	command  string
+	keyspace string
+	shard    string
+	// dir is the local directory. Empty if disabled.
+	dir string
+	// toTable is true if the rows are written to _vt.diff_results.
+	toTable bool
+	// started tells the rows of this run apart from previous runs.
+	started time.Time
+
+	// master is the destination master. It is set by open() if toTable is
+	// true.
+	master *topodatapb.Tablet
+}
+
+// newDiffResultsWriter returns a diffResultsWriter or nil if "dir" is empty
+// and "toTable" is false.
+func newDiffResultsWriter(wr *wrangler.Wrangler, command, keyspace, shard, dir string, toTable bool) *diffResultsWriter {
+	if dir == "" && !toTable {
+		return nil
+	}
+	return &diffResultsWriter{
+		wr:       wr,
+		command:  command,
+		keyspace: keyspace,
+		shard:    shard,
+		dir:      dir,
+		toTable:  toTable,
+		started:  time.Now(),
+	}
+}
+
+// open must be called before any rows are recorded. If enabled, it creates
+// _vt.diff_results on the destination master "masterAlias".
+// It is a no-op for a nil writer.
+func (w *diffResultsWriter) open(ctx context.Context, masterAlias *topodatapb.TabletAlias) error {
+	if w == nil || !w.toTable {
+		return nil
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	ti, err := w.wr.TopoServer().GetTablet(shortCtx, masterAlias)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read master tablet %v", topoproto.TabletAliasString(masterAlias))
+	}
+	w.master = ti.Tablet
+	for _, query := range createDiffResultsTable() {
+		if err := w.execute(ctx, query); err != nil {
+			return vterrors.Wrap(err, "cannot create _vt.diff_results")
+		}
+	}
+	w.wr.Logger().Infof("Writing the different rows to _vt.diff_results on master %v (started=%v)", topoproto.TabletAliasString(masterAlias), w.started.Unix())
+	return nil
+}
+
+func (w *diffResultsWriter) execute(ctx context.Context, query string) error {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	_, err := w.wr.TabletManagerClient().ExecuteFetchAsDba(shortCtx, w.master, true /* usePool */, []byte(query), 0 /* maxRows */, false /* disableBinlogs */, false /* reloadSchema */)
+	return err
+}
+
+// newRecorder returns the recorder for the different rows of "td". Rows
+// which a previous attempt of this run recorded for the table are discarded.
+// It returns nil for a nil writer.
+func (w *diffResultsWriter) newRecorder(ctx context.Context, td *tabletmanagerdatapb.TableDefinition) (*diffResultsRecorder, error) {
+	if w == nil {
+		return nil, nil
+	}
+
+	r := &diffResultsRecorder{
+		ctx:   ctx,
+		w:     w,
+		table: td.Name,
+	}
+	if w.toTable {
+		query := fmt.Sprintf("DELETE FROM _vt.diff_results WHERE keyspace=%v AND shard=%v AND table_name=%v AND started=%v",
+			encodeSQLString(w.keyspace), encodeSQLString(w.shard), encodeSQLString(td.Name), w.started.Unix())
+		if err := w.execute(ctx, query); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot delete previous rows of table %v from _vt.diff_results", td.Name)
+		}
+	}
+	if w.dir != "" {
+		dir := filepath.Join(w.dir, w.command, w.keyspace, w.shard)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, vterrors.Wrapf(err, "cannot create diff results directory %v", dir)
+		}
+		file := filepath.Join(dir, td.Name+".csv")
+		f, err := os.Create(file)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot create diff results file %v", file)
+		}
+		r.file = f
+		r.csv = csv.NewWriter(f)
+		header := append([]string{"diff_type"}, td.PrimaryKeyColumns...)
+		if err := r.csv.Write(header); err != nil {
+			f.Close()
+			return nil, vterrors.Wrapf(err, "cannot write to diff results file %v", file)
+		}
+	}
+	return r, nil
+}
+
+// diffResultsRecorder records the different rows of one table.
+// It is safe to use it from multiple Go routines e.g. for parallel chunks.
+type diffResultsRecorder struct {
+	ctx   context.Context
+	w     *diffResultsWriter
+	table string
+
+	// mu guards all fields in the group below.
+	mu   sync.Mutex
+	file *os.File
+	csv  *csv.Writer
+	// pending has the rows which were not inserted into _vt.diff_results yet.
+	pending []differentRow
+	// err is the first error. Once set, all further rows are dropped.
+	err error
+}
+
+// add records the primary key of a different row. Errors are returned by
+// close(). It is a no-op for a nil recorder.
+func (r *diffResultsRecorder) add(primaryKey []sqltypes.Value, typ DiffType) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	if r.csv != nil {
+		record := []string{diffTypeNames[typ]}
+		for _, v := range primaryKey {
+			record = append(record, v.ToString())
+		}
+		if err := r.csv.Write(record); err != nil {
+			r.err = vterrors.Wrapf(err, "cannot write to diff results file of table %v", r.table)
+			return
+		}
+	}
+	if r.w.toTable {
+		r.pending = append(r.pending, differentRow{diffType: typ, primaryKey: primaryKey})
+		if len(r.pending) >= diffResultsBatchSize {
+			r.flushPendingLocked()
+		}
+	}
+}
+
+// flushPendingLocked inserts the pending rows. r.mu must be held.
+func (r *diffResultsRecorder) flushPendingLocked() {
+	if len(r.pending) == 0 {
+		return
+	}
+	query, err := diffResultsInsertQuery(r.w.command, r.w.keyspace, r.w.shard, r.table, r.w.started.Unix(), r.pending)
+	if err == nil {
+		err = r.w.execute(r.ctx, query)
+	}
+	if err != nil {
+		r.err = vterrors.Wrapf(err, "cannot insert the different rows of table %v into _vt.diff_results", r.table)
+	}
+	r.pending = nil
+}
+
+// close writes all remaining rows. It returns the first error which occurred
+// while recording the rows. It is a no-op for a nil recorder.
+func (r *diffResultsRecorder) close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		r.flushPendingLocked()
+	}
+	if r.csv != nil {
+		r.csv.Flush()
+		if err := r.csv.Error(); err != nil && r.err == nil {
+			r.err = vterrors.Wrapf(err, "cannot write to diff results file of table %v", r.table)
+		}
+		if err := r.file.Close(); err != nil && r.err == nil {
+			r.err = vterrors.Wrapf(err, "cannot close diff results file of table %v", r.table)
+		}
+		r.csv = nil
+	}
+	return r.err
+}
+
+// diffResultsInsertQuery returns the statement which inserts "rows" into
+// _vt.diff_results.
+func diffResultsInsertQuery(command, keyspace, shard, table string, started int64, rows []differentRow) (string, error) {
+	var b bytes.Buffer
+	b.WriteString("INSERT INTO _vt.diff_results (command, keyspace, shard, table_name, started, diff_type, primary_key) VALUES ")
+	for i, row := range rows {
+		pk := make([]string, len(row.primaryKey))
+		for j, v := range row.primaryKey {
+			pk[j] = v.ToString()
+		}
+		data, err := json.Marshal(pk)
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "(%v, %v, %v, %v, %v, %v, %v)",
+			encodeSQLString(command), encodeSQLString(keyspace), encodeSQLString(shard), encodeSQLString(table),
+			started, encodeSQLString(diffTypeNames[row.diffType]), encodeSQLString(string(data)))
+	}
+	return b.String(), nil
+}
+
+// encodeSQLString returns "s" as escaped SQL string literal.
+func encodeSQLString(s string) string {
+	var b bytes.Buffer
+	sqltypes.NewVarBinary(s).EncodeSQL(&b)
+	return b.String()
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestDiffResultsWriterCSV(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "diff_results_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if w := newDiffResultsWriter(nil, "SplitDiff", "ks", "-80", "", false); w != nil {
+		t.Fatalf("newDiffResultsWriter() without a destination should return nil: %v", w)
+	}
+	// A nil writer and its nil recorder are no-ops.
+	var nilWriter *diffResultsWriter
+	r, err := nilWriter.newRecorder(ctx, &tabletmanagerdatapb.TableDefinition{Name: "t1"})
+	if err != nil || r != nil {
+		t.Fatalf("newRecorder() on a nil writer = %v, %v, want nil, nil", r, err)
+	}
+	r.add([]sqltypes.Value{sqltypes.NewInt64(1)}, DiffMissing)
+	if err := r.close(); err != nil {
+		t.Fatalf("close() on a nil recorder failed: %v", err)
+	}
+
+	w := newDiffResultsWriter(nil, "SplitDiff", "ks", "-80", dir, false)
+	if err := w.open(ctx, nil); err != nil {
+		t.Fatalf("open() failed: %v", err)
+	}
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		PrimaryKeyColumns: []string{"id", "name"},
+	}
+	r, err = w.newRecorder(ctx, td)
+	if err != nil {
+		t.Fatalf("newRecorder() failed: %v", err)
+	}
+	r.add([]sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("a,b")}, DiffNotEqual)
+	r.add([]sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarBinary("c")}, DiffExtraneous)
+	if err := r.close(); err != nil {
+		t.Fatalf("close() failed: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "SplitDiff", "ks", "-80", "t1.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "diff_type,id,name\nnot_equal,1,\"a,b\"\nextraneous,2,c\n"
+	if got := string(data); got != want {
+		t.Errorf("wrong CSV file: got = %q, want = %q", got, want)
+	}
+}
+
+func TestDiffResultsInsertQuery(t *testing.T) {
+	rows := []differentRow{
+		{diffType: DiffMissing, primaryKey: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("o'neil")}},
+		{diffType: DiffNotEqual, primaryKey: []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarBinary("x")}},
+	}
+	got, err := diffResultsInsertQuery("SplitDiff", "ks", "-80", "t1", 1500000000, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "INSERT INTO _vt.diff_results (command, keyspace, shard, table_name, started, diff_type, primary_key) VALUES " +
+		"('SplitDiff', 'ks', '-80', 't1', 1500000000, 'missing', '[\\\"1\\\",\\\"o\\'neil\\\"]'), " +
+		"('SplitDiff', 'ks', '-80', 't1', 1500000000, 'not_equal', '[\\\"2\\\",\\\"x\\\"]')"
+	if got != want {
+		t.Errorf("diffResultsInsertQuery() = %v, want = %v", got, want)
+	}
+}
@@ -21,18 +21,23 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"vitess.io/vitess/go/vt/vterrors"
 
 	"golang.org/x/net/context"
+	"golang.org/x/text/collate"
 
 	"github.com/golang/protobuf/proto"
 	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/logutil"
@@ -52,15 +57,17 @@ import (
 // we are confident that the new SplitClone code produces the same diff results
 // as the old diff code.
 type QueryResultReader struct {
-	output sqltypes.ResultStream
-	fields []*querypb.Field
-	conn   queryservice.QueryService
+	ctx       context.Context
+	output    sqltypes.ResultStream
+	fields    []*querypb.Field
+	conn      queryservice.QueryService
+	throttler *scanThrottler
 }
 
 // NewQueryResultReaderForTablet creates a new QueryResultReader for
 // the provided tablet / sql query
 func NewQueryResultReaderForTablet(ctx context.Context, ts *topo.Server, tabletAlias *topodatapb.TabletAlias, sql string) (*QueryResultReader, error) {
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	tablet, err := ts.GetTablet(shortCtx, tabletAlias)
 	cancel()
 	if err != nil {
@@ -85,15 +92,25 @@ func NewQueryResultReaderForTablet(ctx context.Context, ts *topo.Server, tabletA
 	}
 
 	return &QueryResultReader{
-		output: stream,
-		fields: cols.Fields,
-		conn:   conn,
+		ctx:       ctx,
+		output:    stream,
+		fields:    cols.Fields,
+		conn:      conn,
+		throttler: scanThrottlerForTablet(tabletAlias),
 	}, nil
 }
 
 // Next returns the next result on the stream. It implements ResultReader.
+// It blocks if the scan exceeds the rate limits of the tablet.
 func (qrr *QueryResultReader) Next() (*sqltypes.Result, error) {
-	return qrr.output.Recv()
+	result, err := qrr.output.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if err := qrr.throttler.throttle(qrr.ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // Fields returns the field data. It implements ResultReader.
@@ -145,6 +162,82 @@ func reorderColumnsPrimaryKeyFirst(td *tabletmanagerdatapb.TableDefinition) *tab
 	return reorderedTd
 }
 
+// diffTableDefinition returns the definition which the diff workers use for
+// table "td". The columns in "ignoreColumns" are removed from a copy of "td"
+// and therefore never selected or compared.
+// A table without a primary key cannot be diffed row by row because its rows
+// have no order. If "pklessUseFullRow" is set, a copy of "td" which uses all
+// (remaining) columns as primary key is returned instead: Its rows are
+// ordered by all columns and compared as a whole. A different row shows up as
+// one missing and one extraneous row then.
+func diffTableDefinition(td *tabletmanagerdatapb.TableDefinition, pklessUseFullRow bool, ignoreColumns []string) (*tabletmanagerdatapb.TableDefinition, error) {
+	if len(td.PrimaryKeyColumns) == 0 && !pklessUseFullRow {
+		return nil, fmt.Errorf("table %v has no primary key. Use --pkless_tables_use_full_row to compare its rows by all columns or exclude the table", td.Name)
+	}
+	if len(td.PrimaryKeyColumns) > 0 && len(ignoreColumns) == 0 {
+		return td, nil
+	}
+
+	diffTd := proto.Clone(td).(*tabletmanagerdatapb.TableDefinition)
+	if len(ignoreColumns) > 0 {
+		ignored := make(map[string]bool)
+		for _, column := range ignoreColumns {
+			ignored[strings.ToLower(column)] = true
+		}
+		for _, column := range td.PrimaryKeyColumns {
+			if ignored[strings.ToLower(column)] {
+				return nil, fmt.Errorf("cannot ignore the primary key column %v of table %v", column, td.Name)
+			}
+		}
+		diffTd.Columns = nil
+		for _, column := range td.Columns {
+			if ignored[strings.ToLower(column)] {
+				delete(ignored, strings.ToLower(column))
+				continue
+			}
+			diffTd.Columns = append(diffTd.Columns, column)
+		}
+		if len(ignored) > 0 {
+			var missing []string
+			for column := range ignored {
+				missing = append(missing, column)
+			}
+			sort.Strings(missing)
+			return nil, fmt.Errorf("cannot ignore the columns %v of table %v because they do not exist", strings.Join(missing, ", "), td.Name)
+		}
+	}
+	if len(td.PrimaryKeyColumns) == 0 {
+		diffTd.PrimaryKeyColumns = diffTd.Columns
+	}
+	return diffTd, nil
+}
+
+// parseIgnoreColumns parses the value of the --ignore_columns flag e.g.
+// "t1:last_seen,counter;t2:updated" and returns the columns by table name.
+func parseIgnoreColumns(value string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid ignore_columns entry %q: must be table:column1,column2", entry)
+		}
+		table := strings.TrimSpace(parts[0])
+		for _, column := range strings.Split(parts[1], ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				result[table] = append(result[table], column)
+			}
+		}
+		if len(result[table]) == 0 {
+			return nil, fmt.Errorf("invalid ignore_columns entry %q: no columns for table %v", entry, table)
+		}
+	}
+	return result, nil
+}
+
 // orderedColumns returns the list of columns:
 // - first the primary key columns in the right order
 // - then the rest of the columns
@@ -232,48 +325,98 @@ func TableScanByKeyRange(ctx context.Context, log logutil.Logger, ts *topo.Serve
 	}
 
 	// in v2 mode, we can do the filtering at the source
+	where, err := keyRangeWhereClause(keyRange, shardingColumnName, shardingColumnType)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf("SELECT %v FROM %v", strings.Join(escapeAll(orderedColumns(td)), ", "), sqlescape.EscapeID(td.Name))
+	if where != "" {
+		sql += " WHERE " + where
+	}
+	if len(td.PrimaryKeyColumns) > 0 {
+		sql += fmt.Sprintf(" ORDER BY %v", strings.Join(escapeAll(td.PrimaryKeyColumns), ", "))
+	}
+	log.Infof("SQL query for %v/%v: %v", topoproto.TabletAliasString(tabletAlias), td.Name, sql)
+	return NewQueryResultReaderForTablet(ctx, ts, tabletAlias, sql)
+}
+
+// keyRangeWhereClause returns a WHERE clause which matches all rows within
+// "keyRange" based on the (v2) sharding column. It returns an empty string if
+// the key range covers all rows.
+func keyRangeWhereClause(keyRange *topodatapb.KeyRange, shardingColumnName string, shardingColumnType topodatapb.KeyspaceIdType) (string, error) {
 	where := ""
 	switch shardingColumnType {
 	case topodatapb.KeyspaceIdType_UINT64:
 		if len(keyRange.Start) > 0 {
 			if len(keyRange.End) > 0 {
 				// have start & end
-				where = fmt.Sprintf("WHERE %v >= %v AND %v < %v", sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.Start), sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.End))
+				where = fmt.Sprintf("%v >= %v AND %v < %v", sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.Start), sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.End))
 			} else {
 				// have start only
-				where = fmt.Sprintf("WHERE %v >= %v", sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.Start))
+				where = fmt.Sprintf("%v >= %v", sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.Start))
 			}
 		} else {
 			if len(keyRange.End) > 0 {
 				// have end only
-				where = fmt.Sprintf("WHERE %v < %v", sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.End))
+				where = fmt.Sprintf("%v < %v", sqlescape.EscapeID(shardingColumnName), uint64FromKeyspaceID(keyRange.End))
 			}
 		}
 	case topodatapb.KeyspaceIdType_BYTES:
 		if len(keyRange.Start) > 0 {
 			if len(keyRange.End) > 0 {
 				// have start & end
-				where = fmt.Sprintf("WHERE HEX(%v) >= '%v' AND HEX(%v) < '%v'", sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.Start), sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.End))
+				where = fmt.Sprintf("HEX(%v) >= '%v' AND HEX(%v) < '%v'", sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.Start), sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.End))
 			} else {
 				// have start only
-				where = fmt.Sprintf("WHERE HEX(%v) >= '%v'", sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.Start))
+				where = fmt.Sprintf("HEX(%v) >= '%v'", sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.Start))
 			}
 		} else {
 			if len(keyRange.End) > 0 {
 				// have end only
-				where = fmt.Sprintf("WHERE HEX(%v) < '%v'", sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.End))
+				where = fmt.Sprintf("HEX(%v) < '%v'", sqlescape.EscapeID(shardingColumnName), hex.EncodeToString(keyRange.End))
 			}
 		}
 	default:
-		return nil, fmt.Errorf("Unsupported ShardingColumnType: %v", shardingColumnType)
+		return "", fmt.Errorf("Unsupported ShardingColumnType: %v", shardingColumnType)
 	}
+	return where, nil
+}
 
-	sql := fmt.Sprintf("SELECT %v FROM %v %v", strings.Join(escapeAll(orderedColumns(td)), ", "), sqlescape.EscapeID(td.Name), where)
-	if len(td.PrimaryKeyColumns) > 0 {
-		sql += fmt.Sprintf(" ORDER BY %v", strings.Join(escapeAll(td.PrimaryKeyColumns), ", "))
+// joinConditions returns the conjunction of all non-empty "conditions".
+func joinConditions(conditions ...string) string {
+	var nonEmpty []string
+	for _, c := range conditions {
+		if c != "" {
+			nonEmpty = append(nonEmpty, c)
+		}
 	}
-	log.Infof("SQL query for %v/%v: %v", topoproto.TabletAliasString(tabletAlias), td.Name, sql)
-	return NewQueryResultReaderForTablet(ctx, ts, tabletAlias, sql)
+	return strings.Join(nonEmpty, " AND ")
+}
+
+// parenthesize wraps a non-empty, user provided "condition" in parentheses
+// such that it can be combined with other conditions.
+func parenthesize(condition string) string {
+	if condition == "" {
+		return ""
+	}
+	return "(" + condition + ")"
+}
+
+// sampleCondition returns a condition which matches a deterministic,
+// pseudo-random subset of "percent" percent of the rows of "td".
+// The subset is selected by the checksum of the primary key. Therefore, the
+// same rows are selected on all tablets. If "td" has no primary key, all
+// columns are used instead. It returns an empty string for 100 percent.
+func sampleCondition(td *tabletmanagerdatapb.TableDefinition, percent float64) string {
+	if percent >= 100 {
+		return ""
+	}
+	columns := td.PrimaryKeyColumns
+	if len(columns) == 0 {
+		columns = td.Columns
+	}
+	return fmt.Sprintf("CRC32(CONCAT_WS('#', %v)) %% 1000000 < %v", strings.Join(escapeAll(columns), ", "), int64(percent*10000))
 }
 
 // ErrStoppedRowReader is returned by RowReader.Next() when
@@ -286,6 +429,9 @@ type RowReader struct {
 	currentResult          *sqltypes.Result
 	currentIndex           int
 	stopAfterCurrentResult bool
+	// readRows is optional. If set, it is called with the number of rows of
+	// each result which was read from resultReader.
+	readRows func(rows int)
 }
 
 // NewRowReader returns a RowReader based on the QueryResultReader
@@ -314,6 +460,9 @@ func (rr *RowReader) Next() ([]sqltypes.Value, error) {
 			return nil, nil
 		}
 		rr.currentIndex = 0
+		if rr.readRows != nil {
+			rr.readRows(len(rr.currentResult.Rows))
+		}
 	}
 	row := rr.currentResult.Rows[rr.currentIndex]
 	rr.currentIndex++
@@ -360,26 +509,84 @@ type DiffReport struct {
 	mismatchedRows int
 	extraRowsLeft  int
 	extraRowsRight int
+	// hashMismatchedRows is the number of mismatched rows which have a
+	// different hash in a column which was hashed (--hash_columns_larger_than).
+	hashMismatchedRows int
+
+	// differentRows has the first different rows of each DiffType (up to
+	// --diff_report_sample_rows each).
+	differentRows []differentRow
+	// columns are the names of the columns of the rows in differentRows.
+	columns []string
 
 	// QPS variables and stats
 	startingTime  time.Time
+	duration      time.Duration
 	processingQPS int
 }
 
+var diffReportSampleRows = flag.Int("diff_report_sample_rows", 10, "maximum number of example rows which are recorded for each type of difference (missing, not equal, extraneous) in the diff report and shown on the status page of a diff worker")
+
+// differentRow records a row which was found different.
+type differentRow struct {
+	diffType   DiffType
+	primaryKey []sqltypes.Value
+	// sourceRow is not set for DiffExtraneous.
+	sourceRow []sqltypes.Value
+	// destinationRow is not set for DiffMissing.
+	destinationRow []sqltypes.Value
+}
+
 // HasDifferences returns true if the diff job recorded any difference
 func (dr *DiffReport) HasDifferences() bool {
 	return dr.mismatchedRows > 0 || dr.extraRowsLeft > 0 || dr.extraRowsRight > 0
 }
 
-// ComputeQPS fills in processingQPS
+// ComputeQPS fills in processingQPS and duration
 func (dr *DiffReport) ComputeQPS() {
+	dr.duration = time.Now().Sub(dr.startingTime)
 	if dr.processedRows > 0 {
-		dr.processingQPS = int(time.Duration(dr.processedRows) * time.Second / time.Now().Sub(dr.startingTime))
+		dr.processingQPS = int(time.Duration(dr.processedRows) * time.Second / dr.duration)
+	}
+}
+
+// merge adds the counts and the different rows of "other" to the report.
+func (dr *DiffReport) merge(other DiffReport) {
+	dr.processedRows += other.processedRows
+	dr.matchingRows += other.matchingRows
+	dr.mismatchedRows += other.mismatchedRows
+	dr.extraRowsLeft += other.extraRowsLeft
+	dr.extraRowsRight += other.extraRowsRight
+	dr.hashMismatchedRows += other.hashMismatchedRows
+	for _, r := range other.differentRows {
+		dr.addDifferentRow(r)
+	}
+	if dr.columns == nil {
+		dr.columns = other.columns
 	}
 }
 
+// addDifferentRow records "r" unless the report already has
+// --diff_report_sample_rows rows of the same DiffType.
+func (dr *DiffReport) addDifferentRow(r differentRow) {
+	count := 0
+	for _, other := range dr.differentRows {
+		if other.diffType == r.diffType {
+			count++
+		}
+	}
+	if count >= *diffReportSampleRows {
+		return
+	}
+	dr.differentRows = append(dr.differentRows, r)
+}
+
 func (dr *DiffReport) String() string {
-	return fmt.Sprintf("DiffReport{%v processed, %v matching, %v mismatched, %v extra left, %v extra right, %v q/s}", dr.processedRows, dr.matchingRows, dr.mismatchedRows, dr.extraRowsLeft, dr.extraRowsRight, dr.processingQPS)
+	mismatched := fmt.Sprintf("%v", dr.mismatchedRows)
+	if dr.hashMismatchedRows > 0 {
+		mismatched = fmt.Sprintf("%v (%v by hash)", dr.mismatchedRows, dr.hashMismatchedRows)
+	}
+	return fmt.Sprintf("DiffReport{%v processed, %v matching, %v mismatched, %v extra left, %v extra right, %v q/s}", dr.processedRows, dr.matchingRows, mismatched, dr.extraRowsLeft, dr.extraRowsRight, dr.processingQPS)
 }
 
 // RowsEqual returns the index of the first different column, or -1 if
@@ -401,9 +608,27 @@ func RowsEqual(left, right []sqltypes.Value) int {
 // row.
 // TODO: This can panic if types for left and right don't match.
 func CompareRows(fields []*querypb.Field, compareCount int, left, right []sqltypes.Value) (int, error) {
+	return compareRowsCollated(fields, nil /* collators */, compareCount, left, right)
+}
+
+// compareRowsCollated is like CompareRows but compares the text values of a
+// column with the collator at the same index in "collators", if there is one.
+// See pkCollators().
+func compareRowsCollated(fields []*querypb.Field, collators []*collate.Collator, compareCount int, left, right []sqltypes.Value) (int, error) {
 	for i := 0; i < compareCount; i++ {
 		lv, _ := sqltypes.ToNative(left[i])
 		rv, _ := sqltypes.ToNative(right[i])
+		// NULL sorts first, the same as in MySQL. Only columns of tables
+		// without a primary key can be NULL (see diffTableDefinition()).
+		if lv == nil || rv == nil {
+			if lv == nil && rv == nil {
+				continue
+			}
+			if lv == nil {
+				return -1, nil
+			}
+			return 1, nil
+		}
 		switch l := lv.(type) {
 		case int64:
 			r := rv.(int64)
@@ -428,7 +653,15 @@ func CompareRows(fields []*querypb.Field, compareCount int, left, right []sqltyp
 			}
 		case []byte:
 			r := rv.([]byte)
-			return bytes.Compare(l, r), nil
+			var c int
+			if i < len(collators) && collators[i] != nil {
+				c = compareCollated(collators[i], l, r)
+			} else {
+				c = bytes.Compare(l, r)
+			}
+			if c != 0 {
+				return c, nil
+			}
 		default:
 			return 0, fmt.Errorf("Unsuported type %T returned by mysql.proto.Convert", l)
 		}
@@ -443,10 +676,25 @@ type RowDiffer struct {
 	left         *RowReader
 	right        *RowReader
 	pkFieldCount int
+	// collators has an entry for each primary key column which must be
+	// compared according to its case-insensitive collation. See pkCollators().
+	collators []*collate.Collator
+	// floatEpsilon is optional. If > 0, rows whose FLOAT and DOUBLE values
+	// differ by at most this amount are considered equal.
+	floatEpsilon float64
+	// repairer is optional. If set, it gets all rows which are different.
+	repairer *rowRepairer
+	// results is optional. If set, it records the primary key of all rows
+	// which are different.
+	results *diffResultsRecorder
+	// tableStatusList is optional. If set, the number of processed rows is
+	// reported to it for the table at tableIndex.
+	tableStatusList *tableStatusList
+	tableIndex      int
 }
 
 // NewRowDiffer returns a new RowDiffer
-func NewRowDiffer(left, right *QueryResultReader, tableDefinition *tabletmanagerdatapb.TableDefinition) (*RowDiffer, error) {
+func NewRowDiffer(left, right ResultReader, tableDefinition *tabletmanagerdatapb.TableDefinition) (*RowDiffer, error) {
 	leftFields := left.Fields()
 	rightFields := right.Fields()
 	if len(leftFields) != len(rightFields) {
@@ -457,10 +705,15 @@ func NewRowDiffer(left, right *QueryResultReader, tableDefinition *tabletmanager
 			return nil, fmt.Errorf("Cannot diff inputs with different types: field %v types are %v and %v", i, field.Type, rightFields[i].Type)
 		}
 	}
+	collators, err := pkCollators(tableDefinition, leftFields)
+	if err != nil {
+		return nil, err
+	}
 	return &RowDiffer{
 		left:         NewRowReader(left),
 		right:        NewRowReader(right),
 		pkFieldCount: len(tableDefinition.PrimaryKeyColumns),
+		collators:    collators,
 	}, nil
 }
 
@@ -471,6 +724,14 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 	dr.startingTime = time.Now()
 	defer dr.ComputeQPS()
 
+	for _, field := range rd.left.Fields() {
+		dr.columns = append(dr.columns, field.Name)
+	}
+	if rd.tableStatusList != nil {
+		rd.left.readRows = rd.addReadRows
+		rd.right.readRows = rd.addReadRows
+	}
+
 	var left []sqltypes.Value
 	var right []sqltypes.Value
 	advanceLeft := true
@@ -491,6 +752,9 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 			advanceRight = false
 		}
 		dr.processedRows++
+		if rd.tableStatusList != nil {
+			rd.tableStatusList.addCopiedRows(rd.tableIndex, 1)
+		}
 		if left == nil {
 			// no more rows from the left
 			if right == nil {
@@ -500,7 +764,7 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 
 			// drain right, update count
 			log.Errorf("Draining extra row(s) found on the right starting with: %v", right)
-			if count, err := rd.right.Drain(); err != nil {
+			if count, err := rd.drain(&dr, rd.right, right, DiffExtraneous); err != nil {
 				return dr, err
 			} else {
 				dr.extraRowsRight += 1 + count
@@ -511,7 +775,7 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 			// no more rows from the right
 			// we know we have rows from left, drain, update count
 			log.Errorf("Draining extra row(s) found on the left starting with: %v", left)
-			if count, err := rd.left.Drain(); err != nil {
+			if count, err := rd.drain(&dr, rd.left, left, DiffMissing); err != nil {
 				return dr, err
 			} else {
 				dr.extraRowsLeft += 1 + count
@@ -521,6 +785,9 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 
 		// we have both left and right, compare
 		f := RowsEqual(left, right)
+		if f >= rd.pkFieldCount && rd.floatEpsilon > 0 && withinFloatEpsilon(rd.left.Fields(), left, right, f, rd.floatEpsilon) {
+			f = -1
+		}
 		if f == -1 {
 			// rows are the same, next
 			dr.matchingRows++
@@ -531,17 +798,15 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 
 		if f >= rd.pkFieldCount {
 			// rows have the same primary key, only content is different
-			if dr.mismatchedRows < 10 {
-				log.Errorf("Different content %v in same PK: %v != %v", dr.mismatchedRows, left, right)
-			}
-			dr.mismatchedRows++
+			rd.recordMismatch(log, &dr, left, right, f)
+			rd.recordDifference(&dr, left, DiffNotEqual)
 			advanceLeft = true
 			advanceRight = true
 			continue
 		}
 
 		// have to find the 'smallest' row and advance it
-		c, err := CompareRows(rd.left.Fields(), rd.pkFieldCount, left, right)
+		c, err := compareRowsCollated(rd.left.Fields(), rd.collators, rd.pkFieldCount, left, right)
 		if err != nil {
 			return dr, err
 		}
@@ -550,6 +815,7 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 				log.Errorf("Extra row %v on left: %v", dr.extraRowsLeft, left)
 			}
 			dr.extraRowsLeft++
+			rd.recordDifference(&dr, left, DiffMissing)
 			advanceLeft = true
 			continue
 		} else if c > 0 {
@@ -557,6 +823,7 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 				log.Errorf("Extra row %v on right: %v", dr.extraRowsRight, right)
 			}
 			dr.extraRowsRight++
+			rd.recordDifference(&dr, right, DiffExtraneous)
 			advanceRight = true
 			continue
 		}
@@ -564,11 +831,140 @@ func (rd *RowDiffer) Go(log logutil.Logger) (dr DiffReport, err error) {
 		// After looking at primary keys more carefully,
 		// they're the same. Logging a regular difference
 		// then, and advancing both.
-		if dr.mismatchedRows < 10 {
-			log.Errorf("Different content %v in same PK: %v != %v", dr.mismatchedRows, left, right)
-		}
-		dr.mismatchedRows++
+		rd.recordMismatch(log, &dr, left, right, f)
+		rd.recordDifference(&dr, left, DiffNotEqual)
 		advanceLeft = true
 		advanceRight = true
 	}
 }
+
+// recordMismatch counts and logs a row whose content is different on both
+// sides. "first" is the index of the first different column.
+func (rd *RowDiffer) recordMismatch(log logutil.Logger, dr *DiffReport, left, right []sqltypes.Value, first int) {
+	hashMismatch := hasHashMismatch(left, right, first)
+	if dr.mismatchedRows < 10 {
+		if hashMismatch {
+			log.Errorf("Different content hash %v in same PK: %v != %v", dr.mismatchedRows, left, right)
+		} else {
+			log.Errorf("Different content %v in same PK: %v != %v", dr.mismatchedRows, left, right)
+		}
+	}
+	dr.mismatchedRows++
+	if hashMismatch {
+		dr.hashMismatchedRows++
+	}
+	dr.addDifferentRow(differentRow{
+		diffType:       DiffNotEqual,
+		primaryKey:     left[:rd.pkFieldCount],
+		sourceRow:      left,
+		destinationRow: right,
+	})
+}
+
+// recordDifference records the results, if any, and passes the row to the
+// repairer, if any. Example rows of DiffMissing and DiffExtraneous are
+// recorded in the report as well. recordMismatch() does it for DiffNotEqual.
+func (rd *RowDiffer) recordDifference(dr *DiffReport, row []sqltypes.Value, typ DiffType) {
+	switch typ {
+	case DiffMissing:
+		dr.addDifferentRow(differentRow{
+			diffType:   typ,
+			primaryKey: row[:rd.pkFieldCount],
+			sourceRow:  row,
+		})
+	case DiffExtraneous:
+		dr.addDifferentRow(differentRow{
+			diffType:       typ,
+			primaryKey:     row[:rd.pkFieldCount],
+			destinationRow: row,
+		})
+	}
+	if rd.repairer != nil {
+		rd.repairer.add(row, typ)
+	}
+	rd.results.add(row[:rd.pkFieldCount], typ)
+}
+
+// addReadRows reports the rows read by either side to the tableStatusList.
+func (rd *RowDiffer) addReadRows(rows int) {
+	rd.tableStatusList.addReadRows(rd.tableIndex, rows)
+}
+
+// drain empties "rr" and returns how many rows were left after "first".
+// Unlike RowReader.Drain(), it records each row as different.
+func (rd *RowDiffer) drain(dr *DiffReport, rr *RowReader, first []sqltypes.Value, typ DiffType) (int, error) {
+	rd.recordDifference(dr, first, typ)
+	count := 0
+	for {
+		row, err := rr.Next()
+		if err != nil {
+			return 0, err
+		}
+		if row == nil {
+			return count, nil
+		}
+		rd.recordDifference(dr, row, typ)
+		count++
+	}
+}
+
+// diffChunksInParallel runs "diffChunk" for all "chunks" concurrently and
+// returns the merged report of all chunks. If a chunk fails, the first error
+// is returned together with the report of the rows which were diffed so far.
+func diffChunksInParallel(sw *StatusWorker, chunks []chunk, diffChunk func(c chunk) (DiffReport, error)) (DiffReport, error) {
+	var report DiffReport
+	report.startingTime = time.Now()
+
+	var mu sync.Mutex
+	rec := &concurrency.FirstErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for _, c := range chunks {
+		wg.Add(1)
+		go func(c chunk) {
+			defer wg.Done()
+			defer sw.recoverPanic(rec.RecordError)
+			chunkReport, err := diffChunk(c)
+			mu.Lock()
+			report.merge(chunkReport)
+			mu.Unlock()
+			rec.RecordError(err)
+		}(c)
+	}
+	wg.Wait()
+	report.ComputeQPS()
+	return report, rec.Error()
+}
+
+// retryTableDiff runs "diffTable" until it succeeds or "retryCount" retries
+// have failed. The delay before the first retry is "backoff". It doubles
+// after each retry. "diffTable" must re-establish all readers which it uses.
+func retryTableDiff(ctx context.Context, logger logutil.Logger, table string, retryCount int, backoff time.Duration, diffTable func() (DiffReport, error)) (DiffReport, error) {
+	for attempt := 1; ; attempt++ {
+		report, err := diffTable()
+		if err == nil || attempt > retryCount {
+			return report, err
+		}
+		if checkDone(ctx) != nil {
+			return report, err
+		}
+
+		logger.Warningf("table=%v: diff failed at attempt %v out of %v. Retrying in %v. Error: %v", table, attempt, retryCount+1, backoff, err)
+		statsRetryCounters.Add("TableDiff", 1)
+		select {
+		case <-ctx.Done():
+			return report, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// checkTablesMatched returns an error if --tables is set but no table of "sd"
+// matches it. Otherwise, a typo in --tables would result in a successful diff
+// which did not compare any table.
+func checkTablesMatched(tables []string, sd *tabletmanagerdatapb.SchemaDefinition) error {
+	if len(tables) > 0 && len(sd.TableDefinitions) == 0 {
+		return fmt.Errorf("no table matches --tables=%v", strings.Join(tables, ","))
+	}
+	return nil
+}
@@ -18,10 +18,16 @@ package worker
 
 import (
 	"encoding/hex"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -125,6 +131,30 @@ func TestCompareRows(t *testing.T) {
 			right:  []sqltypes.Value{sqltypes.NewVarBinary("abd")},
 			want:   -1,
 		},
+		{
+			fields: []*querypb.Field{
+				{Name: "a", Type: sqltypes.VarBinary},
+				{Name: "b", Type: sqltypes.Int32},
+			},
+			left:  []sqltypes.Value{sqltypes.NewVarBinary("abc"), sqltypes.NewInt32(2)},
+			right: []sqltypes.Value{sqltypes.NewVarBinary("abc"), sqltypes.NewInt32(1)},
+			want:  1,
+		},
+		{
+			fields: []*querypb.Field{
+				{Name: "a", Type: sqltypes.Int32},
+				{Name: "b", Type: sqltypes.VarBinary},
+			},
+			left:  []sqltypes.Value{sqltypes.NewInt32(1), sqltypes.NULL},
+			right: []sqltypes.Value{sqltypes.NewInt32(1), sqltypes.NewVarBinary("")},
+			want:  -1,
+		},
+		{
+			fields: []*querypb.Field{{Name: "a", Type: sqltypes.VarBinary}},
+			left:   []sqltypes.Value{sqltypes.NULL},
+			right:  []sqltypes.Value{sqltypes.NULL},
+			want:   0,
+		},
 	}
 	for _, tc := range table {
 		got, err := CompareRows(tc.fields, len(tc.fields), tc.left, tc.right)
@@ -137,3 +167,230 @@ func TestCompareRows(t *testing.T) {
 		}
 	}
 }
+
+func TestDiffTableDefinition(t *testing.T) {
+	withPK := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"id", "msg"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+	if got, err := diffTableDefinition(withPK, false, nil); err != nil || got != withPK {
+		t.Errorf("diffTableDefinition() = (%v, %v), want the unchanged table definition", got, err)
+	}
+
+	pkless := &tabletmanagerdatapb.TableDefinition{
+		Name:    "t2",
+		Columns: []string{"msg", "count"},
+	}
+	if _, err := diffTableDefinition(pkless, false, nil); err == nil {
+		t.Error("diffTableDefinition() must fail for a table without a primary key")
+	}
+	got, err := diffTableDefinition(pkless, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"msg", "count"}; !reflect.DeepEqual(got.PrimaryKeyColumns, want) {
+		t.Errorf("diffTableDefinition() primary key = %v, want = %v", got.PrimaryKeyColumns, want)
+	}
+	if len(pkless.PrimaryKeyColumns) != 0 {
+		t.Errorf("diffTableDefinition() must not modify its input: %v", pkless)
+	}
+
+	got, err = diffTableDefinition(withPK, false, []string{"MSG"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"id"}; !reflect.DeepEqual(got.Columns, want) {
+		t.Errorf("diffTableDefinition() with ignored columns = %v, want = %v", got.Columns, want)
+	}
+	if want := []string{"id", "msg"}; !reflect.DeepEqual(withPK.Columns, want) {
+		t.Errorf("diffTableDefinition() must not modify its input: %v", withPK)
+	}
+	got, err = diffTableDefinition(pkless, true, []string{"count"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"msg"}; !reflect.DeepEqual(got.PrimaryKeyColumns, want) {
+		t.Errorf("diffTableDefinition() primary key with ignored columns = %v, want = %v", got.PrimaryKeyColumns, want)
+	}
+	if _, err := diffTableDefinition(withPK, false, []string{"id"}); err == nil {
+		t.Error("diffTableDefinition() must fail for an ignored primary key column")
+	}
+	if _, err := diffTableDefinition(withPK, false, []string{"unknown"}); err == nil {
+		t.Error("diffTableDefinition() must fail for an ignored column which does not exist")
+	}
+}
+
+func TestParseIgnoreColumns(t *testing.T) {
+	got, err := parseIgnoreColumns("t1:last_seen, counter; t2:updated;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"t1": {"last_seen", "counter"},
+		"t2": {"updated"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIgnoreColumns() = %v, want = %v", got, want)
+	}
+
+	if got, err := parseIgnoreColumns(""); err != nil || len(got) != 0 {
+		t.Errorf("parseIgnoreColumns(\"\") = (%v, %v), want an empty map", got, err)
+	}
+	for _, value := range []string{"t1", ":a", "t1:"} {
+		if _, err := parseIgnoreColumns(value); err == nil {
+			t.Errorf("parseIgnoreColumns(%q) must fail", value)
+		}
+	}
+}
+
+func TestDiffChunksInParallel(t *testing.T) {
+	chunks := []chunk{
+		{sqltypes.NULL, sqltypes.NewInt64(10), 1, 3},
+		{sqltypes.NewInt64(10), sqltypes.NewInt64(20), 2, 3},
+		{sqltypes.NewInt64(20), sqltypes.NULL, 3, 3},
+	}
+	sw := NewStatusWorker()
+	report, err := diffChunksInParallel(&sw, chunks, func(c chunk) (DiffReport, error) {
+		dr := DiffReport{
+			processedRows: 10,
+			matchingRows:  10,
+		}
+		if c.number == 2 {
+			dr.matchingRows = 9
+			dr.mismatchedRows = 1
+			dr.differentRows = []differentRow{{diffType: DiffNotEqual, primaryKey: []sqltypes.Value{sqltypes.NewInt64(15)}}}
+		}
+		return dr, nil
+	})
+	if err != nil {
+		t.Fatalf("diffChunksInParallel() failed: %v", err)
+	}
+	if report.processedRows != 30 || report.matchingRows != 29 || report.mismatchedRows != 1 || len(report.differentRows) != 1 {
+		t.Errorf("diffChunksInParallel() returned wrong merged report: %v", report.String())
+	}
+
+	wantErr := errors.New("chunk failed")
+	report, err = diffChunksInParallel(&sw, chunks, func(c chunk) (DiffReport, error) {
+		if c.number == 3 {
+			return DiffReport{processedRows: 5}, wantErr
+		}
+		return DiffReport{processedRows: 10, matchingRows: 10}, nil
+	})
+	if err != wantErr {
+		t.Errorf("diffChunksInParallel() = %v, want error: %v", err, wantErr)
+	}
+	if report.processedRows != 25 {
+		t.Errorf("diffChunksInParallel() must return the rows of the failed chunk as well: %v", report.String())
+	}
+
+	// A panic in one chunk must fail the diff instead of crashing vtworker.
+	_, err = diffChunksInParallel(&sw, chunks, func(c chunk) (DiffReport, error) {
+		if c.number == 2 {
+			panic("chunk panicked")
+		}
+		return DiffReport{processedRows: 10, matchingRows: 10}, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "uncaught vtworker panic: chunk panicked") {
+		t.Errorf("diffChunksInParallel() = %v, want the recovered panic as error", err)
+	}
+	if status := sw.StatusAsText(); !strings.Contains(status, "Panic:\n") {
+		t.Errorf("StatusAsText() must include the stack trace of the panic: %v", status)
+	}
+}
+
+func TestDiffReportDifferentRowsLimit(t *testing.T) {
+	defer func(n int) { *diffReportSampleRows = n }(*diffReportSampleRows)
+	*diffReportSampleRows = 2
+
+	var report DiffReport
+	for i := 0; i < 3; i++ {
+		var chunkReport DiffReport
+		chunkReport.addDifferentRow(differentRow{diffType: DiffMissing, primaryKey: []sqltypes.Value{sqltypes.NewInt64(int64(i))}})
+		chunkReport.addDifferentRow(differentRow{diffType: DiffExtraneous, primaryKey: []sqltypes.Value{sqltypes.NewInt64(int64(10 + i))}})
+		report.merge(chunkReport)
+	}
+	report.addDifferentRow(differentRow{diffType: DiffNotEqual, primaryKey: []sqltypes.Value{sqltypes.NewInt64(20)}})
+
+	counts := make(map[DiffType]int)
+	for _, r := range report.differentRows {
+		counts[r.diffType]++
+	}
+	want := map[DiffType]int{DiffMissing: 2, DiffExtraneous: 2, DiffNotEqual: 1}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("different rows per type = %v, want = %v", counts, want)
+	}
+}
+
+func TestRetryTableDiff(t *testing.T) {
+	ctx := context.Background()
+	logger := logutil.NewMemoryLogger()
+	transientErr := errors.New("tablet restarted")
+
+	// The diff succeeds at the third attempt.
+	attempts := 0
+	report, err := retryTableDiff(ctx, logger, "t1", 2 /* retryCount */, time.Millisecond, func() (DiffReport, error) {
+		attempts++
+		if attempts < 3 {
+			return DiffReport{}, transientErr
+		}
+		return DiffReport{processedRows: 10}, nil
+	})
+	if err != nil || attempts != 3 || report.processedRows != 10 {
+		t.Errorf("retryTableDiff() = (%v, %v) after %v attempts, want success after 3 attempts", report.String(), err, attempts)
+	}
+
+	// All attempts fail.
+	attempts = 0
+	_, err = retryTableDiff(ctx, logger, "t1", 1 /* retryCount */, time.Millisecond, func() (DiffReport, error) {
+		attempts++
+		return DiffReport{}, transientErr
+	})
+	if err != transientErr || attempts != 2 {
+		t.Errorf("retryTableDiff() = %v after %v attempts, want error %v after 2 attempts", err, attempts, transientErr)
+	}
+}
+
+func TestJoinConditions(t *testing.T) {
+	testcases := []struct {
+		conditions []string
+		want       string
+	}{
+		{nil, ""},
+		{[]string{"", ""}, ""},
+		{[]string{"`keyspace_id` < 128", ""}, "`keyspace_id` < 128"},
+		{[]string{"`keyspace_id` < 128", parenthesize("a = 1 OR b = 2")}, "`keyspace_id` < 128 AND (a = 1 OR b = 2)"},
+	}
+	for _, tc := range testcases {
+		if got := joinConditions(tc.conditions...); got != tc.want {
+			t.Errorf("joinConditions(%q) = %v, want = %v", tc.conditions, got, tc.want)
+		}
+	}
+}
+
+func TestSampleCondition(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"id", "msg"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+	noPK := &tabletmanagerdatapb.TableDefinition{
+		Name:    "t2",
+		Columns: []string{"id", "msg"},
+	}
+	testcases := []struct {
+		td      *tabletmanagerdatapb.TableDefinition
+		percent float64
+		want    string
+	}{
+		{td, 100, ""},
+		{td, 10, "CRC32(CONCAT_WS('#', `id`)) % 1000000 < 100000"},
+		{td, 0.5, "CRC32(CONCAT_WS('#', `id`)) % 1000000 < 5000"},
+		{noPK, 1, "CRC32(CONCAT_WS('#', `id`, `msg`)) % 1000000 < 10000"},
+	}
+	for _, tc := range testcases {
+		if got := sampleCondition(tc.td, tc.percent); got != tc.want {
+			t.Errorf("sampleCondition(%v, %v) = %v, want = %v", tc.td.Name, tc.percent, got, tc.want)
+		}
+	}
+}
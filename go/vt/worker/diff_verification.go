@@ -0,0 +1,61 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+var recordDiffVerification = flag.Bool("record_diff_verification", true, "If true, the diff workers record the position and time of a successful diff in the topology next to the destination Shard record. MigrateServedTypes and MigrateServedFrom warn if a shard was never verified.")
+
+// verifiesShard returns true if a successful diff with these options
+// verifies the destination shard: It must compare all rows of all tables
+// and must not change the destination (--repair).
+func verifiesShard(tables []string, where string, samplePercent float64, repair bool) bool {
+	return len(tables) == 0 && where == "" && samplePercent >= 100 && !repair
+}
+
+// writeDiffVerification records that the destination shard
+// "keyspace/shard" was successfully compared against "sourceKeyspace/
+// sourceShard" at the replication position "position" of its master.
+// A failure is only logged because the diff itself succeeded.
+func writeDiffVerification(ctx context.Context, wr *wrangler.Wrangler, command, keyspace, shard, sourceKeyspace, sourceShard, position string) {
+	if !*recordDiffVerification {
+		return
+	}
+
+	verification := &topo.ShardDiffVerification{
+		Command:  command,
+		Source:   topoproto.KeyspaceShardString(sourceKeyspace, sourceShard),
+		Position: position,
+		Time:     time.Now(),
+	}
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	if err := wr.TopoServer().UpdateShardDiffVerification(shortCtx, keyspace, shard, verification); err != nil {
+		wr.Logger().Warningf("Cannot record the diff verification of shard %v/%v: %v", keyspace, shard, err)
+		return
+	}
+	wr.Logger().Infof("Recorded the diff verification of shard %v/%v at position %v", keyspace, shard, position)
+}
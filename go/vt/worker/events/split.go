@@ -35,3 +35,13 @@ type VerticalSplitClone struct {
 	Keyspace, Shard, Cell string
 	Tables                []string
 }
+
+// TableMigrate is an event that describes a single step in a copy of tables
+// from one shard to another.
+type TableMigrate struct {
+	base.StatusUpdater
+
+	SourceKeyspace, SourceShard string
+	Keyspace, Shard, Cell       string
+	Tables                      []string
+}
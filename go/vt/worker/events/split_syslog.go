@@ -35,5 +35,12 @@ func (ev *VerticalSplitClone) Syslog() (syslog.Priority, string) {
 		ev.Keyspace, ev.Shard, ev.Cell, ev.Status)
 }
 
+// Syslog writes a TableMigrate event to syslog.
+func (ev *TableMigrate) Syslog() (syslog.Priority, string) {
+	return syslog.LOG_INFO, fmt.Sprintf("%s/%s/%s [table migrate from %s/%s] %s",
+		ev.Keyspace, ev.Shard, ev.Cell, ev.SourceKeyspace, ev.SourceShard, ev.Status)
+}
+
 var _ syslogger.Syslogger = (*SplitClone)(nil)         // compile-time interface check
 var _ syslogger.Syslogger = (*VerticalSplitClone)(nil) // compile-time interface check
+var _ syslogger.Syslogger = (*TableMigrate)(nil)       // compile-time interface check
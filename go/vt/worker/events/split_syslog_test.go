@@ -58,3 +58,23 @@ func TestVerticalSplitCloneSyslog(t *testing.T) {
 		t.Errorf("wrong message: got %v, want %v", gotMsg, wantMsg)
 	}
 }
+
+func TestTableMigrateSyslog(t *testing.T) {
+	wantSev, wantMsg := syslog.LOG_INFO, "keyspace-123/shard-123/cell-1 [table migrate from keyspace-456/shard-456] status"
+	ev := &TableMigrate{
+		Cell:           "cell-1",
+		SourceKeyspace: "keyspace-456",
+		SourceShard:    "shard-456",
+		Keyspace:       "keyspace-123",
+		Shard:          "shard-123",
+		StatusUpdater:  base.StatusUpdater{Status: "status"},
+	}
+	gotSev, gotMsg := ev.Syslog()
+
+	if gotSev != wantSev {
+		t.Errorf("wrong severity: got %v, want %v", gotSev, wantSev)
+	}
+	if gotMsg != wantMsg {
+		t.Errorf("wrong message: got %v, want %v", gotMsg, wantMsg)
+	}
+}
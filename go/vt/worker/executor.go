@@ -17,14 +17,17 @@ limitations under the License.
 package worker
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 
 	"vitess.io/vitess/go/vt/vterrors"
 
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/throttler"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/wrangler"
@@ -33,6 +36,22 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
+// writeQuery is a statement which is sent to the writer threads (see
+// executor.fetchLoop()) of a destination shard.
+type writeQuery struct {
+	sql string
+	// mergeHead is set if "sql" is a multi-row INSERT which ends with its
+	// last row e.g. "INSERT INTO t (a, b) VALUES " for
+	// "INSERT INTO t (a, b) VALUES (1, 2),(3, 4)". The rows of queries with
+	// the same mergeHead can be written in one statement.
+	mergeHead string
+	// rows is the number of rows in the statement.
+	rows int
+	// writes is optional. If set, the executor reports to it once the
+	// statement was executed on the destination.
+	writes *chunkWrites
+}
+
 // executor takes care of the write-side of the copy.
 // There is one executor for each destination shard and writer thread.
 // To-be-written data will be passed in through a channel.
@@ -49,43 +68,123 @@ type executor struct {
 	// statsKey is the cached metric key which we need when we increment the stats
 	// variable when we get throttled.
 	statsKey []string
+
+	// writeLimiter limits the number of bytes per second which fetchLoop sends
+	// to the destination. It is shared by all executors of a worker.
+	// nil means unlimited.
+	writeLimiter *rate.Limiter
+	// compression is the gRPC compressor for the writes of fetchLoop.
+	// Empty means that --grpc_compression is used.
+	compression string
+	// transactionMaxRows and transactionMaxSize limit how many rows of
+	// queued INSERT queries fetchLoop merges into one statement and
+	// therefore one transaction. 0 rows disables the merging i.e. each
+	// query is a transaction of its own.
+	transactionMaxRows int
+	transactionMaxSize int
 }
 
-func newExecutor(wr *wrangler.Wrangler, tsc *discovery.TabletStatsCache, throttler *throttler.Throttler, keyspace, shard string, threadID int) *executor {
+func newExecutor(wr *wrangler.Wrangler, tsc *discovery.TabletStatsCache, throttler *throttler.Throttler, writeLimiter *rate.Limiter, compression, keyspace, shard string, threadID, transactionMaxRows, transactionMaxSize int) *executor {
 	return &executor{
-		wr:        wr,
-		tsc:       tsc,
-		throttler: throttler,
-		keyspace:  keyspace,
-		shard:     shard,
-		threadID:  threadID,
-		statsKey:  []string{keyspace, shard, fmt.Sprint(threadID)},
+		wr:                 wr,
+		tsc:                tsc,
+		throttler:          throttler,
+		keyspace:           keyspace,
+		shard:              shard,
+		threadID:           threadID,
+		statsKey:           []string{keyspace, shard, fmt.Sprint(threadID)},
+		writeLimiter:       writeLimiter,
+		compression:        compression,
+		transactionMaxRows: transactionMaxRows,
+		transactionMaxSize: transactionMaxSize,
 	}
 }
 
 // fetchLoop loops over the provided insertChannel and sends the commands to the
 // current master.
-func (e *executor) fetchLoop(ctx context.Context, insertChannel chan string) error {
+func (e *executor) fetchLoop(ctx context.Context, insertChannel chan *writeQuery) error {
+	// next is a query which was read from insertChannel by merge() but
+	// could not be merged.
+	var next *writeQuery
 	for {
+		q := next
+		next = nil
+		if q == nil {
+			var ok bool
+			select {
+			case q, ok = <-insertChannel:
+				if !ok {
+					// no more to read, we're done
+					return nil
+				}
+			case <-ctx.Done():
+				// Doesn't really matter if this select gets starved, because the other case
+				// will also return an error due to executeFetch's context being closed. This case
+				// does prevent us from blocking indefinitely on insertChannel when the worker is canceled.
+				return nil
+			}
+		}
+
+		var cmd string
+		var merged []*writeQuery
+		cmd, merged, next = e.merge(q, insertChannel)
+		if err := waitN(ctx, e.writeLimiter, len(cmd)); err != nil {
+			// The context was canceled. Same as the ctx.Done() case above.
+			return nil
+		}
+		if err := e.fetchWithRetries(ctx, func(ctx context.Context, tablet *topodatapb.Tablet) error {
+			ctx = grpcclient.NewCompressionContext(ctx, e.compression)
+			_, err := e.wr.TabletManagerClient().ExecuteFetchAsApp(ctx, tablet, true, []byte(cmd), 0)
+			return err
+		}); err != nil {
+			return vterrors.Wrap(err, "ExecuteFetch failed")
+		}
+		for _, q := range merged {
+			if q.writes != nil {
+				q.writes.done()
+			}
+		}
+	}
+}
+
+// merge appends the rows of the INSERT queries, which are already queued in
+// insertChannel, to "q". It does not wait for new queries. This way, the
+// transactions become larger only if the destination cannot keep up.
+// It stops when transactionMaxRows or transactionMaxSize would be exceeded
+// or the next query cannot be merged. It returns the statement, the queries
+// which are part of it and the query which was read but not merged (or nil).
+func (e *executor) merge(q *writeQuery, insertChannel chan *writeQuery) (string, []*writeQuery, *writeQuery) {
+	merged := []*writeQuery{q}
+	if e.transactionMaxRows <= 0 || q.mergeHead == "" {
+		return q.sql, merged, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(q.sql)
+	rows := q.rows
+	for rows < e.transactionMaxRows {
 		select {
-		case cmd, ok := <-insertChannel:
+		case next, ok := <-insertChannel:
 			if !ok {
-				// no more to read, we're done
-				return nil
+				// fetchLoop will notice that the channel is closed.
+				return buf.String(), merged, nil
 			}
-			if err := e.fetchWithRetries(ctx, func(ctx context.Context, tablet *topodatapb.Tablet) error {
-				_, err := e.wr.TabletManagerClient().ExecuteFetchAsApp(ctx, tablet, true, []byte(cmd), 0)
-				return err
-			}); err != nil {
-				return vterrors.Wrap(err, "ExecuteFetch failed")
+			if next.mergeHead != q.mergeHead || rows+next.rows > e.transactionMaxRows {
+				return buf.String(), merged, next
 			}
-		case <-ctx.Done():
-			// Doesn't really matter if this select gets starved, because the other case
-			// will also return an error due to executeFetch's context being closed. This case
-			// does prevent us from blocking indefinitely on insertChannel when the worker is canceled.
-			return nil
+			values := next.sql[len(next.mergeHead):]
+			if buf.Len()+1+len(values) > e.transactionMaxSize {
+				return buf.String(), merged, next
+			}
+			buf.WriteString(",")
+			buf.WriteString(values)
+			rows += next.rows
+			merged = append(merged, next)
+		default:
+			return buf.String(), merged, nil
 		}
 	}
+	return buf.String(), merged, nil
 }
 
 func (e *executor) vreplicationExec(ctx context.Context, cmd string) (qr *sqltypes.Result, err error) {
@@ -0,0 +1,117 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import "testing"
+
+func TestExecutorMerge(t *testing.T) {
+	const head = "INSERT INTO `db`.`t` (`id`) VALUES "
+	insert := func(values string, rows int) *writeQuery {
+		return &writeQuery{sql: head + values, mergeHead: head, rows: rows}
+	}
+	update := &writeQuery{sql: "UPDATE `db`.`t` SET `msg`='a' WHERE `id`=1", rows: 1}
+
+	testcases := []struct {
+		desc     string
+		maxRows  int
+		maxSize  int
+		first    *writeQuery
+		queued   []*writeQuery
+		want     string
+		wantNext *writeQuery
+		// wantQueued is the number of queries which remain in the channel.
+		wantQueued int
+	}{{
+		desc:       "disabled",
+		maxRows:    0,
+		maxSize:    1024,
+		first:      insert("(1),(2)", 2),
+		queued:     []*writeQuery{insert("(3)", 1)},
+		want:       head + "(1),(2)",
+		wantQueued: 1,
+	}, {
+		desc:    "merge all queued inserts",
+		maxRows: 10,
+		maxSize: 1024,
+		first:   insert("(1),(2)", 2),
+		queued:  []*writeQuery{insert("(3)", 1), insert("(4),(5)", 2)},
+		want:    head + "(1),(2),(3),(4),(5)",
+	}, {
+		desc:       "max rows",
+		maxRows:    3,
+		maxSize:    1024,
+		first:      insert("(1),(2)", 2),
+		queued:     []*writeQuery{insert("(3)", 1), insert("(4)", 1)},
+		want:       head + "(1),(2),(3)",
+		wantQueued: 1,
+	}, {
+		desc:     "max size",
+		maxRows:  10,
+		maxSize:  len(head) + len("(1),(2)"),
+		first:    insert("(1)", 1),
+		queued:   []*writeQuery{insert("(2)", 1), insert("(3)", 1)},
+		want:     head + "(1),(2)",
+		wantNext: insert("(3)", 1),
+	}, {
+		desc:     "other statement",
+		maxRows:  10,
+		maxSize:  1024,
+		first:    insert("(1)", 1),
+		queued:   []*writeQuery{update, insert("(2)", 1)},
+		want:     head + "(1)",
+		wantNext: update,
+		// The INSERT after the UPDATE must not be merged because the order of
+		// the statements must be preserved.
+		wantQueued: 1,
+	}, {
+		desc:    "first statement cannot be merged",
+		maxRows: 10,
+		maxSize: 1024,
+		first:   update,
+		queued:  []*writeQuery{insert("(1)", 1)},
+		want:    update.sql,
+		// The channel is not read at all.
+		wantQueued: 1,
+	}}
+	for _, tc := range testcases {
+		insertChannel := make(chan *writeQuery, len(tc.queued))
+		for _, q := range tc.queued {
+			insertChannel <- q
+		}
+		e := newExecutor(nil /* wr */, nil /* tsc */, nil /* throttler */, nil /* writeLimiter */, "" /* compression */, "ks", "0", 0, tc.maxRows, tc.maxSize)
+
+		got, merged, next := e.merge(tc.first, insertChannel)
+		if got != tc.want {
+			t.Errorf("%v: merge() = %v, want = %v", tc.desc, got, tc.want)
+		}
+		// All queries which were read from the channel are part of the
+		// statement, except for the returned next query.
+		wantMerged := 1 + len(tc.queued) - tc.wantQueued
+		if tc.wantNext != nil {
+			wantMerged--
+		}
+		if len(merged) != wantMerged {
+			t.Errorf("%v: merge() merged %v queries, want = %v", tc.desc, len(merged), wantMerged)
+		}
+		if (next == nil) != (tc.wantNext == nil) || (next != nil && next.sql != tc.wantNext.sql) {
+			t.Errorf("%v: merge() returned the next query %+v, want = %+v", tc.desc, next, tc.wantNext)
+		}
+		if got := len(insertChannel); got != tc.wantQueued {
+			t.Errorf("%v: %v queries left in the channel, want = %v", tc.desc, got, tc.wantQueued)
+		}
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/vtctl/fakevtctlclient"
 	"vitess.io/vitess/go/vt/worker/vtworkerclient"
+
+	vtworkerdatapb "vitess.io/vitess/go/vt/proto/vtworkerdata"
 )
 
 // FakeVtworkerClient is a fake which implements the vtworkerclient interface.
@@ -55,5 +57,27 @@ func (c *perAddrFakeVtworkerClient) ExecuteVtworkerCommand(ctx context.Context,
 	return c.FakeLoggerEventStreamingClient.StreamResult(c.addr, args)
 }
 
+// GetStatus is part of the vtworkerclient interface.
+// The fake does not run any workers and therefore always reports an idle
+// vtworker.
+func (c *perAddrFakeVtworkerClient) GetStatus(ctx context.Context) (*vtworkerdatapb.GetStatusResponse, error) {
+	return &vtworkerdatapb.GetStatusResponse{}, nil
+}
+
+// Cancel is part of the vtworkerclient interface.
+func (c *perAddrFakeVtworkerClient) Cancel(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// Pause is part of the vtworkerclient interface.
+func (c *perAddrFakeVtworkerClient) Pause(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// Resume is part of the vtworkerclient interface.
+func (c *perAddrFakeVtworkerClient) Resume(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
 // Close is part of the vtworkerclient interface.
 func (c *perAddrFakeVtworkerClient) Close() {}
@@ -89,6 +89,42 @@ func (client *gRPCVtworkerClient) ExecuteVtworkerCommand(ctx context.Context, ar
 	return &eventStreamAdapter{stream}, nil
 }
 
+// GetStatus is part of the VtworkerClient interface.
+func (client *gRPCVtworkerClient) GetStatus(ctx context.Context) (*vtworkerdatapb.GetStatusResponse, error) {
+	response, err := client.c.GetStatus(ctx, &vtworkerdatapb.GetStatusRequest{})
+	if err != nil {
+		return nil, vterrors.FromGRPC(err)
+	}
+	return response, nil
+}
+
+// Cancel is part of the VtworkerClient interface.
+func (client *gRPCVtworkerClient) Cancel(ctx context.Context) (bool, error) {
+	response, err := client.c.Cancel(ctx, &vtworkerdatapb.CancelRequest{})
+	if err != nil {
+		return false, vterrors.FromGRPC(err)
+	}
+	return response.Canceled, nil
+}
+
+// Pause is part of the VtworkerClient interface.
+func (client *gRPCVtworkerClient) Pause(ctx context.Context) (bool, error) {
+	response, err := client.c.Pause(ctx, &vtworkerdatapb.PauseRequest{})
+	if err != nil {
+		return false, vterrors.FromGRPC(err)
+	}
+	return response.Paused, nil
+}
+
+// Resume is part of the VtworkerClient interface.
+func (client *gRPCVtworkerClient) Resume(ctx context.Context) (bool, error) {
+	response, err := client.c.Resume(ctx, &vtworkerdatapb.ResumeRequest{})
+	if err != nil {
+		return false, vterrors.FromGRPC(err)
+	}
+	return response.Resumed, nil
+}
+
 // Close is part of the VtworkerClient interface.
 func (client *gRPCVtworkerClient) Close() {
 	client.cc.Close()
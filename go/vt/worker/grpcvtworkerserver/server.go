@@ -23,6 +23,7 @@ package grpcvtworkerserver
 import (
 	"sync"
 
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/vt/logutil"
@@ -80,6 +81,30 @@ func (s *VtworkerServer) ExecuteVtworkerCommand(args *vtworkerdatapb.ExecuteVtwo
 	return vterrors.ToGRPC(err)
 }
 
+// GetStatus is part of the vtworkerdatapb.VtworkerServer interface
+func (s *VtworkerServer) GetStatus(ctx context.Context, request *vtworkerdatapb.GetStatusRequest) (response *vtworkerdatapb.GetStatusResponse, err error) {
+	defer servenv.HandlePanic("vtworker", &err)
+	return s.wi.Status(), nil
+}
+
+// Cancel is part of the vtworkerdatapb.VtworkerServer interface
+func (s *VtworkerServer) Cancel(ctx context.Context, request *vtworkerdatapb.CancelRequest) (response *vtworkerdatapb.CancelResponse, err error) {
+	defer servenv.HandlePanic("vtworker", &err)
+	return &vtworkerdatapb.CancelResponse{Canceled: s.wi.Cancel()}, nil
+}
+
+// Pause is part of the vtworkerdatapb.VtworkerServer interface
+func (s *VtworkerServer) Pause(ctx context.Context, request *vtworkerdatapb.PauseRequest) (response *vtworkerdatapb.PauseResponse, err error) {
+	defer servenv.HandlePanic("vtworker", &err)
+	return &vtworkerdatapb.PauseResponse{Paused: s.wi.Pause()}, nil
+}
+
+// Resume is part of the vtworkerdatapb.VtworkerServer interface
+func (s *VtworkerServer) Resume(ctx context.Context, request *vtworkerdatapb.ResumeRequest) (response *vtworkerdatapb.ResumeResponse, err error) {
+	defer servenv.HandlePanic("vtworker", &err)
+	return &vtworkerdatapb.ResumeResponse{Resumed: s.wi.Resume()}, nil
+}
+
 // StartServer registers the VtworkerServer for RPCs
 func StartServer(s *grpc.Server, wi *worker.Instance) {
 	vtworkerservicepb.RegisterVtworkerServer(s, NewVtworkerServer(wi))
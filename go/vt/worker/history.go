@@ -0,0 +1,162 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	historySize = flag.Int("history_size", 100, "number of finished workers whose final state and status summary are kept and shown at /jobs. This includes the commands which were not submitted as job.")
+	historyDir  = flag.String("history_dir", "", "If set, each entry of the worker history is also written as JSON file to this local directory. The history is loaded from there at startup and survives a restart of vtworker.")
+)
+
+// history keeps the completion events (see completionEvent) of the last
+// finished workers. Without it, the result of a command is lost as soon as
+// the next command starts.
+type history struct {
+	size int
+	dir  string
+
+	// mu guards entries.
+	mu sync.Mutex
+	// entries is ordered by the end time, the oldest entry first.
+	entries []*completionEvent
+}
+
+// newHistory returns a history which keeps at most "size" entries. If "dir"
+// is set, the entries in it are loaded.
+func newHistory(size int, dir string) *history {
+	h := &history{
+		size: size,
+		dir:  dir,
+	}
+	if dir != "" {
+		if err := h.load(); err != nil {
+			log.Warningf("cannot load the worker history from %v: %v", dir, err)
+		}
+	}
+	return h
+}
+
+func (h *history) load() error {
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return err
+	}
+	files, err := filepath.Glob(path.Join(h.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		ev := &completionEvent{}
+		if err := json.Unmarshal(data, ev); err != nil {
+			return fmt.Errorf("cannot parse %v: %v", file, err)
+		}
+		h.entries = append(h.entries, ev)
+	}
+	sort.Slice(h.entries, func(i, j int) bool {
+		return h.entries[i].EndTime.Before(h.entries[j].EndTime)
+	})
+	h.evictLocked()
+	return nil
+}
+
+// add records a finished worker. The oldest entry is removed if the history
+// is full.
+func (h *history) add(ev *completionEvent) {
+	if h.size <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.dir != "" {
+		if err := h.write(ev); err != nil {
+			log.Warningf("cannot write the worker history entry %v to %v: %v", ev.ID, h.dir, err)
+		}
+	}
+	h.entries = append(h.entries, ev)
+	h.evictLocked()
+}
+
+func (h *history) write(ev *completionEvent) error {
+	data, err := json.MarshalIndent(ev, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(h.file(ev.ID), data, 0644)
+}
+
+func (h *history) file(id string) string {
+	return path.Join(h.dir, id+".json")
+}
+
+func (h *history) evictLocked() {
+	for len(h.entries) > h.size {
+		ev := h.entries[0]
+		h.entries = h.entries[1:]
+		if h.dir != "" {
+			if err := os.Remove(h.file(ev.ID)); err != nil && !os.IsNotExist(err) {
+				log.Warningf("cannot remove the worker history entry %v: %v", ev.ID, err)
+			}
+		}
+	}
+}
+
+// list returns all entries, the most recent entry first.
+func (h *history) list() []*completionEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := make([]*completionEvent, len(h.entries))
+	for i, ev := range h.entries {
+		result[len(h.entries)-1-i] = ev
+	}
+	return result
+}
+
+func (h *history) get(id string) (*completionEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, ev := range h.entries {
+		if ev.ID == id {
+			return ev, nil
+		}
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "history entry %v does not exist", id)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := newHistory(2, dir)
+	start := time.Now()
+	for i := 1; i <= 3; i++ {
+		h.add(&completionEvent{
+			ID:      fmt.Sprintf("Ping_%v", i),
+			Command: "Ping",
+			State:   string(WorkerStateDone),
+			EndTime: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	// The oldest entry was evicted and the most recent one is listed first.
+	wantIDs := []string{"Ping_3", "Ping_2"}
+	checkIDs := func(h *history) {
+		t.Helper()
+		list := h.list()
+		if len(list) != len(wantIDs) {
+			t.Fatalf("wrong number of history entries: got = %v, want = %v", len(list), len(wantIDs))
+		}
+		for i, ev := range list {
+			if ev.ID != wantIDs[i] {
+				t.Errorf("history entry %v: got = %v, want = %v", i, ev.ID, wantIDs[i])
+			}
+		}
+	}
+	checkIDs(h)
+	if _, err := h.get("Ping_1"); err == nil {
+		t.Error("an evicted entry must not be found")
+	}
+	if ev, err := h.get("Ping_2"); err != nil || ev.Command != "Ping" {
+		t.Errorf("get(Ping_2) = %v, %v", ev, err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("the evicted entry must be removed from the disk as well: %v", files)
+	}
+
+	// A new process loads the history from the disk.
+	checkIDs(newHistory(2, dir))
+}
@@ -31,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	vtworkerdatapb "vitess.io/vitess/go/vt/proto/vtworkerdata"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
@@ -60,6 +61,12 @@ type Instance struct {
 	lastRunError        error
 	lastRunStopTime     time.Time
 
+	// jobManager runs the jobs which are submitted at /jobs. They are
+	// independent of the current worker above.
+	jobManager *jobManager
+	// history has the last finished workers, including the jobs.
+	history *history
+
 	topoServer             *topo.Server
 	cell                   string
 	commandDisplayInterval time.Duration
@@ -70,6 +77,8 @@ func NewInstance(ts *topo.Server, cell string, commandDisplayInterval time.Durat
 	wi := &Instance{topoServer: ts, cell: cell, commandDisplayInterval: commandDisplayInterval}
 	// Note: setAndStartWorker() also adds a MemoryLogger for the webserver.
 	wi.wr = wi.CreateWrangler(logutil.NewConsoleLogger())
+	wi.jobManager = newJobManager(wi, *maxConcurrentJobs, *maxFinishedJobs)
+	wi.history = newHistory(*historySize, *historyDir)
 	return wi
 }
 
@@ -79,9 +88,10 @@ func (wi *Instance) CreateWrangler(logger logutil.Logger) *wrangler.Wrangler {
 }
 
 // setAndStartWorker will set the current worker.
+// "command" is the name of the vtworker command which created the worker.
 // We always log to both memory logger (for display on the web) and
 // console logger (for records / display of command line worker).
-func (wi *Instance) setAndStartWorker(ctx context.Context, wrk Worker, wr *wrangler.Wrangler) (chan struct{}, error) {
+func (wi *Instance) setAndStartWorker(ctx context.Context, command string, wrk Worker, wr *wrangler.Wrangler) (chan struct{}, error) {
 	wi.currentWorkerMutex.Lock()
 	defer wi.currentWorkerMutex.Unlock()
 
@@ -131,6 +141,7 @@ func (wi *Instance) setAndStartWorker(ctx context.Context, wrk Worker, wr *wrang
 	// one go function runs the worker, changes state when done
 	go func() {
 		log.Infof("Starting worker...")
+		startTime := time.Now()
 		var err error
 
 		// Catch all panics and always save the execution state at the end.
@@ -141,11 +152,16 @@ func (wi *Instance) setAndStartWorker(ctx context.Context, wrk Worker, wr *wrang
 				err = fmt.Errorf("uncaught vtworker panic: %v", x)
 			}
 
+			stopTime := time.Now()
+			ev := newCompletionEvent(command, 0 /* jobID */, wrk, startTime, stopTime, err)
+			wi.history.add(ev)
+			notifyCompletion(wi.topoServer, ev)
+
 			wi.currentWorkerMutex.Lock()
 			wi.currentContext = nil
 			wi.currentCancelFunc = nil
 			wi.lastRunError = err
-			wi.lastRunStopTime = time.Now()
+			wi.lastRunStopTime = stopTime
 			wi.currentWorkerMutex.Unlock()
 			close(done)
 		}()
@@ -178,10 +194,14 @@ func (wi *Instance) InstallSignalHandlers() {
 			func() {
 				wi.currentWorkerMutex.Lock()
 				defer wi.currentWorkerMutex.Unlock()
+				jobsCanceled := wi.jobManager.cancelAll()
+				if jobsCanceled > 0 {
+					log.Infof("Trying to cancel %v jobs after receiving signal: %v", jobsCanceled, s)
+				}
 				if wi.currentCancelFunc != nil {
 					log.Infof("Trying to cancel current worker after receiving signal: %v", s)
 					wi.currentCancelFunc()
-				} else {
+				} else if jobsCanceled == 0 {
 					log.Infof("Shutting down idle worker after receiving signal: %v", s)
 					os.Exit(0)
 				}
@@ -209,6 +229,27 @@ func (wi *Instance) Reset() error {
 	return errors.New("worker still executing")
 }
 
+// Status returns the status of the current vtworker job or, if none is
+// running, of the last job which ran since the last Reset().
+func (wi *Instance) Status() *vtworkerdatapb.GetStatusResponse {
+	wi.currentWorkerMutex.Lock()
+	defer wi.currentWorkerMutex.Unlock()
+
+	if wi.currentWorker == nil {
+		return &vtworkerdatapb.GetStatusResponse{}
+	}
+	status := &vtworkerdatapb.GetStatusResponse{
+		Worker: workerType(wi.currentWorker),
+		State:  wi.currentWorker.State().String(),
+		Status: wi.currentWorker.StatusAsText(),
+		Done:   wi.currentContext == nil,
+	}
+	if status.Done && wi.lastRunError != nil {
+		status.Error = wi.lastRunError.Error()
+	}
+	return status
+}
+
 // Cancel calls the cancel function of the current vtworker job.
 // It returns true, if a job was running. False otherwise.
 // NOTE: Cancel won't reset the state as well. Use Reset() to do so.
@@ -227,3 +268,27 @@ func (wi *Instance) Cancel() bool {
 
 	return true
 }
+
+// Pause pauses the current vtworker job. See Worker.Pause() for details.
+// It returns true, if a running job was paused. False otherwise.
+func (wi *Instance) Pause() bool {
+	wi.currentWorkerMutex.Lock()
+	defer wi.currentWorkerMutex.Unlock()
+
+	if wi.currentWorker == nil || wi.currentContext == nil {
+		return false
+	}
+	return wi.currentWorker.Pause()
+}
+
+// Resume resumes the current vtworker job.
+// It returns true, if a paused job was resumed. False otherwise.
+func (wi *Instance) Resume() bool {
+	wi.currentWorkerMutex.Lock()
+	defer wi.currentWorkerMutex.Unlock()
+
+	if wi.currentWorker == nil {
+		return false
+	}
+	return wi.currentWorker.Resume()
+}
@@ -38,6 +38,7 @@ const indexHTML = `
     {{range $i, $group := . }}
       <li><a href="/{{$group.Name}}">{{$group.Name}}</a>: {{$group.Description}}</li>
     {{end}}
+  <p><a href="/jobs">Jobs</a>: run several commands at the same time.</p>
 </body>
 `
 
@@ -127,7 +128,7 @@ func (wi *Instance) InitInteractiveMode() {
 					return
 				}
 
-				if _, err := wi.setAndStartWorker(context.Background(), wrk, wi.wr); err != nil {
+				if _, err := wi.setAndStartWorker(context.Background(), c.Name, wrk, wi.wr); err != nil {
 					httpError(w, "Could not set %s worker: %s", c.Name, err)
 					return
 				}
@@ -0,0 +1,360 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/tb"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	maxConcurrentJobs = flag.Int("max_concurrent_jobs", 2, "maximum number of jobs which are submitted at /jobs and run at the same time. Further jobs are queued until a running job has finished.")
+	maxFinishedJobs   = flag.Int("max_finished_jobs", 100, "maximum number of finished jobs which are kept at /jobs. When a job finishes, the oldest finished jobs beyond this limit are removed. The history of all workers is kept at /status regardless.")
+)
+
+// jobState is the lifecycle state of a job.
+type jobState string
+
+const (
+	// jobStateQueued is set while the job waits for a free slot.
+	jobStateQueued jobState = "queued"
+	// jobStateRunning is set while the worker of the job runs.
+	jobStateRunning jobState = "running"
+	// jobStateDone is set when the worker has returned or the job was
+	// canceled before it started.
+	jobStateDone jobState = "done"
+)
+
+// job is a worker which was submitted to the jobManager.
+type job struct {
+	id      int
+	args    []string
+	worker  Worker
+	logger  *logutil.MemoryLogger
+	ctx     context.Context
+	cancel  context.CancelFunc
+	created time.Time
+
+	// mu guards all fields below.
+	mu        sync.Mutex
+	state     jobState
+	err       error
+	startTime time.Time
+	endTime   time.Time
+}
+
+// jobStatus is a snapshot of a job. It is used by the /jobs pages.
+type jobStatus struct {
+	ID        int
+	Command   string
+	Worker    Worker
+	State     jobState
+	Err       error
+	Created   time.Time
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+func (j *job) status() *jobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return &jobStatus{
+		ID:        j.id,
+		Command:   strings.Join(j.args, " "),
+		Worker:    j.worker,
+		State:     j.state,
+		Err:       j.err,
+		Created:   j.created,
+		StartTime: j.startTime,
+		EndTime:   j.endTime,
+	}
+}
+
+func (j *job) setRunning() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.state = jobStateRunning
+	j.startTime = time.Now()
+}
+
+func (j *job) setDone(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.state = jobStateDone
+	j.err = err
+	j.endTime = time.Now()
+}
+
+// jobManager runs multiple workers concurrently. Unlike the single worker of
+// the Instance, each job has its own logger and status page. At most
+// "maxConcurrentJobs" jobs run at the same time. All other jobs are queued
+// in the order in which they were submitted.
+// Jobs never use the same tablet at the same time (see reservedTablets).
+// Jobs do not publish their state to the process-wide stats variables and
+// do not reset them (see StatusWorker.setJobID()). Their state is exported
+// per job instead (see InitMetrics()).
+// Only the last "maxFinishedJobs" finished jobs are kept.
+type jobManager struct {
+	wi *Instance
+	// slots has one entry for each running job.
+	slots chan struct{}
+	// maxFinishedJobs is the number of finished jobs which are kept.
+	maxFinishedJobs int
+
+	// mu guards all fields below.
+	mu     sync.Mutex
+	lastID int
+	jobs   map[int]*job
+}
+
+func newJobManager(wi *Instance, maxConcurrentJobs, maxFinishedJobs int) *jobManager {
+	if maxConcurrentJobs < 1 {
+		maxConcurrentJobs = 1
+	}
+	if maxFinishedJobs < 0 {
+		maxFinishedJobs = 0
+	}
+	return &jobManager{
+		wi:              wi,
+		slots:           make(chan struct{}, maxConcurrentJobs),
+		maxFinishedJobs: maxFinishedJobs,
+		jobs:            make(map[int]*job),
+	}
+}
+
+// submit creates the worker for the vtworker command "args" and queues it.
+// It returns the id of the new job.
+func (jm *jobManager) submit(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, errors.New("no command specified")
+	}
+
+	logger := logutil.NewMemoryLogger()
+	wr := jm.wi.CreateWrangler(logutil.NewTeeLogger(logger, logutil.NewConsoleLogger()))
+	wrk, timeouts, err := commandWorker(jm.wi, wr, args, jm.wi.cell, false /* runFromCli */)
+	if err != nil {
+		return 0, err
+	}
+	if wrk == nil {
+		return 0, fmt.Errorf("command %v did not create a worker", args[0])
+	}
+
+	// The job must outlive the request which submitted it.
+	ctx, cancel := context.WithCancel(newRemoteActionTimeoutsContext(context.Background(), timeouts))
+	jm.mu.Lock()
+	jm.lastID++
+	if jw, ok := wrk.(jobWorker); ok {
+		jw.setJobID(jm.lastID)
+	}
+	j := &job{
+		id:      jm.lastID,
+		args:    args,
+		worker:  wrk,
+		logger:  logger,
+		ctx:     ctx,
+		cancel:  cancel,
+		created: time.Now(),
+		state:   jobStateQueued,
+	}
+	jm.jobs[j.id] = j
+	jm.mu.Unlock()
+
+	go jm.run(j)
+	return j.id, nil
+}
+
+// jobWorker is implemented by all workers which embed StatusWorker.
+type jobWorker interface {
+	setJobID(id int)
+}
+
+// run waits for a free slot and then runs the worker of the job.
+func (jm *jobManager) run(j *job) {
+	defer jm.pruneFinished()
+	defer j.cancel()
+
+	select {
+	case jm.slots <- struct{}{}:
+	case <-j.ctx.Done():
+		j.setDone(vterrors.Errorf(vtrpcpb.Code_CANCELED, "vtworker job was canceled before it started"))
+		return
+	}
+	defer func() { <-jm.slots }()
+
+	log.Infof("Starting job %v: %v", j.id, strings.Join(j.args, " "))
+	j.setRunning()
+	var err error
+	// Catch all panics and always save the execution state at the end.
+	defer func() {
+		// The recovery code is a copy of servenv.HandlePanic().
+		if x := recover(); x != nil {
+			log.Errorf("uncaught vtworker panic in job %v: %v\n%s", j.id, x, tb.Stack(4))
+			err = fmt.Errorf("uncaught vtworker panic: %v", x)
+		}
+		j.setDone(err)
+		status := j.status()
+		ev := newCompletionEvent(j.args[0], j.id, j.worker, status.StartTime, status.EndTime, err)
+		jm.wi.history.add(ev)
+		notifyCompletion(jm.wi.topoServer, ev)
+	}()
+
+	err = j.worker.Run(j.ctx)
+
+	// If the context was canceled, include the respective error code.
+	if j.ctx.Err() == context.Canceled {
+		err = vterrors.Errorf(vtrpcpb.Code_CANCELED, "vtworker job was canceled: %v", err)
+	}
+}
+
+// list returns the status of all jobs, ordered by their id.
+func (jm *jobManager) list() []*jobStatus {
+	jm.mu.Lock()
+	ids := make([]int, 0, len(jm.jobs))
+	for id := range jm.jobs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	jobs := make([]*job, len(ids))
+	for i, id := range ids {
+		jobs[i] = jm.jobs[id]
+	}
+	jm.mu.Unlock()
+
+	result := make([]*jobStatus, len(jobs))
+	for i, j := range jobs {
+		result[i] = j.status()
+	}
+	return result
+}
+
+func (jm *jobManager) get(id int) (*job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	j, ok := jm.jobs[id]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "job %v does not exist", id)
+	}
+	return j, nil
+}
+
+// cancel cancels a queued or running job. Canceling a finished job is a
+// no-op.
+func (jm *jobManager) cancel(id int) error {
+	j, err := jm.get(id)
+	if err != nil {
+		return err
+	}
+	j.cancel()
+	return nil
+}
+
+// pause pauses a queued or running job. A paused job which is still queued
+// does not issue any queries once it was started.
+func (jm *jobManager) pause(id int) error {
+	j, err := jm.get(id)
+	if err != nil {
+		return err
+	}
+	j.worker.Pause()
+	return nil
+}
+
+// resume resumes a paused job.
+func (jm *jobManager) resume(id int) error {
+	j, err := jm.get(id)
+	if err != nil {
+		return err
+	}
+	j.worker.Resume()
+	return nil
+}
+
+// cancelAll cancels all queued and running jobs. It returns the number of
+// jobs which were not done yet.
+func (jm *jobManager) cancelAll() int {
+	jm.mu.Lock()
+	jobs := make([]*job, 0, len(jm.jobs))
+	for _, j := range jm.jobs {
+		jobs = append(jobs, j)
+	}
+	jm.mu.Unlock()
+
+	canceled := 0
+	for _, j := range jobs {
+		if j.status().State != jobStateDone {
+			j.cancel()
+			canceled++
+		}
+	}
+	return canceled
+}
+
+// remove forgets a finished job. It fails if the job is still queued or
+// running.
+func (jm *jobManager) remove(id int) error {
+	j, err := jm.get(id)
+	if err != nil {
+		return err
+	}
+	if state := j.status().State; state != jobStateDone {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "job %v is still %v", id, state)
+	}
+
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	delete(jm.jobs, id)
+	return nil
+}
+
+// pruneFinished removes the oldest finished jobs such that at most
+// "maxFinishedJobs" finished jobs are kept.
+func (jm *jobManager) pruneFinished() {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	var finished []int
+	for id, j := range jm.jobs {
+		if j.status().State == jobStateDone {
+			finished = append(finished, id)
+		}
+	}
+	if len(finished) <= jm.maxFinishedJobs {
+		return
+	}
+	sort.Ints(finished)
+	for _, id := range finished[:len(finished)-jm.maxFinishedJobs] {
+		delete(jm.jobs, id)
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/acl"
+)
+
+const jobListHTML = `
+<!DOCTYPE html>
+<head>
+  <title>Worker Jobs</title>
+</head>
+<body>
+  <h1>Worker Jobs</h1>
+  <form action="/jobs/submit" method="post">
+    <label for="command">Command: </label>
+    <input type="text" id="command" name="command" size="80" placeholder="SplitDiff --min_healthy_rdonly_tablets=1 keyspace/-80"/>
+    <input type="submit" value="Submit"/>
+  </form>
+  <p>At most {{.MaxConcurrentJobs}} jobs run at the same time. Further jobs are queued.</p>
+  {{if .Jobs}}
+  <table border="1">
+    <tr><th>Id</th><th>Command</th><th>State</th><th>Submitted</th><th>Ended</th><th>Error</th></tr>
+    {{range .Jobs}}
+    <tr>
+      <td><a href="/jobs/status?id={{.ID}}">{{.ID}}</a></td>
+      <td>{{.Command}}</td>
+      <td>{{.State}}</td>
+      <td>{{.Created}}</td>
+      <td>{{if not .EndTime.IsZero}}{{.EndTime}}{{end}}</td>
+      <td>{{if .Err}}{{.Err}}{{end}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{else}}
+  <p>No jobs were submitted yet.</p>
+  {{end}}
+  <h2>History</h2>
+  <p>The last {{.HistorySize}} finished workers, including the commands which were not submitted as job. The most recent one is listed first.</p>
+  {{if .History}}
+  <table border="1">
+    <tr><th>Command</th><th>Job</th><th>State</th><th>Ended</th><th>Duration</th><th>Error</th></tr>
+    {{range .History}}
+    <tr>
+      <td><a href="/jobs/history?id={{.ID}}">{{.Command}}</a></td>
+      <td>{{if .JobID}}{{.JobID}}{{end}}</td>
+      <td>{{.State}}</td>
+      <td>{{.EndTime}}</td>
+      <td>{{printf "%.1f" .DurationSeconds}}s</td>
+      <td>{{.Error}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{else}}
+  <p>No worker has finished yet.</p>
+  {{end}}
+  <p><a href="/">Toplevel Menu</a></p>
+</body>
+`
+
+const jobStatusHTML = `
+<!DOCTYPE html>
+<head>
+  <title>Worker Job {{.ID}}</title>
+</head>
+<body>
+  <h1>Worker Job {{.ID}}</h1>
+  <p><b>Command:</b> {{.Command}}</p>
+  <p><b>State:</b> {{.State}}</p>
+  <h2>Worker status:</h2>
+  <blockquote>
+    {{.Status}}
+  </blockquote>
+  <h2>Worker logs:</h2>
+  <blockquote>
+    {{.Logs}}
+  </blockquote>
+  {{if .Done}}
+  <p><a href="/jobs/remove?id={{.ID}}">Remove Job</a></p>
+  {{else}}
+  {{if .Paused}}
+  <p>This job is paused.</p>
+  <p><a href="/jobs/resume?id={{.ID}}">Resume Job</a></p>
+  {{else}}
+  <p><a href="/jobs/pause?id={{.ID}}">Pause Job</a></p>
+  {{end}}
+  <p><a href="/jobs/cancel?id={{.ID}}">Cancel Job</a></p>
+  {{end}}
+  <p><a href="/jobs">Job List</a></p>
+</body>
+`
+
+const historyEntryHTML = `
+<!DOCTYPE html>
+<head>
+  <title>Finished Worker {{.ID}}</title>
+</head>
+<body>
+  <h1>Finished Worker {{.ID}}</h1>
+  <p><b>Command:</b> {{.Command}}</p>
+  {{if .JobID}}<p><b>Job:</b> {{.JobID}}</p>{{end}}
+  <p><b>State:</b> {{.State}}</p>
+  {{if .Error}}<p><b>Error:</b> {{.Error}}</p>{{end}}
+  <p><b>Start Time:</b> {{.StartTime}}</p>
+  <p><b>End Time:</b> {{.EndTime}}</p>
+  <h2>Final status:</h2>
+  <pre>{{.Summary}}</pre>
+  <p><a href="/jobs">Job List</a></p>
+</body>
+`
+
+// jobID returns the job id from the "id" parameter of the request.
+func jobID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid job id: %v", r.FormValue("id"))
+	}
+	return id, nil
+}
+
+// InitJobHandling installs webserver handlers to submit jobs which run
+// concurrently and to list, inspect, pause, resume, cancel and remove them.
+// The /jobs page also shows the history of finished workers.
+func (wi *Instance) InitJobHandling() {
+	jobListTemplate := mustParseTemplate("jobList", jobListHTML)
+	jobStatusTemplate := mustParseTemplate("jobStatus", jobStatusHTML)
+	historyEntryTemplate := mustParseTemplate("historyEntry", historyEntryHTML)
+
+	// job list
+	http.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		executeTemplate(w, jobListTemplate, map[string]interface{}{
+			"MaxConcurrentJobs": cap(wi.jobManager.slots),
+			"Jobs":              wi.jobManager.list(),
+			"HistorySize":       wi.history.size,
+			"History":           wi.history.list(),
+		})
+	})
+
+	// history entry page
+	http.HandleFunc("/jobs/history", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		ev, err := wi.history.get(r.FormValue("id"))
+		if err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		executeTemplate(w, historyEntryTemplate, ev)
+	})
+
+	// submit handler
+	http.HandleFunc("/jobs/submit", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		id, err := wi.jobManager.submit(strings.Fields(r.FormValue("command")))
+		if err != nil {
+			httpError(w, "cannot submit job: %v", err)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/jobs/status?id=%v", id), http.StatusSeeOther)
+	})
+
+	// per job status page
+	http.HandleFunc("/jobs/status", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		id, err := jobID(r)
+		if err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		j, err := wi.jobManager.get(id)
+		if err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		js := j.status()
+
+		status := js.Worker.StatusAsHTML()
+		if js.State == jobStateDone {
+			if js.Err != nil {
+				status += template.HTML(fmt.Sprintf("<br>\nEnded with an error: %v<br>\n", js.Err))
+			}
+			status += template.HTML(fmt.Sprintf("<br>\n<b>End Time:</b> %v<br>\n", js.EndTime))
+		}
+		executeTemplate(w, jobStatusTemplate, map[string]interface{}{
+			"ID":      js.ID,
+			"Command": js.Command,
+			"State":   js.State,
+			"Status":  status,
+			"Logs":    template.HTML(strings.Replace(template.HTMLEscapeString(j.logger.String()), "\n", "</br>\n", -1)),
+			"Done":    js.State == jobStateDone,
+			"Paused":  js.Worker.IsPaused(),
+		})
+	})
+
+	// cancel handler
+	http.HandleFunc("/jobs/cancel", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		id, err := jobID(r)
+		if err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		if err := wi.jobManager.cancel(id); err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/jobs/status?id=%v", id), http.StatusTemporaryRedirect)
+	})
+
+	// pause and resume handlers
+	for _, action := range []struct {
+		path string
+		run  func(id int) error
+	}{
+		{"/jobs/pause", wi.jobManager.pause},
+		{"/jobs/resume", wi.jobManager.resume},
+	} {
+		run := action.run
+		http.HandleFunc(action.path, func(w http.ResponseWriter, r *http.Request) {
+			if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+				acl.SendError(w, err)
+				return
+			}
+
+			id, err := jobID(r)
+			if err != nil {
+				httpError(w, "%v", err)
+				return
+			}
+			if err := run(id); err != nil {
+				httpError(w, "%v", err)
+				return
+			}
+			http.Redirect(w, r, fmt.Sprintf("/jobs/status?id=%v", id), http.StatusTemporaryRedirect)
+		})
+	}
+
+	// remove handler
+	http.HandleFunc("/jobs/remove", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		id, err := jobID(r)
+		if err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		if err := wi.jobManager.remove(id); err != nil {
+			httpError(w, "%v", err)
+			return
+		}
+		http.Redirect(w, r, "/jobs", http.StatusTemporaryRedirect)
+	})
+}
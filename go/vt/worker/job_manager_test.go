@@ -0,0 +1,142 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+// waitForJobState waits until the job "id" is in the state "want".
+func waitForJobState(t *testing.T, jm *jobManager, id int, want jobState) *jobStatus {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		j, err := jm.get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		js := j.status()
+		if js.State == want {
+			return js
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %v did not reach state %v, current state: %v", id, want, js.State)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestJobManager(t *testing.T) {
+	wi := NewInstance(memorytopo.NewServer("cell1"), "cell1", time.Second)
+	// Only one job may run at a time.
+	jm := newJobManager(wi, 1, 10)
+
+	if _, err := jm.submit([]string{"NoSuchCommand"}); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("submit() with an unknown command = %v, want error containing: unknown command", err)
+	}
+
+	blockID, err := jm.submit([]string{"Block"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJobState(t, jm, blockID, jobStateRunning)
+
+	pingID, err := jm.submit([]string{"Ping", "pong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := jm.get(pingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := j.status().State; got != jobStateQueued {
+		t.Errorf("second job has state %v, want %v", got, jobStateQueued)
+	}
+
+	if err := jm.remove(blockID); err == nil || !strings.Contains(err.Error(), "is still running") {
+		t.Errorf("remove() of a running job = %v, want error containing: is still running", err)
+	}
+
+	// Canceling the first job frees the slot for the second job.
+	if err := jm.cancel(blockID); err != nil {
+		t.Fatal(err)
+	}
+	blockStatus := waitForJobState(t, jm, blockID, jobStateDone)
+	if blockStatus.Err == nil || !strings.Contains(blockStatus.Err.Error(), "canceled") {
+		t.Errorf("canceled job ended with error %v, want error containing: canceled", blockStatus.Err)
+	}
+	pingStatus := waitForJobState(t, jm, pingID, jobStateDone)
+	if pingStatus.Err != nil {
+		t.Errorf("second job failed: %v", pingStatus.Err)
+	}
+	if got, want := j.logger.String(), "message: 'pong'"; !strings.Contains(got, want) {
+		t.Errorf("second job logged %q, want it to contain %q", got, want)
+	}
+
+	list := jm.list()
+	if len(list) != 2 || list[0].ID != blockID || list[1].ID != pingID {
+		t.Fatalf("list() = %v, want jobs %v and %v", list, blockID, pingID)
+	}
+	if err := jm.remove(blockID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jm.get(blockID); err == nil {
+		t.Errorf("get() of a removed job should have failed")
+	}
+	if got := jm.cancelAll(); got != 0 {
+		t.Errorf("cancelAll() = %v, want 0 because all jobs are done", got)
+	}
+}
+
+func TestJobManagerPrunesFinishedJobs(t *testing.T) {
+	wi := NewInstance(memorytopo.NewServer("cell1"), "cell1", time.Second)
+	jm := newJobManager(wi, 1, 1)
+
+	// Jobs must not overwrite the process-wide state of the Instance's worker.
+	statsState.Set("instance worker state")
+	defer statsState.Set("")
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		id, err := jm.submit([]string{"Ping", "pong"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		waitForJobState(t, jm, id, jobStateDone)
+		ids = append(ids, id)
+	}
+
+	// The last finished job is kept. The older ones are removed eventually.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		list := jm.list()
+		if len(list) == 1 && list[0].ID == ids[2] {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("finished jobs were not pruned: %v", list)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got, want := statsState.Get(), "instance worker state"; got != want {
+		t.Errorf("WorkerState = %q after the jobs ran, want %q", got, want)
+	}
+}
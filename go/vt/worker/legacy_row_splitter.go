@@ -70,12 +70,15 @@ func (rs *RowSplitter) Split(result [][][]sqltypes.Value, rows [][]sqltypes.Valu
 }
 
 // Send will send the rows to the list of channels. Returns true if aborted.
-func (rs *RowSplitter) Send(fields []*querypb.Field, result [][][]sqltypes.Value, baseCmds []string, insertChannels []chan string, abort <-chan struct{}) bool {
+func (rs *RowSplitter) Send(fields []*querypb.Field, result [][][]sqltypes.Value, baseCmds []string, insertChannels []chan *writeQuery, abort <-chan struct{}) bool {
 	for i, c := range insertChannels {
 		// one of the chunks might be empty, so no need
 		// to send data in that case
 		if len(result[i]) > 0 {
-			cmd := baseCmds[i] + makeValueString(fields, result[i])
+			cmd := &writeQuery{
+				sql:  baseCmds[i] + makeValueString(fields, result[i]),
+				rows: len(result[i]),
+			}
 			// also check on abort, so we don't wait forever
 			select {
 			case c <- cmd:
@@ -118,10 +118,11 @@ func NewLegacySplitCloneWorker(wr *wrangler.Wrangler, cell, keyspace, shard stri
 		destinationWriterCount:  destinationWriterCount,
 		minHealthyRdonlyTablets: minHealthyRdonlyTablets,
 		maxTPS:                  maxTPS,
-		cleaner:                 &wrangler.Cleaner{},
+		cleaner:                 newCleaner(wr, "LegacySplitClone", keyspace, shard),
 
 		destinationDbNames:    make(map[string]string),
 		destinationThrottlers: make(map[string]*throttler.Throttler),
+		tableStatusList:       tableStatusList{keyspace: keyspace, shard: shard},
 
 		ev: &events.SplitClone{
 			Cell:          cell,
@@ -160,6 +161,7 @@ func (scw *LegacySplitCloneWorker) StatusAsHTML() template.HTML {
 	case WorkerStateCloneOnline:
 		result += "<b>Running</b>:</br>\n"
 		result += "<b>Copying from</b>: " + scw.formatSources() + "</br>\n"
+		result += "<b>Progress</b>: " + scw.tableStatusList.formatProgress() + "</br>\n"
 		statuses, eta := scw.tableStatusList.format()
 		result += "<b>ETA</b>: " + eta.String() + "</br>\n"
 		result += strings.Join(statuses, "</br>\n")
@@ -169,6 +171,7 @@ func (scw *LegacySplitCloneWorker) StatusAsHTML() template.HTML {
 		result += strings.Join(statuses, "</br>\n")
 	}
 
+	result += scw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -182,6 +185,7 @@ func (scw *LegacySplitCloneWorker) StatusAsText() string {
 	case WorkerStateCloneOnline:
 		result += "Running:\n"
 		result += "Copying from: " + scw.formatSources() + "\n"
+		result += "Progress: " + scw.tableStatusList.formatProgress() + "\n"
 		statuses, eta := scw.tableStatusList.format()
 		result += "ETA: " + eta.String() + "\n"
 		result += strings.Join(statuses, "\n")
@@ -190,15 +194,21 @@ func (scw *LegacySplitCloneWorker) StatusAsText() string {
 		statuses, _ := scw.tableStatusList.format()
 		result += strings.Join(statuses, "\n")
 	}
+	result += scw.formatPanicText()
 	return result
 }
 
+// progress is part of the progressReporter interface.
+func (scw *LegacySplitCloneWorker) progress() []tableProgress {
+	return scw.tableStatusList.progress()
+}
+
 // Run implements the Worker interface
 func (scw *LegacySplitCloneWorker) Run(ctx context.Context) error {
-	resetVars()
+	scw.resetRunVars()
 
 	// Run the command.
-	err := scw.run(ctx)
+	err := scw.runRecovered(ctx, scw.run)
 
 	// Cleanup.
 	scw.setState(WorkerStateCleanUp)
@@ -226,6 +236,10 @@ func (scw *LegacySplitCloneWorker) Run(ctx context.Context) error {
 		}
 	}
 
+	if err == nil && *waitForServingTimeout != 0 {
+		scw.setState(WorkerStateWaitForServing)
+		err = waitForServingTablets(ctx, scw.wr, scw.cleaner.ChangedTablets(), *waitForServingTimeout)
+	}
 	if err != nil {
 		scw.setErrorState(err)
 		return err
@@ -266,7 +280,7 @@ func (scw *LegacySplitCloneWorker) init(ctx context.Context) error {
 	var err error
 
 	// read the keyspace and validate it
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	scw.keyspaceInfo, err = scw.wr.TopoServer().GetKeyspace(shortCtx, scw.keyspace)
 	cancel()
 	if err != nil {
@@ -274,7 +288,7 @@ func (scw *LegacySplitCloneWorker) init(ctx context.Context) error {
 	}
 
 	// find the OverlappingShards in the keyspace
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	osList, err := topotools.FindOverlappingShards(shortCtx, scw.wr.TopoServer(), scw.keyspace)
 	cancel()
 	if err != nil {
@@ -346,7 +360,7 @@ func (scw *LegacySplitCloneWorker) findTargets(ctx context.Context) error {
 	// get the tablet info for them, and stop their replication
 	scw.sourceTablets = make([]*topodatapb.Tablet, len(scw.sourceAliases))
 	for i, alias := range scw.sourceAliases {
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 		ti, err := scw.wr.TopoServer().GetTablet(shortCtx, alias)
 		cancel()
 		if err != nil {
@@ -354,7 +368,7 @@ func (scw *LegacySplitCloneWorker) findTargets(ctx context.Context) error {
 		}
 		scw.sourceTablets[i] = ti.Tablet
 
-		shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 		err = scw.wr.TabletManagerClient().StopSlave(shortCtx, scw.sourceTablets[i])
 		cancel()
 		if err != nil {
@@ -389,7 +403,7 @@ func (scw *LegacySplitCloneWorker) findTargets(ctx context.Context) error {
 		master := masters[0]
 
 		// Get the MySQL database name of the tablet.
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 		ti, err := scw.wr.TopoServer().GetTablet(shortCtx, master.Tablet.Alias)
 		cancel()
 		if err != nil {
@@ -424,7 +438,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 	scw.setState(WorkerStateCloneOffline)
 	start := time.Now()
 	defer func() {
-		statsStateDurationsNs.Set(string(WorkerStateCloneOffline), time.Now().Sub(start).Nanoseconds())
+		scw.setStateDuration(WorkerStateCloneOffline, time.Now().Sub(start))
 	}()
 
 	// get source schema from the first shard
@@ -432,7 +446,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 	// on all source shards. Furthermore, we estimate the number of rows
 	// in each source shard for each table to be about the same
 	// (rowCount is used to estimate an ETA)
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
 	sourceSchemaDefinition, err := scw.wr.GetSchema(shortCtx, scw.sourceAliases[0], nil, scw.excludeTables, false /* includeViews */)
 	cancel()
 	if err != nil {
@@ -466,7 +480,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 		mu.Unlock()
 	}
 
-	insertChannels := make([]chan string, len(scw.destinationShards))
+	insertChannels := make([]chan *writeQuery, len(scw.destinationShards))
 	destinationWaitGroup := sync.WaitGroup{}
 	for shardIndex, si := range scw.destinationShards {
 		// we create one channel per destination tablet.  It
@@ -474,19 +488,22 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 		// destinationWriterCount * 2 items, to hopefully
 		// always have data. We then have
 		// destinationWriterCount go routines reading from it.
-		insertChannels[shardIndex] = make(chan string, scw.destinationWriterCount*2)
+		insertChannels[shardIndex] = make(chan *writeQuery, scw.destinationWriterCount*2)
 
-		go func(keyspace, shard string, insertChannel chan string) {
+		go func(keyspace, shard string, insertChannel chan *writeQuery) {
 			for j := 0; j < scw.destinationWriterCount; j++ {
 				destinationWaitGroup.Add(1)
 				go func(threadID int) {
 					defer destinationWaitGroup.Done()
+					defer scw.recoverPanic(func(err error) {
+						processError("%v", err)
+					})
 
 					keyspaceAndShard := topoproto.KeyspaceShardString(keyspace, shard)
 					throttler := scw.destinationThrottlers[keyspaceAndShard]
 					defer throttler.ThreadFinished(threadID)
 
-					executor := newExecutor(scw.wr, scw.tsc, throttler, keyspace, shard, threadID)
+					executor := newExecutor(scw.wr, scw.tsc, throttler, nil /* writeLimiter */, "" /* compression */, keyspace, shard, threadID, 0 /* transactionMaxRows */, 0 /* transactionMaxSize */)
 					if err := executor.fetchLoop(ctx, insertChannel); err != nil {
 						processError("executer.FetchLoop failed: %v", err)
 					}
@@ -542,10 +559,18 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 				sourceWaitGroup.Add(1)
 				go func(td *tabletmanagerdatapb.TableDefinition, shardIndex, tableIndex int, chunk chunk) {
 					defer sourceWaitGroup.Done()
+					defer scw.recoverPanic(func(err error) {
+						processError("%v", err)
+					})
 
 					sema.Acquire()
 					defer sema.Release()
 
+					if err := scw.waitIfPaused(ctx); err != nil {
+						processError("Context expired while the worker was paused. Context error: %v", err)
+						return
+					}
+
 					scw.tableStatusList.threadStarted(tableIndex)
 					defer scw.tableStatusList.threadDone(tableIndex)
 
@@ -564,7 +589,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 						keyspaceAndShard := topoproto.KeyspaceShardString(si.Keyspace(), si.ShardName())
 						dbNames[i] = scw.destinationDbNames[keyspaceAndShard]
 					}
-					if err := scw.processData(ctx, dbNames, td, tableIndex, rr, rowSplitter, insertChannels, scw.destinationPackCount); err != nil {
+					if err := scw.processData(ctx, dbNames, td, tableIndex, newPausableResultReader(ctx, &scw.StatusWorker, rr), rowSplitter, insertChannels, scw.destinationPackCount); err != nil {
 						processError("processData failed: %v", err)
 					}
 				}(td, shardIndex, tableIndex, c)
@@ -584,7 +609,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 	sourcePositions := make([]string, len(scw.sourceShards))
 	// get the current position from the sources
 	for shardIndex := range scw.sourceShards {
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 		status, err := scw.wr.TabletManagerClient().SlaveStatus(shortCtx, scw.sourceTablets[shardIndex])
 		cancel()
 		if err != nil {
@@ -599,7 +624,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 			defer destinationWaitGroup.Done()
 			scw.wr.Logger().Infof("Making and populating vreplication table")
 
-			exc := newExecutor(scw.wr, scw.tsc, nil, keyspace, shard, 0)
+			exc := newExecutor(scw.wr, scw.tsc, nil, nil /* writeLimiter */, "" /* compression */, keyspace, shard, 0, 0 /* transactionMaxRows */, 0 /* transactionMaxSize */)
 			for shardIndex, src := range scw.sourceShards {
 				bls := &binlogdatapb.BinlogSource{
 					Keyspace: src.Keyspace(),
@@ -629,7 +654,7 @@ func (scw *LegacySplitCloneWorker) copy(ctx context.Context) error {
 
 // processData pumps the data out of the provided QueryResultReader.
 // It returns any error the source encounters.
-func (scw *LegacySplitCloneWorker) processData(ctx context.Context, dbNames []string, td *tabletmanagerdatapb.TableDefinition, tableIndex int, rr ResultReader, rowSplitter *RowSplitter, insertChannels []chan string, destinationPackCount int) error {
+func (scw *LegacySplitCloneWorker) processData(ctx context.Context, dbNames []string, td *tabletmanagerdatapb.TableDefinition, tableIndex int, rr ResultReader, rowSplitter *RowSplitter, insertChannels []chan *writeQuery, destinationPackCount int) error {
 	// Store the baseCmd per destination shard because each tablet may have a
 	// different dbName.
 	baseCmds := make([]string, len(dbNames))
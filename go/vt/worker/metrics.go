@@ -0,0 +1,144 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+// This file publishes the per-table progress of the current worker and of
+// all running jobs as gauges. Together with the other worker stats (see
+// worker.go), they are served in the Prometheus text format at /metrics by
+// the prometheus stats backend (see go/cmd/vtworker/plugin_prometheusbackend.go).
+
+import (
+	"strconv"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// currentWorkerJobLabel is the value of the "job" label of the per-table
+// progress of the Instance's worker. Jobs are labeled by their id instead.
+const currentWorkerJobLabel = "current"
+
+// InitMetrics publishes the per-table progress of the current worker and of
+// all running jobs.
+// It must be called at most once per process.
+func (wi *Instance) InitMetrics() {
+	stats.NewGaugesFuncWithMultiLabels(
+		"WorkerTableRowCount",
+		"For every job and table the (estimated) number of rows",
+		[]string{"job", "table"},
+		func() map[string]int64 {
+			return tableProgressGauges(wi.allProgress(), func(p tableProgress) int64 { return int64(p.RowCount) })
+		})
+	stats.NewGaugesFuncWithMultiLabels(
+		"WorkerTableProcessedRows",
+		"For every job and table the number of processed rows",
+		[]string{"job", "table"},
+		func() map[string]int64 {
+			return tableProgressGauges(wi.allProgress(), func(p tableProgress) int64 { return int64(p.ProcessedRows) })
+		})
+	stats.NewGaugesFuncWithMultiLabels(
+		"WorkerTableRunningThreads",
+		"For every job and table the number of threads which currently process it",
+		[]string{"job", "table"},
+		func() map[string]int64 {
+			return tableProgressGauges(wi.allProgress(), func(p tableProgress) int64 { return int64(p.RunningThreads) })
+		})
+	stats.NewGaugesFuncWithMultiLabels(
+		"WorkerTableState",
+		"For every job and table 1 for its current state (not started, running, done or failed)",
+		[]string{"job", "table", "state"},
+		func() map[string]int64 {
+			result := make(map[string]int64)
+			for _, wp := range wi.allProgress() {
+				for _, p := range wp.progress {
+					if p.IsView {
+						continue
+					}
+					result[wp.job+"."+p.Name+"."+p.State] = 1
+				}
+			}
+			return result
+		})
+	stats.NewGaugesFuncWithMultiLabels(
+		"WorkerJobState",
+		"For every job submitted at /jobs 1 for the current state of its worker",
+		[]string{"job", "state"},
+		func() map[string]int64 {
+			result := make(map[string]int64)
+			for _, js := range wi.jobManager.list() {
+				result[strconv.Itoa(js.ID)+"."+js.Worker.State().String()] = 1
+			}
+			return result
+		})
+}
+
+// workerProgress is the per-table progress of one worker.
+type workerProgress struct {
+	// job is the value of the "job" label.
+	job      string
+	progress []tableProgress
+}
+
+// allProgress returns the per-table progress of the current worker and of
+// each running job. Workers which do not track their progress are omitted.
+func (wi *Instance) allProgress() []workerProgress {
+	var result []workerProgress
+	if progress := wi.currentProgress(); progress != nil {
+		result = append(result, workerProgress{job: currentWorkerJobLabel, progress: progress})
+	}
+	if wi.jobManager == nil {
+		return result
+	}
+	for _, js := range wi.jobManager.list() {
+		if js.State != jobStateRunning {
+			continue
+		}
+		if pr, ok := js.Worker.(progressReporter); ok {
+			if progress := pr.progress(); progress != nil {
+				result = append(result, workerProgress{job: strconv.Itoa(js.ID), progress: progress})
+			}
+		}
+	}
+	return result
+}
+
+// currentProgress returns the progress of each table of the current worker.
+// It returns nil if there is no worker or it does not track the progress.
+func (wi *Instance) currentProgress() []tableProgress {
+	wi.currentWorkerMutex.Lock()
+	wrk := wi.currentWorker
+	wi.currentWorkerMutex.Unlock()
+
+	if pr, ok := wrk.(progressReporter); ok {
+		return pr.progress()
+	}
+	return nil
+}
+
+// tableProgressGauges returns "value" for each table in "workers" which is
+// not a view, keyed by the job label and the table name.
+func tableProgressGauges(workers []workerProgress, value func(p tableProgress) int64) map[string]int64 {
+	result := make(map[string]int64)
+	for _, wp := range workers {
+		for _, p := range wp.progress {
+			if p.IsView {
+				continue
+			}
+			result[wp.job+"."+p.Name] = value(p)
+		}
+	}
+	return result
+}
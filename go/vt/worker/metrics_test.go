@@ -0,0 +1,90 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestTableProgressGauges(t *testing.T) {
+	workers := []workerProgress{{
+		job: currentWorkerJobLabel,
+		progress: []tableProgress{
+			{Name: "t1", State: "running", RowCount: 100, ProcessedRows: 50, RunningThreads: 2},
+			{Name: "t2", State: "not started", RowCount: 200},
+			{Name: "v1", IsView: true, State: "not started"},
+		},
+	}, {
+		job: "3",
+		progress: []tableProgress{
+			{Name: "t1", State: "running", RowCount: 100, ProcessedRows: 10, RunningThreads: 1},
+		},
+	}}
+
+	got := tableProgressGauges(workers, func(p tableProgress) int64 { return int64(p.ProcessedRows) })
+	want := map[string]int64{"current.t1": 50, "current.t2": 0, "3.t1": 10}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tableProgressGauges() = %v, want = %v", got, want)
+	}
+
+	if got := tableProgressGauges(nil, func(p tableProgress) int64 { return 1 }); len(got) != 0 {
+		t.Errorf("tableProgressGauges() without a worker = %v, want none", got)
+	}
+}
+
+func TestInstanceCurrentProgress(t *testing.T) {
+	wi := &Instance{}
+	if got := wi.currentProgress(); got != nil {
+		t.Errorf("currentProgress() without a worker = %v, want = nil", got)
+	}
+
+	sdw := &SplitDiffWorker{tableStatusList: &tableStatusList{action: "diff"}}
+	wi.currentWorker = sdw
+	if got := wi.currentProgress(); got != nil {
+		t.Errorf("currentProgress() before the diff started = %v, want = nil", got)
+	}
+}
+
+func TestInstanceAllProgress(t *testing.T) {
+	wi := &Instance{}
+	wi.jobManager = newJobManager(wi, 1, 1)
+
+	newWorker := func() *SplitDiffWorker {
+		sdw := &SplitDiffWorker{tableStatusList: &tableStatusList{action: "diff"}}
+		sdw.tableStatusList.initialize(&tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{Name: "t1", Type: "BASE TABLE", RowCount: 100},
+			},
+		})
+		return sdw
+	}
+	wi.currentWorker = newWorker()
+	wi.jobManager.jobs[1] = &job{id: 1, worker: newWorker(), state: jobStateRunning}
+	// Finished jobs are not reported.
+	wi.jobManager.jobs[2] = &job{id: 2, worker: newWorker(), state: jobStateDone}
+
+	got := wi.allProgress()
+	if len(got) != 2 || got[0].job != currentWorkerJobLabel || got[1].job != "1" {
+		t.Fatalf("allProgress() = %+v, want the progress of the current worker and job 1", got)
+	}
+	if len(got[1].progress) != 1 || got[1].progress[0].Name != "t1" {
+		t.Errorf("allProgress() of job 1 = %+v, want table t1", got[1].progress)
+	}
+}
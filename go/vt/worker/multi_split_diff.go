@@ -0,0 +1,808 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// MultiSplitDiffWorker executes a diff between a source shard and all the
+// destination shards which were split off it.
+// Unlike the SplitDiffWorker, which must run once per destination shard and
+// reads the source once per run, it reads each row of the source only once
+// and compares it against the destination shard whose key range contains it.
+type MultiSplitDiffWorker struct {
+	StatusWorker
+	diffOptions
+
+	wr              *wrangler.Wrangler
+	cell            string
+	keyspace        string
+	shard           string
+	excludeTables   []string
+	comparison      comparisonOptions
+	tableStatusList *tableStatusList
+	cleaner         *wrangler.Cleaner
+
+	// populated during WorkerStateInit, read-only after that
+	keyspaceInfo      *topo.KeyspaceInfo
+	shardInfo         *topo.ShardInfo
+	destinationShards []*topo.ShardInfo
+	// sourceUIDs has the UID of our shard in the SourceShards of each
+	// destination shard.
+	sourceUIDs []uint32
+
+	// populated during WorkerStateFindTargets, read-only after that
+	sourceAlias        *topodatapb.TabletAlias
+	destinationAliases []*topodatapb.TabletAlias
+
+	// populated during WorkerStateSyncReplication, read-only after that
+	// destinationMasterPositions has the position of the master of each
+	// destination shard.
+	destinationMasterPositions []string
+
+	// populated during WorkerStateDiff
+	sourceSchemaDefinition       *tabletmanagerdatapb.SchemaDefinition
+	destinationSchemaDefinitions []*tabletmanagerdatapb.SchemaDefinition
+}
+
+// NewMultiSplitDiffWorker returns a new MultiSplitDiffWorker object.
+// "shard" is the source shard. MultiSplitDiff does not support all flags of
+// SplitDiff. The unsupported fields of "opts" must be left at their zero value.
+func NewMultiSplitDiffWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, excludeTables []string, opts diffOptions) (Worker, error) {
+	comparison, err := opts.validate()
+	if err != nil {
+		return nil, err
+	}
+	opts.where = parenthesize(opts.where)
+
+	return &MultiSplitDiffWorker{
+		StatusWorker:    NewStatusWorker(),
+		diffOptions:     opts,
+		wr:              wr,
+		cell:            cell,
+		keyspace:        keyspace,
+		shard:           shard,
+		excludeTables:   excludeTables,
+		comparison:      comparison,
+		tableStatusList: &tableStatusList{action: "diff", keyspace: keyspace, shard: shard},
+		cleaner:         newCleaner(wr, "MultiSplitDiff", keyspace, shard),
+	}, nil
+}
+
+// StatusAsHTML is part of the Worker interface
+func (msdw *MultiSplitDiffWorker) StatusAsHTML() template.HTML {
+	state := msdw.State()
+
+	result := "<b>Working on:</b> " + msdw.keyspace + "/" + msdw.shard + " and all its destination shards</br>\n"
+	result += "<b>State:</b> " + state.String() + "</br>\n"
+	switch state {
+	case WorkerStateDiff, WorkerStateDiffWillFail:
+		if state == WorkerStateDiff {
+			result += "<b>Running...</b></br>\n"
+		} else {
+			result += "<b>Running - have already found differences...</b></br>\n"
+		}
+		result += "<b>Progress:</b> " + msdw.tableStatusList.formatProgress() + "</br>\n"
+		_, eta := msdw.tableStatusList.format()
+		result += "<b>ETA:</b> " + eta.String() + "</br>\n"
+		result += string(msdw.tableStatusList.formatHTML())
+	case WorkerStateDone:
+		result += "<b>Success.</b></br>\n"
+		result += string(msdw.tableStatusList.formatHTML())
+	case WorkerStateError:
+		// Show which tables failed.
+		result += string(msdw.tableStatusList.formatHTML())
+	}
+
+	result += msdw.formatPanicHTML()
+	return template.HTML(result)
+}
+
+// StatusAsText is part of the Worker interface
+func (msdw *MultiSplitDiffWorker) StatusAsText() string {
+	state := msdw.State()
+
+	result := "Working on: " + msdw.keyspace + "/" + msdw.shard + " and all its destination shards\n"
+	result += "State: " + state.String() + "\n"
+	switch state {
+	case WorkerStateDiff, WorkerStateDiffWillFail:
+		if state == WorkerStateDiff {
+			result += "Running...\n"
+		} else {
+			result += "Running - have already found differences...\n"
+		}
+		result += "Progress: " + msdw.tableStatusList.formatProgress() + "\n"
+		statuses, eta := msdw.tableStatusList.format()
+		result += "ETA: " + eta.String() + "\n"
+		result += strings.Join(statuses, "\n")
+	case WorkerStateDone:
+		result += "Success.\n"
+		statuses, _ := msdw.tableStatusList.format()
+		result += strings.Join(statuses, "\n")
+	}
+	result += msdw.formatPanicText()
+	return result
+}
+
+// progress is part of the progressReporter interface.
+func (msdw *MultiSplitDiffWorker) progress() []tableProgress {
+	return msdw.tableStatusList.progress()
+}
+
+// Run is mostly a wrapper to run the cleanup at the end.
+func (msdw *MultiSplitDiffWorker) Run(ctx context.Context) error {
+	msdw.resetRunVars()
+	err := msdw.runRecovered(ctx, msdw.run)
+
+	msdw.SetState(WorkerStateCleanUp)
+	cerr := msdw.cleaner.CleanUp(msdw.wr)
+	if cerr != nil {
+		if err != nil {
+			msdw.wr.Logger().Errorf("CleanUp failed in addition to job error: %v", cerr)
+		} else {
+			err = cerr
+		}
+	}
+	if err == nil && *waitForServingTimeout != 0 {
+		msdw.SetState(WorkerStateWaitForServing)
+		err = waitForServingTablets(ctx, msdw.wr, msdw.cleaner.ChangedTablets(), *waitForServingTimeout)
+	}
+	if err != nil {
+		msdw.wr.Logger().Errorf("Run() error: %v", err)
+		msdw.SetState(WorkerStateError)
+		return err
+	}
+	msdw.SetState(WorkerStateDone)
+	return nil
+}
+
+func (msdw *MultiSplitDiffWorker) run(ctx context.Context) error {
+	// first state: read what we need to do
+	if err := msdw.init(ctx); err != nil {
+		return vterrors.Wrap(err, "init() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
+	// second state: find targets
+	if err := msdw.findTargets(ctx); err != nil {
+		return vterrors.Wrap(err, "findTargets() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
+	// validate the chosen tablets before we stop any replication
+	if err := msdw.checkTargets(ctx); err != nil {
+		return vterrors.Wrap(err, "checkTargets() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
+	// third phase: synchronize replication
+	if err := msdw.synchronizeReplication(ctx); err != nil {
+		return vterrors.Wrap(err, "synchronizeReplication() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
+	// fourth phase: diff
+	if err := msdw.diff(ctx); err != nil {
+		return vterrors.Wrap(err, "diff() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
+	if verifiesShard(nil /* tables */, msdw.where, msdw.samplePercent, false /* repair */) {
+		for i, si := range msdw.destinationShards {
+			writeDiffVerification(ctx, msdw.wr, "MultiSplitDiff", si.Keyspace(), si.ShardName(), msdw.keyspace, msdw.shard, msdw.destinationMasterPositions[i])
+		}
+	}
+	return nil
+}
+
+// init phase:
+// - read the source shard info
+// - find all destination shards which have our shard as SourceShard
+func (msdw *MultiSplitDiffWorker) init(ctx context.Context) error {
+	msdw.SetState(WorkerStateInit)
+
+	var err error
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	msdw.keyspaceInfo, err = msdw.wr.TopoServer().GetKeyspace(shortCtx, msdw.keyspace)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read keyspace %v", msdw.keyspace)
+	}
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	msdw.shardInfo, err = msdw.wr.TopoServer().GetShard(shortCtx, msdw.keyspace, msdw.shard)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read shard %v/%v", msdw.keyspace, msdw.shard)
+	}
+
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	shards, err := msdw.wr.TopoServer().FindAllShardsInKeyspace(shortCtx, msdw.keyspace)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read shards of keyspace %v", msdw.keyspace)
+	}
+	names := make([]string, 0, len(shards))
+	for name := range shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		si := shards[name]
+		for _, ss := range si.SourceShards {
+			if ss.Keyspace == msdw.keyspace && ss.Shard == msdw.shard && len(ss.Tables) == 0 {
+				if !si.HasMaster() {
+					return fmt.Errorf("destination shard %v/%v has no master", msdw.keyspace, name)
+				}
+				msdw.destinationShards = append(msdw.destinationShards, si)
+				msdw.sourceUIDs = append(msdw.sourceUIDs, ss.Uid)
+				break
+			}
+		}
+	}
+	if len(msdw.destinationShards) == 0 {
+		return fmt.Errorf("shard %v/%v is not the source shard of any shard", msdw.keyspace, msdw.shard)
+	}
+	return nil
+}
+
+// findTargets phase:
+// - find one sourceTabletType in the source shard
+// - find one destinationTabletType in each destination shard
+// - mark them all as 'worker' pointing back to us
+func (msdw *MultiSplitDiffWorker) findTargets(ctx context.Context) error {
+	msdw.SetState(WorkerStateFindTargets)
+
+	var err error
+	msdw.sourceAlias, err = FindWorkerTablet(ctx, msdw.wr, msdw.cleaner, nil /* tsc */, msdw.cell, msdw.keyspace, msdw.shard, msdw.minHealthyRdonlyTablets, msdw.sourceTabletType)
+	if err != nil {
+		return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", msdw.cell, msdw.keyspace, msdw.shard)
+	}
+
+	msdw.destinationAliases = make([]*topodatapb.TabletAlias, len(msdw.destinationShards))
+	for i, si := range msdw.destinationShards {
+		msdw.destinationAliases[i], err = FindWorkerTablet(ctx, msdw.wr, msdw.cleaner, nil /* tsc */, msdw.cell, msdw.keyspace, si.ShardName(), 1 /* minHealthyTablets */, msdw.destinationTabletType)
+		if err != nil {
+			return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", msdw.cell, msdw.keyspace, si.ShardName())
+		}
+	}
+	return nil
+}
+
+// checkTargets phase:
+// - run the pre-flight checks on the destination master(s) and the chosen
+//   source and destination tablets (see checkTabletsHealthy())
+func (msdw *MultiSplitDiffWorker) checkTargets(ctx context.Context) error {
+	var masters []*topodatapb.TabletAlias
+	for _, si := range msdw.destinationShards {
+		masters = append(masters, si.MasterAlias)
+	}
+	slaves := append([]*topodatapb.TabletAlias{msdw.sourceAlias}, msdw.destinationAliases...)
+	return checkTabletsHealthy(ctx, msdw.wr, masters, slaves)
+}
+
+// synchronizeReplication phase:
+// 1 - ask the master of each destination shard to pause filtered
+//   replication, and return the source binlog positions
+//   (add a cleanup task to restart filtered replication on the masters)
+// 2 - stop the source tablet at a binlog position higher than all the
+//   destination masters. Get that new position.
+//   (add a cleanup task to restart binlog replication on the source tablet)
+// 3 - ask the master of each destination shard to resume filtered
+//   replication up to the new position, and return its binlog position.
+// 4 - wait until each destination tablet is equal or passed its master
+//   binlog position, and stop its replication.
+//   (add a cleanup task to restart binlog replication on it)
+// 5 - restart filtered replication on the destination masters.
+// At this point, the source and all destination tablets are stopped at the
+// same point.
+
+func (msdw *MultiSplitDiffWorker) synchronizeReplication(ctx context.Context) error {
+	msdw.SetState(WorkerStateSyncReplication)
+
+	masters := make([]*topo.TabletInfo, len(msdw.destinationShards))
+	for i, si := range msdw.destinationShards {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		masterInfo, err := msdw.wr.TopoServer().GetTablet(shortCtx, si.MasterAlias)
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "synchronizeReplication: cannot get Tablet record for master %v", topoproto.TabletAliasString(si.MasterAlias))
+		}
+		masters[i] = masterInfo
+	}
+
+	// 1 - stop the filtered replication on all destination masters, get their
+	//     current positions
+	vreplicationPositions := make([]string, len(masters))
+	for i, masterInfo := range masters {
+		alias := topoproto.TabletAliasString(masterInfo.Alias)
+		uid := msdw.sourceUIDs[i]
+		msdw.wr.Logger().Infof("Stopping master binlog replication on %v", alias)
+		shortCtx, cancel := context.WithTimeout(ctx, stopBlpTimeoutFor(ctx))
+		_, err := msdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StopVReplication(uid, "for multi split diff"))
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "VReplicationExec(stop) for %v failed", alias)
+		}
+		wrangler.RecordVReplicationAction(msdw.cleaner, masterInfo.Tablet, binlogplayer.StartVReplication(uid))
+
+		shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		p3qr, err := msdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.ReadVReplicationPos(uid))
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "VReplicationExec(read pos) for %v failed", alias)
+		}
+		qr := sqltypes.Proto3ToResult(p3qr)
+		if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 {
+			return fmt.Errorf("Unexpected result while reading position: %v", qr)
+		}
+		vreplicationPositions[i] = qr.Rows[0][0].ToString()
+	}
+	vreplicationPos, err := maxReplicationPosition(vreplicationPositions)
+	if err != nil {
+		return err
+	}
+
+	// 2 - stop replication on the source tablet
+	msdw.wr.Logger().Infof("Stopping slave %v at a minimum of %v", topoproto.TabletAliasString(msdw.sourceAlias), vreplicationPos)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	sourceTablet, err := msdw.wr.TopoServer().GetTablet(shortCtx, msdw.sourceAlias)
+	cancel()
+	if err != nil {
+		return err
+	}
+	shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
+	mysqlPos, err := msdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, sourceTablet.Tablet, vreplicationPos, stopSlaveMinimumTimeoutFor(ctx))
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot stop slave %v at right binlog position %v", topoproto.TabletAliasString(msdw.sourceAlias), vreplicationPos)
+	}
+	// change the cleaner actions from ChangeSlaveType(rdonly)
+	// to StartSlave() + ChangeSlaveType(spare)
+	wrangler.RecordStartSlaveAction(msdw.cleaner, sourceTablet.Tablet)
+
+	for i, masterInfo := range masters {
+		alias := topoproto.TabletAliasString(masterInfo.Alias)
+		uid := msdw.sourceUIDs[i]
+
+		// 3 - ask the master of the destination shard to resume filtered
+		//     replication up to the new position
+		msdw.wr.Logger().Infof("Restarting master %v until it catches up to %v", alias, mysqlPos)
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		_, err := msdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StartVReplicationUntil(uid, mysqlPos))
+		if err != nil {
+			cancel()
+			return vterrors.Wrapf(err, "VReplication(start until) for %v until %v failed", alias, mysqlPos)
+		}
+		if err := msdw.wr.TabletManagerClient().VReplicationWaitForPos(shortCtx, masterInfo.Tablet, int(uid), mysqlPos); err != nil {
+			cancel()
+			return vterrors.Wrapf(err, "VReplicationWaitForPos for %v until %v failed", alias, mysqlPos)
+		}
+		masterPos, err := msdw.wr.TabletManagerClient().MasterPosition(shortCtx, masterInfo.Tablet)
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "MasterPosition for %v failed", alias)
+		}
+		msdw.destinationMasterPositions = append(msdw.destinationMasterPositions, masterPos)
+
+		// 4 - wait until the destination tablet is equal or passed
+		//     that master binlog position, and stop its replication.
+		destinationAlias := msdw.destinationAliases[i]
+		msdw.wr.Logger().Infof("Waiting for destination tablet %v to catch up to %v", topoproto.TabletAliasString(destinationAlias), masterPos)
+		shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		destinationTablet, err := msdw.wr.TopoServer().GetTablet(shortCtx, destinationAlias)
+		cancel()
+		if err != nil {
+			return err
+		}
+		shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
+		_, err = msdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, destinationTablet.Tablet, masterPos, stopSlaveMinimumTimeoutFor(ctx))
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "StopSlaveMinimum for %v at %v failed", topoproto.TabletAliasString(destinationAlias), masterPos)
+		}
+		wrangler.RecordStartSlaveAction(msdw.cleaner, destinationTablet.Tablet)
+
+		// 5 - restart filtered replication on the destination master
+		msdw.wr.Logger().Infof("Restarting filtered replication on master %v", alias)
+		shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		_, err = msdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StartVReplication(uid))
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "VReplicationExec(start) failed for %v", alias)
+		}
+	}
+
+	return nil
+}
+
+// maxReplicationPosition returns the position which is at least as high as
+// all other "positions". It fails if the positions cannot be ordered.
+func maxReplicationPosition(positions []string) (string, error) {
+	var maxPos string
+	var maxPosition mysql.Position
+	for i, pos := range positions {
+		position, err := mysql.DecodePosition(pos)
+		if err != nil {
+			return "", vterrors.Wrapf(err, "cannot decode replication position %v", pos)
+		}
+		if i == 0 || position.AtLeast(maxPosition) {
+			maxPos, maxPosition = pos, position
+			continue
+		}
+		if !maxPosition.AtLeast(position) {
+			return "", fmt.Errorf("replication positions %v and %v cannot be ordered", maxPos, pos)
+		}
+	}
+	return maxPos, nil
+}
+
+// minReplicationPosition returns the position which all other "positions"
+// are at least as high as. It fails if the positions cannot be ordered.
+func minReplicationPosition(positions []string) (string, error) {
+	var minPos string
+	var minPosition mysql.Position
+	for i, pos := range positions {
+		position, err := mysql.DecodePosition(pos)
+		if err != nil {
+			return "", vterrors.Wrapf(err, "cannot decode replication position %v", pos)
+		}
+		if i == 0 || minPosition.AtLeast(position) {
+			minPos, minPosition = pos, position
+			continue
+		}
+		if !position.AtLeast(minPosition) {
+			return "", fmt.Errorf("replication positions %v and %v cannot be ordered", minPos, pos)
+		}
+	}
+	return minPos, nil
+}
+
+// diff phase: will log messages regarding the diff.
+// - get the schema on all tablets
+// - if some table schema mismatches, record them (use existing schema diff tools).
+// - for each table in the source, run a diff pipeline which compares it
+//   against all destination shards.
+
+func (msdw *MultiSplitDiffWorker) diff(ctx context.Context) error {
+	msdw.SetState(WorkerStateDiff)
+
+	msdw.wr.Logger().Infof("Gathering schema information...")
+	msdw.destinationSchemaDefinitions = make([]*tabletmanagerdatapb.SchemaDefinition, len(msdw.destinationAliases))
+	wg := sync.WaitGroup{}
+	rec := &concurrency.AllErrorRecorder{}
+	for i, alias := range msdw.destinationAliases {
+		wg.Add(1)
+		go func(i int, alias *topodatapb.TabletAlias) {
+			defer wg.Done()
+			shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
+			schemaDefinition, err := msdw.wr.GetSchema(
+				shortCtx, alias, nil /* tables */, msdw.excludeTables, msdw.includeViews)
+			cancel()
+			if err != nil {
+				msdw.markAsWillFail(rec, err)
+				return
+			}
+			msdw.destinationSchemaDefinitions[i] = schemaDefinition
+			msdw.wr.Logger().Infof("Got schema from destination %v", topoproto.TabletAliasString(alias))
+		}(i, alias)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
+		msdw.sourceSchemaDefinition, err = msdw.wr.GetSchema(
+			shortCtx, msdw.sourceAlias, nil /* tables */, msdw.excludeTables, msdw.includeViews)
+		cancel()
+		if err != nil {
+			msdw.markAsWillFail(rec, err)
+			return
+		}
+		msdw.wr.Logger().Infof("Got schema from source %v", topoproto.TabletAliasString(msdw.sourceAlias))
+	}()
+	wg.Wait()
+	if rec.HasErrors() {
+		return rec.Error()
+	}
+
+	msdw.wr.Logger().Infof("Diffing the schema...")
+	for i, schemaDefinition := range msdw.destinationSchemaDefinitions {
+		rec := &concurrency.AllErrorRecorder{}
+		tmutils.DiffSchema("destination "+msdw.destinationShards[i].ShardName(), schemaDefinition, "source", msdw.sourceSchemaDefinition, rec)
+		if rec.HasErrors() {
+			msdw.wr.Logger().Warningf("Different schemas on destination %v: %v", msdw.destinationShards[i].ShardName(), rec.Error().Error())
+		} else {
+			msdw.wr.Logger().Infof("Schema of destination %v matches, good.", msdw.destinationShards[i].ShardName())
+		}
+	}
+
+	// read the vschema if needed
+	var keyspaceSchema *vindexes.KeyspaceSchema
+	if *useV3ReshardingMode {
+		kschema, err := msdw.wr.TopoServer().GetVSchema(ctx, msdw.keyspace)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot load VSchema for keyspace %v", msdw.keyspace)
+		}
+		if kschema == nil {
+			return fmt.Errorf("no VSchema for keyspace %v", msdw.keyspace)
+		}
+
+		keyspaceSchema, err = vindexes.BuildKeyspaceSchema(kschema, msdw.keyspace)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot build vschema for keyspace %v", msdw.keyspace)
+		}
+	}
+
+	if msdw.where != "" {
+		msdw.wr.Logger().Infof("Comparing only the rows which match: %v", msdw.where)
+	}
+	if msdw.samplePercent < 100 {
+		msdw.wr.Logger().Infof("Comparing only a sample of %v%% of the rows", msdw.samplePercent)
+	}
+	if msdw.comparison.hashColumnsLargerThan > 0 {
+		msdw.wr.Logger().Infof("Comparing BLOB and TEXT values larger than %v bytes by their MD5 hash", msdw.comparison.hashColumnsLargerThan)
+	}
+	if msdw.comparison.normalizeCharset != "" {
+		msdw.wr.Logger().Infof("Converting text values to the character set %v before comparing them", msdw.comparison.normalizeCharset)
+	}
+	if msdw.comparison.normalizeTimestamps {
+		msdw.wr.Logger().Infof("Comparing TIMESTAMP values as UNIX timestamps")
+	}
+	for table, columns := range msdw.comparison.ignoreColumns {
+		msdw.wr.Logger().Infof("Ignoring the columns %v of table %v", strings.Join(columns, ", "), table)
+	}
+	if msdw.comparison.floatEpsilon > 0 {
+		msdw.wr.Logger().Infof("Ignoring differences of FLOAT and DOUBLE values up to %v", msdw.comparison.floatEpsilon)
+	}
+
+	// run the diffs, parallelDiffsCount at a time
+	msdw.wr.Logger().Infof("Running the diffs (%v tables in parallel)...", msdw.parallelDiffsCount)
+	sem := sync2.NewSemaphore(msdw.parallelDiffsCount, 0)
+	tableDefinitions := msdw.sourceSchemaDefinition.TableDefinitions
+
+	// sort tables by size
+	// if there are large deltas between table sizes then it's more efficient to start working on the large tables first
+	sort.Slice(tableDefinitions, func(i, j int) bool { return tableDefinitions[i].DataLength > tableDefinitions[j].DataLength })
+
+	msdw.tableStatusList.initialize(msdw.sourceSchemaDefinition)
+
+	// The chunks are computed on the source because the table definitions
+	// (and their row count estimates) are from there as well.
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	sourceTablet, err := msdw.wr.TopoServer().GetTablet(shortCtx, msdw.sourceAlias)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read source tablet %v", topoproto.TabletAliasString(msdw.sourceAlias))
+	}
+
+	rec = &concurrency.AllErrorRecorder{}
+	for tableIndex, tableDefinition := range tableDefinitions {
+		if tableDefinition.Type == tmutils.TableView {
+			// Views have no rows of their own. Their definition was
+			// already compared by the schema diff.
+			msdw.wr.Logger().Infof("Skipping the row diff on view %v", tableDefinition.Name)
+			continue
+		}
+
+		wg.Add(1)
+		go func(tableIndex int, tableDefinition *tabletmanagerdatapb.TableDefinition) {
+			defer wg.Done()
+			defer msdw.recoverPanic(func(err error) {
+				msdw.markAsWillFail(rec, err)
+			})
+			// use the semaphore to limit the number of tables that are diffed in parallel
+			sem.Acquire()
+			defer sem.Release()
+
+			msdw.wr.Logger().Infof("Starting the diff on table %v", tableDefinition.Name)
+
+			// A table without a primary key is compared by all columns. The
+			// original definition is used to split it into chunks though
+			// because the leading column may be NULL and would not match any
+			// chunk.
+			originalTableDefinition := tableDefinition
+			tableDefinition, err := diffTableDefinition(originalTableDefinition, msdw.pklessTablesUseFullRow, msdw.comparison.ignoreColumns[originalTableDefinition.Name])
+			if err != nil {
+				msdw.tableStatusList.tableFailed(tableIndex, err)
+				msdw.markAsWillFail(rec, err)
+				msdw.wr.Logger().Errorf("%v", err)
+				return
+			}
+
+			resolver, err := msdw.keyspaceIDResolver(tableDefinition, keyspaceSchema)
+			if err != nil {
+				err = vterrors.Wrapf(err, "cannot resolve sharding keys for table %v", tableDefinition.Name)
+				msdw.tableStatusList.tableFailed(tableIndex, err)
+				msdw.markAsWillFail(rec, err)
+				msdw.wr.Logger().Errorf("%v", err)
+				return
+			}
+
+			report, err := retryTableDiff(ctx, msdw.wr.Logger(), tableDefinition.Name, msdw.tableRetryCount, msdw.tableRetryBackoff, func() (DiffReport, error) {
+				// A failed attempt must not leave its progress behind.
+				msdw.tableStatusList.resetProgress(tableIndex)
+
+				// Split the table into chunks which are diffed in parallel.
+				chunks, err := generateChunks(ctx, msdw.wr, sourceTablet.Tablet, originalTableDefinition, msdw.chunkCount, msdw.minRowsPerChunk)
+				if err != nil {
+					return DiffReport{}, vterrors.Wrapf(err, "failed to split table %v into chunks", tableDefinition.Name)
+				}
+				msdw.tableStatusList.setThreadCount(tableIndex, len(chunks))
+				return diffChunksInParallel(&msdw.StatusWorker, chunks, func(c chunk) (DiffReport, error) {
+					msdw.tableStatusList.threadStarted(tableIndex)
+					defer msdw.tableStatusList.threadDone(tableIndex)
+					return msdw.diffChunk(ctx, tableIndex, tableDefinition, c, resolver)
+				})
+			})
+			if err != nil {
+				msdw.tableStatusList.tableFailed(tableIndex, err)
+				msdw.markAsWillFail(rec, err)
+				msdw.wr.Logger().Errorf("%v", err)
+				return
+			}
+			if report.HasDifferences() {
+				msdw.tableStatusList.setDifferentRows(tableIndex, report)
+				err := fmt.Errorf("Table %v has differences: %v", tableDefinition.Name, report.String())
+				msdw.tableStatusList.tableFailed(tableIndex, err)
+				msdw.markAsWillFail(rec, err)
+				msdw.wr.Logger().Warningf("%v", err)
+			} else {
+				msdw.wr.Logger().Infof("Table %v checks out (%v rows processed, %v qps)", tableDefinition.Name, report.processedRows, report.processingQPS)
+			}
+		}(tableIndex, tableDefinition)
+	}
+	wg.Wait()
+
+	return rec.Error()
+}
+
+// keyspaceIDResolver returns the resolver for the rows of "td" as they are
+// returned by tableScanChunk() i.e. with the primary key columns first.
+func (msdw *MultiSplitDiffWorker) keyspaceIDResolver(td *tabletmanagerdatapb.TableDefinition, keyspaceSchema *vindexes.KeyspaceSchema) (keyspaceIDResolver, error) {
+	if keyspaceSchema != nil {
+		return newV3ResolverFromColumnList(keyspaceSchema, td.Name, orderedColumns(td))
+	}
+	return newV2Resolver(msdw.keyspaceInfo, reorderColumnsPrimaryKeyFirst(td))
+}
+
+// diffChunk compares chunk "c" of table "td" between the source and all
+// destination shards. The source is read only once. Each of its rows is
+// compared against the destination shard whose key range contains it.
+func (msdw *MultiSplitDiffWorker) diffChunk(ctx context.Context, tableIndex int, td *tabletmanagerdatapb.TableDefinition, c chunk, resolver keyspaceIDResolver) (DiffReport, error) {
+	if err := msdw.waitIfPaused(ctx); err != nil {
+		return DiffReport{}, err
+	}
+
+	where := msdw.rowFilter(td)
+	sourceQueryResultReader, err := tableScanChunk(ctx, msdw.wr, msdw.sourceAlias, td, c, where, msdw.comparison)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "tableScanChunk(source) failed")
+	}
+	defer sourceQueryResultReader.Close(ctx)
+
+	destinationQueryResultReaders := make([]*QueryResultReader, 0, len(msdw.destinationAliases))
+	defer func() {
+		for _, r := range destinationQueryResultReaders {
+			r.Close(ctx)
+		}
+	}()
+	keyRanges := make([]*topodatapb.KeyRange, len(msdw.destinationShards))
+	for i, alias := range msdw.destinationAliases {
+		r, err := tableScanChunk(ctx, msdw.wr, alias, td, c, where, msdw.comparison)
+		if err != nil {
+			return DiffReport{}, vterrors.Wrapf(err, "tableScanChunk(destination %v) failed", msdw.destinationShards[i].ShardName())
+		}
+		destinationQueryResultReaders = append(destinationQueryResultReaders, r)
+		keyRanges[i] = msdw.destinationShards[i].KeyRange
+	}
+
+	// Stop demultiplexing the source when a diff fails. Otherwise, the
+	// demuxer would block on the output of the failed diff.
+	demuxCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	demuxer := NewResultDemuxer(demuxCtx, newPausableResultReader(ctx, &msdw.StatusWorker, sourceQueryResultReader), resolver, keyRanges)
+	go demuxer.Run()
+
+	var mu sync.Mutex
+	var report DiffReport
+	rec := &concurrency.FirstErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for i, destinationQueryResultReader := range destinationQueryResultReaders {
+		wg.Add(1)
+		go func(i int, destinationQueryResultReader ResultReader) {
+			defer wg.Done()
+			defer msdw.recoverPanic(func(err error) {
+				rec.RecordError(err)
+				cancel()
+			})
+			shard := msdw.destinationShards[i].ShardName()
+
+			differ, err := NewRowDiffer(
+				demuxer.Output(i),
+				newPausableResultReader(ctx, &msdw.StatusWorker, destinationQueryResultReader),
+				td)
+			if err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "NewRowDiffer() failed for destination %v", shard))
+				cancel()
+				return
+			}
+			differ.tableStatusList = msdw.tableStatusList
+			differ.tableIndex = tableIndex
+			differ.floatEpsilon = msdw.comparison.floatEpsilon
+
+			destinationReport, err := differ.Go(msdw.wr.Logger())
+			if err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "Differ.Go failed for destination %v", shard))
+				cancel()
+				return
+			}
+			if destinationReport.HasDifferences() {
+				msdw.wr.Logger().Warningf("Table %v has differences on destination %v: %v", td.Name, shard, destinationReport.String())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.merge(destinationReport)
+		}(i, destinationQueryResultReader)
+	}
+	wg.Wait()
+	return report, rec.Error()
+}
+
+// rowFilter returns the condition which selects the rows of "td" which are
+// compared. It combines --where and --sample_percent.
+func (msdw *MultiSplitDiffWorker) rowFilter(td *tabletmanagerdatapb.TableDefinition) string {
+	return joinConditions(msdw.where, sampleCondition(td, msdw.samplePercent))
+}
+
+// markAsWillFail records the error and changes the state of the worker to reflect this
+func (msdw *MultiSplitDiffWorker) markAsWillFail(er concurrency.ErrorRecorder, err error) {
+	er.RecordError(err)
+	msdw.SetState(WorkerStateDiffWillFail)
+}
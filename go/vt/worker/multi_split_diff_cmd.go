@@ -0,0 +1,348 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const multiSplitDiffHTML = `
+<!DOCTYPE html>
+<head>
+  <title>Multi Split Diff Action</title>
+</head>
+<body>
+  <h1>Multi Split Diff Action</h1>
+
+    {{if .Error}}
+      <b>Error:</b> {{.Error}}</br>
+    {{else}}
+      {{range $i, $si := .Shards}}
+        <li><a href="/Diffs/MultiSplitDiff?keyspace={{$si.Keyspace}}&shard={{$si.Shard}}">{{$si.Keyspace}}/{{$si.Shard}}</a></li>
+      {{end}}
+    {{end}}
+</body>
+`
+
+const multiSplitDiffHTML2 = `
+<!DOCTYPE html>
+<head>
+  <title>Multi Split Diff Action</title>
+</head>
+<body>
+  <p>Source shard involved: {{.Keyspace}}/{{.Shard}}</p>
+  <h1>Multi Split Diff Action</h1>
+    <form action="/Diffs/MultiSplitDiff" method="post">
+      <LABEL for="excludeTables">Exclude Tables: </LABEL>
+        <INPUT type="text" id="excludeTables" name="excludeTables" value=""></BR>
+      <LABEL for="minHealthyRdonlyTablets">Minimum Number of required healthy RDONLY tablets: </LABEL>
+        <INPUT type="text" id="minHealthyRdonlyTablets" name="minHealthyRdonlyTablets" value="{{.DefaultMinHealthyRdonlyTablets}}"></BR>
+      <LABEL for="parallelDiffsCount">Number of tables to diff in parallel: </LABEL>
+        <INPUT type="text" id="parallelDiffsCount" name="parallelDiffsCount" value="{{.DefaultParallelDiffsCount}}"></BR>
+      <LABEL for="chunkCount">Number of chunks per table which are diffed in parallel: </LABEL>
+        <INPUT type="text" id="chunkCount" name="chunkCount" value="{{.DefaultChunkCount}}"></BR>
+      <LABEL for="minRowsPerChunk">Minimun Number of Rows per Chunk (may reduce the Chunk Count): </LABEL>
+        <INPUT type="text" id="minRowsPerChunk" name="minRowsPerChunk" value="{{.DefaultMinRowsPerChunk}}"></BR>
+      <LABEL for="tableRetryCount">Number of retries of a failed table diff: </LABEL>
+        <INPUT type="text" id="tableRetryCount" name="tableRetryCount" value="{{.DefaultTableRetryCount}}"></BR>
+      <LABEL for="tableRetryBackoff">Delay before the first retry of a table diff (doubles after each retry): </LABEL>
+        <INPUT type="text" id="tableRetryBackoff" name="tableRetryBackoff" value="{{.DefaultTableRetryBackoff}}"></BR>
+      <LABEL for="where">Compare only rows which match this SQL predicate (optional, applied to all tables): </LABEL>
+        <INPUT type="text" id="where" name="where" value=""></BR>
+      <LABEL for="samplePercent">Percentage of rows to compare (a deterministic sample based on the primary key): </LABEL>
+        <INPUT type="text" id="samplePercent" name="samplePercent" value="{{.DefaultSamplePercent}}"></BR>
+      <LABEL for="hashColumnsLargerThan">Compare BLOB and TEXT values larger than this many bytes by their hash (0 disables it): </LABEL>
+        <INPUT type="text" id="hashColumnsLargerThan" name="hashColumnsLargerThan" value="{{.DefaultHashColumnsLargerThan}}"></BR>
+      <LABEL for="normalizeCharset">Convert text values to this character set before comparing them (optional): </LABEL>
+        <INPUT type="text" id="normalizeCharset" name="normalizeCharset" value="{{.DefaultNormalizeCharset}}"></BR>
+      <LABEL for="ignoreColumns">Columns which are not compared, e.g. t1:last_seen,counter;t2:updated (optional): </LABEL>
+        <INPUT type="text" id="ignoreColumns" name="ignoreColumns" value="{{.DefaultIgnoreColumns}}"></BR>
+      <LABEL for="normalizeTimestamps">Compare TIMESTAMP values independent of the time_zone setting of source and destination: </LABEL>
+        <INPUT type="checkbox" id="normalizeTimestamps" name="normalizeTimestamps" value="true"{{if .DefaultNormalizeTimestamps}} checked{{end}}></BR>
+      <LABEL for="floatEpsilon">Ignore differences of FLOAT and DOUBLE values up to this amount (0 disables it): </LABEL>
+        <INPUT type="text" id="floatEpsilon" name="floatEpsilon" value="{{.DefaultFloatEpsilon}}"></BR>
+      <LABEL for="includeViews">Compare the definitions of views as well (views have no row diff): </LABEL>
+        <INPUT type="checkbox" id="includeViews" name="includeViews" value="true"{{if .DefaultIncludeViews}} checked{{end}}></BR>
+      <LABEL for="pklessTablesUseFullRow">Compare tables without a primary key by all columns: </LABEL>
+        <INPUT type="checkbox" id="pklessTablesUseFullRow" name="pklessTablesUseFullRow" value="true"{{if .DefaultPKLessTablesUseFullRow}} checked{{end}}></BR>
+      <INPUT type="hidden" name="keyspace" value="{{.Keyspace}}"/>
+      <INPUT type="hidden" name="shard" value="{{.Shard}}"/>
+      <INPUT type="submit" name="submit" value="Multi Split Diff"/>
+    </form>
+  </body>
+`
+
+var multiSplitDiffTemplate = mustParseTemplate("multiSplitDiff", multiSplitDiffHTML)
+var multiSplitDiffTemplate2 = mustParseTemplate("multiSplitDiff2", multiSplitDiffHTML2)
+
+func commandMultiSplitDiff(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) (Worker, error) {
+	excludeTables := subFlags.String("exclude_tables", "", "comma separated list of tables to exclude")
+	minHealthyRdonlyTablets := subFlags.Int("min_healthy_rdonly_tablets", defaultMinHealthyRdonlyTablets, "minimum number of healthy RDONLY tablets in the source shard before taking out one")
+	tabletTypeStr := subFlags.String("tablet_type", defaultTabletType, "source tablet type (RDONLY or REPLICA) that will be used to compare the shards. REPLICA tablets are drained before they are used")
+	destTabletTypeStr := subFlags.String("dest_tablet_type", defaultDestTabletType, "destination tablet type (RDONLY or REPLICA) that will be used to compare the shards. REPLICA tablets are drained before they are used")
+	parallelDiffsCount := subFlags.Int("parallel_diffs_count", defaultParallelDiffsCount, "number of tables to diff in parallel")
+	chunkCount := subFlags.Int("chunk_count", defaultDiffChunkCount, "number of chunks per table which are diffed in parallel. Tables are split by ranges of the first primary key column")
+	minRowsPerChunk := subFlags.Int("min_rows_per_chunk", defaultMinRowsPerChunk, "minimum number of rows per chunk (may reduce --chunk_count)")
+	tableRetryCount := subFlags.Int("table_retry_count", defaultTableRetryCount, "number of times a failed table diff is retried e.g. after a transient tablet restart")
+	tableRetryBackoff := subFlags.Duration("table_retry_backoff", defaultTableRetryBackoff, "delay before the first retry of a failed table diff. The delay doubles after each retry")
+	where := subFlags.String("where", "", "if set, only rows which match this SQL predicate are compared e.g. \"updated_at > '2016-01-01'\". The predicate is applied to all tables")
+	samplePercent := subFlags.Float64("sample_percent", defaultSamplePercent, "percentage of rows which are compared. The sample is a deterministic pseudo-random subset of the primary keys and the same on source and destination")
+	hashColumnsLargerThan := subFlags.Int("hash_columns_larger_than", defaultHashColumnsLargerThan, "if > 0, values of BLOB and TEXT columns which are larger than this many bytes are compared by their MD5 hash. The hash is computed by MySQL on the tablets which reduces the memory usage of vtworker for tables with large values")
+	normalizeCharset := subFlags.String("normalize_charset", defaultNormalizeCharset, "if set, values of CHAR, VARCHAR and TEXT columns are converted to this character set (e.g. utf8mb4) by MySQL before they are compared. Use it if the source and the destination use different character sets. Primary key columns are not converted")
+	ignoreColumns := subFlags.String("ignore_columns", defaultIgnoreColumns, "semicolon separated list of tables with a comma separated list of columns which are not compared e.g. \"t1:last_seen,counter;t2:updated\". Use it for volatile columns which are not kept in sync by filtered replication. Primary key columns cannot be ignored")
+	normalizeTimestamps := subFlags.Bool("normalize_timestamps", defaultNormalizeTimestamps, "compare the values of TIMESTAMP columns as UNIX timestamps. Use it if the source and the destination have a different time_zone setting. Primary key columns are not converted")
+	floatEpsilon := subFlags.Float64("float_epsilon", defaultFloatEpsilon, "if > 0, values of FLOAT and DOUBLE columns are considered equal if their absolute difference is not larger than this amount")
+	includeViews := subFlags.Bool("include_views", defaultIncludeViews, "include views in the schema diff. Only their definitions are compared because views have no rows of their own")
+	pklessTablesUseFullRow := subFlags.Bool("pkless_tables_use_full_row", defaultPKLessTablesUseFullRow, "compare tables without a primary key by ordering their rows by all columns. A different row is reported as one missing and one extraneous row. Without this flag, such tables fail the diff")
+	if err := subFlags.Parse(args); err != nil {
+		return nil, err
+	}
+	if subFlags.NArg() != 1 {
+		subFlags.Usage()
+		return nil, fmt.Errorf("command MultiSplitDiff requires <keyspace/shard>")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	var excludeTableArray []string
+	if *excludeTables != "" {
+		excludeTableArray = strings.Split(*excludeTables, ",")
+	}
+
+	tabletType, ok := topodatapb.TabletType_value[*tabletTypeStr]
+	if !ok {
+		return nil, fmt.Errorf("command MultiSplitDiff invalid tablet_type: %v", *tabletTypeStr)
+	}
+	destTabletType, ok := topodatapb.TabletType_value[*destTabletTypeStr]
+	if !ok {
+		return nil, fmt.Errorf("command MultiSplitDiff invalid dest_tablet_type: %v", *destTabletTypeStr)
+	}
+
+	worker, err := NewMultiSplitDiffWorker(wr, wi.cell, keyspace, shard, excludeTableArray, diffOptions{
+		minHealthyRdonlyTablets: *minHealthyRdonlyTablets,
+		sourceTabletType:        topodatapb.TabletType(tabletType),
+		destinationTabletType:   topodatapb.TabletType(destTabletType),
+		parallelDiffsCount:      *parallelDiffsCount,
+		chunkCount:              *chunkCount,
+		minRowsPerChunk:         *minRowsPerChunk,
+		tableRetryCount:         *tableRetryCount,
+		tableRetryBackoff:       *tableRetryBackoff,
+		where:                   *where,
+		samplePercent:           *samplePercent,
+		includeViews:            *includeViews,
+		pklessTablesUseFullRow:  *pklessTablesUseFullRow,
+		hashColumnsLargerThan:   *hashColumnsLargerThan,
+		normalizeCharset:        *normalizeCharset,
+		ignoreColumns:           *ignoreColumns,
+		floatEpsilon:            *floatEpsilon,
+		normalizeTimestamps:     *normalizeTimestamps,
+	})
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create multi split diff worker")
+	}
+	return worker, nil
+}
+
+// shardsWithDestinations returns all the shards which are the source shard
+// of at least one other shard i.e. the source shards of a horizontal split.
+func shardsWithDestinations(ctx context.Context, wr *wrangler.Wrangler) ([]map[string]string, error) {
+	destinations, err := shardsWithSources(ctx, wr)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var result []map[string]string
+	for _, d := range destinations {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		si, err := wr.TopoServer().GetShard(shortCtx, d["Keyspace"], d["Shard"])
+		cancel()
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "failed to get details for shard '%v'", topoproto.KeyspaceShardString(d["Keyspace"], d["Shard"]))
+		}
+		for _, ss := range si.SourceShards {
+			name := topoproto.KeyspaceShardString(ss.Keyspace, ss.Shard)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			result = append(result, map[string]string{
+				"Keyspace": ss.Keyspace,
+				"Shard":    ss.Shard,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i]["Keyspace"] != result[j]["Keyspace"] {
+			return result[i]["Keyspace"] < result[j]["Keyspace"]
+		}
+		return result[i]["Shard"] < result[j]["Shard"]
+	})
+	return result, nil
+}
+
+func interactiveMultiSplitDiff(ctx context.Context, wi *Instance, wr *wrangler.Wrangler, w http.ResponseWriter, r *http.Request) (Worker, *template.Template, map[string]interface{}, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse form")
+	}
+	keyspace := r.FormValue("keyspace")
+	shard := r.FormValue("shard")
+
+	if keyspace == "" || shard == "" {
+		// display the list of possible shards to chose from
+		result := make(map[string]interface{})
+		shards, err := shardsWithDestinations(ctx, wr)
+		if err != nil {
+			result["Error"] = err.Error()
+		} else {
+			result["Shards"] = shards
+		}
+		return nil, multiSplitDiffTemplate, result, nil
+	}
+
+	submitButtonValue := r.FormValue("submit")
+	if submitButtonValue == "" {
+		// display the input form
+		result := make(map[string]interface{})
+		result["Keyspace"] = keyspace
+		result["Shard"] = shard
+		result["DefaultMinHealthyRdonlyTablets"] = fmt.Sprintf("%v", defaultMinHealthyRdonlyTablets)
+		result["DefaultParallelDiffsCount"] = fmt.Sprintf("%v", defaultParallelDiffsCount)
+		result["DefaultChunkCount"] = fmt.Sprintf("%v", defaultDiffChunkCount)
+		result["DefaultMinRowsPerChunk"] = fmt.Sprintf("%v", defaultMinRowsPerChunk)
+		result["DefaultTableRetryCount"] = fmt.Sprintf("%v", defaultTableRetryCount)
+		result["DefaultTableRetryBackoff"] = defaultTableRetryBackoff.String()
+		result["DefaultSamplePercent"] = fmt.Sprintf("%v", defaultSamplePercent)
+		result["DefaultHashColumnsLargerThan"] = fmt.Sprintf("%v", defaultHashColumnsLargerThan)
+		result["DefaultNormalizeCharset"] = defaultNormalizeCharset
+		result["DefaultIgnoreColumns"] = defaultIgnoreColumns
+		result["DefaultNormalizeTimestamps"] = defaultNormalizeTimestamps
+		result["DefaultFloatEpsilon"] = fmt.Sprintf("%v", defaultFloatEpsilon)
+		result["DefaultIncludeViews"] = defaultIncludeViews
+		result["DefaultPKLessTablesUseFullRow"] = defaultPKLessTablesUseFullRow
+		return nil, multiSplitDiffTemplate2, result, nil
+	}
+
+	// Process input form.
+	excludeTables := r.FormValue("excludeTables")
+	var excludeTableArray []string
+	if excludeTables != "" {
+		excludeTableArray = strings.Split(excludeTables, ",")
+	}
+	minHealthyRdonlyTabletsStr := r.FormValue("minHealthyRdonlyTablets")
+	minHealthyRdonlyTablets, err := strconv.ParseInt(minHealthyRdonlyTabletsStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse minHealthyRdonlyTablets")
+	}
+	parallelDiffsCountStr := r.FormValue("parallelDiffsCount")
+	parallelDiffsCount, err := strconv.ParseInt(parallelDiffsCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse parallelDiffsCount")
+	}
+	chunkCountStr := r.FormValue("chunkCount")
+	chunkCount, err := strconv.ParseInt(chunkCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse chunkCount")
+	}
+	minRowsPerChunkStr := r.FormValue("minRowsPerChunk")
+	minRowsPerChunk, err := strconv.ParseInt(minRowsPerChunkStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse minRowsPerChunk")
+	}
+	tableRetryCountStr := r.FormValue("tableRetryCount")
+	tableRetryCount, err := strconv.ParseInt(tableRetryCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse tableRetryCount")
+	}
+	tableRetryBackoffStr := r.FormValue("tableRetryBackoff")
+	tableRetryBackoff, err := time.ParseDuration(tableRetryBackoffStr)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse tableRetryBackoff")
+	}
+	where := r.FormValue("where")
+	samplePercentStr := r.FormValue("samplePercent")
+	samplePercent, err := strconv.ParseFloat(samplePercentStr, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse samplePercent")
+	}
+	hashColumnsLargerThanStr := r.FormValue("hashColumnsLargerThan")
+	hashColumnsLargerThan, err := strconv.ParseInt(hashColumnsLargerThanStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse hashColumnsLargerThan")
+	}
+	normalizeCharset := r.FormValue("normalizeCharset")
+	ignoreColumns := r.FormValue("ignoreColumns")
+	normalizeTimestampsStr := r.FormValue("normalizeTimestamps")
+	normalizeTimestamps := normalizeTimestampsStr == "true"
+	floatEpsilonStr := r.FormValue("floatEpsilon")
+	floatEpsilon, err := strconv.ParseFloat(floatEpsilonStr, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse floatEpsilon")
+	}
+	includeViewsStr := r.FormValue("includeViews")
+	includeViews := includeViewsStr == "true"
+	pklessTablesUseFullRowStr := r.FormValue("pklessTablesUseFullRow")
+	pklessTablesUseFullRow := pklessTablesUseFullRowStr == "true"
+
+	// start the diff job
+	wrk, err := NewMultiSplitDiffWorker(wr, wi.cell, keyspace, shard, excludeTableArray, diffOptions{
+		minHealthyRdonlyTablets: int(minHealthyRdonlyTablets),
+		sourceTabletType:        topodatapb.TabletType_RDONLY,
+		destinationTabletType:   topodatapb.TabletType_RDONLY,
+		parallelDiffsCount:      int(parallelDiffsCount),
+		chunkCount:              int(chunkCount),
+		minRowsPerChunk:         int(minRowsPerChunk),
+		tableRetryCount:         int(tableRetryCount),
+		tableRetryBackoff:       tableRetryBackoff,
+		where:                   where,
+		samplePercent:           samplePercent,
+		includeViews:            includeViews,
+		pklessTablesUseFullRow:  pklessTablesUseFullRow,
+		hashColumnsLargerThan:   int(hashColumnsLargerThan),
+		normalizeCharset:        normalizeCharset,
+		ignoreColumns:           ignoreColumns,
+		floatEpsilon:            floatEpsilon,
+		normalizeTimestamps:     normalizeTimestamps,
+	})
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot create worker")
+	}
+	return wrk, nil, nil, nil
+}
+
+func init() {
+	AddCommand("Diffs", Command{"MultiSplitDiff",
+		commandMultiSplitDiff, interactiveMultiSplitDiff,
+		"[--exclude_tables=''] <keyspace/shard>",
+		"Diffs a rdonly source shard against all its destination shards at once. Each source row is read only once"})
+}
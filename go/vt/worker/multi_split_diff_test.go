@@ -0,0 +1,284 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/wrangler"
+	"vitess.io/vitess/go/vt/wrangler/testlib"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+// multiSplitDiffTabletServer is a local QueryService implementation to
+// support the tests. It returns the rows of table1 with the ids from 0 to 99.
+// Even ids are in the key range -40 and odd ids in 40-80.
+type multiSplitDiffTabletServer struct {
+	t *testing.T
+
+	*fakes.StreamHealthQueryService
+	excludedTable string
+	// parity is 0 or 1 to return only the even or odd ids. It is -1 to
+	// return all rows.
+	parity int
+
+	// mu guards queries.
+	mu      sync.Mutex
+	queries int
+}
+
+func (sq *multiSplitDiffTabletServer) StreamExecute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions, callback func(reply *sqltypes.Result) error) error {
+	if strings.Contains(sql, sq.excludedTable) {
+		sq.t.Errorf("MultiSplitDiff should skip the excluded table: %v query: %v", sq.excludedTable, sql)
+	}
+	if strings.Contains(sql, "`keyspace_id`") && strings.Contains(sql, "WHERE") {
+		sq.t.Errorf("MultiSplitDiff should not filter by keyspace_id in SQL; query received: %v", sql)
+	}
+	sq.t.Logf("multiSplitDiffTabletServer: got query: %v", sql)
+	sq.mu.Lock()
+	sq.queries++
+	sq.mu.Unlock()
+
+	// Send the headers
+	if err := callback(&sqltypes.Result{
+		Fields: []*querypb.Field{
+			{
+				Name: "id",
+				Type: sqltypes.Int64,
+			},
+			{
+				Name: "msg",
+				Type: sqltypes.VarChar,
+			},
+			{
+				Name: "keyspace_id",
+				Type: sqltypes.Int64,
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	// Send the values
+	ksids := []uint64{0x2000000000000000, 0x6000000000000000}
+	for i := 0; i < 100; i++ {
+		if sq.parity != -1 && i%2 != sq.parity {
+			continue
+		}
+		if err := callback(&sqltypes.Result{
+			Rows: [][]sqltypes.Value{
+				{
+					sqltypes.NewVarBinary(fmt.Sprintf("%v", i)),
+					sqltypes.NewVarBinary(fmt.Sprintf("Text for %v", i)),
+					sqltypes.NewVarBinary(fmt.Sprintf("%v", ksids[i%2])),
+				},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func testMultiSplitDiff(t *testing.T, v3 bool) {
+	*useV3ReshardingMode = v3
+	ts := memorytopo.NewServer("cell1", "cell2")
+	ctx := context.Background()
+	wi := NewInstance(ts, "cell1", time.Second)
+
+	if v3 {
+		if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+			t.Fatalf("CreateKeyspace v3 failed: %v", err)
+		}
+
+		vs := &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"table1_index": {
+					Type: "numeric",
+				},
+			},
+			Tables: map[string]*vschemapb.Table{
+				"table1": {
+					ColumnVindexes: []*vschemapb.ColumnVindex{
+						{
+							Column: "keyspace_id",
+							Name:   "table1_index",
+						},
+					},
+				},
+			},
+		}
+		if err := ts.SaveVSchema(ctx, "ks", vs); err != nil {
+			t.Fatalf("SaveVSchema v3 failed: %v", err)
+		}
+	} else {
+		if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{
+			ShardingColumnName: "keyspace_id",
+			ShardingColumnType: topodatapb.KeyspaceIdType_UINT64,
+		}); err != nil {
+			t.Fatalf("CreateKeyspace failed: %v", err)
+		}
+	}
+
+	sourceMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 0,
+		topodatapb.TabletType_MASTER, nil, testlib.TabletKeyspaceShard(t, "ks", "-80"))
+	sourceRdonly1 := testlib.NewFakeTablet(t, wi.wr, "cell1", 1,
+		topodatapb.TabletType_RDONLY, nil, testlib.TabletKeyspaceShard(t, "ks", "-80"))
+	sourceRdonly2 := testlib.NewFakeTablet(t, wi.wr, "cell1", 2,
+		topodatapb.TabletType_RDONLY, nil, testlib.TabletKeyspaceShard(t, "ks", "-80"))
+
+	leftMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 10,
+		topodatapb.TabletType_MASTER, nil, testlib.TabletKeyspaceShard(t, "ks", "-40"))
+	leftRdonly := testlib.NewFakeTablet(t, wi.wr, "cell1", 11,
+		topodatapb.TabletType_RDONLY, nil, testlib.TabletKeyspaceShard(t, "ks", "-40"))
+
+	rightMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 20,
+		topodatapb.TabletType_MASTER, nil, testlib.TabletKeyspaceShard(t, "ks", "40-80"))
+	rightRdonly := testlib.NewFakeTablet(t, wi.wr, "cell1", 21,
+		topodatapb.TabletType_RDONLY, nil, testlib.TabletKeyspaceShard(t, "ks", "40-80"))
+
+	// add the topo and schema data we'll need
+	if err := ts.CreateShard(ctx, "ks", "80-"); err != nil {
+		t.Fatalf("CreateShard(\"80-\") failed: %v", err)
+	}
+	for _, shard := range []string{"-40", "40-80"} {
+		if err := wi.wr.SetSourceShards(ctx, "ks", shard, []*topodatapb.TabletAlias{sourceRdonly1.Tablet.Alias}, nil); err != nil {
+			t.Fatalf("SetSourceShards(%v) failed: %v", shard, err)
+		}
+	}
+	if err := wi.wr.SetKeyspaceShardingInfo(ctx, "ks", "keyspace_id", topodatapb.KeyspaceIdType_UINT64, false); err != nil {
+		t.Fatalf("SetKeyspaceShardingInfo failed: %v", err)
+	}
+	if err := wi.wr.RebuildKeyspaceGraph(ctx, "ks", nil); err != nil {
+		t.Fatalf("RebuildKeyspaceGraph failed: %v", err)
+	}
+
+	excludedTable := "excludedTable1"
+
+	for _, rdonly := range []*testlib.FakeTablet{sourceRdonly1, sourceRdonly2, leftRdonly, rightRdonly} {
+		rdonly.FakeMysqlDaemon.Schema = &tabletmanagerdatapb.SchemaDefinition{
+			DatabaseSchema: "",
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:              "table1",
+					Columns:           []string{"id", "msg", "keyspace_id"},
+					PrimaryKeyColumns: []string{"id"},
+					Type:              tmutils.TableBaseTable,
+				},
+				{
+					Name:              excludedTable,
+					Columns:           []string{"id", "msg", "keyspace_id"},
+					PrimaryKeyColumns: []string{"id"},
+					Type:              tmutils.TableBaseTable,
+				},
+			},
+		}
+	}
+
+	servers := make(map[*testlib.FakeTablet]*multiSplitDiffTabletServer)
+	for rdonly, parity := range map[*testlib.FakeTablet]int{sourceRdonly1: -1, sourceRdonly2: -1, leftRdonly: 0, rightRdonly: 1} {
+		qs := fakes.NewStreamHealthQueryService(rdonly.Target())
+		qs.AddDefaultHealthResponse()
+		server := &multiSplitDiffTabletServer{
+			t:                        t,
+			StreamHealthQueryService: qs,
+			excludedTable:            excludedTable,
+			parity:                   parity,
+		}
+		grpcqueryservice.Register(rdonly.RPCServer, server)
+		servers[rdonly] = server
+	}
+
+	// Start action loop after having registered all RPC services.
+	for _, ft := range []*testlib.FakeTablet{sourceMaster, sourceRdonly1, sourceRdonly2, leftMaster, leftRdonly, rightMaster, rightRdonly} {
+		ft.StartActionLoop(t, wi.wr)
+		defer ft.StopActionLoop(t)
+	}
+
+	// Run the vtworker command.
+	args := []string{
+		"MultiSplitDiff",
+		"-exclude_tables", excludedTable,
+		"ks/-80",
+	}
+	// We need to use FakeTabletManagerClient because we don't
+	// have a good way to fake the binlog player yet, which is
+	// necessary for synchronizing replication.
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, newFakeTMCTopo(ts))
+	if err := runCommand(t, wi, wr, args); err != nil {
+		t.Fatal(err)
+	}
+
+	// The source must have been read only once for both destinations.
+	sourceQueries := servers[sourceRdonly1].queries + servers[sourceRdonly2].queries
+	if got, want := sourceQueries, defaultDiffChunkCount; got > want {
+		t.Errorf("source got %v queries, want at most %v (one per chunk)", got, want)
+	}
+}
+
+func TestMultiSplitDiffv2(t *testing.T) {
+	testMultiSplitDiff(t, false)
+}
+
+func TestMultiSplitDiffv3(t *testing.T) {
+	testMultiSplitDiff(t, true)
+}
+
+func TestMaxReplicationPosition(t *testing.T) {
+	got, err := maxReplicationPosition([]string{"MariaDB/0-1-5", "MariaDB/0-1-10", "MariaDB/0-1-7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "MariaDB/0-1-10"; got != want {
+		t.Errorf("maxReplicationPosition() = %v, want %v", got, want)
+	}
+
+	if _, err := maxReplicationPosition([]string{"MariaDB/0-1-5", "MariaDB/1-1-10"}); err == nil {
+		t.Errorf("maxReplicationPosition() with positions of different domains should fail")
+	}
+}
+
+func TestMinReplicationPosition(t *testing.T) {
+	got, err := minReplicationPosition([]string{"MariaDB/0-1-7", "MariaDB/0-1-5", "MariaDB/0-1-10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "MariaDB/0-1-5"; got != want {
+		t.Errorf("minReplicationPosition() = %v, want %v", got, want)
+	}
+
+	if _, err := minReplicationPosition([]string{"MariaDB/0-1-5", "MariaDB/1-1-10"}); err == nil {
+		t.Errorf("minReplicationPosition() with positions of different domains should fail")
+	}
+}
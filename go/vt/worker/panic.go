@@ -52,6 +52,7 @@ func (pw *PanicWorker) StatusAsHTML() template.HTML {
 		result += "panic() should have been executed and logged by the vtworker framework.</br>\n"
 	}
 
+	result += pw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -65,13 +66,14 @@ func (pw *PanicWorker) StatusAsText() string {
 	case WorkerStateDone:
 		result += "panic() should have been executed and logged by the vtworker framework.\n"
 	}
+	result += pw.formatPanicText()
 	return result
 }
 
 // Run implements the Worker interface.
 func (pw *PanicWorker) Run(ctx context.Context) error {
-	resetVars()
-	err := pw.run(ctx)
+	pw.resetRunVars()
+	err := pw.runRecovered(ctx, pw.run)
 
 	pw.SetState(WorkerStateCleanUp)
 	if err != nil {
@@ -57,6 +57,7 @@ func (pw *PingWorker) StatusAsHTML() template.HTML {
 		result += "Logged message: '" + pw.message + "'</br>\n"
 	}
 
+	result += pw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -72,13 +73,14 @@ func (pw *PingWorker) StatusAsText() string {
 	case WorkerStateDone:
 		result += "Logged message: '" + pw.message + "'\n"
 	}
+	result += pw.formatPanicText()
 	return result
 }
 
 // Run implements the Worker interface.
 func (pw *PingWorker) Run(ctx context.Context) error {
-	resetVars()
-	err := pw.run(ctx)
+	pw.resetRunVars()
+	err := pw.runRecovered(ctx, pw.run)
 
 	pw.SetState(WorkerStateCleanUp)
 	if err != nil {
@@ -0,0 +1,125 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// preflightDiskSpaceHook is the name of the vthook which checks the free
+// disk space of a tablet. It is called with "--min_free_mb=<n>" and must
+// exit with a non-zero status if less space is available.
+const preflightDiskSpaceHook = "preflight_disk_space"
+
+var (
+	preflightChecks             = flag.Bool("preflight_checks", true, "If true, the diff workers check the chosen tablets (ping, replication running and lag, free disk space) before they synchronize replication and fail fast if a tablet is not healthy.")
+	preflightMaxReplicationLag  = flag.Duration("preflight_max_replication_lag", 0, "Maximum replication lag of a chosen tablet during the pre-flight checks. 0 means --remote_actions_stop_slave_timeout because stopping the tablet at the master position would time out anyway.")
+	preflightMinFreeDiskSpaceMB = flag.Int("preflight_min_free_disk_space_mb", 0, "Minimum free disk space (in MB) of a chosen tablet during the pre-flight checks. The check runs the '"+preflightDiskSpaceHook+"' hook on the tablet. 0 disables the check.")
+)
+
+// checkTabletsHealthy runs the pre-flight checks on the tablets which were
+// chosen by the findTargets phase:
+// - all tablets must respond to a Ping
+// - replication must be running on the "slaves" and their lag must be below
+//   --preflight_max_replication_lag
+// - all tablets must have --preflight_min_free_disk_space_mb free disk space
+// It returns the first failed check as error. This way, a worker fails fast
+// before it stops any replication instead of timing out in the middle of the
+// synchronizeReplication phase.
+func checkTabletsHealthy(ctx context.Context, wr *wrangler.Wrangler, masters, slaves []*topodatapb.TabletAlias) error {
+	if !*preflightChecks {
+		return nil
+	}
+
+	for _, alias := range masters {
+		if err := checkTabletHealthy(ctx, wr, alias, false /* replicating */); err != nil {
+			return err
+		}
+	}
+	for _, alias := range slaves {
+		if err := checkTabletHealthy(ctx, wr, alias, true /* replicating */); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkTabletHealthy(ctx context.Context, wr *wrangler.Wrangler, alias *topodatapb.TabletAlias, replicating bool) error {
+	tabletAlias := topoproto.TabletAliasString(alias)
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	ti, err := wr.TopoServer().GetTablet(shortCtx, alias)
+	if err != nil {
+		return vterrors.Wrapf(err, "pre-flight check: cannot read tablet %v", tabletAlias)
+	}
+
+	if err := wr.TabletManagerClient().Ping(shortCtx, ti.Tablet); err != nil {
+		return vterrors.Wrapf(err, "pre-flight check: tablet %v does not respond to Ping", tabletAlias)
+	}
+
+	if replicating {
+		status, err := wr.TabletManagerClient().SlaveStatus(shortCtx, ti.Tablet)
+		if err != nil {
+			return vterrors.Wrapf(err, "pre-flight check: SlaveStatus for tablet %v failed", tabletAlias)
+		}
+		if !status.SlaveIoRunning || !status.SlaveSqlRunning {
+			return fmt.Errorf("pre-flight check: replication is not running on tablet %v (IO thread running: %v, SQL thread running: %v). Start replication and retry", tabletAlias, status.SlaveIoRunning, status.SlaveSqlRunning)
+		}
+		maxLag := preflightMaxReplicationLagFor(ctx)
+		if lag := time.Duration(status.SecondsBehindMaster) * time.Second; lag > maxLag {
+			return fmt.Errorf("pre-flight check: replication lag of tablet %v is %v which is higher than the maximum of %v", tabletAlias, lag, maxLag)
+		}
+	}
+
+	if *preflightMinFreeDiskSpaceMB > 0 {
+		hk := hook.NewHook(preflightDiskSpaceHook, []string{fmt.Sprintf("--min_free_mb=%v", *preflightMinFreeDiskSpaceMB)})
+		hr, err := wr.TabletManagerClient().ExecuteHook(shortCtx, ti.Tablet, hk)
+		if err != nil {
+			return vterrors.Wrapf(err, "pre-flight check: cannot run the '%v' hook on tablet %v", preflightDiskSpaceHook, tabletAlias)
+		}
+		switch hr.ExitStatus {
+		case hook.HOOK_SUCCESS:
+		case hook.HOOK_DOES_NOT_EXIST:
+			return fmt.Errorf("pre-flight check: --preflight_min_free_disk_space_mb requires the '%v' hook but it does not exist on tablet %v", preflightDiskSpaceHook, tabletAlias)
+		default:
+			return fmt.Errorf("pre-flight check: tablet %v has less than %v MB free disk space: %v", tabletAlias, *preflightMinFreeDiskSpaceMB, hr.String())
+		}
+	}
+
+	return nil
+}
+
+// preflightMaxReplicationLagFor returns the maximum replication lag which the
+// pre-flight checks accept for the command which runs with "ctx".
+func preflightMaxReplicationLagFor(ctx context.Context) time.Duration {
+	if *preflightMaxReplicationLag > 0 {
+		return *preflightMaxReplicationLag
+	}
+	return stopSlaveMinimumTimeoutFor(ctx)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// preflightFakeTMC lets the pre-flight checks of selected tablets fail.
+type preflightFakeTMC struct {
+	tmclient.TabletManagerClient
+
+	unreachable string
+	status      map[string]*replicationdatapb.Status
+	hookStatus  int
+}
+
+// Ping is part of the tmclient.TabletManagerClient interface.
+func (f *preflightFakeTMC) Ping(ctx context.Context, tablet *topodatapb.Tablet) error {
+	if topoproto.TabletAliasString(tablet.Alias) == f.unreachable {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// SlaveStatus is part of the tmclient.TabletManagerClient interface.
+func (f *preflightFakeTMC) SlaveStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	if status, ok := f.status[topoproto.TabletAliasString(tablet.Alias)]; ok {
+		return status, nil
+	}
+	return f.TabletManagerClient.SlaveStatus(ctx, tablet)
+}
+
+// ExecuteHook is part of the tmclient.TabletManagerClient interface.
+func (f *preflightFakeTMC) ExecuteHook(ctx context.Context, tablet *topodatapb.Tablet, hk *hook.Hook) (*hook.HookResult, error) {
+	if hk.Name != preflightDiskSpaceHook {
+		return nil, errors.New("unexpected hook: " + hk.Name)
+	}
+	return &hook.HookResult{ExitStatus: f.hookStatus, Stderr: "only 10 MB free"}, nil
+}
+
+func TestCheckTabletsHealthy(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	master := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	rdonly := &topodatapb.TabletAlias{Cell: "cell1", Uid: 2}
+	for _, alias := range []*topodatapb.TabletAlias{master, rdonly} {
+		if err := ts.CreateTablet(ctx, &topodatapb.Tablet{Alias: alias, Keyspace: "ks", Shard: "0"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func(minFree int) { *preflightMinFreeDiskSpaceMB = minFree }(*preflightMinFreeDiskSpaceMB)
+	defer func(maxLag time.Duration) { *preflightMaxReplicationLag = maxLag }(*preflightMaxReplicationLag)
+	*preflightMaxReplicationLag = time.Minute
+
+	testcases := []struct {
+		desc        string
+		unreachable string
+		status      *replicationdatapb.Status
+		minFreeMB   int
+		hookStatus  int
+		wantErr     string
+	}{{
+		desc: "healthy",
+	}, {
+		desc:        "master does not respond",
+		unreachable: "cell1-0000000001",
+		wantErr:     "tablet cell1-0000000001 does not respond to Ping",
+	}, {
+		desc:    "replication stopped",
+		status:  &replicationdatapb.Status{SlaveIoRunning: true},
+		wantErr: "replication is not running on tablet cell1-0000000002",
+	}, {
+		desc:    "replication lag too high",
+		status:  &replicationdatapb.Status{SlaveIoRunning: true, SlaveSqlRunning: true, SecondsBehindMaster: 120},
+		wantErr: "replication lag of tablet cell1-0000000002 is 2m0s which is higher than the maximum of 1m0s",
+	}, {
+		desc:      "enough disk space",
+		minFreeMB: 1024,
+	}, {
+		desc:       "not enough disk space",
+		minFreeMB:  1024,
+		hookStatus: 1,
+		wantErr:    "tablet cell1-0000000001 has less than 1024 MB free disk space",
+	}, {
+		desc:       "disk space hook missing",
+		minFreeMB:  1024,
+		hookStatus: hook.HOOK_DOES_NOT_EXIST,
+		wantErr:    "requires the 'preflight_disk_space' hook",
+	}}
+	for _, tc := range testcases {
+		tmc := &preflightFakeTMC{
+			TabletManagerClient: newFakeTMCTopo(ts),
+			unreachable:         tc.unreachable,
+			status:              map[string]*replicationdatapb.Status{},
+			hookStatus:          tc.hookStatus,
+		}
+		if tc.status != nil {
+			tmc.status["cell1-0000000002"] = tc.status
+		}
+		*preflightMinFreeDiskSpaceMB = tc.minFreeMB
+		wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmc)
+
+		err := checkTabletsHealthy(ctx, wr, []*topodatapb.TabletAlias{master}, []*topodatapb.TabletAlias{rdonly})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%v: checkTabletsHealthy() failed: %v", tc.desc, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%v: checkTabletsHealthy() = %v, want error containing: %v", tc.desc, err, tc.wantErr)
+		}
+	}
+}
+
+func TestCheckTabletsHealthyDisabled(t *testing.T) {
+	defer func(enabled bool) { *preflightChecks = enabled }(*preflightChecks)
+	*preflightChecks = false
+
+	// The tablet does not exist but the checks are skipped.
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, newFakeTMCTopo(ts))
+	if err := checkTabletsHealthy(context.Background(), wr, nil, []*topodatapb.TabletAlias{{Cell: "cell1", Uid: 1}}); err != nil {
+		t.Errorf("checkTabletsHealthy() with disabled checks failed: %v", err)
+	}
+}
+
+func TestPreflightMaxReplicationLagDefault(t *testing.T) {
+	ctx := newRemoteActionTimeoutsContext(context.Background(), &remoteActionTimeouts{
+		remoteActions:    time.Minute,
+		stopSlaveMinimum: time.Hour,
+	})
+	if got, want := preflightMaxReplicationLagFor(ctx), time.Hour; got != want {
+		t.Errorf("preflightMaxReplicationLagFor() = %v, want = %v", got, want)
+	}
+}
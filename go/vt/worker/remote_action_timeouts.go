@@ -0,0 +1,114 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+var (
+	stopSlaveMinimumTimeout = flag.Duration("remote_actions_stop_slave_timeout", 0, "Amount of time to wait for StopSlaveMinimum i.e. until a tablet has replicated up to a position and stopped replication. 0 means --remote_actions_timeout.")
+	getSchemaTimeout        = flag.Duration("remote_actions_get_schema_timeout", 0, "Amount of time to wait for GetSchema. Keyspaces with thousands of tables may require a higher value than --remote_actions_timeout. 0 means --remote_actions_timeout.")
+	stopBlpTimeout          = flag.Duration("remote_actions_stop_blp_timeout", 0, "Amount of time to wait for stopping filtered replication (vreplication) on a destination master. 0 means --remote_actions_timeout.")
+)
+
+// remoteActionTimeouts are the timeouts of the RPCs which the workers send
+// to the tablets.
+// Each command can override the values of the global flags with its own
+// flags (see addFlags()).
+type remoteActionTimeouts struct {
+	// remoteActions is the timeout for all RPCs which have no specific
+	// timeout below.
+	remoteActions time.Duration
+	// The timeouts below default to remoteActions if they are 0.
+	stopSlaveMinimum time.Duration
+	getSchema        time.Duration
+	stopBlp          time.Duration
+}
+
+// remoteActionTimeoutsKey is the context key for the remoteActionTimeouts of
+// a command.
+type remoteActionTimeoutsKey struct{}
+
+// addFlags adds the per-command flags which override the timeouts to
+// "subFlags". Their defaults are the values of the global flags.
+func (t *remoteActionTimeouts) addFlags(subFlags *flag.FlagSet) {
+	subFlags.DurationVar(&t.remoteActions, "remote_actions_timeout", *remoteActionsTimeout, "Amount of time to wait for remote actions (like replication stop, ...) of this command")
+	subFlags.DurationVar(&t.stopSlaveMinimum, "remote_actions_stop_slave_timeout", *stopSlaveMinimumTimeout, "Amount of time to wait for StopSlaveMinimum of this command. 0 means --remote_actions_timeout.")
+	subFlags.DurationVar(&t.getSchema, "remote_actions_get_schema_timeout", *getSchemaTimeout, "Amount of time to wait for GetSchema of this command. 0 means --remote_actions_timeout.")
+	subFlags.DurationVar(&t.stopBlp, "remote_actions_stop_blp_timeout", *stopBlpTimeout, "Amount of time to wait for stopping filtered replication of this command. 0 means --remote_actions_timeout.")
+}
+
+// newRemoteActionTimeoutsContext returns a context which carries the timeouts
+// of a command. The worker functions look them up with the *TimeoutFor()
+// functions below.
+func newRemoteActionTimeoutsContext(ctx context.Context, t *remoteActionTimeouts) context.Context {
+	if t == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteActionTimeoutsKey{}, t)
+}
+
+// remoteActionTimeoutsFromContext returns the timeouts of the command which
+// runs with "ctx". Without an override, it returns the global flag values.
+func remoteActionTimeoutsFromContext(ctx context.Context) *remoteActionTimeouts {
+	if t, ok := ctx.Value(remoteActionTimeoutsKey{}).(*remoteActionTimeouts); ok {
+		return t
+	}
+	return &remoteActionTimeouts{
+		remoteActions:    *remoteActionsTimeout,
+		stopSlaveMinimum: *stopSlaveMinimumTimeout,
+		getSchema:        *getSchemaTimeout,
+		stopBlp:          *stopBlpTimeout,
+	}
+}
+
+// orDefault returns "timeout" or, if it is not set, the timeout of the
+// general remote actions.
+func (t *remoteActionTimeouts) orDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return t.remoteActions
+	}
+	return timeout
+}
+
+// remoteActionsTimeoutFor returns the timeout for a remote action of the
+// command which runs with "ctx".
+func remoteActionsTimeoutFor(ctx context.Context) time.Duration {
+	return remoteActionTimeoutsFromContext(ctx).remoteActions
+}
+
+// stopSlaveMinimumTimeoutFor returns the timeout for StopSlaveMinimum.
+func stopSlaveMinimumTimeoutFor(ctx context.Context) time.Duration {
+	t := remoteActionTimeoutsFromContext(ctx)
+	return t.orDefault(t.stopSlaveMinimum)
+}
+
+// getSchemaTimeoutFor returns the timeout for GetSchema.
+func getSchemaTimeoutFor(ctx context.Context) time.Duration {
+	t := remoteActionTimeoutsFromContext(ctx)
+	return t.orDefault(t.getSchema)
+}
+
+// stopBlpTimeoutFor returns the timeout for stopping filtered replication.
+func stopBlpTimeoutFor(ctx context.Context) time.Duration {
+	t := remoteActionTimeoutsFromContext(ctx)
+	return t.orDefault(t.stopBlp)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestRemoteActionTimeoutsDefaults(t *testing.T) {
+	ctx := context.Background()
+	if got, want := remoteActionsTimeoutFor(ctx), *remoteActionsTimeout; got != want {
+		t.Errorf("remoteActionsTimeoutFor() = %v, want = %v", got, want)
+	}
+	// Without specific timeouts, all remote actions use the general one.
+	for name, f := range map[string]func(context.Context) time.Duration{
+		"stopSlaveMinimumTimeoutFor": stopSlaveMinimumTimeoutFor,
+		"getSchemaTimeoutFor":        getSchemaTimeoutFor,
+		"stopBlpTimeoutFor":          stopBlpTimeoutFor,
+	} {
+		if got, want := f(ctx), *remoteActionsTimeout; got != want {
+			t.Errorf("%v() = %v, want = %v", name, got, want)
+		}
+	}
+}
+
+func TestRemoteActionTimeoutsOverride(t *testing.T) {
+	ctx := newRemoteActionTimeoutsContext(context.Background(), &remoteActionTimeouts{
+		remoteActions: 2 * time.Minute,
+		getSchema:     time.Hour,
+	})
+	// Derived contexts keep the timeouts.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if got, want := remoteActionsTimeoutFor(ctx), 2*time.Minute; got != want {
+		t.Errorf("remoteActionsTimeoutFor() = %v, want = %v", got, want)
+	}
+	if got, want := getSchemaTimeoutFor(ctx), time.Hour; got != want {
+		t.Errorf("getSchemaTimeoutFor() = %v, want = %v", got, want)
+	}
+	if got, want := stopSlaveMinimumTimeoutFor(ctx), 2*time.Minute; got != want {
+		t.Errorf("stopSlaveMinimumTimeoutFor() = %v, want = %v", got, want)
+	}
+}
+
+func TestCommandWorkerRemoteActionTimeoutFlags(t *testing.T) {
+	wi := NewInstance(memorytopo.NewServer("cell1"), "cell1", time.Second)
+	_, timeouts, err := commandWorker(wi, wi.wr, []string{"Ping", "-remote_actions_timeout", "3m", "-remote_actions_get_schema_timeout", "1h", "pong"}, "cell1", false /* runFromCli */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := newRemoteActionTimeoutsContext(context.Background(), timeouts)
+	if got, want := remoteActionsTimeoutFor(ctx), 3*time.Minute; got != want {
+		t.Errorf("remoteActionsTimeoutFor() = %v, want = %v", got, want)
+	}
+	if got, want := getSchemaTimeoutFor(ctx), time.Hour; got != want {
+		t.Errorf("getSchemaTimeoutFor() = %v, want = %v", got, want)
+	}
+	if got, want := stopBlpTimeoutFor(ctx), 3*time.Minute; got != want {
+		t.Errorf("stopBlpTimeoutFor() = %v, want = %v", got, want)
+	}
+}
@@ -180,6 +180,11 @@ func (r *RestartableResultReader) Next() (*sqltypes.Result, error) {
 	if result != nil && len(result.Rows) > 0 {
 		r.lastRow = result.Rows[len(result.Rows)-1]
 	}
+	if err == nil {
+		if err := scanThrottlerForTablet(r.tablet.Alias).throttle(r.ctx, result); err != nil {
+			return nil, err
+		}
+	}
 	return result, err
 }
 
@@ -0,0 +1,158 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"io"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// demuxedResultsBufferSize is the number of Results which are buffered for
+// each output of a ResultDemuxer.
+const demuxedResultsBufferSize = 10
+
+// ResultDemuxer is the opposite of the ResultMerger: It reads a single
+// ResultReader input stream and splits it into one output stream per key
+// range. Each row goes to the first key range which contains its keyspace id.
+// Rows which are in none of the key ranges are dropped.
+// The order of the rows within each output is the same as in the input.
+//
+// The outputs must be read concurrently because the ResultDemuxer blocks
+// while the buffer of an output is full.
+type ResultDemuxer struct {
+	ctx       context.Context
+	input     ResultReader
+	resolver  keyspaceIDResolver
+	keyRanges []*topodatapb.KeyRange
+	outputs   []*demuxedResultReader
+}
+
+// NewResultDemuxer returns a new ResultDemuxer. Run() must be called to start
+// the demultiplexing. Once "ctx" is done, the ResultDemuxer stops and all
+// outputs return the error of the context.
+func NewResultDemuxer(ctx context.Context, input ResultReader, resolver keyspaceIDResolver, keyRanges []*topodatapb.KeyRange) *ResultDemuxer {
+	outputs := make([]*demuxedResultReader, len(keyRanges))
+	for i := range outputs {
+		outputs[i] = &demuxedResultReader{
+			ctx:     ctx,
+			fields:  input.Fields(),
+			results: make(chan demuxedResult, demuxedResultsBufferSize),
+		}
+	}
+	return &ResultDemuxer{
+		ctx:       ctx,
+		input:     input,
+		resolver:  resolver,
+		keyRanges: keyRanges,
+		outputs:   outputs,
+	}
+}
+
+// Output returns the ResultReader for the i-th key range.
+func (d *ResultDemuxer) Output(i int) ResultReader {
+	return d.outputs[i]
+}
+
+// Run reads the input until it's exhausted or fails. The end of the input
+// (or its error) is forwarded to all outputs.
+// It should be called in a separate Go routine.
+func (d *ResultDemuxer) Run() {
+	err := d.demux()
+	for _, o := range d.outputs {
+		select {
+		case o.results <- demuxedResult{err: err}:
+		case <-d.ctx.Done():
+		}
+		close(o.results)
+	}
+}
+
+func (d *ResultDemuxer) demux() error {
+	for {
+		result, err := d.input.Next()
+		if err != nil {
+			return err
+		}
+
+		rows := make([][][]sqltypes.Value, len(d.outputs))
+		for _, row := range result.Rows {
+			keyspaceID, err := d.resolver.keyspaceID(row)
+			if err != nil {
+				return err
+			}
+			for i, keyRange := range d.keyRanges {
+				if key.KeyRangeContains(keyRange, keyspaceID) {
+					rows[i] = append(rows[i], row)
+					break
+				}
+			}
+		}
+
+		for i, o := range d.outputs {
+			if len(rows[i]) == 0 {
+				continue
+			}
+			select {
+			case o.results <- demuxedResult{result: &sqltypes.Result{Rows: rows[i]}}:
+			case <-d.ctx.Done():
+				return d.ctx.Err()
+			}
+		}
+	}
+}
+
+// demuxedResult is either a Result or the error which ended the input.
+type demuxedResult struct {
+	result *sqltypes.Result
+	err    error
+}
+
+// demuxedResultReader is an output of the ResultDemuxer.
+// It implements the ResultReader interface.
+type demuxedResultReader struct {
+	ctx     context.Context
+	fields  []*querypb.Field
+	results chan demuxedResult
+}
+
+// Fields is part of the ResultReader interface.
+func (r *demuxedResultReader) Fields() []*querypb.Field {
+	return r.fields
+}
+
+// Next is part of the ResultReader interface.
+func (r *demuxedResultReader) Next() (*sqltypes.Result, error) {
+	select {
+	case dr, ok := <-r.results:
+		if !ok {
+			return nil, io.EOF
+		}
+		if dr.err != nil {
+			return nil, dr.err
+		}
+		return dr.result, nil
+	case <-r.ctx.Done():
+		return nil, r.ctx.Err()
+	}
+}
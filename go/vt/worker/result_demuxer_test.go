@@ -0,0 +1,118 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// parityResolver maps rows with an even id to the keyspace id 0x00 and rows
+// with an odd id to 0x80.
+type parityResolver struct{}
+
+func (parityResolver) keyspaceID(row []sqltypes.Value) ([]byte, error) {
+	id, err := sqltypes.ToInt64(row[0])
+	if err != nil {
+		return nil, err
+	}
+	if id%2 == 0 {
+		return []byte{0x00}, nil
+	}
+	return []byte{0x80}, nil
+}
+
+func TestResultDemuxer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keyRanges, err := key.ParseShardingSpec("-80-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := newFakeResultReader(singlePk, 0, []int{1}, 1000)
+	d := NewResultDemuxer(ctx, input, parityResolver{}, keyRanges)
+	go d.Run()
+
+	// The outputs must be read concurrently.
+	ids := make([][]int64, len(keyRanges))
+	errs := make([]error, len(keyRanges))
+	var wg sync.WaitGroup
+	for i := range keyRanges {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rr := NewRowReader(d.Output(i))
+			for {
+				row, err := rr.Next()
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				if row == nil {
+					return
+				}
+				id, err := sqltypes.ToInt64(row[0])
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				ids[i] = append(ids[i], id)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, keyRange := range keyRanges {
+		if errs[i] != nil {
+			t.Fatalf("output %v failed: %v", key.KeyRangeString(keyRange), errs[i])
+		}
+		if got, want := len(ids[i]), 500; got != want {
+			t.Errorf("output %v has %v rows, want %v", key.KeyRangeString(keyRange), got, want)
+		}
+		for j, id := range ids[i] {
+			if want := int64(2*j + i); id != want {
+				t.Fatalf("row %v of output %v has id %v, want %v", j, key.KeyRangeString(keyRange), id, want)
+			}
+		}
+	}
+}
+
+func TestResultDemuxerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	keyRanges := []*topodatapb.KeyRange{{}}
+	input := newFakeResultReader(singlePk, 0, []int{1}, 1000)
+	d := NewResultDemuxer(ctx, input, parityResolver{}, keyRanges)
+	done := make(chan struct{})
+	go func() {
+		d.Run()
+		close(done)
+	}()
+
+	// Nobody reads the output. Run() must return anyway once the context is
+	// canceled.
+	cancel()
+	<-done
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package worker
 
 import (
+	"golang.org/x/net/context"
+
 	"vitess.io/vitess/go/sqltypes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -40,3 +42,32 @@ type ResultReader interface {
 	// It will return io.EOF if the stream ended.
 	Next() (*sqltypes.Result, error)
 }
+
+// closableResultReader is a ResultReader which must be closed after use.
+type closableResultReader interface {
+	ResultReader
+
+	// Close releases the resources of the reader.
+	Close(ctx context.Context) error
+}
+
+// pausableResultReader blocks in Next() while the worker "w" is paused.
+// This way, a paused worker stops reading from the tablets after the current
+// result was processed.
+type pausableResultReader struct {
+	ResultReader
+	ctx context.Context
+	w   *StatusWorker
+}
+
+func newPausableResultReader(ctx context.Context, w *StatusWorker, r ResultReader) *pausableResultReader {
+	return &pausableResultReader{ResultReader: r, ctx: ctx, w: w}
+}
+
+// Next is part of the ResultReader interface.
+func (r *pausableResultReader) Next() (*sqltypes.Result, error) {
+	if err := r.w.waitIfPaused(r.ctx); err != nil {
+		return nil, err
+	}
+	return r.ResultReader.Next()
+}
@@ -32,7 +32,7 @@ import (
 
 // RowAggregator aggregates SQL reconciliation statements into one statement.
 // Once a limit (maxRows or maxSize) is reached, the statement will be sent to
-// the destination's insertChannel.
+// the destination's queue.
 // RowAggregator is also aware of the type of statement (DiffType) and
 // constructs the necessary SQL command based on that.
 // Aggregating multiple statements is done to improve the overall performance.
@@ -44,11 +44,17 @@ type RowAggregator struct {
 	ctx           context.Context
 	maxRows       int
 	maxSize       int
-	insertChannel chan string
+	queue         *destinationQueue
 	td            *tabletmanagerdatapb.TableDefinition
 	diffType      DiffType
 	builder       QueryBuilder
 	statsCounters *stats.CountersWithSingleLabel
+	// writtenRows is optional. If set, it is called with the number of rows
+	// of each query which was sent to the queue.
+	writtenRows func(rows int)
+	// writes is optional. If set, it tracks each query which was sent to
+	// the queue until the destination executed it.
+	writes *chunkWrites
 
 	buffer       bytes.Buffer
 	bufferedRows int
@@ -58,7 +64,7 @@ type RowAggregator struct {
 // The index of the elements in statCounters must match the elements
 // in "DiffTypes" i.e. the first counter is for inserts, second for updates
 // and the third for deletes.
-func NewRowAggregator(ctx context.Context, maxRows, maxSize int, insertChannel chan string, dbName string, td *tabletmanagerdatapb.TableDefinition, diffType DiffType, statsCounters *stats.CountersWithSingleLabel) *RowAggregator {
+func NewRowAggregator(ctx context.Context, maxRows, maxSize int, queue *destinationQueue, dbName string, td *tabletmanagerdatapb.TableDefinition, diffType DiffType, statsCounters *stats.CountersWithSingleLabel) *RowAggregator {
 	// Construct head and tail base commands for the reconciliation statement.
 	var builder QueryBuilder
 	switch diffType {
@@ -81,7 +87,7 @@ func NewRowAggregator(ctx context.Context, maxRows, maxSize int, insertChannel c
 		ctx:           ctx,
 		maxRows:       maxRows,
 		maxSize:       maxSize,
-		insertChannel: insertChannel,
+		queue:         queue,
 		td:            td,
 		diffType:      diffType,
 		builder:       builder,
@@ -120,15 +126,31 @@ func (ra *RowAggregator) Flush() error {
 	}
 
 	ra.builder.WriteTail(&ra.buffer)
-	// select blocks until sending the SQL succeeded or the context was canceled.
-	select {
-	case ra.insertChannel <- ra.buffer.String():
-	case <-ra.ctx.Done():
-		return fmt.Errorf("failed to flush RowAggregator and send the query to a writer thread channel: %v", ra.ctx.Err())
+	q := &writeQuery{
+		sql:    ra.buffer.String(),
+		rows:   ra.bufferedRows,
+		writes: ra.writes,
+	}
+	if ib, ok := ra.builder.(*InsertsQueryBuilder); ok {
+		// INSERTs have no tail and the executor may append more rows.
+		q.mergeHead = ib.head
+	}
+	if ra.writes != nil {
+		ra.writes.add()
+	}
+	// send blocks until sending the SQL succeeded or the context was canceled.
+	if err := ra.queue.send(ra.ctx, q); err != nil {
+		if ra.writes != nil {
+			ra.writes.done()
+		}
+		return fmt.Errorf("failed to flush RowAggregator and send the query to a writer thread channel: %v", err)
 	}
 
 	// Update our statistics.
 	ra.statsCounters.Add(ra.td.Name, int64(ra.bufferedRows))
+	if ra.writtenRows != nil {
+		ra.writtenRows(ra.bufferedRows)
+	}
 
 	ra.buffer.Reset()
 	ra.bufferedRows = 0
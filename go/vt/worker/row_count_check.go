@@ -0,0 +1,87 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// rowCountQuery returns a query which counts the rows of "td" which match
+// the optional filter "where".
+func rowCountQuery(td *tabletmanagerdatapb.TableDefinition, where string) string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM %v%v", sqlescape.EscapeID(td.Name), whereClause(nil, where))
+}
+
+// countRows runs the row count query for "td" on the tablet.
+func countRows(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, where string) (uint64, error) {
+	sql := rowCountQuery(td, where)
+	reader, err := NewQueryResultReaderForTablet(ctx, wr.TopoServer(), tabletAlias, sql)
+	if err != nil {
+		return 0, vterrors.Wrapf(err, "tablet=%v table=%v: row count query failed", topoproto.TabletAliasString(tabletAlias), td.Name)
+	}
+	defer reader.Close(ctx)
+
+	rowReader := NewRowReader(reader)
+	row, err := rowReader.Next()
+	if err != nil {
+		return 0, vterrors.Wrapf(err, "tablet=%v table=%v: cannot read row count", topoproto.TabletAliasString(tabletAlias), td.Name)
+	}
+	if row == nil || len(row) != 1 {
+		return 0, fmt.Errorf("tablet=%v table=%v: row count query returned an unexpected result: %v", topoproto.TabletAliasString(tabletAlias), td.Name, row)
+	}
+	// Drain the stream to make sure that it did not fail at the end.
+	if _, err := rowReader.Drain(); err != nil {
+		return 0, vterrors.Wrapf(err, "tablet=%v table=%v: cannot read row count", topoproto.TabletAliasString(tabletAlias), td.Name)
+	}
+	count, err := sqltypes.ToUint64(row[0])
+	if err != nil {
+		return 0, vterrors.Wrapf(err, "tablet=%v table=%v: invalid row count", topoproto.TabletAliasString(tabletAlias), td.Name)
+	}
+	return count, nil
+}
+
+// checkRowCount compares the number of rows of "td" on the source and the
+// destination tablet. It returns an error if they differ. Tables which fail
+// this check do not need a row by row comparison to be reported as broken.
+// "sourceWhere" and "destinationWhere" are optional filters which are applied
+// on the respective tablet.
+func checkRowCount(ctx context.Context, wr *wrangler.Wrangler, sourceAlias, destinationAlias *topodatapb.TabletAlias, td *tabletmanagerdatapb.TableDefinition, sourceWhere, destinationWhere string) error {
+	sourceCount, err := countRows(ctx, wr, sourceAlias, td, sourceWhere)
+	if err != nil {
+		return err
+	}
+	destinationCount, err := countRows(ctx, wr, destinationAlias, td, destinationWhere)
+	if err != nil {
+		return err
+	}
+	if sourceCount != destinationCount {
+		return fmt.Errorf("Table %v has a row count mismatch: source has %v rows, destination has %v rows", td.Name, sourceCount, destinationCount)
+	}
+	wr.Logger().Infof("table=%v: row counts match (%v rows). Comparing all rows.", td.Name, sourceCount)
+	return nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestRowCountQuery(t *testing.T) {
+	td := &tabletmanagerdatapb.TableDefinition{
+		Name:              "t1",
+		Columns:           []string{"id", "msg"},
+		PrimaryKeyColumns: []string{"id"},
+	}
+
+	testcases := []struct {
+		where string
+		want  string
+	}{
+		{"", "SELECT COUNT(*) FROM `t1`"},
+		{"`keyspace_id` < 9223372036854775808", "SELECT COUNT(*) FROM `t1` WHERE `keyspace_id` < 9223372036854775808"},
+	}
+	for _, tc := range testcases {
+		if got := rowCountQuery(td, tc.where); got != tc.want {
+			t.Errorf("rowCountQuery(%q) = %v, want = %v", tc.where, got, tc.want)
+		}
+	}
+}
@@ -83,7 +83,7 @@ type RowDiffer2 struct {
 
 // NewRowDiffer2 returns a new RowDiffer2.
 // We assume that the indexes of the slice parameters always correspond to the
-// same shard e.g. insertChannels[0] refers to destinationShards[0] and so on.
+// same shard e.g. destinationQueues[0] refers to destinationShards[0] and so on.
 // The column list td.Columns must be have all primary key columns first and
 // then the non-primary-key columns. The columns in the rows returned by
 // both ResultReader must have the same order as td.Columns.
@@ -91,7 +91,7 @@ func NewRowDiffer2(ctx context.Context, left, right ResultReader, td *tabletmana
 	// Parameters required by RowRouter.
 	destinationShards []*topo.ShardInfo, keyResolver keyspaceIDResolver,
 	// Parameters required by RowAggregator.
-	insertChannels []chan string, abort <-chan struct{}, dbNames []string, writeQueryMaxRows, writeQueryMaxSize int, statsCounters []*stats.CountersWithSingleLabel) (*RowDiffer2, error) {
+	destinationQueues []*destinationQueue, abort <-chan struct{}, dbNames []string, writeQueryMaxRows, writeQueryMaxSize int, statsCounters []*stats.CountersWithSingleLabel) (*RowDiffer2, error) {
 
 	if len(statsCounters) != len(DiffTypes) {
 		panic(fmt.Sprintf("statsCounter has the wrong number of elements. got = %v, want = %v", len(statsCounters), len(DiffTypes)))
@@ -101,6 +101,9 @@ func NewRowDiffer2(ctx context.Context, left, right ResultReader, td *tabletmana
 		return nil, err
 	}
 
+	addWrittenRows := func(rows int) {
+		tableStatusList.addWrittenRows(tableIndex, rows)
+	}
 	// Create a RowAggregator for each destination shard and DiffType.
 	aggregators := make([][]*RowAggregator, len(destinationShards))
 	for i := range destinationShards {
@@ -108,13 +111,22 @@ func NewRowDiffer2(ctx context.Context, left, right ResultReader, td *tabletmana
 		for _, typ := range DiffFoundTypes {
 			maxRows := writeQueryMaxRows
 			aggregators[i][typ] = NewRowAggregator(ctx, maxRows, writeQueryMaxSize,
-				insertChannels[i], dbNames[i], td, typ, statsCounters[typ])
+				destinationQueues[i], dbNames[i], td, typ, statsCounters[typ])
+			aggregators[i][typ].writtenRows = addWrittenRows
 		}
 	}
 
+	addReadRows := func(rows int) {
+		tableStatusList.addReadRows(tableIndex, rows)
+	}
+	leftReader := NewRowReader(left)
+	leftReader.readRows = addReadRows
+	rightReader := NewRowReader(right)
+	rightReader.readRows = addReadRows
+
 	return &RowDiffer2{
-		left:                   NewRowReader(left),
-		right:                  NewRowReader(right),
+		left:                   leftReader,
+		right:                  rightReader,
 		pkFieldCount:           len(td.PrimaryKeyColumns),
 		tableStatusList:        tableStatusList,
 		tableIndex:             tableIndex,
@@ -125,6 +137,16 @@ func NewRowDiffer2(ctx context.Context, left, right ResultReader, td *tabletmana
 	}, nil
 }
 
+// trackWrites makes the RowDiffer2 report all reconciliation queries of
+// the diff to "writes".
+func (rd *RowDiffer2) trackWrites(writes *chunkWrites) {
+	for i := range rd.aggregators {
+		for _, aggregator := range rd.aggregators[i] {
+			aggregator.writes = writes
+		}
+	}
+}
+
 func compareFields(left, right []*querypb.Field) error {
 	if len(left) != len(right) {
 		return fmt.Errorf("Cannot diff inputs with different number of fields: left: %v right: %v", left, right)
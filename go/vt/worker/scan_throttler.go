@@ -0,0 +1,122 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"sync"
+
+	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	scanMaxRowsPerSecond = flag.Int("scan_max_rows_per_second", 0, "maximum number of rows per second which all table scans of the workers read from a single tablet. 0 means unlimited.")
+	scanMaxMBPerSecond   = flag.Int("scan_max_mb_per_second", 0, "maximum number of MB per second which all table scans of the workers read from a single tablet. 0 means unlimited.")
+)
+
+// scanThrottler limits the rate at which table scans read from a tablet.
+// It has a token bucket for the number of rows and one for the number of
+// bytes. Both are optional.
+type scanThrottler struct {
+	rows  *rate.Limiter
+	bytes *rate.Limiter
+}
+
+var (
+	// scanThrottlersMu guards scanThrottlers.
+	scanThrottlersMu sync.Mutex
+	// scanThrottlers has the scanThrottler of each tablet, keyed by alias.
+	// All scans on the same tablet share the throttler.
+	scanThrottlers = make(map[string]*scanThrottler)
+)
+
+// scanThrottlerForTablet returns the scanThrottler for "alias".
+// It returns nil if neither --scan_max_rows_per_second nor
+// --scan_max_mb_per_second is set.
+func scanThrottlerForTablet(alias *topodatapb.TabletAlias) *scanThrottler {
+	return getScanThrottler(topoproto.TabletAliasString(alias), *scanMaxRowsPerSecond, *scanMaxMBPerSecond*1024*1024)
+}
+
+func getScanThrottler(key string, maxRowsPerSecond, maxBytesPerSecond int) *scanThrottler {
+	if maxRowsPerSecond <= 0 && maxBytesPerSecond <= 0 {
+		return nil
+	}
+
+	scanThrottlersMu.Lock()
+	defer scanThrottlersMu.Unlock()
+	if t, ok := scanThrottlers[key]; ok {
+		return t
+	}
+	t := &scanThrottler{}
+	// The burst is one second worth of tokens.
+	if maxRowsPerSecond > 0 {
+		t.rows = rate.NewLimiter(rate.Limit(maxRowsPerSecond), maxRowsPerSecond)
+	}
+	if maxBytesPerSecond > 0 {
+		t.bytes = rate.NewLimiter(rate.Limit(maxBytesPerSecond), maxBytesPerSecond)
+	}
+	scanThrottlers[key] = t
+	return t
+}
+
+// throttle blocks until the rows and bytes of "result" are within the rate
+// limits. It is a no-op for a nil throttler.
+func (t *scanThrottler) throttle(ctx context.Context, result *sqltypes.Result) error {
+	if t == nil || result == nil {
+		return nil
+	}
+	if err := waitN(ctx, t.rows, len(result.Rows)); err != nil {
+		return err
+	}
+	return waitN(ctx, t.bytes, resultSize(result))
+}
+
+// waitN is the same as Limiter.WaitN() but supports an "n" which is greater
+// than the burst of the limiter. It is a no-op for a nil limiter.
+func waitN(ctx context.Context, limiter *rate.Limiter, n int) error {
+	if limiter == nil {
+		return nil
+	}
+	for n > 0 {
+		wait := n
+		if wait > limiter.Burst() {
+			wait = limiter.Burst()
+		}
+		if err := limiter.WaitN(ctx, wait); err != nil {
+			return err
+		}
+		n -= wait
+	}
+	return nil
+}
+
+// resultSize returns the number of bytes of all values in "result".
+func resultSize(result *sqltypes.Result) int {
+	size := 0
+	for _, row := range result.Rows {
+		for _, v := range row {
+			size += v.Len()
+		}
+	}
+	return size
+}
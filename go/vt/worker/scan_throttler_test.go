@@ -0,0 +1,71 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestGetScanThrottler(t *testing.T) {
+	if got := getScanThrottler("cell1-0000000001", 0, 0); got != nil {
+		t.Errorf("getScanThrottler() without limits = %v, want nil", got)
+	}
+
+	t1 := getScanThrottler("cell1-0000000001", 100, 0)
+	if t1 == nil || t1.rows == nil || t1.bytes != nil {
+		t.Fatalf("getScanThrottler() = %+v, want a throttler with a rows limiter only", t1)
+	}
+	if got := getScanThrottler("cell1-0000000001", 100, 0); got != t1 {
+		t.Errorf("getScanThrottler() for the same tablet must return the same throttler")
+	}
+	if got := getScanThrottler("cell1-0000000002", 100, 0); got == t1 {
+		t.Errorf("getScanThrottler() for a different tablet must return a different throttler")
+	}
+
+	// A nil throttler does not throttle.
+	var nilThrottler *scanThrottler
+	if err := nilThrottler.throttle(context.Background(), &sqltypes.Result{}); err != nil {
+		t.Errorf("throttle() on a nil throttler failed: %v", err)
+	}
+}
+
+func TestScanThrottlerLargerThanBurst(t *testing.T) {
+	throttler := getScanThrottler("TestScanThrottlerLargerThanBurst", 100000, 100000)
+	result := &sqltypes.Result{}
+	for i := 0; i < 120000; i++ {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarBinary("a")})
+	}
+	if got, want := resultSize(result), 120000; got != want {
+		t.Errorf("resultSize() = %v, want = %v", got, want)
+	}
+
+	// A result with more rows than the burst must not fail.
+	if err := throttler.throttle(context.Background(), result); err != nil {
+		t.Fatalf("throttle() failed: %v", err)
+	}
+
+	// The limit is exceeded and the context has no time left to wait.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := throttler.throttle(ctx, result); err == nil {
+		t.Errorf("throttle() with a canceled context should have failed")
+	}
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// SchemaDiffWorker compares only the schemas of the source and the
+// destination shards of a split, or of two arbitrary tablets.
+// Unlike SplitDiff, it does not take any tablet out of serving and does not
+// stop replication. It is meant as a cheap pre-flight check before the data
+// diff is scheduled.
+type SchemaDiffWorker struct {
+	StatusWorker
+
+	wr            *wrangler.Wrangler
+	keyspace      string
+	shard         string
+	tables        []string
+	excludeTables []string
+	includeViews  bool
+	// If both are set, these tablets are compared instead of the masters of
+	// the split.
+	sourceAlias      *topodatapb.TabletAlias
+	destinationAlias *topodatapb.TabletAlias
+
+	// populated during WorkerStateFindTargets, read-only after that
+	pairs []*schemaDiffPair
+
+	// populated during WorkerStateDiff
+	// mu guards differences.
+	mu          sync.Mutex
+	differences []string
+}
+
+// schemaDiffPair is a source and a destination tablet whose schemas are
+// compared.
+type schemaDiffPair struct {
+	source      *topodatapb.TabletAlias
+	destination *topodatapb.TabletAlias
+	// tables is the --tables filter for this pair. For vertical splits, it
+	// defaults to the tables of the source shard.
+	tables []string
+}
+
+// NewSchemaDiffWorker returns a new SchemaDiffWorker object.
+func NewSchemaDiffWorker(wr *wrangler.Wrangler, keyspace, shard string, tables, excludeTables []string, includeViews bool, sourceTabletAlias, destinationTabletAlias *topodatapb.TabletAlias) (Worker, error) {
+	if (sourceTabletAlias == nil) != (destinationTabletAlias == nil) {
+		return nil, errors.New("source_tablet_alias and destination_tablet_alias must be specified together")
+	}
+	if sourceTabletAlias == nil && (keyspace == "" || shard == "") {
+		return nil, errors.New("either <keyspace/shard> or source_tablet_alias and destination_tablet_alias must be specified")
+	}
+	return &SchemaDiffWorker{
+		StatusWorker:     NewStatusWorker(),
+		wr:               wr,
+		keyspace:         keyspace,
+		shard:            shard,
+		tables:           tables,
+		excludeTables:    excludeTables,
+		includeViews:     includeViews,
+		sourceAlias:      sourceTabletAlias,
+		destinationAlias: destinationTabletAlias,
+	}, nil
+}
+
+func (sdw *SchemaDiffWorker) description() string {
+	if sdw.sourceAlias != nil {
+		return fmt.Sprintf("tablets %v and %v", topoproto.TabletAliasString(sdw.sourceAlias), topoproto.TabletAliasString(sdw.destinationAlias))
+	}
+	return "shard " + topoproto.KeyspaceShardString(sdw.keyspace, sdw.shard)
+}
+
+// StatusAsHTML implements the Worker interface
+func (sdw *SchemaDiffWorker) StatusAsHTML() template.HTML {
+	state := sdw.State()
+
+	result := "<b>Working on:</b> " + sdw.description() + "</br>\n"
+	result += "<b>State:</b> " + state.String() + "</br>\n"
+	sdw.mu.Lock()
+	differences := sdw.differences
+	sdw.mu.Unlock()
+	switch state {
+	case WorkerStateDiff:
+		result += "<b>Running</b>:</br>\n"
+	case WorkerStateDone:
+		result += "<b>Success</b>: The schemas match.</br>\n"
+	case WorkerStateError:
+		if len(differences) > 0 {
+			result += "<b>Differences</b>:</br>\n"
+			for _, d := range differences {
+				result += template.HTMLEscapeString(d) + "</br>\n"
+			}
+		}
+	}
+
+	result += sdw.formatPanicHTML()
+	return template.HTML(result)
+}
+
+// StatusAsText implements the Worker interface.
+func (sdw *SchemaDiffWorker) StatusAsText() string {
+	state := sdw.State()
+
+	result := "Working on: " + sdw.description() + "\n"
+	result += "State: " + state.String() + "\n"
+	sdw.mu.Lock()
+	differences := sdw.differences
+	sdw.mu.Unlock()
+	switch state {
+	case WorkerStateDiff:
+		result += "Running...\n"
+	case WorkerStateDone:
+		result += "Success: The schemas match.\n"
+	case WorkerStateError:
+		if len(differences) > 0 {
+			result += "Differences:\n"
+			for _, d := range differences {
+				result += d + "\n"
+			}
+		}
+	}
+	result += sdw.formatPanicText()
+	return result
+}
+
+// Run implements the Worker interface.
+func (sdw *SchemaDiffWorker) Run(ctx context.Context) error {
+	sdw.resetRunVars()
+	err := sdw.runRecovered(ctx, sdw.run)
+
+	sdw.SetState(WorkerStateCleanUp)
+	if err != nil {
+		sdw.SetState(WorkerStateError)
+		return err
+	}
+	sdw.SetState(WorkerStateDone)
+	return nil
+}
+
+func (sdw *SchemaDiffWorker) run(ctx context.Context) error {
+	if err := sdw.findTargets(ctx); err != nil {
+		return vterrors.Wrap(err, "findTargets() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
+	return sdw.diff(ctx)
+}
+
+// findTargets phase: If the tablets were specified, use them. Otherwise, use
+// the master of the destination shard and the master of each of its source
+// shards. No tablet is changed to a worker tablet.
+func (sdw *SchemaDiffWorker) findTargets(ctx context.Context) error {
+	sdw.SetState(WorkerStateFindTargets)
+
+	if sdw.sourceAlias != nil {
+		sdw.pairs = []*schemaDiffPair{{
+			source:      sdw.sourceAlias,
+			destination: sdw.destinationAlias,
+			tables:      sdw.tables,
+		}}
+		return nil
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	shardInfo, err := sdw.wr.TopoServer().GetShard(shortCtx, sdw.keyspace, sdw.shard)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read shard %v/%v", sdw.keyspace, sdw.shard)
+	}
+	if len(shardInfo.SourceShards) == 0 {
+		return fmt.Errorf("shard %v/%v has no source shard", sdw.keyspace, sdw.shard)
+	}
+	if !shardInfo.HasMaster() {
+		return fmt.Errorf("shard %v/%v has no master", sdw.keyspace, sdw.shard)
+	}
+
+	for _, ss := range shardInfo.SourceShards {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		sourceShardInfo, err := sdw.wr.TopoServer().GetShard(shortCtx, ss.Keyspace, ss.Shard)
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot read source shard %v/%v", ss.Keyspace, ss.Shard)
+		}
+		if !sourceShardInfo.HasMaster() {
+			return fmt.Errorf("source shard %v/%v has no master", ss.Keyspace, ss.Shard)
+		}
+		tables := sdw.tables
+		if len(tables) == 0 {
+			// Vertical splits move only these tables.
+			tables = ss.Tables
+		}
+		sdw.pairs = append(sdw.pairs, &schemaDiffPair{
+			source:      sourceShardInfo.MasterAlias,
+			destination: shardInfo.MasterAlias,
+			tables:      tables,
+		})
+	}
+	return nil
+}
+
+// diff phase: get the schemas of each pair in parallel and diff them.
+func (sdw *SchemaDiffWorker) diff(ctx context.Context) error {
+	sdw.SetState(WorkerStateDiff)
+
+	wg := sync.WaitGroup{}
+	rec := &concurrency.AllErrorRecorder{}
+	differences := &concurrency.AllErrorRecorder{}
+	for _, pair := range sdw.pairs {
+		wg.Add(1)
+		go func(pair *schemaDiffPair) {
+			defer wg.Done()
+			if err := sdw.diffPair(ctx, pair, differences); err != nil {
+				rec.RecordError(err)
+			}
+		}(pair)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return rec.Error()
+	}
+
+	if differences.HasErrors() {
+		sdw.mu.Lock()
+		for _, err := range differences.Errors {
+			sdw.differences = append(sdw.differences, err.Error())
+		}
+		sdw.mu.Unlock()
+		return fmt.Errorf("schemas are different: %v", differences.Error())
+	}
+	sdw.wr.Logger().Infof("Schemas match for %v.", sdw.description())
+	return nil
+}
+
+// diffPair records each difference between the schemas of "pair" in "rec".
+func (sdw *SchemaDiffWorker) diffPair(ctx context.Context, pair *schemaDiffPair, rec concurrency.ErrorRecorder) error {
+	var sourceSchema, destinationSchema *tabletmanagerdatapb.SchemaDefinition
+	var sourceErr, destinationErr error
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
+		sourceSchema, sourceErr = sdw.wr.GetSchema(shortCtx, pair.source, pair.tables, sdw.excludeTables, sdw.includeViews)
+		cancel()
+	}()
+	go func() {
+		defer wg.Done()
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
+		destinationSchema, destinationErr = sdw.wr.GetSchema(shortCtx, pair.destination, pair.tables, sdw.excludeTables, sdw.includeViews)
+		cancel()
+	}()
+	wg.Wait()
+	if sourceErr != nil {
+		return vterrors.Wrapf(sourceErr, "cannot get schema from source %v", topoproto.TabletAliasString(pair.source))
+	}
+	if destinationErr != nil {
+		return vterrors.Wrapf(destinationErr, "cannot get schema from destination %v", topoproto.TabletAliasString(pair.destination))
+	}
+	if err := checkTablesMatched(pair.tables, destinationSchema); err != nil {
+		return err
+	}
+
+	sdw.wr.Logger().Infof("Diffing the schema of source %v and destination %v...", topoproto.TabletAliasString(pair.source), topoproto.TabletAliasString(pair.destination))
+	tmutils.DiffSchema(
+		"destination "+topoproto.TabletAliasString(pair.destination), destinationSchema,
+		"source "+topoproto.TabletAliasString(pair.source), sourceSchema,
+		rec)
+	return nil
+}
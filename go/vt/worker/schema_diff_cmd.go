@@ -0,0 +1,181 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const schemaDiffHTML = `
+<!DOCTYPE html>
+<head>
+  <title>Schema Diff Action</title>
+</head>
+<body>
+  <h1>Schema Diff Action</h1>
+
+    {{if .Error}}
+      <b>Error:</b> {{.Error}}</br>
+    {{else}}
+      {{range $i, $si := .Shards}}
+        <li><a href="/Diffs/SchemaDiff?keyspace={{$si.Keyspace}}&shard={{$si.Shard}}">{{$si.Keyspace}}/{{$si.Shard}}</a></li>
+      {{end}}
+    {{end}}
+</body>
+`
+
+const schemaDiffHTML2 = `
+<!DOCTYPE html>
+<head>
+  <title>Schema Diff Action</title>
+</head>
+<body>
+  <p>Shard involved: {{.Keyspace}}/{{.Shard}}</p>
+  <h1>Schema Diff Action</h1>
+    <form action="/Diffs/SchemaDiff" method="post">
+      <LABEL for="tables">Only include tables (optional, comma separated): </LABEL>
+        <INPUT type="text" id="tables" name="tables" value=""></BR>
+      <LABEL for="excludeTables">Exclude Tables (optional, comma separated): </LABEL>
+        <INPUT type="text" id="excludeTables" name="excludeTables" value=""></BR>
+      <LABEL for="includeViews">Include views in the schema diff: </LABEL>
+        <INPUT type="checkbox" id="includeViews" name="includeViews" value="true"{{if .DefaultIncludeViews}} checked{{end}}></BR>
+      <INPUT type="hidden" name="keyspace" value="{{.Keyspace}}"/>
+      <INPUT type="hidden" name="shard" value="{{.Shard}}"/>
+      <INPUT type="submit" name="submit" value="Schema Diff"/>
+    </form>
+  </body>
+`
+
+var schemaDiffTemplate = mustParseTemplate("schemaDiff", schemaDiffHTML)
+var schemaDiffTemplate2 = mustParseTemplate("schemaDiff2", schemaDiffHTML2)
+
+func commandSchemaDiff(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) (Worker, error) {
+	tables := subFlags.String("tables", "", "comma separated list of tables to diff. Each is either an exact match, or a regular expression of the form /regexp/. For vertical splits, it defaults to the tables of the source shard")
+	excludeTables := subFlags.String("exclude_tables", "", "comma separated list of tables to exclude")
+	includeViews := subFlags.Bool("include_views", defaultIncludeViews, "include views in the schema diff")
+	sourceTabletAliasStr := subFlags.String("source_tablet_alias", "", "if set together with --destination_tablet_alias, compare these two tablets instead of the masters of the split")
+	destinationTabletAliasStr := subFlags.String("destination_tablet_alias", "", "if set together with --source_tablet_alias, compare these two tablets instead of the masters of the split")
+	if err := subFlags.Parse(args); err != nil {
+		return nil, err
+	}
+	var sourceTabletAlias, destinationTabletAlias *topodatapb.TabletAlias
+	var err error
+	if *sourceTabletAliasStr != "" {
+		if sourceTabletAlias, err = topoproto.ParseTabletAlias(*sourceTabletAliasStr); err != nil {
+			return nil, vterrors.Wrap(err, "cannot parse source_tablet_alias")
+		}
+	}
+	if *destinationTabletAliasStr != "" {
+		if destinationTabletAlias, err = topoproto.ParseTabletAlias(*destinationTabletAliasStr); err != nil {
+			return nil, vterrors.Wrap(err, "cannot parse destination_tablet_alias")
+		}
+	}
+	var keyspace, shard string
+	switch {
+	case subFlags.NArg() == 1:
+		if keyspace, shard, err = topoproto.ParseKeyspaceShard(subFlags.Arg(0)); err != nil {
+			return nil, err
+		}
+	case subFlags.NArg() == 0 && sourceTabletAlias != nil && destinationTabletAlias != nil:
+	default:
+		subFlags.Usage()
+		return nil, fmt.Errorf("command SchemaDiff requires <keyspace/shard> or --source_tablet_alias and --destination_tablet_alias")
+	}
+	var tableArray []string
+	if *tables != "" {
+		tableArray = strings.Split(*tables, ",")
+	}
+	var excludeTableArray []string
+	if *excludeTables != "" {
+		excludeTableArray = strings.Split(*excludeTables, ",")
+	}
+
+	worker, err := NewSchemaDiffWorker(wr, keyspace, shard, tableArray, excludeTableArray, *includeViews, sourceTabletAlias, destinationTabletAlias)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create schema diff worker")
+	}
+	return worker, nil
+}
+
+func interactiveSchemaDiff(ctx context.Context, wi *Instance, wr *wrangler.Wrangler, w http.ResponseWriter, r *http.Request) (Worker, *template.Template, map[string]interface{}, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse form")
+	}
+	keyspace := r.FormValue("keyspace")
+	shard := r.FormValue("shard")
+
+	if keyspace == "" || shard == "" {
+		// display the list of possible shards to chose from
+		result := make(map[string]interface{})
+		shards, err := shardsWithSources(ctx, wr)
+		if err != nil {
+			result["Error"] = err.Error()
+		} else {
+			result["Shards"] = shards
+		}
+		return nil, schemaDiffTemplate, result, nil
+	}
+
+	submitButtonValue := r.FormValue("submit")
+	if submitButtonValue == "" {
+		// display the input form
+		result := make(map[string]interface{})
+		result["Keyspace"] = keyspace
+		result["Shard"] = shard
+		result["DefaultIncludeViews"] = defaultIncludeViews
+		return nil, schemaDiffTemplate2, result, nil
+	}
+
+	// Process input form.
+	tables := r.FormValue("tables")
+	var tableArray []string
+	if tables != "" {
+		tableArray = strings.Split(tables, ",")
+	}
+	excludeTables := r.FormValue("excludeTables")
+	var excludeTableArray []string
+	if excludeTables != "" {
+		excludeTableArray = strings.Split(excludeTables, ",")
+	}
+	includeViews := r.FormValue("includeViews") == "true"
+
+	// start the diff job
+	wrk, err := NewSchemaDiffWorker(wr, keyspace, shard, tableArray, excludeTableArray, includeViews, nil /* sourceTabletAlias */, nil /* destinationTabletAlias */)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot create worker")
+	}
+	return wrk, nil, nil, nil
+}
+
+func init() {
+	AddCommand("Diffs", Command{"SchemaDiff",
+		commandSchemaDiff, interactiveSchemaDiff,
+		"[--tables=''] [--exclude_tables=''] [--include_views] [--source_tablet_alias=<alias> --destination_tablet_alias=<alias>] [<keyspace/shard>]",
+		"Compares only the schemas of the destination shard master and the master of each of its source shards, or of the two given tablets. Does not stop replication and does not take any tablet out of serving. Use it as a quick check before SplitDiff or VerticalSplitDiff."})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler/testlib"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	ts := memorytopo.NewServer("cell1", "cell2")
+	ctx := context.Background()
+	wi := NewInstance(ts, "cell1", time.Second)
+
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	sourceMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 0,
+		topodatapb.TabletType_MASTER, nil, testlib.TabletKeyspaceShard(t, "ks", "-80"))
+	destMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 10,
+		topodatapb.TabletType_MASTER, nil, testlib.TabletKeyspaceShard(t, "ks", "-40"))
+	if err := wi.wr.SetSourceShards(ctx, "ks", "-40", []*topodatapb.TabletAlias{sourceMaster.Tablet.Alias}, nil); err != nil {
+		t.Fatalf("SetSourceShards failed: %v", err)
+	}
+
+	schema := func(columns ...string) *tabletmanagerdatapb.SchemaDefinition {
+		return &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:              "table1",
+					Schema:            "CREATE TABLE `table1` (" + strings.Join(columns, ", ") + ")",
+					Columns:           columns,
+					PrimaryKeyColumns: []string{"id"},
+					Type:              tmutils.TableBaseTable,
+				},
+			},
+		}
+	}
+	sourceMaster.FakeMysqlDaemon.Schema = schema("id", "msg")
+	destMaster.FakeMysqlDaemon.Schema = schema("id", "msg")
+
+	for _, ft := range []*testlib.FakeTablet{sourceMaster, destMaster} {
+		ft.StartActionLoop(t, wi.wr)
+		defer ft.StopActionLoop(t)
+	}
+
+	if err := runCommand(t, wi, wi.wr, []string{"SchemaDiff", "ks/-40"}); err != nil {
+		t.Fatalf("SchemaDiff of equal schemas failed: %v", err)
+	}
+
+	// The destination is missing a column.
+	if err := wi.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	destMaster.FakeMysqlDaemon.Schema = schema("id")
+	err := runCommand(t, wi, wi.wr, []string{"SchemaDiff", "ks/-40"})
+	if err == nil || !strings.Contains(err.Error(), "schemas are different") {
+		t.Fatalf("SchemaDiff of different schemas must fail, got: %v", err)
+	}
+
+	// Compare two explicitly specified tablets.
+	if err := wi.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	err = runCommand(t, wi, wi.wr, []string{"SchemaDiff",
+		"-source_tablet_alias", topoproto.TabletAliasString(sourceMaster.Tablet.Alias),
+		"-destination_tablet_alias", topoproto.TabletAliasString(sourceMaster.Tablet.Alias)})
+	if err != nil {
+		t.Fatalf("SchemaDiff of the same tablet failed: %v", err)
+	}
+}
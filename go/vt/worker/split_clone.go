@@ -27,6 +27,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 
 	"golang.org/x/net/context"
+	"golang.org/x/time/rate"
 
 	"vitess.io/vitess/go/event"
 	"vitess.io/vitess/go/stats"
@@ -34,6 +35,7 @@ import (
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/throttler"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -47,15 +49,31 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
-// cloneType specifies whether it is a horizontal resharding or a vertical split.
+// cloneType specifies whether it is a horizontal resharding, a vertical split
+// or a one-off copy of tables between two arbitrary shards.
 // TODO(mberlin): Remove this once we merged both into one command.
 type cloneType int
 
 const (
 	horizontalResharding cloneType = iota
 	verticalSplit
+	// tableMigrate copies a list of tables from any source shard to any
+	// destination shard. Unlike verticalSplit, it does not require
+	// KeyspaceServedFrom and does not set up filtered replication.
+	tableMigrate
 )
 
+// commandName returns the name of the vtworker command for the clone type.
+func (ct cloneType) commandName() string {
+	switch ct {
+	case verticalSplit:
+		return "VerticalSplitClone"
+	case tableMigrate:
+		return "TableMigrate"
+	}
+	return "SplitClone"
+}
+
 // servingTypes is the list of tabletTypes which the source keyspace must be serving.
 var servingTypes = []topodatapb.TabletType{topodatapb.TabletType_MASTER, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY}
 
@@ -71,22 +89,56 @@ type SplitCloneWorker struct {
 	shard               string
 	online              bool
 	offline             bool
-	// verticalSplit only: List of tables which should be split out.
+	// resume is true if the online phase should continue from the checkpoint
+	// of a previous run.
+	resume bool
+	// catchUp is true if filtered replication should catch up with the
+	// changes since the start of the online phase. It replaces the offline
+	// phase.
+	catchUp bool
+	// verticalSplit and tableMigrate only: List of tables which should be
+	// split out or copied.
 	tables []string
 	// horizontalResharding only: List of tables which will be skipped.
-	excludeTables           []string
+	excludeTables []string
+	// tableMigrate only: The shard from which the tables are copied.
+	sourceKeyspace string
+	sourceShard    string
+	// tableMigrate only: verify is true if the copied tables should be
+	// compared between the source and the destination after the offline phase.
+	verify                  bool
 	chunkCount              int
 	minRowsPerChunk         int
 	sourceReaderCount       int
 	writeQueryMaxRows       int
 	writeQueryMaxSize       int
+	writeTransactionMaxRows int
+	writeTransactionMaxSize int
 	destinationWriterCount  int
 	minHealthyRdonlyTablets int
 	maxTPS                  int64
 	maxReplicationLag       int64
+	sourceTabletAliases     []*topodatapb.TabletAlias
 	cleaner                 *wrangler.Cleaner
 	tabletTracker           *TabletTracker
 
+	// maxWriteMBPerSecond caps the bandwidth of all writes to the destination
+	// shards. 0 means unlimited.
+	maxWriteMBPerSecond int
+	// writeLimiter enforces maxWriteMBPerSecond. nil if unlimited.
+	writeLimiter *rate.Limiter
+	// compression is the gRPC compressor for the writes to the destination
+	// shards. Empty means that --grpc_compression is used.
+	compression string
+
+	// destinationQueueSize is the size of the write query queue of each
+	// destination shard. 0 means 2 * destinationWriterCount.
+	destinationQueueSize int
+
+	// checkpointer records the progress of the online phase.
+	// populated during WorkerStateInit if the online phase is enabled.
+	checkpointer *cloneCheckpointer
+
 	// populated during WorkerStateInit, read-only after that
 	destinationKeyspaceInfo *topo.KeyspaceInfo
 	sourceShards            []*topo.ShardInfo
@@ -130,6 +182,22 @@ type SplitCloneWorker struct {
 	// used source tablets during the offline clone phase.
 	formattedOfflineSources string
 
+	// destinationQueuesMu guards destinationQueues.
+	destinationQueuesMu sync.Mutex
+	// destinationQueues has the write query queue of each destination shard.
+	// Set during WorkerStateClone(Online|Offline).
+	destinationQueues []*destinationQueue
+
+	// catchUpPositionsMu guards catchUpPositions.
+	catchUpPositionsMu sync.Mutex
+	// catchUpPositions has the position of each source master at the end of
+	// the online phase. Filtered replication must reach them during
+	// WorkerStateCatchUp.
+	catchUpPositions []string
+	// catchUpUIDs has the vreplication ids per destination and source shard.
+	// Populated during WorkerStateCatchUp, read-only after that.
+	catchUpUIDs [][]uint32
+
 	// tableStatusList* holds the status for each table.
 	// populated during WorkerStateCloneOnline
 	tableStatusListOnline *tableStatusList
@@ -140,21 +208,27 @@ type SplitCloneWorker struct {
 }
 
 // newSplitCloneWorker returns a new worker object for the SplitClone command.
-func newSplitCloneWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, online, offline bool, excludeTables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, destinationWriterCount, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64) (Worker, error) {
-	return newCloneWorker(wr, horizontalResharding, cell, keyspace, shard, online, offline, nil /* tables */, excludeTables, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, destinationWriterCount, minHealthyRdonlyTablets, maxTPS, maxReplicationLag)
+func newSplitCloneWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, online, offline, resume, catchUp bool, excludeTables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64, maxWriteMBPerSecond int, compression string, sourceTabletAliases []*topodatapb.TabletAlias) (Worker, error) {
+	return newCloneWorker(wr, horizontalResharding, cell, keyspace, shard, online, offline, resume, catchUp, false /* verify */, "" /* sourceKeyspace */, "" /* sourceShard */, nil /* tables */, excludeTables, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets, maxTPS, maxReplicationLag, maxWriteMBPerSecond, compression, sourceTabletAliases)
 }
 
 // newVerticalSplitCloneWorker returns a new worker object for the
 // VerticalSplitClone command.
-func newVerticalSplitCloneWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, online, offline bool, tables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, destinationWriterCount, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64) (Worker, error) {
-	return newCloneWorker(wr, verticalSplit, cell, keyspace, shard, online, offline, tables, nil /* excludeTables */, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, destinationWriterCount, minHealthyRdonlyTablets, maxTPS, maxReplicationLag)
+func newVerticalSplitCloneWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, online, offline, resume, catchUp bool, tables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64, maxWriteMBPerSecond int, compression string, sourceTabletAliases []*topodatapb.TabletAlias) (Worker, error) {
+	return newCloneWorker(wr, verticalSplit, cell, keyspace, shard, online, offline, resume, catchUp, false /* verify */, "" /* sourceKeyspace */, "" /* sourceShard */, tables, nil /* excludeTables */, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets, maxTPS, maxReplicationLag, maxWriteMBPerSecond, compression, sourceTabletAliases)
+}
+
+// newTableMigrateWorker returns a new worker object for the TableMigrate
+// command.
+func newTableMigrateWorker(wr *wrangler.Wrangler, cell, sourceKeyspace, sourceShard, keyspace, shard string, online, offline, resume, verify bool, tables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64, maxWriteMBPerSecond int, compression string, sourceTabletAliases []*topodatapb.TabletAlias) (Worker, error) {
+	return newCloneWorker(wr, tableMigrate, cell, keyspace, shard, online, offline, resume, false /* catchUp */, verify, sourceKeyspace, sourceShard, tables, nil /* excludeTables */, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets, maxTPS, maxReplicationLag, maxWriteMBPerSecond, compression, sourceTabletAliases)
 }
 
-// newCloneWorker returns a new SplitCloneWorker object which is used both by
-// the SplitClone and VerticalSplitClone command.
+// newCloneWorker returns a new SplitCloneWorker object which is used by the
+// SplitClone, VerticalSplitClone and TableMigrate command.
 // TODO(mberlin): Rename SplitCloneWorker to cloneWorker.
-func newCloneWorker(wr *wrangler.Wrangler, cloneType cloneType, cell, keyspace, shard string, online, offline bool, tables, excludeTables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, destinationWriterCount, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64) (Worker, error) {
-	if cloneType != horizontalResharding && cloneType != verticalSplit {
+func newCloneWorker(wr *wrangler.Wrangler, cloneType cloneType, cell, keyspace, shard string, online, offline, resume, catchUp, verify bool, sourceKeyspace, sourceShard string, tables, excludeTables []string, chunkCount, minRowsPerChunk, sourceReaderCount, writeQueryMaxRows, writeQueryMaxSize, writeTransactionMaxRows, writeTransactionMaxSize, destinationWriterCount, destinationQueueSize, minHealthyRdonlyTablets int, maxTPS, maxReplicationLag int64, maxWriteMBPerSecond int, compression string, sourceTabletAliases []*topodatapb.TabletAlias) (Worker, error) {
+	if cloneType != horizontalResharding && cloneType != verticalSplit && cloneType != tableMigrate {
 		return nil, fmt.Errorf("unknown cloneType: %v This is a bug. Please report", cloneType)
 	}
 
@@ -162,6 +236,24 @@ func newCloneWorker(wr *wrangler.Wrangler, cloneType cloneType, cell, keyspace,
 	if !online && !offline {
 		return nil, errors.New("at least one clone phase (-online, -offline) must be enabled (and not set to false)")
 	}
+	if resume && !online {
+		return nil, errors.New("-resume requires the online clone phase (-online) to be enabled")
+	}
+	if catchUp && !online {
+		return nil, errors.New("-catch_up requires the online clone phase (-online) to be enabled")
+	}
+	if catchUp && offline {
+		return nil, errors.New("-catch_up replaces the offline clone phase (-offline) and requires -offline=false")
+	}
+	if verify && !offline {
+		return nil, errors.New("-verify requires the offline clone phase (-offline) to be enabled")
+	}
+	if cloneType == tableMigrate && sourceKeyspace == keyspace && sourceShard == shard {
+		return nil, fmt.Errorf("source and destination shard must be different: %v", topoproto.KeyspaceShardString(keyspace, shard))
+	}
+	if len(sourceTabletAliases) > 0 && !offline {
+		return nil, errors.New("-source_tablet_alias requires the offline clone phase (-offline) to be enabled")
+	}
 	if tables != nil && len(tables) == 0 {
 		return nil, errors.New("list of tablets to be split out must not be empty")
 	}
@@ -180,9 +272,18 @@ func newCloneWorker(wr *wrangler.Wrangler, cloneType cloneType, cell, keyspace,
 	if writeQueryMaxSize <= 0 {
 		return nil, fmt.Errorf("write_query_max_size must be > 0: %v", writeQueryMaxSize)
 	}
+	if writeTransactionMaxRows < 0 {
+		return nil, fmt.Errorf("write_transaction_max_rows must be >= 0: %v", writeTransactionMaxRows)
+	}
+	if writeTransactionMaxRows > 0 && writeTransactionMaxSize < writeQueryMaxSize {
+		return nil, fmt.Errorf("write_transaction_max_size must be >= write_query_max_size: %v >= %v", writeTransactionMaxSize, writeQueryMaxSize)
+	}
 	if destinationWriterCount <= 0 {
 		return nil, fmt.Errorf("destination_writer_count must be > 0: %v", destinationWriterCount)
 	}
+	if destinationQueueSize < 0 {
+		return nil, fmt.Errorf("destination_queue_size must be >= 0: %v", destinationQueueSize)
+	}
 	if minHealthyRdonlyTablets < 0 {
 		return nil, fmt.Errorf("min_healthy_rdonly_tablets must be >= 0: %v", minHealthyRdonlyTablets)
 	}
@@ -195,6 +296,18 @@ func newCloneWorker(wr *wrangler.Wrangler, cloneType cloneType, cell, keyspace,
 	if maxReplicationLag <= 0 {
 		return nil, fmt.Errorf("max_replication_lag must be >= 1s: %v", maxReplicationLag)
 	}
+	if maxWriteMBPerSecond < 0 {
+		return nil, fmt.Errorf("max_write_mb_per_second must be >= 0: %v", maxWriteMBPerSecond)
+	}
+	if !grpcclient.IsValidCompression(compression) {
+		return nil, fmt.Errorf("unsupported compression: %v (supported: snappy, gzip)", compression)
+	}
+	var writeLimiter *rate.Limiter
+	if maxWriteMBPerSecond > 0 {
+		// The burst is one second worth of bytes.
+		maxBytesPerSecond := maxWriteMBPerSecond * 1024 * 1024
+		writeLimiter = rate.NewLimiter(rate.Limit(maxBytesPerSecond), maxBytesPerSecond)
+	}
 
 	scw := &SplitCloneWorker{
 		StatusWorker:            NewStatusWorker(),
@@ -205,25 +318,37 @@ func newCloneWorker(wr *wrangler.Wrangler, cloneType cloneType, cell, keyspace,
 		shard:                   shard,
 		online:                  online,
 		offline:                 offline,
+		resume:                  resume,
+		catchUp:                 catchUp,
 		tables:                  tables,
 		excludeTables:           excludeTables,
+		sourceKeyspace:          sourceKeyspace,
+		sourceShard:             sourceShard,
+		verify:                  verify,
 		chunkCount:              chunkCount,
 		minRowsPerChunk:         minRowsPerChunk,
 		sourceReaderCount:       sourceReaderCount,
 		writeQueryMaxRows:       writeQueryMaxRows,
 		writeQueryMaxSize:       writeQueryMaxSize,
+		writeTransactionMaxRows: writeTransactionMaxRows,
+		writeTransactionMaxSize: writeTransactionMaxSize,
 		destinationWriterCount:  destinationWriterCount,
+		destinationQueueSize:    destinationQueueSize,
 		minHealthyRdonlyTablets: minHealthyRdonlyTablets,
 		maxTPS:                  maxTPS,
 		maxReplicationLag:       maxReplicationLag,
-		cleaner:                 &wrangler.Cleaner{},
+		maxWriteMBPerSecond:     maxWriteMBPerSecond,
+		writeLimiter:            writeLimiter,
+		compression:             compression,
+		sourceTabletAliases:     sourceTabletAliases,
+		cleaner:                 newCleaner(wr, cloneType.commandName(), keyspace, shard),
 		tabletTracker:           NewTabletTracker(),
 		throttlers:              make(map[string]*throttler.Throttler),
 
 		destinationDbNames: make(map[string]string),
 
-		tableStatusListOnline:  &tableStatusList{},
-		tableStatusListOffline: &tableStatusList{},
+		tableStatusListOnline:  &tableStatusList{phase: "online", keyspace: keyspace, shard: shard},
+		tableStatusListOffline: &tableStatusList{phase: "offline", keyspace: keyspace, shard: shard},
 	}
 	scw.initializeEventDescriptor()
 	return scw, nil
@@ -245,6 +370,15 @@ func (scw *SplitCloneWorker) initializeEventDescriptor() {
 			Shard:    scw.shard,
 			Tables:   scw.tables,
 		}
+	case tableMigrate:
+		scw.ev = &events.TableMigrate{
+			Cell:           scw.cell,
+			SourceKeyspace: scw.sourceKeyspace,
+			SourceShard:    scw.sourceShard,
+			Keyspace:       scw.destinationKeyspace,
+			Shard:          scw.shard,
+			Tables:         scw.tables,
+		}
 	}
 }
 
@@ -266,6 +400,47 @@ func (scw *SplitCloneWorker) formatOnlineSources() string {
 	return aliases
 }
 
+// formatWriteBandwidth returns the bandwidth limit and compression of the
+// writes to the destination shards.
+func (scw *SplitCloneWorker) formatWriteBandwidth() string {
+	limit := "unlimited"
+	if scw.maxWriteMBPerSecond > 0 {
+		limit = fmt.Sprintf("max %v MB/s", scw.maxWriteMBPerSecond)
+	}
+	compression := scw.compression
+	if compression == "" {
+		compression = "--grpc_compression"
+	}
+	return fmt.Sprintf("%v (compression: %v)", limit, compression)
+}
+
+func (scw *SplitCloneWorker) destinationQueueSizeOrDefault() int {
+	if scw.destinationQueueSize == 0 {
+		return scw.destinationWriterCount * 2
+	}
+	return scw.destinationQueueSize
+}
+
+func (scw *SplitCloneWorker) setDestinationQueues(queues []*destinationQueue) {
+	scw.destinationQueuesMu.Lock()
+	defer scw.destinationQueuesMu.Unlock()
+
+	scw.destinationQueues = queues
+}
+
+// formatDestinationQueues returns the backlog of each destination shard.
+// A destination whose queue is full is slower than the others.
+func (scw *SplitCloneWorker) formatDestinationQueues() []string {
+	scw.destinationQueuesMu.Lock()
+	defer scw.destinationQueuesMu.Unlock()
+
+	var result []string
+	for _, dq := range scw.destinationQueues {
+		result = append(result, dq.format())
+	}
+	return result
+}
+
 func (scw *SplitCloneWorker) setFormattedOfflineSources(aliases []*topodatapb.TabletAlias) {
 	scw.formattedOfflineSourcesMu.Lock()
 	defer scw.formattedOfflineSourcesMu.Unlock()
@@ -299,12 +474,14 @@ func (scw *SplitCloneWorker) StatusAsHTML() template.HTML {
 	case WorkerStateCloneOnline:
 		result += "<b>Running:</b></br>\n"
 		result += "<b>Copying from:</b> " + scw.formatOnlineSources() + "</br>\n"
+		result += "<b>Progress:</b> " + scw.tableStatusListOnline.formatProgress() + "</br>\n"
 		statuses, eta := scw.tableStatusListOnline.format()
 		result += "<b>ETA:</b> " + eta.String() + "</br>\n"
 		result += strings.Join(statuses, "</br>\n")
 	case WorkerStateCloneOffline:
 		result += "<b>Running:</b></br>\n"
 		result += "<b>Copying from:</b> " + scw.FormattedOfflineSources() + "</br>\n"
+		result += "<b>Progress:</b> " + scw.tableStatusListOffline.formatProgress() + "</br>\n"
 		statuses, eta := scw.tableStatusListOffline.format()
 		result += "<b>ETA:</b> " + eta.String() + "</br>\n"
 		result += strings.Join(statuses, "</br>\n")
@@ -314,6 +491,13 @@ func (scw *SplitCloneWorker) StatusAsHTML() template.HTML {
 			statuses, _ := scw.tableStatusListOnline.format()
 			result += strings.Join(statuses, "</br>\n")
 		}
+	case WorkerStateCatchUp:
+		result += "<b>Running:</b></br>\n"
+		result += "<b>Waiting for filtered replication to reach:</b> " + scw.formatCatchUpPositions() + "</br>\n"
+		result += "</br>\n"
+		result += "<b>Result from preceding Online Clone:</b></br>\n"
+		statuses, _ := scw.tableStatusListOnline.format()
+		result += strings.Join(statuses, "</br>\n")
 	case WorkerStateDone:
 		result += "<b>Success</b>:</br>\n"
 		if scw.online {
@@ -334,7 +518,17 @@ func (scw *SplitCloneWorker) StatusAsHTML() template.HTML {
 		result += "</br>\n"
 		result += `<b>Resharding Throttler:</b> <a href="/throttlerz">see /throttlerz for details</a></br>`
 	}
+	if (state == WorkerStateCloneOnline || state == WorkerStateCloneOffline) && (scw.maxWriteMBPerSecond > 0 || scw.compression != "") {
+		result += "</br>\n"
+		result += "<b>Write Bandwidth:</b> " + scw.formatWriteBandwidth() + "</br>\n"
+	}
+	if queues := scw.formatDestinationQueues(); len(queues) > 0 {
+		result += "</br>\n"
+		result += "<b>Destination Backlog:</b></br>\n"
+		result += strings.Join(queues, "</br>\n") + "</br>\n"
+	}
 
+	result += scw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -348,12 +542,14 @@ func (scw *SplitCloneWorker) StatusAsText() string {
 	case WorkerStateCloneOnline:
 		result += "Running:\n"
 		result += "Comparing source and destination using: " + scw.formatOnlineSources() + "\n"
+		result += "Progress: " + scw.tableStatusListOnline.formatProgress() + "\n"
 		statuses, eta := scw.tableStatusListOnline.format()
 		result += "ETA: " + eta.String() + "\n"
 		result += strings.Join(statuses, "\n")
 	case WorkerStateCloneOffline:
 		result += "Running:\n"
 		result += "Copying from: " + scw.FormattedOfflineSources() + "\n"
+		result += "Progress: " + scw.tableStatusListOffline.formatProgress() + "\n"
 		statuses, eta := scw.tableStatusListOffline.format()
 		result += "ETA: " + eta.String() + "\n"
 		result += strings.Join(statuses, "\n")
@@ -364,6 +560,13 @@ func (scw *SplitCloneWorker) StatusAsText() string {
 			statuses, _ := scw.tableStatusListOnline.format()
 			result += strings.Join(statuses, "\n")
 		}
+	case WorkerStateCatchUp:
+		result += "Running:\n"
+		result += "Waiting for filtered replication to reach: " + scw.formatCatchUpPositions() + "\n"
+		result += "\n"
+		result += "Result from preceding Online Clone:\n"
+		statuses, _ := scw.tableStatusListOnline.format()
+		result += strings.Join(statuses, "\n")
 	case WorkerStateDone:
 		result += "Success:"
 		if scw.online {
@@ -381,15 +584,34 @@ func (scw *SplitCloneWorker) StatusAsText() string {
 			result += strings.Join(statuses, "\n")
 		}
 	}
+	if (state == WorkerStateCloneOnline || state == WorkerStateCloneOffline) && (scw.maxWriteMBPerSecond > 0 || scw.compression != "") {
+		result += "\n"
+		result += "Write Bandwidth: " + scw.formatWriteBandwidth() + "\n"
+	}
+	if queues := scw.formatDestinationQueues(); len(queues) > 0 {
+		result += "\n"
+		result += "Destination Backlog:\n"
+		result += strings.Join(queues, "\n") + "\n"
+	}
+	result += scw.formatPanicText()
 	return result
 }
 
+// progress is part of the progressReporter interface. It returns the status
+// of the offline clone once it has started and of the online clone before.
+func (scw *SplitCloneWorker) progress() []tableProgress {
+	if scw.tableStatusListOffline.isInitialized() {
+		return scw.tableStatusListOffline.progress()
+	}
+	return scw.tableStatusListOnline.progress()
+}
+
 // Run implements the Worker interface
 func (scw *SplitCloneWorker) Run(ctx context.Context) error {
-	resetVars()
+	scw.resetRunVars()
 
 	// Run the command.
-	err := scw.run(ctx)
+	err := scw.runRecovered(ctx, scw.run)
 
 	// Cleanup.
 	scw.setState(WorkerStateCleanUp)
@@ -416,6 +638,10 @@ func (scw *SplitCloneWorker) Run(ctx context.Context) error {
 		}
 	}
 
+	if err == nil && *waitForServingTimeout != 0 {
+		scw.setState(WorkerStateWaitForServing)
+		err = waitForServingTablets(ctx, scw.wr, scw.cleaner.ChangedTablets(), *waitForServingTimeout)
+	}
 	if err != nil {
 		scw.setErrorState(err)
 		return err
@@ -442,13 +668,25 @@ func (scw *SplitCloneWorker) run(ctx context.Context) error {
 	}
 
 	// Phase 3: (optional) online clone.
-	if scw.online {
+	if scw.online && scw.checkpointer.onlineDone() {
+		scw.wr.Logger().Infof("Online clone skipped because it was already completed by a previous run (see --resume).")
+	} else if scw.online {
 		scw.wr.Logger().Infof("Online clone will be run now.")
 		// 3a: Wait for minimum number of source tablets (required for the diff).
 		if err := scw.waitForTablets(ctx, scw.sourceShards, *waitForHealthyTabletsTimeout); err != nil {
 			return vterrors.Wrap(err, "waitForTablets(sourceShards) failed")
 		}
-		// 3b: Clone the data.
+		if scw.catchUp {
+			// 3b: Verify that filtered replication can replay changes which
+			// were already copied and record where it has to start later.
+			if err := scw.checkCatchUpBinlogFormat(ctx); err != nil {
+				return vterrors.Wrap(err, "checkCatchUpBinlogFormat() failed")
+			}
+			if err := scw.recordCatchUpStartPositions(ctx); err != nil {
+				return vterrors.Wrap(err, "recordCatchUpStartPositions() failed")
+			}
+		}
+		// 3c: Clone the data.
 		start := time.Now()
 		if err := scw.clone(ctx, WorkerStateCloneOnline); err != nil {
 			return vterrors.Wrap(err, "online clone() failed")
@@ -457,6 +695,9 @@ func (scw *SplitCloneWorker) run(ctx context.Context) error {
 		if err := checkDone(ctx); err != nil {
 			return err
 		}
+		if err := scw.checkpointer.markOnlineDone(ctx); err != nil {
+			return vterrors.Wrap(err, "failed to save the checkpoint")
+		}
 		// TODO(mberlin): Output diff report of the online clone.
 		// Round duration to second granularity to make it more readable.
 		scw.wr.Logger().Infof("Online clone finished after %v.", time.Duration(d.Nanoseconds()/time.Second.Nanoseconds()*time.Second.Nanoseconds()))
@@ -500,6 +741,43 @@ func (scw *SplitCloneWorker) run(ctx context.Context) error {
 		scw.wr.Logger().Infof("Offline clone skipped because --offline=false was specified.")
 	}
 
+	// Phase 5: (optional) catch up with filtered replication.
+	if scw.catchUp {
+		// 5a: Replay all changes since the start of the online clone.
+		start := time.Now()
+		if err := scw.catchUpFilteredReplication(ctx); err != nil {
+			return vterrors.Wrap(err, "catchUpFilteredReplication() failed")
+		}
+		if err := checkDone(ctx); err != nil {
+			return err
+		}
+		// 5b: Verify the row counts at a consistent position.
+		if err := scw.checkCatchUpRowCounts(ctx); err != nil {
+			return vterrors.Wrap(err, "checkCatchUpRowCounts() failed")
+		}
+		d := time.Since(start)
+		// Round duration to second granularity to make it more readable.
+		scw.wr.Logger().Infof("Catch up finished after %v.", time.Duration(d.Nanoseconds()/time.Second.Nanoseconds()*time.Second.Nanoseconds()))
+	}
+
+	// Phase 6: (optional) verify the copied tables.
+	if scw.verify {
+		start := time.Now()
+		if err := scw.verifyTables(ctx); err != nil {
+			return vterrors.Wrap(err, "verifyTables() failed")
+		}
+		d := time.Since(start)
+		// Round duration to second granularity to make it more readable.
+		scw.wr.Logger().Infof("Verification finished after %v.", time.Duration(d.Nanoseconds()/time.Second.Nanoseconds()*time.Second.Nanoseconds()))
+	}
+
+	// The checkpoint is no longer needed after a successful run.
+	if scw.checkpointer != nil {
+		if err := scw.checkpointer.delete(ctx); err != nil {
+			return vterrors.Wrap(err, "failed to delete the checkpoint")
+		}
+	}
+
 	return nil
 }
 
@@ -509,7 +787,7 @@ func (scw *SplitCloneWorker) init(ctx context.Context) error {
 	scw.setState(WorkerStateInit)
 
 	// read the keyspace and validate it
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	var err error
 	scw.destinationKeyspaceInfo, err = scw.wr.TopoServer().GetKeyspace(shortCtx, scw.destinationKeyspace)
 	cancel()
@@ -527,6 +805,10 @@ func (scw *SplitCloneWorker) init(ctx context.Context) error {
 		if err := scw.initShardsForVerticalSplit(ctx); err != nil {
 			return vterrors.Wrap(err, "failed initShardsForVerticalSplit")
 		}
+	case tableMigrate:
+		if err := scw.initShardsForTableMigrate(ctx); err != nil {
+			return vterrors.Wrap(err, "failed initShardsForTableMigrate")
+		}
 	}
 
 	if err := scw.sanityCheckShardInfos(); err != nil {
@@ -539,6 +821,15 @@ func (scw *SplitCloneWorker) init(ctx context.Context) error {
 		}
 	}
 
+	if scw.online {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		scw.checkpointer, err = newCloneCheckpointer(shortCtx, scw.wr.TopoServer(), scw.wr.Logger(), scw.cloneType.commandName(), scw.destinationKeyspace, scw.shard, scw.chunkCount, scw.minRowsPerChunk, scw.resume)
+		cancel()
+		if err != nil {
+			return vterrors.Wrap(err, "failed to initialize the checkpoint")
+		}
+	}
+
 	// Initialize healthcheck and add destination shards to it.
 	scw.healthCheck = discovery.NewHealthCheck(*healthcheckRetryDelay, *healthCheckTimeout)
 	scw.tsc = discovery.NewTabletStatsCacheDoNotSetListener(scw.wr.TopoServer(), scw.cell)
@@ -559,7 +850,7 @@ func (scw *SplitCloneWorker) init(ctx context.Context) error {
 
 func (scw *SplitCloneWorker) initShardsForHorizontalResharding(ctx context.Context) error {
 	// find the OverlappingShards in the keyspace
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	osList, err := topotools.FindOverlappingShards(shortCtx, scw.wr.TopoServer(), scw.destinationKeyspace)
 	cancel()
 	if err != nil {
@@ -608,7 +899,7 @@ func (scw *SplitCloneWorker) initShardsForVerticalSplit(ctx context.Context) err
 	}
 	sourceKeyspace := servedFrom
 
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	shardMap, err := scw.wr.TopoServer().FindAllShardsInKeyspace(shortCtx, sourceKeyspace)
 	cancel()
 	if err != nil {
@@ -637,7 +928,33 @@ func (scw *SplitCloneWorker) initShardsForVerticalSplit(ctx context.Context) err
 	return nil
 }
 
+func (scw *SplitCloneWorker) initShardsForTableMigrate(ctx context.Context) error {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	sourceShardInfo, err := scw.wr.TopoServer().GetShard(shortCtx, scw.sourceKeyspace, scw.sourceShard)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read source shard %v/%v", scw.sourceKeyspace, scw.sourceShard)
+	}
+	scw.sourceShards = []*topo.ShardInfo{sourceShardInfo}
+
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	destShardInfo, err := scw.wr.TopoServer().GetShard(shortCtx, scw.destinationKeyspace, scw.shard)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read destination shard %v/%v", scw.destinationKeyspace, scw.shard)
+	}
+	scw.destinationShards = []*topo.ShardInfo{destShardInfo}
+
+	return nil
+}
+
 func (scw *SplitCloneWorker) sanityCheckShardInfos() error {
+	if scw.cloneType == tableMigrate {
+		// TableMigrate neither sets up filtered replication nor changes the
+		// served types. Therefore, the shards can be in any state.
+		return nil
+	}
+
 	// Verify that filtered replication is not already enabled.
 	for _, si := range scw.destinationShards {
 		if len(si.SourceShards) > 0 {
@@ -698,19 +1015,50 @@ func (scw *SplitCloneWorker) loadVSchema(ctx context.Context) error {
 }
 
 // findOfflineSourceTablets phase:
-// - find one rdonly in the source shard
+// - find one rdonly in the source shard (unless set by -source_tablet_alias)
 // - mark it as 'worker' pointing back to us
 // - get the aliases of all the source tablets
 func (scw *SplitCloneWorker) findOfflineSourceTablets(ctx context.Context) error {
 	scw.setState(WorkerStateFindTargets)
 
+	// map the tablets chosen by the user to their source shard
+	// Map key format: "keyspace/shard" e.g. "test_keyspace/-80"
+	isSourceShard := make(map[string]bool)
+	for _, si := range scw.sourceShards {
+		isSourceShard[topoproto.KeyspaceShardString(si.Keyspace(), si.ShardName())] = true
+	}
+	pinnedAliases := make(map[string]*topodatapb.TabletAlias)
+	for _, alias := range scw.sourceTabletAliases {
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+		ti, err := scw.wr.TopoServer().GetTablet(shortCtx, alias)
+		cancel()
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot read tablet %v", topoproto.TabletAliasString(alias))
+		}
+		keyspaceAndShard := topoproto.KeyspaceShardString(ti.Keyspace, ti.Shard)
+		if !isSourceShard[keyspaceAndShard] {
+			return fmt.Errorf("tablet %v from -source_tablet_alias is in %v which is not a source shard", topoproto.TabletAliasString(alias), keyspaceAndShard)
+		}
+		if _, ok := pinnedAliases[keyspaceAndShard]; ok {
+			return fmt.Errorf("-source_tablet_alias has more than one tablet for source shard %v", keyspaceAndShard)
+		}
+		pinnedAliases[keyspaceAndShard] = alias
+	}
+
 	// find an appropriate tablet in the source shards
 	scw.offlineSourceAliases = make([]*topodatapb.TabletAlias, len(scw.sourceShards))
 	for i, si := range scw.sourceShards {
-		var err error
-		scw.offlineSourceAliases[i], err = FindWorkerTablet(ctx, scw.wr, scw.cleaner, scw.tsc, scw.cell, si.Keyspace(), si.ShardName(), scw.minHealthyRdonlyTablets, topodatapb.TabletType_RDONLY)
-		if err != nil {
-			return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", scw.cell, si.Keyspace(), si.ShardName())
+		if alias, ok := pinnedAliases[topoproto.KeyspaceShardString(si.Keyspace(), si.ShardName())]; ok {
+			if err := UseWorkerTablet(ctx, scw.wr, scw.cleaner, alias, si.Keyspace(), si.ShardName(), topodatapb.TabletType_RDONLY); err != nil {
+				return vterrors.Wrapf(err, "UseWorkerTablet() failed for %v", topoproto.TabletAliasString(alias))
+			}
+			scw.offlineSourceAliases[i] = alias
+		} else {
+			var err error
+			scw.offlineSourceAliases[i], err = FindWorkerTablet(ctx, scw.wr, scw.cleaner, scw.tsc, scw.cell, si.Keyspace(), si.ShardName(), scw.minHealthyRdonlyTablets, topodatapb.TabletType_RDONLY)
+			if err != nil {
+				return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", scw.cell, si.Keyspace(), si.ShardName())
+			}
 		}
 		scw.wr.Logger().Infof("Using tablet %v as source for %v/%v", topoproto.TabletAliasString(scw.offlineSourceAliases[i]), si.Keyspace(), si.ShardName())
 	}
@@ -719,7 +1067,7 @@ func (scw *SplitCloneWorker) findOfflineSourceTablets(ctx context.Context) error
 	// get the tablet info for them, and stop their replication
 	scw.sourceTablets = make([]*topodatapb.Tablet, len(scw.offlineSourceAliases))
 	for i, alias := range scw.offlineSourceAliases {
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 		ti, err := scw.wr.TopoServer().GetTablet(shortCtx, alias)
 		cancel()
 		if err != nil {
@@ -727,7 +1075,7 @@ func (scw *SplitCloneWorker) findOfflineSourceTablets(ctx context.Context) error
 		}
 		scw.sourceTablets[i] = ti.Tablet
 
-		shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 		err = scw.wr.TabletManagerClient().StopSlave(shortCtx, scw.sourceTablets[i])
 		cancel()
 		if err != nil {
@@ -786,7 +1134,7 @@ func (scw *SplitCloneWorker) waitForTablets(ctx context.Context, shardInfos []*t
 			// We wait for --min_healthy_rdonly_tablets because we will use several
 			// tablets per shard to spread reading the chunks of rows across as many
 			// tablets as possible.
-			if _, err := waitForHealthyTablets(ctx, scw.wr, scw.tsc, scw.cell, keyspace, shard, scw.minHealthyRdonlyTablets, timeout, topodatapb.TabletType_RDONLY); err != nil {
+			if _, err := waitForHealthyTablets(ctx, scw.wr, scw.tsc, scw.cell, keyspace, shard, scw.minHealthyRdonlyTablets, timeout, topodatapb.TabletType_RDONLY, nil /* exclude */); err != nil {
 				rec.RecordError(err)
 			}
 		}(si.Keyspace(), si.ShardName())
@@ -806,7 +1154,7 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 	scw.setState(state)
 	start := time.Now()
 	defer func() {
-		statsStateDurationsNs.Set(string(state), time.Now().Sub(start).Nanoseconds())
+		scw.setStateDuration(state, time.Now().Sub(start))
 	}()
 
 	var firstSourceTablet *topodatapb.Tablet
@@ -877,31 +1225,38 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 	// races between "defer throttler.ThreadFinished()" (must be executed first)
 	// and "defer scw.closeThrottlers()". Otherwise, vtworker will panic.
 
-	insertChannels := make([]chan string, len(scw.destinationShards))
+	destinationQueues := make([]*destinationQueue, len(scw.destinationShards))
 	destinationWaitGroup := sync.WaitGroup{}
 	for shardIndex, si := range scw.destinationShards {
-		// We create one channel per destination tablet. It is sized to have a
-		// buffer of a maximum of destinationWriterCount * 2 items, to hopefully
-		// always have data. We then have destinationWriterCount go routines reading
-		// from it.
-		insertChannels[shardIndex] = make(chan string, scw.destinationWriterCount*2)
+		// We create one queue per destination shard. By default, it is sized to
+		// have a buffer of a maximum of destinationWriterCount * 2 items, to
+		// hopefully always have data. We then have destinationWriterCount go
+		// routines reading from it. A larger queue (--destination_queue_size)
+		// prevents that a single slow destination blocks the readers right away.
+		destinationQueues[shardIndex] = newDestinationQueue(si.Keyspace(), si.ShardName(), scw.destinationQueueSizeOrDefault())
 
 		for j := 0; j < scw.destinationWriterCount; j++ {
 			destinationWaitGroup.Add(1)
-			go func(keyspace, shard string, insertChannel chan string, throttler *throttler.Throttler, threadID int) {
+			go func(keyspace, shard string, insertChannel chan *writeQuery, throttler *throttler.Throttler, threadID int) {
 				defer destinationWaitGroup.Done()
 				defer throttler.ThreadFinished(threadID)
+				defer scw.recoverPanic(func(err error) {
+					processError("%v", err)
+				})
 
-				executor := newExecutor(scw.wr, scw.tsc, throttler, keyspace, shard, threadID)
+				executor := newExecutor(scw.wr, scw.tsc, throttler, scw.writeLimiter, scw.compression, keyspace, shard, threadID, scw.writeTransactionMaxRows, scw.writeTransactionMaxSize)
 				if err := executor.fetchLoop(ctx, insertChannel); err != nil {
 					processError("executer.FetchLoop failed: %v", err)
 				}
-			}(si.Keyspace(), si.ShardName(), insertChannels[shardIndex], scw.getThrottler(si.Keyspace(), si.ShardName()), j)
+			}(si.Keyspace(), si.ShardName(), destinationQueues[shardIndex].ch, scw.getThrottler(si.Keyspace(), si.ShardName()), j)
 		}
 	}
 
+	scw.setDestinationQueues(destinationQueues)
+	defer scw.setDestinationQueues(nil)
+
 	// Now for each table, read data chunks and send them to all
-	// insertChannels
+	// destinationQueues
 	sourceWaitGroup := sync.WaitGroup{}
 	sema := sync2.NewSemaphore(scw.sourceReaderCount, 0)
 	for tableIndex, td := range sourceSchemaDefinition.TableDefinitions {
@@ -915,10 +1270,35 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 
 		// TODO(mberlin): We're going to chunk *all* source shards based on the MIN
 		// and MAX values of the *first* source shard. Is this going to be a problem?
-		chunks, err := generateChunks(ctx, scw.wr, firstSourceTablet, td, scw.chunkCount, scw.minRowsPerChunk)
-		if err != nil {
-			processError("failed to split table into chunks: %v", err)
-			break
+		var chunks []chunk
+		var resumed bool
+		if state == WorkerStateCloneOnline {
+			// Reuse the chunks of a previous run. Otherwise, we could not tell
+			// which chunks were already copied.
+			chunks, resumed = scw.checkpointer.chunks(td.Name)
+		}
+		if !resumed {
+			chunks, err = generateChunks(ctx, scw.wr, firstSourceTablet, td, scw.chunkCount, scw.minRowsPerChunk)
+			if err != nil {
+				processError("failed to split table into chunks: %v", err)
+				break
+			}
+			if state == WorkerStateCloneOnline {
+				if err := scw.checkpointer.setChunks(ctx, td.Name, chunks); err != nil {
+					processError("failed to save the checkpoint for table %v: %v", td.Name, err)
+					break
+				}
+			}
+		}
+		if resumed {
+			var pending []chunk
+			for _, c := range chunks {
+				if !scw.checkpointer.isChunkDone(td.Name, c) {
+					pending = append(pending, c)
+				}
+			}
+			scw.wr.Logger().Infof("table=%v: Resuming online clone. Skipping %v out of %v chunks which were already copied.", td.Name, len(chunks)-len(pending), len(chunks))
+			chunks = pending
 		}
 		tableStatusList.setThreadCount(tableIndex, len(chunks))
 
@@ -926,6 +1306,9 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 			sourceWaitGroup.Add(1)
 			go func(td *tabletmanagerdatapb.TableDefinition, tableIndex int, chunk chunk) {
 				defer sourceWaitGroup.Done()
+				defer scw.recoverPanic(func(err error) {
+					processError("%v", err)
+				})
 				errPrefix := fmt.Sprintf("table=%v chunk=%v", td.Name, chunk)
 
 				// We need our own error per Go routine to avoid races.
@@ -938,6 +1321,10 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 					processError("%v: Context expired while this thread was waiting for its turn. Context error: %v", errPrefix, err)
 					return
 				}
+				if err := scw.waitIfPaused(ctx); err != nil {
+					processError("%v: Context expired while the worker was paused. Context error: %v", errPrefix, err)
+					return
+				}
 
 				tableStatusList.threadStarted(tableIndex)
 				defer tableStatusList.threadDone(tableIndex)
@@ -1009,6 +1396,8 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 				} else {
 					destReader = destReaders[0]
 				}
+				sourceReader = newPausableResultReader(ctx, &scw.StatusWorker, sourceReader)
+				destReader = newPausableResultReader(ctx, &scw.StatusWorker, destReader)
 
 				dbNames := make([]string, len(scw.destinationShards))
 				for i, si := range scw.destinationShards {
@@ -1018,40 +1407,56 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 				// Compare the data and reconcile any differences.
 				differ, err := NewRowDiffer2(ctx, sourceReader, destReader, td, tableStatusList, tableIndex,
 					scw.destinationShards, keyResolver,
-					insertChannels, ctx.Done(), dbNames, scw.writeQueryMaxRows, scw.writeQueryMaxSize, statsCounters)
+					destinationQueues, ctx.Done(), dbNames, scw.writeQueryMaxRows, scw.writeQueryMaxSize, statsCounters)
 				if err != nil {
 					processError("%v: NewRowDiffer2 failed: %v", errPrefix, err)
 					return
 				}
+				var writes *chunkWrites
+				if state == WorkerStateCloneOnline {
+					writes = newChunkWrites()
+					differ.trackWrites(writes)
+				}
 				// Ignore the diff report because all diffs should get reconciled.
 				_ /* DiffReport */, err = differ.Diff()
 				if err != nil {
 					processError("%v: RowDiffer2 failed: %v", errPrefix, err)
 					return
 				}
+
+				if state == WorkerStateCloneOnline {
+					// Checkpoint the chunk only after the destinations committed all
+					// of its writes. Otherwise, a --resume after a crash would skip a
+					// chunk whose writes were lost. There may be no offline phase
+					// which reconciles them (--offline=false, --catch_up).
+					if err := writes.wait(ctx); err != nil {
+						processError("%v: Context expired while waiting for the writes of the chunk: %v", errPrefix, err)
+						return
+					}
+					if err := scw.checkpointer.markChunkDone(ctx, td.Name, chunk); err != nil {
+						processError("%v: failed to save the checkpoint: %v", errPrefix, err)
+						return
+					}
+				}
 			}(td, tableIndex, c)
 		}
 	}
 	sourceWaitGroup.Wait()
 
-	for shardIndex := range scw.destinationShards {
-		close(insertChannels[shardIndex])
+	for _, dq := range destinationQueues {
+		dq.close()
 	}
 	destinationWaitGroup.Wait()
 	if firstError != nil {
 		return firstError
 	}
 
-	if state == WorkerStateCloneOffline {
-		// Create and populate the vreplication table to give filtered replication
-		// a starting point.
-		queries := make([]string, 0, 4)
-		queries = append(queries, binlogplayer.CreateVReplicationTable()...)
-
+	// TableMigrate is a one-off copy and does not start filtered replication.
+	if state == WorkerStateCloneOffline && scw.cloneType != tableMigrate {
 		// get the current position from the sources
 		sourcePositions := make([]string, len(scw.sourceShards))
 		for shardIndex := range scw.sourceShards {
-			shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+			shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 			status, err := scw.wr.TabletManagerClient().SlaveStatus(shortCtx, scw.sourceTablets[shardIndex])
 			cancel()
 			if err != nil {
@@ -1060,45 +1465,70 @@ func (scw *SplitCloneWorker) clone(ctx context.Context, state StatusWorkerState)
 			sourcePositions[shardIndex] = status.Position
 		}
 
-		for _, si := range scw.destinationShards {
-			destinationWaitGroup.Add(1)
-			go func(keyspace, shard string, kr *topodatapb.KeyRange) {
-				defer destinationWaitGroup.Done()
-				scw.wr.Logger().Infof("Making and populating vreplication table")
+		// Create and populate the vreplication table to give filtered replication
+		// a starting point.
+		if _, err := scw.startFilteredReplication(ctx, sourcePositions, nil /* catchUpPositions */); err != nil {
+			return err
+		}
+	} // clonePhase == offline
+	return nil
+}
 
-				exc := newExecutor(scw.wr, scw.tsc, nil, keyspace, shard, 0)
-				for shardIndex, src := range scw.sourceShards {
-					bls := &binlogdatapb.BinlogSource{
-						Keyspace: src.Keyspace(),
-						Shard:    src.ShardName(),
-					}
-					if scw.tables == nil {
-						bls.KeyRange = kr
-					} else {
-						bls.Tables = scw.tables
-					}
-					// TODO(mberlin): Fill in scw.maxReplicationLag once the adapative
-					//                throttler is enabled by default.
-					qr, err := exc.vreplicationExec(ctx, binlogplayer.CreateVReplication("SplitClone", bls, sourcePositions[shardIndex], scw.maxTPS, throttler.ReplicationLagModuleDisabled, time.Now().Unix()))
-					if err != nil {
-						processError("vreplication queries failed: %v", err)
-						break
-					}
-					if err := scw.wr.SourceShardAdd(ctx, keyspace, shard, uint32(qr.InsertID), src.Keyspace(), src.ShardName(), src.Shard.KeyRange, scw.tables); err != nil {
-						processError("could not add source shard: %v", err)
-						break
-					}
+// startFilteredReplication creates a vreplication stream for each source
+// shard on all destination masters. Each stream starts at the respective
+// entry of "sourcePositions". If "catchUpPositions" is set, the streams
+// ignore duplicate key errors until they reach the respective position.
+// It returns the vreplication ids per destination and source shard.
+func (scw *SplitCloneWorker) startFilteredReplication(ctx context.Context, sourcePositions, catchUpPositions []string) ([][]uint32, error) {
+	uids := make([][]uint32, len(scw.destinationShards))
+	wg := sync.WaitGroup{}
+	rec := concurrency.AllErrorRecorder{}
+	for i, si := range scw.destinationShards {
+		uids[i] = make([]uint32, len(scw.sourceShards))
+		wg.Add(1)
+		go func(keyspace, shard string, kr *topodatapb.KeyRange, uids []uint32) {
+			defer wg.Done()
+			scw.wr.Logger().Infof("Making and populating vreplication table")
+
+			exc := newExecutor(scw.wr, scw.tsc, nil, nil /* writeLimiter */, "" /* compression */, keyspace, shard, 0, 0 /* transactionMaxRows */, 0 /* transactionMaxSize */)
+			for shardIndex, src := range scw.sourceShards {
+				bls := &binlogdatapb.BinlogSource{
+					Keyspace: src.Keyspace(),
+					Shard:    src.ShardName(),
 				}
-				// refreshState will cause the destination to become non-serving because
-				// it's now participating in the resharding workflow.
-				if err := exc.refreshState(ctx); err != nil {
-					processError("RefreshState failed on tablet %v/%v: %v", keyspace, shard, err)
+				if scw.tables == nil {
+					bls.KeyRange = kr
+				} else {
+					bls.Tables = scw.tables
 				}
-			}(si.Keyspace(), si.ShardName(), si.KeyRange)
-		}
-		destinationWaitGroup.Wait()
-	} // clonePhase == offline
-	return firstError
+				if catchUpPositions != nil {
+					bls.CatchUpPosition = catchUpPositions[shardIndex]
+				}
+				// TODO(mberlin): Fill in scw.maxReplicationLag once the adapative
+				//                throttler is enabled by default.
+				qr, err := exc.vreplicationExec(ctx, binlogplayer.CreateVReplication("SplitClone", bls, sourcePositions[shardIndex], scw.maxTPS, throttler.ReplicationLagModuleDisabled, time.Now().Unix()))
+				if err != nil {
+					rec.RecordError(vterrors.Wrap(err, "vreplication queries failed"))
+					return
+				}
+				uids[shardIndex] = uint32(qr.InsertID)
+				if err := scw.wr.SourceShardAdd(ctx, keyspace, shard, uint32(qr.InsertID), src.Keyspace(), src.ShardName(), src.Shard.KeyRange, scw.tables); err != nil {
+					rec.RecordError(vterrors.Wrap(err, "could not add source shard"))
+					return
+				}
+			}
+			// refreshState will cause the destination to become non-serving because
+			// it's now participating in the resharding workflow.
+			if err := exc.refreshState(ctx); err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "RefreshState failed on tablet %v/%v", keyspace, shard))
+			}
+		}(si.Keyspace(), si.ShardName(), si.KeyRange, uids[i])
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+	return uids, nil
 }
 
 func (scw *SplitCloneWorker) getSourceSchema(ctx context.Context, tablet *topodatapb.Tablet) (*tabletmanagerdatapb.SchemaDefinition, error) {
@@ -1107,7 +1537,7 @@ func (scw *SplitCloneWorker) getSourceSchema(ctx context.Context, tablet *topoda
 	// on all source shards. Furthermore, we estimate the number of rows
 	// in each source shard for each table to be about the same
 	// (rowCount is used to estimate an ETA)
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
 	sourceSchemaDefinition, err := scw.wr.GetSchema(shortCtx, tablet.Alias, scw.tables, scw.excludeTables, false /* includeViews */)
 	cancel()
 	if err != nil {
@@ -1124,14 +1554,48 @@ func (scw *SplitCloneWorker) getSourceSchema(ctx context.Context, tablet *topoda
 	return sourceSchemaDefinition, nil
 }
 
+// verifyTables compares each copied table between the source tablet of the
+// offline phase and the destination master. The source tablet still has
+// replication stopped at the position of the offline clone. Therefore, any
+// difference means that the copy is incomplete or that the tables were
+// modified on the destination in the meantime.
+func (scw *SplitCloneWorker) verifyTables(ctx context.Context) error {
+	scw.setState(WorkerStateDiff)
+
+	si := scw.destinationShards[0]
+	masters := scw.tsc.GetHealthyTabletStats(si.Keyspace(), si.ShardName(), topodatapb.TabletType_MASTER)
+	if len(masters) == 0 {
+		return fmt.Errorf("cannot find MASTER tablet for destination shard for %v/%v (in cell: %v) in HealthCheck: empty TabletStats list", si.Keyspace(), si.ShardName(), scw.cell)
+	}
+	destinationAlias := masters[0].Tablet.Alias
+
+	sourceSchemaDefinition, err := scw.getSourceSchema(ctx, scw.sourceTablets[0])
+	if err != nil {
+		return err
+	}
+	for _, td := range sourceSchemaDefinition.TableDefinitions {
+		scw.wr.Logger().Infof("Verifying table %v on %v against %v", td.Name, topoproto.TabletAliasString(destinationAlias), topoproto.TabletAliasString(scw.offlineSourceAliases[0]))
+		report, err := checksumDiffTable(ctx, scw.wr, &scw.StatusWorker, scw.offlineSourceAliases[0], destinationAlias, td, "" /* sourceWhere */, "" /* destinationWhere */, nil /* repairer */, nil /* results */, comparisonOptions{}, scw.chunkCount, scw.minRowsPerChunk)
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot verify table %v", td.Name)
+		}
+		if report.HasDifferences() {
+			return fmt.Errorf("table %v differs between source and destination after the copy: %v", td.Name, report.String())
+		}
+		scw.wr.Logger().Infof("Table %v checks out", td.Name)
+	}
+	return nil
+}
+
 // createKeyResolver is called at the start of each chunk pipeline.
 // It creates a keyspaceIDResolver which translates a given row to a
 // keyspace ID. This is necessary to route the to be copied rows to the
 // different destination shards.
 func (scw *SplitCloneWorker) createKeyResolver(td *tabletmanagerdatapb.TableDefinition) (keyspaceIDResolver, error) {
-	if scw.cloneType == verticalSplit {
-		// VerticalSplitClone currently always has exactly one destination shard
-		// and therefore does not require routing between multiple shards.
+	if scw.cloneType == verticalSplit || scw.cloneType == tableMigrate {
+		// VerticalSplitClone and TableMigrate currently always have exactly one
+		// destination shard and therefore do not require routing between
+		// multiple shards.
 		return nil, nil
 	}
 
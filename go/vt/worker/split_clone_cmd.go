@@ -32,6 +32,8 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 const splitCloneHTML = `
@@ -68,6 +70,10 @@ const splitCloneHTML2 = `
         <INPUT type="checkbox" id="online" name="online" value="true"{{if .DefaultOnline}} checked{{end}}></BR>
       <LABEL for="offline">Do Offline Copy: (exact copy at a specific GTID, required before shard migration, source and destination tablets will be put out of serving during copy)</LABEL>
         <INPUT type="checkbox" id="offline" name="offline" value="true"{{if .DefaultOnline}} checked{{end}}></BR>
+      <LABEL for="resume">Resume Online Copy: (continue the online copy from the checkpoint of a previous run, requires the same chunk parameters)</LABEL>
+        <INPUT type="checkbox" id="resume" name="resume" value="true"{{if .DefaultResume}} checked{{end}}></BR>
+      <LABEL for="catchUp">Catch Up: (instead of the offline copy, let filtered replication catch up after the online copy and compare the row counts at the end, requires Do Online Copy, no Offline Copy and row-based binlogs with full row images on the source tablets)</LABEL>
+        <INPUT type="checkbox" id="catchUp" name="catchUp" value="true"{{if .DefaultCatchUp}} checked{{end}}></BR>
       <LABEL for="excludeTables">Exclude Tables: </LABEL>
         <INPUT type="text" id="excludeTables" name="excludeTables" value="/ignored/"></BR>
       <LABEL for="chunkCount">Chunk Count: </LABEL>
@@ -80,14 +86,24 @@ const splitCloneHTML2 = `
         <INPUT type="text" id="writeQueryMaxRows" name="writeQueryMaxRows" value="{{.DefaultWriteQueryMaxRows}}"></BR>
       <LABEL for="writeQueryMaxSize">Maximum Size (in bytes) per Write Query: </LABEL>
         <INPUT type="text" id="writeQueryMaxSize" name="writeQueryMaxSize" value="{{.DefaultWriteQueryMaxSize}}"></BR>
+      <LABEL for="writeTransactionMaxRows">Maximum Number of Rows per Write Transaction (0 means one write query per transaction): </LABEL>
+        <INPUT type="text" id="writeTransactionMaxRows" name="writeTransactionMaxRows" value="{{.DefaultWriteTransactionMaxRows}}"></BR>
+      <LABEL for="writeTransactionMaxSize">Maximum Size (in bytes) per Write Transaction: </LABEL>
+        <INPUT type="text" id="writeTransactionMaxSize" name="writeTransactionMaxSize" value="{{.DefaultWriteTransactionMaxSize}}"></BR>
       <LABEL for="destinationWriterCount">Destination Writer Count: </LABEL>
         <INPUT type="text" id="destinationWriterCount" name="destinationWriterCount" value="{{.DefaultDestinationWriterCount}}"></BR>
+      <LABEL for="destinationQueueSize">Destination Queue Size (0 = 2 * Destination Writer Count): </LABEL>
+        <INPUT type="text" id="destinationQueueSize" name="destinationQueueSize" value="{{.DefaultDestinationQueueSize}}"></BR>
       <LABEL for="minHealthyRdonlyTablets">Minimum Number of required healthy RDONLY tablets in the source and destination shard at start: </LABEL>
         <INPUT type="text" id="minHealthyRdonlyTablets" name="minHealthyRdonlyTablets" value="{{.DefaultMinHealthyRdonlyTablets}}"></BR>
       <LABEL for="maxTPS">Maximum Write Transactions/second (If non-zero, writes on the destination will be throttled. Unlimited by default.): </LABEL>
         <INPUT type="text" id="maxTPS" name="maxTPS" value="{{.DefaultMaxTPS}}"></BR>
       <LABEL for="maxReplicationLag">Maximum Replication Lag Seconds (enables the adapative throttler. Disabled by default.): </LABEL>
         <INPUT type="text" id="maxReplicationLag" name="maxReplicationLag" value="{{.DefaultMaxReplicationLag}}"></BR>
+      <LABEL for="maxWriteMBPerSecond">Maximum Write Bandwidth in MB/second (If non-zero, the writes to all destination shards will be throttled. Unlimited by default.): </LABEL>
+        <INPUT type="text" id="maxWriteMBPerSecond" name="maxWriteMBPerSecond" value="{{.DefaultMaxWriteMBPerSecond}}"></BR>
+      <LABEL for="compression">Compression of the Writes (snappy or gzip. Uses --grpc_compression if empty.): </LABEL>
+        <INPUT type="text" id="compression" name="compression" value="{{.DefaultCompression}}"></BR>
       <INPUT type="hidden" name="keyspace" value="{{.Keyspace}}"/>
       <INPUT type="hidden" name="shard" value="{{.Shard}}"/>
       <INPUT type="submit" value="Clone"/>
@@ -101,16 +117,24 @@ var splitCloneTemplate2 = mustParseTemplate("splitClone2", splitCloneHTML2)
 func commandSplitClone(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) (Worker, error) {
 	online := subFlags.Bool("online", defaultOnline, "do online copy (optional approximate copy, source and destination tablets will not be put out of serving, minimizes downtime during offline copy)")
 	offline := subFlags.Bool("offline", defaultOffline, "do offline copy (exact copy at a specific GTID, required before shard migration, source and destination tablets will be put out of serving during copy)")
+	resume := subFlags.Bool("resume", defaultResume, "resume the online copy from the checkpoint of a previous run which was interrupted (requires the same --chunk_count and --min_rows_per_chunk)")
+	catchUp := subFlags.Bool("catch_up", defaultCatchUp, "instead of the offline copy, start filtered replication at the position before the online copy, wait until it caught up and compare the row counts (requires --offline=false and binlog_format=ROW with binlog_row_image=FULL on the source tablets)")
 	excludeTables := subFlags.String("exclude_tables", "", "comma separated list of tables to exclude. Each is either an exact match, or a regular expression of the form /regexp/")
 	chunkCount := subFlags.Int("chunk_count", defaultChunkCount, "number of chunks per table")
 	minRowsPerChunk := subFlags.Int("min_rows_per_chunk", defaultMinRowsPerChunk, "minimum number of rows per chunk (may reduce --chunk_count)")
 	sourceReaderCount := subFlags.Int("source_reader_count", defaultSourceReaderCount, "number of concurrent streaming queries to use on the source")
 	writeQueryMaxRows := subFlags.Int("write_query_max_rows", defaultWriteQueryMaxRows, "maximum number of rows per write query")
 	writeQueryMaxSize := subFlags.Int("write_query_max_size", defaultWriteQueryMaxSize, "maximum size (in bytes) per write query")
+	writeTransactionMaxRows := subFlags.Int("write_transaction_max_rows", defaultWriteTransactionMaxRows, "maximum number of rows per write transaction. If > 0, a writer thread merges the INSERT queries of the same table which are already queued into one transaction. This way, there are fewer and larger transactions when the destination cannot keep up. 0 means one transaction per write query")
+	writeTransactionMaxSize := subFlags.Int("write_transaction_max_size", defaultWriteTransactionMaxSize, "maximum size (in bytes) per write transaction. Must be >= -write_query_max_size")
 	destinationWriterCount := subFlags.Int("destination_writer_count", defaultDestinationWriterCount, "number of concurrent RPCs to execute on the destination")
+	destinationQueueSize := subFlags.Int("destination_queue_size", defaultDestinationQueueSize, "number of write queries which are buffered for each destination shard. A larger queue prevents that a temporarily slow destination blocks the copy to all other destinations. 0 means 2 * --destination_writer_count")
 	minHealthyRdonlyTablets := subFlags.Int("min_healthy_rdonly_tablets", defaultMinHealthyRdonlyTablets, "minimum number of healthy RDONLY tablets in the source and destination shard at start")
 	maxTPS := subFlags.Int64("max_tps", defaultMaxTPS, "rate limit of maximum number of (write) transactions/second on the destination (unlimited by default)")
 	maxReplicationLag := subFlags.Int64("max_replication_lag", defaultMaxReplicationLag, "if set, the adapative throttler will be enabled and automatically adjust the write rate to keep the lag below the set value in seconds (disabled by default)")
+	maxWriteMBPerSecond := subFlags.Int("max_write_mb_per_second", defaultMaxWriteMBPerSecond, "if non-zero, limit the bandwidth of the writes to all destination shards to this many MB/second (unlimited by default)")
+	compression := subFlags.String("compression", defaultCompression, "gRPC compression of the writes to the destination shards: snappy or gzip (uses --grpc_compression by default)")
+	sourceTabletAliases := subFlags.String("source_tablet_alias", "", "comma separated list of source tablets (at most one per source shard) to use for the offline copy instead of a random healthy RDONLY tablet")
 	if err := subFlags.Parse(args); err != nil {
 		return nil, err
 	}
@@ -127,7 +151,11 @@ func commandSplitClone(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagS
 	if *excludeTables != "" {
 		excludeTableArray = strings.Split(*excludeTables, ",")
 	}
-	worker, err := newSplitCloneWorker(wr, wi.cell, keyspace, shard, *online, *offline, excludeTableArray, *chunkCount, *minRowsPerChunk, *sourceReaderCount, *writeQueryMaxRows, *writeQueryMaxSize, *destinationWriterCount, *minHealthyRdonlyTablets, *maxTPS, *maxReplicationLag)
+	sourceTabletAliasArray, err := parseTabletAliases(*sourceTabletAliases)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot parse source_tablet_alias")
+	}
+	worker, err := newSplitCloneWorker(wr, wi.cell, keyspace, shard, *online, *offline, *resume, *catchUp, excludeTableArray, *chunkCount, *minRowsPerChunk, *sourceReaderCount, *writeQueryMaxRows, *writeQueryMaxSize, *writeTransactionMaxRows, *writeTransactionMaxSize, *destinationWriterCount, *destinationQueueSize, *minHealthyRdonlyTablets, *maxTPS, *maxReplicationLag, *maxWriteMBPerSecond, *compression, sourceTabletAliasArray)
 	if err != nil {
 		return nil, vterrors.Wrap(err, "cannot create split clone worker")
 	}
@@ -135,7 +163,7 @@ func commandSplitClone(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagS
 }
 
 func keyspacesWithOverlappingShards(ctx context.Context, wr *wrangler.Wrangler) ([]map[string]string, error) {
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	keyspaces, err := wr.TopoServer().GetKeyspaces(shortCtx)
 	cancel()
 	if err != nil {
@@ -150,7 +178,7 @@ func keyspacesWithOverlappingShards(ctx context.Context, wr *wrangler.Wrangler)
 		wg.Add(1)
 		go func(keyspace string) {
 			defer wg.Done()
-			shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+			shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 			osList, err := topotools.FindOverlappingShards(shortCtx, wr.TopoServer(), keyspace)
 			cancel()
 			if err != nil {
@@ -206,15 +234,22 @@ func interactiveSplitClone(ctx context.Context, wi *Instance, wr *wrangler.Wrang
 		result["Shard"] = shard
 		result["DefaultOnline"] = defaultOnline
 		result["DefaultOffline"] = defaultOffline
+		result["DefaultResume"] = defaultResume
+		result["DefaultCatchUp"] = defaultCatchUp
 		result["DefaultChunkCount"] = fmt.Sprintf("%v", defaultChunkCount)
 		result["DefaultMinRowsPerChunk"] = fmt.Sprintf("%v", defaultMinRowsPerChunk)
 		result["DefaultSourceReaderCount"] = fmt.Sprintf("%v", defaultSourceReaderCount)
 		result["DefaultWriteQueryMaxRows"] = fmt.Sprintf("%v", defaultWriteQueryMaxRows)
 		result["DefaultWriteQueryMaxSize"] = fmt.Sprintf("%v", defaultWriteQueryMaxSize)
+		result["DefaultWriteTransactionMaxRows"] = fmt.Sprintf("%v", defaultWriteTransactionMaxRows)
+		result["DefaultWriteTransactionMaxSize"] = fmt.Sprintf("%v", defaultWriteTransactionMaxSize)
 		result["DefaultDestinationWriterCount"] = fmt.Sprintf("%v", defaultDestinationWriterCount)
+		result["DefaultDestinationQueueSize"] = fmt.Sprintf("%v", defaultDestinationQueueSize)
 		result["DefaultMinHealthyRdonlyTablets"] = fmt.Sprintf("%v", defaultMinHealthyRdonlyTablets)
 		result["DefaultMaxTPS"] = fmt.Sprintf("%v", defaultMaxTPS)
 		result["DefaultMaxReplicationLag"] = fmt.Sprintf("%v", defaultMaxReplicationLag)
+		result["DefaultMaxWriteMBPerSecond"] = fmt.Sprintf("%v", defaultMaxWriteMBPerSecond)
+		result["DefaultCompression"] = defaultCompression
 		return nil, splitCloneTemplate2, result, nil
 	}
 
@@ -223,6 +258,10 @@ func interactiveSplitClone(ctx context.Context, wi *Instance, wr *wrangler.Wrang
 	online := onlineStr == "true"
 	offlineStr := r.FormValue("offline")
 	offline := offlineStr == "true"
+	resumeStr := r.FormValue("resume")
+	resume := resumeStr == "true"
+	catchUpStr := r.FormValue("catchUp")
+	catchUp := catchUpStr == "true"
 	excludeTables := r.FormValue("excludeTables")
 	var excludeTableArray []string
 	if excludeTables != "" {
@@ -252,11 +291,26 @@ func interactiveSplitClone(ctx context.Context, wi *Instance, wr *wrangler.Wrang
 	if err != nil {
 		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeQueryMaxSize")
 	}
+	writeTransactionMaxRowsStr := r.FormValue("writeTransactionMaxRows")
+	writeTransactionMaxRows, err := strconv.ParseInt(writeTransactionMaxRowsStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeTransactionMaxRows")
+	}
+	writeTransactionMaxSizeStr := r.FormValue("writeTransactionMaxSize")
+	writeTransactionMaxSize, err := strconv.ParseInt(writeTransactionMaxSizeStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeTransactionMaxSize")
+	}
 	destinationWriterCountStr := r.FormValue("destinationWriterCount")
 	destinationWriterCount, err := strconv.ParseInt(destinationWriterCountStr, 0, 64)
 	if err != nil {
 		return nil, nil, nil, vterrors.Wrap(err, "cannot parse destinationWriterCount")
 	}
+	destinationQueueSizeStr := r.FormValue("destinationQueueSize")
+	destinationQueueSize, err := strconv.ParseInt(destinationQueueSizeStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse destinationQueueSize")
+	}
 	minHealthyRdonlyTabletsStr := r.FormValue("minHealthyRdonlyTablets")
 	minHealthyRdonlyTablets, err := strconv.ParseInt(minHealthyRdonlyTabletsStr, 0, 64)
 	if err != nil {
@@ -272,9 +326,15 @@ func interactiveSplitClone(ctx context.Context, wi *Instance, wr *wrangler.Wrang
 	if err != nil {
 		return nil, nil, nil, vterrors.Wrap(err, "cannot parse maxReplicationLag")
 	}
+	maxWriteMBPerSecondStr := r.FormValue("maxWriteMBPerSecond")
+	maxWriteMBPerSecond, err := strconv.ParseInt(maxWriteMBPerSecondStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse maxWriteMBPerSecond")
+	}
+	compression := r.FormValue("compression")
 
 	// start the clone job
-	wrk, err := newSplitCloneWorker(wr, wi.cell, keyspace, shard, online, offline, excludeTableArray, int(chunkCount), int(minRowsPerChunk), int(sourceReaderCount), int(writeQueryMaxRows), int(writeQueryMaxSize), int(destinationWriterCount), int(minHealthyRdonlyTablets), maxTPS, maxReplicationLag)
+	wrk, err := newSplitCloneWorker(wr, wi.cell, keyspace, shard, online, offline, resume, catchUp, excludeTableArray, int(chunkCount), int(minRowsPerChunk), int(sourceReaderCount), int(writeQueryMaxRows), int(writeQueryMaxSize), int(writeTransactionMaxRows), int(writeTransactionMaxSize), int(destinationWriterCount), int(destinationQueueSize), int(minHealthyRdonlyTablets), maxTPS, maxReplicationLag, int(maxWriteMBPerSecond), compression, nil /* sourceTabletAliases */)
 	if err != nil {
 		return nil, nil, nil, vterrors.Wrap(err, "cannot create worker")
 	}
@@ -284,6 +344,23 @@ func interactiveSplitClone(ctx context.Context, wi *Instance, wr *wrangler.Wrang
 func init() {
 	AddCommand("Clones", Command{"SplitClone",
 		commandSplitClone, interactiveSplitClone,
-		"[--online=false] [--offline=false] [--exclude_tables=''] <keyspace/shard>",
+		"[--online=false] [--offline=false] [--resume] [--catch_up] [--exclude_tables=''] <keyspace/shard>",
 		"Replicates the data and creates configuration for a horizontal split."})
 }
+
+// parseTabletAliases parses a comma separated list of tablet aliases.
+// It returns nil for an empty string.
+func parseTabletAliases(value string) ([]*topodatapb.TabletAlias, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var aliases []*topodatapb.TabletAlias
+	for _, aliasStr := range strings.Split(value, ",") {
+		alias, err := topoproto.ParseTabletAlias(aliasStr)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, alias)
+	}
+	return aliases, nil
+}
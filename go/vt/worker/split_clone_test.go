@@ -852,6 +852,12 @@ func TestSplitCloneV2_Offline_Reconciliation(t *testing.T) {
 	if err := verifyOfflineCounters(4, 4, 10, 86); err != nil {
 		t.Fatalf("wrong Offline counters: %v", err)
 	}
+	if got, want := statsTableRowsComparedCounters.Counts()["ks.-80.offline.table1"], int64(4+4+10+86); got != want {
+		t.Errorf("wrong WorkerTableRowsCompared count: got = %v, want = %v", got, want)
+	}
+	if got, want := statsTableRowsWrittenCounters.Counts()["ks.-80.offline.table1"], int64(4+4+10); got != want {
+		t.Errorf("wrong WorkerTableRowsWritten count: got = %v, want = %v", got, want)
+	}
 }
 
 func TestSplitCloneV2_Throttled(t *testing.T) {
@@ -892,6 +898,22 @@ func TestSplitCloneV2_Throttled(t *testing.T) {
 	}
 }
 
+// TestSplitCloneV2_WriteBandwidth is identical to the regular test
+// TestSplitCloneV2 but limits the write bandwidth and compresses the writes.
+func TestSplitCloneV2_WriteBandwidth(t *testing.T) {
+	tc := &splitCloneTestCase{t: t}
+	tc.setUp(false /* v3 */)
+	defer tc.tearDown()
+
+	args := []string{"SplitClone", "-max_write_mb_per_second", "1", "-compression", "gzip"}
+	args = append(args, tc.defaultWorkerArgs[1:]...)
+
+	// Run the vtworker command.
+	if err := runCommand(t, tc.wi, tc.wi.wr, args); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestSplitCloneV2_RetryDueToReadonly is identical to the regular test
 // TestSplitCloneV2 with the additional twist that the destination masters
 // fail the first write because they are read-only and succeed after that.
@@ -1063,6 +1085,58 @@ func TestSplitCloneV3(t *testing.T) {
 	}
 }
 
+// TestSplitCloneCatchUpFlags verifies that --catch_up can only replace the
+// offline phase.
+func TestSplitCloneCatchUpFlags(t *testing.T) {
+	for _, tc := range []struct {
+		online, offline bool
+		want            string
+	}{
+		{false, false, "at least one clone phase"},
+		{false, true, "-catch_up requires the online clone phase"},
+		{true, true, "-catch_up replaces the offline clone phase"},
+		{true, false, ""},
+	} {
+		_, err := newSplitCloneWorker(nil /* wr */, "cell1", "ks", "-80", tc.online, tc.offline, false /* resume */, true /* catchUp */, nil /* excludeTables */, defaultChunkCount, defaultMinRowsPerChunk, defaultSourceReaderCount, defaultWriteQueryMaxRows, defaultWriteQueryMaxSize, defaultWriteTransactionMaxRows, defaultWriteTransactionMaxSize, defaultDestinationWriterCount, defaultDestinationQueueSize, defaultMinHealthyRdonlyTablets, defaultMaxTPS, defaultMaxReplicationLag, defaultMaxWriteMBPerSecond, defaultCompression, nil /* sourceTabletAliases */)
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("online=%v offline=%v: newSplitCloneWorker failed: %v", tc.online, tc.offline, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("online=%v offline=%v: newSplitCloneWorker error = %v, want error containing: %v", tc.online, tc.offline, err, tc.want)
+		}
+	}
+}
+
+// TestSplitCloneWriteBandwidthFlags verifies the validation of
+// --max_write_mb_per_second and --compression.
+func TestSplitCloneWriteBandwidthFlags(t *testing.T) {
+	for _, tc := range []struct {
+		maxWriteMBPerSecond int
+		compression         string
+		want                string
+	}{
+		{-1, "", "max_write_mb_per_second must be >= 0"},
+		{0, "zstd", "unsupported compression: zstd"},
+		{0, "", ""},
+		{10, "snappy", ""},
+		{10, "gzip", ""},
+	} {
+		_, err := newSplitCloneWorker(nil /* wr */, "cell1", "ks", "-80", defaultOnline, defaultOffline, false /* resume */, false /* catchUp */, nil /* excludeTables */, defaultChunkCount, defaultMinRowsPerChunk, defaultSourceReaderCount, defaultWriteQueryMaxRows, defaultWriteQueryMaxSize, defaultWriteTransactionMaxRows, defaultWriteTransactionMaxSize, defaultDestinationWriterCount, defaultDestinationQueueSize, defaultMinHealthyRdonlyTablets, defaultMaxTPS, defaultMaxReplicationLag, tc.maxWriteMBPerSecond, tc.compression, nil /* sourceTabletAliases */)
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("max_write_mb_per_second=%v compression=%v: newSplitCloneWorker failed: %v", tc.maxWriteMBPerSecond, tc.compression, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("max_write_mb_per_second=%v compression=%v: newSplitCloneWorker error = %v, want error containing: %v", tc.maxWriteMBPerSecond, tc.compression, err, tc.want)
+		}
+	}
+}
+
 func verifyOnlineCounters(inserts, updates, deletes, equal int64) error {
 	rec := concurrency.AllErrorRecorder{}
 	if got, want := statsOnlineInsertsCounters.Counts()["table1"], inserts; got != want {
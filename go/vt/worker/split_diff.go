@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"html/template"
 	"sort"
+	"strings"
 	"sync"
 
 	"vitess.io/vitess/go/vt/vterrors"
@@ -33,6 +34,7 @@ import (
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/wrangler"
 
@@ -44,18 +46,21 @@ import (
 // source shards in a shard split case.
 type SplitDiffWorker struct {
 	StatusWorker
-
-	wr                      *wrangler.Wrangler
-	cell                    string
-	keyspace                string
-	shard                   string
-	sourceUID               uint32
-	sourceShard             *topodatapb.Shard_SourceShard
-	excludeTables           []string
-	minHealthyRdonlyTablets int
-	destinationTabletType   topodatapb.TabletType
-	parallelDiffsCount      int
-	cleaner                 *wrangler.Cleaner
+	diffOptions
+
+	wr              *wrangler.Wrangler
+	cell            string
+	keyspace        string
+	shard           string
+	sourceUID       uint32
+	sourceShard     *topodatapb.Shard_SourceShard
+	tables          []string
+	excludeTables   []string
+	comparison      comparisonOptions
+	reportWriter    *diffReportWriter
+	resultsWriter   *diffResultsWriter
+	tableStatusList *tableStatusList
+	cleaner         *wrangler.Cleaner
 
 	// populated during WorkerStateInit, read-only after that
 	keyspaceInfo *topo.KeyspaceInfo
@@ -65,26 +70,43 @@ type SplitDiffWorker struct {
 	sourceAlias      *topodatapb.TabletAlias
 	destinationAlias *topodatapb.TabletAlias
 
+	// populated during WorkerStateSyncReplication with
+	// --use_consistent_snapshot, read-only after that
+	sourceSnapshot      *consistentSnapshot
+	destinationSnapshot *consistentSnapshot
+
+	// populated during WorkerStateSyncReplication, read-only after that
+	destinationMasterPosition string
+
 	// populated during WorkerStateDiff
 	sourceSchemaDefinition      *tabletmanagerdatapb.SchemaDefinition
 	destinationSchemaDefinition *tabletmanagerdatapb.SchemaDefinition
 }
 
 // NewSplitDiffWorker returns a new SplitDiffWorker object.
-func NewSplitDiffWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, sourceUID uint32, excludeTables []string, minHealthyRdonlyTablets, parallelDiffsCount int, tabletType topodatapb.TabletType) Worker {
-	return &SplitDiffWorker{
-		StatusWorker:            NewStatusWorker(),
-		wr:                      wr,
-		cell:                    cell,
-		keyspace:                keyspace,
-		shard:                   shard,
-		sourceUID:               sourceUID,
-		excludeTables:           excludeTables,
-		minHealthyRdonlyTablets: minHealthyRdonlyTablets,
-		destinationTabletType:   tabletType,
-		parallelDiffsCount:      parallelDiffsCount,
-		cleaner:                 &wrangler.Cleaner{},
+func NewSplitDiffWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, sourceUID uint32, tables, excludeTables []string, opts diffOptions) (Worker, error) {
+	comparison, err := opts.validate()
+	if err != nil {
+		return nil, err
 	}
+	opts.where = parenthesize(opts.where)
+
+	return &SplitDiffWorker{
+		StatusWorker:    NewStatusWorker(),
+		diffOptions:     opts,
+		wr:              wr,
+		cell:            cell,
+		keyspace:        keyspace,
+		shard:           shard,
+		sourceUID:       sourceUID,
+		tables:          tables,
+		excludeTables:   excludeTables,
+		comparison:      comparison,
+		reportWriter:    newDiffReportWriter(wr.TopoServer(), "SplitDiff", keyspace, shard, opts.reportDir, opts.reportToTopo, opts.samplePercent),
+		resultsWriter:   newDiffResultsWriter(wr, "SplitDiff", keyspace, shard, opts.diffResultsDir, opts.diffResultsToTable),
+		tableStatusList: &tableStatusList{action: "diff", keyspace: keyspace, shard: shard},
+		cleaner:         newCleaner(wr, "SplitDiff", keyspace, shard),
+	}, nil
 }
 
 // StatusAsHTML is part of the Worker interface
@@ -94,14 +116,25 @@ func (sdw *SplitDiffWorker) StatusAsHTML() template.HTML {
 	result := "<b>Working on:</b> " + sdw.keyspace + "/" + sdw.shard + "</br>\n"
 	result += "<b>State:</b> " + state.String() + "</br>\n"
 	switch state {
-	case WorkerStateDiff:
-		result += "<b>Running...</b></br>\n"
-	case WorkerStateDiffWillFail:
-		result += "<b>Running - have already found differences...</b></br>\n"
+	case WorkerStateDiff, WorkerStateDiffWillFail:
+		if state == WorkerStateDiff {
+			result += "<b>Running...</b></br>\n"
+		} else {
+			result += "<b>Running - have already found differences...</b></br>\n"
+		}
+		result += "<b>Progress:</b> " + sdw.tableStatusList.formatProgress() + "</br>\n"
+		_, eta := sdw.tableStatusList.format()
+		result += "<b>ETA:</b> " + eta.String() + "</br>\n"
+		result += string(sdw.tableStatusList.formatHTML())
 	case WorkerStateDone:
 		result += "<b>Success.</b></br>\n"
+		result += string(sdw.tableStatusList.formatHTML())
+	case WorkerStateError:
+		// Show which tables failed.
+		result += string(sdw.tableStatusList.formatHTML())
 	}
 
+	result += sdw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -112,20 +145,34 @@ func (sdw *SplitDiffWorker) StatusAsText() string {
 	result := "Working on: " + sdw.keyspace + "/" + sdw.shard + "\n"
 	result += "State: " + state.String() + "\n"
 	switch state {
-	case WorkerStateDiff:
-		result += "Running...\n"
-	case WorkerStateDiffWillFail:
-		result += "Running - have already found differences...\n"
+	case WorkerStateDiff, WorkerStateDiffWillFail:
+		if state == WorkerStateDiff {
+			result += "Running...\n"
+		} else {
+			result += "Running - have already found differences...\n"
+		}
+		result += "Progress: " + sdw.tableStatusList.formatProgress() + "\n"
+		statuses, eta := sdw.tableStatusList.format()
+		result += "ETA: " + eta.String() + "\n"
+		result += strings.Join(statuses, "\n")
 	case WorkerStateDone:
 		result += "Success.\n"
+		statuses, _ := sdw.tableStatusList.format()
+		result += strings.Join(statuses, "\n")
 	}
+	result += sdw.formatPanicText()
 	return result
 }
 
+// progress is part of the progressReporter interface.
+func (sdw *SplitDiffWorker) progress() []tableProgress {
+	return sdw.tableStatusList.progress()
+}
+
 // Run is mostly a wrapper to run the cleanup at the end.
 func (sdw *SplitDiffWorker) Run(ctx context.Context) error {
-	resetVars()
-	err := sdw.run(ctx)
+	sdw.resetRunVars()
+	err := sdw.runRecovered(ctx, sdw.run)
 
 	sdw.SetState(WorkerStateCleanUp)
 	cerr := sdw.cleaner.CleanUp(sdw.wr)
@@ -136,6 +183,10 @@ func (sdw *SplitDiffWorker) Run(ctx context.Context) error {
 			err = cerr
 		}
 	}
+	if err == nil && *waitForServingTimeout != 0 {
+		sdw.SetState(WorkerStateWaitForServing)
+		err = waitForServingTablets(ctx, sdw.wr, sdw.cleaner.ChangedTablets(), *waitForServingTimeout)
+	}
 	if err != nil {
 		sdw.wr.Logger().Errorf("Run() error: %v", err)
 		sdw.SetState(WorkerStateError)
@@ -162,6 +213,14 @@ func (sdw *SplitDiffWorker) run(ctx context.Context) error {
 		return err
 	}
 
+	// validate the chosen tablets before we stop any replication
+	if err := sdw.checkTargets(ctx); err != nil {
+		return vterrors.Wrap(err, "checkTargets() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
 	// third phase: synchronize replication
 	if err := sdw.synchronizeReplication(ctx); err != nil {
 		return vterrors.Wrap(err, "synchronizeReplication() failed")
@@ -178,6 +237,9 @@ func (sdw *SplitDiffWorker) run(ctx context.Context) error {
 		return err
 	}
 
+	if verifiesShard(sdw.tables, sdw.where, sdw.samplePercent, sdw.repair) {
+		writeDiffVerification(ctx, sdw.wr, "SplitDiff", sdw.keyspace, sdw.shard, sdw.sourceShard.Keyspace, sdw.sourceShard.Shard, sdw.destinationMasterPosition)
+	}
 	return nil
 }
 
@@ -187,13 +249,13 @@ func (sdw *SplitDiffWorker) init(ctx context.Context) error {
 	sdw.SetState(WorkerStateInit)
 
 	var err error
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	sdw.keyspaceInfo, err = sdw.wr.TopoServer().GetKeyspace(shortCtx, sdw.keyspace)
 	cancel()
 	if err != nil {
 		return vterrors.Wrapf(err, "cannot read keyspace %v", sdw.keyspace)
 	}
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	sdw.shardInfo, err = sdw.wr.TopoServer().GetShard(shortCtx, sdw.keyspace, sdw.shard)
 	cancel()
 	if err != nil {
@@ -229,42 +291,57 @@ func (sdw *SplitDiffWorker) init(ctx context.Context) error {
 }
 
 // findTargets phase:
-// - find one rdonly per source shard
-// - find one rdonly in destination shard
+// - find one sourceTabletType per source shard
+// - find one destinationTabletType in destination shard
 // - mark them all as 'worker' pointing back to us
 func (sdw *SplitDiffWorker) findTargets(ctx context.Context) error {
 	sdw.SetState(WorkerStateFindTargets)
 
 	// find an appropriate tablet in destination shard
 	var err error
-	sdw.destinationAlias, err = FindWorkerTablet(
-		ctx,
-		sdw.wr,
-		sdw.cleaner,
-		nil, /* tsc */
-		sdw.cell,
-		sdw.keyspace,
-		sdw.shard,
-		1, /* minHealthyTablets */
-		sdw.destinationTabletType,
-	)
-	if err != nil {
-		return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", sdw.cell, sdw.keyspace, sdw.shard)
+	if sdw.destinationTabletAlias != nil {
+		sdw.destinationAlias = sdw.destinationTabletAlias
+		if err := UseWorkerTablet(ctx, sdw.wr, sdw.cleaner, sdw.destinationAlias, sdw.keyspace, sdw.shard, sdw.destinationTabletType); err != nil {
+			return vterrors.Wrapf(err, "UseWorkerTablet() failed for %v", topoproto.TabletAliasString(sdw.destinationAlias))
+		}
+	} else {
+		sdw.destinationAlias, err = FindWorkerTablet(
+			ctx,
+			sdw.wr,
+			sdw.cleaner,
+			nil, /* tsc */
+			sdw.cell,
+			sdw.keyspace,
+			sdw.shard,
+			1, /* minHealthyTablets */
+			sdw.destinationTabletType,
+		)
+		if err != nil {
+			return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", sdw.cell, sdw.keyspace, sdw.shard)
+		}
+	}
+
+	if sdw.sourceTabletAlias != nil {
+		sdw.sourceAlias = sdw.sourceTabletAlias
+		if err := UseWorkerTablet(ctx, sdw.wr, sdw.cleaner, sdw.sourceAlias, sdw.keyspace, sdw.sourceShard.Shard, sdw.sourceTabletType); err != nil {
+			return vterrors.Wrapf(err, "UseWorkerTablet() failed for %v", topoproto.TabletAliasString(sdw.sourceAlias))
+		}
+		return nil
 	}
 
 	// find an appropriate tablet in the source shard
 	// During an horizontal shard split, multiple workers will race to get
-	// a RDONLY tablet in the source shard. When this happen, concurrent calls
+	// a tablet in the source shard. When this happen, concurrent calls
 	// to FindWorkerTablet could attempt to set to DRAIN state the same tablet. Only
 	// one of these calls to FindWorkerTablet will succeed and the rest will fail.
 	// The following, makes sures we keep trying to find a worker tablet when this error occur.
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	for {
 		select {
 		case <-shortCtx.Done():
-			return fmt.Errorf("Could not find healthy table for %v/%v%v: after: %v, aborting", sdw.cell, sdw.keyspace, sdw.sourceShard.Shard, *remoteActionsTimeout)
+			return fmt.Errorf("Could not find healthy table for %v/%v%v: after: %v, aborting", sdw.cell, sdw.keyspace, sdw.sourceShard.Shard, remoteActionsTimeoutFor(ctx))
 		default:
-			sdw.sourceAlias, err = FindWorkerTablet(ctx, sdw.wr, sdw.cleaner, nil /* tsc */, sdw.cell, sdw.keyspace, sdw.sourceShard.Shard, sdw.minHealthyRdonlyTablets, topodatapb.TabletType_RDONLY)
+			sdw.sourceAlias, err = FindWorkerTablet(ctx, sdw.wr, sdw.cleaner, nil /* tsc */, sdw.cell, sdw.keyspace, sdw.sourceShard.Shard, sdw.minHealthyRdonlyTablets, sdw.sourceTabletType)
 			if err != nil {
 				sdw.wr.Logger().Infof("FindWorkerTablet() failed for %v/%v/%v: %v retrying...", sdw.cell, sdw.keyspace, sdw.sourceShard.Shard, err)
 				continue
@@ -275,6 +352,15 @@ func (sdw *SplitDiffWorker) findTargets(ctx context.Context) error {
 	}
 }
 
+// checkTargets phase:
+// - run the pre-flight checks on the destination master(s) and the chosen
+//   source and destination tablets (see checkTabletsHealthy())
+func (sdw *SplitDiffWorker) checkTargets(ctx context.Context) error {
+	return checkTabletsHealthy(ctx, sdw.wr,
+		[]*topodatapb.TabletAlias{sdw.shardInfo.MasterAlias},
+		[]*topodatapb.TabletAlias{sdw.sourceAlias, sdw.destinationAlias})
+}
+
 // synchronizeReplication phase:
 // 1 - ask the master of the destination shard to pause filtered replication,
 //   and return the source binlog positions
@@ -291,13 +377,18 @@ func (sdw *SplitDiffWorker) findTargets(ctx context.Context) error {
 //    the existing ChangeSlaveType cleanup action to 'spare' type)
 // 5 - restart filtered replication on the destination master.
 //   (remove the cleanup task that does the same)
+//   With --repair_execute, filtered replication stays stopped until the
+//   repair statements were executed and the cleanup task restarts it.
 // At this point, the source and the destination tablet are stopped at the same
 // point.
+// With --use_consistent_snapshot, transactions are opened on the source and
+// destination tablet while they are stopped in step 2 and 4. Their replication
+// is restarted right afterwards and the diff reads from the transactions.
 
 func (sdw *SplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 	sdw.SetState(WorkerStateSyncReplication)
 
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	masterInfo, err := sdw.wr.TopoServer().GetTablet(shortCtx, sdw.shardInfo.MasterAlias)
 	if err != nil {
@@ -306,7 +397,7 @@ func (sdw *SplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 
 	// 1 - stop the master binlog replication, get its current position
 	sdw.wr.Logger().Infof("Stopping master binlog replication on %v", sdw.shardInfo.MasterAlias)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, stopBlpTimeoutFor(ctx))
 	defer cancel()
 	_, err = sdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StopVReplication(sdw.sourceShard.Uid, "for split diff"))
 	if err != nil {
@@ -331,9 +422,9 @@ func (sdw *SplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 		return err
 	}
 
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
 	defer cancel()
-	mysqlPos, err := sdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, sourceTablet.Tablet, vreplicationPos, *remoteActionsTimeout)
+	mysqlPos, err := sdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, sourceTablet.Tablet, vreplicationPos, stopSlaveMinimumTimeoutFor(ctx))
 	if err != nil {
 		return vterrors.Wrapf(err, "cannot stop slave %v at right binlog position %v", sdw.sourceAlias, vreplicationPos)
 	}
@@ -342,10 +433,16 @@ func (sdw *SplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 	// to StartSlave() + ChangeSlaveType(spare)
 	wrangler.RecordStartSlaveAction(sdw.cleaner, sourceTablet.Tablet)
 
+	if sdw.useConsistentSnapshot {
+		if sdw.sourceSnapshot, err = sdw.openSnapshot(ctx, sourceTablet.Tablet); err != nil {
+			return err
+		}
+	}
+
 	// 3 - ask the master of the destination shard to resume filtered
 	//     replication up to the new list of positions
 	sdw.wr.Logger().Infof("Restarting master %v until it catches up to %v", sdw.shardInfo.MasterAlias, mysqlPos)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	_, err = sdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StartVReplicationUntil(sdw.sourceShard.Uid, mysqlPos))
 	if err != nil {
@@ -358,26 +455,41 @@ func (sdw *SplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 	if err != nil {
 		return vterrors.Wrapf(err, "MasterPosition for %v failed", sdw.shardInfo.MasterAlias)
 	}
+	sdw.destinationMasterPosition = masterPos
 
 	// 4 - wait until the destination tablet is equal or passed
 	//     that master binlog position, and stop its replication.
 	sdw.wr.Logger().Infof("Waiting for destination tablet %v to catch up to %v", sdw.destinationAlias, masterPos)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	destinationTablet, err := sdw.wr.TopoServer().GetTablet(shortCtx, sdw.destinationAlias)
 	if err != nil {
 		return err
 	}
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
 	defer cancel()
-	if _, err = sdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, destinationTablet.Tablet, masterPos, *remoteActionsTimeout); err != nil {
+	if _, err = sdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, destinationTablet.Tablet, masterPos, stopSlaveMinimumTimeoutFor(ctx)); err != nil {
 		return vterrors.Wrapf(err, "StopSlaveMinimum for %v at %v failed", sdw.destinationAlias, masterPos)
 	}
 	wrangler.RecordStartSlaveAction(sdw.cleaner, destinationTablet.Tablet)
 
+	if sdw.useConsistentSnapshot {
+		if sdw.destinationSnapshot, err = sdw.openSnapshot(ctx, destinationTablet.Tablet); err != nil {
+			return err
+		}
+	}
+
 	// 5 - restart filtered replication on destination master
+	if sdw.repairExecute {
+		// The repair statements are computed from the stopped tablets. They
+		// must be executed on the destination master while it is still at the
+		// same position. Otherwise, they would overwrite newer changes of
+		// filtered replication. The cleanup task restarts it at the end.
+		sdw.wr.Logger().Infof("Keeping filtered replication stopped on master %v until the differences were repaired (see --repair_execute)", sdw.shardInfo.MasterAlias)
+		return nil
+	}
 	sdw.wr.Logger().Infof("Restarting filtered replication on master %v", sdw.shardInfo.MasterAlias)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	if _, err = sdw.wr.TabletManagerClient().VReplicationExec(ctx, masterInfo.Tablet, binlogplayer.StartVReplication(sdw.sourceShard.Uid)); err != nil {
 		return vterrors.Wrapf(err, "VReplicationExec(start) failed for %v", sdw.shardInfo.MasterAlias)
@@ -386,6 +498,29 @@ func (sdw *SplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 	return nil
 }
 
+// openSnapshot opens a consistentSnapshot on the stopped "tablet" and
+// restarts its replication afterwards.
+func (sdw *SplitDiffWorker) openSnapshot(ctx context.Context, tablet *topodatapb.Tablet) (*consistentSnapshot, error) {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	sdw.wr.Logger().Infof("Opening consistent snapshot on %v", alias)
+	// Each chunk pipeline needs its own transaction.
+	snapshot, err := openConsistentSnapshot(ctx, sdw.wr, tablet.Alias, sdw.parallelDiffsCount*sdw.chunkCount)
+	if err != nil {
+		return nil, err
+	}
+	sdw.cleaner.Record("CloseConsistentSnapshot", alias, func(ctx context.Context, wr *wrangler.Wrangler) error {
+		return snapshot.close(ctx)
+	})
+
+	sdw.wr.Logger().Infof("Restarting replication on %v", alias)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	if err := sdw.wr.TabletManagerClient().StartSlave(shortCtx, tablet); err != nil {
+		return nil, vterrors.Wrapf(err, "StartSlave for %v failed", alias)
+	}
+	return snapshot, nil
+}
+
 // diff phase: will log messages regarding the diff.
 // - get the schema on all tablets
 // - if some table schema mismatches, record them (use existing schema diff tools).
@@ -400,9 +535,9 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 	wg.Add(1)
 	go func() {
 		var err error
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
 		sdw.destinationSchemaDefinition, err = sdw.wr.GetSchema(
-			shortCtx, sdw.destinationAlias, nil /* tables */, sdw.excludeTables, false /* includeViews */)
+			shortCtx, sdw.destinationAlias, sdw.tables, sdw.excludeTables, sdw.includeViews)
 		cancel()
 		if err != nil {
 			sdw.markAsWillFail(rec, err)
@@ -413,9 +548,9 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 	wg.Add(1)
 	go func() {
 		var err error
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
 		sdw.sourceSchemaDefinition, err = sdw.wr.GetSchema(
-			shortCtx, sdw.sourceAlias, nil /* tables */, sdw.excludeTables, false /* includeViews */)
+			shortCtx, sdw.sourceAlias, sdw.tables, sdw.excludeTables, sdw.includeViews)
 		cancel()
 		if err != nil {
 			sdw.markAsWillFail(rec, err)
@@ -428,6 +563,9 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 	if rec.HasErrors() {
 		return rec.Error()
 	}
+	if err := checkTablesMatched(sdw.tables, sdw.destinationSchemaDefinition); err != nil {
+		return err
+	}
 
 	sdw.wr.Logger().Infof("Diffing the schema...")
 	rec = &concurrency.AllErrorRecorder{}
@@ -464,8 +602,63 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 		return vterrors.Wrap(err, "Source shard doesn't overlap with destination")
 	}
 
-	// run the diffs, 8 at a time
-	sdw.wr.Logger().Infof("Running the diffs...")
+	// In checksum mode and for the row count check, MySQL must filter the rows
+	// which are outside of the overlap. That's not possible in v3 mode where
+	// we filter in vtworker.
+	checksumOnly := sdw.checksumOnly
+	rowCountCheck := sdw.rowCountCheck
+	var sourceWhere, destinationWhere string
+	if checksumOnly || rowCountCheck {
+		if keyspaceSchema != nil && (!key.KeyRangeEqual(overlap, sdw.sourceShard.KeyRange) || !key.KeyRangeEqual(overlap, sdw.shardInfo.KeyRange)) {
+			if checksumOnly {
+				sdw.wr.Logger().Warningf("Checksum mode is not supported for v3 keyspaces whose rows must be filtered by key range. Running a full diff instead.")
+				checksumOnly = false
+			}
+			if rowCountCheck {
+				sdw.wr.Logger().Warningf("The row count check is not supported for v3 keyspaces whose rows must be filtered by key range. Skipping it.")
+				rowCountCheck = false
+			}
+		} else {
+			if !key.KeyRangeEqual(overlap, sdw.sourceShard.KeyRange) {
+				if sourceWhere, err = keyRangeWhereClause(overlap, sdw.keyspaceInfo.ShardingColumnName, sdw.keyspaceInfo.ShardingColumnType); err != nil {
+					return err
+				}
+			}
+			if !key.KeyRangeEqual(overlap, sdw.shardInfo.KeyRange) {
+				if destinationWhere, err = keyRangeWhereClause(overlap, sdw.keyspaceInfo.ShardingColumnName, sdw.keyspaceInfo.ShardingColumnType); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if sdw.where != "" {
+		sdw.wr.Logger().Infof("Comparing only the rows which match: %v", sdw.where)
+	}
+	if sdw.samplePercent < 100 {
+		sdw.wr.Logger().Infof("Comparing only a sample of %v%% of the rows", sdw.samplePercent)
+	}
+	if sdw.comparison.hashColumnsLargerThan > 0 {
+		sdw.wr.Logger().Infof("Comparing BLOB and TEXT values larger than %v bytes by their MD5 hash", sdw.comparison.hashColumnsLargerThan)
+	}
+	if sdw.comparison.normalizeCharset != "" {
+		sdw.wr.Logger().Infof("Converting text values to the character set %v before comparing them", sdw.comparison.normalizeCharset)
+	}
+	if sdw.comparison.normalizeTimestamps {
+		sdw.wr.Logger().Infof("Comparing TIMESTAMP values as UNIX timestamps")
+	}
+	for table, columns := range sdw.comparison.ignoreColumns {
+		sdw.wr.Logger().Infof("Ignoring the columns %v of table %v", strings.Join(columns, ", "), table)
+	}
+	if sdw.comparison.floatEpsilon > 0 {
+		sdw.wr.Logger().Infof("Ignoring differences of FLOAT and DOUBLE values up to %v", sdw.comparison.floatEpsilon)
+	}
+
+	if err := sdw.resultsWriter.open(ctx, sdw.shardInfo.MasterAlias); err != nil {
+		return err
+	}
+
+	// run the diffs, parallelDiffsCount at a time
+	sdw.wr.Logger().Infof("Running the diffs (%v tables in parallel)...", sdw.parallelDiffsCount)
 	sem := sync2.NewSemaphore(sdw.parallelDiffsCount, 0)
 	tableDefinitions := sdw.destinationSchemaDefinition.TableDefinitions
 
@@ -473,10 +666,21 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 	// if there are large deltas between table sizes then it's more efficient to start working on the large tables first
 	sort.Slice(tableDefinitions, func(i, j int) bool { return tableDefinitions[i].DataLength > tableDefinitions[j].DataLength })
 
+	sdw.tableStatusList.initialize(sdw.destinationSchemaDefinition)
+
+	// The chunks are computed on the destination because the table
+	// definitions (and their row count estimates) are from there as well.
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	destinationTablet, err := sdw.wr.TopoServer().GetTablet(shortCtx, sdw.destinationAlias)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read destination tablet %v", topoproto.TabletAliasString(sdw.destinationAlias))
+	}
+
 	// use a channel to make sure tables are diffed in order
-	tableChan := make(chan *tabletmanagerdatapb.TableDefinition, len(tableDefinitions))
-	for _, tableDefinition := range tableDefinitions {
-		tableChan <- tableDefinition
+	tableChan := make(chan int, len(tableDefinitions))
+	for tableIndex := range tableDefinitions {
+		tableChan <- tableIndex
 	}
 
 	// start as many goroutines as there are tables to diff
@@ -484,70 +688,113 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			defer sdw.recoverPanic(func(err error) {
+				sdw.markAsWillFail(rec, err)
+			})
 			// use the semaphore to limit the number of tables that are diffed in parallel
 			sem.Acquire()
 			defer sem.Release()
 
 			// grab the table to process out of the channel
-			tableDefinition := <-tableChan
+			tableIndex := <-tableChan
+			tableDefinition := tableDefinitions[tableIndex]
+
+			if tableDefinition.Type == tmutils.TableView {
+				// Views have no rows of their own. Their definition was
+				// already compared by the schema diff.
+				sdw.wr.Logger().Infof("Skipping the row diff on view %v", tableDefinition.Name)
+				return
+			}
 
 			sdw.wr.Logger().Infof("Starting the diff on table %v", tableDefinition.Name)
 
-			// On the source, see if we need a full scan
-			// or a filtered scan.
-			var sourceQueryResultReader *QueryResultReader
-			if key.KeyRangeEqual(overlap, sdw.sourceShard.KeyRange) {
-				sourceQueryResultReader, err = TableScan(ctx, sdw.wr.Logger(), sdw.wr.TopoServer(), sdw.sourceAlias, tableDefinition)
-			} else {
-				sourceQueryResultReader, err = TableScanByKeyRange(ctx, sdw.wr.Logger(), sdw.wr.TopoServer(), sdw.sourceAlias, tableDefinition, overlap, keyspaceSchema, sdw.keyspaceInfo.ShardingColumnName, sdw.keyspaceInfo.ShardingColumnType)
-			}
+			// A table without a primary key is compared by all columns. The
+			// original definition is used to split it into chunks though
+			// because the leading column may be NULL and would not match any
+			// chunk.
+			originalTableDefinition := tableDefinition
+			tableDefinition, err := diffTableDefinition(originalTableDefinition, sdw.pklessTablesUseFullRow, sdw.comparison.ignoreColumns[originalTableDefinition.Name])
 			if err != nil {
-				newErr := vterrors.Wrap(err, "TableScan(ByKeyRange?)(source) failed")
-				sdw.markAsWillFail(rec, newErr)
-				sdw.wr.Logger().Errorf("%v", newErr)
+				sdw.writeDiffReport(ctx, rec, originalTableDefinition.Name, DiffReport{}, err)
+				sdw.tableStatusList.tableFailed(tableIndex, err)
+				sdw.markAsWillFail(rec, err)
+				sdw.wr.Logger().Errorf("%v", err)
 				return
 			}
-			defer sourceQueryResultReader.Close(ctx)
-
-			// On the destination, see if we need a full scan
-			// or a filtered scan.
-			var destinationQueryResultReader *QueryResultReader
-			if key.KeyRangeEqual(overlap, sdw.shardInfo.KeyRange) {
-				destinationQueryResultReader, err = TableScan(ctx, sdw.wr.Logger(), sdw.wr.TopoServer(), sdw.destinationAlias, tableDefinition)
-			} else {
-				destinationQueryResultReader, err = TableScanByKeyRange(ctx, sdw.wr.Logger(), sdw.wr.TopoServer(), sdw.destinationAlias, tableDefinition, overlap, keyspaceSchema, sdw.keyspaceInfo.ShardingColumnName, sdw.keyspaceInfo.ShardingColumnType)
+			tableChecksumOnly := checksumOnly
+			if tableChecksumOnly && len(originalTableDefinition.PrimaryKeyColumns) == 0 {
+				sdw.wr.Logger().Warningf("Checksum mode is not supported for table %v without a primary key. Running a full diff instead.", tableDefinition.Name)
+				tableChecksumOnly = false
 			}
-			if err != nil {
-				newErr := vterrors.Wrap(err, "TableScan(ByKeyRange?)(destination) failed")
-				sdw.markAsWillFail(rec, newErr)
-				sdw.wr.Logger().Errorf("%v", newErr)
-				return
+
+			if rowCountCheck {
+				if err := checkRowCount(ctx, sdw.wr, sdw.sourceAlias, sdw.destinationAlias, tableDefinition, joinConditions(sourceWhere, sdw.rowFilter(tableDefinition)), joinConditions(destinationWhere, sdw.rowFilter(tableDefinition))); err != nil {
+					sdw.writeDiffReport(ctx, rec, tableDefinition.Name, DiffReport{}, err)
+					sdw.tableStatusList.tableFailed(tableIndex, err)
+					sdw.markAsWillFail(rec, err)
+					sdw.wr.Logger().Errorf("%v", err)
+					return
+				}
 			}
-			defer destinationQueryResultReader.Close(ctx)
 
-			// Create the row differ.
-			differ, err := NewRowDiffer(sourceQueryResultReader, destinationQueryResultReader, tableDefinition)
+			var repairer *rowRepairer
+			report, err := retryTableDiff(ctx, sdw.wr.Logger(), tableDefinition.Name, sdw.tableRetryCount, sdw.tableRetryBackoff, func() (report DiffReport, err error) {
+				// A failed attempt must not leave its progress or repair
+				// statements behind.
+				sdw.tableStatusList.resetProgress(tableIndex)
+				if sdw.repair {
+					repairer, err = newRowRepairerForTablet(ctx, sdw.wr, sdw.destinationAlias, tableDefinition, sdw.repairMaxRows)
+					if err != nil {
+						return DiffReport{}, vterrors.Wrap(err, "newRowRepairerForTablet() failed")
+					}
+				}
+				results, err := sdw.resultsWriter.newRecorder(ctx, tableDefinition)
+				if err != nil {
+					return DiffReport{}, vterrors.Wrap(err, "newRecorder() failed")
+				}
+				defer func() {
+					if closeErr := results.close(); closeErr != nil && err == nil {
+						err = closeErr
+					}
+				}()
+
+				if tableChecksumOnly {
+					sdw.tableStatusList.setThreadCount(tableIndex, 1)
+					sdw.tableStatusList.threadStarted(tableIndex)
+					defer sdw.tableStatusList.threadDone(tableIndex)
+					report, err := checksumDiffTable(ctx, sdw.wr, &sdw.StatusWorker, sdw.sourceAlias, sdw.destinationAlias, tableDefinition, joinConditions(sourceWhere, sdw.rowFilter(tableDefinition)), joinConditions(destinationWhere, sdw.rowFilter(tableDefinition)), repairer, results, sdw.comparison, sdw.chunkCount, sdw.minRowsPerChunk)
+					if err != nil {
+						return report, vterrors.Wrap(err, "checksumDiffTable() failed")
+					}
+					return report, nil
+				}
+
+				// Split the table into chunks which are diffed in parallel.
+				chunks, err := generateChunks(ctx, sdw.wr, destinationTablet.Tablet, originalTableDefinition, sdw.chunkCount, sdw.minRowsPerChunk)
+				if err != nil {
+					return DiffReport{}, vterrors.Wrapf(err, "failed to split table %v into chunks", tableDefinition.Name)
+				}
+				sdw.tableStatusList.setThreadCount(tableIndex, len(chunks))
+				return diffChunksInParallel(&sdw.StatusWorker, chunks, func(c chunk) (DiffReport, error) {
+					sdw.tableStatusList.threadStarted(tableIndex)
+					defer sdw.tableStatusList.threadDone(tableIndex)
+					return sdw.diffChunk(ctx, tableIndex, tableDefinition, c, overlap, keyspaceSchema, repairer, results)
+				})
+			})
+			sdw.writeDiffReport(ctx, rec, tableDefinition.Name, report, err)
 			if err != nil {
-				newErr := vterrors.Wrap(err, "NewRowDiffer() failed")
-				sdw.markAsWillFail(rec, newErr)
-				sdw.wr.Logger().Errorf("%v", newErr)
+				sdw.tableStatusList.tableFailed(tableIndex, err)
+				sdw.markAsWillFail(rec, err)
+				sdw.wr.Logger().Errorf("%v", err)
 				return
 			}
-
-			// And run the diff.
-			report, err := differ.Go(sdw.wr.Logger())
-			if err != nil {
-				newErr := fmt.Errorf("Differ.Go failed: %v", err.Error())
-				sdw.markAsWillFail(rec, newErr)
-				sdw.wr.Logger().Errorf("%v", newErr)
+			if report.HasDifferences() {
+				sdw.tableStatusList.setDifferentRows(tableIndex, report)
+				sdw.handleDifferences(ctx, rec, tableIndex, tableDefinition, report, repairer)
+			} else if tableChecksumOnly {
+				sdw.wr.Logger().Infof("Table %v checks out (%v rows compared row by row after a checksum mismatch)", tableDefinition.Name, report.processedRows)
 			} else {
-				if report.HasDifferences() {
-					err := fmt.Errorf("Table %v has differences: %v", tableDefinition.Name, report.String())
-					sdw.markAsWillFail(rec, err)
-					sdw.wr.Logger().Warningf(err.Error())
-				} else {
-					sdw.wr.Logger().Infof("Table %v checks out (%v rows processed, %v qps)", tableDefinition.Name, report.processedRows, report.processingQPS)
-				}
+				sdw.wr.Logger().Infof("Table %v checks out (%v rows processed, %v qps)", tableDefinition.Name, report.processedRows, report.processingQPS)
 			}
 		}()
 	}
@@ -558,6 +805,133 @@ func (sdw *SplitDiffWorker) diff(ctx context.Context) error {
 	return rec.Error()
 }
 
+// diffChunk runs a row by row comparison of chunk "c" of table "td".
+func (sdw *SplitDiffWorker) diffChunk(ctx context.Context, tableIndex int, td *tabletmanagerdatapb.TableDefinition, c chunk, overlap *topodatapb.KeyRange, keyspaceSchema *vindexes.KeyspaceSchema, repairer *rowRepairer, results *diffResultsRecorder) (DiffReport, error) {
+	if err := sdw.waitIfPaused(ctx); err != nil {
+		return DiffReport{}, err
+	}
+
+	// On the source, see if we need a full scan
+	// or a filtered scan.
+	sourceQueryResultReader, err := sdw.tableScan(ctx, sdw.sourceAlias, sdw.sourceSnapshot, sdw.sourceShard.KeyRange, overlap, td, c, keyspaceSchema)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "TableScan(ByKeyRange?)(source) failed")
+	}
+	defer sourceQueryResultReader.Close(ctx)
+
+	// On the destination, see if we need a full scan
+	// or a filtered scan.
+	destinationQueryResultReader, err := sdw.tableScan(ctx, sdw.destinationAlias, sdw.destinationSnapshot, sdw.shardInfo.KeyRange, overlap, td, c, keyspaceSchema)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "TableScan(ByKeyRange?)(destination) failed")
+	}
+	defer destinationQueryResultReader.Close(ctx)
+
+	// Create the row differ.
+	differ, err := NewRowDiffer(
+		newPausableResultReader(ctx, &sdw.StatusWorker, sourceQueryResultReader),
+		newPausableResultReader(ctx, &sdw.StatusWorker, destinationQueryResultReader),
+		td)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "NewRowDiffer() failed")
+	}
+	differ.repairer = repairer
+	differ.results = results
+	differ.tableStatusList = sdw.tableStatusList
+	differ.tableIndex = tableIndex
+	differ.floatEpsilon = sdw.comparison.floatEpsilon
+
+	// And run the diff.
+	report, err := differ.Go(sdw.wr.Logger())
+	if err != nil {
+		return report, fmt.Errorf("Differ.Go failed: %v", err.Error())
+	}
+	return report, nil
+}
+
+// rowFilter returns the condition which selects the rows of "td" which are
+// compared. It combines --where and --sample_percent.
+func (sdw *SplitDiffWorker) rowFilter(td *tabletmanagerdatapb.TableDefinition) string {
+	return joinConditions(sdw.where, sampleCondition(td, sdw.samplePercent))
+}
+
+// tableScan returns a reader for the rows of chunk "c" of "td" within
+// "overlap" on the tablet "alias" of a shard with the key range "keyRange".
+// If "snapshot" is set, the rows are read from it instead of a streaming
+// query.
+func (sdw *SplitDiffWorker) tableScan(ctx context.Context, alias *topodatapb.TabletAlias, snapshot *consistentSnapshot, keyRange, overlap *topodatapb.KeyRange, td *tabletmanagerdatapb.TableDefinition, c chunk, keyspaceSchema *vindexes.KeyspaceSchema) (closableResultReader, error) {
+	// If the overlap is a subset, we filter the rows. In v2 mode, MySQL
+	// filters them. In v3 mode, vtworker filters them.
+	var where string
+	var filterKeyRange *topodatapb.KeyRange
+	if !key.KeyRangeEqual(overlap, keyRange) {
+		if keyspaceSchema != nil {
+			filterKeyRange = overlap
+		} else {
+			var err error
+			if where, err = keyRangeWhereClause(overlap, sdw.keyspaceInfo.ShardingColumnName, sdw.keyspaceInfo.ShardingColumnType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if filterKeyRange == nil {
+		keyspaceSchema = nil
+	}
+	where = joinConditions(where, sdw.rowFilter(td))
+
+	if snapshot != nil {
+		conditions := chunkWhereClauses(td, c)
+		if where != "" {
+			conditions = append(conditions, where)
+		}
+		return snapshot.tableScan(ctx, td, strings.Join(conditions, " AND "), filterKeyRange, keyspaceSchema, sdw.comparison)
+	}
+
+	scan, err := tableScanChunk(ctx, sdw.wr, alias, td, c, where, sdw.comparison)
+	if err != nil {
+		return nil, err
+	}
+	if keyspaceSchema != nil {
+		keyResolver, err := newV3ResolverFromColumnList(keyspaceSchema, td.Name, orderedColumns(td))
+		if err != nil {
+			scan.Close(ctx)
+			return nil, vterrors.Wrapf(err, "cannot resolve v3 sharding keys for table %v", td.Name)
+		}
+		scan.output = &v3KeyRangeFilter{
+			input:    scan.output,
+			resolver: keyResolver.(*v3Resolver),
+			keyRange: filterKeyRange,
+		}
+	}
+	return scan, nil
+}
+
+// handleDifferences is called for a table with differences. If --repair is
+// set, it tries to repair them. Otherwise, or if the repair fails, the diff
+// will fail.
+func (sdw *SplitDiffWorker) handleDifferences(ctx context.Context, rec concurrency.ErrorRecorder, tableIndex int, td *tabletmanagerdatapb.TableDefinition, report DiffReport, repairer *rowRepairer) {
+	if repairer != nil {
+		err := repairTable(ctx, sdw.wr, sdw.shardInfo.MasterAlias, td.Name, repairer, sdw.repairExecute)
+		if err == nil {
+			sdw.wr.Logger().Warningf("Table %v had differences which were repaired: %v", td.Name, report.String())
+			return
+		}
+		sdw.wr.Logger().Errorf("%v", err)
+	}
+	err := fmt.Errorf("Table %v has differences: %v", td.Name, report.String())
+	sdw.tableStatusList.tableFailed(tableIndex, err)
+	sdw.markAsWillFail(rec, err)
+	sdw.wr.Logger().Warningf("%v", err)
+}
+
+// writeDiffReport saves the machine-readable report of a table, if enabled.
+func (sdw *SplitDiffWorker) writeDiffReport(ctx context.Context, rec concurrency.ErrorRecorder, table string, report DiffReport, diffErr error) {
+	if err := sdw.reportWriter.write(ctx, table, report, diffErr); err != nil {
+		sdw.markAsWillFail(rec, err)
+		sdw.wr.Logger().Errorf("%v", err)
+	}
+}
+
 // markAsWillFail records the error and changes the state of the worker to reflect this
 func (sdw *SplitDiffWorker) markAsWillFail(er concurrency.ErrorRecorder, err error) {
 	er.RecordError(err)
@@ -22,11 +22,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"golang.org/x/net/context"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
 	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
 	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
@@ -45,9 +47,27 @@ type destinationTabletServer struct {
 	t *testing.T
 
 	*fakes.StreamHealthQueryService
+	target        querypb.Target
 	excludedTable string
 }
 
+// StreamHealth sends one healthy record on each stream. Unlike the embedded
+// fake, it supports more than one stream. For REPLICA tablets, the worker
+// opens another one to wait until the tablet is drained.
+func (sq *destinationTabletServer) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	if err := callback(&querypb.StreamHealthResponse{
+		Target:  proto.Clone(&sq.target).(*querypb.Target),
+		Serving: true,
+		RealtimeStats: &querypb.RealtimeStats{
+			SecondsBehindMaster: fakes.DefaultSecondsBehindMaster,
+		},
+	}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
 func (sq *destinationTabletServer) StreamExecute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, options *querypb.ExecuteOptions, callback func(reply *sqltypes.Result) error) error {
 	if strings.Contains(sql, sq.excludedTable) {
 		sq.t.Errorf("Split Diff operation on destination should skip the excluded table: %v query: %v", sq.excludedTable, sql)
@@ -168,7 +188,7 @@ func (sq *sourceTabletServer) StreamExecute(ctx context.Context, target *querypb
 
 // TODO(aaijazi): Create a test in which source and destination data does not match
 
-func testSplitDiff(t *testing.T, v3 bool, destinationTabletType topodatapb.TabletType) {
+func testSplitDiff(t *testing.T, v3 bool, destinationTabletType topodatapb.TabletType, useTables bool) {
 	*useV3ReshardingMode = v3
 	ts := memorytopo.NewServer("cell1", "cell2")
 	ctx := context.Background()
@@ -274,10 +294,10 @@ func testSplitDiff(t *testing.T, v3 bool, destinationTabletType topodatapb.Table
 
 	for _, destRdonly := range []*testlib.FakeTablet{leftRdonly1, leftRdonly2} {
 		qs := fakes.NewStreamHealthQueryService(destRdonly.Target())
-		qs.AddDefaultHealthResponse()
 		grpcqueryservice.Register(destRdonly.RPCServer, &destinationTabletServer{
 			t: t,
 			StreamHealthQueryService: qs,
+			target:                   destRdonly.Target(),
 			excludedTable:            excludedTable,
 		})
 	}
@@ -290,12 +310,14 @@ func testSplitDiff(t *testing.T, v3 bool, destinationTabletType topodatapb.Table
 
 	tabletTypeName, _ := topodatapb.TabletType_name[int32(destinationTabletType)]
 	// Run the vtworker command.
-	args := []string{
-		"SplitDiff",
-		"-exclude_tables", excludedTable,
-		"-dest_tablet_type", tabletTypeName,
-		"ks/-40",
+	args := []string{"SplitDiff"}
+	if useTables {
+		// Only "table1" matches. The excluded table must be skipped as well.
+		args = append(args, "-tables", "/^table/")
+	} else {
+		args = append(args, "-exclude_tables", excludedTable)
 	}
+	args = append(args, "-dest_tablet_type", tabletTypeName, "ks/-40")
 	// We need to use FakeTabletManagerClient because we don't
 	// have a good way to fake the binlog player yet, which is
 	// necessary for synchronizing replication.
@@ -303,16 +325,77 @@ func testSplitDiff(t *testing.T, v3 bool, destinationTabletType topodatapb.Table
 	if err := runCommand(t, wi, wr, args); err != nil {
 		t.Fatal(err)
 	}
+
+	// Only a diff of all tables verifies the destination shard.
+	verification, err := ts.GetShardDiffVerification(ctx, "ks", "-40")
+	if useTables {
+		if !topo.IsErrType(err, topo.NoNode) {
+			t.Errorf("a diff of selected tables must not verify the shard: %v %v", verification, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("GetShardDiffVerification() failed: %v", err)
+	}
+	if verification.Command != "SplitDiff" || verification.Source != "ks/-80" {
+		t.Errorf("wrong diff verification: %+v", verification)
+	}
 }
 
 func TestSplitDiffv2(t *testing.T) {
-	testSplitDiff(t, false, topodatapb.TabletType_RDONLY)
+	testSplitDiff(t, false, topodatapb.TabletType_RDONLY, false /* useTables */)
 }
 
 func TestSplitDiffv3(t *testing.T) {
-	testSplitDiff(t, true, topodatapb.TabletType_RDONLY)
+	testSplitDiff(t, true, topodatapb.TabletType_RDONLY, false /* useTables */)
 }
 
 func TestSplitDiffWithReplica(t *testing.T) {
-	testSplitDiff(t, true, topodatapb.TabletType_REPLICA)
+	testSplitDiff(t, true, topodatapb.TabletType_REPLICA, false /* useTables */)
+}
+
+func TestSplitDiffTables(t *testing.T) {
+	testSplitDiff(t, false, topodatapb.TabletType_RDONLY, true /* useTables */)
+}
+
+func TestSplitDiffInvalidFlags(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	wi := NewInstance(ts, "cell1", time.Second)
+
+	testcases := []struct {
+		flags   []string
+		wantErr string
+	}{
+		{[]string{"-min_healthy_rdonly_tablets", "-1"}, "min_healthy_rdonly_tablets must be >= 0"},
+		{[]string{"-tablet_type", "MASTER"}, "tablet_type must be RDONLY or REPLICA"},
+		{[]string{"-parallel_diffs_count", "0"}, "parallel_diffs_count must be > 0"},
+		{[]string{"-diff_parallelism", "0"}, "parallel_diffs_count must be > 0"},
+		{[]string{"-chunk_count", "0"}, "chunk_count must be > 0"},
+		{[]string{"-min_rows_per_chunk", "0"}, "min_rows_per_chunk must be > 0"},
+		{[]string{"-table_retry_count", "-1"}, "table_retry_count must be >= 0"},
+		{[]string{"-sample_percent", "0"}, "sample_percent must be > 0 and <= 100"},
+		{[]string{"-sample_percent", "101"}, "sample_percent must be > 0 and <= 100"},
+		{[]string{"-repair_execute"}, "repair_execute requires repair"},
+		{[]string{"-repair", "-repair_max_rows", "0"}, "repair_max_rows must be > 0"},
+		{[]string{"-use_consistent_snapshot", "-checksum_only"}, "use_consistent_snapshot cannot be combined with checksum_only"},
+		{[]string{"-use_consistent_snapshot", "-row_count_check"}, "use_consistent_snapshot cannot be combined with row_count_check"},
+		{[]string{"-repair", "-row_count_check"}, "repair cannot be combined with row_count_check"},
+		{[]string{"-hash_columns_larger_than", "-1"}, "hash_columns_larger_than must be >= 0"},
+		{[]string{"-repair", "-hash_columns_larger_than", "1024"}, "repair cannot be combined with hash_columns_larger_than"},
+		{[]string{"-use_consistent_snapshot", "-pkless_tables_use_full_row"}, "use_consistent_snapshot cannot be combined with pkless_tables_use_full_row"},
+		{[]string{"-repair", "-pkless_tables_use_full_row"}, "repair cannot be combined with pkless_tables_use_full_row"},
+		{[]string{"-normalize_charset", "utf8 mb4"}, "normalize_charset must be the name of a character set"},
+		{[]string{"-repair", "-normalize_charset", "utf8mb4"}, "repair cannot be combined with normalize_charset"},
+		{[]string{"-float_epsilon", "-0.1"}, "float_epsilon must be >= 0"},
+		{[]string{"-repair", "-normalize_timestamps"}, "repair cannot be combined with normalize_timestamps"},
+		{[]string{"-ignore_columns", "t1"}, "invalid ignore_columns entry"},
+		{[]string{"-repair", "-ignore_columns", "t1:c1"}, "repair cannot be combined with ignore_columns"},
+	}
+	for _, tc := range testcases {
+		args := append(append([]string{"SplitDiff"}, tc.flags...), "ks/-40")
+		_, _, err := wi.RunCommand(context.Background(), args, nil, false /* runFromCli */)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("SplitDiff %v: got error %v, want error containing %q", tc.flags, err, tc.wantErr)
+		}
+	}
 }
@@ -17,10 +17,12 @@ limitations under the License.
 package worker
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
 	"strings"
+	"time"
 
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/vt/servenv"
@@ -60,6 +62,12 @@ const workerStatusHTML = `
   {{if .Done}}
   <p><a href="/reset">Reset Job</a></p>
   {{else}}
+  {{if .Paused}}
+  <p>This worker is paused.</p>
+  <p><a href="/resume">Resume Job</a></p>
+  {{else}}
+  <p><a href="/pause">Pause Job</a></p>
+  {{end}}
   <p><a href="/cancel">Cancel Job</a></p>
   {{end}}
 {{else}}
@@ -70,7 +78,58 @@ const workerStatusHTML = `
 </html>
 `
 
-// InitStatusHandling installs webserver handlers for global actions like /status, /reset and /cancel.
+// progressReporter is implemented by workers which track the progress of
+// each table.
+type progressReporter interface {
+	progress() []tableProgress
+}
+
+// workerStatus is the machine-readable status which is served at
+// /status.json.
+type workerStatus struct {
+	// Worker is the type of the current worker e.g. "SplitDiffWorker".
+	// It is empty if no worker ran since the last reset.
+	Worker string `json:"worker,omitempty"`
+	State  string `json:"state,omitempty"`
+	Done   bool   `json:"done"`
+	// Paused is true while a running worker is paused.
+	Paused bool `json:"paused,omitempty"`
+	// EndTime is set once the worker has stopped.
+	EndTime *time.Time      `json:"end_time,omitempty"`
+	Tables  []tableProgress `json:"tables,omitempty"`
+	Errors  []string        `json:"errors,omitempty"`
+}
+
+// workerType returns the name of the type of "wrk" e.g. "SplitDiffWorker".
+func workerType(wrk Worker) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", wrk), "*worker.")
+}
+
+// newWorkerStatus returns the status of "wrk". "running" is true while the
+// worker has not returned yet.
+func newWorkerStatus(wrk Worker, running bool, err error, stopTime time.Time) *workerStatus {
+	status := &workerStatus{}
+	if wrk == nil {
+		return status
+	}
+	status.Worker = workerType(wrk)
+	status.State = wrk.State().String()
+	if pr, ok := wrk.(progressReporter); ok {
+		status.Tables = pr.progress()
+	}
+	if running {
+		status.Paused = wrk.IsPaused()
+	} else {
+		status.Done = true
+		status.EndTime = &stopTime
+		if err != nil {
+			status.Errors = []string{err.Error()}
+		}
+	}
+	return status
+}
+
+// InitStatusHandling installs webserver handlers for global actions like /status, /status.json, /reset, /cancel, /pause and /resume.
 func (wi *Instance) InitStatusHandling() {
 	// code to serve /status
 	workerTemplate := mustParseTemplate("worker", workerStatusHTML)
@@ -97,6 +156,8 @@ func (wi *Instance) InitStatusHandling() {
 					status += template.HTML(fmt.Sprintf("<br>\nEnded with an error: %v<br>\n", err))
 				}
 				status += template.HTML(fmt.Sprintf("<br>\n<b>End Time:</b> %v<br>\n", stopTime))
+			} else {
+				data["Paused"] = wrk.IsPaused()
 			}
 			data["Status"] = status
 			if logger != nil {
@@ -108,6 +169,26 @@ func (wi *Instance) InitStatusHandling() {
 		executeTemplate(w, workerTemplate, data)
 	})
 
+	// code to serve /status.json
+	http.HandleFunc("/status.json", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		wi.currentWorkerMutex.Lock()
+		status := newWorkerStatus(wi.currentWorker, wi.currentContext != nil, wi.lastRunError, wi.lastRunStopTime)
+		wi.currentWorkerMutex.Unlock()
+
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			httpError(w, "cannot marshal status: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+
 	// add the section in status that does auto-refresh of status div
 	servenv.AddStatusPart("Worker Status", workerStatusPartHTML, func() interface{} {
 		return nil
@@ -143,4 +224,26 @@ func (wi *Instance) InitStatusHandling() {
 			http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
 		}
 	})
+
+	// pause handler
+	http.HandleFunc("/pause", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		wi.Pause()
+		http.Redirect(w, r, servenv.StatusURLPath(), http.StatusTemporaryRedirect)
+	})
+
+	// resume handler
+	http.HandleFunc("/resume", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.ADMIN); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+
+		wi.Resume()
+		http.Redirect(w, r, servenv.StatusURLPath(), http.StatusTemporaryRedirect)
+	})
 }
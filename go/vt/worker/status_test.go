@@ -0,0 +1,59 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+func TestNewWorkerStatus(t *testing.T) {
+	if got, want := newWorkerStatus(nil, false, nil, time.Time{}), (&workerStatus{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("newWorkerStatus() without a worker = %+v, want = %+v", got, want)
+	}
+
+	wrk, err := NewPingWorker(wrangler.New(logutil.NewConsoleLogger(), nil, nil), "pong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := newWorkerStatus(wrk, true /* running */, nil, time.Time{})
+	want := &workerStatus{
+		Worker: "PingWorker",
+		State:  WorkerStateNotStarted.String(),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newWorkerStatus() for a running worker = %+v, want = %+v", got, want)
+	}
+
+	stopTime := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	got = newWorkerStatus(wrk, false /* running */, errors.New("ping failed"), stopTime)
+	want = &workerStatus{
+		Worker:  "PingWorker",
+		State:   WorkerStateNotStarted.String(),
+		Done:    true,
+		EndTime: &stopTime,
+		Errors:  []string{"ping failed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newWorkerStatus() for a stopped worker = %+v, want = %+v", got, want)
+	}
+}
@@ -17,8 +17,15 @@ limitations under the License.
 package worker
 
 import (
+	"fmt"
 	"html/template"
 	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/tb"
+	"vitess.io/vitess/go/vt/log"
 )
 
 // StatusWorkerState is the type for a StatusWorker's status
@@ -46,6 +53,9 @@ const (
 	WorkerStateCloneOnline StatusWorkerState = "cloning the data (online)"
 	// WorkerStateCloneOffline is set when the worker copies the data in the offline phase.
 	WorkerStateCloneOffline StatusWorkerState = "cloning the data (offline)"
+	// WorkerStateCatchUp is set when the worker waits for filtered replication
+	// to catch up after an online clone (see SplitClone --catch_up).
+	WorkerStateCatchUp StatusWorkerState = "catching up with filtered replication"
 
 	// WorkerStateDiff is set when the worker compares the data.
 	WorkerStateDiff StatusWorkerState = "running the diff"
@@ -59,6 +69,11 @@ const (
 	// WorkerStateCleanUp is set when the worker reverses the initialization e.g.
 	// the type of a taken out RDONLY tablet is changed back from "worker" to "spare".
 	WorkerStateCleanUp StatusWorkerState = "cleaning up"
+
+	// WorkerStateWaitForServing is set when the worker waits for the tablets,
+	// which it used and returned during the clean up, to serve again
+	// (see --wait_for_serving_tablets_timeout).
+	WorkerStateWaitForServing StatusWorkerState = "waiting for the used tablets to serve again"
 )
 
 func (state StatusWorkerState) String() string {
@@ -74,6 +89,15 @@ type StatusWorker struct {
 	mu *sync.Mutex
 	// state contains the worker's current state. Guarded by mu.
 	state StatusWorkerState
+	// jobID is the id of the job if the worker was submitted to the
+	// jobManager and 0 otherwise. Guarded by mu.
+	jobID int
+	// resumed is non-nil while the worker is paused. Resume() closes it to
+	// wake up all waiters in waitIfPaused(). Guarded by mu.
+	resumed chan struct{}
+	// panicStack is the stack trace of the first panic which was recovered by
+	// recoverPanic(). Guarded by mu.
+	panicStack string
 }
 
 // NewStatusWorker returns a StatusWorker in state WorkerStateNotStarted.
@@ -89,8 +113,50 @@ func (w *StatusWorker) SetState(state StatusWorkerState) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	old := w.state
 	w.state = state
+	if w.jobID != 0 {
+		return
+	}
+	statsStateActive.Set(string(old), 0)
 	statsState.Set(string(state))
+	statsStateActive.Set(string(state), 1)
+	statsStateTransitions.Add(string(state), 1)
+}
+
+// setJobID marks the worker as the job "id" of the jobManager.
+// Jobs run concurrently. Therefore, they neither publish their state nor
+// reset the process-wide stats which describe the worker of the Instance.
+// The state of each job is exported by the jobManager instead.
+func (w *StatusWorker) setJobID(id int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.jobID = id
+}
+
+// resetRunVars calls resetVars() unless the worker runs as a job.
+// Workers call it at the beginning of Run().
+func (w *StatusWorker) resetRunVars() {
+	w.mu.Lock()
+	isJob := w.jobID != 0
+	w.mu.Unlock()
+
+	if !isJob {
+		resetVars()
+	}
+}
+
+// setStateDuration records how much time the worker spent in "state" unless
+// the worker runs as a job.
+func (w *StatusWorker) setStateDuration(state StatusWorkerState, d time.Duration) {
+	w.mu.Lock()
+	isJob := w.jobID != 0
+	w.mu.Unlock()
+
+	if !isJob {
+		statsStateDurationsNs.Set(string(state), d.Nanoseconds())
+	}
 }
 
 // State is part of the Worker interface.
@@ -106,7 +172,7 @@ func (w *StatusWorker) StatusAsHTML() template.HTML {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	return template.HTML("<b>State:</b> " + w.state.String() + "</br>\n")
+	return template.HTML("<b>State:</b> " + w.state.String() + "</br>\n" + w.formatPanicHTMLLocked())
 }
 
 // StatusAsText is part of the Worker interface.
@@ -114,5 +180,122 @@ func (w *StatusWorker) StatusAsText() string {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	return "State: " + w.state.String() + "\n"
+	return "State: " + w.state.String() + "\n" + w.formatPanicTextLocked()
+}
+
+// Pause is part of the Worker interface.
+func (w *StatusWorker) Pause() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.resumed != nil {
+		return false
+	}
+	w.resumed = make(chan struct{})
+	return true
+}
+
+// Resume is part of the Worker interface.
+func (w *StatusWorker) Resume() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.resumed == nil {
+		return false
+	}
+	close(w.resumed)
+	w.resumed = nil
+	return true
+}
+
+// IsPaused is part of the Worker interface.
+func (w *StatusWorker) IsPaused() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.resumed != nil
+}
+
+// waitIfPaused blocks while the worker is paused. Workers call it before they
+// issue a query. It returns the context error if the context is done before
+// the worker was resumed.
+func (w *StatusWorker) waitIfPaused(ctx context.Context) error {
+	w.mu.Lock()
+	resumed := w.resumed
+	w.mu.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recoverPanic must be deferred at the start of each Go routine of a worker
+// which processes rows. It recovers from a panic and passes it as error to
+// "record". This way, the worker fails as it would for any other error and
+// still runs its cleaner instead of crashing vtworker. The stack trace of the
+// first panic is shown by the StatusAs* methods.
+func (w *StatusWorker) recoverPanic(record func(error)) {
+	x := recover()
+	if x == nil {
+		return
+	}
+	// The recovery code is similar to servenv.HandlePanic().
+	stack := string(tb.Stack(4))
+	log.Errorf("uncaught vtworker panic: %v\n%s", x, stack)
+
+	w.mu.Lock()
+	if w.panicStack == "" {
+		w.panicStack = stack
+	}
+	w.mu.Unlock()
+
+	record(fmt.Errorf("uncaught vtworker panic: %v", x))
+}
+
+// runRecovered calls "run" and returns a panic in it as error.
+// Run() of each worker uses it to call run() such that the cleanup after it
+// is always executed. See recoverPanic().
+func (w *StatusWorker) runRecovered(ctx context.Context, run func(context.Context) error) (err error) {
+	defer w.recoverPanic(func(panicErr error) {
+		err = panicErr
+	})
+	return run(ctx)
+}
+
+// formatPanicHTML returns the stack trace of a recovered panic for
+// StatusAsHTML(). It is empty if there was no panic.
+func (w *StatusWorker) formatPanicHTML() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.formatPanicHTMLLocked()
+}
+
+func (w *StatusWorker) formatPanicHTMLLocked() string {
+	if w.panicStack == "" {
+		return ""
+	}
+	return "<b>Panic:</b></br>\n<pre>" + template.HTMLEscapeString(w.panicStack) + "</pre>\n"
+}
+
+// formatPanicText returns the stack trace of a recovered panic for
+// StatusAsText(). It is empty if there was no panic.
+func (w *StatusWorker) formatPanicText() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.formatPanicTextLocked()
+}
+
+func (w *StatusWorker) formatPanicTextLocked() string {
+	if w.panicStack == "" {
+		return ""
+	}
+	return "Panic:\n" + w.panicStack
 }
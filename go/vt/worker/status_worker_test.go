@@ -0,0 +1,87 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestStatusWorkerPause(t *testing.T) {
+	ctx := context.Background()
+	w := NewStatusWorker()
+	if err := w.waitIfPaused(ctx); err != nil {
+		t.Fatalf("waitIfPaused() of a running worker failed: %v", err)
+	}
+	if w.Resume() {
+		t.Errorf("Resume() of a running worker = true, want = false")
+	}
+
+	if !w.Pause() {
+		t.Fatalf("Pause() = false, want = true")
+	}
+	if w.Pause() {
+		t.Errorf("Pause() of a paused worker = true, want = false")
+	}
+	if !w.IsPaused() {
+		t.Errorf("IsPaused() = false, want = true")
+	}
+
+	resumed := make(chan error)
+	go func() {
+		resumed <- w.waitIfPaused(ctx)
+	}()
+	select {
+	case err := <-resumed:
+		t.Fatalf("waitIfPaused() returned while the worker is paused: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if !w.Resume() {
+		t.Fatalf("Resume() = false, want = true")
+	}
+	if err := <-resumed; err != nil {
+		t.Errorf("waitIfPaused() after Resume() failed: %v", err)
+	}
+
+	// A canceled context aborts the wait.
+	w.Pause()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := w.waitIfPaused(cancelCtx); err != context.Canceled {
+		t.Errorf("waitIfPaused() with a canceled context = %v, want = %v", err, context.Canceled)
+	}
+}
+
+func TestStatusWorkerStateStats(t *testing.T) {
+	resetVars()
+	w := NewStatusWorker()
+	w.SetState(WorkerStateInit)
+	w.SetState(WorkerStateFindTargets)
+
+	active := statsStateActive.Counts()
+	if got, want := active[string(WorkerStateInit)], int64(0); got != want {
+		t.Errorf("WorkerStateActive[%v] = %v, want = %v", WorkerStateInit, got, want)
+	}
+	if got, want := active[string(WorkerStateFindTargets)], int64(1); got != want {
+		t.Errorf("WorkerStateActive[%v] = %v, want = %v", WorkerStateFindTargets, got, want)
+	}
+	if got, want := statsStateTransitions.Counts()[string(WorkerStateInit)], int64(1); got != want {
+		t.Errorf("WorkerStateTransitions[%v] = %v, want = %v", WorkerStateInit, got, want)
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	"golang.org/x/net/context"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+const tableMigrateHTML = `
+<!DOCTYPE html>
+<head>
+  <title>Table Migrate Action</title>
+</head>
+<body>
+  <h1>Table Migrate Action</h1>
+    <form action="/Clones/TableMigrate" method="post">
+      <LABEL for="source">Source Keyspace/Shard: </LABEL>
+        <INPUT type="text" id="source" name="source" value=""></BR>
+      <LABEL for="destination">Destination Keyspace/Shard: </LABEL>
+        <INPUT type="text" id="destination" name="destination" value=""></BR>
+      <LABEL for="tables">Tables: </LABEL>
+        <INPUT type="text" id="tables" name="tables" value=""></BR>
+      <LABEL for="online">Do Online Copy: (optional approximate copy, source and destination tablets will not be put out of serving, minimizes downtime during offline copy)</LABEL>
+        <INPUT type="checkbox" id="online" name="online" value="true"{{if .DefaultOnline}} checked{{end}}></BR>
+      <LABEL for="offline">Do Offline Copy: (exact copy at a specific GTID, a source RDONLY tablet will be put out of serving during copy)</LABEL>
+        <INPUT type="checkbox" id="offline" name="offline" value="true"{{if .DefaultOffline}} checked{{end}}></BR>
+      <LABEL for="resume">Resume Online Copy: (continue the online copy from the checkpoint of a previous run, requires the same chunk parameters)</LABEL>
+        <INPUT type="checkbox" id="resume" name="resume" value="true"{{if .DefaultResume}} checked{{end}}></BR>
+      <LABEL for="verify">Verify: (compare the copied tables between the source and the destination after the offline copy, requires Do Offline Copy)</LABEL>
+        <INPUT type="checkbox" id="verify" name="verify" value="true"{{if .DefaultVerify}} checked{{end}}></BR>
+      <LABEL for="chunkCount">Chunk Count: </LABEL>
+        <INPUT type="text" id="chunkCount" name="chunkCount" value="{{.DefaultChunkCount}}"></BR>
+      <LABEL for="minRowsPerChunk">Minimun Number of Rows per Chunk (may reduce the Chunk Count): </LABEL>
+        <INPUT type="text" id="minRowsPerChunk" name="minRowsPerChunk" value="{{.DefaultMinRowsPerChunk}}"></BR>
+      <LABEL for="sourceReaderCount">Source Reader Count: </LABEL>
+        <INPUT type="text" id="sourceReaderCount" name="sourceReaderCount" value="{{.DefaultSourceReaderCount}}"></BR>
+      <LABEL for="writeQueryMaxRows">Maximum Number of Rows per Write Query: </LABEL>
+        <INPUT type="text" id="writeQueryMaxRows" name="writeQueryMaxRows" value="{{.DefaultWriteQueryMaxRows}}"></BR>
+      <LABEL for="writeQueryMaxSize">Maximum Size (in bytes) per Write Query: </LABEL>
+        <INPUT type="text" id="writeQueryMaxSize" name="writeQueryMaxSize" value="{{.DefaultWriteQueryMaxSize}}"></BR>
+      <LABEL for="writeTransactionMaxRows">Maximum Number of Rows per Write Transaction (0 means one write query per transaction): </LABEL>
+        <INPUT type="text" id="writeTransactionMaxRows" name="writeTransactionMaxRows" value="{{.DefaultWriteTransactionMaxRows}}"></BR>
+      <LABEL for="writeTransactionMaxSize">Maximum Size (in bytes) per Write Transaction: </LABEL>
+        <INPUT type="text" id="writeTransactionMaxSize" name="writeTransactionMaxSize" value="{{.DefaultWriteTransactionMaxSize}}"></BR>
+      <LABEL for="destinationWriterCount">Destination Writer Count: </LABEL>
+        <INPUT type="text" id="destinationWriterCount" name="destinationWriterCount" value="{{.DefaultDestinationWriterCount}}"></BR>
+      <LABEL for="destinationQueueSize">Destination Queue Size (0 = 2 * Destination Writer Count): </LABEL>
+        <INPUT type="text" id="destinationQueueSize" name="destinationQueueSize" value="{{.DefaultDestinationQueueSize}}"></BR>
+      <LABEL for="minHealthyRdonlyTablets">Minimum Number of required healthy RDONLY tablets: </LABEL>
+        <INPUT type="text" id="minHealthyRdonlyTablets" name="minHealthyRdonlyTablets" value="{{.DefaultMinHealthyRdonlyTablets}}"></BR>
+      <LABEL for="maxTPS">Maximum Write Transactions/second (If non-zero, writes on the destination will be throttled. Unlimited by default.): </LABEL>
+        <INPUT type="text" id="maxTPS" name="maxTPS" value="{{.DefaultMaxTPS}}"></BR>
+      <LABEL for="maxReplicationLag">Maximum Replication Lag Seconds (enables the adapative throttler. Disabled by default.): </LABEL>
+        <INPUT type="text" id="maxReplicationLag" name="maxReplicationLag" value="{{.DefaultMaxReplicationLag}}"></BR>
+      <LABEL for="maxWriteMBPerSecond">Maximum Write Bandwidth in MB/second (If non-zero, the writes to the destination shard will be throttled. Unlimited by default.): </LABEL>
+        <INPUT type="text" id="maxWriteMBPerSecond" name="maxWriteMBPerSecond" value="{{.DefaultMaxWriteMBPerSecond}}"></BR>
+      <LABEL for="compression">Compression of the Writes (snappy or gzip. Uses --grpc_compression if empty.): </LABEL>
+        <INPUT type="text" id="compression" name="compression" value="{{.DefaultCompression}}"></BR>
+      <INPUT type="submit" value="Migrate"/>
+    </form>
+  </body>
+`
+
+var tableMigrateTemplate = mustParseTemplate("tableMigrate", tableMigrateHTML)
+
+func commandTableMigrate(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) (Worker, error) {
+	online := subFlags.Bool("online", defaultOnline, "do online copy (optional approximate copy, source and destination tablets will not be put out of serving, minimizes downtime during offline copy)")
+	offline := subFlags.Bool("offline", defaultOffline, "do offline copy (exact copy at a specific GTID, a source RDONLY tablet will be put out of serving during copy)")
+	resume := subFlags.Bool("resume", defaultResume, "resume the online copy from the checkpoint of a previous run which was interrupted (requires the same --chunk_count and --min_rows_per_chunk)")
+	verify := subFlags.Bool("verify", defaultVerify, "after the offline copy, compare the copied tables between the source RDONLY tablet and the destination master and fail if they differ (requires --offline)")
+	tables := subFlags.String("tables", "", "comma separated list of tables to copy. Each is either an exact match, or a regular expression of the form /regexp/")
+	chunkCount := subFlags.Int("chunk_count", defaultChunkCount, "number of chunks per table")
+	minRowsPerChunk := subFlags.Int("min_rows_per_chunk", defaultMinRowsPerChunk, "minimum number of rows per chunk (may reduce --chunk_count)")
+	sourceReaderCount := subFlags.Int("source_reader_count", defaultSourceReaderCount, "number of concurrent streaming queries to use on the source")
+	writeQueryMaxRows := subFlags.Int("write_query_max_rows", defaultWriteQueryMaxRows, "maximum number of rows per write query")
+	writeQueryMaxSize := subFlags.Int("write_query_max_size", defaultWriteQueryMaxSize, "maximum size (in bytes) per write query")
+	writeTransactionMaxRows := subFlags.Int("write_transaction_max_rows", defaultWriteTransactionMaxRows, "maximum number of rows per write transaction. If > 0, a writer thread merges the INSERT queries of the same table which are already queued into one transaction. 0 means one transaction per write query")
+	writeTransactionMaxSize := subFlags.Int("write_transaction_max_size", defaultWriteTransactionMaxSize, "maximum size (in bytes) per write transaction. Must be >= -write_query_max_size")
+	destinationWriterCount := subFlags.Int("destination_writer_count", defaultDestinationWriterCount, "number of concurrent RPCs to execute on the destination")
+	destinationQueueSize := subFlags.Int("destination_queue_size", defaultDestinationQueueSize, "number of write queries which are buffered for each destination shard. A larger queue prevents that a temporarily slow destination blocks the copy to all other destinations. 0 means 2 * --destination_writer_count")
+	minHealthyRdonlyTablets := subFlags.Int("min_healthy_rdonly_tablets", defaultMinHealthyRdonlyTablets, "minimum number of healthy RDONLY tablets before taking out one")
+	maxTPS := subFlags.Int64("max_tps", defaultMaxTPS, "if non-zero, limit copy to maximum number of (write) transactions/second on the destination (unlimited by default)")
+	maxReplicationLag := subFlags.Int64("max_replication_lag", defaultMaxReplicationLag, "if set, the adapative throttler will be enabled and automatically adjust the write rate to keep the lag below the set value in seconds (disabled by default)")
+	maxWriteMBPerSecond := subFlags.Int("max_write_mb_per_second", defaultMaxWriteMBPerSecond, "if non-zero, limit the bandwidth of the writes to the destination shard to this many MB/second (unlimited by default)")
+	compression := subFlags.String("compression", defaultCompression, "gRPC compression of the writes to the destination shard: snappy or gzip (uses --grpc_compression by default)")
+	sourceTabletAlias := subFlags.String("source_tablet_alias", "", "source tablet to use for the offline copy instead of a random healthy RDONLY tablet")
+	if err := subFlags.Parse(args); err != nil {
+		return nil, err
+	}
+	if subFlags.NArg() != 2 {
+		subFlags.Usage()
+		return nil, fmt.Errorf("command TableMigrate requires <source keyspace/shard> <destination keyspace/shard>")
+	}
+
+	sourceKeyspace, sourceShard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(subFlags.Arg(1))
+	if err != nil {
+		return nil, err
+	}
+	// The tableArray has zero elements when table flag is empty, which is an
+	// error case captured in function newTableMigrateWorker.
+	tableArray := []string{}
+	if *tables != "" {
+		tableArray = strings.Split(*tables, ",")
+	}
+	sourceTabletAliasArray, err := parseTabletAliases(*sourceTabletAlias)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot parse source_tablet_alias")
+	}
+	worker, err := newTableMigrateWorker(wr, wi.cell, sourceKeyspace, sourceShard, keyspace, shard, *online, *offline, *resume, *verify, tableArray, *chunkCount, *minRowsPerChunk, *sourceReaderCount, *writeQueryMaxRows, *writeQueryMaxSize, *writeTransactionMaxRows, *writeTransactionMaxSize, *destinationWriterCount, *destinationQueueSize, *minHealthyRdonlyTablets, *maxTPS, *maxReplicationLag, *maxWriteMBPerSecond, *compression, sourceTabletAliasArray)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create worker")
+	}
+	return worker, nil
+}
+
+func interactiveTableMigrate(ctx context.Context, wi *Instance, wr *wrangler.Wrangler, w http.ResponseWriter, r *http.Request) (Worker, *template.Template, map[string]interface{}, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse form")
+	}
+
+	source := r.FormValue("source")
+	if source == "" {
+		// display the input form
+		result := make(map[string]interface{})
+		result["DefaultOnline"] = defaultOnline
+		result["DefaultOffline"] = defaultOffline
+		result["DefaultResume"] = defaultResume
+		result["DefaultVerify"] = defaultVerify
+		result["DefaultChunkCount"] = fmt.Sprintf("%v", defaultChunkCount)
+		result["DefaultMinRowsPerChunk"] = fmt.Sprintf("%v", defaultMinRowsPerChunk)
+		result["DefaultSourceReaderCount"] = fmt.Sprintf("%v", defaultSourceReaderCount)
+		result["DefaultWriteQueryMaxRows"] = fmt.Sprintf("%v", defaultWriteQueryMaxRows)
+		result["DefaultWriteQueryMaxSize"] = fmt.Sprintf("%v", defaultWriteQueryMaxSize)
+		result["DefaultWriteTransactionMaxRows"] = fmt.Sprintf("%v", defaultWriteTransactionMaxRows)
+		result["DefaultWriteTransactionMaxSize"] = fmt.Sprintf("%v", defaultWriteTransactionMaxSize)
+		result["DefaultDestinationWriterCount"] = fmt.Sprintf("%v", defaultDestinationWriterCount)
+		result["DefaultDestinationQueueSize"] = fmt.Sprintf("%v", defaultDestinationQueueSize)
+		result["DefaultMinHealthyRdonlyTablets"] = fmt.Sprintf("%v", defaultMinHealthyRdonlyTablets)
+		result["DefaultMaxTPS"] = fmt.Sprintf("%v", defaultMaxTPS)
+		result["DefaultMaxReplicationLag"] = fmt.Sprintf("%v", defaultMaxReplicationLag)
+		result["DefaultMaxWriteMBPerSecond"] = fmt.Sprintf("%v", defaultMaxWriteMBPerSecond)
+		result["DefaultCompression"] = defaultCompression
+		return nil, tableMigrateTemplate, result, nil
+	}
+	sourceKeyspace, sourceShard, err := topoproto.ParseKeyspaceShard(source)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse source")
+	}
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(r.FormValue("destination"))
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse destination")
+	}
+	tableArray := []string{}
+	if tables := r.FormValue("tables"); tables != "" {
+		tableArray = strings.Split(tables, ",")
+	}
+
+	// get other parameters
+	onlineStr := r.FormValue("online")
+	online := onlineStr == "true"
+	offlineStr := r.FormValue("offline")
+	offline := offlineStr == "true"
+	resumeStr := r.FormValue("resume")
+	resume := resumeStr == "true"
+	verifyStr := r.FormValue("verify")
+	verify := verifyStr == "true"
+	chunkCountStr := r.FormValue("chunkCount")
+	chunkCount, err := strconv.ParseInt(chunkCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse chunkCount")
+	}
+	minRowsPerChunkStr := r.FormValue("minRowsPerChunk")
+	minRowsPerChunk, err := strconv.ParseInt(minRowsPerChunkStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse minRowsPerChunk")
+	}
+	sourceReaderCountStr := r.FormValue("sourceReaderCount")
+	sourceReaderCount, err := strconv.ParseInt(sourceReaderCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse sourceReaderCount")
+	}
+	writeQueryMaxRowsStr := r.FormValue("writeQueryMaxRows")
+	writeQueryMaxRows, err := strconv.ParseInt(writeQueryMaxRowsStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeQueryMaxRows")
+	}
+	writeQueryMaxSizeStr := r.FormValue("writeQueryMaxSize")
+	writeQueryMaxSize, err := strconv.ParseInt(writeQueryMaxSizeStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeQueryMaxSize")
+	}
+	writeTransactionMaxRowsStr := r.FormValue("writeTransactionMaxRows")
+	writeTransactionMaxRows, err := strconv.ParseInt(writeTransactionMaxRowsStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeTransactionMaxRows")
+	}
+	writeTransactionMaxSizeStr := r.FormValue("writeTransactionMaxSize")
+	writeTransactionMaxSize, err := strconv.ParseInt(writeTransactionMaxSizeStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse writeTransactionMaxSize")
+	}
+	destinationWriterCountStr := r.FormValue("destinationWriterCount")
+	destinationWriterCount, err := strconv.ParseInt(destinationWriterCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse destinationWriterCount")
+	}
+	destinationQueueSizeStr := r.FormValue("destinationQueueSize")
+	destinationQueueSize, err := strconv.ParseInt(destinationQueueSizeStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse destinationQueueSize")
+	}
+	minHealthyRdonlyTabletsStr := r.FormValue("minHealthyRdonlyTablets")
+	minHealthyRdonlyTablets, err := strconv.ParseInt(minHealthyRdonlyTabletsStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse minHealthyRdonlyTablets")
+	}
+	maxTPSStr := r.FormValue("maxTPS")
+	maxTPS, err := strconv.ParseInt(maxTPSStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse maxTPS")
+	}
+	maxReplicationLagStr := r.FormValue("maxReplicationLag")
+	maxReplicationLag, err := strconv.ParseInt(maxReplicationLagStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse maxReplicationLag")
+	}
+	maxWriteMBPerSecondStr := r.FormValue("maxWriteMBPerSecond")
+	maxWriteMBPerSecond, err := strconv.ParseInt(maxWriteMBPerSecondStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse maxWriteMBPerSecond")
+	}
+	compression := r.FormValue("compression")
+
+	// start the migration job
+	wrk, err := newTableMigrateWorker(wr, wi.cell, sourceKeyspace, sourceShard, keyspace, shard, online, offline, resume, verify, tableArray, int(chunkCount), int(minRowsPerChunk), int(sourceReaderCount), int(writeQueryMaxRows), int(writeQueryMaxSize), int(writeTransactionMaxRows), int(writeTransactionMaxSize), int(destinationWriterCount), int(destinationQueueSize), int(minHealthyRdonlyTablets), maxTPS, maxReplicationLag, int(maxWriteMBPerSecond), compression, nil /* sourceTabletAliases */)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot create worker")
+	}
+	return wrk, nil, nil, nil
+}
+
+func init() {
+	AddCommand("Clones", Command{"TableMigrate",
+		commandTableMigrate, interactiveTableMigrate,
+		"--tables=<tables> <source keyspace/shard> <destination keyspace/shard>",
+		"Copies a list of tables from one shard to another shard of any keyspace. Unlike VerticalSplitClone, it neither requires KeyspaceServedFrom nor sets up filtered replication."})
+}
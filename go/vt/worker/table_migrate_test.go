@@ -0,0 +1,173 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/wrangler/testlib"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// tableMigrateTestMin is the minimum value of the primary key.
+	tableMigrateTestMin int = 100
+	// tableMigrateTestMax is the maximum value of the primary key.
+	tableMigrateTestMax int = 200
+)
+
+// TestTableMigrate will run TableMigrate in the combined online and offline
+// mode between two keyspaces which are not part of a vertical split. The
+// online phase will copy 100 rows from the source to the destination and the
+// offline phase won't copy any rows as the source has not changed in the
+// meantime. Unlike VerticalSplitClone, filtered replication must not be set up.
+func TestTableMigrate(t *testing.T) {
+	ts := memorytopo.NewServer("cell1", "cell2")
+	ctx := context.Background()
+	wi := NewInstance(ts, "cell1", time.Second)
+
+	sourceRdonlyFakeDB := sourceRdonlyFakeDB(t, "vt_source_ks", "moving1", tableMigrateTestMin, tableMigrateTestMax)
+
+	sourceMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 0,
+		topodatapb.TabletType_MASTER, nil, testlib.TabletKeyspaceShard(t, "source_ks", "0"))
+	sourceRdonly := testlib.NewFakeTablet(t, wi.wr, "cell1", 1,
+		topodatapb.TabletType_RDONLY, sourceRdonlyFakeDB, testlib.TabletKeyspaceShard(t, "source_ks", "0"))
+
+	// See TestVerticalSplitClone for the number of insert statements.
+	destMasterFakeDb := createVerticalSplitCloneDestinationFakeDb(t, "destMaster", 30)
+	defer destMasterFakeDb.VerifyAllExecutedOrFail()
+
+	destMaster := testlib.NewFakeTablet(t, wi.wr, "cell1", 10,
+		topodatapb.TabletType_MASTER, destMasterFakeDb, testlib.TabletKeyspaceShard(t, "destination_ks", "0"))
+	destRdonly := testlib.NewFakeTablet(t, wi.wr, "cell1", 11,
+		topodatapb.TabletType_RDONLY, nil, testlib.TabletKeyspaceShard(t, "destination_ks", "0"))
+
+	// add the topo and schema data we'll need
+	if err := wi.wr.RebuildKeyspaceGraph(ctx, "source_ks", nil); err != nil {
+		t.Fatalf("RebuildKeyspaceGraph failed: %v", err)
+	}
+	if err := wi.wr.RebuildKeyspaceGraph(ctx, "destination_ks", nil); err != nil {
+		t.Fatalf("RebuildKeyspaceGraph failed: %v", err)
+	}
+
+	// Set up source rdonly which will be used as input for the diff during the clone.
+	sourceRdonly.FakeMysqlDaemon.Schema = &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "",
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:              "moving1",
+				Columns:           []string{"id", "msg"},
+				PrimaryKeyColumns: []string{"id"},
+				Type:              tmutils.TableBaseTable,
+				// Set the row count to avoid that --min_rows_per_chunk reduces the
+				// number of chunks.
+				RowCount: 100,
+			},
+		},
+	}
+	sourceRdonly.FakeMysqlDaemon.CurrentMasterPosition = mysql.Position{
+		GTIDSet: mysql.MariadbGTIDSet{mysql.MariadbGTID{Domain: 12, Server: 34, Sequence: 5678}},
+	}
+	sourceRdonly.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		"STOP SLAVE",
+		"START SLAVE",
+	}
+	sourceRdonlyShqs := fakes.NewStreamHealthQueryService(sourceRdonly.Target())
+	sourceRdonlyShqs.AddDefaultHealthResponse()
+	sourceRdonlyQs := newTestQueryService(t, sourceRdonly.Target(), sourceRdonlyShqs, 0, 1, topoproto.TabletAliasString(sourceRdonly.Tablet.Alias), true /* omitKeyspaceID */)
+	sourceRdonlyQs.addGeneratedRows(tableMigrateTestMin, tableMigrateTestMax)
+	grpcqueryservice.Register(sourceRdonly.RPCServer, sourceRdonlyQs)
+
+	// Set up destination master which will be used as input for the diff during the clone.
+	destMasterShqs := fakes.NewStreamHealthQueryService(destMaster.Target())
+	destMasterShqs.AddDefaultHealthResponse()
+	destMasterQs := newTestQueryService(t, destMaster.Target(), destMasterShqs, 0, 1, topoproto.TabletAliasString(destMaster.Tablet.Alias), true /* omitKeyspaceID */)
+	// This tablet is empty and does not return any rows.
+	grpcqueryservice.Register(destMaster.RPCServer, destMasterQs)
+
+	// Only wait 1 ms between retries, so that the test passes faster
+	*executeFetchRetryTime = (1 * time.Millisecond)
+
+	// When the online clone inserted the last rows, modify the destination test
+	// query service such that it will return them as well.
+	destMasterFakeDb.GetEntry(29).AfterFunc = func() {
+		destMasterQs.addGeneratedRows(tableMigrateTestMin, tableMigrateTestMax)
+	}
+
+	// Start action loop after having registered all RPC services.
+	for _, ft := range []*testlib.FakeTablet{sourceMaster, sourceRdonly, destMaster, destRdonly} {
+		ft.StartActionLoop(t, wi.wr)
+		defer ft.StopActionLoop(t)
+	}
+
+	// Run the vtworker command.
+	args := []string{
+		"TableMigrate",
+		"-max_tps", "9999",
+		"-tables", "/moving/",
+		"-source_reader_count", "10",
+		// Each chunk pipeline will process 10 rows. To spread them out across 3
+		// write queries, set the max row count per query to 4. (10 = 4+4+2)
+		"-write_query_max_rows", "4",
+		"-min_rows_per_chunk", "10",
+		"-destination_writer_count", "10",
+		// This test uses only one healthy RDONLY tablet.
+		"-min_healthy_rdonly_tablets", "1",
+		// The test query service does not support the checksum queries.
+		"-verify=false",
+		"source_ks/0",
+		"destination_ks/0",
+	}
+	if err := runCommand(t, wi, wi.wr, args); err != nil {
+		t.Fatal(err)
+	}
+	if inserts := statsOnlineInsertsCounters.Counts()["moving1"]; inserts != 100 {
+		t.Errorf("wrong number of rows inserted: got = %v, want = %v", inserts, 100)
+	}
+	if inserts := statsOfflineInsertsCounters.Counts()["moving1"]; inserts != 0 {
+		t.Errorf("no stats for the offline clone phase should have been modified. got inserts = %v", inserts)
+	}
+
+	si, err := ts.GetShard(ctx, "destination_ks", "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(si.SourceShards) != 0 {
+		t.Errorf("TableMigrate must not set up filtered replication. got SourceShards = %v", si.SourceShards)
+	}
+}
+
+func TestTableMigrateSameShard(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	wi := NewInstance(ts, "cell1", time.Second)
+	_, err := newTableMigrateWorker(wi.wr, "cell1", "ks", "0", "ks", "0", true /* online */, true /* offline */, false /* resume */, true /* verify */, []string{"t1"}, defaultChunkCount, defaultMinRowsPerChunk, defaultSourceReaderCount, defaultWriteQueryMaxRows, defaultWriteQueryMaxSize, defaultWriteTransactionMaxRows, defaultWriteTransactionMaxSize, defaultDestinationWriterCount, defaultDestinationQueueSize, defaultMinHealthyRdonlyTablets, defaultMaxTPS, defaultMaxReplicationLag, defaultMaxWriteMBPerSecond, defaultCompression, nil /* sourceTabletAliases */)
+	if err == nil || !strings.Contains(err.Error(), "source and destination shard must be different") {
+		t.Errorf("newTableMigrateWorker() with the same source and destination shard: got err = %v", err)
+	}
+}
@@ -18,6 +18,9 @@ package worker
 
 import (
 	"fmt"
+	"html"
+	"html/template"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +33,18 @@ import (
 // Functions which modify the status of a table must use the same index for
 // the table which the table had in schema passed in to initialize().
 type tableStatusList struct {
+	// action is the name of the operation which is shown in the status,
+	// e.g. "copy" or "diff". Defaults to "copy".
+	action string
+	// phase is the value of the "phase" label of the per-table stats e.g.
+	// "online" or "offline". Defaults to the action.
+	phase string
+	// keyspace and shard are the values of the "keyspace" and "shard" labels
+	// of the per-table stats. They are the keyspace and shard which the
+	// worker was started for. Workers which run concurrently as jobs
+	// therefore do not share the stats of a table.
+	keyspace, shard string
+
 	// mu guards all fields in the group below.
 	mu sync.Mutex
 	// initialized is true when initialize() was called.
@@ -87,15 +102,91 @@ func (t *tableStatusList) threadDone(tableIndex int) {
 		panic("threadDone() requires an initialized tableStatusList")
 	}
 
-	t.tableStatuses[tableIndex].threadDone()
+	ts := t.tableStatuses[tableIndex]
+	key := t.statsKey(tableIndex)
+	statsTableChunksDoneCounters.Add(key, 1)
+	if elapsed, done := ts.threadDone(); done {
+		statsTableDurationsNs.Set(key, elapsed.Nanoseconds())
+	}
 }
 
+// addCopiedRows records that "copiedRows" rows of the table were compared
+// (and reconciled if necessary).
 func (t *tableStatusList) addCopiedRows(tableIndex, copiedRows int) {
 	if !t.isInitialized() {
 		panic("addCopiedRows() requires an initialized tableStatusList")
 	}
 
 	t.tableStatuses[tableIndex].addCopiedRows(copiedRows)
+	statsTableRowsComparedCounters.Add(t.statsKey(tableIndex), int64(copiedRows))
+}
+
+// addReadRows records that "readRows" rows of the table were read from a
+// source or destination tablet.
+func (t *tableStatusList) addReadRows(tableIndex, readRows int) {
+	if !t.isInitialized() {
+		panic("addReadRows() requires an initialized tableStatusList")
+	}
+
+	statsTableRowsReadCounters.Add(t.statsKey(tableIndex), int64(readRows))
+}
+
+// addWrittenRows records that "writtenRows" rows of the table were inserted,
+// updated or deleted on a destination tablet.
+func (t *tableStatusList) addWrittenRows(tableIndex, writtenRows int) {
+	if !t.isInitialized() {
+		panic("addWrittenRows() requires an initialized tableStatusList")
+	}
+
+	statsTableRowsWrittenCounters.Add(t.statsKey(tableIndex), int64(writtenRows))
+}
+
+// statsKey returns the label values of the per-table stats.
+func (t *tableStatusList) statsKey(tableIndex int) []string {
+	return []string{t.keyspace, t.shard, t.phaseName(), t.tableStatuses[tableIndex].name}
+}
+
+func (t *tableStatusList) actionName() string {
+	if t.action == "" {
+		return "copy"
+	}
+	return t.action
+}
+
+func (t *tableStatusList) phaseName() string {
+	if t.phase == "" {
+		return t.actionName()
+	}
+	return t.phase
+}
+
+// tableFailed records that the processing of the table failed with "err".
+func (t *tableStatusList) tableFailed(tableIndex int, err error) {
+	if !t.isInitialized() {
+		panic("tableFailed() requires an initialized tableStatusList")
+	}
+
+	t.tableStatuses[tableIndex].failed(err)
+}
+
+// resetProgress discards the progress of a table whose processing is
+// started over.
+func (t *tableStatusList) resetProgress(tableIndex int) {
+	if !t.isInitialized() {
+		panic("resetProgress() requires an initialized tableStatusList")
+	}
+
+	t.tableStatuses[tableIndex].resetProgress()
+}
+
+// setDifferentRows records the example rows of the diff report "dr" of a
+// table. They are shown on the status page.
+func (t *tableStatusList) setDifferentRows(tableIndex int, dr DiffReport) {
+	if !t.isInitialized() {
+		panic("setDifferentRows() requires an initialized tableStatusList")
+	}
+
+	t.tableStatuses[tableIndex].setDifferentRows(dr)
 }
 
 // format returns a status for each table and the overall ETA.
@@ -104,6 +195,8 @@ func (t *tableStatusList) format() ([]string, time.Time) {
 		return nil, time.Now()
 	}
 
+	action := t.actionName()
+	now := time.Now()
 	copiedRows := uint64(0)
 	rowCount := uint64(0)
 	result := make([]string, len(t.tableStatuses))
@@ -112,12 +205,15 @@ func (t *tableStatusList) format() ([]string, time.Time) {
 		if ts.isView {
 			// views are not copied
 			result[i] = fmt.Sprintf("%v is a view", ts.name)
+		} else if ts.err != nil {
+			result[i] = fmt.Sprintf("%v: %v failed after %v processed rows: %v", ts.name, action, ts.copiedRows, ts.err)
 		} else if ts.threadsStarted == 0 {
 			// we haven't started yet
-			result[i] = fmt.Sprintf("%v: copy not started (estimating %v rows)", ts.name, ts.rowCount)
+			result[i] = fmt.Sprintf("%v: %v not started (estimating %v rows)", ts.name, action, ts.rowCount)
 		} else if ts.threadsDone == ts.threadCount {
 			// we are done with the copy
-			result[i] = fmt.Sprintf("%v: copy done, processed %v rows", ts.name, ts.copiedRows)
+			elapsed := ts.endTime.Sub(ts.startTime)
+			result[i] = fmt.Sprintf("%v: %v done, processed %v rows in %v (%.0f rows/s)", ts.name, action, ts.copiedRows, elapsed, rowsPerSecond(ts.copiedRows, elapsed))
 		} else {
 			// copy is running
 			// Display 0% if rowCount is 0 because the actual number of rows can be > 0
@@ -126,13 +222,13 @@ func (t *tableStatusList) format() ([]string, time.Time) {
 			if ts.rowCount > 0 {
 				percentage = float64(ts.copiedRows) / float64(ts.rowCount) * 100.0
 			}
-			result[i] = fmt.Sprintf("%v: copy running using %v threads (%v/%v rows processed, %.1f%%)", ts.name, ts.threadsStarted-ts.threadsDone, ts.copiedRows, ts.rowCount, percentage)
+			elapsed := now.Sub(ts.startTime)
+			result[i] = fmt.Sprintf("%v: %v running using %v threads (%v/%v rows processed, %.1f%%, %.0f rows/s, ETA: %v)", ts.name, action, ts.threadsStarted-ts.threadsDone, ts.copiedRows, ts.rowCount, percentage, rowsPerSecond(ts.copiedRows, elapsed), formatETA(now, elapsed, ts.copiedRows, ts.rowCount))
 		}
 		copiedRows += ts.copiedRows
 		rowCount += ts.rowCount
 		ts.mu.Unlock()
 	}
-	now := time.Now()
 	if rowCount == 0 || copiedRows == 0 {
 		return result, now
 	}
@@ -140,6 +236,148 @@ func (t *tableStatusList) format() ([]string, time.Time) {
 	return result, eta
 }
 
+// formatProgress returns the overall progress across all tables.
+func (t *tableStatusList) formatProgress() string {
+	if !t.isInitialized() {
+		return "not started"
+	}
+
+	copiedRows := uint64(0)
+	rowCount := uint64(0)
+	for _, ts := range t.tableStatuses {
+		ts.mu.Lock()
+		copiedRows += ts.copiedRows
+		rowCount += ts.rowCount
+		ts.mu.Unlock()
+	}
+	percentage := 0.0
+	if rowCount > 0 {
+		percentage = float64(copiedRows) / float64(rowCount) * 100.0
+	}
+	return fmt.Sprintf("%v/%v rows processed (%.1f%%), %.0f rows/s", copiedRows, rowCount, percentage, rowsPerSecond(copiedRows, time.Since(t.startTime)))
+}
+
+// tableProgress is the machine-readable status of a table.
+type tableProgress struct {
+	Name   string `json:"name"`
+	IsView bool   `json:"is_view,omitempty"`
+	// State is "not started", "running", "done" or "failed".
+	State string `json:"state"`
+	// RowCount is the estimated number of rows.
+	RowCount      uint64  `json:"row_count"`
+	ProcessedRows uint64  `json:"processed_rows"`
+	RowsPerSecond float64 `json:"rows_per_second"`
+	// RunningThreads is the number of threads which currently process the table.
+	RunningThreads int `json:"running_threads"`
+	// Error is set if the state is "failed".
+	Error string `json:"error,omitempty"`
+	// DifferentRows has example rows if a diff found differences.
+	DifferentRows []differentRowReport `json:"different_rows,omitempty"`
+	// Columns are the names of the columns of the rows in DifferentRows.
+	Columns []string `json:"columns,omitempty"`
+}
+
+// progress returns the status of each table. It returns nil if initialize()
+// was not called yet.
+func (t *tableStatusList) progress() []tableProgress {
+	if !t.isInitialized() {
+		return nil
+	}
+
+	now := time.Now()
+	result := make([]tableProgress, len(t.tableStatuses))
+	for i, ts := range t.tableStatuses {
+		ts.mu.Lock()
+		p := tableProgress{
+			Name:          ts.name,
+			IsView:        ts.isView,
+			RowCount:      ts.rowCount,
+			ProcessedRows: ts.copiedRows,
+			DifferentRows: ts.differentRows,
+			Columns:       ts.differentColumns,
+		}
+		switch {
+		case ts.err != nil:
+			p.State = "failed"
+			p.Error = ts.err.Error()
+			p.RowsPerSecond = rowsPerSecond(ts.copiedRows, ts.endTime.Sub(ts.startTime))
+		case ts.isView || ts.threadsStarted == 0:
+			p.State = "not started"
+		case ts.threadsDone == ts.threadCount:
+			p.State = "done"
+			p.RowsPerSecond = rowsPerSecond(ts.copiedRows, ts.endTime.Sub(ts.startTime))
+		default:
+			p.State = "running"
+			p.RowsPerSecond = rowsPerSecond(ts.copiedRows, now.Sub(ts.startTime))
+			p.RunningThreads = ts.threadsStarted - ts.threadsDone
+		}
+		ts.mu.Unlock()
+		result[i] = p
+	}
+	return result
+}
+
+// formatHTML returns an HTML table with one row per table. Each row shows the
+// state, the processed rows, the rate and the error of a table.
+// It returns an empty string if initialize() was not called yet.
+func (t *tableStatusList) formatHTML() template.HTML {
+	tables := t.progress()
+	if tables == nil {
+		return ""
+	}
+
+	result := "<table>\n"
+	result += "<tr><th>Table</th><th>State</th><th>Processed Rows</th><th>Rows/s</th><th>Error</th></tr>\n"
+	for _, p := range tables {
+		state := p.State
+		if p.IsView {
+			state = "view"
+		}
+		result += fmt.Sprintf("<tr><td>%v</td><td>%v</td><td>%v/%v</td><td>%.0f</td><td>%v</td></tr>\n",
+			html.EscapeString(p.Name), state, p.ProcessedRows, p.RowCount, p.RowsPerSecond, html.EscapeString(p.Error))
+	}
+	result += "</table>\n"
+	for _, p := range tables {
+		if len(p.DifferentRows) > 0 {
+			result += formatDifferentRowsHTML(p)
+		}
+	}
+	return template.HTML(result)
+}
+
+// formatDifferentRowsHTML returns an HTML table with the example rows of a
+// table which has differences.
+func formatDifferentRowsHTML(p tableProgress) string {
+	result := fmt.Sprintf("<b>Example differences in table %v:</b></br>\n", html.EscapeString(p.Name))
+	result += "<table>\n"
+	result += fmt.Sprintf("<tr><th>Type</th><th>Primary Key</th><th>Source (%[1]v)</th><th>Destination (%[1]v)</th></tr>\n", html.EscapeString(strings.Join(p.Columns, ", ")))
+	for _, r := range p.DifferentRows {
+		result += fmt.Sprintf("<tr><td>%v</td><td>%v</td><td>%v</td><td>%v</td></tr>\n",
+			r.Type, html.EscapeString(strings.Join(r.PrimaryKey, ", ")), html.EscapeString(strings.Join(r.SourceRow, ", ")), html.EscapeString(strings.Join(r.DestinationRow, ", ")))
+	}
+	result += "</table>\n"
+	return result
+}
+
+// rowsPerSecond returns the average rate at which "rows" were processed
+// within "elapsed".
+func rowsPerSecond(rows uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(rows) / elapsed.Seconds()
+}
+
+// formatETA returns the estimated time when "rowCount" rows will have been
+// processed if it took "elapsed" to process "copiedRows".
+func formatETA(now time.Time, elapsed time.Duration, copiedRows, rowCount uint64) string {
+	if copiedRows == 0 || rowCount == 0 {
+		return "unknown"
+	}
+	remaining := time.Duration(float64(elapsed) * float64(rowCount-copiedRows) / float64(copiedRows))
+	return now.Add(remaining).Format(time.RFC3339)
+}
+
 // tableStatus keeps track of the status for a given table.
 type tableStatus struct {
 	name   string
@@ -152,6 +390,16 @@ type tableStatus struct {
 	threadCount    int    // how many concurrent threads will copy the data
 	threadsStarted int    // how many threads have started
 	threadsDone    int    // how many threads are done
+	// startTime is set when the first thread has started.
+	startTime time.Time
+	// endTime is set when the last thread is done or the table failed.
+	endTime time.Time
+	// err is set when the table failed.
+	err error
+	// differentRows has the example rows of a diff with differences.
+	differentRows []differentRowReport
+	// differentColumns are the column names of the rows in differentRows.
+	differentColumns []string
 }
 
 func newTableStatus(name string, isView bool, rowCount uint64) *tableStatus {
@@ -170,14 +418,24 @@ func (ts *tableStatus) setThreadCount(threadCount int) {
 
 func (ts *tableStatus) threadStarted() {
 	ts.mu.Lock()
+	if ts.threadsStarted == 0 {
+		ts.startTime = time.Now()
+	}
 	ts.threadsStarted++
 	ts.mu.Unlock()
 }
 
-func (ts *tableStatus) threadDone() {
+// threadDone returns true and the time it took to process the table if this
+// was the last thread.
+func (ts *tableStatus) threadDone() (time.Duration, bool) {
 	ts.mu.Lock()
+	defer ts.mu.Unlock()
 	ts.threadsDone++
-	ts.mu.Unlock()
+	if ts.threadsDone == ts.threadCount {
+		ts.endTime = time.Now()
+		return ts.endTime.Sub(ts.startTime), true
+	}
+	return 0, false
 }
 
 func (ts *tableStatus) addCopiedRows(copiedRows int) {
@@ -189,3 +447,34 @@ func (ts *tableStatus) addCopiedRows(copiedRows int) {
 	}
 	ts.mu.Unlock()
 }
+
+func (ts *tableStatus) failed(err error) {
+	ts.mu.Lock()
+	ts.err = err
+	ts.endTime = time.Now()
+	if ts.threadsStarted == 0 {
+		// The table failed before any thread was started e.g. in the
+		// row count check.
+		ts.startTime = ts.endTime
+	}
+	ts.mu.Unlock()
+}
+
+func (ts *tableStatus) setDifferentRows(dr DiffReport) {
+	ts.mu.Lock()
+	ts.differentRows = newDifferentRowReports(dr.differentRows)
+	ts.differentColumns = dr.columns
+	ts.mu.Unlock()
+}
+
+func (ts *tableStatus) resetProgress() {
+	ts.mu.Lock()
+	ts.err = nil
+	ts.differentRows = nil
+	ts.differentColumns = nil
+	ts.copiedRows = 0
+	ts.threadCount = 0
+	ts.threadsStarted = 0
+	ts.threadsDone = 0
+	ts.mu.Unlock()
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func TestTableStatusListFormat(t *testing.T) {
+	tsl := &tableStatusList{action: "diff"}
+	if got, want := tsl.formatProgress(), "not started"; got != want {
+		t.Errorf("formatProgress() before initialize() = %v, want = %v", got, want)
+	}
+
+	tsl.initialize(&tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Type: "BASE TABLE", RowCount: 100},
+			{Name: "t2", Type: "BASE TABLE", RowCount: 200},
+			{Name: "t3", Type: "BASE TABLE", RowCount: 10},
+		},
+	})
+	for i := 0; i < 3; i++ {
+		tsl.setThreadCount(i, 1)
+	}
+	tsl.threadStarted(0)
+	tsl.addCopiedRows(0, 50)
+	tsl.threadStarted(2)
+	tsl.addCopiedRows(2, 12)
+	tsl.threadDone(2)
+
+	statuses, _ := tsl.format()
+	want := []string{
+		"t1: diff running using 1 threads (50/100 rows processed, 50.0%,",
+		"t2: diff not started (estimating 200 rows)",
+		"t3: diff done, processed 12 rows in ",
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("format() returned %v statuses, want %v: %v", len(statuses), len(want), statuses)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(statuses[i], w) {
+			t.Errorf("format()[%v] = %v, want prefix: %v", i, statuses[i], w)
+		}
+	}
+
+	// t3 has more rows than estimated.
+	if got, want := tsl.formatProgress(), "62/312 rows processed (19.9%), "; !strings.HasPrefix(got, want) {
+		t.Errorf("formatProgress() = %v, want prefix: %v", got, want)
+	}
+}
+
+func TestTableStatusListProgress(t *testing.T) {
+	tsl := &tableStatusList{action: "diff"}
+	if got := tsl.progress(); got != nil {
+		t.Errorf("progress() before initialize() = %v, want = nil", got)
+	}
+
+	tsl.initialize(&tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Type: "BASE TABLE", RowCount: 100},
+			{Name: "t2", Type: "BASE TABLE", RowCount: 200},
+			{Name: "t3", Type: "BASE TABLE", RowCount: 10},
+			{Name: "v1", Type: "VIEW"},
+		},
+	})
+	tsl.setThreadCount(0, 2)
+	tsl.setThreadCount(2, 1)
+	tsl.threadStarted(0)
+	tsl.addCopiedRows(0, 50)
+	tsl.threadStarted(2)
+	tsl.addCopiedRows(2, 10)
+	tsl.threadDone(2)
+
+	got := tsl.progress()
+	want := []tableProgress{
+		{Name: "t1", State: "running", RowCount: 100, ProcessedRows: 50, RunningThreads: 1},
+		{Name: "t2", State: "not started", RowCount: 200},
+		{Name: "t3", State: "done", RowCount: 10, ProcessedRows: 10},
+		{Name: "v1", IsView: true, State: "not started"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("progress() returned %v tables, want %v: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		// The rate depends on the elapsed time.
+		got[i].RowsPerSecond = 0
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("progress()[%v] = %+v, want = %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTableStatusListFailed(t *testing.T) {
+	tsl := &tableStatusList{action: "diff"}
+	if got := tsl.formatHTML(); got != "" {
+		t.Errorf("formatHTML() before initialize() = %v, want empty", got)
+	}
+
+	tsl.initialize(&tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Type: "BASE TABLE", RowCount: 100},
+			{Name: "t2", Type: "BASE TABLE", RowCount: 200},
+		},
+	})
+	tsl.setThreadCount(0, 1)
+	tsl.threadStarted(0)
+	tsl.addCopiedRows(0, 40)
+	tsl.threadDone(0)
+	tsl.tableFailed(0, errors.New("Table t1 has differences: <3 rows>"))
+
+	got := tsl.progress()
+	got[0].RowsPerSecond = 0
+	want := tableProgress{Name: "t1", State: "failed", RowCount: 100, ProcessedRows: 40, Error: "Table t1 has differences: <3 rows>"}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("progress()[0] = %+v, want = %+v", got[0], want)
+	}
+
+	statuses, _ := tsl.format()
+	if want := "t1: diff failed after 40 processed rows: Table t1 has differences: <3 rows>"; statuses[0] != want {
+		t.Errorf("format()[0] = %v, want = %v", statuses[0], want)
+	}
+
+	html := string(tsl.formatHTML())
+	for _, want := range []string{
+		"<tr><td>t1</td><td>failed</td><td>40/100</td>",
+		"<td>Table t1 has differences: &lt;3 rows&gt;</td></tr>",
+		"<tr><td>t2</td><td>not started</td><td>0/200</td><td>0</td><td></td></tr>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("formatHTML() = %v, want substring: %v", html, want)
+		}
+	}
+
+	// A retry clears the error.
+	tsl.resetProgress(0)
+	if got := tsl.progress()[0]; got.State != "not started" || got.Error != "" {
+		t.Errorf("progress()[0] after resetProgress() = %+v, want not started without error", got)
+	}
+}
+
+func TestTableStatusListDifferentRows(t *testing.T) {
+	tsl := &tableStatusList{action: "diff"}
+	tsl.initialize(&tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Type: "BASE TABLE", RowCount: 100},
+		},
+	})
+	tsl.setDifferentRows(0, DiffReport{
+		mismatchedRows: 1,
+		differentRows: []differentRow{{
+			diffType:       DiffNotEqual,
+			primaryKey:     []sqltypes.Value{sqltypes.NewInt64(1)},
+			sourceRow:      []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("<a>")},
+			destinationRow: []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("b")},
+		}},
+		columns: []string{"id", "msg"},
+	})
+
+	got := tsl.progress()[0]
+	wantRows := []differentRowReport{{Type: "not_equal", PrimaryKey: []string{"1"}, SourceRow: []string{"1", "<a>"}, DestinationRow: []string{"1", "b"}}}
+	if !reflect.DeepEqual(got.DifferentRows, wantRows) || !reflect.DeepEqual(got.Columns, []string{"id", "msg"}) {
+		t.Errorf("progress()[0] = %+v, want different rows: %+v", got, wantRows)
+	}
+
+	html := string(tsl.formatHTML())
+	for _, want := range []string{
+		"<b>Example differences in table t1:</b>",
+		"<th>Source (id, msg)</th><th>Destination (id, msg)</th>",
+		"<tr><td>not_equal</td><td>1</td><td>1, &lt;a&gt;</td><td>1, b</td></tr>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("formatHTML() = %v, want substring: %v", html, want)
+		}
+	}
+
+	// A retry clears the different rows.
+	tsl.resetProgress(0)
+	if got := tsl.progress()[0]; got.DifferentRows != nil {
+		t.Errorf("progress()[0] after resetProgress() = %+v, want no different rows", got)
+	}
+}
+
+func TestTableStatusListStats(t *testing.T) {
+	resetVars()
+	tsl := &tableStatusList{phase: "online", keyspace: "ks", shard: "-80"}
+	tsl.initialize(&tabletmanagerdatapb.SchemaDefinition{
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{Name: "t1", Type: "BASE TABLE", RowCount: 100},
+		},
+	})
+	tsl.setThreadCount(0, 2)
+	tsl.threadStarted(0)
+	tsl.threadStarted(0)
+	tsl.addReadRows(0, 120)
+	tsl.addCopiedRows(0, 100)
+	tsl.addWrittenRows(0, 20)
+	tsl.threadDone(0)
+	if _, ok := statsTableDurationsNs.Counts()["ks.-80.online.t1"]; ok {
+		t.Errorf("WorkerTableDurations must not be set before all chunks are done: %v", statsTableDurationsNs.Counts())
+	}
+	tsl.threadDone(0)
+
+	for _, tc := range []struct {
+		name   string
+		counts map[string]int64
+		want   int64
+	}{
+		{"WorkerTableRowsRead", statsTableRowsReadCounters.Counts(), 120},
+		{"WorkerTableRowsCompared", statsTableRowsComparedCounters.Counts(), 100},
+		{"WorkerTableRowsWritten", statsTableRowsWrittenCounters.Counts(), 20},
+		{"WorkerTableChunksDone", statsTableChunksDoneCounters.Counts(), 2},
+	} {
+		if got := tc.counts["ks.-80.online.t1"]; got != tc.want {
+			t.Errorf("%v[ks.-80.online.t1] = %v, want = %v", tc.name, got, tc.want)
+		}
+	}
+	if _, ok := statsTableDurationsNs.Counts()["ks.-80.online.t1"]; !ok {
+		t.Errorf("WorkerTableDurations must be set when all chunks are done: %v", statsTableDurationsNs.Counts())
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got, want := formatETA(now, time.Minute, 0, 100), "unknown"; got != want {
+		t.Errorf("formatETA() without progress = %v, want = %v", got, want)
+	}
+	// A quarter of the rows took a minute. The rest takes three more minutes.
+	if got, want := formatETA(now, time.Minute, 25, 100), "2018-01-01T00:03:00Z"; got != want {
+		t.Errorf("formatETA() = %v, want = %v", got, want)
+	}
+}
@@ -57,7 +57,7 @@ func newSingleTabletProvider(ctx context.Context, ts *topo.Server, alias *topoda
 }
 
 func (p *singleTabletProvider) getTablet() (*topodatapb.Tablet, error) {
-	shortCtx, cancel := context.WithTimeout(p.ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(p.ctx, remoteActionsTimeoutFor(p.ctx))
 	tablet, err := p.ts.GetTablet(shortCtx, p.alias)
 	cancel()
 	if err != nil {
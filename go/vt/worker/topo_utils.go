@@ -19,18 +19,24 @@ package worker
 import (
 	"flag"
 	"fmt"
+	"io"
 	"math/rand"
+	"sync"
 	"time"
 
 	"vitess.io/vitess/go/vt/vterrors"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
 	"vitess.io/vitess/go/vt/wrangler"
 
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -41,33 +47,120 @@ var (
 	// Therefore, the default for this variable must be higher
 	// than vttablet's -health_check_interval.
 	waitForHealthyTabletsTimeout = flag.Duration("wait_for_healthy_tablets_timeout", 60*time.Second, "maximum time to wait at the start if less than --min_healthy_tablets are available")
+	// waitForDrainTimeout must be higher than vttablet's
+	// -serving_state_grace_period and the interval in which its QPS rate is
+	// updated.
+	waitForDrainTimeout = flag.Duration("wait_for_drain_timeout", 60*time.Second, "maximum time to wait for a REPLICA tablet to finish its in-flight queries after it was taken out of serving")
+	// minRemainingHealthyRdonlyTablets protects other users of the RDONLY
+	// tablets (e.g. batch jobs) during a long running worker.
+	minRemainingHealthyRdonlyTablets = flag.Int("min_remaining_healthy_rdonly_tablets", 0, "if set, a RDONLY tablet is only taken out of serving if at least this many healthy RDONLY tablets remain in its shard. Unlike the --min_healthy_rdonly_tablets flag of the commands, this does not include the tablet which is taken out")
+	// waitForServingTimeout must be higher than vttablet's
+	// -health_check_interval because a tablet whose type was changed back
+	// reports healthy only after its next health check.
+	waitForServingTimeout = flag.Duration("wait_for_serving_tablets_timeout", 0, "if set, maximum time to wait at the end until the tablets which were used by the worker are healthy and serving again. 0 disables the wait")
 )
 
+// reservedTablets has the tablets which are currently used by a worker of
+// this process. Jobs which run concurrently (see jobManager) must never use
+// the same tablet.
+var reservedTablets = newTabletReservations()
+
+// tabletReservations is a thread-safe set of tablet aliases.
+type tabletReservations struct {
+	mu      sync.Mutex
+	aliases map[string]bool
+}
+
+func newTabletReservations() *tabletReservations {
+	return &tabletReservations{aliases: make(map[string]bool)}
+}
+
+// reserve marks the tablet as used. It fails if the tablet is already used.
+func (tr *tabletReservations) reserve(tabletAlias *topodatapb.TabletAlias) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	key := topoproto.TabletAliasString(tabletAlias)
+	if tr.aliases[key] {
+		return fmt.Errorf("tablet %v is already used by another job of this vtworker", key)
+	}
+	tr.aliases[key] = true
+	return nil
+}
+
+func (tr *tabletReservations) release(tabletAlias *topodatapb.TabletAlias) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	delete(tr.aliases, topoproto.TabletAliasString(tabletAlias))
+}
+
+func (tr *tabletReservations) isReserved(tabletAlias *topodatapb.TabletAlias) bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	return tr.aliases[topoproto.TabletAliasString(tabletAlias)]
+}
+
 // FindHealthyTablet returns a random healthy tabletType tablet.
 // Since we don't want to use them all, we require at least
 // minHealthyRdonlyTablets servers to be healthy.
+// Tablets which are reserved by another job of this process are neither
+// returned nor counted.
 // May block up to -wait_for_healthy_rdonly_tablets_timeout.
 func FindHealthyTablet(ctx context.Context, wr *wrangler.Wrangler, tsc *discovery.TabletStatsCache, cell, keyspace, shard string, minHealthyRdonlyTablets int, tabletType topodatapb.TabletType) (*topodatapb.TabletAlias, error) {
 	if tsc == nil {
 		// No healthcheck instance provided. Create one.
-		healthCheck := discovery.NewHealthCheck(*healthcheckRetryDelay, *healthCheckTimeout)
-		tsc = discovery.NewTabletStatsCache(healthCheck, wr.TopoServer(), cell)
-		watcher := discovery.NewShardReplicationWatcher(wr.TopoServer(), healthCheck, cell, keyspace, shard, *healthCheckTopologyRefresh, discovery.DefaultTopoReadConcurrency)
-		defer watcher.Stop()
-		defer healthCheck.Close()
+		var stop func()
+		tsc, stop = newTabletStatsCache(wr, cell, keyspace, shard)
+		defer stop()
 	}
 
-	healthyTablets, err := waitForHealthyTablets(ctx, wr, tsc, cell, keyspace, shard, minHealthyRdonlyTablets, *waitForHealthyTabletsTimeout, tabletType)
+	healthyTablets, err := waitForHealthyTablets(ctx, wr, tsc, cell, keyspace, shard, minHealthyRdonlyTablets, *waitForHealthyTabletsTimeout, tabletType, nil /* exclude */)
 	if err != nil {
 		return nil, err
 	}
+	if len(healthyTablets) == 0 {
+		return nil, fmt.Errorf("no healthy %v tablets in (%v,%v/%v) which are not used by other jobs of this vtworker", tabletType, cell, keyspace, shard)
+	}
 
 	// random server in the list is what we want
 	index := rand.Intn(len(healthyTablets))
 	return healthyTablets[index].Tablet.Alias, nil
 }
 
-func waitForHealthyTablets(ctx context.Context, wr *wrangler.Wrangler, tsc *discovery.TabletStatsCache, cell, keyspace, shard string, minHealthyRdonlyTablets int, timeout time.Duration, tabletType topodatapb.TabletType) ([]discovery.TabletStats, error) {
+// newTabletStatsCache returns a TabletStatsCache which watches the tablets of
+// "keyspace/shard" in "cell". The returned function must be called to stop it.
+func newTabletStatsCache(wr *wrangler.Wrangler, cell, keyspace, shard string) (*discovery.TabletStatsCache, func()) {
+	healthCheck := discovery.NewHealthCheck(*healthcheckRetryDelay, *healthCheckTimeout)
+	tsc := discovery.NewTabletStatsCache(healthCheck, wr.TopoServer(), cell)
+	watcher := discovery.NewShardReplicationWatcher(wr.TopoServer(), healthCheck, cell, keyspace, shard, *healthCheckTopologyRefresh, discovery.DefaultTopoReadConcurrency)
+	return tsc, func() {
+		watcher.Stop()
+		healthCheck.Close()
+	}
+}
+
+// availableTablets returns the tablets of "stats" which are neither "exclude"
+// nor reserved by a job of this process (see reservedTablets).
+// "exclude" may be nil.
+func availableTablets(stats []discovery.TabletStats, exclude *topodatapb.TabletAlias) []discovery.TabletStats {
+	var result []discovery.TabletStats
+	for _, ts := range stats {
+		if exclude != nil && topoproto.TabletAliasEqual(ts.Tablet.Alias, exclude) {
+			continue
+		}
+		if reservedTablets.isReserved(ts.Tablet.Alias) {
+			continue
+		}
+		result = append(result, ts)
+	}
+	return result
+}
+
+// waitForHealthyTablets waits until at least minHealthyRdonlyTablets healthy
+// "tabletType" tablets are available (see availableTablets()) and returns them.
+func waitForHealthyTablets(ctx context.Context, wr *wrangler.Wrangler, tsc *discovery.TabletStatsCache, cell, keyspace, shard string, minHealthyRdonlyTablets int, timeout time.Duration, tabletType topodatapb.TabletType, exclude *topodatapb.TabletAlias) ([]discovery.TabletStats, error) {
 	busywaitCtx, busywaitCancel := context.WithTimeout(ctx, timeout)
 	defer busywaitCancel()
 
@@ -90,7 +183,7 @@ func waitForHealthyTablets(ctx context.Context, wr *wrangler.Wrangler, tsc *disc
 		default:
 		}
 
-		healthyTablets = discovery.RemoveUnhealthyTablets(tsc.GetTabletStats(keyspace, shard, tabletType))
+		healthyTablets = availableTablets(discovery.RemoveUnhealthyTablets(tsc.GetTabletStats(keyspace, shard, tabletType)), exclude)
 		if len(healthyTablets) >= minHealthyRdonlyTablets {
 			break
 		}
@@ -113,25 +206,105 @@ func waitForHealthyTablets(ctx context.Context, wr *wrangler.Wrangler, tsc *disc
 
 // FindWorkerTablet will:
 // - find a tabletType instance in the keyspace / shard
-// - mark it as worker
-// - tag it with our worker process
+// - mark it as worker (see markWorkerTablet())
+// It does not take out a RDONLY tablet if less than
+// --min_remaining_healthy_rdonly_tablets healthy ones would be left.
 func FindWorkerTablet(ctx context.Context, wr *wrangler.Wrangler, cleaner *wrangler.Cleaner, tsc *discovery.TabletStatsCache, cell, keyspace, shard string, minHealthyTablets int, tabletType topodatapb.TabletType) (*topodatapb.TabletAlias, error) {
+	if required := requiredHealthyTablets(minHealthyTablets, tabletType); required > minHealthyTablets {
+		wr.Logger().Infof("Requiring %v instead of %v healthy %v tablets in (%v,%v/%v) to keep at least %v after taking out one (--min_remaining_healthy_rdonly_tablets)",
+			required, minHealthyTablets, tabletType, cell, keyspace, shard, *minRemainingHealthyRdonlyTablets)
+		minHealthyTablets = required
+	}
 	tabletAlias, err := FindHealthyTablet(ctx, wr, tsc, cell, keyspace, shard, minHealthyTablets, tabletType)
 	if err != nil {
 		return nil, err
 	}
+	if err := markWorkerTablet(ctx, wr, cleaner, tabletAlias, tabletType); err != nil {
+		return nil, err
+	}
+	return tabletAlias, nil
+}
+
+// requiredHealthyTablets returns how many healthy "tabletType" tablets must be
+// available before FindWorkerTablet takes out one of them.
+func requiredHealthyTablets(minHealthyTablets int, tabletType topodatapb.TabletType) int {
+	if tabletType != topodatapb.TabletType_RDONLY || *minRemainingHealthyRdonlyTablets <= 0 {
+		return minHealthyTablets
+	}
+	if required := *minRemainingHealthyRdonlyTablets + 1; required > minHealthyTablets {
+		return required
+	}
+	return minHealthyTablets
+}
+
+// UseWorkerTablet is identical to FindWorkerTablet but uses the tablet
+// "tabletAlias" chosen by the user instead of a random healthy one.
+// It fails if the tablet is not a "tabletType" tablet in "keyspace"/"shard".
+// Like FindWorkerTablet, it does not take out a RDONLY tablet if less than
+// --min_remaining_healthy_rdonly_tablets healthy ones would be left.
+func UseWorkerTablet(ctx context.Context, wr *wrangler.Wrangler, cleaner *wrangler.Cleaner, tabletAlias *topodatapb.TabletAlias, keyspace, shard string, tabletType topodatapb.TabletType) error {
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	ti, err := wr.TopoServer().GetTablet(shortCtx, tabletAlias)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read tablet %v", topoproto.TabletAliasString(tabletAlias))
+	}
+	if ti.Keyspace != keyspace || ti.Shard != shard {
+		return fmt.Errorf("tablet %v is in %v/%v and not in %v/%v", topoproto.TabletAliasString(tabletAlias), ti.Keyspace, ti.Shard, keyspace, shard)
+	}
+	if ti.Type != tabletType {
+		return fmt.Errorf("tablet %v has type %v and not %v", topoproto.TabletAliasString(tabletAlias), ti.Type, tabletType)
+	}
+	if tabletType == topodatapb.TabletType_RDONLY && *minRemainingHealthyRdonlyTablets > 0 {
+		tsc, stop := newTabletStatsCache(wr, tabletAlias.Cell, keyspace, shard)
+		_, err := waitForHealthyTablets(ctx, wr, tsc, tabletAlias.Cell, keyspace, shard, *minRemainingHealthyRdonlyTablets, *waitForHealthyTabletsTimeout, tabletType, tabletAlias)
+		stop()
+		if err != nil {
+			return vterrors.Wrapf(err, "cannot take out tablet %v because less than --min_remaining_healthy_rdonly_tablets=%v healthy tablets would be left", topoproto.TabletAliasString(tabletAlias), *minRemainingHealthyRdonlyTablets)
+		}
+	}
+	return markWorkerTablet(ctx, wr, cleaner, tabletAlias, tabletType)
+}
+
+// newCleaner returns the Cleaner for a run of "command" on "keyspace/shard".
+// Its actions are also stored in the directory of the shard in the global
+// topology. If vtworker dies before it runs them,
+// "vtctl CleanupOrphanedWorkerActions" can run them instead and e.g. return
+// the tablets to their original type.
+func newCleaner(wr *wrangler.Wrangler, command, keyspace, shard string) *wrangler.Cleaner {
+	if wr == nil || wr.TopoServer() == nil {
+		return &wrangler.Cleaner{}
+	}
+	id := fmt.Sprintf("%v_%v_%v_%v", command, keyspace, shard, time.Now().UnixNano())
+	return wrangler.NewDurableCleaner(wr.TopoServer(), keyspace, shard, id, servenv.ListeningURL.String())
+}
+
+// markWorkerTablet will:
+// - reserve the tablet for this job (see reservedTablets)
+// - mark the tabletType tablet as worker
+// - tag it with our worker process
+// - for a REPLICA tablet, wait until it is drained (see waitForDrain())
+func markWorkerTablet(ctx context.Context, wr *wrangler.Wrangler, cleaner *wrangler.Cleaner, tabletAlias *topodatapb.TabletAlias, tabletType topodatapb.TabletType) error {
+	if err := reservedTablets.reserve(tabletAlias); err != nil {
+		return err
+	}
+	// Recorded first, the release runs as the very last clean-up action.
+	cleaner.Record("ReleaseTablet", topoproto.TabletAliasString(tabletAlias), func(ctx context.Context, wr *wrangler.Wrangler) error {
+		reservedTablets.release(tabletAlias)
+		return nil
+	})
 
 	wr.Logger().Infof("Changing tablet %v to '%v'", topoproto.TabletAliasString(tabletAlias), topodatapb.TabletType_DRAINED)
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
-	err = wr.ChangeSlaveType(shortCtx, tabletAlias, topodatapb.TabletType_DRAINED)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	err := wr.ChangeSlaveType(shortCtx, tabletAlias, topodatapb.TabletType_DRAINED)
 	cancel()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	ourURL := servenv.ListeningURL.String()
 	wr.Logger().Infof("Adding tag[worker]=%v to tablet %v", ourURL, topoproto.TabletAliasString(tabletAlias))
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	_, err = wr.TopoServer().UpdateTabletFields(shortCtx, tabletAlias, func(tablet *topodatapb.Tablet) error {
 		if tablet.Tags == nil {
 			tablet.Tags = make(map[string]string)
@@ -142,7 +315,7 @@ func FindWorkerTablet(ctx context.Context, wr *wrangler.Wrangler, cleaner *wrang
 	})
 	cancel()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	// Using "defer" here because we remove the tag *before* calling
 	// ChangeSlaveType back, so we need to record this tag change after the change
@@ -154,14 +327,122 @@ func FindWorkerTablet(ctx context.Context, wr *wrangler.Wrangler, cleaner *wrang
 	wrangler.RecordChangeSlaveTypeAction(cleaner, tabletAlias, topodatapb.TabletType_DRAINED, tabletType)
 
 	// We refresh the destination vttablet reloads the worker URL when it reloads the tablet.
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	wr.RefreshTabletState(shortCtx, tabletAlias)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	cancel()
 
-	return tabletAlias, nil
+	// Unlike RDONLY tablets, REPLICA tablets usually serve application
+	// traffic. Don't start using the tablet before that traffic is gone.
+	if tabletType == topodatapb.TabletType_REPLICA {
+		if err := waitForDrain(ctx, wr, tabletAlias, *waitForDrainTimeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForDrain blocks until the DRAINED tablet "tabletAlias" reports a QPS
+// rate of 0.0 in its health stream. At that point, the in-flight queries of
+// its previous clients have finished and vtgate stopped routing queries to it.
+// Note that vttablet keeps its query service running for DRAINED tablets
+// because the worker itself queries it.
+func waitForDrain(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ti, err := wr.TopoServer().GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	conn, err := tabletconn.GetDialer()(ti.Tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot connect to tablet %v", topoproto.TabletAliasString(tabletAlias))
+	}
+	defer conn.Close(ctx)
+
+	drained := false
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		if shr.RealtimeStats == nil {
+			return fmt.Errorf("health record does not include RealtimeStats message. tablet: %v health record: %v", topoproto.TabletAliasString(tabletAlias), shr)
+		}
+		if shr.RealtimeStats.Qps == 0.0 {
+			drained = true
+			return io.EOF
+		}
+		wr.Logger().Infof("Waiting for tablet %v to be drained. Current QPS rate: %.1f", topoproto.TabletAliasString(tabletAlias), shr.RealtimeStats.Qps)
+		return nil
+	})
+	if err != nil {
+		return vterrors.Wrapf(err, "tablet %v was not drained within %v", topoproto.TabletAliasString(tabletAlias), timeout)
+	}
+	if !drained {
+		return fmt.Errorf("health stream of tablet %v ended before it was drained", topoproto.TabletAliasString(tabletAlias))
+	}
+	wr.Logger().Infof("Tablet %v was drained", topoproto.TabletAliasString(tabletAlias))
+	return nil
+}
+
+// waitForServingTablets blocks until all tablets in "tabletAliases" report
+// that they are serving and healthy in their health stream.
+// Workers call it after their clean-up changed the type of the tablets back
+// (see WorkerStateWaitForServing). Until then, the capacity of the shard is
+// still reduced.
+func waitForServingTablets(ctx context.Context, wr *wrangler.Wrangler, tabletAliases []*topodatapb.TabletAlias, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	wg := sync.WaitGroup{}
+	rec := concurrency.AllErrorRecorder{}
+	for _, tabletAlias := range tabletAliases {
+		wg.Add(1)
+		go func(tabletAlias *topodatapb.TabletAlias) {
+			defer wg.Done()
+			if err := waitForServing(ctx, wr, tabletAlias); err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "tablet %v is not serving after %v", topoproto.TabletAliasString(tabletAlias), timeout))
+			}
+		}(tabletAlias)
+	}
+	wg.Wait()
+	return rec.Error()
+}
+
+// waitForServing blocks until the tablet "tabletAlias" is serving, not
+// DRAINED and has no health error.
+func waitForServing(ctx context.Context, wr *wrangler.Wrangler, tabletAlias *topodatapb.TabletAlias) error {
+	ti, err := wr.TopoServer().GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	conn, err := tabletconn.GetDialer()(ti.Tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot connect to tablet %v", topoproto.TabletAliasString(tabletAlias))
+	}
+	defer conn.Close(ctx)
+
+	serving := false
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		if shr.RealtimeStats == nil {
+			return fmt.Errorf("health record does not include RealtimeStats message. tablet: %v health record: %v", topoproto.TabletAliasString(tabletAlias), shr)
+		}
+		if shr.Serving && shr.RealtimeStats.HealthError == "" && shr.Target != nil && shr.Target.TabletType != topodatapb.TabletType_DRAINED {
+			serving = true
+			return io.EOF
+		}
+		wr.Logger().Infof("Waiting for tablet %v to be serving again. serving: %v health error: %v", topoproto.TabletAliasString(tabletAlias), shr.Serving, shr.RealtimeStats.HealthError)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !serving {
+		return fmt.Errorf("health stream of tablet %v ended before it was serving", topoproto.TabletAliasString(tabletAlias))
+	}
+	wr.Logger().Infof("Tablet %v is serving again", topoproto.TabletAliasString(tabletAlias))
+	return nil
 }
 
 func init() {
@@ -0,0 +1,164 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestUseWorkerTabletValidation(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	alias := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	if err := ts.CreateTablet(ctx, &topodatapb.Tablet{
+		Alias:    alias,
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_RDONLY,
+	}); err != nil {
+		t.Fatalf("CreateTablet failed: %v", err)
+	}
+
+	testcases := []struct {
+		desc       string
+		shard      string
+		tabletType topodatapb.TabletType
+		wantErr    string
+	}{
+		{
+			desc:       "wrong shard",
+			shard:      "80-",
+			tabletType: topodatapb.TabletType_RDONLY,
+			wantErr:    "is in ks/-80 and not in ks/80-",
+		},
+		{
+			desc:       "wrong type",
+			shard:      "-80",
+			tabletType: topodatapb.TabletType_REPLICA,
+			wantErr:    "has type RDONLY and not REPLICA",
+		},
+	}
+	for _, tc := range testcases {
+		err := UseWorkerTablet(ctx, wr, &wrangler.Cleaner{}, alias, "ks", tc.shard, tc.tabletType)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%v: UseWorkerTablet() = %v, want error containing: %v", tc.desc, err, tc.wantErr)
+		}
+	}
+}
+
+func TestTabletReservations(t *testing.T) {
+	tr := newTabletReservations()
+	alias := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	if err := tr.reserve(alias); err != nil {
+		t.Fatalf("reserve() failed: %v", err)
+	}
+	if !tr.isReserved(alias) {
+		t.Errorf("isReserved() = false after reserve(), want = true")
+	}
+	// A second job must not use the same tablet.
+	if err := tr.reserve(&topodatapb.TabletAlias{Cell: "cell1", Uid: 1}); err == nil || !strings.Contains(err.Error(), "already used by another job") {
+		t.Errorf("second reserve() = %v, want error containing: already used by another job", err)
+	}
+	tr.release(alias)
+	if tr.isReserved(alias) {
+		t.Errorf("isReserved() = true after release(), want = false")
+	}
+	if err := tr.reserve(alias); err != nil {
+		t.Errorf("reserve() after release() failed: %v", err)
+	}
+}
+
+func TestAvailableTablets(t *testing.T) {
+	stats := func(uids ...uint32) []discovery.TabletStats {
+		var result []discovery.TabletStats
+		for _, uid := range uids {
+			result = append(result, discovery.TabletStats{
+				Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "cell1", Uid: uid}},
+			})
+		}
+		return result
+	}
+	reserved := &topodatapb.TabletAlias{Cell: "cell1", Uid: 2}
+	if err := reservedTablets.reserve(reserved); err != nil {
+		t.Fatalf("reserve() failed: %v", err)
+	}
+	defer reservedTablets.release(reserved)
+
+	// Tablets reserved by other jobs and the excluded tablet are not counted.
+	got := availableTablets(stats(1, 2, 3, 4), &topodatapb.TabletAlias{Cell: "cell1", Uid: 3})
+	if want := stats(1, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("availableTablets() = %v, want = %v", got, want)
+	}
+	got = availableTablets(stats(1, 2), nil /* exclude */)
+	if want := stats(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("availableTablets() = %v, want = %v", got, want)
+	}
+}
+
+func TestParseTabletAliases(t *testing.T) {
+	got, err := parseTabletAliases("")
+	if err != nil || got != nil {
+		t.Errorf("parseTabletAliases(\"\") = (%v, %v), want = (nil, nil)", got, err)
+	}
+
+	got, err = parseTabletAliases("cell1-0000000001,cell2-0000000002")
+	if err != nil {
+		t.Fatalf("parseTabletAliases() failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Cell != "cell1" || got[0].Uid != 1 || got[1].Cell != "cell2" || got[1].Uid != 2 {
+		t.Errorf("parseTabletAliases() = %v, want = [cell1-0000000001 cell2-0000000002]", got)
+	}
+
+	if _, err := parseTabletAliases("cell1-0000000001,invalid"); err == nil {
+		t.Errorf("parseTabletAliases() with an invalid alias should have failed")
+	}
+}
+
+func TestRequiredHealthyTablets(t *testing.T) {
+	defer func(v int) { *minRemainingHealthyRdonlyTablets = v }(*minRemainingHealthyRdonlyTablets)
+
+	testcases := []struct {
+		minRemaining int
+		minHealthy   int
+		tabletType   topodatapb.TabletType
+		want         int
+	}{
+		{0, 2, topodatapb.TabletType_RDONLY, 2},
+		{2, 2, topodatapb.TabletType_RDONLY, 3},
+		{2, 5, topodatapb.TabletType_RDONLY, 5},
+		{2, 1, topodatapb.TabletType_REPLICA, 1},
+	}
+	for _, tc := range testcases {
+		*minRemainingHealthyRdonlyTablets = tc.minRemaining
+		if got := requiredHealthyTablets(tc.minHealthy, tc.tabletType); got != tc.want {
+			t.Errorf("requiredHealthyTablets(%v, %v) with --min_remaining_healthy_rdonly_tablets=%v = %v, want = %v", tc.minHealthy, tc.tabletType, tc.minRemaining, got, tc.want)
+		}
+	}
+}
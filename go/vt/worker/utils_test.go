@@ -31,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/wrangler"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
@@ -88,7 +89,8 @@ func sourceRdonlyFakeDB(t *testing.T, dbName, tableName string, min, max int) *f
 }
 
 // fakeTMCTopo is a FakeTabletManagerClient extension that implements ChangeType
-// using the provided topo server.
+// using the provided topo server. It also reports running replication for all
+// tablets to pass the pre-flight checks.
 type fakeTMCTopo struct {
 	tmclient.TabletManagerClient
 	server *topo.Server
@@ -109,3 +111,11 @@ func (f *fakeTMCTopo) ChangeType(ctx context.Context, tablet *topodatapb.Tablet,
 	})
 	return err
 }
+
+// SlaveStatus is part of the tmclient.TabletManagerClient interface.
+func (f *fakeTMCTopo) SlaveStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	return &replicationdatapb.Status{
+		SlaveIoRunning:  true,
+		SlaveSqlRunning: true,
+	}, nil
+}
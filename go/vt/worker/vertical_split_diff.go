@@ -19,6 +19,7 @@ package worker
 import (
 	"fmt"
 	"html/template"
+	"strings"
 	"sync"
 
 	"vitess.io/vitess/go/vt/vterrors"
@@ -42,23 +43,34 @@ import (
 // source shards in a shard split case.
 type VerticalSplitDiffWorker struct {
 	StatusWorker
-
-	wr                      *wrangler.Wrangler
-	cell                    string
-	keyspace                string
-	shard                   string
-	minHealthyRdonlyTablets int
-	parallelDiffsCount      int
-	cleaner                 *wrangler.Cleaner
+	diffOptions
+
+	wr              *wrangler.Wrangler
+	cell            string
+	keyspace        string
+	shard           string
+	tables          []string
+	comparison      comparisonOptions
+	reportWriter    *diffReportWriter
+	resultsWriter   *diffResultsWriter
+	tableStatusList *tableStatusList
+	cleaner         *wrangler.Cleaner
 
 	// populated during WorkerStateInit, read-only after that
 	keyspaceInfo *topo.KeyspaceInfo
 	shardInfo    *topo.ShardInfo
 
 	// populated during WorkerStateFindTargets, read-only after that
-	sourceAlias           *topodatapb.TabletAlias
-	destinationAlias      *topodatapb.TabletAlias
-	destinationTabletType topodatapb.TabletType
+	sourceAlias      *topodatapb.TabletAlias
+	destinationAlias *topodatapb.TabletAlias
+
+	// populated during WorkerStateSyncReplication with
+	// --use_consistent_snapshot, read-only after that
+	sourceSnapshot      *consistentSnapshot
+	destinationSnapshot *consistentSnapshot
+
+	// populated during WorkerStateSyncReplication, read-only after that
+	destinationMasterPosition string
 
 	// populated during WorkerStateDiff
 	sourceSchemaDefinition      *tabletmanagerdatapb.SchemaDefinition
@@ -66,18 +78,27 @@ type VerticalSplitDiffWorker struct {
 }
 
 // NewVerticalSplitDiffWorker returns a new VerticalSplitDiffWorker object.
-func NewVerticalSplitDiffWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, minHealthyRdonlyTablets, parallelDiffsCount int, destintationTabletType topodatapb.TabletType) Worker {
-	return &VerticalSplitDiffWorker{
-		StatusWorker: NewStatusWorker(),
-		wr:           wr,
-		cell:         cell,
-		keyspace:     keyspace,
-		shard:        shard,
-		minHealthyRdonlyTablets: minHealthyRdonlyTablets,
-		destinationTabletType:   destintationTabletType,
-		parallelDiffsCount:      parallelDiffsCount,
-		cleaner:                 &wrangler.Cleaner{},
+func NewVerticalSplitDiffWorker(wr *wrangler.Wrangler, cell, keyspace, shard string, tables []string, opts diffOptions) (Worker, error) {
+	comparison, err := opts.validate()
+	if err != nil {
+		return nil, err
 	}
+	opts.where = parenthesize(opts.where)
+
+	return &VerticalSplitDiffWorker{
+		StatusWorker:    NewStatusWorker(),
+		diffOptions:     opts,
+		wr:              wr,
+		cell:            cell,
+		keyspace:        keyspace,
+		shard:           shard,
+		tables:          tables,
+		comparison:      comparison,
+		reportWriter:    newDiffReportWriter(wr.TopoServer(), "VerticalSplitDiff", keyspace, shard, opts.reportDir, opts.reportToTopo, opts.samplePercent),
+		resultsWriter:   newDiffResultsWriter(wr, "VerticalSplitDiff", keyspace, shard, opts.diffResultsDir, opts.diffResultsToTable),
+		tableStatusList: &tableStatusList{action: "diff", keyspace: keyspace, shard: shard},
+		cleaner:         newCleaner(wr, "VerticalSplitDiff", keyspace, shard),
+	}, nil
 }
 
 // StatusAsHTML is part of the Worker interface.
@@ -87,14 +108,25 @@ func (vsdw *VerticalSplitDiffWorker) StatusAsHTML() template.HTML {
 	result := "<b>Working on:</b> " + vsdw.keyspace + "/" + vsdw.shard + "</br>\n"
 	result += "<b>State:</b> " + state.String() + "</br>\n"
 	switch state {
-	case WorkerStateDiff:
-		result += "<b>Running</b>:</br>\n"
-	case WorkerStateDiffWillFail:
-		result += "<b>Running - have already found differences...</b></br>\n"
+	case WorkerStateDiff, WorkerStateDiffWillFail:
+		if state == WorkerStateDiff {
+			result += "<b>Running</b>:</br>\n"
+		} else {
+			result += "<b>Running - have already found differences...</b></br>\n"
+		}
+		result += "<b>Progress:</b> " + vsdw.tableStatusList.formatProgress() + "</br>\n"
+		_, eta := vsdw.tableStatusList.format()
+		result += "<b>ETA:</b> " + eta.String() + "</br>\n"
+		result += string(vsdw.tableStatusList.formatHTML())
 	case WorkerStateDone:
 		result += "<b>Success</b>:</br>\n"
+		result += string(vsdw.tableStatusList.formatHTML())
+	case WorkerStateError:
+		// Show which tables failed.
+		result += string(vsdw.tableStatusList.formatHTML())
 	}
 
+	result += vsdw.formatPanicHTML()
 	return template.HTML(result)
 }
 
@@ -105,20 +137,34 @@ func (vsdw *VerticalSplitDiffWorker) StatusAsText() string {
 	result := "Working on: " + vsdw.keyspace + "/" + vsdw.shard + "\n"
 	result += "State: " + state.String() + "\n"
 	switch state {
-	case WorkerStateDiff:
-		result += "Running...\n"
-	case WorkerStateDiffWillFail:
-		result += "Running - have already found differences...\n"
+	case WorkerStateDiff, WorkerStateDiffWillFail:
+		if state == WorkerStateDiff {
+			result += "Running...\n"
+		} else {
+			result += "Running - have already found differences...\n"
+		}
+		result += "Progress: " + vsdw.tableStatusList.formatProgress() + "\n"
+		statuses, eta := vsdw.tableStatusList.format()
+		result += "ETA: " + eta.String() + "\n"
+		result += strings.Join(statuses, "\n")
 	case WorkerStateDone:
 		result += "Success.\n"
+		statuses, _ := vsdw.tableStatusList.format()
+		result += strings.Join(statuses, "\n")
 	}
+	result += vsdw.formatPanicText()
 	return result
 }
 
+// progress is part of the progressReporter interface.
+func (vsdw *VerticalSplitDiffWorker) progress() []tableProgress {
+	return vsdw.tableStatusList.progress()
+}
+
 // Run is mostly a wrapper to run the cleanup at the end.
 func (vsdw *VerticalSplitDiffWorker) Run(ctx context.Context) error {
-	resetVars()
-	err := vsdw.run(ctx)
+	vsdw.resetRunVars()
+	err := vsdw.runRecovered(ctx, vsdw.run)
 
 	vsdw.SetState(WorkerStateCleanUp)
 	cerr := vsdw.cleaner.CleanUp(vsdw.wr)
@@ -129,6 +175,10 @@ func (vsdw *VerticalSplitDiffWorker) Run(ctx context.Context) error {
 			err = cerr
 		}
 	}
+	if err == nil && *waitForServingTimeout != 0 {
+		vsdw.SetState(WorkerStateWaitForServing)
+		err = waitForServingTablets(ctx, vsdw.wr, vsdw.cleaner.ChangedTablets(), *waitForServingTimeout)
+	}
 	if err != nil {
 		vsdw.SetState(WorkerStateError)
 		return err
@@ -154,6 +204,14 @@ func (vsdw *VerticalSplitDiffWorker) run(ctx context.Context) error {
 		return err
 	}
 
+	// validate the chosen tablets before we stop any replication
+	if err := vsdw.checkTargets(ctx); err != nil {
+		return vterrors.Wrap(err, "checkTargets() failed")
+	}
+	if err := checkDone(ctx); err != nil {
+		return err
+	}
+
 	// third phase: synchronize replication
 	if err := vsdw.synchronizeReplication(ctx); err != nil {
 		return vterrors.Wrap(err, "synchronizeReplication() failed")
@@ -170,6 +228,10 @@ func (vsdw *VerticalSplitDiffWorker) run(ctx context.Context) error {
 		return err
 	}
 
+	if verifiesShard(vsdw.tables, vsdw.where, vsdw.samplePercent, vsdw.repair) {
+		ss := vsdw.shardInfo.SourceShards[0]
+		writeDiffVerification(ctx, vsdw.wr, "VerticalSplitDiff", vsdw.keyspace, vsdw.shard, ss.Keyspace, ss.Shard, vsdw.destinationMasterPosition)
+	}
 	return nil
 }
 
@@ -209,30 +271,44 @@ func (vsdw *VerticalSplitDiffWorker) init(ctx context.Context) error {
 
 // findTargets phase:
 // - find one destinationTabletType in destination shard
-// - find one rdonly per source shard
+// - find one sourceTabletType per source shard
 // - mark them all as 'worker' pointing back to us
 func (vsdw *VerticalSplitDiffWorker) findTargets(ctx context.Context) error {
 	vsdw.SetState(WorkerStateFindTargets)
 
 	// find an appropriate tablet in destination shard
 	var err error
-	vsdw.destinationAlias, err = FindWorkerTablet(
-		ctx,
-		vsdw.wr,
-		vsdw.cleaner,
-		nil, /* tsc */
-		vsdw.cell,
-		vsdw.keyspace,
-		vsdw.shard,
-		1, /* minHealthyTablets */
-		vsdw.destinationTabletType,
-	)
-	if err != nil {
-		return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", vsdw.cell, vsdw.keyspace, vsdw.shard)
+	if vsdw.destinationTabletAlias != nil {
+		vsdw.destinationAlias = vsdw.destinationTabletAlias
+		if err := UseWorkerTablet(ctx, vsdw.wr, vsdw.cleaner, vsdw.destinationAlias, vsdw.keyspace, vsdw.shard, vsdw.destinationTabletType); err != nil {
+			return vterrors.Wrapf(err, "UseWorkerTablet() failed for %v", topoproto.TabletAliasString(vsdw.destinationAlias))
+		}
+	} else {
+		vsdw.destinationAlias, err = FindWorkerTablet(
+			ctx,
+			vsdw.wr,
+			vsdw.cleaner,
+			nil, /* tsc */
+			vsdw.cell,
+			vsdw.keyspace,
+			vsdw.shard,
+			1, /* minHealthyTablets */
+			vsdw.destinationTabletType,
+		)
+		if err != nil {
+			return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", vsdw.cell, vsdw.keyspace, vsdw.shard)
+		}
 	}
 
 	// find an appropriate tablet in the source shard
-	vsdw.sourceAlias, err = FindWorkerTablet(ctx, vsdw.wr, vsdw.cleaner, nil /* tsc */, vsdw.cell, vsdw.shardInfo.SourceShards[0].Keyspace, vsdw.shardInfo.SourceShards[0].Shard, vsdw.minHealthyRdonlyTablets, topodatapb.TabletType_RDONLY)
+	if vsdw.sourceTabletAlias != nil {
+		vsdw.sourceAlias = vsdw.sourceTabletAlias
+		if err := UseWorkerTablet(ctx, vsdw.wr, vsdw.cleaner, vsdw.sourceAlias, vsdw.shardInfo.SourceShards[0].Keyspace, vsdw.shardInfo.SourceShards[0].Shard, vsdw.sourceTabletType); err != nil {
+			return vterrors.Wrapf(err, "UseWorkerTablet() failed for %v", topoproto.TabletAliasString(vsdw.sourceAlias))
+		}
+		return nil
+	}
+	vsdw.sourceAlias, err = FindWorkerTablet(ctx, vsdw.wr, vsdw.cleaner, nil /* tsc */, vsdw.cell, vsdw.shardInfo.SourceShards[0].Keyspace, vsdw.shardInfo.SourceShards[0].Shard, vsdw.minHealthyRdonlyTablets, vsdw.sourceTabletType)
 	if err != nil {
 		return vterrors.Wrapf(err, "FindWorkerTablet() failed for %v/%v/%v", vsdw.cell, vsdw.shardInfo.SourceShards[0].Keyspace, vsdw.shardInfo.SourceShards[0].Shard)
 	}
@@ -240,6 +316,15 @@ func (vsdw *VerticalSplitDiffWorker) findTargets(ctx context.Context) error {
 	return nil
 }
 
+// checkTargets phase:
+// - run the pre-flight checks on the destination master(s) and the chosen
+//   source and destination tablets (see checkTabletsHealthy())
+func (vsdw *VerticalSplitDiffWorker) checkTargets(ctx context.Context) error {
+	return checkTabletsHealthy(ctx, vsdw.wr,
+		[]*topodatapb.TabletAlias{vsdw.shardInfo.MasterAlias},
+		[]*topodatapb.TabletAlias{vsdw.sourceAlias, vsdw.destinationAlias})
+}
+
 // synchronizeReplication phase:
 // 1 - ask the master of the destination shard to pause filtered replication,
 //   and return the source binlog positions
@@ -256,12 +341,17 @@ func (vsdw *VerticalSplitDiffWorker) findTargets(ctx context.Context) error {
 //    the existing ChangeSlaveType cleanup action to 'spare' type)
 // 5 - restart filtered replication on destination master.
 //   (remove the cleanup task that does the same)
+//   With --repair_execute, filtered replication stays stopped until the
+//   repair statements were executed and the cleanup task restarts it.
 // At this point, all source and destination tablets are stopped at the same point.
+// With --use_consistent_snapshot, transactions are opened on the source and
+// destination tablet while they are stopped. Their replication is restarted
+// right afterwards and the diff reads from the transactions.
 
 func (vsdw *VerticalSplitDiffWorker) synchronizeReplication(ctx context.Context) error {
 	vsdw.SetState(WorkerStateSyncReplication)
 
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	masterInfo, err := vsdw.wr.TopoServer().GetTablet(shortCtx, vsdw.shardInfo.MasterAlias)
 	if err != nil {
@@ -272,7 +362,7 @@ func (vsdw *VerticalSplitDiffWorker) synchronizeReplication(ctx context.Context)
 
 	// 1 - stop the master binlog replication, get its current position
 	vsdw.wr.Logger().Infof("Stopping master binlog replication on %v", topoproto.TabletAliasString(vsdw.shardInfo.MasterAlias))
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, stopBlpTimeoutFor(ctx))
 	defer cancel()
 	_, err = vsdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StopVReplication(ss.Uid, "for split diff"))
 	if err != nil {
@@ -291,13 +381,13 @@ func (vsdw *VerticalSplitDiffWorker) synchronizeReplication(ctx context.Context)
 
 	// stop replication
 	vsdw.wr.Logger().Infof("Stopping slave %v at a minimum of %v", topoproto.TabletAliasString(vsdw.sourceAlias), vreplicationPos)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
 	defer cancel()
 	sourceTablet, err := vsdw.wr.TopoServer().GetTablet(shortCtx, vsdw.sourceAlias)
 	if err != nil {
 		return err
 	}
-	mysqlPos, err := vsdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, sourceTablet.Tablet, vreplicationPos, *remoteActionsTimeout)
+	mysqlPos, err := vsdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, sourceTablet.Tablet, vreplicationPos, stopSlaveMinimumTimeoutFor(ctx))
 	if err != nil {
 		return vterrors.Wrapf(err, "cannot stop slave %v at right binlog position %v", topoproto.TabletAliasString(vsdw.sourceAlias), vreplicationPos)
 	}
@@ -306,10 +396,16 @@ func (vsdw *VerticalSplitDiffWorker) synchronizeReplication(ctx context.Context)
 	// to StartSlave() + ChangeSlaveType(spare)
 	wrangler.RecordStartSlaveAction(vsdw.cleaner, sourceTablet.Tablet)
 
+	if vsdw.useConsistentSnapshot {
+		if vsdw.sourceSnapshot, err = vsdw.openSnapshot(ctx, sourceTablet.Tablet); err != nil {
+			return err
+		}
+	}
+
 	// 3 - ask the master of the destination shard to resume filtered
 	//     replication up to the new list of positions
 	vsdw.wr.Logger().Infof("Restarting master %v until it catches up to %v", topoproto.TabletAliasString(vsdw.shardInfo.MasterAlias), mysqlPos)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	_, err = vsdw.wr.TabletManagerClient().VReplicationExec(shortCtx, masterInfo.Tablet, binlogplayer.StartVReplicationUntil(ss.Uid, mysqlPos))
 	if err != nil {
@@ -322,27 +418,42 @@ func (vsdw *VerticalSplitDiffWorker) synchronizeReplication(ctx context.Context)
 	if err != nil {
 		return vterrors.Wrapf(err, "MasterPosition for %v failed", vsdw.shardInfo.MasterAlias)
 	}
+	vsdw.destinationMasterPosition = masterPos
 
 	// 4 - wait until the destination tablet is equal or passed
 	//     that master binlog position, and stop its replication.
 	vsdw.wr.Logger().Infof("Waiting for destination tablet %v to catch up to %v", topoproto.TabletAliasString(vsdw.destinationAlias), masterPos)
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	destinationTablet, err := vsdw.wr.TopoServer().GetTablet(shortCtx, vsdw.destinationAlias)
 	if err != nil {
 		return err
 	}
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, stopSlaveMinimumTimeoutFor(ctx))
 	defer cancel()
-	_, err = vsdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, destinationTablet.Tablet, masterPos, *remoteActionsTimeout)
+	_, err = vsdw.wr.TabletManagerClient().StopSlaveMinimum(shortCtx, destinationTablet.Tablet, masterPos, stopSlaveMinimumTimeoutFor(ctx))
 	if err != nil {
 		return vterrors.Wrapf(err, "StopSlaveMinimum on %v at %v failed", topoproto.TabletAliasString(vsdw.destinationAlias), masterPos)
 	}
 	wrangler.RecordStartSlaveAction(vsdw.cleaner, destinationTablet.Tablet)
 
+	if vsdw.useConsistentSnapshot {
+		if vsdw.destinationSnapshot, err = vsdw.openSnapshot(ctx, destinationTablet.Tablet); err != nil {
+			return err
+		}
+	}
+
 	// 5 - restart filtered replication on destination master
+	if vsdw.repairExecute {
+		// The repair statements are computed from the stopped tablets. They
+		// must be executed on the destination master while it is still at the
+		// same position. Otherwise, they would overwrite newer changes of
+		// filtered replication. The cleanup task restarts it at the end.
+		vsdw.wr.Logger().Infof("Keeping filtered replication stopped on master %v until the differences were repaired (see --repair_execute)", topoproto.TabletAliasString(vsdw.shardInfo.MasterAlias))
+		return nil
+	}
 	vsdw.wr.Logger().Infof("Restarting filtered replication on master %v", topoproto.TabletAliasString(vsdw.shardInfo.MasterAlias))
-	shortCtx, cancel = context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel = context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	defer cancel()
 	if _, err = vsdw.wr.TabletManagerClient().VReplicationExec(ctx, masterInfo.Tablet, binlogplayer.StartVReplication(ss.Uid)); err != nil {
 		return vterrors.Wrapf(err, "VReplicationExec(start) failed for %v", vsdw.shardInfo.MasterAlias)
@@ -351,6 +462,29 @@ func (vsdw *VerticalSplitDiffWorker) synchronizeReplication(ctx context.Context)
 	return nil
 }
 
+// openSnapshot opens a consistentSnapshot on the stopped "tablet" and
+// restarts its replication afterwards.
+func (vsdw *VerticalSplitDiffWorker) openSnapshot(ctx context.Context, tablet *topodatapb.Tablet) (*consistentSnapshot, error) {
+	alias := topoproto.TabletAliasString(tablet.Alias)
+	vsdw.wr.Logger().Infof("Opening consistent snapshot on %v", alias)
+	// Each chunk pipeline needs its own transaction.
+	snapshot, err := openConsistentSnapshot(ctx, vsdw.wr, tablet.Alias, vsdw.parallelDiffsCount*vsdw.chunkCount)
+	if err != nil {
+		return nil, err
+	}
+	vsdw.cleaner.Record("CloseConsistentSnapshot", alias, func(ctx context.Context, wr *wrangler.Wrangler) error {
+		return snapshot.close(ctx)
+	})
+
+	vsdw.wr.Logger().Infof("Restarting replication on %v", alias)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	defer cancel()
+	if err := vsdw.wr.TabletManagerClient().StartSlave(shortCtx, tablet); err != nil {
+		return nil, vterrors.Wrapf(err, "StartSlave for %v failed", alias)
+	}
+	return snapshot, nil
+}
+
 // diff phase: will create a list of messages regarding the diff.
 // - get the schema on all tablets
 // - if some table schema mismatches, record them (use existing schema diff tools).
@@ -365,9 +499,9 @@ func (vsdw *VerticalSplitDiffWorker) diff(ctx context.Context) error {
 	wg.Add(1)
 	go func() {
 		var err error
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
 		vsdw.destinationSchemaDefinition, err = vsdw.wr.GetSchema(
-			shortCtx, vsdw.destinationAlias, vsdw.shardInfo.SourceShards[0].Tables, nil /* excludeTables */, false /* includeViews */)
+			shortCtx, vsdw.destinationAlias, vsdw.shardInfo.SourceShards[0].Tables, nil /* excludeTables */, vsdw.includeViews)
 		cancel()
 		if err != nil {
 			vsdw.markAsWillFail(rec, err)
@@ -378,9 +512,9 @@ func (vsdw *VerticalSplitDiffWorker) diff(ctx context.Context) error {
 	wg.Add(1)
 	go func() {
 		var err error
-		shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+		shortCtx, cancel := context.WithTimeout(ctx, getSchemaTimeoutFor(ctx))
 		vsdw.sourceSchemaDefinition, err = vsdw.wr.GetSchema(
-			shortCtx, vsdw.sourceAlias, vsdw.shardInfo.SourceShards[0].Tables, nil /* excludeTables */, false /* includeViews */)
+			shortCtx, vsdw.sourceAlias, vsdw.shardInfo.SourceShards[0].Tables, nil /* excludeTables */, vsdw.includeViews)
 		cancel()
 		if err != nil {
 			vsdw.markAsWillFail(rec, err)
@@ -393,6 +527,21 @@ func (vsdw *VerticalSplitDiffWorker) diff(ctx context.Context) error {
 		return rec.Error()
 	}
 
+	// Restrict the diff to the tables given by --tables. The source shard
+	// Tables list remains the upper bound.
+	if len(vsdw.tables) > 0 {
+		var err error
+		if vsdw.destinationSchemaDefinition, err = tmutils.FilterTables(vsdw.destinationSchemaDefinition, vsdw.tables, nil /* excludeTables */, vsdw.includeViews); err != nil {
+			return vterrors.Wrap(err, "cannot filter the destination schema by --tables")
+		}
+		if vsdw.sourceSchemaDefinition, err = tmutils.FilterTables(vsdw.sourceSchemaDefinition, vsdw.tables, nil /* excludeTables */, vsdw.includeViews); err != nil {
+			return vterrors.Wrap(err, "cannot filter the source schema by --tables")
+		}
+	}
+	if err := checkTablesMatched(vsdw.tables, vsdw.destinationSchemaDefinition); err != nil {
+		return err
+	}
+
 	// Check the schema
 	vsdw.wr.Logger().Infof("Diffing the schema...")
 	rec = &concurrency.AllErrorRecorder{}
@@ -403,62 +552,245 @@ func (vsdw *VerticalSplitDiffWorker) diff(ctx context.Context) error {
 		vsdw.wr.Logger().Infof("Schema match, good.")
 	}
 
-	// run the diffs, 8 at a time
-	vsdw.wr.Logger().Infof("Running the diffs...")
+	if vsdw.where != "" {
+		vsdw.wr.Logger().Infof("Comparing only the rows which match: %v", vsdw.where)
+	}
+	if vsdw.samplePercent < 100 {
+		vsdw.wr.Logger().Infof("Comparing only a sample of %v%% of the rows", vsdw.samplePercent)
+	}
+	if vsdw.comparison.hashColumnsLargerThan > 0 {
+		vsdw.wr.Logger().Infof("Comparing BLOB and TEXT values larger than %v bytes by their MD5 hash", vsdw.comparison.hashColumnsLargerThan)
+	}
+	if vsdw.comparison.normalizeCharset != "" {
+		vsdw.wr.Logger().Infof("Converting text values to the character set %v before comparing them", vsdw.comparison.normalizeCharset)
+	}
+	if vsdw.comparison.normalizeTimestamps {
+		vsdw.wr.Logger().Infof("Comparing TIMESTAMP values as UNIX timestamps")
+	}
+	for table, columns := range vsdw.comparison.ignoreColumns {
+		vsdw.wr.Logger().Infof("Ignoring the columns %v of table %v", strings.Join(columns, ", "), table)
+	}
+	if vsdw.comparison.floatEpsilon > 0 {
+		vsdw.wr.Logger().Infof("Ignoring differences of FLOAT and DOUBLE values up to %v", vsdw.comparison.floatEpsilon)
+	}
+
+	if err := vsdw.resultsWriter.open(ctx, vsdw.shardInfo.MasterAlias); err != nil {
+		return err
+	}
+
+	// run the diffs, parallelDiffsCount at a time
+	vsdw.wr.Logger().Infof("Running the diffs (%v tables in parallel)...", vsdw.parallelDiffsCount)
 	sem := sync2.NewSemaphore(vsdw.parallelDiffsCount, 0)
-	for _, tableDefinition := range vsdw.destinationSchemaDefinition.TableDefinitions {
+	vsdw.tableStatusList.initialize(vsdw.destinationSchemaDefinition)
+
+	// The chunks are computed on the destination because the table
+	// definitions (and their row count estimates) are from there as well.
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
+	destinationTablet, err := vsdw.wr.TopoServer().GetTablet(shortCtx, vsdw.destinationAlias)
+	cancel()
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot read destination tablet %v", topoproto.TabletAliasString(vsdw.destinationAlias))
+	}
+
+	for tableIndex, tableDefinition := range vsdw.destinationSchemaDefinition.TableDefinitions {
 		wg.Add(1)
-		go func(tableDefinition *tabletmanagerdatapb.TableDefinition) {
+		go func(tableIndex int, tableDefinition *tabletmanagerdatapb.TableDefinition) {
 			defer wg.Done()
+			defer vsdw.recoverPanic(func(err error) {
+				vsdw.markAsWillFail(rec, err)
+			})
 			sem.Acquire()
 			defer sem.Release()
 
-			vsdw.wr.Logger().Infof("Starting the diff on table %v", tableDefinition.Name)
-			sourceQueryResultReader, err := TableScan(ctx, vsdw.wr.Logger(), vsdw.wr.TopoServer(), vsdw.sourceAlias, tableDefinition)
-			if err != nil {
-				newErr := vterrors.Wrap(err, "TableScan(source) failed")
-				vsdw.markAsWillFail(rec, newErr)
-				vsdw.wr.Logger().Errorf("%v", newErr)
+			if tableDefinition.Type == tmutils.TableView {
+				// Views have no rows of their own. Their definition was
+				// already compared by the schema diff.
+				vsdw.wr.Logger().Infof("Skipping the row diff on view %v", tableDefinition.Name)
 				return
 			}
-			defer sourceQueryResultReader.Close(ctx)
 
-			destinationQueryResultReader, err := TableScan(ctx, vsdw.wr.Logger(), vsdw.wr.TopoServer(), vsdw.destinationAlias, tableDefinition)
-			if err != nil {
-				newErr := vterrors.Wrap(err, "TableScan(destination) failed")
-				vsdw.markAsWillFail(rec, newErr)
-				vsdw.wr.Logger().Errorf("%v", newErr)
-				return
-			}
-			defer destinationQueryResultReader.Close(ctx)
+			vsdw.wr.Logger().Infof("Starting the diff on table %v", tableDefinition.Name)
 
-			differ, err := NewRowDiffer(sourceQueryResultReader, destinationQueryResultReader, tableDefinition)
+			// A table without a primary key is compared by all columns. The
+			// original definition is used to split it into chunks though
+			// because the leading column may be NULL and would not match any
+			// chunk.
+			originalTableDefinition := tableDefinition
+			tableDefinition, err := diffTableDefinition(originalTableDefinition, vsdw.pklessTablesUseFullRow, vsdw.comparison.ignoreColumns[originalTableDefinition.Name])
 			if err != nil {
-				newErr := vterrors.Wrap(err, "NewRowDiffer() failed")
-				vsdw.markAsWillFail(rec, newErr)
-				vsdw.wr.Logger().Errorf("%v", newErr)
+				vsdw.writeDiffReport(ctx, rec, originalTableDefinition.Name, DiffReport{}, err)
+				vsdw.tableStatusList.tableFailed(tableIndex, err)
+				vsdw.markAsWillFail(rec, err)
+				vsdw.wr.Logger().Errorf("%v", err)
 				return
 			}
+			tableChecksumOnly := vsdw.checksumOnly
+			if tableChecksumOnly && len(originalTableDefinition.PrimaryKeyColumns) == 0 {
+				vsdw.wr.Logger().Warningf("Checksum mode is not supported for table %v without a primary key. Running a full diff instead.", tableDefinition.Name)
+				tableChecksumOnly = false
+			}
 
-			report, err := differ.Go(vsdw.wr.Logger())
-			if err != nil {
-				vsdw.wr.Logger().Errorf("Differ.Go failed: %v", err)
-			} else {
-				if report.HasDifferences() {
-					err := fmt.Errorf("Table %v has differences: %v", tableDefinition.Name, report.String())
+			if vsdw.rowCountCheck {
+				if err := checkRowCount(ctx, vsdw.wr, vsdw.sourceAlias, vsdw.destinationAlias, tableDefinition, vsdw.rowFilter(tableDefinition), vsdw.rowFilter(tableDefinition)); err != nil {
+					vsdw.writeDiffReport(ctx, rec, tableDefinition.Name, DiffReport{}, err)
+					vsdw.tableStatusList.tableFailed(tableIndex, err)
 					vsdw.markAsWillFail(rec, err)
 					vsdw.wr.Logger().Errorf("%v", err)
-				} else {
-					vsdw.wr.Logger().Infof("Table %v checks out (%v rows processed, %v qps)", tableDefinition.Name, report.processedRows, report.processingQPS)
+					return
+				}
+			}
+
+			var repairer *rowRepairer
+			report, err := retryTableDiff(ctx, vsdw.wr.Logger(), tableDefinition.Name, vsdw.tableRetryCount, vsdw.tableRetryBackoff, func() (report DiffReport, err error) {
+				// A failed attempt must not leave its progress or repair
+				// statements behind.
+				vsdw.tableStatusList.resetProgress(tableIndex)
+				if vsdw.repair {
+					repairer, err = newRowRepairerForTablet(ctx, vsdw.wr, vsdw.destinationAlias, tableDefinition, vsdw.repairMaxRows)
+					if err != nil {
+						return DiffReport{}, vterrors.Wrap(err, "newRowRepairerForTablet() failed")
+					}
+				}
+				results, err := vsdw.resultsWriter.newRecorder(ctx, tableDefinition)
+				if err != nil {
+					return DiffReport{}, vterrors.Wrap(err, "newRecorder() failed")
 				}
+				defer func() {
+					if closeErr := results.close(); closeErr != nil && err == nil {
+						err = closeErr
+					}
+				}()
+
+				if tableChecksumOnly {
+					vsdw.tableStatusList.setThreadCount(tableIndex, 1)
+					vsdw.tableStatusList.threadStarted(tableIndex)
+					defer vsdw.tableStatusList.threadDone(tableIndex)
+					report, err := checksumDiffTable(ctx, vsdw.wr, &vsdw.StatusWorker, vsdw.sourceAlias, vsdw.destinationAlias, tableDefinition, vsdw.rowFilter(tableDefinition), vsdw.rowFilter(tableDefinition), repairer, results, vsdw.comparison, vsdw.chunkCount, vsdw.minRowsPerChunk)
+					if err != nil {
+						return report, vterrors.Wrap(err, "checksumDiffTable() failed")
+					}
+					return report, nil
+				}
+
+				// Split the table into chunks which are diffed in parallel.
+				chunks, err := generateChunks(ctx, vsdw.wr, destinationTablet.Tablet, originalTableDefinition, vsdw.chunkCount, vsdw.minRowsPerChunk)
+				if err != nil {
+					return DiffReport{}, vterrors.Wrapf(err, "failed to split table %v into chunks", tableDefinition.Name)
+				}
+				vsdw.tableStatusList.setThreadCount(tableIndex, len(chunks))
+				return diffChunksInParallel(&vsdw.StatusWorker, chunks, func(c chunk) (DiffReport, error) {
+					vsdw.tableStatusList.threadStarted(tableIndex)
+					defer vsdw.tableStatusList.threadDone(tableIndex)
+					return vsdw.diffChunk(ctx, tableIndex, tableDefinition, c, repairer, results)
+				})
+			})
+			vsdw.writeDiffReport(ctx, rec, tableDefinition.Name, report, err)
+			if err != nil {
+				vsdw.tableStatusList.tableFailed(tableIndex, err)
+				vsdw.markAsWillFail(rec, err)
+				vsdw.wr.Logger().Errorf("%v", err)
+				return
+			}
+			if report.HasDifferences() {
+				vsdw.tableStatusList.setDifferentRows(tableIndex, report)
+				vsdw.handleDifferences(ctx, rec, tableIndex, tableDefinition, report, repairer)
+			} else if tableChecksumOnly {
+				vsdw.wr.Logger().Infof("Table %v checks out (%v rows compared row by row after a checksum mismatch)", tableDefinition.Name, report.processedRows)
+			} else {
+				vsdw.wr.Logger().Infof("Table %v checks out (%v rows processed, %v qps)", tableDefinition.Name, report.processedRows, report.processingQPS)
 			}
-		}(tableDefinition)
+		}(tableIndex, tableDefinition)
 	}
 	wg.Wait()
 
 	return rec.Error()
 }
 
+// diffChunk runs a row by row comparison of chunk "c" of table "td".
+func (vsdw *VerticalSplitDiffWorker) diffChunk(ctx context.Context, tableIndex int, td *tabletmanagerdatapb.TableDefinition, c chunk, repairer *rowRepairer, results *diffResultsRecorder) (DiffReport, error) {
+	if err := vsdw.waitIfPaused(ctx); err != nil {
+		return DiffReport{}, err
+	}
+
+	sourceQueryResultReader, err := vsdw.tableScan(ctx, vsdw.sourceAlias, vsdw.sourceSnapshot, td, c)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "TableScan(source) failed")
+	}
+	defer sourceQueryResultReader.Close(ctx)
+
+	destinationQueryResultReader, err := vsdw.tableScan(ctx, vsdw.destinationAlias, vsdw.destinationSnapshot, td, c)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "TableScan(destination) failed")
+	}
+	defer destinationQueryResultReader.Close(ctx)
+
+	differ, err := NewRowDiffer(
+		newPausableResultReader(ctx, &vsdw.StatusWorker, sourceQueryResultReader),
+		newPausableResultReader(ctx, &vsdw.StatusWorker, destinationQueryResultReader),
+		td)
+	if err != nil {
+		return DiffReport{}, vterrors.Wrap(err, "NewRowDiffer() failed")
+	}
+	differ.repairer = repairer
+	differ.results = results
+	differ.tableStatusList = vsdw.tableStatusList
+	differ.tableIndex = tableIndex
+	differ.floatEpsilon = vsdw.comparison.floatEpsilon
+
+	report, err := differ.Go(vsdw.wr.Logger())
+	if err != nil {
+		return report, fmt.Errorf("Differ.Go failed: %v", err)
+	}
+	return report, nil
+}
+
+// rowFilter returns the condition which selects the rows of "td" which are
+// compared. It combines --where and --sample_percent.
+func (vsdw *VerticalSplitDiffWorker) rowFilter(td *tabletmanagerdatapb.TableDefinition) string {
+	return joinConditions(vsdw.where, sampleCondition(td, vsdw.samplePercent))
+}
+
+// tableScan returns a reader for all rows of chunk "c" of "td" on the tablet
+// "alias". If "snapshot" is set, the rows are read from it instead of a
+// streaming query.
+func (vsdw *VerticalSplitDiffWorker) tableScan(ctx context.Context, alias *topodatapb.TabletAlias, snapshot *consistentSnapshot, td *tabletmanagerdatapb.TableDefinition, c chunk) (closableResultReader, error) {
+	where := vsdw.rowFilter(td)
+	if snapshot != nil {
+		conditions := chunkWhereClauses(td, c)
+		if where != "" {
+			conditions = append(conditions, where)
+		}
+		return snapshot.tableScan(ctx, td, strings.Join(conditions, " AND "), nil /* keyRange */, nil /* keyspaceSchema */, vsdw.comparison)
+	}
+	return tableScanChunk(ctx, vsdw.wr, alias, td, c, where, vsdw.comparison)
+}
+
+// handleDifferences is called for a table with differences. If --repair is
+// set, it tries to repair them. Otherwise, or if the repair fails, the diff
+// will fail.
+func (vsdw *VerticalSplitDiffWorker) handleDifferences(ctx context.Context, rec concurrency.ErrorRecorder, tableIndex int, td *tabletmanagerdatapb.TableDefinition, report DiffReport, repairer *rowRepairer) {
+	if repairer != nil {
+		err := repairTable(ctx, vsdw.wr, vsdw.shardInfo.MasterAlias, td.Name, repairer, vsdw.repairExecute)
+		if err == nil {
+			vsdw.wr.Logger().Warningf("Table %v had differences which were repaired: %v", td.Name, report.String())
+			return
+		}
+		vsdw.wr.Logger().Errorf("%v", err)
+	}
+	err := fmt.Errorf("Table %v has differences: %v", td.Name, report.String())
+	vsdw.tableStatusList.tableFailed(tableIndex, err)
+	vsdw.markAsWillFail(rec, err)
+	vsdw.wr.Logger().Errorf("%v", err)
+}
+
+// writeDiffReport saves the machine-readable report of a table, if enabled.
+func (vsdw *VerticalSplitDiffWorker) writeDiffReport(ctx context.Context, rec concurrency.ErrorRecorder, table string, report DiffReport, diffErr error) {
+	if err := vsdw.reportWriter.write(ctx, table, report, diffErr); err != nil {
+		vsdw.markAsWillFail(rec, err)
+		vsdw.wr.Logger().Errorf("%v", err)
+	}
+}
+
 // markAsWillFail records the error and changes the state of the worker to reflect this
 func (vsdw *VerticalSplitDiffWorker) markAsWillFail(er concurrency.ErrorRecorder, err error) {
 	er.RecordError(err)
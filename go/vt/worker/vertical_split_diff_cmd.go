@@ -22,7 +22,9 @@ import (
 	"html/template"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"vitess.io/vitess/go/vt/vterrors"
 
@@ -61,10 +63,58 @@ const verticalSplitDiffHTML2 = `
   <h1>Vertical Split Diff Action</h1>
     <form action="/Diffs/VerticalSplitDiff" method="post">
 			<INPUT type="hidden" name="keyspace" value="{{.Keyspace}}"/>
+      <LABEL for="tables">Tables (optional, comma separated, exact names or /regexp/, default: all tables of the source shard): </LABEL>
+        <INPUT type="text" id="tables" name="tables" value=""></BR>
 			<LABEL for="minHealthyRdonlyTablets">Minimum Number of required healthy RDONLY tablets: </LABEL>
         <INPUT type="text" id="minHealthyRdonlyTablets" name="minHealthyRdonlyTablets" value="{{.DefaultMinHealthyRdonlyTablets}}"></BR>
       <LABEL for="parallelDiffsCount">Number of tables to diff in parallel: </LABEL>
         <INPUT type="text" id="parallelDiffsCount" name="parallelDiffsCount" value="{{.DefaultParallelDiffsCount}}"></BR>
+      <LABEL for="chunkCount">Number of chunks per table which are diffed in parallel: </LABEL>
+        <INPUT type="text" id="chunkCount" name="chunkCount" value="{{.DefaultChunkCount}}"></BR>
+      <LABEL for="minRowsPerChunk">Minimun Number of Rows per Chunk (may reduce the Chunk Count): </LABEL>
+        <INPUT type="text" id="minRowsPerChunk" name="minRowsPerChunk" value="{{.DefaultMinRowsPerChunk}}"></BR>
+      <LABEL for="tableRetryCount">Number of retries of a failed table diff: </LABEL>
+        <INPUT type="text" id="tableRetryCount" name="tableRetryCount" value="{{.DefaultTableRetryCount}}"></BR>
+      <LABEL for="tableRetryBackoff">Delay before the first retry of a table diff (doubles after each retry): </LABEL>
+        <INPUT type="text" id="tableRetryBackoff" name="tableRetryBackoff" value="{{.DefaultTableRetryBackoff}}"></BR>
+      <LABEL for="where">Compare only rows which match this SQL predicate (optional, applied to all tables): </LABEL>
+        <INPUT type="text" id="where" name="where" value=""></BR>
+      <LABEL for="samplePercent">Percentage of rows to compare (a deterministic sample based on the primary key): </LABEL>
+        <INPUT type="text" id="samplePercent" name="samplePercent" value="{{.DefaultSamplePercent}}"></BR>
+      <LABEL for="includeViews">Compare the definitions of views as well (views have no row diff): </LABEL>
+        <INPUT type="checkbox" id="includeViews" name="includeViews" value="true"{{if .DefaultIncludeViews}} checked{{end}}></BR>
+      <LABEL for="pklessTablesUseFullRow">Compare tables without a primary key by all columns: </LABEL>
+        <INPUT type="checkbox" id="pklessTablesUseFullRow" name="pklessTablesUseFullRow" value="true"{{if .DefaultPKLessTablesUseFullRow}} checked{{end}}></BR>
+      <LABEL for="rowCountCheck">Compare the row counts first and skip the row diff for tables whose row counts differ: </LABEL>
+        <INPUT type="checkbox" id="rowCountCheck" name="rowCountCheck" value="true"{{if .DefaultRowCountCheck}} checked{{end}}></BR>
+      <LABEL for="checksumOnly">Compare checksums per chunk first and compare rows only for chunks with a different checksum: </LABEL>
+        <INPUT type="checkbox" id="checksumOnly" name="checksumOnly" value="true"{{if .DefaultChecksumOnly}} checked{{end}}></BR>
+      <LABEL for="repair">Generate statements which repair the differences on the destination: </LABEL>
+        <INPUT type="checkbox" id="repair" name="repair" value="true"{{if .DefaultRepair}} checked{{end}}></BR>
+      <LABEL for="repairExecute">Execute the repair statements on the destination master: </LABEL>
+        <INPUT type="checkbox" id="repairExecute" name="repairExecute" value="true"{{if .DefaultRepairExecute}} checked{{end}}></BR>
+      <LABEL for="repairMaxRows">Maximum number of rows per table which may be repaired: </LABEL>
+        <INPUT type="text" id="repairMaxRows" name="repairMaxRows" value="{{.DefaultRepairMaxRows}}"></BR>
+      <LABEL for="hashColumnsLargerThan">Compare BLOB and TEXT values larger than this many bytes by their hash (0 disables it): </LABEL>
+        <INPUT type="text" id="hashColumnsLargerThan" name="hashColumnsLargerThan" value="{{.DefaultHashColumnsLargerThan}}"></BR>
+      <LABEL for="normalizeCharset">Convert text values to this character set before comparing them (optional): </LABEL>
+        <INPUT type="text" id="normalizeCharset" name="normalizeCharset" value="{{.DefaultNormalizeCharset}}"></BR>
+      <LABEL for="ignoreColumns">Columns which are not compared, e.g. t1:last_seen,counter;t2:updated (optional): </LABEL>
+        <INPUT type="text" id="ignoreColumns" name="ignoreColumns" value="{{.DefaultIgnoreColumns}}"></BR>
+      <LABEL for="normalizeTimestamps">Compare TIMESTAMP values independent of the time_zone setting of source and destination: </LABEL>
+        <INPUT type="checkbox" id="normalizeTimestamps" name="normalizeTimestamps" value="true"{{if .DefaultNormalizeTimestamps}} checked{{end}}></BR>
+      <LABEL for="floatEpsilon">Ignore differences of FLOAT and DOUBLE values up to this amount (0 disables it): </LABEL>
+        <INPUT type="text" id="floatEpsilon" name="floatEpsilon" value="{{.DefaultFloatEpsilon}}"></BR>
+      <LABEL for="reportDir">Local directory for JSON diff reports (optional): </LABEL>
+        <INPUT type="text" id="reportDir" name="reportDir" value="{{.DefaultReportDir}}"></BR>
+      <LABEL for="reportToTopo">Store JSON diff reports in the global topology: </LABEL>
+        <INPUT type="checkbox" id="reportToTopo" name="reportToTopo" value="true"{{if .DefaultReportToTopo}} checked{{end}}></BR>
+      <LABEL for="diffResultsDir">Local directory for CSV files with all different rows (optional): </LABEL>
+        <INPUT type="text" id="diffResultsDir" name="diffResultsDir" value="{{.DefaultDiffResultsDir}}"></BR>
+      <LABEL for="diffResultsToTable">Write all different rows to _vt.diff_results on the destination master: </LABEL>
+        <INPUT type="checkbox" id="diffResultsToTable" name="diffResultsToTable" value="true"{{if .DefaultDiffResultsToTable}} checked{{end}}></BR>
+      <LABEL for="useConsistentSnapshot">Diff consistent snapshots instead of stopping replication for the whole diff: </LABEL>
+        <INPUT type="checkbox" id="useConsistentSnapshot" name="useConsistentSnapshot" value="true"{{if .DefaultUseConsistentSnapshot}} checked{{end}}></BR>
       <INPUT type="hidden" name="shard" value="{{.Shard}}"/>
       <INPUT type="submit" name="submit" value="Vertical Split Diff"/>
     </form>
@@ -75,9 +125,37 @@ var verticalSplitDiffTemplate = mustParseTemplate("verticalSplitDiff", verticalS
 var verticalSplitDiffTemplate2 = mustParseTemplate("verticalSplitDiff2", verticalSplitDiffHTML2)
 
 func commandVerticalSplitDiff(wi *Instance, wr *wrangler.Wrangler, subFlags *flag.FlagSet, args []string) (Worker, error) {
+	tables := subFlags.String("tables", "", "comma separated list of tables to diff. Each is either an exact match, or a regular expression of the form /regexp/. Useful to re-verify only the tables whose diff failed")
 	minHealthyRdonlyTablets := subFlags.Int("min_healthy_rdonly_tablets", defaultMinHealthyRdonlyTablets, "minimum number of healthy RDONLY tablets before taking out one")
 	parallelDiffsCount := subFlags.Int("parallel_diffs_count", defaultParallelDiffsCount, "number of tables to diff in parallel")
-	destTabletTypeStr := subFlags.String("dest_tablet_type", defaultDestTabletType, "destination tablet type (RDONLY or REPLICA) that will be used to compare the shards")
+	subFlags.IntVar(parallelDiffsCount, "diff_parallelism", defaultParallelDiffsCount, "alias for -parallel_diffs_count")
+	chunkCount := subFlags.Int("chunk_count", defaultDiffChunkCount, "number of chunks per table which are diffed in parallel. Tables are split by ranges of the first primary key column")
+	minRowsPerChunk := subFlags.Int("min_rows_per_chunk", defaultMinRowsPerChunk, "minimum number of rows per chunk (may reduce --chunk_count)")
+	tableRetryCount := subFlags.Int("table_retry_count", defaultTableRetryCount, "number of times a failed table diff is retried e.g. after a transient tablet restart")
+	tableRetryBackoff := subFlags.Duration("table_retry_backoff", defaultTableRetryBackoff, "delay before the first retry of a failed table diff. The delay doubles after each retry")
+	where := subFlags.String("where", "", "if set, only rows which match this SQL predicate are compared e.g. \"updated_at > '2016-01-01'\". The predicate is applied to all tables")
+	samplePercent := subFlags.Float64("sample_percent", defaultSamplePercent, "percentage of rows which are compared. The sample is a deterministic pseudo-random subset of the primary keys and the same on source and destination")
+	includeViews := subFlags.Bool("include_views", defaultIncludeViews, "include views in the schema diff. Only their definitions are compared because views have no rows of their own")
+	pklessTablesUseFullRow := subFlags.Bool("pkless_tables_use_full_row", defaultPKLessTablesUseFullRow, "compare tables without a primary key by ordering their rows by all columns. A different row is reported as one missing and one extraneous row. Without this flag, such tables fail the diff. Cannot be combined with --repair and --use_consistent_snapshot")
+	rowCountCheck := subFlags.Bool("row_count_check", defaultRowCountCheck, "compare the row count of each table first. Tables whose row counts differ fail immediately without a row by row comparison")
+	checksumOnly := subFlags.Bool("checksum_only", defaultChecksumOnly, "compare the checksum of each chunk first and compare the rows only for chunks whose checksums differ")
+	repair := subFlags.Bool("repair", defaultRepair, "generate and log the INSERT, UPDATE and DELETE statements which repair the differences on the destination")
+	repairExecute := subFlags.Bool("repair_execute", defaultRepairExecute, "execute the repair statements on the destination master (requires --repair). Filtered replication on the destination master stays stopped until the diff is done")
+	repairMaxRows := subFlags.Int("repair_max_rows", defaultRepairMaxRows, "do not repair a table if more than this number of rows are different")
+	hashColumnsLargerThan := subFlags.Int("hash_columns_larger_than", defaultHashColumnsLargerThan, "if > 0, values of BLOB and TEXT columns which are larger than this many bytes are compared by their MD5 hash. The hash is computed by MySQL on the tablets which reduces the memory usage of vtworker for tables with large values. Cannot be combined with --repair")
+	normalizeCharset := subFlags.String("normalize_charset", defaultNormalizeCharset, "if set, values of CHAR, VARCHAR and TEXT columns are converted to this character set (e.g. utf8mb4) by MySQL before they are compared. Use it if the source and the destination use different character sets. Primary key columns are not converted. Cannot be combined with --repair")
+	ignoreColumns := subFlags.String("ignore_columns", defaultIgnoreColumns, "semicolon separated list of tables with a comma separated list of columns which are not compared e.g. \"t1:last_seen,counter;t2:updated\". Use it for volatile columns which are not kept in sync by filtered replication. Primary key columns cannot be ignored. Cannot be combined with --repair")
+	normalizeTimestamps := subFlags.Bool("normalize_timestamps", defaultNormalizeTimestamps, "compare the values of TIMESTAMP columns as UNIX timestamps. Use it if the source and the destination have a different time_zone setting. Primary key columns are not converted. Cannot be combined with --repair")
+	floatEpsilon := subFlags.Float64("float_epsilon", defaultFloatEpsilon, "if > 0, values of FLOAT and DOUBLE columns are considered equal if their absolute difference is not larger than this amount")
+	reportDir := subFlags.String("report_dir", defaultReportDir, "if set, a JSON diff report for each table will be written to this local directory")
+	reportToTopo := subFlags.Bool("report_to_topo", defaultReportToTopo, "if true, a JSON diff report for each table will be stored in the global topology")
+	diffResultsDir := subFlags.String("diff_results_dir", defaultDiffResultsDir, "if set, the primary key and the difference type of each different row will be written to a CSV file per table in this local directory")
+	diffResultsToTable := subFlags.Bool("diff_results_to_table", defaultDiffResultsToTable, "if true, the primary key and the difference type of each different row will be written to the _vt.diff_results table on the destination master")
+	useConsistentSnapshot := subFlags.Bool("use_consistent_snapshot", defaultUseConsistentSnapshot, "instead of keeping replication stopped during the diff, open transactions with a consistent snapshot on the source and destination tablet and restart replication right away. Requires a primary key for each table and -enable_consistent_snapshot_read_only on the tablets. Each tablet holds one transaction per chunk which is diffed in parallel (--parallel_diffs_count * --chunk_count)")
+	tabletTypeStr := subFlags.String("tablet_type", defaultTabletType, "source tablet type (RDONLY or REPLICA) that will be used to compare the shards. REPLICA tablets are drained before they are used")
+	sourceTabletAliasStr := subFlags.String("source_tablet_alias", "", "if set, use this source tablet instead of a random healthy one")
+	destinationTabletAliasStr := subFlags.String("destination_tablet_alias", "", "if set, use this destination tablet instead of a random healthy one")
+	destTabletTypeStr := subFlags.String("dest_tablet_type", defaultDestTabletType, "destination tablet type (RDONLY or REPLICA) that will be used to compare the shards. REPLICA tablets are drained before they are used")
 	if err := subFlags.Parse(args); err != nil {
 		return nil, err
 	}
@@ -89,19 +167,72 @@ func commandVerticalSplitDiff(wi *Instance, wr *wrangler.Wrangler, subFlags *fla
 	if err != nil {
 		return nil, err
 	}
+	var tableArray []string
+	if *tables != "" {
+		tableArray = strings.Split(*tables, ",")
+	}
 
+	tabletType, ok := topodatapb.TabletType_value[*tabletTypeStr]
+	if !ok {
+		return nil, fmt.Errorf("command VerticalSplitDiff invalid tablet_type: %v", *tabletTypeStr)
+	}
 	destTabletType, ok := topodatapb.TabletType_value[*destTabletTypeStr]
 	if !ok {
 		return nil, fmt.Errorf("command VerticalSplitDiff invalid dest_tablet_type: %v", destTabletType)
 	}
+	var sourceTabletAlias, destinationTabletAlias *topodatapb.TabletAlias
+	if *sourceTabletAliasStr != "" {
+		if sourceTabletAlias, err = topoproto.ParseTabletAlias(*sourceTabletAliasStr); err != nil {
+			return nil, vterrors.Wrap(err, "cannot parse source_tablet_alias")
+		}
+	}
+	if *destinationTabletAliasStr != "" {
+		if destinationTabletAlias, err = topoproto.ParseTabletAlias(*destinationTabletAliasStr); err != nil {
+			return nil, vterrors.Wrap(err, "cannot parse destination_tablet_alias")
+		}
+	}
 
-	return NewVerticalSplitDiffWorker(wr, wi.cell, keyspace, shard, *minHealthyRdonlyTablets, *parallelDiffsCount, topodatapb.TabletType(destTabletType)), nil
+	worker, err := NewVerticalSplitDiffWorker(wr, wi.cell, keyspace, shard, tableArray, diffOptions{
+		minHealthyRdonlyTablets: *minHealthyRdonlyTablets,
+		sourceTabletType:        topodatapb.TabletType(tabletType),
+		destinationTabletType:   topodatapb.TabletType(destTabletType),
+		sourceTabletAlias:       sourceTabletAlias,
+		destinationTabletAlias:  destinationTabletAlias,
+		parallelDiffsCount:      *parallelDiffsCount,
+		chunkCount:              *chunkCount,
+		minRowsPerChunk:         *minRowsPerChunk,
+		tableRetryCount:         *tableRetryCount,
+		tableRetryBackoff:       *tableRetryBackoff,
+		where:                   *where,
+		samplePercent:           *samplePercent,
+		includeViews:            *includeViews,
+		pklessTablesUseFullRow:  *pklessTablesUseFullRow,
+		rowCountCheck:           *rowCountCheck,
+		checksumOnly:            *checksumOnly,
+		repair:                  *repair,
+		repairExecute:           *repairExecute,
+		repairMaxRows:           *repairMaxRows,
+		useConsistentSnapshot:   *useConsistentSnapshot,
+		hashColumnsLargerThan:   *hashColumnsLargerThan,
+		normalizeCharset:        *normalizeCharset,
+		ignoreColumns:           *ignoreColumns,
+		floatEpsilon:            *floatEpsilon,
+		normalizeTimestamps:     *normalizeTimestamps,
+		reportDir:               *reportDir,
+		reportToTopo:            *reportToTopo,
+		diffResultsDir:          *diffResultsDir,
+		diffResultsToTable:      *diffResultsToTable,
+	})
+	if err != nil {
+		return nil, vterrors.Wrap(err, "cannot create vertical split diff worker")
+	}
+	return worker, nil
 }
 
 // shardsWithTablesSources returns all the shards that have SourceShards set
 // to one value, with an array of Tables.
 func shardsWithTablesSources(ctx context.Context, wr *wrangler.Wrangler) ([]map[string]string, error) {
-	shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+	shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 	keyspaces, err := wr.TopoServer().GetKeyspaces(shortCtx)
 	cancel()
 	if err != nil {
@@ -116,7 +247,7 @@ func shardsWithTablesSources(ctx context.Context, wr *wrangler.Wrangler) ([]map[
 		wg.Add(1)
 		go func(keyspace string) {
 			defer wg.Done()
-			shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+			shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 			shards, err := wr.TopoServer().GetShardNames(shortCtx, keyspace)
 			cancel()
 			if err != nil {
@@ -127,7 +258,7 @@ func shardsWithTablesSources(ctx context.Context, wr *wrangler.Wrangler) ([]map[
 				wg.Add(1)
 				go func(keyspace, shard string) {
 					defer wg.Done()
-					shortCtx, cancel := context.WithTimeout(ctx, *remoteActionsTimeout)
+					shortCtx, cancel := context.WithTimeout(ctx, remoteActionsTimeoutFor(ctx))
 					si, err := wr.TopoServer().GetShard(shortCtx, keyspace, shard)
 					cancel()
 					if err != nil {
@@ -185,10 +316,37 @@ func interactiveVerticalSplitDiff(ctx context.Context, wi *Instance, wr *wrangle
 		result["Shard"] = shard
 		result["DefaultMinHealthyRdonlyTablets"] = fmt.Sprintf("%v", defaultMinHealthyRdonlyTablets)
 		result["DefaultParallelDiffsCount"] = fmt.Sprintf("%v", defaultParallelDiffsCount)
+		result["DefaultChunkCount"] = fmt.Sprintf("%v", defaultDiffChunkCount)
+		result["DefaultMinRowsPerChunk"] = fmt.Sprintf("%v", defaultMinRowsPerChunk)
+		result["DefaultTableRetryCount"] = fmt.Sprintf("%v", defaultTableRetryCount)
+		result["DefaultTableRetryBackoff"] = defaultTableRetryBackoff.String()
+		result["DefaultSamplePercent"] = fmt.Sprintf("%v", defaultSamplePercent)
+		result["DefaultIncludeViews"] = defaultIncludeViews
+		result["DefaultPKLessTablesUseFullRow"] = defaultPKLessTablesUseFullRow
+		result["DefaultRowCountCheck"] = defaultRowCountCheck
+		result["DefaultChecksumOnly"] = defaultChecksumOnly
+		result["DefaultRepair"] = defaultRepair
+		result["DefaultRepairExecute"] = defaultRepairExecute
+		result["DefaultRepairMaxRows"] = fmt.Sprintf("%v", defaultRepairMaxRows)
+		result["DefaultHashColumnsLargerThan"] = fmt.Sprintf("%v", defaultHashColumnsLargerThan)
+		result["DefaultNormalizeCharset"] = defaultNormalizeCharset
+		result["DefaultIgnoreColumns"] = defaultIgnoreColumns
+		result["DefaultNormalizeTimestamps"] = defaultNormalizeTimestamps
+		result["DefaultFloatEpsilon"] = fmt.Sprintf("%v", defaultFloatEpsilon)
+		result["DefaultReportDir"] = defaultReportDir
+		result["DefaultReportToTopo"] = defaultReportToTopo
+		result["DefaultDiffResultsDir"] = defaultDiffResultsDir
+		result["DefaultDiffResultsToTable"] = defaultDiffResultsToTable
+		result["DefaultUseConsistentSnapshot"] = defaultUseConsistentSnapshot
 		return nil, verticalSplitDiffTemplate2, result, nil
 	}
 
 	// get other parameters
+	tables := r.FormValue("tables")
+	var tableArray []string
+	if tables != "" {
+		tableArray = strings.Split(tables, ",")
+	}
 	minHealthyRdonlyTabletsStr := r.FormValue("minHealthyRdonlyTablets")
 	minHealthyRdonlyTablets, err := strconv.ParseInt(minHealthyRdonlyTabletsStr, 0, 64)
 	if err != nil {
@@ -199,17 +357,113 @@ func interactiveVerticalSplitDiff(ctx context.Context, wi *Instance, wr *wrangle
 	if err != nil {
 		return nil, nil, nil, vterrors.Wrap(err, "cannot parse parallelDiffsCount")
 	}
+	chunkCountStr := r.FormValue("chunkCount")
+	chunkCount, err := strconv.ParseInt(chunkCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse chunkCount")
+	}
+	minRowsPerChunkStr := r.FormValue("minRowsPerChunk")
+	minRowsPerChunk, err := strconv.ParseInt(minRowsPerChunkStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse minRowsPerChunk")
+	}
+	tableRetryCountStr := r.FormValue("tableRetryCount")
+	tableRetryCount, err := strconv.ParseInt(tableRetryCountStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse tableRetryCount")
+	}
+	tableRetryBackoffStr := r.FormValue("tableRetryBackoff")
+	tableRetryBackoff, err := time.ParseDuration(tableRetryBackoffStr)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse tableRetryBackoff")
+	}
+	where := r.FormValue("where")
+	samplePercentStr := r.FormValue("samplePercent")
+	samplePercent, err := strconv.ParseFloat(samplePercentStr, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse samplePercent")
+	}
+	includeViewsStr := r.FormValue("includeViews")
+	includeViews := includeViewsStr == "true"
+	pklessTablesUseFullRowStr := r.FormValue("pklessTablesUseFullRow")
+	pklessTablesUseFullRow := pklessTablesUseFullRowStr == "true"
+	rowCountCheckStr := r.FormValue("rowCountCheck")
+	rowCountCheck := rowCountCheckStr == "true"
+	checksumOnlyStr := r.FormValue("checksumOnly")
+	checksumOnly := checksumOnlyStr == "true"
+	repairStr := r.FormValue("repair")
+	repair := repairStr == "true"
+	repairExecuteStr := r.FormValue("repairExecute")
+	repairExecute := repairExecuteStr == "true"
+	repairMaxRowsStr := r.FormValue("repairMaxRows")
+	repairMaxRows, err := strconv.ParseInt(repairMaxRowsStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse repairMaxRows")
+	}
+	hashColumnsLargerThanStr := r.FormValue("hashColumnsLargerThan")
+	hashColumnsLargerThan, err := strconv.ParseInt(hashColumnsLargerThanStr, 0, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse hashColumnsLargerThan")
+	}
+	normalizeCharset := r.FormValue("normalizeCharset")
+	ignoreColumns := r.FormValue("ignoreColumns")
+	normalizeTimestampsStr := r.FormValue("normalizeTimestamps")
+	normalizeTimestamps := normalizeTimestampsStr == "true"
+	floatEpsilonStr := r.FormValue("floatEpsilon")
+	floatEpsilon, err := strconv.ParseFloat(floatEpsilonStr, 64)
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot parse floatEpsilon")
+	}
+	reportDir := r.FormValue("reportDir")
+	reportToTopoStr := r.FormValue("reportToTopo")
+	reportToTopo := reportToTopoStr == "true"
+	diffResultsDir := r.FormValue("diffResultsDir")
+	diffResultsToTableStr := r.FormValue("diffResultsToTable")
+	diffResultsToTable := diffResultsToTableStr == "true"
+	useConsistentSnapshotStr := r.FormValue("useConsistentSnapshot")
+	useConsistentSnapshot := useConsistentSnapshotStr == "true"
 
 	// start the diff job
 	// TODO: @rafael - Add option to set destination tablet type in UI form.
-	wrk := NewVerticalSplitDiffWorker(wr, wi.cell, keyspace, shard, int(minHealthyRdonlyTablets), int(parallelDiffsCount), topodatapb.TabletType_RDONLY)
+	wrk, err := NewVerticalSplitDiffWorker(wr, wi.cell, keyspace, shard, tableArray, diffOptions{
+		minHealthyRdonlyTablets: int(minHealthyRdonlyTablets),
+		sourceTabletType:        topodatapb.TabletType_RDONLY,
+		destinationTabletType:   topodatapb.TabletType_RDONLY,
+		parallelDiffsCount:      int(parallelDiffsCount),
+		chunkCount:              int(chunkCount),
+		minRowsPerChunk:         int(minRowsPerChunk),
+		tableRetryCount:         int(tableRetryCount),
+		tableRetryBackoff:       tableRetryBackoff,
+		where:                   where,
+		samplePercent:           samplePercent,
+		includeViews:            includeViews,
+		pklessTablesUseFullRow:  pklessTablesUseFullRow,
+		rowCountCheck:           rowCountCheck,
+		checksumOnly:            checksumOnly,
+		repair:                  repair,
+		repairExecute:           repairExecute,
+		repairMaxRows:           int(repairMaxRows),
+		useConsistentSnapshot:   useConsistentSnapshot,
+		hashColumnsLargerThan:   int(hashColumnsLargerThan),
+		normalizeCharset:        normalizeCharset,
+		ignoreColumns:           ignoreColumns,
+		floatEpsilon:            floatEpsilon,
+		normalizeTimestamps:     normalizeTimestamps,
+		reportDir:               reportDir,
+		reportToTopo:            reportToTopo,
+		diffResultsDir:          diffResultsDir,
+		diffResultsToTable:      diffResultsToTable,
+	})
+	if err != nil {
+		return nil, nil, nil, vterrors.Wrap(err, "cannot create worker")
+	}
 	return wrk, nil, nil, nil
 }
 
 func init() {
 	AddCommand("Diffs", Command{"VerticalSplitDiff",
 		commandVerticalSplitDiff, interactiveVerticalSplitDiff,
-		"<keyspace/shard>",
+		"[--tables=''] <keyspace/shard>",
 		"Diffs an rdonly tablet from the (destination) keyspace/shard against an rdonly tablet from the respective source keyspace/shard." +
-			" Only compares the tables which were set by a previous VerticalSplitClone command."})
+			" Only compares the tables which were set by a previous VerticalSplitClone command (or a subset of them with --tables)."})
 }
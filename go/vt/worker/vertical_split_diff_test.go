@@ -91,7 +91,7 @@ func (sq *verticalDiffTabletServer) StreamExecute(ctx context.Context, target *q
 
 // TODO(aaijazi): Create a test in which source and destination data does not match
 
-func TestVerticalSplitDiff(t *testing.T) {
+func testVerticalSplitDiff(t *testing.T, includeViews bool, tables string, wantErr string) {
 	ts := memorytopo.NewServer("cell1", "cell2")
 	ctx := context.Background()
 	wi := NewInstance(ts, "cell1", time.Second)
@@ -129,7 +129,7 @@ func TestVerticalSplitDiff(t *testing.T) {
 	destRdonly2 := testlib.NewFakeTablet(t, wi.wr, "cell1", 12,
 		topodatapb.TabletType_RDONLY, nil, testlib.TabletKeyspaceShard(t, "destination_ks", "0"))
 
-	wi.wr.SetSourceShards(ctx, "destination_ks", "0", []*topodatapb.TabletAlias{sourceRdonly1.Tablet.Alias}, []string{"moving.*", "view1"})
+	wi.wr.SetSourceShards(ctx, "destination_ks", "0", []*topodatapb.TabletAlias{sourceRdonly1.Tablet.Alias}, []string{"/moving/", "view1"})
 
 	// add the topo and schema data we'll need
 	if err := wi.wr.RebuildKeyspaceGraph(ctx, "source_ks", nil); err != nil {
@@ -183,12 +183,45 @@ func TestVerticalSplitDiff(t *testing.T) {
 	}
 
 	// Run the vtworker command.
-	args := []string{"VerticalSplitDiff", "destination_ks/0"}
+	args := []string{"VerticalSplitDiff"}
+	if includeViews {
+		// "view1" must be compared by the schema diff but not row by row.
+		args = append(args, "--include_views")
+	}
+	if tables != "" {
+		args = append(args, "--tables", tables)
+	}
+	args = append(args, "destination_ks/0")
 	// We need to use FakeTabletManagerClient because we don't
 	// have a good way to fake the binlog player yet, which is
 	// necessary for synchronizing replication.
 	wr := wrangler.New(logutil.NewConsoleLogger(), ts, newFakeTMCTopo(ts))
-	if err := runCommand(t, wi, wr, args); err != nil {
-		t.Fatal(err)
+	err := runCommand(t, wi, wr, args)
+	if wantErr == "" {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
 	}
+	if err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Fatalf("VerticalSplitDiff --tables=%v = %v, want error containing: %v", tables, err, wantErr)
+	}
+}
+
+func TestVerticalSplitDiff(t *testing.T) {
+	testVerticalSplitDiff(t, false /* includeViews */, "" /* tables */, "" /* wantErr */)
+}
+
+func TestVerticalSplitDiffIncludeViews(t *testing.T) {
+	testVerticalSplitDiff(t, true /* includeViews */, "" /* tables */, "" /* wantErr */)
+}
+
+func TestVerticalSplitDiffTables(t *testing.T) {
+	// "staying1" is not in the source shard Tables list and must not be
+	// diffed although it matches --tables.
+	testVerticalSplitDiff(t, false /* includeViews */, "moving1,/^stay/", "" /* wantErr */)
+}
+
+func TestVerticalSplitDiffTablesNoMatch(t *testing.T) {
+	testVerticalSplitDiff(t, false /* includeViews */, "extra1", "no table matches --tables=extra1")
 }
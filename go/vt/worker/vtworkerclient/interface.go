@@ -24,6 +24,8 @@ import (
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/logutil"
+
+	vtworkerdatapb "vitess.io/vitess/go/vt/proto/vtworkerdata"
 )
 
 // protocol specifices which RPC client implementation should be used.
@@ -34,6 +36,21 @@ type Client interface {
 	// ExecuteVtworkerCommand will execute the command remotely.
 	ExecuteVtworkerCommand(ctx context.Context, args []string) (logutil.EventStream, error)
 
+	// GetStatus returns the status of the current or last vtworker command.
+	GetStatus(ctx context.Context) (*vtworkerdatapb.GetStatusResponse, error)
+
+	// Cancel cancels the currently running vtworker command.
+	// It returns true if a command was running.
+	Cancel(ctx context.Context) (bool, error)
+
+	// Pause pauses the currently running vtworker command.
+	// It returns true if a running command was paused.
+	Pause(ctx context.Context) (bool, error)
+
+	// Resume resumes the currently paused vtworker command.
+	// It returns true if a paused command was resumed.
+	Resume(ctx context.Context) (bool, error)
+
 	// Close will terminate the connection. This object won't be
 	// used after this.
 	Close()
@@ -71,6 +71,8 @@ func TestSuite(t *testing.T, c vtworkerclient.Client) {
 	commandErrorsBecauseBusy(t, c, true /* server side cancelation */)
 
 	commandPanics(t, c)
+
+	statusAndCancel(t, c)
 }
 
 func commandSucceeds(t *testing.T, client vtworkerclient.Client) {
@@ -278,3 +280,120 @@ func commandPanics(t *testing.T, client vtworkerclient.Client) {
 		t.Fatalf("Unexpected remote error, got: '%v' was expecting to find '%v'", err, expected)
 	}
 }
+
+// statusAndCancel tests GetStatus(), Pause(), Resume() and Cancel() while the
+// "Block" command is running and after it was canceled.
+func statusAndCancel(t *testing.T, client vtworkerclient.Client) {
+	ctx := context.Background()
+
+	// The previous test function leaves vtworker with a finished worker.
+	if err := resetVtworker(t, client); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := client.GetStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetStatus() failed: %v", err)
+	}
+	if status.Worker != "" {
+		t.Fatalf("GetStatus() should not report a worker while vtworker is idle: %v", status)
+	}
+	canceled, err := client.Cancel(ctx)
+	if err != nil {
+		t.Fatalf("Cancel() failed: %v", err)
+	}
+	if canceled {
+		t.Fatal("Cancel() should not cancel anything while vtworker is idle")
+	}
+	paused, err := client.Pause(ctx)
+	if err != nil {
+		t.Fatalf("Pause() failed: %v", err)
+	}
+	if paused {
+		t.Fatal("Pause() should not pause anything while vtworker is idle")
+	}
+
+	// Run the vtworker "Block" command which blocks until it gets canceled.
+	blockCommandStarted := make(chan struct{})
+	blockCommandDone := make(chan struct{})
+	go func() {
+		defer close(blockCommandDone)
+		stream, err := client.ExecuteVtworkerCommand(ctx, []string{"Block"})
+		if err != nil {
+			close(blockCommandStarted)
+			return
+		}
+		firstLineReceived := false
+		for {
+			if _, err := stream.Recv(); err != nil {
+				break
+			}
+			if !firstLineReceived {
+				firstLineReceived = true
+				close(blockCommandStarted)
+			}
+		}
+	}()
+	<-blockCommandStarted
+
+	status, err = client.GetStatus(ctx)
+	if err != nil {
+		t.Fatalf("GetStatus() failed: %v", err)
+	}
+	if status.Worker != "BlockWorker" || status.Done {
+		t.Fatalf("GetStatus() should report the running Block command: %v", status)
+	}
+
+	// Pause and resume the Block command. Both calls are not repeatable.
+	for _, tc := range []struct {
+		name string
+		call func(context.Context) (bool, error)
+		want bool
+	}{
+		{"Pause", client.Pause, true},
+		{"Pause", client.Pause, false},
+		{"Resume", client.Resume, true},
+		{"Resume", client.Resume, false},
+	} {
+		got, err := tc.call(ctx)
+		if err != nil {
+			t.Fatalf("%v() failed: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%v() = %v, want = %v", tc.name, got, tc.want)
+		}
+	}
+
+	canceled, err = client.Cancel(ctx)
+	if err != nil {
+		t.Fatalf("Cancel() failed: %v", err)
+	}
+	if !canceled {
+		t.Fatal("Cancel() should have canceled the Block command")
+	}
+	<-blockCommandDone
+
+	// The RPC may return before vtworker has fully stopped the command.
+	start := time.Now()
+	for {
+		status, err = client.GetStatus(ctx)
+		if err != nil {
+			t.Fatalf("GetStatus() failed: %v", err)
+		}
+		if status.Done {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("GetStatus() did not report the canceled Block command as done after 5s: %v", status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(status.Error, "canceled") {
+		t.Errorf("GetStatus() should report that the Block command was canceled: %v", status)
+	}
+
+	// Reset vtworker for the next test function.
+	if err := resetVtworker(t, client); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -41,6 +41,19 @@ type Worker interface {
 	// StatusAsText returns the current worker status in plain text.
 	StatusAsText() string
 
+	// Pause asks the worker to stop issuing queries until Resume() is called.
+	// The worker keeps its state while it is paused. Queries which are
+	// already in flight are not interrupted.
+	// It returns false if the worker was already paused.
+	Pause() bool
+
+	// Resume continues a paused worker.
+	// It returns false if the worker was not paused.
+	Resume() bool
+
+	// IsPaused returns true if the worker is paused.
+	IsPaused() bool
+
 	// Run is the main entry point for the worker. It will be
 	// called in a go routine.  When the passed in context is canceled, Run()
 	// should exit as soon as possible.
@@ -66,6 +79,11 @@ var (
 		Mainly used for testing. If throttling is enabled this should always be non-zero for all threads`,
 		[]string{"Keyspace", "ShardName", "ThreadId"})
 	statsStateDurationsNs = stats.NewGaugesWithSingleLabel("WorkerStateDurations", "How much time was spent in each state. Mainly used for testing.", "state")
+	// statsStateActive is 1 for the current state and 0 for all previous states.
+	// Unlike statsState it can be exported to monitoring systems which only
+	// support numbers e.g. Prometheus.
+	statsStateActive      = stats.NewGaugesWithSingleLabel("WorkerStateActive", "1 for the current state of the worker and 0 for all previous states", "state")
+	statsStateTransitions = stats.NewCountersWithSingleLabel("WorkerStateTransitions", "How often the worker entered each state", "state")
 
 	statsOnlineInsertsCounters = stats.NewCountersWithSingleLabel(
 		"WorkerOnlineInsertsCounters",
@@ -101,6 +119,27 @@ var (
 		"For every table how many rows were equal",
 		"table")
 
+	statsTableRowsReadCounters = stats.NewCountersWithMultiLabels(
+		"WorkerTableRowsRead",
+		"For every keyspace, shard, phase and table how many rows were read from the source and destination tablets",
+		[]string{"keyspace", "shard", "phase", "table"})
+	statsTableRowsComparedCounters = stats.NewCountersWithMultiLabels(
+		"WorkerTableRowsCompared",
+		"For every keyspace, shard, phase and table how many rows were compared between source and destination",
+		[]string{"keyspace", "shard", "phase", "table"})
+	statsTableRowsWrittenCounters = stats.NewCountersWithMultiLabels(
+		"WorkerTableRowsWritten",
+		"For every keyspace, shard, phase and table how many rows were inserted, updated or deleted on the destination",
+		[]string{"keyspace", "shard", "phase", "table"})
+	statsTableChunksDoneCounters = stats.NewCountersWithMultiLabels(
+		"WorkerTableChunksDone",
+		"For every keyspace, shard, phase and table how many chunks were processed",
+		[]string{"keyspace", "shard", "phase", "table"})
+	statsTableDurationsNs = stats.NewGaugesWithMultiLabels(
+		"WorkerTableDurations",
+		"For every keyspace, shard, phase and table how long it took to process all chunks of the table",
+		[]string{"keyspace", "shard", "phase", "table"})
+
 	statsStreamingQueryCounters = stats.NewCountersWithSingleLabel(
 		"StreamingQueryCounters",
 		"For every tablet alias how often a streaming query was successfully established there",
@@ -128,9 +167,12 @@ const (
 )
 
 // resetVars resets the debug variables that are meant to provide information on a
-// per-run basis. This should be called at the beginning of each worker run.
+// per-run basis. Workers call it at the beginning of each run through
+// StatusWorker.resetRunVars() which skips it for jobs of the jobManager.
 func resetVars() {
 	statsState.Set("")
+	statsStateActive.ResetAll()
+	statsStateTransitions.ResetAll()
 	statsRetryCount.Reset()
 	statsRetryCounters.ResetAll()
 
@@ -144,6 +186,12 @@ func resetVars() {
 	statsOfflineDeletesCounters.ResetAll()
 	statsOfflineEqualRowsCounters.ResetAll()
 
+	statsTableRowsReadCounters.ResetAll()
+	statsTableRowsComparedCounters.ResetAll()
+	statsTableRowsWrittenCounters.ResetAll()
+	statsTableChunksDoneCounters.ResetAll()
+	statsTableDurationsNs.ResetAll()
+
 	statsStreamingQueryCounters.ResetAll()
 	statsStreamingQueryErrorsCounters.ResetAll()
 }
@@ -67,7 +67,8 @@ type Factory interface {
 }
 
 // Manager is the main Workflow manager object.
-// Its management API allows it to create, start and stop workflows.
+// Its management API allows it to create, start, stop, pause, resume
+// and retry workflows.
 type Manager struct {
 	// ts is the topo server to use for all topo operations.
 	ts *topo.Server
@@ -121,6 +122,11 @@ type runningWorkflow struct {
 	// false e.g. if the Manager and its workflows are shut down
 	// by canceling the context.
 	stopped bool
+
+	// paused is true if the workflow was explicitly paused (by
+	// calling Manager.Pause(ctx, uuid)). The workflow is then saved
+	// as NotStarted, and can be resumed from its last checkpoint.
+	paused bool
 }
 
 // NewManager creates an initialized Manager.
@@ -299,6 +305,8 @@ func (m *Manager) instantiateWorkflow(w *workflowpb.Workflow) (*runningWorkflow,
 	rw.rootNode.CreateTime = w.CreateTime
 	rw.rootNode.Path = "/" + rw.rootNode.PathName
 	rw.rootNode.State = w.State
+	rw.rootNode.Paused = isPaused(w)
+	rw.rootNode.Error = w.Error
 
 	factory, ok := factories[w.FactoryName]
 	if !ok {
@@ -319,7 +327,8 @@ func (m *Manager) instantiateWorkflow(w *workflowpb.Workflow) (*runningWorkflow,
 }
 
 // Start will start a Workflow. It will load it in memory, update its
-// status to Running, and call its Run() method.
+// status to Running, and call its Run() method. A paused workflow is
+// resumed from its last checkpoint.
 func (m *Manager) Start(ctx context.Context, uuid string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -338,21 +347,55 @@ func (m *Manager) Start(ctx context.Context, uuid string) error {
 		return fmt.Errorf("workflow with uuid %v is in state %v", uuid, rw.wi.State)
 	}
 
+	return m.startLocked(ctx, rw)
+}
+
+// startLocked changes the state of a NotStarted workflow to Running,
+// and runs it. It needs to be run holding m.mu.
+func (m *Manager) startLocked(ctx context.Context, rw *runningWorkflow) error {
+	// A workflow that already ran (because it was paused, or is
+	// retried) cannot run again: re-instantiate it from its last
+	// checkpoint.
+	if rw.wi.StartTime != 0 || rw.wi.State == workflowpb.WorkflowState_Done {
+		var err error
+		if rw, err = m.reinstantiateWorkflowLocked(rw); err != nil {
+			return err
+		}
+	}
+
 	// Change its state in the topo server. Note we do that first,
 	// so if the running part fails, we will retry next time.
 	rw.wi.State = workflowpb.WorkflowState_Running
-	rw.wi.StartTime = time.Now().Unix()
+	if rw.wi.StartTime == 0 {
+		rw.wi.StartTime = time.Now().Unix()
+	}
 	if err := m.ts.SaveWorkflow(ctx, rw.wi); err != nil {
 		return err
 	}
 
 	rw.rootNode.State = workflowpb.WorkflowState_Running
+	rw.rootNode.Paused = false
+	rw.rootNode.Error = ""
 	rw.rootNode.BroadcastChanges(false /* updateChildren */)
 
 	m.runWorkflow(rw)
 	return nil
 }
 
+// reinstantiateWorkflowLocked replaces the in-memory Workflow object
+// and UI nodes of a workflow that is not running by new ones, built
+// from its persisted state. It needs to be run holding m.mu.
+func (m *Manager) reinstantiateWorkflowLocked(rw *runningWorkflow) (*runningWorkflow, error) {
+	m.nodeManager.RemoveRootNode(rw.rootNode)
+	newRw, err := m.instantiateWorkflow(rw.wi.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the WorkflowInfo, it has the topo version to save with.
+	newRw.wi = rw.wi
+	return newRw, nil
+}
+
 func (m *Manager) runWorkflow(rw *runningWorkflow) {
 	// Create a context to run it.
 	var ctx context.Context
@@ -378,7 +421,12 @@ func (m *Manager) executeWorkflowRun(ctx context.Context, rw *runningWorkflow) {
 	// to save our state there, so that when the Manager restarts
 	// next time, it restarts the workflow where it left of.
 	//
-	// 3. The workflow is done (with a valid context). err can be
+	// 3. The workflow is paused (calling Pause(uuid)). At this
+	// point, err is most likely context.Canceled, or an error
+	// caused by the cancellation. We save our workflow state as
+	// NotStarted, with its checkpoint, so it can be resumed.
+	//
+	// 4. The workflow is done (with a valid context). err can be
 	// anything (including nil), we just need to save it.
 	log.Infof("Running workflow %s (%s, %s)",
 		rw.wi.Workflow.Uuid, rw.wi.Workflow.FactoryName, rw.wi.Workflow.Name)
@@ -396,7 +444,21 @@ func (m *Manager) executeWorkflowRun(ctx context.Context, rw *runningWorkflow) {
 	defer m.mu.Unlock()
 
 	// Check for manager stoppage (case 2. above).
-	if err == context.Canceled && !rw.stopped {
+	if err == context.Canceled && !rw.stopped && !rw.paused {
+		return
+	}
+
+	// Check for a pause (case 3. above). If the workflow managed
+	// to finish before it was interrupted, it is done.
+	if rw.paused && err != nil {
+		rw.wi.State = workflowpb.WorkflowState_NotStarted
+		if err := m.ts.SaveWorkflow(m.ctx, rw.wi); err != nil {
+			log.Errorf("Could not save workflow %v after pause: %v", rw.wi, err)
+		}
+
+		rw.rootNode.State = workflowpb.WorkflowState_NotStarted
+		rw.rootNode.Paused = true
+		rw.rootNode.BroadcastChanges(false /* updateChildren */)
 		return
 	}
 
@@ -412,6 +474,7 @@ func (m *Manager) executeWorkflowRun(ctx context.Context, rw *runningWorkflow) {
 	}
 
 	rw.rootNode.State = workflowpb.WorkflowState_Done
+	rw.rootNode.Error = rw.wi.Error
 	rw.rootNode.BroadcastChanges(false /* updateChildren */)
 }
 
@@ -439,6 +502,85 @@ func (m *Manager) Stop(ctx context.Context, uuid string) error {
 	return nil
 }
 
+// Pause stops the running workflow, but keeps it resumable: it is
+// saved as NotStarted with its last checkpoint. It will cancel its
+// context and wait for it to exit.
+func (m *Manager) Pause(ctx context.Context, uuid string) error {
+	// Find the workflow, mark it as paused.
+	m.mu.Lock()
+	rw, ok := m.workflows[uuid]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("no running workflow with uuid %v", uuid)
+	}
+	if rw.wi.State != workflowpb.WorkflowState_Running || rw.cancel == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("workflow with uuid %v is in state %v", uuid, rw.wi.State)
+	}
+	rw.paused = true
+	m.mu.Unlock()
+
+	// Cancel the running guy, and waits for it.
+	rw.cancel()
+	select {
+	case <-rw.done:
+		break
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// Resume restarts a paused workflow from its last checkpoint.
+func (m *Manager) Resume(ctx context.Context, uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check the manager is running.
+	if m.ctx == nil {
+		return fmt.Errorf("manager not running")
+	}
+
+	rw, ok := m.workflows[uuid]
+	if !ok {
+		return fmt.Errorf("Cannot find workflow %v in the workflow list", uuid)
+	}
+	if !isPaused(rw.wi.Workflow) {
+		return fmt.Errorf("workflow with uuid %v is not paused", uuid)
+	}
+
+	return m.startLocked(ctx, rw)
+}
+
+// Retry restarts a workflow that finished with an error, or was
+// stopped, from its last checkpoint.
+func (m *Manager) Retry(ctx context.Context, uuid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check the manager is running.
+	if m.ctx == nil {
+		return fmt.Errorf("manager not running")
+	}
+
+	rw, ok := m.workflows[uuid]
+	if !ok {
+		return fmt.Errorf("Cannot find workflow %v in the workflow list", uuid)
+	}
+	if rw.wi.State != workflowpb.WorkflowState_Done || rw.wi.Error == "" {
+		return fmt.Errorf("workflow with uuid %v did not fail, it cannot be retried", uuid)
+	}
+
+	rw.wi.Error = ""
+	rw.wi.EndTime = 0
+	return m.startLocked(ctx, rw)
+}
+
+// isPaused returns true if the workflow was started, and then paused.
+func isPaused(w *workflowpb.Workflow) bool {
+	return w.State == workflowpb.WorkflowState_NotStarted && w.StartTime != 0
+}
+
 // Delete deletes the finished or not started workflow.
 func (m *Manager) Delete(ctx context.Context, uuid string) error {
 	m.mu.Lock()
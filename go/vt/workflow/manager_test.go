@@ -155,3 +155,125 @@ func TestManagerRestart(t *testing.T) {
 		t.Errorf("invalid workflow error: %v", wi.Error)
 	}
 }
+
+// checkWorkflowState reads the workflow from the topo server, and checks
+// its state.
+func checkWorkflowState(t *testing.T, m *Manager, uuid string, state workflowpb.WorkflowState) *workflowpb.Workflow {
+	wi, err := m.TopoServer().GetWorkflow(context.Background(), uuid)
+	if err != nil {
+		t.Fatalf("cannot read workflow %v: %v", uuid, err)
+	}
+	if wi.State != state {
+		t.Fatalf("unexpected workflow state %v was expecting %v", wi.State, state)
+	}
+	return wi.Workflow
+}
+
+// TestManagerPauseResume pauses a job, restarts the manager, and
+// resumes the job.
+func TestManagerPauseResume(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	m := NewManager(ts)
+
+	// Run the manager in the background.
+	wg, _, cancel := StartManager(m)
+
+	// Create and start a Sleep job.
+	uuid, err := m.Create(context.Background(), sleepFactoryName, []string{"-duration", "60"})
+	if err != nil {
+		t.Fatalf("cannot create sleep workflow: %v", err)
+	}
+	if err := m.Start(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot start sleep workflow: %v", err)
+	}
+
+	// Only running workflows can be paused, only paused
+	// workflows can be resumed.
+	if err := m.Resume(context.Background(), uuid); err == nil {
+		t.Errorf("Resume of a running workflow should have failed")
+	}
+	if err := m.Pause(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot pause sleep workflow: %v", err)
+	}
+	if err := m.Pause(context.Background(), uuid); err == nil {
+		t.Errorf("Pause of a paused workflow should have failed")
+	}
+
+	// The paused workflow is saved as NotStarted, and keeps its
+	// start time.
+	w := checkWorkflowState(t, m, uuid, workflowpb.WorkflowState_NotStarted)
+	if !isPaused(w) || w.Error != "" {
+		t.Errorf("workflow should be paused without error: %v", w)
+	}
+
+	// Restart the manager: the paused workflow is not started.
+	cancel()
+	wg.Wait()
+	m = NewManager(ts)
+	wg, _, cancel = StartManager(m)
+	checkWorkflowState(t, m, uuid, workflowpb.WorkflowState_NotStarted)
+
+	// Resume it.
+	if err := m.Resume(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot resume sleep workflow: %v", err)
+	}
+	resumed := checkWorkflowState(t, m, uuid, workflowpb.WorkflowState_Running)
+	if resumed.StartTime != w.StartTime {
+		t.Errorf("resumed workflow start time changed: got %v, want %v", resumed.StartTime, w.StartTime)
+	}
+
+	// Stop the job, and the manager.
+	if err := m.Stop(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot stop sleep workflow: %v", err)
+	}
+	cancel()
+	wg.Wait()
+}
+
+// TestManagerRetry stops a job, and retries it.
+func TestManagerRetry(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	m := NewManager(ts)
+
+	// Run the manager in the background.
+	wg, _, cancel := StartManager(m)
+
+	// Create and start a Sleep job.
+	uuid, err := m.Create(context.Background(), sleepFactoryName, []string{"-duration", "60"})
+	if err != nil {
+		t.Fatalf("cannot create sleep workflow: %v", err)
+	}
+	if err := m.Start(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot start sleep workflow: %v", err)
+	}
+
+	// A running workflow cannot be retried.
+	if err := m.Retry(context.Background(), uuid); err == nil {
+		t.Errorf("Retry of a running workflow should have failed")
+	}
+
+	// Stop it, it is done with an error.
+	if err := m.Stop(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot stop sleep workflow: %v", err)
+	}
+	w := checkWorkflowState(t, m, uuid, workflowpb.WorkflowState_Done)
+	if !strings.Contains(w.Error, "canceled") {
+		t.Errorf("invalid workflow error: %v", w.Error)
+	}
+
+	// Retry it, it is running again without an error.
+	if err := m.Retry(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot retry sleep workflow: %v", err)
+	}
+	w = checkWorkflowState(t, m, uuid, workflowpb.WorkflowState_Running)
+	if w.Error != "" || w.EndTime != 0 {
+		t.Errorf("retried workflow should have no error and end time: %v", w)
+	}
+
+	// Stop the job, and the manager.
+	if err := m.Stop(context.Background(), uuid); err != nil {
+		t.Fatalf("cannot stop sleep workflow: %v", err)
+	}
+	cancel()
+	wg.Wait()
+}
@@ -149,6 +149,12 @@ type Node struct {
 	Log             string                   `json:"log"`
 	Disabled        bool                     `json:"disabled"`
 	Actions         []*Action                `json:"actions"`
+
+	// Paused and Error are only set on the root node of a workflow,
+	// by the Manager. Paused is true if the workflow was paused and
+	// can be resumed, Error is the error the workflow finished with.
+	Paused bool   `json:"paused,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
 // Action must match node.ts Action.
@@ -37,15 +37,15 @@ func (m *MockReshardingWrangler) EXPECT() *MockReshardingWranglerMockRecorder {
 }
 
 // CopySchemaShardFromShard mocks base method
-func (m *MockReshardingWrangler) CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitSlaveTimeout time.Duration) error {
-	ret := m.ctrl.Call(m, "CopySchemaShardFromShard", ctx, tables, excludeTables, includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, waitSlaveTimeout)
+func (m *MockReshardingWrangler) CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews, includeTriggers bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitSlaveTimeout, waitReplicasTimeout time.Duration) error {
+	ret := m.ctrl.Call(m, "CopySchemaShardFromShard", ctx, tables, excludeTables, includeViews, includeTriggers, sourceKeyspace, sourceShard, destKeyspace, destShard, waitSlaveTimeout, waitReplicasTimeout)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CopySchemaShardFromShard indicates an expected call of CopySchemaShardFromShard
-func (mr *MockReshardingWranglerMockRecorder) CopySchemaShardFromShard(ctx, tables, excludeTables, includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, waitSlaveTimeout interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySchemaShardFromShard", reflect.TypeOf((*MockReshardingWrangler)(nil).CopySchemaShardFromShard), ctx, tables, excludeTables, includeViews, sourceKeyspace, sourceShard, destKeyspace, destShard, waitSlaveTimeout)
+func (mr *MockReshardingWranglerMockRecorder) CopySchemaShardFromShard(ctx, tables, excludeTables, includeViews, includeTriggers, sourceKeyspace, sourceShard, destKeyspace, destShard, waitSlaveTimeout, waitReplicasTimeout interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySchemaShardFromShard", reflect.TypeOf((*MockReshardingWrangler)(nil).CopySchemaShardFromShard), ctx, tables, excludeTables, includeViews, includeTriggers, sourceKeyspace, sourceShard, destKeyspace, destShard, waitSlaveTimeout, waitReplicasTimeout)
 }
 
 // WaitForFilteredReplication mocks base method
@@ -29,7 +29,7 @@ import (
 
 // ReshardingWrangler is the interface to be used in creating mock interface for wrangler, which is used for unit test. It includes a subset of the methods in go/vt/Wrangler.
 type ReshardingWrangler interface {
-	CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitSlaveTimeout time.Duration) error
+	CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews, includeTriggers bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitSlaveTimeout, waitReplicasTimeout time.Duration) error
 
 	WaitForFilteredReplication(ctx context.Context, keyspace, shard string, maxDelay time.Duration) error
 
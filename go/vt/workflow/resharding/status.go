@@ -0,0 +1,85 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreedto in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resharding
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	"vitess.io/vitess/go/vt/workflow"
+
+	workflowpb "vitess.io/vitess/go/vt/proto/workflow"
+)
+
+// executionOrder lists the phases in the order in which runWorkflow
+// executes them.
+var executionOrder = []workflow.PhaseType{
+	phaseCopySchema,
+	phaseClone,
+	phaseWaitForFilteredReplication,
+	phaseDiff,
+	phaseMigrateRdonly,
+	phaseMigrateReplica,
+	phaseMigrateMaster,
+}
+
+// IsHorizontalReshardingWorkflow returns true if "w" was created by the
+// horizontal resharding factory.
+func IsHorizontalReshardingWorkflow(w *workflowpb.Workflow) bool {
+	return w.FactoryName == horizontalReshardingFactoryName
+}
+
+// Status returns a human readable summary of the checkpoint of a horizontal
+// resharding workflow. It lists each phase in execution order with the
+// state of its tasks. It does not require a running workflow.Manager because
+// it only reads the checkpoint which is stored in the topology.
+func Status(w *workflowpb.Workflow) (string, error) {
+	if !IsHorizontalReshardingWorkflow(w) {
+		return "", fmt.Errorf("workflow %v is not a %v workflow", w.Uuid, horizontalReshardingFactoryName)
+	}
+	checkpoint := &workflowpb.WorkflowCheckpoint{}
+	if err := proto.Unmarshal(w.Data, checkpoint); err != nil {
+		return "", fmt.Errorf("cannot read the checkpoint of workflow %v: %v", w.Uuid, err)
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "Source shards: %v\n", checkpoint.Settings["source_shards"])
+	fmt.Fprintf(b, "Destination shards: %v\n", checkpoint.Settings["destination_shards"])
+	for _, phase := range executionOrder {
+		tasks := getTasks(checkpoint, phase)
+		done := 0
+		for _, t := range tasks {
+			if t != nil && t.State == workflowpb.TaskState_TaskDone {
+				done++
+			}
+		}
+		fmt.Fprintf(b, "%v: %v/%v tasks done\n", phase, done, len(tasks))
+		for _, t := range tasks {
+			if t == nil {
+				continue
+			}
+			fmt.Fprintf(b, "  %v: %v", t.Id, t.State)
+			if t.Error != "" {
+				fmt.Fprintf(b, " (error: %v)", t.Error)
+			}
+			fmt.Fprintf(b, "\n")
+		}
+	}
+	return b.String(), nil
+}
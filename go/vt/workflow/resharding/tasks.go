@@ -39,12 +39,16 @@ func createTaskID(phase workflow.PhaseType, shardName string) string {
 // GetTasks returns selected tasks for a phase from the checkpoint
 // with expected execution order.
 func (hw *horizontalReshardingWorkflow) GetTasks(phase workflow.PhaseType) []*workflowpb.Task {
+	return getTasks(hw.checkpoint, phase)
+}
+
+func getTasks(checkpoint *workflowpb.WorkflowCheckpoint, phase workflow.PhaseType) []*workflowpb.Task {
 	var shards []string
 	switch phase {
 	case phaseCopySchema, phaseWaitForFilteredReplication, phaseDiff:
-		shards = strings.Split(hw.checkpoint.Settings["destination_shards"], ",")
+		shards = strings.Split(checkpoint.Settings["destination_shards"], ",")
 	case phaseClone, phaseMigrateRdonly, phaseMigrateReplica, phaseMigrateMaster:
-		shards = strings.Split(hw.checkpoint.Settings["source_shards"], ",")
+		shards = strings.Split(checkpoint.Settings["source_shards"], ",")
 	default:
 		log.Fatalf("BUG: unknown phase type: %v", phase)
 	}
@@ -52,7 +56,7 @@ func (hw *horizontalReshardingWorkflow) GetTasks(phase workflow.PhaseType) []*wo
 	var tasks []*workflowpb.Task
 	for _, s := range shards {
 		taskID := createTaskID(phase, s)
-		tasks = append(tasks, hw.checkpoint.Tasks[taskID])
+		tasks = append(tasks, checkpoint.Tasks[taskID])
 	}
 	return tasks
 }
@@ -62,7 +66,7 @@ func (hw *horizontalReshardingWorkflow) runCopySchema(ctx context.Context, t *wo
 	sourceShard := t.Attributes["source_shard"]
 	destShard := t.Attributes["destination_shard"]
 	return hw.wr.CopySchemaShardFromShard(ctx, nil /* tableArray*/, nil /* excludeTableArray */, true, /*includeViews*/
-		keyspace, sourceShard, keyspace, destShard, wrangler.DefaultWaitSlaveTimeout)
+		false /* includeTriggers */, keyspace, sourceShard, keyspace, destShard, wrangler.DefaultWaitSlaveTimeout, 0 /* waitReplicasTimeout */)
 }
 
 func (hw *horizontalReshardingWorkflow) runSplitClone(ctx context.Context, t *workflowpb.Task) error {
@@ -79,6 +79,7 @@ func (*Factory) Init(m *workflow.Manager, w *workflowpb.Workflow, args []string)
 	splitDiffDestTabletType := subFlags.String("split_diff_dest_tablet_type", "RDONLY", "Specifies tablet type to use in destination shards while performing SplitDiff operation")
 	phaseEnaableApprovalsDesc := fmt.Sprintf("Comma separated phases that require explicit approval in the UI to execute. Phase names are: %v", strings.Join(WorkflowPhases(), ","))
 	phaseEnableApprovalsStr := subFlags.String("phase_enable_approvals", strings.Join(WorkflowPhases(), ","), phaseEnaableApprovalsDesc)
+	createDestinationShards := subFlags.Bool("create_destination_shards", false, "If set, the destination shards which do not exist yet are created in the topology before the workflow is validated")
 
 	if err := subFlags.Parse(args); err != nil {
 		return err
@@ -103,6 +104,12 @@ func (*Factory) Init(m *workflow.Manager, w *workflowpb.Workflow, args []string)
 		}
 	}
 
+	if *createDestinationShards {
+		if err := createShards(context.Background(), m.TopoServer(), *keyspace, destinationShards); err != nil {
+			return err
+		}
+	}
+
 	err := validateWorkflow(m, *keyspace, vtworkers, sourceShards, destinationShards, *minHealthyRdonlyTablets)
 	if err != nil {
 		return err
@@ -229,6 +236,21 @@ func createUINodes(rootNode *workflow.Node, phaseName workflow.PhaseType, shards
 	return nil
 }
 
+// createShards creates the shards which do not exist yet. The new shards do
+// not serve any type which is still served by an overlapping shard.
+func createShards(ctx context.Context, ts *topo.Server, keyspace string, shards []string) error {
+	for _, shard := range shards {
+		if err := ts.CreateShard(ctx, keyspace, shard); err != nil {
+			if topo.IsErrType(err, topo.NodeExists) {
+				continue
+			}
+			return fmt.Errorf("cannot create destination shard %v/%v: %v", keyspace, shard, err)
+		}
+		log.Infof("Created destination shard %v/%v", keyspace, shard)
+	}
+	return nil
+}
+
 // validateWorkflow validates that workflow has valid input parameters.
 func validateWorkflow(m *workflow.Manager, keyspace string, vtworkers, sourceShards, destinationShards []string, minHealthyRdonlyTablets string) error {
 	if len(sourceShards) == 0 || len(destinationShards) == 0 {
@@ -18,7 +18,9 @@ package resharding
 
 import (
 	"flag"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"golang.org/x/net/context"
@@ -88,6 +90,55 @@ func TestSourceDestShards(t *testing.T) {
 	cancel()
 }
 
+// TestCreateDestinationShards tests that missing destination shards are
+// created and that the status of the new workflow can be read.
+func TestCreateDestinationShards(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell")
+	if err := ts.CreateKeyspace(ctx, testKeyspace, &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace: %v", err)
+	}
+	if err := ts.CreateShard(ctx, testKeyspace, "0"); err != nil {
+		t.Fatalf("CreateShard: %v", err)
+	}
+	// The workflow is only created and never started. Therefore, the
+	// manager does not need to run.
+	m := workflow.NewManager(ts)
+
+	vtworkersParameter := testVtworkers + "," + testVtworkers
+	uuid, err := m.Create(ctx, horizontalReshardingFactoryName, []string{"-keyspace=" + testKeyspace, "-vtworkers=" + vtworkersParameter, "-phase_enable_approvals=", "-min_healthy_rdonly_tablets=2", "-source_shards=0", "-destination_shards=-40,40-", "-create_destination_shards"})
+	if err != nil {
+		t.Fatalf("cannot create resharding workflow: %v", err)
+	}
+	for _, shard := range []string{"-40", "40-"} {
+		si, err := ts.GetShard(ctx, testKeyspace, shard)
+		if err != nil {
+			t.Fatalf("destination shard %v was not created: %v", shard, err)
+		}
+		if len(si.ServedTypes) != 0 {
+			t.Errorf("destination shard %v must not serve any type: %v", shard, si.ServedTypes)
+		}
+	}
+
+	wi, err := ts.GetWorkflow(ctx, uuid)
+	if err != nil {
+		t.Fatalf("GetWorkflow failed: %v", err)
+	}
+	status, err := Status(wi.Workflow)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	for _, want := range []string{
+		"Destination shards: -40,40-\n",
+		"copy_schema: 0/2 tasks done\n  copy_schema/-40: TaskNotStarted\n",
+		"migrate_master: 0/1 tasks done\n  migrate_master/0: TaskNotStarted\n",
+	} {
+		if !strings.Contains(status, want) {
+			t.Errorf("Status does not contain %q:\n%v", want, status)
+		}
+	}
+}
+
 // TestHorizontalResharding runs the happy path of HorizontalReshardingWorkflow.
 func TestHorizontalResharding(t *testing.T) {
 	ctx := context.Background()
@@ -156,8 +207,8 @@ func setupFakeVtworker(keyspace, vtworkers string) *fakevtworkerclient.FakeVtwor
 func setupMockWrangler(ctrl *gomock.Controller, keyspace string) *MockReshardingWrangler {
 	mockWranglerInterface := NewMockReshardingWrangler(ctrl)
 	// Set the expected behaviors for mock wrangler.
-	mockWranglerInterface.EXPECT().CopySchemaShardFromShard(gomock.Any(), nil /* tableArray*/, nil /* excludeTableArray */, true /*includeViews*/, keyspace, "0", keyspace, "-80", wrangler.DefaultWaitSlaveTimeout).Return(nil)
-	mockWranglerInterface.EXPECT().CopySchemaShardFromShard(gomock.Any(), nil /* tableArray*/, nil /* excludeTableArray */, true /*includeViews*/, keyspace, "0", keyspace, "80-", wrangler.DefaultWaitSlaveTimeout).Return(nil)
+	mockWranglerInterface.EXPECT().CopySchemaShardFromShard(gomock.Any(), nil /* tableArray*/, nil /* excludeTableArray */, true /*includeViews*/, false /* includeTriggers */, keyspace, "0", keyspace, "-80", wrangler.DefaultWaitSlaveTimeout, time.Duration(0)).Return(nil)
+	mockWranglerInterface.EXPECT().CopySchemaShardFromShard(gomock.Any(), nil /* tableArray*/, nil /* excludeTableArray */, true /*includeViews*/, false /* includeTriggers */, keyspace, "0", keyspace, "80-", wrangler.DefaultWaitSlaveTimeout, time.Duration(0)).Return(nil)
 
 	mockWranglerInterface.EXPECT().WaitForFilteredReplication(gomock.Any(), keyspace, "-80", wrangler.DefaultWaitForFilteredReplicationMaxDelay).Return(nil)
 	mockWranglerInterface.EXPECT().WaitForFilteredReplication(gomock.Any(), keyspace, "80-", wrangler.DefaultWaitForFilteredReplicationMaxDelay).Return(nil)
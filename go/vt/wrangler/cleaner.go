@@ -17,22 +17,73 @@ limitations under the License.
 package wrangler
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"path"
+	"sort"
 	"sync"
 	"time"
 
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
+// CleanerActionsPath is the directory in the directory of a shard in the
+// global topology which has the pending clean-up actions of the durable
+// Cleaners of that shard (see NewDurableCleaner). There is one file per
+// Cleaner.
+const CleanerActionsPath = "cleaner_actions"
+
+// cleanerOwnerAliveTimeout is the timeout of the request which checks if
+// the owner of a durable Cleaner is still running.
+const cleanerOwnerAliveTimeout = 5 * time.Second
+
+// cleanerOwnerAlive returns true if the process "owner" which recorded the
+// actions of a durable Cleaner still responds to HTTP requests.
+// Tests can replace it.
+var cleanerOwnerAlive = func(ctx context.Context, owner string) bool {
+	if owner == "" {
+		return false
+	}
+	req, err := http.NewRequest("GET", owner, nil)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, cleanerOwnerAliveTimeout)
+	defer cancel()
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
+}
+
+// cleanerActionsDir returns the directory of the durable Cleaners of
+// "keyspace"/"shard" in the global topology.
+func cleanerActionsDir(keyspace, shard string) string {
+	return path.Join(topo.KeyspacesPath, keyspace, topo.ShardsPath, shard, CleanerActionsPath)
+}
+
 // Cleaner remembers a list of cleanup steps to perform.  Just record
 // action cleanup steps, and execute them at the end in reverse
 // order, with various guarantees.
 type Cleaner struct {
+	// ts, keyspace, shard and id are set for a durable Cleaner.
+	ts       *topo.Server
+	keyspace string
+	shard    string
+	id       string
+	// owner describes the process which records the actions.
+	owner string
+
 	// mu protects the following members
 	mu      sync.Mutex
 	actions []cleanerActionReference
@@ -43,6 +94,58 @@ type cleanerActionReference struct {
 	name   string
 	target string
 	action CleanerFunction
+	// record is set for actions which survive a crash of the process.
+	record *cleanerActionRecord
+}
+
+// cleanerActionRecord is the serializable form of one of the actions
+// below. Only the fields of the respective action are set.
+type cleanerActionRecord struct {
+	Name   string
+	Target string
+
+	// TabletAlias is set for ChangeSlaveTypeAction and TabletTagAction.
+	TabletAlias *topodatapb.TabletAlias `json:",omitempty"`
+	// Tablet is set for StartSlaveAction and VReplicationAction.
+	Tablet *topodatapb.Tablet `json:",omitempty"`
+
+	// ChangeSlaveTypeAction
+	From topodatapb.TabletType `json:",omitempty"`
+	To   topodatapb.TabletType `json:",omitempty"`
+
+	// TabletTagAction
+	TagName  string `json:",omitempty"`
+	TagValue string `json:",omitempty"`
+
+	// VReplicationAction
+	Query string `json:",omitempty"`
+}
+
+// cleanerActionsFile is the content of the file of a durable Cleaner in the
+// global topology.
+type cleanerActionsFile struct {
+	Owner   string
+	Actions []*cleanerActionRecord
+}
+
+// NewDurableCleaner returns a Cleaner which also stores its actions in the
+// directory of "keyspace"/"shard" in the global topology under
+// CleanerActionsPath/"id". If the process dies before CleanUp() was called,
+// CleanupOrphanedWorkerActions() can run them later.
+// "keyspace"/"shard" is the shard the process works on. "id" must be unique
+// e.g. include the command and a timestamp. "owner" is the URL of the
+// process which records the actions. The actions are not run by
+// CleanupOrphanedWorkerActions() while it responds.
+// Only the actions of the Record*Action() functions below are stored.
+// Actions which are recorded with Record() exist only in memory.
+func NewDurableCleaner(ts *topo.Server, keyspace, shard, id, owner string) *Cleaner {
+	return &Cleaner{
+		ts:       ts,
+		keyspace: keyspace,
+		shard:    shard,
+		id:       id,
+		owner:    owner,
+	}
 }
 
 // CleanerFunction is the interface that clean-up actions need to implement
@@ -59,6 +162,69 @@ func (cleaner *Cleaner) Record(name, target string, action CleanerFunction) {
 	cleaner.mu.Unlock()
 }
 
+// recordDurable adds the action described by "record" to the list and
+// stores the list in the global topology if the Cleaner is durable.
+func (cleaner *Cleaner) recordDurable(record *cleanerActionRecord) {
+	cleaner.mu.Lock()
+	defer cleaner.mu.Unlock()
+
+	cleaner.actions = append(cleaner.actions, cleanerActionReference{
+		name:   record.Name,
+		target: record.Target,
+		action: record.action(),
+		record: record,
+	})
+	if err := cleaner.saveLocked(cleaner.durableRecordsLocked(nil)); err != nil {
+		// The action will still run in CleanUp(). It is only lost if the
+		// process dies before.
+		log.Warningf("cannot store clean-up action %v on %v in the topology: %v", record.Name, record.Target, err)
+	}
+}
+
+// durableRecordsLocked returns the records of all actions which are
+// stored in the topology, except the ones in "skip". mu must be held.
+func (cleaner *Cleaner) durableRecordsLocked(skip map[*cleanerActionRecord]bool) []*cleanerActionRecord {
+	var records []*cleanerActionRecord
+	for _, a := range cleaner.actions {
+		if a.record != nil && !skip[a.record] {
+			records = append(records, a.record)
+		}
+	}
+	return records
+}
+
+// saveLocked stores "records" in the topology. It deletes the file if
+// "records" is empty. It is a no-op if the Cleaner is not durable.
+// mu must be held.
+func (cleaner *Cleaner) saveLocked(records []*cleanerActionRecord) error {
+	if cleaner.ts == nil {
+		return nil
+	}
+	// Same as CleanUp(), don't depend on the context of the caller.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	conn, err := cleaner.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	filePath := path.Join(cleanerActionsDir(cleaner.keyspace, cleaner.shard), cleaner.id)
+	if len(records) == 0 {
+		if err := conn.Delete(ctx, filePath, nil); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(&cleanerActionsFile{
+		Owner:   cleaner.owner,
+		Actions: records,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = conn.Update(ctx, filePath, data, nil)
+	return err
+}
+
 type cleanUpHelper struct {
 	err error
 }
@@ -75,6 +241,8 @@ func (cleaner *Cleaner) CleanUp(wr *Wrangler) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	actionMap := make(map[string]*cleanUpHelper)
 	rec := concurrency.AllErrorRecorder{}
+	// done has the durable actions which ran successfully.
+	done := make(map[*cleanerActionRecord]bool)
 	cleaner.mu.Lock()
 	for i := len(cleaner.actions) - 1; i >= 0; i-- {
 		actionReference := cleaner.actions[i]
@@ -96,13 +264,204 @@ func (cleaner *Cleaner) CleanUp(wr *Wrangler) error {
 			wr.Logger().Errorf("action %v failed on %v: %v", actionReference.name, actionReference.target, err)
 		} else {
 			wr.Logger().Infof("action %v successful on %v", actionReference.name, actionReference.target)
+			if actionReference.record != nil {
+				done[actionReference.record] = true
+			}
 		}
 	}
+	// Keep the failed and skipped actions in the topology. They can be
+	// retried with CleanupOrphanedWorkerActions().
+	if err := cleaner.saveLocked(cleaner.durableRecordsLocked(done)); err != nil {
+		rec.RecordError(vterrors.Wrapf(err, "cannot update the clean-up actions %v in the topology", cleaner.id))
+	}
 	cleaner.mu.Unlock()
 	cancel()
 	return rec.Error()
 }
 
+// CleanupOrphanedWorkerActions runs the clean-up actions which durable
+// Cleaners stored in the global topology but did not run, e.g. because
+// vtworker was killed in the middle of a command. The actions of a Cleaner
+// whose owner still responds are skipped.
+// If "ids" is not empty, only the actions of these Cleaners are run.
+// If "keyspace" and "shard" are set, only the Cleaners of this shard and the
+// ones which have at least one action on a tablet of this shard are run. All
+// actions of such a Cleaner are run, not only the ones on the shard.
+// With "dryRun", the actions are only logged.
+func (wr *Wrangler) CleanupOrphanedWorkerActions(ctx context.Context, ids []string, keyspace, shard string, dryRun bool) error {
+	conn, err := wr.ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		return err
+	}
+	cleaners, err := wr.findDurableCleaners(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		wanted := make(map[string]bool)
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		var filtered []durableCleanerRef
+		for _, c := range cleaners {
+			if wanted[c.id] {
+				filtered = append(filtered, c)
+				delete(wanted, c.id)
+			}
+		}
+		if len(wanted) > 0 {
+			var missing []string
+			for id := range wanted {
+				missing = append(missing, id)
+			}
+			sort.Strings(missing)
+			return fmt.Errorf("no clean-up actions found for: %v", missing)
+		}
+		cleaners = filtered
+	}
+	if len(cleaners) == 0 {
+		wr.Logger().Printf("No orphaned worker actions found.\n")
+		return nil
+	}
+
+	rec := concurrency.AllErrorRecorder{}
+	for _, c := range cleaners {
+		data, _, err := conn.Get(ctx, path.Join(cleanerActionsDir(c.keyspace, c.shard), c.id))
+		if err != nil {
+			rec.RecordError(vterrors.Wrapf(err, "cannot read the clean-up actions %v", c.id))
+			continue
+		}
+		file := &cleanerActionsFile{}
+		if err := json.Unmarshal(data, file); err != nil {
+			rec.RecordError(vterrors.Wrapf(err, "cannot parse the clean-up actions %v", c.id))
+			continue
+		}
+
+		if (keyspace != "" || shard != "") && (c.keyspace != keyspace || c.shard != shard) {
+			affected, err := wr.cleanerActionsAffectShard(ctx, file.Actions, keyspace, shard)
+			if err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "cannot check the clean-up actions %v", c.id))
+				continue
+			}
+			if !affected {
+				continue
+			}
+		}
+
+		if cleanerOwnerAlive(ctx, file.Owner) {
+			wr.Logger().Printf("Skipping the clean-up actions %v because %v which recorded them is still running.\n", c.id, file.Owner)
+			continue
+		}
+
+		wr.Logger().Printf("Clean-up actions %v of %v recorded by %v:\n", c.id, topoproto.KeyspaceShardString(c.keyspace, c.shard), file.Owner)
+		cleaner := NewDurableCleaner(wr.ts, c.keyspace, c.shard, c.id, file.Owner)
+		for _, record := range file.Actions {
+			wr.Logger().Printf("  %v on %v\n", record.Name, record.Target)
+			// Don't use recordDurable() which would rewrite the file.
+			cleaner.actions = append(cleaner.actions, cleanerActionReference{
+				name:   record.Name,
+				target: record.Target,
+				action: record.action(),
+				record: record,
+			})
+		}
+		if dryRun {
+			continue
+		}
+		if err := cleaner.CleanUp(wr); err != nil {
+			rec.RecordError(vterrors.Wrapf(err, "clean-up actions %v failed", c.id))
+		}
+	}
+	return rec.Error()
+}
+
+// durableCleanerRef identifies the stored actions of a durable Cleaner.
+type durableCleanerRef struct {
+	keyspace string
+	shard    string
+	id       string
+}
+
+// findDurableCleaners returns the durable Cleaners of all shards which have
+// stored actions.
+func (wr *Wrangler) findDurableCleaners(ctx context.Context, conn topo.Conn) ([]durableCleanerRef, error) {
+	keyspaces, err := listDirNames(ctx, conn, topo.KeyspacesPath)
+	if err != nil {
+		return nil, err
+	}
+	var result []durableCleanerRef
+	for _, keyspace := range keyspaces {
+		shards, err := listDirNames(ctx, conn, path.Join(topo.KeyspacesPath, keyspace, topo.ShardsPath))
+		if err != nil {
+			return nil, err
+		}
+		for _, shard := range shards {
+			ids, err := listDirNames(ctx, conn, cleanerActionsDir(keyspace, shard))
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				result = append(result, durableCleanerRef{keyspace: keyspace, shard: shard, id: id})
+			}
+		}
+	}
+	return result, nil
+}
+
+// listDirNames returns the names of the entries of "dirPath". A missing
+// directory is treated as empty.
+func listDirNames(ctx context.Context, conn topo.Conn, dirPath string) ([]string, error) {
+	entries, err := conn.ListDir(ctx, dirPath, false /* full */)
+	if err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return topo.DirEntriesToStringArray(entries), nil
+}
+
+// cleanerActionsAffectShard returns true if one of "records" is an action on
+// a tablet of "keyspace"/"shard".
+func (wr *Wrangler) cleanerActionsAffectShard(ctx context.Context, records []*cleanerActionRecord, keyspace, shard string) (bool, error) {
+	for _, r := range records {
+		tablet := r.Tablet
+		if tablet == nil {
+			ti, err := wr.ts.GetTablet(ctx, r.TabletAlias)
+			if err != nil {
+				if topo.IsErrType(err, topo.NoNode) {
+					// The tablet was deleted in the meantime. The
+					// action would fail anyway.
+					continue
+				}
+				return false, err
+			}
+			tablet = ti.Tablet
+		}
+		if tablet.Keyspace == keyspace && tablet.Shard == shard {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// action returns the function which runs the action described by "r".
+func (r *cleanerActionRecord) action() CleanerFunction {
+	switch r.Name {
+	case ChangeSlaveTypeActionName:
+		return changeSlaveTypeAction(r.TabletAlias, r.From, r.To)
+	case TabletTagActionName:
+		return tabletTagAction(r.TabletAlias, r.TagName, r.TagValue)
+	case StartSlaveActionName:
+		return startSlaveAction(r.Tablet)
+	case VReplicationActionName:
+		return vReplicationAction(r.Tablet, r.Query)
+	}
+	return func(context.Context, *Wrangler) error {
+		return fmt.Errorf("unknown clean-up action %v on %v", r.Name, r.Target)
+	}
+}
+
 //
 // ChangeSlaveTypeAction CleanerFunction
 //
@@ -114,7 +473,37 @@ const ChangeSlaveTypeActionName = "ChangeSlaveTypeAction"
 // RecordChangeSlaveTypeAction records a new ChangeSlaveTypeAction
 // into the specified Cleaner
 func RecordChangeSlaveTypeAction(cleaner *Cleaner, tabletAlias *topodatapb.TabletAlias, from topodatapb.TabletType, to topodatapb.TabletType) {
-	cleaner.Record(ChangeSlaveTypeActionName, topoproto.TabletAliasString(tabletAlias), func(ctx context.Context, wr *Wrangler) error {
+	cleaner.recordDurable(&cleanerActionRecord{
+		Name:        ChangeSlaveTypeActionName,
+		Target:      topoproto.TabletAliasString(tabletAlias),
+		TabletAlias: tabletAlias,
+		From:        from,
+		To:          to,
+	})
+}
+
+// ChangedTablets returns the tablets whose type is changed back by a
+// recorded ChangeSlaveTypeAction, in the order in which they were recorded.
+// For example, a worker can wait after CleanUp() until these tablets are
+// serving again.
+func (cleaner *Cleaner) ChangedTablets() []*topodatapb.TabletAlias {
+	cleaner.mu.Lock()
+	defer cleaner.mu.Unlock()
+
+	var result []*topodatapb.TabletAlias
+	seen := make(map[string]bool)
+	for _, a := range cleaner.actions {
+		if a.record == nil || a.record.Name != ChangeSlaveTypeActionName || seen[a.target] {
+			continue
+		}
+		seen[a.target] = true
+		result = append(result, a.record.TabletAlias)
+	}
+	return result
+}
+
+func changeSlaveTypeAction(tabletAlias *topodatapb.TabletAlias, from topodatapb.TabletType, to topodatapb.TabletType) CleanerFunction {
+	return func(ctx context.Context, wr *Wrangler) error {
 		ti, err := wr.ts.GetTablet(ctx, tabletAlias)
 		if err != nil {
 			return err
@@ -128,7 +517,7 @@ func RecordChangeSlaveTypeAction(cleaner *Cleaner, tabletAlias *topodatapb.Table
 
 		// ask the tablet to make the change
 		return wr.tmc.ChangeType(ctx, ti.Tablet, to)
-	})
+	}
 }
 
 //
@@ -141,7 +530,17 @@ const TabletTagActionName = "TabletTagAction"
 // RecordTabletTagAction records a new action to set / remove a tag
 // into the specified Cleaner
 func RecordTabletTagAction(cleaner *Cleaner, tabletAlias *topodatapb.TabletAlias, name, value string) {
-	cleaner.Record(TabletTagActionName, topoproto.TabletAliasString(tabletAlias), func(ctx context.Context, wr *Wrangler) error {
+	cleaner.recordDurable(&cleanerActionRecord{
+		Name:        TabletTagActionName,
+		Target:      topoproto.TabletAliasString(tabletAlias),
+		TabletAlias: tabletAlias,
+		TagName:     name,
+		TagValue:    value,
+	})
+}
+
+func tabletTagAction(tabletAlias *topodatapb.TabletAlias, name, value string) CleanerFunction {
+	return func(ctx context.Context, wr *Wrangler) error {
 		_, err := wr.TopoServer().UpdateTabletFields(ctx, tabletAlias, func(tablet *topodatapb.Tablet) error {
 			if tablet.Tags == nil {
 				tablet.Tags = make(map[string]string)
@@ -154,7 +553,7 @@ func RecordTabletTagAction(cleaner *Cleaner, tabletAlias *topodatapb.TabletAlias
 			return nil
 		})
 		return err
-	})
+	}
 }
 
 //
@@ -167,11 +566,19 @@ const StartSlaveActionName = "StartSlaveAction"
 // RecordStartSlaveAction records a new action to restart binlog replication on a server
 // into the specified Cleaner
 func RecordStartSlaveAction(cleaner *Cleaner, tablet *topodatapb.Tablet) {
-	cleaner.Record(StartSlaveActionName, topoproto.TabletAliasString(tablet.Alias), func(ctx context.Context, wr *Wrangler) error {
-		return wr.TabletManagerClient().StartSlave(ctx, tablet)
+	cleaner.recordDurable(&cleanerActionRecord{
+		Name:   StartSlaveActionName,
+		Target: topoproto.TabletAliasString(tablet.Alias),
+		Tablet: tablet,
 	})
 }
 
+func startSlaveAction(tablet *topodatapb.Tablet) CleanerFunction {
+	return func(ctx context.Context, wr *Wrangler) error {
+		return wr.TabletManagerClient().StartSlave(ctx, tablet)
+	}
+}
+
 //
 // VReplication CleanerAction
 //
@@ -182,8 +589,17 @@ const VReplicationActionName = "VReplicationAction"
 // RecordVReplicationAction records an action to restart binlog replication on a server
 // into the specified Cleaner
 func RecordVReplicationAction(cleaner *Cleaner, tablet *topodatapb.Tablet, query string) {
-	cleaner.Record(VReplicationActionName, topoproto.TabletAliasString(tablet.Alias), func(ctx context.Context, wr *Wrangler) error {
+	cleaner.recordDurable(&cleanerActionRecord{
+		Name:   VReplicationActionName,
+		Target: topoproto.TabletAliasString(tablet.Alias),
+		Tablet: tablet,
+		Query:  query,
+	})
+}
+
+func vReplicationAction(tablet *topodatapb.Tablet, query string) CleanerFunction {
+	return func(ctx context.Context, wr *Wrangler) error {
 		_, err := wr.TabletManagerClient().VReplicationExec(ctx, tablet, query)
 		return err
-	})
+	}
 }
@@ -0,0 +1,233 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"encoding/json"
+	"path"
+	"testing"
+
+	"golang.org/x/net/context"
+	"vitess.io/vitess/go/vt/logutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/faketmclient"
+
+	// import the gRPC client implementation for tablet manager
+	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
+)
+
+// readCleanerActions returns the names of the actions which are stored for
+// the durable Cleaner "id" of "keyspace"/"shard". It returns nil if there
+// are none.
+func readCleanerActions(t *testing.T, ts *topo.Server, keyspace, shard, id string) []string {
+	ctx := context.Background()
+	conn, err := ts.ConnForCell(ctx, topo.GlobalCell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := conn.Get(ctx, path.Join(cleanerActionsDir(keyspace, shard), id))
+	if topo.IsErrType(err, topo.NoNode) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := &cleanerActionsFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range file.Actions {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// setCleanerOwnerAlive makes cleanerOwnerAlive return "alive" until the
+// returned function is called.
+func setCleanerOwnerAlive(alive bool) func() {
+	old := cleanerOwnerAlive
+	cleanerOwnerAlive = func(context.Context, string) bool { return alive }
+	return func() { cleanerOwnerAlive = old }
+}
+
+func TestDurableCleaner(t *testing.T) {
+	defer setCleanerOwnerAlive(false)()
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := New(logutil.NewConsoleLogger(), ts, faketmclient.NewFakeTabletManagerClient())
+
+	alias := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	if err := ts.CreateTablet(ctx, &topodatapb.Tablet{
+		Alias:    alias,
+		Keyspace: "ks",
+		Shard:    "0",
+		Type:     topodatapb.TabletType_DRAINED,
+		Tags:     map[string]string{"worker": "http://vtworker:8080"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cleaner := NewDurableCleaner(ts, "ks", "0", "SplitDiff_ks_0_1", "http://vtworker:8080")
+	cleaner.Record("ReleaseTablet", "cell1-0000000001", func(context.Context, *Wrangler) error {
+		t.Errorf("in-memory actions must not be run by CleanupOrphanedWorkerActions")
+		return nil
+	})
+	RecordChangeSlaveTypeAction(cleaner, alias, topodatapb.TabletType_DRAINED, topodatapb.TabletType_RDONLY)
+	RecordTabletTagAction(cleaner, alias, "worker", "")
+	if got, want := readCleanerActions(t, ts, "ks", "0", "SplitDiff_ks_0_1"), []string{ChangeSlaveTypeActionName, TabletTagActionName}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("stored actions = %v, want = %v", got, want)
+	}
+
+	// The process dies without calling CleanUp(). A dry run does not
+	// change anything.
+	if err := wr.CleanupOrphanedWorkerActions(ctx, nil, "", "", true /* dryRun */); err != nil {
+		t.Fatal(err)
+	}
+	ti, err := ts.GetTablet(ctx, alias)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ti.Tags["worker"]; !ok {
+		t.Errorf("dry run must not remove the tag: %v", ti.Tags)
+	}
+	if got := readCleanerActions(t, ts, "ks", "0", "SplitDiff_ks_0_1"); len(got) != 2 {
+		t.Errorf("dry run must not remove the stored actions: %v", got)
+	}
+
+	if err := wr.CleanupOrphanedWorkerActions(ctx, nil, "", "", false /* dryRun */); err != nil {
+		t.Fatal(err)
+	}
+	ti, err = ts.GetTablet(ctx, alias)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ti.Tags["worker"]; ok {
+		t.Errorf("CleanupOrphanedWorkerActions must remove the tag: %v", ti.Tags)
+	}
+	if got := readCleanerActions(t, ts, "ks", "0", "SplitDiff_ks_0_1"); got != nil {
+		t.Errorf("stored actions after CleanupOrphanedWorkerActions = %v, want none", got)
+	}
+
+	// Without any stored actions, there is nothing to do.
+	if err := wr.CleanupOrphanedWorkerActions(ctx, nil, "", "", false /* dryRun */); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDurableCleanerKeepsFailedActions(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := New(logutil.NewConsoleLogger(), ts, faketmclient.NewFakeTabletManagerClient())
+
+	alias1 := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	alias2 := &topodatapb.TabletAlias{Cell: "cell1", Uid: 2}
+	for _, alias := range []*topodatapb.TabletAlias{alias1, alias2} {
+		if err := ts.CreateTablet(ctx, &topodatapb.Tablet{Alias: alias, Keyspace: "ks", Shard: "0", Type: topodatapb.TabletType_DRAINED}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cleaner := NewDurableCleaner(ts, "ks", "0", "SplitDiff_ks_0_2", "http://vtworker:8080")
+	// The type of the tablet does not match. This action fails.
+	RecordChangeSlaveTypeAction(cleaner, alias1, topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY)
+	RecordTabletTagAction(cleaner, alias2, "worker", "")
+
+	if err := cleaner.CleanUp(wr); err == nil {
+		t.Fatalf("CleanUp() should have failed")
+	}
+	if got, want := readCleanerActions(t, ts, "ks", "0", "SplitDiff_ks_0_2"), []string{ChangeSlaveTypeActionName}; len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("stored actions after a failed CleanUp() = %v, want = %v", got, want)
+	}
+}
+
+func TestCleanerChangedTablets(t *testing.T) {
+	alias1 := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	alias2 := &topodatapb.TabletAlias{Cell: "cell1", Uid: 2}
+
+	cleaner := &Cleaner{}
+	RecordChangeSlaveTypeAction(cleaner, alias1, topodatapb.TabletType_DRAINED, topodatapb.TabletType_RDONLY)
+	RecordTabletTagAction(cleaner, alias1, "worker", "")
+	RecordChangeSlaveTypeAction(cleaner, alias2, topodatapb.TabletType_DRAINED, topodatapb.TabletType_RDONLY)
+	RecordChangeSlaveTypeAction(cleaner, alias1, topodatapb.TabletType_DRAINED, topodatapb.TabletType_SPARE)
+
+	got := cleaner.ChangedTablets()
+	if len(got) != 2 || got[0] != alias1 || got[1] != alias2 {
+		t.Errorf("ChangedTablets() = %v, want = [%v %v]", got, alias1, alias2)
+	}
+}
+
+func TestCleanupOrphanedWorkerActionsOfShard(t *testing.T) {
+	defer setCleanerOwnerAlive(false)()
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := New(logutil.NewConsoleLogger(), ts, faketmclient.NewFakeTabletManagerClient())
+
+	alias1 := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	alias2 := &topodatapb.TabletAlias{Cell: "cell1", Uid: 2}
+	for i, alias := range []*topodatapb.TabletAlias{alias1, alias2} {
+		shard := []string{"-80", "80-"}[i]
+		if err := ts.CreateTablet(ctx, &topodatapb.Tablet{Alias: alias, Keyspace: "ks", Shard: shard, Type: topodatapb.TabletType_DRAINED}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cleaner1 := NewDurableCleaner(ts, "ks", "-80", "SplitDiff_ks_-80_1", "http://vtworker:8080")
+	RecordTabletTagAction(cleaner1, alias1, "worker", "")
+	cleaner2 := NewDurableCleaner(ts, "ks", "80-", "SplitDiff_ks_80-_1", "http://vtworker:8080")
+	RecordTabletTagAction(cleaner2, alias2, "worker", "")
+
+	if err := wr.CleanupOrphanedWorkerActions(ctx, nil, "ks", "80-", false /* dryRun */); err != nil {
+		t.Fatal(err)
+	}
+	if got := readCleanerActions(t, ts, "ks", "-80", "SplitDiff_ks_-80_1"); len(got) != 1 {
+		t.Errorf("actions on other shards must not be run: %v", got)
+	}
+	if got := readCleanerActions(t, ts, "ks", "80-", "SplitDiff_ks_80-_1"); got != nil {
+		t.Errorf("stored actions of the shard after CleanupOrphanedWorkerActions = %v, want none", got)
+	}
+}
+
+func TestCleanupOrphanedWorkerActionsSkipsLiveOwner(t *testing.T) {
+	defer setCleanerOwnerAlive(true)()
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := New(logutil.NewConsoleLogger(), ts, faketmclient.NewFakeTabletManagerClient())
+
+	alias := &topodatapb.TabletAlias{Cell: "cell1", Uid: 1}
+	if err := ts.CreateTablet(ctx, &topodatapb.Tablet{Alias: alias, Keyspace: "ks", Shard: "0", Type: topodatapb.TabletType_DRAINED}); err != nil {
+		t.Fatal(err)
+	}
+	cleaner := NewDurableCleaner(ts, "ks", "0", "SplitDiff_ks_0_3", "http://vtworker:8080")
+	RecordTabletTagAction(cleaner, alias, "worker", "")
+
+	// The vtworker which recorded the actions is still running. They must
+	// not be run.
+	if err := wr.CleanupOrphanedWorkerActions(ctx, nil, "", "", false /* dryRun */); err != nil {
+		t.Fatal(err)
+	}
+	if got := readCleanerActions(t, ts, "ks", "0", "SplitDiff_ks_0_3"); len(got) != 1 {
+		t.Errorf("the actions of a running owner must be kept: %v", got)
+	}
+
+	// An unknown id is an error.
+	if err := wr.CleanupOrphanedWorkerActions(ctx, []string{"unknown"}, "", "", false /* dryRun */); err == nil {
+		t.Errorf("CleanupOrphanedWorkerActions with an unknown id should have failed")
+	}
+}
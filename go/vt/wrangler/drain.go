@@ -0,0 +1,158 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// DrainedFromTag is the tablet tag in which DrainTablet records the type of
+// the tablet before it was drained. UndrainTablet restores this type.
+const DrainedFromTag = "drained_from"
+
+// replicationLagPollInterval is the time between two checks of the
+// replication lag in waitForReplicationLag.
+var replicationLagPollInterval = 1 * time.Second
+
+// DrainTablet takes a REPLICA or RDONLY tablet out of serving for
+// maintenance. The tablet is changed to DRAINED. The tablet announces that it
+// no longer serves via its health stream, which removes it from the
+// healthcheck of vtgate, and stops its query service after the in-flight
+// queries and transactions have finished. The original type is recorded in
+// the tablet tag DrainedFromTag for UndrainTablet.
+// If maxReplicationLag is not zero, DrainTablet waits afterwards until the
+// replication lag of the tablet is at most maxReplicationLag.
+func (wr *Wrangler) DrainTablet(ctx context.Context, tabletAlias *topodatapb.TabletAlias, maxReplicationLag time.Duration) error {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	switch ti.Type {
+	case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY:
+	case topodatapb.TabletType_DRAINED:
+		return fmt.Errorf("tablet %v is already drained, use UndrainTablet to make it serve again", topoproto.TabletAliasString(tabletAlias))
+	default:
+		return fmt.Errorf("tablet %v is a %v tablet, only REPLICA and RDONLY tablets can be drained", topoproto.TabletAliasString(tabletAlias), ti.Type)
+	}
+
+	// Record the type first. If the type change fails halfway,
+	// UndrainTablet can still restore it.
+	if _, err := wr.ts.UpdateTabletFields(ctx, tabletAlias, func(tablet *topodatapb.Tablet) error {
+		if tablet.Tags == nil {
+			tablet.Tags = make(map[string]string)
+		}
+		tablet.Tags[DrainedFromTag] = ti.Type.String()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("cannot record the type of tablet %v: %v", topoproto.TabletAliasString(tabletAlias), err)
+	}
+
+	// ChangeType returns after the tablet went through its lameduck period
+	// (-serving_state_grace_period) and its query service was stopped.
+	wr.Logger().Infof("Draining tablet %v (%v)", topoproto.TabletAliasString(tabletAlias), ti.Type)
+	if err := wr.tmc.ChangeType(ctx, ti.Tablet, topodatapb.TabletType_DRAINED); err != nil {
+		return fmt.Errorf("cannot drain tablet %v: %v", topoproto.TabletAliasString(tabletAlias), err)
+	}
+
+	if maxReplicationLag > 0 {
+		return wr.waitForReplicationLag(ctx, ti, maxReplicationLag)
+	}
+	return nil
+}
+
+// UndrainTablet makes a tablet which was drained with DrainTablet serve
+// again. If tabletType is UNKNOWN, the type recorded by DrainTablet is
+// restored. If maxReplicationLag is not zero, UndrainTablet waits until the
+// replication lag of the tablet is at most maxReplicationLag before the
+// tablet serves again. This way, it does not serve stale data after a long
+// maintenance.
+func (wr *Wrangler) UndrainTablet(ctx context.Context, tabletAlias *topodatapb.TabletAlias, tabletType topodatapb.TabletType, maxReplicationLag time.Duration) error {
+	ti, err := wr.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return err
+	}
+	if ti.Type != topodatapb.TabletType_DRAINED {
+		return fmt.Errorf("tablet %v is a %v tablet and not drained", topoproto.TabletAliasString(tabletAlias), ti.Type)
+	}
+	if tabletType == topodatapb.TabletType_UNKNOWN {
+		drainedFrom, ok := ti.Tags[DrainedFromTag]
+		if !ok {
+			return fmt.Errorf("tablet %v has no %v tag. It was not drained with DrainTablet, specify the tablet type", topoproto.TabletAliasString(tabletAlias), DrainedFromTag)
+		}
+		if tabletType, err = topoproto.ParseTabletType(drainedFrom); err != nil {
+			return fmt.Errorf("tablet %v has an invalid %v tag: %v", topoproto.TabletAliasString(tabletAlias), DrainedFromTag, err)
+		}
+	}
+	if !topo.IsInServingGraph(tabletType) || tabletType == topodatapb.TabletType_MASTER {
+		return fmt.Errorf("cannot undrain tablet %v to %v, only REPLICA and RDONLY are allowed", topoproto.TabletAliasString(tabletAlias), tabletType)
+	}
+
+	if maxReplicationLag > 0 {
+		if err := wr.waitForReplicationLag(ctx, ti, maxReplicationLag); err != nil {
+			return err
+		}
+	}
+
+	wr.Logger().Infof("Undraining tablet %v to %v", topoproto.TabletAliasString(tabletAlias), tabletType)
+	if err := wr.tmc.ChangeType(ctx, ti.Tablet, tabletType); err != nil {
+		return fmt.Errorf("cannot undrain tablet %v: %v", topoproto.TabletAliasString(tabletAlias), err)
+	}
+
+	_, err = wr.ts.UpdateTabletFields(ctx, tabletAlias, func(tablet *topodatapb.Tablet) error {
+		if _, ok := tablet.Tags[DrainedFromTag]; !ok {
+			return topo.NewError(topo.NoUpdateNeeded, topoproto.TabletAliasString(tabletAlias))
+		}
+		delete(tablet.Tags, DrainedFromTag)
+		return nil
+	})
+	return err
+}
+
+// waitForReplicationLag waits until the replication of the tablet runs and
+// its lag is at most maxLag. It gives up when ctx is done.
+func (wr *Wrangler) waitForReplicationLag(ctx context.Context, ti *topo.TabletInfo, maxLag time.Duration) error {
+	for {
+		status, err := wr.tmc.SlaveStatus(ctx, ti.Tablet)
+		if err != nil {
+			return fmt.Errorf("cannot get the replication status of tablet %v: %v", ti.AliasString(), err)
+		}
+		lag := time.Duration(status.SecondsBehindMaster) * time.Second
+		if status.SlaveIoRunning && status.SlaveSqlRunning && lag <= maxLag {
+			wr.Logger().Infof("Replication lag of tablet %v is %v", ti.AliasString(), lag)
+			return nil
+		}
+		if status.SlaveIoRunning && status.SlaveSqlRunning {
+			wr.Logger().Infof("Waiting for the replication lag of tablet %v to settle: %v > %v", ti.AliasString(), lag, maxLag)
+		} else {
+			wr.Logger().Infof("Waiting for the replication of tablet %v to run", ti.AliasString())
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the replication lag of tablet %v did not settle below %v: %v", ti.AliasString(), maxLag, ctx.Err())
+		case <-time.After(replicationLagPollInterval):
+		}
+	}
+}
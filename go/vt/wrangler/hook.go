@@ -18,10 +18,17 @@ package wrangler
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/concurrency"
 	hk "vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
@@ -42,3 +49,93 @@ func (wr *Wrangler) ExecuteHook(ctx context.Context, tabletAlias *topodatapb.Tab
 func (wr *Wrangler) ExecuteTabletHook(ctx context.Context, tablet *topodatapb.Tablet, hook *hk.Hook) (hookResult *hk.HookResult, err error) {
 	return wr.tmc.ExecuteHook(ctx, tablet, hook)
 }
+
+// ExecuteHookOnTablets runs the hook on all tablets of the keyspace which
+// are in one of the shards and have one of the tablet types. An empty list
+// of shards or tablet types matches all of them. "sema" limits how many
+// hooks run at the same time. As soon as the hook finished on a tablet,
+// its stdout and stderr are logged line by line with the tablet alias as
+// prefix. The returned error lists the tablets on which the hook failed.
+func (wr *Wrangler) ExecuteHookOnTablets(ctx context.Context, keyspace string, shards []string, tabletTypes []topodatapb.TabletType, hook *hk.Hook, sema *sync2.Semaphore) error {
+	if strings.Contains(hook.Name, "/") {
+		return fmt.Errorf("hook name cannot have a '/' in it")
+	}
+	if len(shards) == 0 {
+		var err error
+		shards, err = wr.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return fmt.Errorf("GetShardNames(%v) failed: %v", keyspace, err)
+		}
+	}
+
+	var tablets []*topo.TabletInfo
+	for _, shard := range shards {
+		tabletMap, err := wr.ts.GetTabletMapForShard(ctx, keyspace, shard)
+		if err != nil {
+			return fmt.Errorf("GetTabletMapForShard(%v, %v) failed: %v", keyspace, shard, err)
+		}
+		for _, ti := range tabletMap {
+			if len(tabletTypes) > 0 && !topoproto.IsTypeInList(ti.Type, tabletTypes) {
+				continue
+			}
+			tablets = append(tablets, ti)
+		}
+	}
+	if len(tablets) == 0 {
+		return fmt.Errorf("no tablets in keyspace %v match the shards %v and the tablet types %v", keyspace, shards, tabletTypes)
+	}
+	sort.Slice(tablets, func(i, j int) bool {
+		return tablets[i].AliasString() < tablets[j].AliasString()
+	})
+
+	var mu sync.Mutex
+	var failed []string
+	er := concurrency.AllErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for _, ti := range tablets {
+		wg.Add(1)
+		go func(ti *topo.TabletInfo) {
+			defer wg.Done()
+			sema.Acquire()
+			defer sema.Release()
+
+			hr, err := wr.ExecuteTabletHook(ctx, ti.Tablet, hook)
+			if err != nil {
+				er.RecordError(fmt.Errorf("ExecuteHook(%v) failed: %v", ti.AliasString(), err))
+				return
+			}
+
+			// Log the output of one tablet together, so it doesn't
+			// interleave with the output of other tablets.
+			mu.Lock()
+			defer mu.Unlock()
+			wr.logHookOutput(ti.AliasString(), "stdout", hr.Stdout)
+			wr.logHookOutput(ti.AliasString(), "stderr", hr.Stderr)
+			if hr.ExitStatus != hk.HOOK_SUCCESS {
+				failed = append(failed, fmt.Sprintf("%v (exit status %v)", ti.AliasString(), hr.ExitStatus))
+			}
+		}(ti)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		er.RecordError(fmt.Errorf("hook %v failed on %v of %v tablets: %v", hook.Name, len(failed), len(tablets), strings.Join(failed, ", ")))
+	}
+	if er.HasErrors() {
+		return er.Error()
+	}
+	wr.Logger().Printf("hook %v succeeded on %v tablets\n", hook.Name, len(tablets))
+	return nil
+}
+
+// logHookOutput logs each line of the output of a hook with the tablet
+// alias and the stream name as prefix.
+func (wr *Wrangler) logHookOutput(alias, stream, output string) {
+	if output == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		wr.Logger().Printf("%v %v: %v\n", alias, stream, line)
+	}
+}
@@ -225,20 +225,8 @@ func (wr *Wrangler) cancelHorizontalResharding(ctx context.Context, keyspace, sh
 // MigrateServedTypes is used during horizontal splits to migrate a
 // served type from a list of shards to another.
 func (wr *Wrangler) MigrateServedTypes(ctx context.Context, keyspace, shard string, cells []string, servedType topodatapb.TabletType, reverse, skipReFreshState bool, filteredReplicationWaitTime time.Duration, reverseReplication bool) (err error) {
-	// check input parameters
-	if servedType == topodatapb.TabletType_MASTER {
-		// we cannot migrate a master back, since when master migration
-		// is done, the source shards are dead
-		if reverse {
-			return fmt.Errorf("Cannot migrate master back to %v/%v", keyspace, shard)
-		}
-		// we cannot skip refresh state for a master
-		if skipReFreshState {
-			return fmt.Errorf("Cannot skip refresh state for master migration on %v/%v", keyspace, shard)
-		}
-		if cells != nil {
-			return fmt.Errorf("Cannot specify cells for master migration on %v/%v", keyspace, shard)
-		}
+	if err := checkMigrateServedTypesParameters(keyspace, shard, cells, servedType, reverse, skipReFreshState); err != nil {
+		return err
 	}
 
 	// lock the keyspace
@@ -248,35 +236,24 @@ func (wr *Wrangler) MigrateServedTypes(ctx context.Context, keyspace, shard stri
 	}
 	defer unlock(&err)
 
-	// find overlapping shards in this keyspace
-	wr.Logger().Infof("Finding the overlapping shards in keyspace %v", keyspace)
-	osList, err := topotools.FindOverlappingShards(ctx, wr.ts, keyspace)
+	plan, err := wr.planMigrateServedTypes(ctx, keyspace, shard, cells, servedType, reverse, reverseReplication)
 	if err != nil {
-		return fmt.Errorf("FindOverlappingShards failed: %v", err)
+		return err
 	}
 
-	// find our shard in there
-	os := topotools.OverlappingShardsForShard(osList, shard)
-	if os == nil {
-		return fmt.Errorf("Shard %v is not involved in any overlapping shards", shard)
+	// execute the migration
+	switch {
+	case servedType == topodatapb.TabletType_MASTER && reverse:
+		err = wr.reverseMasterMigrateServedType(ctx, plan, filteredReplicationWaitTime)
+	case servedType == topodatapb.TabletType_MASTER:
+		err = wr.masterMigrateServedType(ctx, plan, filteredReplicationWaitTime)
+	default:
+		err = wr.replicaMigrateServedType(ctx, plan)
 	}
-
-	sourceShards, destinationShards, err := wr.findSourceDest(ctx, os)
 	if err != nil {
 		return err
 	}
 
-	// execute the migration
-	if servedType == topodatapb.TabletType_MASTER {
-		if err = wr.masterMigrateServedType(ctx, keyspace, sourceShards, destinationShards, filteredReplicationWaitTime, reverseReplication); err != nil {
-			return err
-		}
-	} else {
-		if err = wr.replicaMigrateServedType(ctx, keyspace, sourceShards, destinationShards, cells, servedType, reverse); err != nil {
-			return err
-		}
-	}
-
 	// rebuild the keyspace serving graph now that there is no error
 	if err = topotools.RebuildKeyspaceLocked(ctx, wr.logger, wr.ts, keyspace, cells); err != nil {
 		return err
@@ -300,17 +277,193 @@ func (wr *Wrangler) MigrateServedTypes(ctx context.Context, keyspace, shard stri
 	wr.Logger().Infof("WaitForDrain: Sleeping finished. Shutting down queryservice on old tablets now.")
 
 	rec := concurrency.AllErrorRecorder{}
-	refreshShards := sourceShards
-	if reverse {
-		// For a backwards migration, we should refresh (disable) destination shards instead.
-		refreshShards = destinationShards
-	}
-	for _, si := range refreshShards {
+	for _, si := range plan.fromShards {
 		rec.RecordError(wr.RefreshTabletsByShard(ctx, si, []topodatapb.TabletType{servedType}, cells))
 	}
 	return rec.Error()
 }
 
+// migrateServedTypesPlan describes the changes of MigrateServedTypes.
+// It is computed by planMigrateServedTypes from the current shard records
+// and then either applied by MigrateServedTypes or logged by
+// MigrateServedTypesDryRun.
+type migrateServedTypesPlan struct {
+	keyspace           string
+	servedType         topodatapb.TabletType
+	cells              []string
+	reverse            bool
+	reverseReplication bool
+
+	// sourceShards and destinationShards are the shards of the split,
+	// see findSourceDest.
+	sourceShards      []*topo.ShardInfo
+	destinationShards []*topo.ShardInfo
+	// fromShards stop serving servedType and toShards start serving it.
+	// For a reverse master migration, fromShards are the shards which
+	// currently serve the master and toShards the shards which served it
+	// before the last master migration.
+	fromShards []*topo.ShardInfo
+	toShards   []*topo.ShardInfo
+	// newFromShards and newToShards are copies of the records of
+	// fromShards and toShards as they will be after the migration. For
+	// the master, the ids of the reverse replication streams are unknown.
+	newFromShards []*topo.ShardInfo
+	newToShards   []*topo.ShardInfo
+
+	// startReverseReplication is set for a reverse master migration if
+	// the reverse replication to toShards is not running yet.
+	startReverseReplication bool
+	// reverseBlockedErr is set for a reverse master migration if the
+	// master cannot be migrated back yet. The reverse replication is
+	// started anyway, which makes toShards the destination of the split.
+	reverseBlockedErr error
+}
+
+// planMigrateServedTypes checks that "servedType" can be migrated and
+// computes the plan of the migration. It only reads the topology.
+// MigrateServedTypes must hold the keyspace lock while computing and
+// applying the plan.
+func (wr *Wrangler) planMigrateServedTypes(ctx context.Context, keyspace, shard string, cells []string, servedType topodatapb.TabletType, reverse, reverseReplication bool) (*migrateServedTypesPlan, error) {
+	sourceShards, destinationShards, err := wr.findMigrateServedTypesShards(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !reverse {
+		wr.warnIfNotDiffVerified(ctx, destinationShards)
+	}
+
+	p := &migrateServedTypesPlan{
+		keyspace:           keyspace,
+		servedType:         servedType,
+		cells:              cells,
+		reverse:            reverse,
+		reverseReplication: reverseReplication,
+		sourceShards:       sourceShards,
+		destinationShards:  destinationShards,
+		fromShards:         sourceShards,
+		toShards:           destinationShards,
+	}
+	switch {
+	case servedType == topodatapb.TabletType_MASTER && reverse:
+		if p.fromShards, p.toShards, err = reverseMasterMigrationShards(sourceShards, destinationShards); err != nil {
+			return nil, err
+		}
+		p.startReverseReplication = isFrozen(p.toShards)
+		if err := checkOtherServedTypesMigrated(p.fromShards); err != nil {
+			p.reverseBlockedErr = fmt.Errorf("the reverse replication to %v is running, but the master cannot be migrated back yet: %v. The shards %v are now the destination of the split, migrate the other types to them with MigrateServedTypes (without -reverse) and run this command again", shardNames(p.toShards), err, shardNames(p.toShards))
+		}
+	case servedType == topodatapb.TabletType_MASTER:
+		if err := checkOtherServedTypesMigrated(p.fromShards); err != nil {
+			return nil, err
+		}
+	default:
+		if reverse && isFrozen(sourceShards) {
+			// The source shards would serve stale data.
+			return nil, fmt.Errorf("cannot migrate %v back to %v because their master was migrated and no filtered replication to them is running. Run 'MigrateServedTypes -reverse %v/%v master' first to start the reverse replication", servedType, shardNames(sourceShards), keyspace, shard)
+		}
+		if reverse {
+			p.fromShards, p.toShards = destinationShards, sourceShards
+		}
+	}
+
+	// Apply the changes to copies of the shard records. This also checks
+	// that the changes are possible, e.g. that no frozen shard would
+	// serve again.
+	p.newFromShards = cloneShardInfos(p.fromShards)
+	p.newToShards = cloneShardInfos(p.toShards)
+	if p.startReverseReplication {
+		for _, si := range p.newToShards {
+			if err := setFrozen(si, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p.reverseBlockedErr != nil {
+		return p, nil
+	}
+	for _, si := range p.newFromShards {
+		if err := p.updateFromShard(si); err != nil {
+			return nil, err
+		}
+		if servedType != topodatapb.TabletType_MASTER {
+			continue
+		}
+		if len(si.SourceShards) == 0 {
+			addReverseSourceShards(si, p.toShards, nil)
+		}
+		if !reverseReplication {
+			if err := setFrozen(si, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, si := range p.newToShards {
+		if err := p.updateToShard(si); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// updateFromShard changes the record of a shard which stops serving the
+// type.
+func (p *migrateServedTypesPlan) updateFromShard(si *topo.ShardInfo) error {
+	if err := si.UpdateServedTypesMap(p.servedType, p.cells, true /* remove */); err != nil {
+		return err
+	}
+	return si.SetDisableQueryService(p.servedType, p.cells, true /* disable */)
+}
+
+// updateToShard changes the record of a shard which starts serving the
+// type. A shard which starts serving the master no longer has filtered
+// replication.
+func (p *migrateServedTypesPlan) updateToShard(si *topo.ShardInfo) error {
+	if err := si.UpdateServedTypesMap(p.servedType, p.cells, false /* remove */); err != nil {
+		return err
+	}
+	if err := si.SetDisableQueryService(p.servedType, p.cells, false /* disable */); err != nil {
+		return err
+	}
+	if p.servedType == topodatapb.TabletType_MASTER {
+		si.SourceShards = nil
+	}
+	return nil
+}
+
+// checkMigrateServedTypesParameters checks the input parameters of
+// MigrateServedTypes.
+func checkMigrateServedTypesParameters(keyspace, shard string, cells []string, servedType topodatapb.TabletType, reverse, skipReFreshState bool) error {
+	if servedType == topodatapb.TabletType_MASTER {
+		// we cannot skip refresh state for a master
+		if skipReFreshState {
+			return fmt.Errorf("Cannot skip refresh state for master migration on %v/%v", keyspace, shard)
+		}
+		if cells != nil {
+			return fmt.Errorf("Cannot specify cells for master migration on %v/%v", keyspace, shard)
+		}
+	}
+	return nil
+}
+
+// findMigrateServedTypesShards returns the source and destination shards of
+// the split which "shard" is part of.
+func (wr *Wrangler) findMigrateServedTypesShards(ctx context.Context, keyspace, shard string) (sourceShards, destinationShards []*topo.ShardInfo, err error) {
+	// find overlapping shards in this keyspace
+	wr.Logger().Infof("Finding the overlapping shards in keyspace %v", keyspace)
+	osList, err := topotools.FindOverlappingShards(ctx, wr.ts, keyspace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("FindOverlappingShards failed: %v", err)
+	}
+
+	// find our shard in there
+	os := topotools.OverlappingShardsForShard(osList, shard)
+	if os == nil {
+		return nil, nil, fmt.Errorf("Shard %v is not involved in any overlapping shards", shard)
+	}
+
+	return wr.findSourceDest(ctx, os)
+}
+
 // findSourceDest derives the source and destination from the overlapping shards.
 // Whichever side has SourceShards is a destination.
 func (wr *Wrangler) findSourceDest(ctx context.Context, os *topotools.OverlappingShards) (sourceShards, destinationShards []*topo.ShardInfo, err error) {
@@ -348,6 +501,23 @@ func (wr *Wrangler) findSourceDest(ctx context.Context, os *topotools.Overlappin
 	return nil, nil, fmt.Errorf("neither Shard '%v' nor Shard '%v' have a 'SourceShards' entry. Did you successfully run vtworker SplitClone before? Or did you already migrate the MASTER type?", os.Left[0].ShardName(), os.Right[0].ShardName())
 }
 
+// warnIfNotDiffVerified logs a warning for each of "shards" which was never
+// successfully compared against its source (e.g. by vtworker SplitDiff).
+// It does not fail the migration because the diff is optional.
+func (wr *Wrangler) warnIfNotDiffVerified(ctx context.Context, shards []*topo.ShardInfo) {
+	for _, si := range shards {
+		verification, err := wr.ts.GetShardDiffVerification(ctx, si.Keyspace(), si.ShardName())
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			wr.Logger().Warningf("Shard %v/%v was never verified by a diff (e.g. vtworker SplitDiff or VerticalSplitDiff). Migrating traffic to it without a diff is not recommended.", si.Keyspace(), si.ShardName())
+		case err != nil:
+			wr.Logger().Warningf("Cannot read the diff verification of shard %v/%v: %v", si.Keyspace(), si.ShardName(), err)
+		default:
+			wr.Logger().Infof("Shard %v/%v was last verified by %v against %v at position %v (%v)", si.Keyspace(), si.ShardName(), verification.Command, verification.Source, verification.Position, verification.Time)
+		}
+	}
+}
+
 func (wr *Wrangler) getMastersPosition(ctx context.Context, shards []*topo.ShardInfo) (map[*topo.ShardInfo]string, error) {
 	mu := sync.Mutex{}
 	result := make(map[*topo.ShardInfo]string)
@@ -451,14 +621,15 @@ func (wr *Wrangler) refreshMasters(ctx context.Context, shards []*topo.ShardInfo
 	return rec.Error()
 }
 
-// replicaMigrateServedType operates with the keyspace locked
-func (wr *Wrangler) replicaMigrateServedType(ctx context.Context, keyspace string, sourceShards, destinationShards []*topo.ShardInfo, cells []string, servedType topodatapb.TabletType, reverse bool) (err error) {
+// replicaMigrateServedType applies the plan of a non-master migration.
+// It operates with the keyspace locked.
+func (wr *Wrangler) replicaMigrateServedType(ctx context.Context, p *migrateServedTypesPlan) (err error) {
 	ev := &events.MigrateServedTypes{
-		KeyspaceName:      keyspace,
-		SourceShards:      sourceShards,
-		DestinationShards: destinationShards,
-		ServedType:        servedType,
-		Reverse:           reverse,
+		KeyspaceName:      p.keyspace,
+		SourceShards:      p.sourceShards,
+		DestinationShards: p.destinationShards,
+		ServedType:        p.servedType,
+		Reverse:           p.reverse,
 	}
 	event.DispatchUpdate(ev, "start")
 	defer func() {
@@ -467,43 +638,34 @@ func (wr *Wrangler) replicaMigrateServedType(ctx context.Context, keyspace strin
 		}
 	}()
 
-	fromShards, toShards := sourceShards, destinationShards
-	if reverse {
-		fromShards, toShards = toShards, fromShards
-	}
-
 	// Check and update all source shard records.
 	// Enable query service if needed
 	event.DispatchUpdate(ev, "updating shards to migrate from")
-	if err = wr.updateShardRecords(ctx, fromShards, cells, servedType, true); err != nil {
+	if err = wr.updateShards(ctx, p.fromShards, p.updateFromShard); err != nil {
 		return err
 	}
 
 	// Do the same for destination shards
 	event.DispatchUpdate(ev, "updating shards to migrate to")
-	if err = wr.updateShardRecords(ctx, toShards, cells, servedType, false); err != nil {
+	if err = wr.updateShards(ctx, p.toShards, p.updateToShard); err != nil {
 		return err
 	}
+	// Refresh the destination shards to make them serve.
+	// The source shards will be refreshed after traffic has migrated.
+	for _, si := range p.toShards {
+		wr.RefreshTabletsByShard(ctx, si, []topodatapb.TabletType{p.servedType}, p.cells)
+	}
 
 	event.DispatchUpdate(ev, "finished")
 	return nil
 }
 
-// masterMigrateServedType operates with the keyspace locked
-func (wr *Wrangler) masterMigrateServedType(ctx context.Context, keyspace string, sourceShards, destinationShards []*topo.ShardInfo, filteredReplicationWaitTime time.Duration, reverseReplication bool) (err error) {
-	// Ensure other served types have migrated.
-	if si := sourceShards[0]; len(si.ServedTypes) > 1 {
-		var types []string
-		for _, servedType := range si.ServedTypes {
-			if servedType.TabletType != topodatapb.TabletType_MASTER {
-				types = append(types, servedType.TabletType.String())
-			}
-		}
-		return fmt.Errorf("cannot migrate MASTER away from %v/%v until everything else is migrated. Make sure that the following types are migrated first: %v", si.Keyspace(), si.ShardName(), strings.Join(types, ", "))
-	}
-
+// masterMigrateServedType applies the plan of a master migration from
+// p.fromShards to p.toShards. It operates with the keyspace locked.
+func (wr *Wrangler) masterMigrateServedType(ctx context.Context, p *migrateServedTypesPlan, filteredReplicationWaitTime time.Duration) (err error) {
+	sourceShards, destinationShards := p.fromShards, p.toShards
 	ev := &events.MigrateServedTypes{
-		KeyspaceName:      keyspace,
+		KeyspaceName:      p.keyspace,
 		SourceShards:      sourceShards,
 		DestinationShards: destinationShards,
 		ServedType:        topodatapb.TabletType_MASTER,
@@ -521,7 +683,7 @@ func (wr *Wrangler) masterMigrateServedType(ctx context.Context, keyspace string
 	// - wait for filtered replication to catch up
 	// - mark source shards as frozen
 	event.DispatchUpdate(ev, "disabling query service on all source masters")
-	if err := wr.updateShardRecords(ctx, sourceShards, nil, topodatapb.TabletType_MASTER, true); err != nil {
+	if err := wr.updateShards(ctx, sourceShards, p.updateFromShard); err != nil {
 		wr.cancelMasterMigrateServedTypes(ctx, sourceShards)
 		return err
 	}
@@ -556,7 +718,8 @@ func (wr *Wrangler) masterMigrateServedType(ctx context.Context, keyspace string
 		return err
 	}
 
-	// Destination shards need different handling than what updateShardRecords does.
+	// Destination shards need different handling than the source shards:
+	// their filtered replication is removed.
 	event.DispatchUpdate(ev, "updating destination shards")
 	for i, si := range destinationShards {
 		ti, err := wr.ts.GetTablet(ctx, si.MasterAlias)
@@ -569,19 +732,7 @@ func (wr *Wrangler) masterMigrateServedType(ctx context.Context, keyspace string
 				return err
 			}
 		}
-		// Similar to updateShardRecords, but we also remove SourceShards.
-		destinationShards[i], err = wr.ts.UpdateShardFields(ctx, si.Keyspace(), si.ShardName(), func(si *topo.ShardInfo) error {
-			if err := si.UpdateServedTypesMap(topodatapb.TabletType_MASTER, nil, false); err != nil {
-				return err
-			}
-			if err := si.UpdateDisableQueryService(ctx, topodatapb.TabletType_MASTER, nil, false); err != nil {
-				return err
-			}
-
-			si.SourceShards = nil
-			return nil
-		})
-		if err != nil {
+		if destinationShards[i], err = wr.updateShard(ctx, si, p.updateToShard); err != nil {
 			return err
 		}
 	}
@@ -591,7 +742,7 @@ func (wr *Wrangler) masterMigrateServedType(ctx context.Context, keyspace string
 		return err
 	}
 
-	if reverseReplication {
+	if p.reverseReplication {
 		if err := wr.startReverseReplication(ctx, sourceShards); err != nil {
 			return err
 		}
@@ -605,6 +756,96 @@ func (wr *Wrangler) masterMigrateServedType(ctx context.Context, keyspace string
 	return nil
 }
 
+// reverseMasterMigrateServedType migrates the master back to the shards which
+// served it before the last master migration. It requires the reverse
+// replication which masterMigrateServedType set up. If the reverse
+// replication was not started yet, it is started first. Then the master is
+// migrated with the roles of source and destination shards swapped, i.e. the
+// filtered replication is set up in the original direction again.
+// There is no equivalent for vertical splits, see checkMigrateServedFrom.
+// It operates with the keyspace locked.
+func (wr *Wrangler) reverseMasterMigrateServedType(ctx context.Context, p *migrateServedTypesPlan, filteredReplicationWaitTime time.Duration) error {
+	if p.startReverseReplication {
+		wr.Logger().Infof("Starting the reverse replication to %v", shardNames(p.toShards))
+		if err := wr.startReverseReplication(ctx, p.toShards); err != nil {
+			return err
+		}
+		if err := wr.updateFrozenFlag(ctx, p.toShards, false); err != nil {
+			return err
+		}
+	}
+	if p.reverseBlockedErr != nil {
+		return p.reverseBlockedErr
+	}
+	return wr.masterMigrateServedType(ctx, p, filteredReplicationWaitTime)
+}
+
+// reverseMasterMigrationShards returns the shards which currently serve the
+// master ("fromShards") and the shards which served it before the last master
+// migration ("toShards"). It fails if there is no reverse replication from
+// each of "fromShards" to each of "toShards".
+func reverseMasterMigrationShards(sourceShards, destinationShards []*topo.ShardInfo) (fromShards, toShards []*topo.ShardInfo, err error) {
+	fromShards, toShards = destinationShards, sourceShards
+	if sourceShards[0].GetServedType(topodatapb.TabletType_MASTER) != nil {
+		fromShards, toShards = sourceShards, destinationShards
+	}
+	for _, from := range fromShards {
+		if from.GetServedType(topodatapb.TabletType_MASTER) == nil {
+			return nil, nil, fmt.Errorf("cannot migrate master back: shard %v/%v does not serve the master. The master was not migrated yet", from.Keyspace(), from.ShardName())
+		}
+	}
+	for _, to := range toShards {
+		for _, from := range fromShards {
+			found := false
+			for _, ss := range to.SourceShards {
+				if ss.Keyspace == from.Keyspace() && ss.Shard == from.ShardName() {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, nil, fmt.Errorf("cannot migrate master back: shard %v/%v has no reverse replication from %v/%v", to.Keyspace(), to.ShardName(), from.Keyspace(), from.ShardName())
+			}
+		}
+	}
+	return fromShards, toShards, nil
+}
+
+// isFrozen returns true if the master tablet control of one of the shards
+// is frozen, i.e. the master was migrated away and no filtered replication
+// to the shard is running.
+func isFrozen(shards []*topo.ShardInfo) bool {
+	for _, si := range shards {
+		if tc := si.GetTabletControl(topodatapb.TabletType_MASTER); tc != nil && tc.Frozen {
+			return true
+		}
+	}
+	return false
+}
+
+func shardNames(shards []*topo.ShardInfo) string {
+	var names []string
+	for _, si := range shards {
+		names = append(names, topoproto.KeyspaceShardString(si.Keyspace(), si.ShardName()))
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkOtherServedTypesMigrated returns an error if the source shards still
+// serve other types than MASTER.
+func checkOtherServedTypesMigrated(sourceShards []*topo.ShardInfo) error {
+	if si := sourceShards[0]; len(si.ServedTypes) > 1 {
+		var types []string
+		for _, servedType := range si.ServedTypes {
+			if servedType.TabletType != topodatapb.TabletType_MASTER {
+				types = append(types, servedType.TabletType.String())
+			}
+		}
+		return fmt.Errorf("cannot migrate MASTER away from %v/%v until everything else is migrated. Make sure that the following types are migrated first: %v", si.Keyspace(), si.ShardName(), strings.Join(types, ", "))
+	}
+	return nil
+}
+
 func (wr *Wrangler) cancelMasterMigrateServedTypes(ctx context.Context, sourceShards []*topo.ShardInfo) {
 	if err := wr.updateShardRecords(ctx, sourceShards, nil, topodatapb.TabletType_MASTER, false); err != nil {
 		wr.Logger().Errorf("failed to re-enable source masters: %v", err)
@@ -660,14 +901,7 @@ func (wr *Wrangler) setupReverseReplication(ctx context.Context, sourceShards, d
 		// If this fails, there's no harm because the unstarted vreplication streams will just be abandoned.
 		var err error
 		sourceShards[i], err = wr.ts.UpdateShardFields(ctx, sourceShard.Keyspace(), sourceShard.ShardName(), func(si *topo.ShardInfo) error {
-			for j, dest := range destinationShards {
-				si.SourceShards = append(si.SourceShards, &topodatapb.Shard_SourceShard{
-					Uid:      uids[j],
-					Keyspace: dest.Keyspace(),
-					Shard:    dest.ShardName(),
-					KeyRange: dest.KeyRange,
-				})
-			}
+			addReverseSourceShards(si, destinationShards, uids)
 			return nil
 		})
 		if err != nil {
@@ -678,6 +912,24 @@ func (wr *Wrangler) setupReverseReplication(ctx context.Context, sourceShards, d
 	return nil
 }
 
+// addReverseSourceShards adds the reverse replication from each of
+// "destinationShards" to the record of the source shard "si". "uids" are the
+// ids of the VReplication streams. They are nil if the streams were not
+// created, e.g. in a dry run.
+func addReverseSourceShards(si *topo.ShardInfo, destinationShards []*topo.ShardInfo, uids []uint32) {
+	for j, dest := range destinationShards {
+		ss := &topodatapb.Shard_SourceShard{
+			Keyspace: dest.Keyspace(),
+			Shard:    dest.ShardName(),
+			KeyRange: dest.KeyRange,
+		}
+		if uids != nil {
+			ss.Uid = uids[j]
+		}
+		si.SourceShards = append(si.SourceShards, ss)
+	}
+}
+
 func (wr *Wrangler) startReverseReplication(ctx context.Context, sourceShards []*topo.ShardInfo) error {
 	for _, sourceShard := range sourceShards {
 		for _, dest := range sourceShard.SourceShards {
@@ -712,25 +964,47 @@ func (wr *Wrangler) updateShardRecords(ctx context.Context, shards []*topo.Shard
 	return nil
 }
 
-// updateFrozenFlag sets or unsets the Frozen flag for master migration. This is performed
-// for all master tablet control records.
-func (wr *Wrangler) updateFrozenFlag(ctx context.Context, shards []*topo.ShardInfo, value bool) (err error) {
+// updateShards rewrites the records of "shards" with "update" and replaces
+// them with the new records. The keyspace must be locked.
+func (wr *Wrangler) updateShards(ctx context.Context, shards []*topo.ShardInfo, update func(*topo.ShardInfo) error) (err error) {
 	for i, si := range shards {
-		shards[i], err = wr.ts.UpdateShardFields(ctx, si.Keyspace(), si.ShardName(), func(si *topo.ShardInfo) error {
-			tc := si.GetTabletControl(topodatapb.TabletType_MASTER)
-			if tc == nil {
-				return fmt.Errorf("unexpected: missing tablet control record for source %v/%v", si.Keyspace(), si.ShardName())
-			}
-			tc.Frozen = value
-			return nil
-		})
-		if err != nil {
+		if shards[i], err = wr.updateShard(ctx, si, update); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// updateShard rewrites the record of "si" with "update" and returns the new
+// record. The keyspace must be locked.
+func (wr *Wrangler) updateShard(ctx context.Context, si *topo.ShardInfo, update func(*topo.ShardInfo) error) (*topo.ShardInfo, error) {
+	return wr.ts.UpdateShardFields(ctx, si.Keyspace(), si.ShardName(), func(si *topo.ShardInfo) error {
+		if err := topo.CheckKeyspaceLocked(ctx, si.Keyspace()); err != nil {
+			return err
+		}
+		return update(si)
+	})
+}
+
+// updateFrozenFlag sets or unsets the Frozen flag for master migration. This is performed
+// for all master tablet control records.
+func (wr *Wrangler) updateFrozenFlag(ctx context.Context, shards []*topo.ShardInfo, value bool) error {
+	return wr.updateShards(ctx, shards, func(si *topo.ShardInfo) error {
+		return setFrozen(si, value)
+	})
+}
+
+// setFrozen sets or unsets the Frozen flag of the master tablet control
+// record of "si".
+func setFrozen(si *topo.ShardInfo, value bool) error {
+	tc := si.GetTabletControl(topodatapb.TabletType_MASTER)
+	if tc == nil {
+		return fmt.Errorf("unexpected: missing tablet control record for source %v/%v", si.Keyspace(), si.ShardName())
+	}
+	tc.Frozen = value
+	return nil
+}
+
 // WaitForDrain blocks until the selected tablets (cells/keyspace/shard/tablet_type)
 // have reported a QPS rate of 0.0.
 // NOTE: This is just an observation of one point in time and no guarantee that
@@ -909,29 +1183,13 @@ func (wr *Wrangler) cancelVerticalResharding(ctx context.Context, keyspace, shar
 // MigrateServedFrom is used during vertical splits to migrate a
 // served type from a keyspace to another.
 func (wr *Wrangler) MigrateServedFrom(ctx context.Context, keyspace, shard string, servedType topodatapb.TabletType, cells []string, reverse bool, filteredReplicationWaitTime time.Duration) (err error) {
-	// read the destination keyspace, check it
-	ki, err := wr.ts.GetKeyspace(ctx, keyspace)
+	ki, si, err := wr.checkMigrateServedFrom(ctx, keyspace, shard, servedType, cells, reverse)
 	if err != nil {
 		return err
 	}
-	if len(ki.ServedFroms) == 0 {
-		return fmt.Errorf("Destination keyspace %v is not a vertical split target", keyspace)
-	}
-
-	// read the destination shard, check it
-	si, err := wr.ts.GetShard(ctx, keyspace, shard)
-	if err != nil {
-		return err
-	}
-	if len(si.SourceShards) != 1 || len(si.SourceShards[0].Tables) == 0 {
-		return fmt.Errorf("Destination shard %v/%v is not a vertical split target", keyspace, shard)
-	}
-
-	// check the migration is valid before locking (will also be checked
-	// after locking to be sure)
 	sourceKeyspace := si.SourceShards[0].Keyspace
-	if err := ki.CheckServedFromMigration(servedType, cells, sourceKeyspace, !reverse); err != nil {
-		return err
+	if !reverse {
+		wr.warnIfNotDiffVerified(ctx, []*topo.ShardInfo{si})
 	}
 
 	// lock the keyspaces, source first.
@@ -957,6 +1215,42 @@ func (wr *Wrangler) MigrateServedFrom(ctx context.Context, keyspace, shard strin
 	return err
 }
 
+// checkMigrateServedFrom reads the destination keyspace and shard of a
+// vertical split and checks that "servedType" can be migrated.
+func (wr *Wrangler) checkMigrateServedFrom(ctx context.Context, keyspace, shard string, servedType topodatapb.TabletType, cells []string, reverse bool) (*topo.KeyspaceInfo, *topo.ShardInfo, error) {
+	// Unlike MigrateServedTypes, the master cannot be migrated back: the
+	// master migration removes the filtered replication and the ServedFrom
+	// records, and no reverse replication of the tables is set up.
+	if servedType == topodatapb.TabletType_MASTER && reverse {
+		return nil, nil, fmt.Errorf("MigrateServedFrom -reverse is not supported for the master of %v/%v: after the master migration there is no filtered replication of the tables back to the source keyspace", keyspace, shard)
+	}
+
+	// read the destination keyspace, check it
+	ki, err := wr.ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ki.ServedFroms) == 0 {
+		return nil, nil, fmt.Errorf("Destination keyspace %v is not a vertical split target", keyspace)
+	}
+
+	// read the destination shard, check it
+	si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(si.SourceShards) != 1 || len(si.SourceShards[0].Tables) == 0 {
+		return nil, nil, fmt.Errorf("Destination shard %v/%v is not a vertical split target", keyspace, shard)
+	}
+
+	// check the migration is valid before locking (will also be checked
+	// after locking to be sure)
+	if err := ki.CheckServedFromMigration(servedType, cells, si.SourceShards[0].Keyspace, !reverse); err != nil {
+		return nil, nil, err
+	}
+	return ki, si, nil
+}
+
 func (wr *Wrangler) migrateServedFromLocked(ctx context.Context, ki *topo.KeyspaceInfo, destinationShard *topo.ShardInfo, servedType topodatapb.TabletType, cells []string, reverse bool, filteredReplicationWaitTime time.Duration) (err error) {
 
 	// re-read and update keyspace info record
@@ -1184,17 +1478,29 @@ func (wr *Wrangler) RefreshTabletsByShard(ctx context.Context, si *topo.ShardInf
 }
 
 // DeleteKeyspace will do all the necessary changes in the topology server
-// to entirely remove a keyspace.
-func (wr *Wrangler) DeleteKeyspace(ctx context.Context, keyspace string, recursive bool) error {
+// to entirely remove a keyspace. If recursive is set, it deletes all its
+// shards with DeleteShard. Unless evenIfServing is set, it refuses to do
+// so if any of the shards is serving, or still has serving tablets.
+func (wr *Wrangler) DeleteKeyspace(ctx context.Context, keyspace string, recursive, evenIfServing bool) error {
 	shards, err := wr.ts.GetShardNames(ctx, keyspace)
 	if err != nil {
 		return err
 	}
 	if recursive {
+		if !evenIfServing {
+			// Check all shards first, so we don't delete
+			// some of them and then stop on a serving one.
+			for _, shard := range shards {
+				if err := wr.checkShardNotServing(ctx, keyspace, shard); err != nil {
+					return err
+				}
+			}
+		}
+
 		wr.Logger().Infof("Deleting all shards (and their tablets) in keyspace %v", keyspace)
 		for _, shard := range shards {
 			wr.Logger().Infof("Recursively deleting shard %v/%v", keyspace, shard)
-			if err := wr.DeleteShard(ctx, keyspace, shard, true /* recursive */, true /* evenIfServing */); err != nil && !topo.IsErrType(err, topo.NoNode) {
+			if err := wr.DeleteShard(ctx, keyspace, shard, true /* recursive */, evenIfServing); err != nil && !topo.IsErrType(err, topo.NoNode) {
 				// Unlike the errors below in non-recursive steps, we don't want to
 				// continue if a DeleteShard fails. If we continue and delete the
 				// keyspace, the tablet records will be orphaned, since we'll
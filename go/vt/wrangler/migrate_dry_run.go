@@ -0,0 +1,342 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file contains the dry runs of MigrateServedTypes and
+// MigrateServedFrom. They apply the changes of the migration to copies of
+// the topology records and log the difference. Nothing is written, no lock
+// is taken and no tablet is contacted.
+
+// MigrateServedTypesDryRun logs which Shard and SrvKeyspace records
+// MigrateServedTypes would rewrite and which tablets it would refresh.
+// It does not lock the keyspace. The logged plan may therefore be outdated
+// if another action changes the keyspace concurrently.
+func (wr *Wrangler) MigrateServedTypesDryRun(ctx context.Context, keyspace, shard string, cells []string, servedType topodatapb.TabletType, reverse, skipReFreshState, reverseReplication bool) error {
+	if err := checkMigrateServedTypesParameters(keyspace, shard, cells, servedType, reverse, skipReFreshState); err != nil {
+		return err
+	}
+	plan, err := wr.planMigrateServedTypes(ctx, keyspace, shard, cells, servedType, reverse, reverseReplication)
+	if err != nil {
+		return err
+	}
+
+	wr.Logger().Printf("Dry run of MigrateServedTypes(%v) in keyspace %v. Nothing is changed.\n", servedType, keyspace)
+	if plan.startReverseReplication {
+		wr.Logger().Printf("The reverse replication to %v would be started\n", shardNames(plan.toShards))
+	}
+	if servedType == topodatapb.TabletType_MASTER && plan.reverseBlockedErr == nil {
+		for _, si := range plan.fromShards {
+			if len(si.SourceShards) == 0 {
+				wr.Logger().Printf("Reverse replication streams would be created on the master of %v/%v (started: %v)\n", si.Keyspace(), si.ShardName(), reverseReplication)
+			}
+		}
+		for _, si := range plan.toShards {
+			for _, sourceShard := range si.SourceShards {
+				wr.Logger().Printf("VReplication stream %v on the master of %v/%v would be deleted\n", sourceShard.Uid, si.Keyspace(), si.ShardName())
+			}
+		}
+	}
+	for i := range plan.fromShards {
+		wr.logShardChange(plan.fromShards[i], plan.newFromShards[i])
+	}
+	for i := range plan.toShards {
+		wr.logShardChange(plan.toShards[i], plan.newToShards[i])
+	}
+	if plan.reverseBlockedErr != nil {
+		return plan.reverseBlockedErr
+	}
+
+	ki, err := wr.ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	if err := wr.logSrvKeyspaceChanges(ctx, ki, ki, append(plan.newFromShards, plan.newToShards...), cells); err != nil {
+		return err
+	}
+
+	// The refreshed tablets.
+	if servedType == topodatapb.TabletType_MASTER {
+		return wr.logRefreshedTablets(ctx, append(plan.fromShards, plan.toShards...), []topodatapb.TabletType{topodatapb.TabletType_MASTER}, nil)
+	}
+	refreshShards := plan.toShards
+	if !skipReFreshState {
+		// After the traffic was migrated, the shards which no longer serve
+		// the type are refreshed too.
+		refreshShards = append(plan.fromShards, plan.toShards...)
+	}
+	return wr.logRefreshedTablets(ctx, refreshShards, []topodatapb.TabletType{servedType}, cells)
+}
+
+// MigrateServedFromDryRun logs which Keyspace, Shard and SrvKeyspace records
+// MigrateServedFrom would rewrite, which blacklisted tables would change and
+// which tablets it would refresh. Like MigrateServedTypesDryRun, it does not
+// lock the keyspaces.
+func (wr *Wrangler) MigrateServedFromDryRun(ctx context.Context, keyspace, shard string, servedType topodatapb.TabletType, cells []string, reverse bool) error {
+	ki, destinationShard, err := wr.checkMigrateServedFrom(ctx, keyspace, shard, servedType, cells, reverse)
+	if err != nil {
+		return err
+	}
+	if !reverse {
+		wr.warnIfNotDiffVerified(ctx, []*topo.ShardInfo{destinationShard})
+	}
+	sourceShard, err := wr.ts.GetShard(ctx, destinationShard.SourceShards[0].Keyspace, destinationShard.SourceShards[0].Shard)
+	if err != nil {
+		return err
+	}
+	tables := destinationShard.SourceShards[0].Tables
+
+	// Read the keyspace again for an independent copy which we can modify.
+	newKi, err := wr.ts.GetKeyspace(ctx, keyspace)
+	if err != nil {
+		return err
+	}
+	if reverse {
+		err = newKi.UpdateServedFromMap(servedType, cells, sourceShard.Keyspace(), false, nil)
+	} else {
+		err = newKi.UpdateServedFromMap(servedType, cells, sourceShard.Keyspace(), true, destinationShard.Cells)
+	}
+	if err != nil {
+		return err
+	}
+	newSourceShard := cloneShardInfos([]*topo.ShardInfo{sourceShard})[0]
+	newDestinationShard := cloneShardInfos([]*topo.ShardInfo{destinationShard})[0]
+	if servedType == topodatapb.TabletType_MASTER {
+		err = newSourceShard.SetSourceBlacklistedTables(topodatapb.TabletType_MASTER, nil, false, tables)
+		wr.Logger().Printf("VReplication stream %v on the master of %v/%v would be deleted\n", destinationShard.SourceShards[0].Uid, keyspace, shard)
+		newDestinationShard.SourceShards = nil
+	} else {
+		err = newSourceShard.SetSourceBlacklistedTables(servedType, cells, reverse, tables)
+	}
+	if err != nil {
+		return err
+	}
+
+	wr.Logger().Printf("Dry run of MigrateServedFrom(%v) from keyspace %v to keyspace %v. Nothing is changed.\n", servedType, sourceShard.Keyspace(), keyspace)
+	if before, after := formatServedFroms(ki.ServedFroms), formatServedFroms(newKi.ServedFroms); before != after {
+		wr.Logger().Printf("Keyspace %v would be rewritten:\n  served from: %v -> %v\n", keyspace, before, after)
+	}
+	wr.logShardChange(sourceShard, newSourceShard)
+	wr.logShardChange(destinationShard, newDestinationShard)
+	if err := wr.logSrvKeyspaceChanges(ctx, ki, newKi, []*topo.ShardInfo{newDestinationShard}, cells); err != nil {
+		return err
+	}
+
+	if servedType == topodatapb.TabletType_MASTER {
+		return wr.logRefreshedTablets(ctx, []*topo.ShardInfo{sourceShard, destinationShard}, []topodatapb.TabletType{topodatapb.TabletType_MASTER}, nil)
+	}
+	return wr.logRefreshedTablets(ctx, []*topo.ShardInfo{sourceShard}, []topodatapb.TabletType{servedType}, cells)
+}
+
+func cloneShardInfos(shards []*topo.ShardInfo) []*topo.ShardInfo {
+	result := make([]*topo.ShardInfo, len(shards))
+	for i, si := range shards {
+		result[i] = topo.NewShardInfo(si.Keyspace(), si.ShardName(), proto.Clone(si.Shard).(*topodatapb.Shard), si.Version())
+	}
+	return result
+}
+
+// logShardChange logs the fields of the shard record which differ between
+// "before" and "after".
+func (wr *Wrangler) logShardChange(before, after *topo.ShardInfo) {
+	if proto.Equal(before.Shard, after.Shard) {
+		wr.Logger().Printf("Shard %v/%v would not change\n", before.Keyspace(), before.ShardName())
+		return
+	}
+	wr.Logger().Printf("Shard %v/%v would be rewritten:\n", before.Keyspace(), before.ShardName())
+	if b, a := formatServedTypes(before.ServedTypes), formatServedTypes(after.ServedTypes); b != a {
+		wr.Logger().Printf("  served types: %v -> %v\n", b, a)
+	}
+	for _, tabletType := range tabletControlTypes(before, after) {
+		b, a := before.GetTabletControl(tabletType), after.GetTabletControl(tabletType)
+		if bt, at := blacklistedTables(b), blacklistedTables(a); bt != at {
+			wr.Logger().Printf("  blacklisted tables for %v: %v -> %v\n", tabletType, bt, at)
+		}
+		if bc, ac := formatTabletControl(b), formatTabletControl(a); bc != ac {
+			wr.Logger().Printf("  tablet control for %v: %v -> %v\n", tabletType, bc, ac)
+		}
+	}
+	if b, a := formatSourceShards(before.SourceShards), formatSourceShards(after.SourceShards); b != a {
+		wr.Logger().Printf("  source shards: %v -> %v\n", b, a)
+	}
+}
+
+// logSrvKeyspaceChanges logs the SrvKeyspace records which a rebuild of
+// the keyspace would change. "changedShards" are the shard records as they
+// would be after the migration.
+func (wr *Wrangler) logSrvKeyspaceChanges(ctx context.Context, before, after *topo.KeyspaceInfo, changedShards []*topo.ShardInfo, cells []string) error {
+	shards, err := wr.ts.FindAllShardsInKeyspace(ctx, before.KeyspaceName())
+	if err != nil {
+		return err
+	}
+	newShards := make(map[string]*topo.ShardInfo)
+	for name, si := range shards {
+		newShards[name] = si
+	}
+	for _, si := range changedShards {
+		newShards[si.ShardName()] = si
+	}
+
+	// Same as topotools.RebuildKeyspaceLocked, all cells of the shards
+	// limited to "cells".
+	cellSet := make(map[string]bool)
+	for _, si := range shards {
+		for _, cell := range si.Cells {
+			if topo.InCellList(cell, cells) {
+				cellSet[cell] = true
+			}
+		}
+	}
+	var allCells []string
+	for cell := range cellSet {
+		allCells = append(allCells, cell)
+	}
+	sort.Strings(allCells)
+
+	for _, cell := range allCells {
+		b := formatSrvKeyspace(before, shards, cell)
+		a := formatSrvKeyspace(after, newShards, cell)
+		if a == b {
+			continue
+		}
+		wr.Logger().Printf("SrvKeyspace %v in cell %v would be rewritten:\n  %v\n  -> %v\n", before.KeyspaceName(), cell, b, a)
+	}
+	return nil
+}
+
+// logRefreshedTablets logs the tablets of "shards" with one of "tabletTypes"
+// in "cells" which would be refreshed. It selects the same tablets as
+// RefreshTabletsByShard.
+func (wr *Wrangler) logRefreshedTablets(ctx context.Context, shards []*topo.ShardInfo, tabletTypes []topodatapb.TabletType, cells []string) error {
+	for _, si := range shards {
+		tabletMap, err := wr.ts.GetTabletMapForShardByCell(ctx, si.Keyspace(), si.ShardName(), cells)
+		if err != nil && !topo.IsErrType(err, topo.PartialResult) {
+			return err
+		}
+		var aliases []string
+		for alias, ti := range tabletMap {
+			if topoproto.IsTypeInList(ti.Type, tabletTypes) && ti.Hostname != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+		sort.Strings(aliases)
+		for _, alias := range aliases {
+			wr.Logger().Printf("Tablet %v (%v) of shard %v/%v would be refreshed\n", alias, tabletMap[alias].Type, si.Keyspace(), si.ShardName())
+		}
+	}
+	return nil
+}
+
+// formatSrvKeyspace describes the SrvKeyspace which a rebuild would write
+// for "cell": the shards of each partition and the served from records.
+func formatSrvKeyspace(ki *topo.KeyspaceInfo, shards map[string]*topo.ShardInfo, cell string) string {
+	partitions := make(map[topodatapb.TabletType][]string)
+	for name, si := range shards {
+		for _, tabletType := range si.GetServedTypesPerCell(cell) {
+			partitions[tabletType] = append(partitions[tabletType], name)
+		}
+	}
+	var types []topodatapb.TabletType
+	for tabletType := range partitions {
+		types = append(types, tabletType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	var parts []string
+	for _, tabletType := range types {
+		names := partitions[tabletType]
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("%v: [%v]", tabletType, strings.Join(names, " ")))
+	}
+	var servedFroms []string
+	for _, sf := range ki.ComputeCellServedFrom(cell) {
+		servedFroms = append(servedFroms, fmt.Sprintf("%v from %v", sf.TabletType, sf.Keyspace))
+	}
+	if len(servedFroms) != 0 {
+		parts = append(parts, fmt.Sprintf("served from: [%v]", strings.Join(servedFroms, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func tabletControlTypes(before, after *topo.ShardInfo) []topodatapb.TabletType {
+	seen := make(map[topodatapb.TabletType]bool)
+	var result []topodatapb.TabletType
+	for _, tcs := range [][]*topodatapb.Shard_TabletControl{before.TabletControls, after.TabletControls} {
+		for _, tc := range tcs {
+			if !seen[tc.TabletType] {
+				seen[tc.TabletType] = true
+				result = append(result, tc.TabletType)
+			}
+		}
+	}
+	return result
+}
+
+func blacklistedTables(tc *topodatapb.Shard_TabletControl) string {
+	if tc == nil {
+		return "[]"
+	}
+	return fmt.Sprintf("%v", tc.BlacklistedTables)
+}
+
+// formatTabletControl returns the tablet control without the blacklisted
+// tables. They are logged separately.
+func formatTabletControl(tc *topodatapb.Shard_TabletControl) string {
+	if tc == nil {
+		return "none"
+	}
+	tc = proto.Clone(tc).(*topodatapb.Shard_TabletControl)
+	tc.BlacklistedTables = nil
+	return proto.CompactTextString(tc)
+}
+
+func formatServedTypes(servedTypes []*topodatapb.Shard_ServedType) string {
+	var result []string
+	for _, st := range servedTypes {
+		result = append(result, proto.CompactTextString(st))
+	}
+	return "[" + strings.Join(result, ", ") + "]"
+}
+
+func formatSourceShards(sourceShards []*topodatapb.Shard_SourceShard) string {
+	var result []string
+	for _, ss := range sourceShards {
+		result = append(result, topoproto.KeyspaceShardString(ss.Keyspace, ss.Shard))
+	}
+	return "[" + strings.Join(result, ", ") + "]"
+}
+
+func formatServedFroms(servedFroms []*topodatapb.Keyspace_ServedFrom) string {
+	var result []string
+	for _, sf := range servedFroms {
+		result = append(result, proto.CompactTextString(sf))
+	}
+	return "[" + strings.Join(result, ", ") + "]"
+}
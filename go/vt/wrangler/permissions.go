@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
@@ -41,9 +42,8 @@ func (wr *Wrangler) GetPermissions(ctx context.Context, tabletAlias *topodatapb.
 	return wr.tmc.GetPermissions(ctx, ti.Tablet)
 }
 
-// diffPermissions is a helper method to asynchronously diff a permissions
-func (wr *Wrangler) diffPermissions(ctx context.Context, masterPermissions *tabletmanagerdatapb.Permissions, masterAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
-	defer wg.Done()
+// diffPermissions is a helper method to diff the permissions of a tablet
+func (wr *Wrangler) diffPermissions(ctx context.Context, masterPermissions *tabletmanagerdatapb.Permissions, masterAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, er concurrency.ErrorRecorder) {
 	log.Infof("Gathering permissions for %v", topoproto.TabletAliasString(alias))
 	slavePermissions, err := wr.GetPermissions(ctx, alias)
 	if err != nil {
@@ -88,7 +88,10 @@ func (wr *Wrangler) ValidatePermissionsShard(ctx context.Context, keyspace, shar
 			continue
 		}
 		wg.Add(1)
-		go wr.diffPermissions(ctx, masterPermissions, si.MasterAlias, alias, &wg, &er)
+		go func(alias *topodatapb.TabletAlias) {
+			defer wg.Done()
+			wr.diffPermissions(ctx, masterPermissions, si.MasterAlias, alias, &er)
+		}(alias)
 	}
 	wg.Wait()
 	if er.HasErrors() {
@@ -99,7 +102,7 @@ func (wr *Wrangler) ValidatePermissionsShard(ctx context.Context, keyspace, shar
 
 // ValidatePermissionsKeyspace validates all the permissions are the same
 // in a keyspace
-func (wr *Wrangler) ValidatePermissionsKeyspace(ctx context.Context, keyspace string) error {
+func (wr *Wrangler) ValidatePermissionsKeyspace(ctx context.Context, keyspace string, sema *sync2.Semaphore) error {
 	// find all the shards
 	shards, err := wr.ts.GetShardNames(ctx, keyspace)
 	if err != nil {
@@ -131,27 +134,11 @@ func (wr *Wrangler) ValidatePermissionsKeyspace(ctx context.Context, keyspace st
 	}
 
 	// then diff with all tablets but master 0
-	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for _, shard := range shards {
-		aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
-		if err != nil {
-			er.RecordError(err)
-			continue
-		}
-
-		for _, alias := range aliases {
-			if topoproto.TabletAliasEqual(alias, si.MasterAlias) {
-				continue
-			}
-
-			wg.Add(1)
-			go wr.diffPermissions(ctx, referencePermissions, referenceAlias, alias, &wg, &er)
-		}
-	}
-	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("Permissions diffs: %v", er.Error().Error())
+	err = wr.validateKeyspaceTablets(ctx, keyspace, shards, referenceAlias, sema, func(ctx context.Context, alias *topodatapb.TabletAlias, er concurrency.ErrorRecorder) {
+		wr.diffPermissions(ctx, referencePermissions, referenceAlias, alias, er)
+	})
+	if err != nil {
+		return fmt.Errorf("Permissions diffs: %v", err)
 	}
 	return nil
 }
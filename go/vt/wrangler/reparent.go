@@ -327,7 +327,15 @@ func (wr *Wrangler) initShardMasterLocked(ctx context.Context, ev *events.Repare
 
 // PlannedReparentShard will make the provided tablet the master for the shard,
 // when both the current and new master are reachable and in good shape.
-func (wr *Wrangler) PlannedReparentShard(ctx context.Context, keyspace, shard string, masterElectTabletAlias, avoidMasterAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration) (err error) {
+// If no tablet is provided, "policy" chooses it. If "policy" is nil, the
+// policy of --master_candidate_policy is used.
+func (wr *Wrangler) PlannedReparentShard(ctx context.Context, keyspace, shard string, masterElectTabletAlias, avoidMasterAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration, policy MasterCandidatePolicy) (err error) {
+	if policy == nil {
+		if policy, err = NewMasterCandidatePolicy(""); err != nil {
+			return err
+		}
+	}
+
 	// lock the shard
 	lockAction := fmt.Sprintf(
 		"PlannedReparentShard(%v, avoid_master=%v)",
@@ -352,7 +360,7 @@ func (wr *Wrangler) PlannedReparentShard(ctx context.Context, keyspace, shard st
 	}
 
 	// do the work
-	err = wr.plannedReparentShardLocked(ctx, ev, keyspace, shard, masterElectTabletAlias, avoidMasterAlias, waitSlaveTimeout)
+	err = wr.plannedReparentShardLocked(ctx, ev, keyspace, shard, masterElectTabletAlias, avoidMasterAlias, waitSlaveTimeout, policy)
 	if err != nil {
 		event.DispatchUpdate(ev, "failed PlannedReparentShard: "+err.Error())
 	} else {
@@ -361,7 +369,7 @@ func (wr *Wrangler) PlannedReparentShard(ctx context.Context, keyspace, shard st
 	return err
 }
 
-func (wr *Wrangler) plannedReparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, masterElectTabletAlias, avoidMasterTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration) error {
+func (wr *Wrangler) plannedReparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, masterElectTabletAlias, avoidMasterTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration, policy MasterCandidatePolicy) error {
 	shardInfo, err := wr.ts.GetShard(ctx, keyspace, shard)
 	if err != nil {
 		return err
@@ -384,7 +392,7 @@ func (wr *Wrangler) plannedReparentShardLocked(ctx context.Context, ev *events.R
 			return nil
 		}
 		event.DispatchUpdate(ev, "searching for master candidate")
-		masterElectTabletAlias, err = wr.chooseNewMaster(ctx, shardInfo, tabletMap, avoidMasterTabletAlias, waitSlaveTimeout)
+		masterElectTabletAlias, err = wr.chooseNewMaster(ctx, shardInfo, tabletMap, avoidMasterTabletAlias, waitSlaveTimeout, policy)
 		if err != nil {
 			return err
 		}
@@ -502,93 +510,78 @@ func (wr *Wrangler) plannedReparentShardLocked(ctx context.Context, ev *events.R
 	return nil
 }
 
-// maxReplPosSearch is a struct helping to search for a tablet with the largest replication
-// position querying status from all tablets in parallel.
-type maxReplPosSearch struct {
-	wrangler         *Wrangler
-	ctx              context.Context
-	waitSlaveTimeout time.Duration
-	waitGroup        sync.WaitGroup
-	maxPosLock       sync.Mutex
-	maxPos           mysql.Position
-	maxPosTablet     *topodatapb.Tablet
-}
-
-func (maxPosSearch *maxReplPosSearch) processTablet(tablet *topodatapb.Tablet) {
-	defer maxPosSearch.waitGroup.Done()
-	maxPosSearch.wrangler.logger.Infof("getting replication position from %v", topoproto.TabletAliasString(tablet.Alias))
-
-	slaveStatusCtx, cancelSlaveStatus := context.WithTimeout(maxPosSearch.ctx, maxPosSearch.waitSlaveTimeout)
-	defer cancelSlaveStatus()
-
-	status, err := maxPosSearch.wrangler.tmc.SlaveStatus(slaveStatusCtx, tablet)
-	if err != nil {
-		maxPosSearch.wrangler.logger.Warningf("failed to get replication status from %v, ignoring tablet: %v", topoproto.TabletAliasString(tablet.Alias), err)
-		return
-	}
-	replPos, err := mysql.DecodePosition(status.Position)
-	if err != nil {
-		maxPosSearch.wrangler.logger.Warningf("cannot decode slave %v position %v: %v", topoproto.TabletAliasString(tablet.Alias), status.Position, err)
-		return
-	}
-
-	maxPosSearch.maxPosLock.Lock()
-	if maxPosSearch.maxPosTablet == nil || !maxPosSearch.maxPos.AtLeast(replPos) {
-		maxPosSearch.maxPos = replPos
-		maxPosSearch.maxPosTablet = tablet
-	}
-	maxPosSearch.maxPosLock.Unlock()
-}
-
-// chooseNewMaster finds a tablet that is going to become master after reparent. The criterias
-// for the new master-elect are (preferably) to be in the same cell as the current master, and
-// to be different from avoidMasterTabletAlias. The tablet with the largest replication
-// position is chosen to minimize the time of catching up with the master. Note that the search
-// for largest replication position will race with transactions being executed on the master at
-// the same time, so when all tablets are roughly at the same position then the choice of the
-// new master-elect will be somewhat unpredictable.
+// chooseNewMaster finds a tablet that is going to become master after
+// reparent. The candidates are the REPLICA tablets of the shard except
+// avoidMasterTabletAlias. "policy" chooses one of them (see
+// MasterCandidatePolicy).
 func (wr *Wrangler) chooseNewMaster(
 	ctx context.Context,
 	shardInfo *topo.ShardInfo,
 	tabletMap map[string]*topo.TabletInfo,
 	avoidMasterTabletAlias *topodatapb.TabletAlias,
-	waitSlaveTimeout time.Duration) (*topodatapb.TabletAlias, error) {
+	waitSlaveTimeout time.Duration,
+	policy MasterCandidatePolicy) (*topodatapb.TabletAlias, error) {
 
 	if avoidMasterTabletAlias == nil {
 		return nil, fmt.Errorf("tablet to avoid for reparent is not provided, cannot choose new master")
 	}
-	var masterCell string
-	if shardInfo.MasterAlias != nil {
-		masterCell = shardInfo.MasterAlias.Cell
-	}
 
-	maxPosSearch := maxReplPosSearch{
-		wrangler:         wr,
-		ctx:              ctx,
-		waitSlaveTimeout: waitSlaveTimeout,
-		waitGroup:        sync.WaitGroup{},
-		maxPosLock:       sync.Mutex{},
-	}
+	var candidates []*topo.TabletInfo
 	for _, tabletInfo := range tabletMap {
-		if (masterCell != "" && tabletInfo.Alias.Cell != masterCell) ||
-			topoproto.TabletAliasEqual(tabletInfo.Alias, avoidMasterTabletAlias) ||
+		if topoproto.TabletAliasEqual(tabletInfo.Alias, avoidMasterTabletAlias) ||
 			tabletInfo.Tablet.Type != topodatapb.TabletType_REPLICA {
 			continue
 		}
-		maxPosSearch.waitGroup.Add(1)
-		go maxPosSearch.processTablet(tabletInfo.Tablet)
+		candidates = append(candidates, tabletInfo)
 	}
-	maxPosSearch.waitGroup.Wait()
+	return policy.ChooseNewMaster(ctx, wr, shardInfo, candidates, waitSlaveTimeout)
+}
 
-	if maxPosSearch.maxPosTablet == nil {
-		return nil, nil
+// findMostAdvancedPosition returns the tablet whose position contains the
+// positions of all other tablets. The master-elect is preferred if it is
+// one of the most advanced tablets. It fails if the GTID sets diverged,
+// i.e. if no single tablet has all transactions.
+func findMostAdvancedPosition(masterElectAlias string, masterElectPos mysql.Position, positions map[string]mysql.Position) (string, mysql.Position, error) {
+	mostAdvanced, mostAdvancedPos := masterElectAlias, masterElectPos
+	for alias, pos := range positions {
+		if !mostAdvancedPos.AtLeast(pos) {
+			mostAdvanced, mostAdvancedPos = alias, pos
+		}
+	}
+	if !mostAdvancedPos.AtLeast(masterElectPos) {
+		return "", mysql.Position{}, fmt.Errorf("no tablet has all transactions: the positions of %v and %v diverged", masterElectAlias, mostAdvanced)
+	}
+	for alias, pos := range positions {
+		if !mostAdvancedPos.AtLeast(pos) {
+			return "", mysql.Position{}, fmt.Errorf("no tablet has all transactions: the positions of %v and %v diverged", alias, mostAdvanced)
+		}
+	}
+	return mostAdvanced, mostAdvancedPos, nil
+}
+
+// backfillMasterElect makes the master-elect replicate from the binlogs of
+// the given slave, until it has all transactions up to pos. Replication on
+// the master-elect is stopped afterwards, so it can be promoted.
+func (wr *Wrangler) backfillMasterElect(ctx context.Context, masterElect, source *topo.TabletInfo, pos mysql.Position, waitSlaveTimeout time.Duration) error {
+	wr.logger.Infof("master elect %v is behind %v, replicating the missing transactions up to %v", masterElect.AliasString(), source.AliasString(), mysql.EncodePosition(pos))
+	// A zero timeCreatedNS skips waiting for a reparent journal entry.
+	if err := wr.tmc.SetMaster(ctx, masterElect.Tablet, source.Alias, 0, true /* forceStartSlave */); err != nil {
+		return fmt.Errorf("cannot make master elect %v replicate from %v: %v", masterElect.AliasString(), source.AliasString(), err)
+	}
+	newPos, err := wr.tmc.StopSlaveMinimum(ctx, masterElect.Tablet, mysql.EncodePosition(pos), waitSlaveTimeout)
+	if err != nil {
+		return fmt.Errorf("master elect %v failed to replicate the missing transactions from %v: %v", masterElect.AliasString(), source.AliasString(), err)
 	}
-	return maxPosSearch.maxPosTablet.Alias, nil
+	wr.logger.Infof("master elect %v replicated the missing transactions from %v and is at position %v", masterElect.AliasString(), source.AliasString(), newPos)
+	return nil
 }
 
 // EmergencyReparentShard will make the provided tablet the master for
 // the shard, when the old master is completely unreachable.
-func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration) (err error) {
+// If backfillFromMostAdvanced is set and another slave has more
+// transactions than the master-elect, the master-elect first replicates
+// the missing transactions from the binlogs of that slave.
+func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration, backfillFromMostAdvanced bool) (err error) {
 	// lock the shard
 	ctx, unlock, lockErr := wr.ts.LockShard(ctx, keyspace, shard, fmt.Sprintf("EmergencyReparentShard(%v)", topoproto.TabletAliasString(masterElectTabletAlias)))
 	if lockErr != nil {
@@ -600,7 +593,7 @@ func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard
 	ev := &events.Reparent{}
 
 	// do the work
-	err = wr.emergencyReparentShardLocked(ctx, ev, keyspace, shard, masterElectTabletAlias, waitSlaveTimeout)
+	err = wr.emergencyReparentShardLocked(ctx, ev, keyspace, shard, masterElectTabletAlias, waitSlaveTimeout, backfillFromMostAdvanced)
 	if err != nil {
 		event.DispatchUpdate(ev, "failed EmergencyReparentShard: "+err.Error())
 	} else {
@@ -609,7 +602,7 @@ func (wr *Wrangler) EmergencyReparentShard(ctx context.Context, keyspace, shard
 	return err
 }
 
-func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration) error {
+func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events.Reparent, keyspace, shard string, masterElectTabletAlias *topodatapb.TabletAlias, waitSlaveTimeout time.Duration, backfillFromMostAdvanced bool) error {
 	shardInfo, err := wr.ts.GetShard(ctx, keyspace, shard)
 	if err != nil {
 		return err
@@ -701,6 +694,7 @@ func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events
 	if err != nil {
 		return fmt.Errorf("cannot decode master elect position %v: %v", masterElectStatus.Position, err)
 	}
+	positions := make(map[string]mysql.Position)
 	for alias, status := range statusMap {
 		if alias == masterElectTabletAliasStr {
 			continue
@@ -709,8 +703,24 @@ func (wr *Wrangler) emergencyReparentShardLocked(ctx context.Context, ev *events
 		if err != nil {
 			return fmt.Errorf("cannot decode slave %v position %v: %v", alias, status.Position, err)
 		}
+		positions[alias] = pos
+	}
+	if backfillFromMostAdvanced {
+		mostAdvanced, mostAdvancedPos, err := findMostAdvancedPosition(masterElectTabletAliasStr, masterElectPos, positions)
+		if err != nil {
+			return err
+		}
+		if mostAdvanced != masterElectTabletAliasStr {
+			event.DispatchUpdate(ev, "backfilling master elect")
+			if err := wr.backfillMasterElect(ctx, masterElectTabletInfo, tabletMap[mostAdvanced], mostAdvancedPos, waitSlaveTimeout); err != nil {
+				return err
+			}
+			masterElectPos = mostAdvancedPos
+		}
+	}
+	for alias, pos := range positions {
 		if !masterElectPos.AtLeast(pos) {
-			return fmt.Errorf("tablet %v is more advanced than master elect tablet %v: %v > %v", alias, masterElectTabletAliasStr, status.Position, masterElectStatus)
+			return fmt.Errorf("tablet %v is more advanced than master elect tablet %v: %v > %v", alias, masterElectTabletAliasStr, statusMap[alias].Position, masterElectStatus)
 		}
 	}
 
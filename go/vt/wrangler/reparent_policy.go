@@ -0,0 +1,237 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// MostCaughtUpPolicyName is the name of the default MasterCandidatePolicy.
+const MostCaughtUpPolicyName = "most_caught_up"
+
+var masterCandidatePolicy = flag.String("master_candidate_policy", MostCaughtUpPolicyName, "policy which chooses the new master for PlannedReparentShard if none is specified. Format: <name>[:<argument>]. Built-in policies: "+
+	"\"most_caught_up\" (the REPLICA in the cell of the current master with the largest replication position), "+
+	"\"cell:<cell1>,<cell2>,...\" (the most caught-up REPLICA in the first listed cell which has one), "+
+	"\"tag:<key>=<value>\" (the most caught-up REPLICA whose tablet has this tag, e.g. for durability requirements)")
+
+// MasterCandidatePolicy chooses the new master for PlannedReparentShard if
+// no master-elect tablet was specified.
+type MasterCandidatePolicy interface {
+	// ChooseNewMaster returns one of the "candidates" or nil if none
+	// qualifies. All candidates are REPLICA tablets of the shard, except
+	// the tablet which should no longer be the master.
+	ChooseNewMaster(ctx context.Context, wr *Wrangler, shardInfo *topo.ShardInfo, candidates []*topo.TabletInfo, waitSlaveTimeout time.Duration) (*topodatapb.TabletAlias, error)
+}
+
+// MasterCandidatePolicyFactory creates a MasterCandidatePolicy. "arg" is the
+// part of the policy specification after the colon. It may be empty.
+type MasterCandidatePolicyFactory func(arg string) (MasterCandidatePolicy, error)
+
+var masterCandidatePolicyFactories = make(map[string]MasterCandidatePolicyFactory)
+
+// RegisterMasterCandidatePolicy registers a MasterCandidatePolicy under
+// "name". Plugins can use it to provide their own policy.
+func RegisterMasterCandidatePolicy(name string, factory MasterCandidatePolicyFactory) {
+	if _, ok := masterCandidatePolicyFactories[name]; ok {
+		log.Fatalf("RegisterMasterCandidatePolicy %s already exists", name)
+	}
+	masterCandidatePolicyFactories[name] = factory
+}
+
+// NewMasterCandidatePolicy returns the policy for the specification "spec"
+// which has the format <name>[:<argument>]. If "spec" is empty, the value
+// of --master_candidate_policy is used.
+func NewMasterCandidatePolicy(spec string) (MasterCandidatePolicy, error) {
+	if spec == "" {
+		spec = *masterCandidatePolicy
+	}
+	name, arg := spec, ""
+	if i := strings.Index(spec, ":"); i != -1 {
+		name, arg = spec[:i], spec[i+1:]
+	}
+	factory, ok := masterCandidatePolicyFactories[name]
+	if !ok {
+		var names []string
+		for n := range masterCandidatePolicyFactories {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown master candidate policy %v, available policies: %v", name, strings.Join(names, ", "))
+	}
+	return factory(arg)
+}
+
+func init() {
+	RegisterMasterCandidatePolicy(MostCaughtUpPolicyName, func(arg string) (MasterCandidatePolicy, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("master candidate policy %v has no argument: %v", MostCaughtUpPolicyName, arg)
+		}
+		return mostCaughtUpPolicy{}, nil
+	})
+	RegisterMasterCandidatePolicy("cell", func(arg string) (MasterCandidatePolicy, error) {
+		if arg == "" {
+			return nil, fmt.Errorf("master candidate policy cell requires a list of cells e.g. cell:cell1,cell2")
+		}
+		return cellPreferencePolicy{cells: strings.Split(arg, ",")}, nil
+	})
+	RegisterMasterCandidatePolicy("tag", func(arg string) (MasterCandidatePolicy, error) {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("master candidate policy tag requires a tag e.g. tag:durability=high, got: %v", arg)
+		}
+		return tagPolicy{key: parts[0], value: parts[1]}, nil
+	})
+}
+
+// mostCaughtUpPolicy chooses the candidate in the cell of the current master
+// with the largest replication position. This minimizes the time of catching
+// up with the master.
+type mostCaughtUpPolicy struct{}
+
+// ChooseNewMaster is part of the MasterCandidatePolicy interface.
+func (mostCaughtUpPolicy) ChooseNewMaster(ctx context.Context, wr *Wrangler, shardInfo *topo.ShardInfo, candidates []*topo.TabletInfo, waitSlaveTimeout time.Duration) (*topodatapb.TabletAlias, error) {
+	if shardInfo.MasterAlias != nil {
+		candidates = filterTablets(candidates, func(ti *topo.TabletInfo) bool {
+			return ti.Alias.Cell == shardInfo.MasterAlias.Cell
+		})
+	}
+	return wr.MostCaughtUpTablet(ctx, candidates, waitSlaveTimeout), nil
+}
+
+// cellPreferencePolicy chooses the most caught-up candidate in the first of
+// its cells which has a reachable candidate.
+type cellPreferencePolicy struct {
+	cells []string
+}
+
+// ChooseNewMaster is part of the MasterCandidatePolicy interface.
+func (p cellPreferencePolicy) ChooseNewMaster(ctx context.Context, wr *Wrangler, shardInfo *topo.ShardInfo, candidates []*topo.TabletInfo, waitSlaveTimeout time.Duration) (*topodatapb.TabletAlias, error) {
+	for _, cell := range p.cells {
+		inCell := filterTablets(candidates, func(ti *topo.TabletInfo) bool {
+			return ti.Alias.Cell == cell
+		})
+		if alias := wr.MostCaughtUpTablet(ctx, inCell, waitSlaveTimeout); alias != nil {
+			return alias, nil
+		}
+		wr.Logger().Infof("no master candidate in cell %v", cell)
+	}
+	return nil, nil
+}
+
+// tagPolicy chooses the most caught-up candidate which has the tablet tag
+// "key" with "value" e.g. to satisfy durability requirements.
+type tagPolicy struct {
+	key   string
+	value string
+}
+
+// ChooseNewMaster is part of the MasterCandidatePolicy interface.
+func (p tagPolicy) ChooseNewMaster(ctx context.Context, wr *Wrangler, shardInfo *topo.ShardInfo, candidates []*topo.TabletInfo, waitSlaveTimeout time.Duration) (*topodatapb.TabletAlias, error) {
+	candidates = filterTablets(candidates, func(ti *topo.TabletInfo) bool {
+		value, ok := ti.Tags[p.key]
+		return ok && value == p.value
+	})
+	return wr.MostCaughtUpTablet(ctx, candidates, waitSlaveTimeout), nil
+}
+
+func filterTablets(tablets []*topo.TabletInfo, keep func(ti *topo.TabletInfo) bool) []*topo.TabletInfo {
+	var result []*topo.TabletInfo
+	for _, ti := range tablets {
+		if keep(ti) {
+			result = append(result, ti)
+		}
+	}
+	return result
+}
+
+// maxReplPosSearch is a struct helping to search for a tablet with the largest replication
+// position querying status from all tablets in parallel.
+type maxReplPosSearch struct {
+	wrangler         *Wrangler
+	ctx              context.Context
+	waitSlaveTimeout time.Duration
+	waitGroup        sync.WaitGroup
+	maxPosLock       sync.Mutex
+	maxPos           mysql.Position
+	maxPosTablet     *topodatapb.Tablet
+}
+
+func (maxPosSearch *maxReplPosSearch) processTablet(tablet *topodatapb.Tablet) {
+	defer maxPosSearch.waitGroup.Done()
+	maxPosSearch.wrangler.logger.Infof("getting replication position from %v", topoproto.TabletAliasString(tablet.Alias))
+
+	slaveStatusCtx, cancelSlaveStatus := context.WithTimeout(maxPosSearch.ctx, maxPosSearch.waitSlaveTimeout)
+	defer cancelSlaveStatus()
+
+	status, err := maxPosSearch.wrangler.tmc.SlaveStatus(slaveStatusCtx, tablet)
+	if err != nil {
+		maxPosSearch.wrangler.logger.Warningf("failed to get replication status from %v, ignoring tablet: %v", topoproto.TabletAliasString(tablet.Alias), err)
+		return
+	}
+	replPos, err := mysql.DecodePosition(status.Position)
+	if err != nil {
+		maxPosSearch.wrangler.logger.Warningf("cannot decode slave %v position %v: %v", topoproto.TabletAliasString(tablet.Alias), status.Position, err)
+		return
+	}
+
+	maxPosSearch.maxPosLock.Lock()
+	if maxPosSearch.maxPosTablet == nil || !maxPosSearch.maxPos.AtLeast(replPos) {
+		maxPosSearch.maxPos = replPos
+		maxPosSearch.maxPosTablet = tablet
+	}
+	maxPosSearch.maxPosLock.Unlock()
+}
+
+// MostCaughtUpTablet returns the tablet with the largest replication
+// position. Tablets whose position cannot be read are ignored. It returns nil
+// if no position could be read. Note that the search races with the
+// transactions which are executed on the master at the same time. When all
+// tablets are roughly at the same position, the result is somewhat
+// unpredictable.
+func (wr *Wrangler) MostCaughtUpTablet(ctx context.Context, tablets []*topo.TabletInfo, waitSlaveTimeout time.Duration) *topodatapb.TabletAlias {
+	maxPosSearch := maxReplPosSearch{
+		wrangler:         wr,
+		ctx:              ctx,
+		waitSlaveTimeout: waitSlaveTimeout,
+		waitGroup:        sync.WaitGroup{},
+		maxPosLock:       sync.Mutex{},
+	}
+	for _, tabletInfo := range tablets {
+		maxPosSearch.waitGroup.Add(1)
+		go maxPosSearch.processTablet(tabletInfo.Tablet)
+	}
+	maxPosSearch.waitGroup.Wait()
+
+	if maxPosSearch.maxPosTablet == nil {
+		return nil
+	}
+	return maxPosSearch.maxPosTablet.Alias
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// positionTMClient returns a fixed replication position for each tablet.
+type positionTMClient struct {
+	tmclient.TabletManagerClient
+	positions map[string]string
+}
+
+func (c *positionTMClient) SlaveStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.Status, error) {
+	pos, ok := c.positions[topoproto.TabletAliasString(tablet.Alias)]
+	if !ok {
+		return nil, fmt.Errorf("tablet %v is not reachable", topoproto.TabletAliasString(tablet.Alias))
+	}
+	return &replicationdatapb.Status{Position: pos}, nil
+}
+
+func TestMasterCandidatePolicies(t *testing.T) {
+	tablet := func(cell string, uid uint32, tags map[string]string) *topo.TabletInfo {
+		return &topo.TabletInfo{Tablet: &topodatapb.Tablet{
+			Alias: &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Type:  topodatapb.TabletType_REPLICA,
+			Tags:  tags,
+		}}
+	}
+	candidates := []*topo.TabletInfo{
+		tablet("cell1", 1, nil),
+		tablet("cell2", 2, map[string]string{"durability": "high"}),
+		tablet("cell2", 3, nil),
+		// Unreachable tablets are ignored.
+		tablet("cell3", 4, nil),
+	}
+	wr := New(logutil.NewConsoleLogger(), nil, &positionTMClient{
+		positions: map[string]string{
+			"cell1-0000000001": "MariaDB/0-1-5",
+			"cell2-0000000002": "MariaDB/0-1-7",
+			"cell2-0000000003": "MariaDB/0-1-9",
+		},
+	})
+	shardInfo := topo.NewShardInfo("ks", "0", &topodatapb.Shard{
+		MasterAlias: &topodatapb.TabletAlias{Cell: "cell1", Uid: 100},
+	}, nil)
+
+	testcases := []struct {
+		spec string
+		want string
+	}{
+		{"", "cell1-0000000001"},
+		{"most_caught_up", "cell1-0000000001"},
+		{"cell:cell3,cell2", "cell2-0000000003"},
+		{"cell:cell3", "<nil>"},
+		{"tag:durability=high", "cell2-0000000002"},
+		{"tag:durability=low", "<nil>"},
+	}
+	for _, tc := range testcases {
+		policy, err := NewMasterCandidatePolicy(tc.spec)
+		if err != nil {
+			t.Errorf("NewMasterCandidatePolicy(%v) failed: %v", tc.spec, err)
+			continue
+		}
+		alias, err := policy.ChooseNewMaster(context.Background(), wr, shardInfo, candidates, time.Second)
+		if err != nil {
+			t.Errorf("policy %v failed: %v", tc.spec, err)
+			continue
+		}
+		if got := topoproto.TabletAliasString(alias); got != tc.want {
+			t.Errorf("policy %v chose %v, want = %v", tc.spec, got, tc.want)
+		}
+	}
+
+	for _, spec := range []string{"unknown", "most_caught_up:x", "cell", "tag:durability", "tag:=high"} {
+		if _, err := NewMasterCandidatePolicy(spec); err == nil {
+			t.Errorf("NewMasterCandidatePolicy(%v) must fail", spec)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/vt/concurrency"
@@ -202,69 +203,134 @@ func (wr *Wrangler) ValidateSchemaKeyspace(ctx context.Context, keyspace string,
 		return wr.ValidateSchemaShard(ctx, keyspace, shards[0], excludeTables, includeViews)
 	}
 
-	// find the reference schema using the first shard's master
-	si, err := wr.ts.GetShard(ctx, keyspace, shards[0])
+	er := concurrency.AllErrorRecorder{}
+	reference, others, err := wr.gatherKeyspaceSchemas(ctx, keyspace, shards, excludeTables, includeViews, &er)
 	if err != nil {
-		return fmt.Errorf("GetShard(%v, %v) failed: %v", keyspace, shards[0], err)
+		return err
 	}
-	if !si.HasMaster() {
-		return fmt.Errorf("No master in shard %v/%v", keyspace, shards[0])
+	referenceName := topoproto.TabletAliasString(reference.alias)
+	for _, other := range others {
+		log.Infof("Diffing schema for %v", topoproto.TabletAliasString(other.alias))
+		tmutils.DiffSchema(referenceName, reference.schema, topoproto.TabletAliasString(other.alias), other.schema, &er)
 	}
-	referenceAlias := si.MasterAlias
-	log.Infof("Gathering schema for reference master %v", topoproto.TabletAliasString(referenceAlias))
-	referenceSchema, err := wr.GetSchema(ctx, referenceAlias, nil, excludeTables, includeViews)
+	if er.HasErrors() {
+		return fmt.Errorf("Schema diffs: %v", er.Error().Error())
+	}
+	return nil
+}
+
+// ValidateSchemaKeyspaceDiffs diffs the schema of all tablets in the keyspace
+// with the schema of the master of the first shard, like
+// ValidateSchemaKeyspace. It returns the differences per table instead of an
+// error. The returned error lists the tablets whose schema could not be read.
+func (wr *Wrangler) ValidateSchemaKeyspaceDiffs(ctx context.Context, keyspace string, excludeTables []string, includeViews bool) ([]*tmutils.SchemaDiff, error) {
+	shards, err := wr.ts.GetShardNames(ctx, keyspace)
 	if err != nil {
-		return fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", referenceAlias, excludeTables, includeViews, err)
+		return nil, fmt.Errorf("GetShardNames(%v) failed: %v", keyspace, err)
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("No shards in keyspace %v", keyspace)
 	}
+	sort.Strings(shards)
 
-	// then diff with all other tablets everywhere
 	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-
-	// first diff the slaves in the reference shard 0
-	aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shards[0])
+	reference, others, err := wr.gatherKeyspaceSchemas(ctx, keyspace, shards, excludeTables, includeViews, &er)
 	if err != nil {
-		return fmt.Errorf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shards[0], err)
-	}
+		return nil, err
+	}
+	var diffs []*tmutils.SchemaDiff
+	referenceName := topoproto.TabletAliasString(reference.alias)
+	for _, other := range others {
+		diffs = append(diffs, tmutils.StructuredDiffSchema(referenceName, reference.schema, topoproto.TabletAliasString(other.alias), other.schema)...)
+	}
+	// Group the differences of all tablets by table.
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Table < diffs[j].Table
+	})
+	return diffs, er.Error()
+}
 
-	for _, alias := range aliases {
-		if topoproto.TabletAliasEqual(alias, si.MasterAlias) {
-			continue
-		}
+// tabletSchema is the schema of one tablet.
+type tabletSchema struct {
+	alias  *topodatapb.TabletAlias
+	schema *tabletmanagerdatapb.SchemaDefinition
+}
 
-		wg.Add(1)
-		go wr.diffSchema(ctx, referenceSchema, referenceAlias, alias, excludeTables, includeViews, &wg, &er)
+// gatherKeyspaceSchemas reads the schema of all tablets in the shards of the
+// keyspace concurrently. It returns the schema of the master of the first
+// shard as reference and the schemas of all other tablets, sorted by alias.
+// It fails if the reference schema cannot be read. Other failures are
+// recorded in "er".
+func (wr *Wrangler) gatherKeyspaceSchemas(ctx context.Context, keyspace string, shards []string, excludeTables []string, includeViews bool, er *concurrency.AllErrorRecorder) (*tabletSchema, []*tabletSchema, error) {
+	// The master of the first shard must exist, it provides the
+	// reference schema.
+	si, err := wr.ts.GetShard(ctx, keyspace, shards[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetShard(%v, %v) failed: %v", keyspace, shards[0], err)
+	}
+	if !si.HasMaster() {
+		return nil, nil, fmt.Errorf("No master in shard %v/%v", keyspace, shards[0])
 	}
+	referenceAlias := si.MasterAlias
 
-	// then diffs all tablets in the other shards
-	for _, shard := range shards[1:] {
-		si, err := wr.ts.GetShard(ctx, keyspace, shard)
+	var mu sync.Mutex
+	var reference *tabletSchema
+	var others []*tabletSchema
+	wg := sync.WaitGroup{}
+	getSchema := func(alias *topodatapb.TabletAlias) {
+		defer wg.Done()
+		log.Infof("Gathering schema for %v", topoproto.TabletAliasString(alias))
+		schema, err := wr.GetSchema(ctx, alias, nil, excludeTables, includeViews)
 		if err != nil {
-			er.RecordError(fmt.Errorf("GetShard(%v, %v) failed: %v", keyspace, shard, err))
-			continue
-		}
-
-		if !si.HasMaster() {
-			er.RecordError(fmt.Errorf("No master in shard %v/%v", keyspace, shard))
-			continue
+			er.RecordError(fmt.Errorf("GetSchema(%v, nil, %v, %v) failed: %v", alias, excludeTables, includeViews, err))
+			return
 		}
-
-		aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
-		if err != nil {
-			er.RecordError(fmt.Errorf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err))
-			continue
+		mu.Lock()
+		defer mu.Unlock()
+		if topoproto.TabletAliasEqual(alias, referenceAlias) {
+			reference = &tabletSchema{alias: alias, schema: schema}
+		} else {
+			others = append(others, &tabletSchema{alias: alias, schema: schema})
 		}
+	}
 
-		for _, alias := range aliases {
-			wg.Add(1)
-			go wr.diffSchema(ctx, referenceSchema, referenceAlias, alias, excludeTables, includeViews, &wg, &er)
-		}
+	// All shards are read concurrently. Each shard starts the reads of the
+	// schemas of its tablets.
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i int, shard string) {
+			defer wg.Done()
+			if i > 0 {
+				si, err := wr.ts.GetShard(ctx, keyspace, shard)
+				if err != nil {
+					er.RecordError(fmt.Errorf("GetShard(%v, %v) failed: %v", keyspace, shard, err))
+					return
+				}
+				if !si.HasMaster() {
+					er.RecordError(fmt.Errorf("No master in shard %v/%v", keyspace, shard))
+					return
+				}
+			}
+			aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+			if err != nil {
+				er.RecordError(fmt.Errorf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err))
+				return
+			}
+			for _, alias := range aliases {
+				wg.Add(1)
+				go getSchema(alias)
+			}
+		}(i, shard)
 	}
 	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("Schema diffs: %v", er.Error().Error())
+
+	if reference == nil {
+		return nil, nil, fmt.Errorf("cannot read the reference schema of master %v of shard %v/%v: %v", topoproto.TabletAliasString(referenceAlias), keyspace, shards[0], er.Error())
 	}
-	return nil
+	sort.Slice(others, func(i, j int) bool {
+		return topoproto.TabletAliasString(others[i].alias) < topoproto.TabletAliasString(others[j].alias)
+	})
+	return reference, others, nil
 }
 
 // PreflightSchema will try a schema change on the remote tablet.
@@ -278,7 +344,7 @@ func (wr *Wrangler) PreflightSchema(ctx context.Context, tabletAlias *topodatapb
 
 // CopySchemaShardFromShard copies the schema from a source shard to the specified destination shard.
 // For both source and destination it picks the master tablet. See also CopySchemaShard.
-func (wr *Wrangler) CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitSlaveTimeout time.Duration) error {
+func (wr *Wrangler) CopySchemaShardFromShard(ctx context.Context, tables, excludeTables []string, includeViews, includeTriggers bool, sourceKeyspace, sourceShard, destKeyspace, destShard string, waitSlaveTimeout, waitReplicasTimeout time.Duration) error {
 	sourceShardInfo, err := wr.ts.GetShard(ctx, sourceKeyspace, sourceShard)
 	if err != nil {
 		return fmt.Errorf("GetShard(%v, %v) failed: %v", sourceKeyspace, sourceShard, err)
@@ -287,14 +353,18 @@ func (wr *Wrangler) CopySchemaShardFromShard(ctx context.Context, tables, exclud
 		return fmt.Errorf("no master in shard record %v/%v. Consider running 'vtctl InitShardMaster' in case of a new shard or to reparent the shard to fix the topology data, or providing a non-master tablet alias", sourceKeyspace, sourceShard)
 	}
 
-	return wr.CopySchemaShard(ctx, sourceShardInfo.MasterAlias, tables, excludeTables, includeViews, destKeyspace, destShard, waitSlaveTimeout)
+	return wr.CopySchemaShard(ctx, sourceShardInfo.MasterAlias, tables, excludeTables, includeViews, includeTriggers, destKeyspace, destShard, waitSlaveTimeout, waitReplicasTimeout)
 }
 
 // CopySchemaShard copies the schema from a source tablet to the
 // specified shard.  The schema is applied directly on the master of
 // the destination shard, and is propogated to the replicas through
 // binlogs.
-func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews bool, destKeyspace, destShard string, waitSlaveTimeout time.Duration) error {
+// With "includeTriggers", the triggers of the copied tables are copied too.
+// If "waitReplicasTimeout" is set, CopySchemaShard fails unless all REPLICA
+// and RDONLY tablets of the destination shard replicated the schema within
+// this time. Otherwise, their schema reload is best-effort.
+func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, tables, excludeTables []string, includeViews, includeTriggers bool, destKeyspace, destShard string, waitSlaveTimeout, waitReplicasTimeout time.Duration) error {
 	destShardInfo, err := wr.ts.GetShard(ctx, destKeyspace, destShard)
 	if err != nil {
 		return fmt.Errorf("GetShard(%v, %v) failed: %v", destKeyspace, destShard, err)
@@ -313,7 +383,7 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 	if err != nil {
 		return fmt.Errorf("CopySchemaShard failed because schemas could not be compared initially: %v", err)
 	}
-	if diffs == nil {
+	if diffs == nil && !includeTriggers {
 		// Return early because dest has already the same schema as source.
 		return nil
 	}
@@ -322,20 +392,32 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 	if err != nil {
 		return fmt.Errorf("GetSchema(%v, %v, %v, %v) failed: %v", sourceTabletAlias, tables, excludeTables, includeViews, err)
 	}
-	createSQL := tmutils.SchemaDefinitionToSQLStrings(sourceSd)
 	destTabletInfo, err := wr.ts.GetTablet(ctx, destShardInfo.MasterAlias)
 	if err != nil {
 		return fmt.Errorf("GetTablet(%v) failed: %v", destShardInfo.MasterAlias, err)
 	}
-	for i, sqlLine := range createSQL {
-		err = wr.applySQLShard(ctx, destTabletInfo, sqlLine, i == len(createSQL)-1)
+	if diffs != nil {
+		createSQL := tmutils.SchemaDefinitionToSQLStrings(sourceSd)
+		for i, sqlLine := range createSQL {
+			err = wr.applySQLShard(ctx, destTabletInfo, sqlLine, i == len(createSQL)-1)
+			if err != nil {
+				return fmt.Errorf("creating a table failed."+
+					" Most likely some tables already exist on the destination and differ from the source."+
+					" Please remove all to be copied tables from the destination manually and run this command again."+
+					" Full error: %v", err)
+			}
+		}
+	}
+	copiedTriggers := 0
+	if includeTriggers {
+		copiedTriggers, err = wr.copyTriggers(ctx, sourceTabletAlias, destTabletInfo, sourceSd)
 		if err != nil {
-			return fmt.Errorf("creating a table failed."+
-				" Most likely some tables already exist on the destination and differ from the source."+
-				" Please remove all to be copied tables from the destination manually and run this command again."+
-				" Full error: %v", err)
+			return fmt.Errorf("CopySchemaShard failed to copy the triggers: %v", err)
 		}
 	}
+	if diffs == nil && copiedTriggers == 0 {
+		return nil
+	}
 
 	// Remember the replication position after all the above were applied.
 	destMasterPos, err := wr.tmc.MasterPosition(ctx, destTabletInfo.Tablet)
@@ -357,6 +439,10 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 		return fmt.Errorf("CopySchemaShard was not successful because the schemas between the two tablets %v and %v differ: %v", sourceTabletAlias, destShardInfo.MasterAlias, diffs)
 	}
 
+	if waitReplicasTimeout > 0 {
+		return wr.waitForSchemaReplicated(ctx, destKeyspace, destShard, destMasterPos, waitReplicasTimeout)
+	}
+
 	// Notify slaves to reload schema. This is best-effort.
 	concurrency := sync2.NewSemaphore(10, 0)
 	reloadCtx, cancel := context.WithTimeout(ctx, waitSlaveTimeout)
@@ -365,6 +451,103 @@ func (wr *Wrangler) CopySchemaShard(ctx context.Context, sourceTabletAlias *topo
 	return nil
 }
 
+// copyTriggers creates the triggers of the tables in "sd" on the destination
+// master unless a trigger with the same name already exists there. It
+// returns the number of created triggers.
+func (wr *Wrangler) copyTriggers(ctx context.Context, sourceTabletAlias *topodatapb.TabletAlias, destTabletInfo *topo.TabletInfo, sd *tabletmanagerdatapb.SchemaDefinition) (int, error) {
+	sourceTabletInfo, err := wr.ts.GetTablet(ctx, sourceTabletAlias)
+	if err != nil {
+		return 0, fmt.Errorf("GetTablet(%v) failed: %v", sourceTabletAlias, err)
+	}
+	sourceTriggers, err := wr.listTriggers(ctx, sourceTabletInfo)
+	if err != nil {
+		return 0, err
+	}
+	destTriggers, err := wr.listTriggers(ctx, destTabletInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	var names []string
+	for name := range sourceTriggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	count := 0
+	for _, name := range names {
+		if _, ok := tmutils.SchemaDefinitionGetTable(sd, sourceTriggers[name]); !ok {
+			// The table of the trigger is not copied.
+			continue
+		}
+		if _, ok := destTriggers[name]; ok {
+			wr.Logger().Infof("trigger %v already exists on %v, not copying it", name, destTabletInfo.AliasString())
+			continue
+		}
+		qr, err := wr.tmc.ExecuteFetchAsDba(ctx, sourceTabletInfo.Tablet, false, []byte(fmt.Sprintf("SHOW CREATE TRIGGER %v", sqlescape.EscapeID(name))), 1, false, false)
+		if err != nil {
+			return count, fmt.Errorf("cannot read the definition of trigger %v on %v: %v", name, sourceTabletInfo.AliasString(), err)
+		}
+		// The third column is "SQL Original Statement".
+		result := sqltypes.Proto3ToResult(qr)
+		if len(result.Rows) != 1 || len(result.Rows[0]) < 3 {
+			return count, fmt.Errorf("unexpected result of SHOW CREATE TRIGGER %v on %v: %v", name, sourceTabletInfo.AliasString(), result.Rows)
+		}
+		createTrigger := result.Rows[0][2].ToString()
+		if _, err := wr.tmc.ExecuteFetchAsDba(ctx, destTabletInfo.Tablet, false, []byte(createTrigger), 0, false, false); err != nil {
+			return count, fmt.Errorf("creating trigger %v on %v failed: %v", name, destTabletInfo.AliasString(), err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// listTriggers returns the table of each trigger in the database of the tablet.
+func (wr *Wrangler) listTriggers(ctx context.Context, ti *topo.TabletInfo) (map[string]string, error) {
+	queryBuf := bytes.Buffer{}
+	queryBuf.WriteString("SELECT trigger_name, event_object_table FROM information_schema.triggers WHERE trigger_schema = ")
+	sqltypes.NewVarChar(ti.DbName()).EncodeSQL(&queryBuf)
+	qr, err := wr.tmc.ExecuteFetchAsDba(ctx, ti.Tablet, false, queryBuf.Bytes(), 10000, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the triggers on %v: %v", ti.AliasString(), err)
+	}
+	triggers := make(map[string]string)
+	for _, row := range sqltypes.Proto3ToResult(qr).Rows {
+		triggers[row[0].ToString()] = row[1].ToString()
+	}
+	return triggers, nil
+}
+
+// waitForSchemaReplicated waits until all REPLICA and RDONLY tablets of the
+// shard reached "pos" and reloads their schema. Unlike ReloadSchemaShard, it
+// fails if one of them does not get there within "timeout".
+func (wr *Wrangler) waitForSchemaReplicated(ctx context.Context, keyspace, shard, pos string, timeout time.Duration) error {
+	tablets, err := wr.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return fmt.Errorf("GetTabletMapForShard(%v, %v) failed: %v", keyspace, shard, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	rec := concurrency.AllErrorRecorder{}
+	var wg sync.WaitGroup
+	for _, ti := range tablets {
+		if ti.Type != topodatapb.TabletType_REPLICA && ti.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		wg.Add(1)
+		go func(ti *topo.TabletInfo) {
+			defer wg.Done()
+			if err := wr.tmc.ReloadSchema(ctx, ti.Tablet, pos); err != nil {
+				rec.RecordError(fmt.Errorf("tablet %v did not replicate the schema change: %v", ti.AliasString(), err))
+				return
+			}
+			wr.Logger().Infof("tablet %v replicated the schema change", ti.AliasString())
+		}(ti)
+	}
+	wg.Wait()
+	return rec.Error()
+}
+
 // copyShardMetadata copies contents of _vt.shard_metadata table from the source
 // tablet to the destination tablet. It's assumed that destination tablet is a
 // master and binlogging is not turned off when INSERT statements are executed.
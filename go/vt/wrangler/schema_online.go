@@ -0,0 +1,591 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file implements ApplySchemaOnline. It changes the schema of a table
+// the same way as pt-online-schema-change does: The change is applied to an
+// empty copy of the table (the shadow table). Triggers on the original
+// table keep the shadow table up to date while the existing rows are copied
+// in small chunks. At the end, both tables are swapped with an atomic
+// RENAME TABLE. The original table is locked only for the duration of each
+// chunk and of the RENAME, instead of the whole ALTER TABLE.
+
+const (
+	// DefaultOnlineSchemaChangeChunkSize is the default number of rows which
+	// are copied by ApplySchemaOnline with one statement.
+	DefaultOnlineSchemaChangeChunkSize = 1000
+
+	// onlineSchemaChangeStatementTimeout is the timeout of each statement
+	// which ApplySchemaOnline runs on a master, except for the copy of a
+	// chunk.
+	onlineSchemaChangeStatementTimeout = 30 * time.Second
+
+	// onlineSchemaChangeCutOverAttempts is the number of times
+	// ApplySchemaOnline tries to swap the tables on a shard.
+	onlineSchemaChangeCutOverAttempts = 3
+
+	// onlineSchemaChangeCutOverRetryDelay is the delay between two attempts
+	// of the cut-over.
+	onlineSchemaChangeCutOverRetryDelay = time.Second
+)
+
+// onlineSchemaChange is the state of ApplySchemaOnline on one shard.
+type onlineSchemaChange struct {
+	wr       *Wrangler
+	keyspace string
+	shard    string
+	master   *topo.TabletInfo
+	// replicas are checked for their replication lag between two chunks.
+	replicas []*topo.TabletInfo
+
+	table       string
+	shadowTable string
+	oldTable    string
+	triggers    []string
+
+	// populated by prepare()
+	// columns are the columns which exist in the original and in the
+	// shadow table. Only they are copied.
+	columns   []string
+	pkColumns []string
+
+	// These fields record the progress. They are used for the rollback.
+	shadowCreated   bool
+	triggersCreated bool
+	cutOverDone     bool
+	copiedRows      uint64
+}
+
+func (wr *Wrangler) newOnlineSchemaChange(ctx context.Context, si *topo.ShardInfo, table string) (*onlineSchemaChange, error) {
+	if !si.HasMaster() {
+		return nil, fmt.Errorf("shard %v/%v has no master", si.Keyspace(), si.ShardName())
+	}
+	tabletMap, err := wr.ts.GetTabletMapForShard(ctx, si.Keyspace(), si.ShardName())
+	if err != nil && !topo.IsErrType(err, topo.PartialResult) {
+		return nil, fmt.Errorf("GetTabletMapForShard(%v/%v) failed: %v", si.Keyspace(), si.ShardName(), err)
+	}
+	master, ok := tabletMap[topoproto.TabletAliasString(si.MasterAlias)]
+	if !ok {
+		return nil, fmt.Errorf("master %v of shard %v/%v not found", topoproto.TabletAliasString(si.MasterAlias), si.Keyspace(), si.ShardName())
+	}
+	osc := &onlineSchemaChange{
+		wr:          wr,
+		keyspace:    si.Keyspace(),
+		shard:       si.ShardName(),
+		master:      master,
+		table:       table,
+		shadowTable: "_" + table + "_new",
+		oldTable:    "_" + table + "_old",
+		triggers:    []string{"_" + table + "_ins", "_" + table + "_upd", "_" + table + "_del"},
+	}
+	for _, ti := range tabletMap {
+		if ti.Type == topodatapb.TabletType_REPLICA || ti.Type == topodatapb.TabletType_RDONLY {
+			osc.replicas = append(osc.replicas, ti)
+		}
+	}
+	return osc, nil
+}
+
+func (osc *onlineSchemaChange) String() string {
+	return topoproto.KeyspaceShardString(osc.keyspace, osc.shard)
+}
+
+// execute runs "query" on the master. The statement is replicated.
+func (osc *onlineSchemaChange) execute(ctx context.Context, query string, maxRows int, reloadSchema bool) (*sqltypes.Result, error) {
+	qr, err := osc.wr.tmc.ExecuteFetchAsDba(ctx, osc.master.Tablet, false /* usePool */, []byte(query), maxRows, false /* disableBinlogs */, reloadSchema)
+	if err != nil {
+		return nil, fmt.Errorf("%v: query %v failed: %v", osc, query, err)
+	}
+	return sqltypes.Proto3ToResult(qr), nil
+}
+
+// executeWithTimeout runs "query" with onlineSchemaChangeStatementTimeout.
+func (osc *onlineSchemaChange) executeWithTimeout(ctx context.Context, query string) error {
+	ctx, cancel := context.WithTimeout(ctx, onlineSchemaChangeStatementTimeout)
+	defer cancel()
+	_, err := osc.execute(ctx, query, 0, false /* reloadSchema */)
+	return err
+}
+
+// prepare creates the shadow table, applies the change to it and creates
+// the triggers which keep it up to date.
+func (osc *onlineSchemaChange) prepare(ctx context.Context, alter string) error {
+	tabletAlias := osc.master.Alias
+	sd, err := osc.wr.GetSchema(ctx, tabletAlias, []string{osc.table}, nil /* excludeTables */, false /* includeViews */)
+	if err != nil {
+		return err
+	}
+	if len(sd.TableDefinitions) != 1 {
+		return fmt.Errorf("%v: table %v does not exist", osc, osc.table)
+	}
+	td := sd.TableDefinitions[0]
+	if len(td.PrimaryKeyColumns) == 0 {
+		return fmt.Errorf("%v: table %v has no primary key", osc, osc.table)
+	}
+	osc.pkColumns = td.PrimaryKeyColumns
+
+	// Left-overs of a previous run must be removed manually. Otherwise, we
+	// may drop a table with data which is still needed.
+	leftOvers, err := osc.wr.GetSchema(ctx, tabletAlias, []string{osc.shadowTable, osc.oldTable}, nil /* excludeTables */, false /* includeViews */)
+	if err != nil {
+		return err
+	}
+	if len(leftOvers.TableDefinitions) > 0 {
+		return fmt.Errorf("%v: table %v already exists. It is probably a left-over of a previous ApplySchemaOnline and must be dropped manually", osc, leftOvers.TableDefinitions[0].Name)
+	}
+
+	if err := osc.executeWithTimeout(ctx, fmt.Sprintf("CREATE TABLE %v LIKE %v", sqlescape.EscapeID(osc.shadowTable), sqlescape.EscapeID(osc.table))); err != nil {
+		return err
+	}
+	osc.shadowCreated = true
+	if err := osc.executeWithTimeout(ctx, fmt.Sprintf("ALTER TABLE %v %v", sqlescape.EscapeID(osc.shadowTable), alter)); err != nil {
+		return err
+	}
+
+	sd, err = osc.wr.GetSchema(ctx, tabletAlias, []string{osc.shadowTable}, nil /* excludeTables */, false /* includeViews */)
+	if err != nil {
+		return err
+	}
+	if len(sd.TableDefinitions) != 1 {
+		return fmt.Errorf("%v: table %v was not created", osc, osc.shadowTable)
+	}
+	shadowTd := sd.TableDefinitions[0]
+	// The triggers and the copy rely on the primary key to find the rows.
+	if !reflect.DeepEqual(shadowTd.PrimaryKeyColumns, td.PrimaryKeyColumns) {
+		return fmt.Errorf("%v: the change must not modify the primary key: old = %v, new = %v", osc, td.PrimaryKeyColumns, shadowTd.PrimaryKeyColumns)
+	}
+	shadowColumns := make(map[string]bool)
+	for _, c := range shadowTd.Columns {
+		shadowColumns[c] = true
+	}
+	for _, c := range td.Columns {
+		if shadowColumns[c] {
+			osc.columns = append(osc.columns, c)
+		}
+	}
+
+	osc.triggersCreated = true
+	for _, trigger := range osc.triggerStatements() {
+		if err := osc.executeWithTimeout(ctx, trigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// triggerStatements returns the CREATE TRIGGER statements which apply
+// each change of the original table to the shadow table.
+func (osc *onlineSchemaChange) triggerStatements() []string {
+	table := sqlescape.EscapeID(osc.table)
+	shadowTable := sqlescape.EscapeID(osc.shadowTable)
+	columns := escapeIDs(osc.columns)
+	newValues := make([]string, len(osc.columns))
+	for i, c := range osc.columns {
+		newValues[i] = "NEW." + sqlescape.EscapeID(c)
+	}
+	pkMatch := make([]string, len(osc.pkColumns))
+	for i, c := range osc.pkColumns {
+		pkMatch[i] = fmt.Sprintf("%v <=> OLD.%v", sqlescape.EscapeID(c), sqlescape.EscapeID(c))
+	}
+
+	replace := fmt.Sprintf("REPLACE INTO %v (%v) VALUES (%v)", shadowTable, strings.Join(columns, ", "), strings.Join(newValues, ", "))
+	deleteOld := fmt.Sprintf("DELETE IGNORE FROM %v WHERE %v", shadowTable, strings.Join(pkMatch, " AND "))
+	return []string{
+		fmt.Sprintf("CREATE TRIGGER %v AFTER INSERT ON %v FOR EACH ROW %v", sqlescape.EscapeID(osc.triggers[0]), table, replace),
+		// An update may change the primary key. Therefore, the old row is
+		// removed first.
+		fmt.Sprintf("CREATE TRIGGER %v AFTER UPDATE ON %v FOR EACH ROW BEGIN %v; %v; END", sqlescape.EscapeID(osc.triggers[1]), table, deleteOld, replace),
+		fmt.Sprintf("CREATE TRIGGER %v AFTER DELETE ON %v FOR EACH ROW %v", sqlescape.EscapeID(osc.triggers[2]), table, deleteOld),
+	}
+}
+
+// copy copies the existing rows in chunks of "chunkSize" rows ordered by the
+// primary key. The bounds of a chunk are tuples of all primary key columns.
+// Otherwise, a chunk would end in the middle of the rows which share the
+// same value of the first column and the remaining rows would be skipped.
+// Rows which were already inserted by the triggers are not overwritten.
+func (osc *onlineSchemaChange) copy(ctx context.Context, chunkSize int, chunkSleep, maxReplicationLag time.Duration) error {
+	pkColumns := escapeIDs(osc.pkColumns)
+	pkList := strings.Join(pkColumns, ", ")
+	pk := sqlTuple(pkColumns)
+	table := sqlescape.EscapeID(osc.table)
+	columns := strings.Join(escapeIDs(osc.columns), ", ")
+
+	var lowerBound string
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var conditions []string
+		if lowerBound != "" {
+			conditions = append(conditions, fmt.Sprintf("%v > %v", pk, lowerBound))
+		}
+		// Find the upper bound of the chunk. If there is none, this is the
+		// last chunk.
+		qr, err := osc.execute(ctx, fmt.Sprintf("SELECT %v FROM %v%v ORDER BY %v LIMIT %v, 1", pkList, table, whereClause(conditions), pkList, chunkSize-1), 1, false /* reloadSchema */)
+		if err != nil {
+			return err
+		}
+		upperBound := ""
+		if len(qr.Rows) == 1 {
+			values := make([]string, len(qr.Rows[0]))
+			for i, v := range qr.Rows[0] {
+				values[i] = encodeSQLValue(v)
+			}
+			upperBound = sqlTuple(values)
+			conditions = append(conditions, fmt.Sprintf("%v <= %v", pk, upperBound))
+		}
+
+		qr, err = osc.execute(ctx, fmt.Sprintf("INSERT IGNORE INTO %v (%v) SELECT %v FROM %v%v LOCK IN SHARE MODE", sqlescape.EscapeID(osc.shadowTable), columns, columns, table, whereClause(conditions)), 0, false /* reloadSchema */)
+		if err != nil {
+			return err
+		}
+		osc.copiedRows += qr.RowsAffected
+		if upperBound == "" {
+			osc.wr.Logger().Infof("%v: copied %v rows of table %v", osc, osc.copiedRows, osc.table)
+			return nil
+		}
+		lowerBound = upperBound
+
+		if err := osc.throttle(ctx, chunkSleep, maxReplicationLag); err != nil {
+			return err
+		}
+	}
+}
+
+// throttle waits "chunkSleep" and then until the replication lag of all
+// replicas is at most "maxReplicationLag" (if set).
+func (osc *onlineSchemaChange) throttle(ctx context.Context, chunkSleep, maxReplicationLag time.Duration) error {
+	if chunkSleep > 0 {
+		select {
+		case <-time.After(chunkSleep):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if maxReplicationLag == 0 {
+		return nil
+	}
+
+	for {
+		lag := osc.replicationLag(ctx)
+		if lag <= maxReplicationLag {
+			return nil
+		}
+		osc.wr.Logger().Infof("%v: replication lag is %v, waiting until it is below %v", osc, lag, maxReplicationLag)
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// replicationLag returns the highest replication lag of all replicas.
+// Replicas which cannot be reached are ignored. Otherwise, a single broken
+// replica would block the change.
+func (osc *onlineSchemaChange) replicationLag(ctx context.Context) time.Duration {
+	var maxLag time.Duration
+	for _, ti := range osc.replicas {
+		shortCtx, cancel := context.WithTimeout(ctx, onlineSchemaChangeStatementTimeout)
+		status, err := osc.wr.tmc.SlaveStatus(shortCtx, ti.Tablet)
+		cancel()
+		if err != nil {
+			osc.wr.Logger().Warningf("%v: cannot get the replication lag of %v: %v", osc, topoproto.TabletAliasString(ti.Alias), err)
+			continue
+		}
+		if lag := time.Duration(status.SecondsBehindMaster) * time.Second; lag > maxLag {
+			maxLag = lag
+		}
+	}
+	return maxLag
+}
+
+// cutOver swaps the original and the shadow table and drops the triggers.
+// It may be called again after it failed.
+func (osc *onlineSchemaChange) cutOver(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, onlineSchemaChangeStatementTimeout)
+	defer cancel()
+	if !osc.cutOverDone {
+		if _, err := osc.execute(ctx, osc.renameStatement(), 0, true /* reloadSchema */); err != nil {
+			// The RENAME may have been executed although the RPC failed.
+			swapped, checkErr := osc.isSwapped(ctx)
+			if checkErr != nil || !swapped {
+				return err
+			}
+		}
+		osc.cutOverDone = true
+	}
+	// The triggers moved with the old table and are no longer needed.
+	return osc.dropTriggers(ctx)
+}
+
+func (osc *onlineSchemaChange) renameStatement() string {
+	return fmt.Sprintf("RENAME TABLE %v TO %v, %v TO %v", sqlescape.EscapeID(osc.table), sqlescape.EscapeID(osc.oldTable), sqlescape.EscapeID(osc.shadowTable), sqlescape.EscapeID(osc.table))
+}
+
+// isSwapped returns true if the original table was already renamed to the
+// old table and the shadow table to the original table.
+func (osc *onlineSchemaChange) isSwapped(ctx context.Context) (bool, error) {
+	sd, err := osc.wr.GetSchema(ctx, osc.master.Alias, []string{osc.shadowTable, osc.oldTable}, nil /* excludeTables */, false /* includeViews */)
+	if err != nil {
+		return false, err
+	}
+	return len(sd.TableDefinitions) == 1 && sd.TableDefinitions[0].Name == osc.oldTable, nil
+}
+
+func (osc *onlineSchemaChange) dropTriggers(ctx context.Context) error {
+	for _, trigger := range osc.triggers {
+		if err := osc.executeWithTimeout(ctx, "DROP TRIGGER IF EXISTS "+sqlescape.EscapeID(trigger)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback removes the triggers and the shadow table. It must not be
+// called after the cut-over.
+func (osc *onlineSchemaChange) rollback(ctx context.Context) error {
+	if osc.triggersCreated {
+		if err := osc.dropTriggers(ctx); err != nil {
+			return err
+		}
+	}
+	if osc.shadowCreated {
+		if err := osc.executeWithTimeout(ctx, "DROP TABLE IF EXISTS "+sqlescape.EscapeID(osc.shadowTable)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplySchemaOnline changes the schema of "table" on all shards of
+// "keyspace" without locking the table for the duration of the change.
+// "alter" is the part of an ALTER TABLE statement after the table name
+// e.g. "ADD COLUMN c INT". It must not change the primary key.
+// The rows are copied in chunks of "chunkSize" rows. After each chunk, it
+// waits "chunkSleep" and until the replication lag of all replicas is at most
+// "maxReplicationLag" (0 disables the check).
+// The tables are swapped only after the copy has finished on all shards.
+// If the change fails on any shard before that, it is rolled back on all
+// shards. Once the tables were swapped on a shard, a failed swap on the
+// other shards is retried instead. If "dropOldTable" is false, the original
+// table is kept as _<table>_old.
+// The keyspace is locked for the duration of the change.
+func (wr *Wrangler) ApplySchemaOnline(ctx context.Context, keyspace, table, alter string, chunkSize int, chunkSleep, maxReplicationLag time.Duration, dropOldTable bool) (err error) {
+	if chunkSize < 1 {
+		return fmt.Errorf("chunk size must be at least 1: %v", chunkSize)
+	}
+	if strings.TrimSpace(alter) == "" {
+		return fmt.Errorf("no change specified for table %v", table)
+	}
+
+	// Two concurrent changes of the same table would use the same shadow
+	// table and triggers.
+	ctx, unlock, lockErr := wr.ts.LockKeyspace(ctx, keyspace, fmt.Sprintf("ApplySchemaOnline(%v)", table))
+	if lockErr != nil {
+		return lockErr
+	}
+	defer unlock(&err)
+
+	shards, err := wr.ts.FindAllShardsInKeyspace(ctx, keyspace)
+	if err != nil {
+		return fmt.Errorf("FindAllShardsInKeyspace(%v) failed: %v", keyspace, err)
+	}
+	shardNames := make([]string, 0, len(shards))
+	for name := range shards {
+		shardNames = append(shardNames, name)
+	}
+	sort.Strings(shardNames)
+	var changes []*onlineSchemaChange
+	for _, name := range shardNames {
+		osc, err := wr.newOnlineSchemaChange(ctx, shards[name], table)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, osc)
+	}
+
+	wr.Logger().Infof("Copying table %v to the changed shadow table on %v shards", table, len(changes))
+	if err := runOnlineSchemaChanges(changes, func(osc *onlineSchemaChange) error {
+		if err := osc.prepare(ctx, alter); err != nil {
+			return err
+		}
+		return osc.copy(ctx, chunkSize, chunkSleep, maxReplicationLag)
+	}); err != nil {
+		wr.rollbackOnlineSchemaChanges(changes)
+		return fmt.Errorf("ApplySchemaOnline failed and was rolled back: %v", err)
+	}
+
+	wr.Logger().Infof("Swapping table %v and the shadow table on %v shards", table, len(changes))
+	if err := wr.cutOverOnlineSchemaChanges(ctx, changes); err != nil {
+		return err
+	}
+
+	if dropOldTable {
+		if err := runOnlineSchemaChanges(changes, func(osc *onlineSchemaChange) error {
+			return osc.executeWithTimeout(ctx, "DROP TABLE IF EXISTS "+sqlescape.EscapeID(osc.oldTable))
+		}); err != nil {
+			return fmt.Errorf("the schema of table %v was changed on all shards, but the old table could not be dropped: %v", table, err)
+		}
+	}
+	wr.Logger().Infof("Changed the schema of table %v on all shards", table)
+	return nil
+}
+
+// cutOverOnlineSchemaChanges swaps the tables on all shards. A failed swap
+// is retried up to onlineSchemaChangeCutOverAttempts times.
+// If no shard was swapped in the end, the change is rolled back. Otherwise,
+// it cannot be rolled back anymore because the new tables may already have
+// rows which are not in the old tables. In that case, the shadow tables and
+// the triggers of the remaining shards are kept such that the change can be
+// finished manually.
+func (wr *Wrangler) cutOverOnlineSchemaChanges(ctx context.Context, changes []*onlineSchemaChange) error {
+	pending := changes
+	var err error
+	for attempt := 1; ; attempt++ {
+		var mu sync.Mutex
+		var failed []*onlineSchemaChange
+		err = runOnlineSchemaChanges(pending, func(osc *onlineSchemaChange) error {
+			if err := osc.cutOver(ctx); err != nil {
+				mu.Lock()
+				failed = append(failed, osc)
+				mu.Unlock()
+				return err
+			}
+			return nil
+		})
+		if err == nil {
+			return nil
+		}
+		pending = failed
+		if attempt == onlineSchemaChangeCutOverAttempts || ctx.Err() != nil {
+			break
+		}
+		wr.Logger().Warningf("The cut-over of ApplySchemaOnline failed on %v shards and will be retried in %v: %v", len(pending), onlineSchemaChangeCutOverRetryDelay, err)
+		select {
+		case <-time.After(onlineSchemaChangeCutOverRetryDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	var swapped bool
+	for _, osc := range changes {
+		if osc.cutOverDone {
+			swapped = true
+		}
+	}
+	if !swapped {
+		wr.rollbackOnlineSchemaChanges(changes)
+		return fmt.Errorf("ApplySchemaOnline failed during the cut-over and was rolled back: %v", err)
+	}
+
+	var remaining []string
+	for _, osc := range pending {
+		if osc.cutOverDone {
+			remaining = append(remaining, fmt.Sprintf("%v (DROP TRIGGER for %v)", osc, strings.Join(osc.triggers, ", ")))
+		} else {
+			remaining = append(remaining, fmt.Sprintf("%v (%v, then DROP TRIGGER for %v)", osc, osc.renameStatement(), strings.Join(osc.triggers, ", ")))
+		}
+	}
+	sort.Strings(remaining)
+	return fmt.Errorf("ApplySchemaOnline failed during the cut-over after the tables were already swapped on some shards. The shadow tables and the triggers of the remaining shards were kept and stay up to date. Finish the change on their masters: %v: %v", strings.Join(remaining, "; "), err)
+}
+
+// rollbackOnlineSchemaChanges rolls back all changes which were not cut
+// over yet. Errors are only logged.
+func (wr *Wrangler) rollbackOnlineSchemaChanges(changes []*onlineSchemaChange) {
+	var rollback []*onlineSchemaChange
+	for _, osc := range changes {
+		if !osc.cutOverDone {
+			rollback = append(rollback, osc)
+		}
+	}
+	// The context of the command may have been canceled already.
+	ctx := context.Background()
+	if err := runOnlineSchemaChanges(rollback, func(osc *onlineSchemaChange) error {
+		return osc.rollback(ctx)
+	}); err != nil {
+		wr.Logger().Errorf("The rollback of ApplySchemaOnline failed. The shadow tables and the triggers must be dropped manually: %v", err)
+	}
+}
+
+// runOnlineSchemaChanges runs "f" for all shards in parallel.
+func runOnlineSchemaChanges(changes []*onlineSchemaChange, f func(osc *onlineSchemaChange) error) error {
+	wg := sync.WaitGroup{}
+	rec := concurrency.AllErrorRecorder{}
+	for _, osc := range changes {
+		wg.Add(1)
+		go func(osc *onlineSchemaChange) {
+			defer wg.Done()
+			rec.RecordError(f(osc))
+		}(osc)
+	}
+	wg.Wait()
+	return rec.Error()
+}
+
+func escapeIDs(ids []string) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = sqlescape.EscapeID(id)
+	}
+	return result
+}
+
+func encodeSQLValue(v sqltypes.Value) string {
+	buf := &bytes.Buffer{}
+	v.EncodeSQL(buf)
+	return buf.String()
+}
+
+// sqlTuple returns "values" as row constructor e.g. "(`a`, `b`)". A single
+// value is returned as it is.
+func sqlTuple(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
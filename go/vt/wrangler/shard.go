@@ -18,6 +18,7 @@ package wrangler
 
 import (
 	"fmt"
+	"time"
 
 	"golang.org/x/net/context"
 	"vitess.io/vitess/go/vt/topo"
@@ -122,7 +123,10 @@ func (wr *Wrangler) SetShardTabletControl(ctx context.Context, keyspace, shard s
 }
 
 // DeleteShard will do all the necessary changes in the topology server
-// to entirely remove a shard.
+// to entirely remove a shard: its tablets (if recursive is set), its
+// replication graph, and its references in the SrvKeyspace of all cells.
+// Unless evenIfServing is set, it refuses to delete a shard that is
+// serving, or that still has serving tablets.
 func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, recursive, evenIfServing bool) error {
 	// Read the Shard object. If it's not there, try to clean up
 	// the topology anyway.
@@ -141,7 +145,10 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 		return fmt.Errorf("shard %v/%v is still serving, cannot delete it, use even_if_serving flag if needed", keyspace, shard)
 	}
 
-	// Go through all the cells.
+	// Go through all the cells, and find all tablets that may belong
+	// to our shard. We check them all before deleting any of them,
+	// so we don't leave a half-deleted shard behind.
+	tabletMaps := make(map[string]map[string]*topo.TabletInfo)
 	for _, cell := range shardInfo.Cells {
 		var aliases []*topodatapb.TabletAlias
 
@@ -187,31 +194,43 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 			}
 		}
 
-		// Now see if we need to DeleteTablet, and if we can, do it.
-		if len(tabletMap) > 0 {
-			if !recursive {
-				return fmt.Errorf("shard %v/%v still has %v tablets in cell %v; use -recursive or remove them manually", keyspace, shard, len(tabletMap), cell)
-			}
-
-			wr.Logger().Infof("Deleting all tablets in shard %v/%v cell %v", keyspace, shard, cell)
+		// See if we can DeleteTablet.
+		if len(tabletMap) == 0 {
+			continue
+		}
+		if !recursive {
+			return fmt.Errorf("shard %v/%v still has %v tablets in cell %v; use -recursive or remove them manually", keyspace, shard, len(tabletMap), cell)
+		}
+		if !evenIfServing {
 			for tabletAlias, tabletInfo := range tabletMap {
-				// We don't care about scrapping or updating the replication graph,
-				// because we're about to delete the entire replication graph.
-				wr.Logger().Infof("Deleting tablet %v", tabletAlias)
-				if err := wr.TopoServer().DeleteTablet(ctx, tabletInfo.Alias); err != nil && !topo.IsErrType(err, topo.NoNode) {
-					// We don't want to continue if a DeleteTablet fails for
-					// any good reason (other than missing tablet, in which
-					// case it's just a topology server inconsistency we can
-					// ignore). If we continue and delete the replication
-					// graph, the tablet record will be orphaned, since
-					// we'll no longer know it belongs to this shard.
-					//
-					// If the problem is temporary, or resolved externally, re-running
-					// DeleteShard will skip over tablets that were already deleted.
-					return fmt.Errorf("can't delete tablet %v: %v", tabletAlias, err)
+				if topo.IsInServingGraph(tabletInfo.Type) {
+					return fmt.Errorf("shard %v/%v still has serving tablet %v of type %v in cell %v, cannot delete it, use even_if_serving flag if needed", keyspace, shard, tabletAlias, tabletInfo.Type, cell)
 				}
 			}
 		}
+		tabletMaps[cell] = tabletMap
+	}
+
+	// Now delete the tablets.
+	for cell, tabletMap := range tabletMaps {
+		wr.Logger().Infof("Deleting all tablets in shard %v/%v cell %v", keyspace, shard, cell)
+		for tabletAlias, tabletInfo := range tabletMap {
+			// We don't care about scrapping or updating the replication graph,
+			// because we're about to delete the entire replication graph.
+			wr.Logger().Infof("Deleting tablet %v", tabletAlias)
+			if err := wr.TopoServer().DeleteTablet(ctx, tabletInfo.Alias); err != nil && !topo.IsErrType(err, topo.NoNode) {
+				// We don't want to continue if a DeleteTablet fails for
+				// any good reason (other than missing tablet, in which
+				// case it's just a topology server inconsistency we can
+				// ignore). If we continue and delete the replication
+				// graph, the tablet record will be orphaned, since
+				// we'll no longer know it belongs to this shard.
+				//
+				// If the problem is temporary, or resolved externally, re-running
+				// DeleteShard will skip over tablets that were already deleted.
+				return fmt.Errorf("can't delete tablet %v: %v", tabletAlias, err)
+			}
+		}
 	}
 
 	// Try to remove the replication graph and serving graph in each cell,
@@ -222,9 +241,79 @@ func (wr *Wrangler) DeleteShard(ctx context.Context, keyspace, shard string, rec
 		}
 	}
 
+	// Remove the references to the shard from the SrvKeyspace in
+	// all cells, the shard may be served in cells it doesn't have
+	// tablets in.
+	cells, err := wr.ts.GetKnownCells(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		if err := wr.removeShardFromSrvKeyspace(ctx, cell, keyspace, shard); err != nil {
+			wr.Logger().Warningf("Cannot remove shard %v/%v from SrvKeyspace in cell %v: %v", keyspace, shard, cell, err)
+		}
+	}
+
 	return wr.ts.DeleteShard(ctx, keyspace, shard)
 }
 
+// checkShardNotServing returns an error if the shard is serving,
+// or if any of its tablets is serving. A missing shard is not an error.
+func (wr *Wrangler) checkShardNotServing(ctx context.Context, keyspace, shard string) error {
+	shardInfo, err := wr.ts.GetShard(ctx, keyspace, shard)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return nil
+	case err != nil:
+		return err
+	}
+	if len(shardInfo.ServedTypes) > 0 {
+		return fmt.Errorf("shard %v/%v is still serving, cannot delete it, use even_if_serving flag if needed", keyspace, shard)
+	}
+
+	tabletMap, err := wr.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return fmt.Errorf("GetTabletMapForShard(%v, %v) failed: %v", keyspace, shard, err)
+	}
+	for tabletAlias, tabletInfo := range tabletMap {
+		if topo.IsInServingGraph(tabletInfo.Type) {
+			return fmt.Errorf("shard %v/%v still has serving tablet %v of type %v, cannot delete it, use even_if_serving flag if needed", keyspace, shard, tabletAlias, tabletInfo.Type)
+		}
+	}
+	return nil
+}
+
+// removeShardFromSrvKeyspace removes all references to a shard from the
+// partitions of the SrvKeyspace in a cell. A missing SrvKeyspace is not
+// an error.
+func (wr *Wrangler) removeShardFromSrvKeyspace(ctx context.Context, cell, keyspace, shard string) error {
+	srvKeyspace, err := wr.ts.GetSrvKeyspace(ctx, cell, keyspace)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	changed := false
+	for _, partition := range srvKeyspace.Partitions {
+		var shardReferences []*topodatapb.ShardReference
+		for _, sr := range partition.ShardReferences {
+			if sr.Name == shard {
+				changed = true
+				continue
+			}
+			shardReferences = append(shardReferences, sr)
+		}
+		partition.ShardReferences = shardReferences
+	}
+	if !changed {
+		return nil
+	}
+	wr.Logger().Infof("Removing shard %v/%v from SrvKeyspace in cell %v", keyspace, shard, cell)
+	return wr.ts.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace)
+}
+
 // RemoveShardCell will remove a cell from the Cells list in a shard.
 //
 // It will first check the shard has no tablets there. If 'force' is
@@ -361,3 +450,30 @@ func (wr *Wrangler) SourceShardAdd(ctx context.Context, keyspace, shard string,
 	})
 	return err
 }
+
+// ForceUnlockShard breaks the lock currently held on a shard, typically
+// left behind by a crashed process. Unless force is set, it refuses to
+// break a lock that has not outlived its TTL yet, as its holder may still
+// be running.
+func (wr *Wrangler) ForceUnlockShard(ctx context.Context, keyspace, shard string, force bool) error {
+	l, holder, err := wr.ts.GetShardLock(ctx, keyspace, shard)
+	if err != nil {
+		return err
+	}
+	if !l.Expired(time.Now()) {
+		if !force {
+			return fmt.Errorf("lock on shard %v/%v for action %v held by %v@%v (pid %v) since %v has not expired yet (ttl %v), use -force to break it anyway", keyspace, shard, l.Action, l.UserName, l.HostName, l.PID, l.Time, l.TTL)
+		}
+		wr.Logger().Warningf("Breaking non-expired lock on shard %v/%v for action %v held by %v@%v (pid %v) since %v", keyspace, shard, l.Action, l.UserName, l.HostName, l.PID, l.Time)
+	}
+	wr.Logger().Infof("Force-unlocking shard %v/%v, lock was for action %v held by %v@%v (pid %v) since %v", keyspace, shard, l.Action, l.UserName, l.HostName, l.PID, l.Time)
+	// Only break the lock we just checked: if it was released
+	// in the meantime, its new holder keeps it.
+	if err := wr.ts.ForceUnlockShard(ctx, keyspace, shard, holder); err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("lock on shard %v/%v was released or taken by someone else in the meantime, not breaking it", keyspace, shard)
+		}
+		return err
+	}
+	return nil
+}
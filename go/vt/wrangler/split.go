@@ -19,10 +19,12 @@ package wrangler
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/grpcclient"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -125,11 +127,12 @@ func (wr *Wrangler) WaitForFilteredReplication(ctx context.Context, keyspace, sh
 		if lastSeenDelay < 0 {
 			return fmt.Errorf("last seen delay should never be negative. tablet: %v delay: %v", alias, lastSeenDelay)
 		}
+		positions := wr.filteredReplicationPositions(ctx, tabletInfo.Tablet)
 		if lastSeenDelay <= maxDelay {
-			wr.Logger().Printf("Filtered replication on tablet: %v has caught up. Last seen delay: %.1f seconds\n", alias, lastSeenDelay.Seconds())
+			wr.Logger().Printf("Filtered replication on tablet: %v has caught up. Last seen delay: %.1f seconds Positions: %v\n", alias, lastSeenDelay.Seconds(), positions)
 			return io.EOF
 		}
-		wr.Logger().Printf("Waiting for filtered replication to catch up on tablet: %v Last seen delay: %.1f seconds\n", alias, lastSeenDelay.Seconds())
+		wr.Logger().Printf("Waiting for filtered replication to catch up on tablet: %v Last seen delay: %.1f seconds Positions: %v\n", alias, lastSeenDelay.Seconds(), positions)
 		return nil
 	})
 	if err != nil {
@@ -143,3 +146,18 @@ func (wr *Wrangler) WaitForFilteredReplication(ctx context.Context, keyspace, sh
 	}
 	return nil
 }
+
+// filteredReplicationPositions returns the replication position of each
+// filtered replication stream on the tablet e.g. "0: MariaDB/0-1-1083". It is
+// only used for progress messages and therefore never fails.
+func (wr *Wrangler) filteredReplicationPositions(ctx context.Context, tablet *topodatapb.Tablet) string {
+	qr, err := wr.tmc.VReplicationExec(ctx, tablet, "select id, pos from _vt.vreplication")
+	if err != nil {
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	var positions []string
+	for _, row := range sqltypes.Proto3ToResult(qr).Rows {
+		positions = append(positions, fmt.Sprintf("%v: %v", row[0].ToString(), row[1].ToString()))
+	}
+	return strings.Join(positions, ", ")
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	oscCreateShadow = "CREATE TABLE `_t1_new` LIKE `t1`"
+	oscAlter        = "ALTER TABLE `_t1_new` ADD COLUMN c INT"
+	oscDropShadow   = "DROP TABLE IF EXISTS `_t1_new`"
+)
+
+var oscTriggers = []string{
+	"CREATE TRIGGER `_t1_ins` AFTER INSERT ON `t1` FOR EACH ROW REPLACE INTO `_t1_new` (`id`, `msg`) VALUES (NEW.`id`, NEW.`msg`)",
+	"CREATE TRIGGER `_t1_upd` AFTER UPDATE ON `t1` FOR EACH ROW BEGIN DELETE IGNORE FROM `_t1_new` WHERE `id` <=> OLD.`id`; REPLACE INTO `_t1_new` (`id`, `msg`) VALUES (NEW.`id`, NEW.`msg`); END",
+	"CREATE TRIGGER `_t1_del` AFTER DELETE ON `t1` FOR EACH ROW DELETE IGNORE FROM `_t1_new` WHERE `id` <=> OLD.`id`",
+}
+
+// setUpOnlineSchemaChange creates a keyspace with one shard. The schema of
+// its master changes with each GetSchema call the same way as during
+// ApplySchemaOnline. Table t1 has the columns "columns" and the primary key
+// "pkColumns".
+func setUpOnlineSchemaChange(t *testing.T, columns, pkColumns []string) (*VtctlPipe, *fakesqldb.DB, func()) {
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+
+	if err := ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	db := fakesqldb.New(t)
+	master := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, db, TabletKeyspaceShard(t, "ks", "0"))
+	master.StartActionLoop(t, wr)
+
+	table := func(name string, columns []string) *tabletmanagerdatapb.SchemaDefinition {
+		return &tabletmanagerdatapb.SchemaDefinition{
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+				{
+					Name:              name,
+					Columns:           columns,
+					PrimaryKeyColumns: pkColumns,
+					Type:              tmutils.TableBaseTable,
+				},
+			},
+		}
+	}
+	// 1. The original table. 2. No left-overs. 3. The changed shadow table.
+	schemas := []*tabletmanagerdatapb.SchemaDefinition{
+		table("t1", columns),
+		{},
+		table("_t1_new", append(append([]string{}, columns...), "c")),
+	}
+	var mu sync.Mutex
+	master.FakeMysqlDaemon.SchemaFunc = func() (*tabletmanagerdatapb.SchemaDefinition, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(schemas) == 0 {
+			return nil, errors.New("unexpected GetSchema call")
+		}
+		sd := schemas[0]
+		schemas = schemas[1:]
+		return sd, nil
+	}
+
+	db.AddQuery("USE vt_ks", &sqltypes.Result{})
+	db.AddQuery(oscCreateShadow, &sqltypes.Result{})
+	db.AddQuery(oscDropShadow, &sqltypes.Result{})
+
+	return vp, db, func() {
+		master.StopActionLoop(t)
+		db.Close()
+		vp.Close()
+	}
+}
+
+func TestApplySchemaOnline(t *testing.T) {
+	vp, db, cleanUp := setUpOnlineSchemaChange(t, []string{"id", "msg"}, []string{"id"})
+	defer cleanUp()
+
+	idFields := []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}}
+	queries := []struct {
+		query  string
+		result *sqltypes.Result
+	}{
+		{oscAlter, &sqltypes.Result{}},
+		{oscTriggers[0], &sqltypes.Result{}},
+		{oscTriggers[1], &sqltypes.Result{}},
+		{oscTriggers[2], &sqltypes.Result{}},
+		// The first chunk ends at id 2.
+		{"SELECT `id` FROM `t1` ORDER BY `id` LIMIT 1, 1", &sqltypes.Result{
+			Fields: idFields,
+			Rows:   [][]sqltypes.Value{{sqltypes.NewInt64(2)}},
+		}},
+		{"INSERT IGNORE INTO `_t1_new` (`id`, `msg`) SELECT `id`, `msg` FROM `t1` WHERE `id` <= 2 LOCK IN SHARE MODE", &sqltypes.Result{RowsAffected: 2}},
+		// The second chunk is the last one.
+		{"SELECT `id` FROM `t1` WHERE `id` > 2 ORDER BY `id` LIMIT 1, 1", &sqltypes.Result{Fields: idFields}},
+		{"INSERT IGNORE INTO `_t1_new` (`id`, `msg`) SELECT `id`, `msg` FROM `t1` WHERE `id` > 2 LOCK IN SHARE MODE", &sqltypes.Result{RowsAffected: 1}},
+		{"RENAME TABLE `t1` TO `_t1_old`, `_t1_new` TO `t1`", &sqltypes.Result{}},
+		{"DROP TRIGGER IF EXISTS `_t1_ins`", &sqltypes.Result{}},
+		{"DROP TRIGGER IF EXISTS `_t1_upd`", &sqltypes.Result{}},
+		{"DROP TRIGGER IF EXISTS `_t1_del`", &sqltypes.Result{}},
+		{"DROP TABLE IF EXISTS `_t1_old`", &sqltypes.Result{}},
+	}
+	for _, q := range queries {
+		db.AddQuery(q.query, q.result)
+	}
+
+	if err := vp.Run([]string{"ApplySchemaOnline", "-alter", "ADD COLUMN c INT", "-chunk_size", "2", "ks", "t1"}); err != nil {
+		t.Fatalf("ApplySchemaOnline failed: %v", err)
+	}
+
+	if count := db.GetQueryCalledNum(oscCreateShadow); count != 1 {
+		t.Errorf("the shadow table was created %v times, want once", count)
+	}
+	for _, q := range queries {
+		if count := db.GetQueryCalledNum(q.query); count != 1 {
+			t.Errorf("query %v was executed %v times, want once", q.query, count)
+		}
+	}
+	if count := db.GetQueryCalledNum(oscDropShadow); count != 0 {
+		t.Errorf("the shadow table must not be dropped after a successful change")
+	}
+}
+
+func TestApplySchemaOnlineRollback(t *testing.T) {
+	vp, db, cleanUp := setUpOnlineSchemaChange(t, []string{"id", "msg"}, []string{"id"})
+	defer cleanUp()
+
+	db.AddRejectedQuery(oscAlter, errors.New("syntax error"))
+
+	err := vp.Run([]string{"ApplySchemaOnline", "-alter", "ADD COLUMN c INT", "ks", "t1"})
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("ApplySchemaOnline with an invalid change must fail and roll back, got: %v", err)
+	}
+	if count := db.GetQueryCalledNum(oscDropShadow); count != 1 {
+		t.Errorf("the shadow table was dropped %v times, want once", count)
+	}
+	for _, trigger := range oscTriggers {
+		if count := db.GetQueryCalledNum(trigger); count != 0 {
+			t.Errorf("no trigger must be created if the change of the shadow table fails: %v", trigger)
+		}
+	}
+}
+
+func TestApplySchemaOnlineCompositePrimaryKey(t *testing.T) {
+	vp, db, cleanUp := setUpOnlineSchemaChange(t, []string{"id", "seq", "msg"}, []string{"id", "seq"})
+	defer cleanUp()
+
+	pkFields := []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}, {Name: "seq", Type: querypb.Type_INT64}}
+	replace := "REPLACE INTO `_t1_new` (`id`, `seq`, `msg`) VALUES (NEW.`id`, NEW.`seq`, NEW.`msg`)"
+	deleteOld := "DELETE IGNORE FROM `_t1_new` WHERE `id` <=> OLD.`id` AND `seq` <=> OLD.`seq`"
+	queries := []struct {
+		query  string
+		result *sqltypes.Result
+	}{
+		{oscAlter, &sqltypes.Result{}},
+		{"CREATE TRIGGER `_t1_ins` AFTER INSERT ON `t1` FOR EACH ROW " + replace, &sqltypes.Result{}},
+		{"CREATE TRIGGER `_t1_upd` AFTER UPDATE ON `t1` FOR EACH ROW BEGIN " + deleteOld + "; " + replace + "; END", &sqltypes.Result{}},
+		{"CREATE TRIGGER `_t1_del` AFTER DELETE ON `t1` FOR EACH ROW " + deleteOld, &sqltypes.Result{}},
+		// The first chunk ends in the middle of the rows with id 1.
+		// The second chunk must continue with the next row of id 1.
+		{"SELECT `id`, `seq` FROM `t1` ORDER BY `id`, `seq` LIMIT 1, 1", &sqltypes.Result{
+			Fields: pkFields,
+			Rows:   [][]sqltypes.Value{{sqltypes.NewInt64(1), sqltypes.NewInt64(2)}},
+		}},
+		{"INSERT IGNORE INTO `_t1_new` (`id`, `seq`, `msg`) SELECT `id`, `seq`, `msg` FROM `t1` WHERE (`id`, `seq`) <= (1, 2) LOCK IN SHARE MODE", &sqltypes.Result{RowsAffected: 2}},
+		{"SELECT `id`, `seq` FROM `t1` WHERE (`id`, `seq`) > (1, 2) ORDER BY `id`, `seq` LIMIT 1, 1", &sqltypes.Result{Fields: pkFields}},
+		{"INSERT IGNORE INTO `_t1_new` (`id`, `seq`, `msg`) SELECT `id`, `seq`, `msg` FROM `t1` WHERE (`id`, `seq`) > (1, 2) LOCK IN SHARE MODE", &sqltypes.Result{RowsAffected: 1}},
+		{"RENAME TABLE `t1` TO `_t1_old`, `_t1_new` TO `t1`", &sqltypes.Result{}},
+		{"DROP TRIGGER IF EXISTS `_t1_ins`", &sqltypes.Result{}},
+		{"DROP TRIGGER IF EXISTS `_t1_upd`", &sqltypes.Result{}},
+		{"DROP TRIGGER IF EXISTS `_t1_del`", &sqltypes.Result{}},
+	}
+	for _, q := range queries {
+		db.AddQuery(q.query, q.result)
+	}
+
+	if err := vp.Run([]string{"ApplySchemaOnline", "-alter", "ADD COLUMN c INT", "-chunk_size", "2", "-keep_old_table", "ks", "t1"}); err != nil {
+		t.Fatalf("ApplySchemaOnline failed: %v", err)
+	}
+	for _, q := range queries {
+		if count := db.GetQueryCalledNum(q.query); count != 1 {
+			t.Errorf("query %v was executed %v times, want once", q.query, count)
+		}
+	}
+}
+
+func TestApplySchemaOnlineCutOverRetry(t *testing.T) {
+	vp, db, cleanUp := setUpOnlineSchemaChange(t, []string{"id", "msg"}, []string{"id"})
+	defer cleanUp()
+
+	rename := "RENAME TABLE `t1` TO `_t1_old`, `_t1_new` TO `t1`"
+	for _, q := range append([]string{oscAlter}, oscTriggers...) {
+		db.AddQuery(q, &sqltypes.Result{})
+	}
+	db.AddQuery("SELECT `id` FROM `t1` ORDER BY `id` LIMIT 999, 1", &sqltypes.Result{})
+	db.AddQuery("INSERT IGNORE INTO `_t1_new` (`id`, `msg`) SELECT `id`, `msg` FROM `t1` LOCK IN SHARE MODE", &sqltypes.Result{})
+	for _, trigger := range []string{"_t1_ins", "_t1_upd", "_t1_del"} {
+		db.AddQuery("DROP TRIGGER IF EXISTS `"+trigger+"`", &sqltypes.Result{})
+	}
+	db.AddRejectedQuery(rename, errors.New("lock wait timeout exceeded"))
+
+	// The swap fails on the only shard. Therefore, the change can still be
+	// rolled back after the last attempt.
+	err := vp.Run([]string{"ApplySchemaOnline", "-alter", "ADD COLUMN c INT", "ks", "t1"})
+	if err == nil || !strings.Contains(err.Error(), "during the cut-over and was rolled back") {
+		t.Fatalf("ApplySchemaOnline with a failing cut-over must fail and roll back, got: %v", err)
+	}
+	if count := db.GetQueryCalledNum(rename); count != 3 {
+		t.Errorf("the cut-over was tried %v times, want 3 times", count)
+	}
+	if count := db.GetQueryCalledNum(oscDropShadow); count != 1 {
+		t.Errorf("the shadow table was dropped %v times, want once", count)
+	}
+}
@@ -181,3 +181,95 @@ func copySchema(t *testing.T, useShardAsSource bool) {
 		t.Errorf("CopySchemaShard did not create the table view exactly once. Query count: %v", count)
 	}
 }
+
+func TestCopySchemaShard_IncludeTriggers(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	if err := ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+
+	sourceMasterDb := fakesqldb.New(t).SetName("sourceMasterDb")
+	defer sourceMasterDb.Close()
+	sourceMaster := NewFakeTablet(t, wr, "cell1", 0,
+		topodatapb.TabletType_MASTER, sourceMasterDb, TabletKeyspaceShard(t, "ks", "0"))
+
+	destinationMasterDb := fakesqldb.New(t).SetName("destinationMasterDb")
+	defer destinationMasterDb.Close()
+	destinationMaster := NewFakeTablet(t, wr, "cell1", 10,
+		topodatapb.TabletType_MASTER, destinationMasterDb, TabletKeyspaceShard(t, "ks", "-80"))
+
+	destinationRdonlyDb := fakesqldb.New(t).SetName("destinationRdonlyDb")
+	defer destinationRdonlyDb.Close()
+	destinationRdonly := NewFakeTablet(t, wr, "cell1", 11,
+		topodatapb.TabletType_RDONLY, destinationRdonlyDb, TabletKeyspaceShard(t, "ks", "-80"))
+
+	for _, ft := range []*FakeTablet{sourceMaster, destinationMaster, destinationRdonly} {
+		ft.StartActionLoop(t, wr)
+		defer ft.StopActionLoop(t)
+	}
+
+	schema := &tabletmanagerdatapb.SchemaDefinition{
+		DatabaseSchema: "CREATE DATABASE `{{.DatabaseName}}` /*!40100 DEFAULT CHARACTER SET utf8 */",
+		TableDefinitions: []*tabletmanagerdatapb.TableDefinition{
+			{
+				Name:   "table1",
+				Schema: "CREATE TABLE `table1` (\n  `id` bigint(20) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8",
+				Type:   tmutils.TableBaseTable,
+			},
+		},
+	}
+	sourceMaster.FakeMysqlDaemon.Schema = schema
+
+	changeToDb := "USE vt_ks"
+	createDb := "CREATE DATABASE `vt_ks` /*!40100 DEFAULT CHARACTER SET utf8 */"
+	createTable := "CREATE TABLE `vt_ks`.`table1` (\n  `id` bigint(20) NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8"
+	selectTriggers := "SELECT trigger_name, event_object_table FROM information_schema.triggers WHERE trigger_schema = 'vt_ks'"
+	createTrigger := "CREATE DEFINER=`root`@`localhost` TRIGGER trigger1 BEFORE INSERT ON table1 FOR EACH ROW SET NEW.id = NEW.id"
+
+	sourceMasterDb.AddQuery(changeToDb, &sqltypes.Result{})
+	sourceMasterDb.AddQuery("SELECT 1 FROM information_schema.tables WHERE table_schema = '_vt' AND table_name = 'shard_metadata'", &sqltypes.Result{})
+	sourceMasterDb.AddQuery(selectTriggers, &sqltypes.Result{
+		Fields: sqltypes.MakeTestFields("trigger_name|event_object_table", "varchar|varchar"),
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewVarChar("trigger1"), sqltypes.NewVarChar("table1")},
+			// The table of this trigger is not copied.
+			{sqltypes.NewVarChar("trigger2"), sqltypes.NewVarChar("table2")},
+		},
+	})
+	sourceMasterDb.AddQuery("SHOW CREATE TRIGGER `trigger1`", &sqltypes.Result{
+		Fields: sqltypes.MakeTestFields("Trigger|sql_mode|SQL Original Statement", "varchar|varchar|varchar"),
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewVarChar("trigger1"), sqltypes.NewVarChar(""), sqltypes.NewVarChar(createTrigger)},
+		},
+	})
+
+	destinationMasterDb.AddQuery(changeToDb, &sqltypes.Result{})
+	destinationMasterDb.AddQuery(createDb, &sqltypes.Result{})
+	destinationMasterDb.AddQuery(createTable, &sqltypes.Result{})
+	destinationMasterDb.AddQuery(selectTriggers, &sqltypes.Result{})
+	destinationMasterDb.AddQuery(createTrigger, &sqltypes.Result{})
+	destinationMaster.FakeMysqlDaemon.SchemaFunc = func() (*tabletmanagerdatapb.SchemaDefinition, error) {
+		if destinationMasterDb.GetQueryCalledNum(createTable) == 1 {
+			return schema, nil
+		}
+		return &tabletmanagerdatapb.SchemaDefinition{DatabaseSchema: schema.DatabaseSchema}, nil
+	}
+
+	if err := vp.Run([]string{"CopySchemaShard", "-include-triggers", "-wait_replicas_timeout", "10s", "ks/0", "ks/-80"}); err != nil {
+		t.Fatalf("CopySchemaShard failed: %v", err)
+	}
+
+	if count := destinationMasterDb.GetQueryCalledNum(createTable); count != 1 {
+		t.Errorf("CopySchemaShard did not create the table exactly once. Query count: %v", count)
+	}
+	if count := destinationMasterDb.GetQueryCalledNum(createTrigger); count != 1 {
+		t.Errorf("CopySchemaShard did not create the trigger exactly once. Query count: %v", count)
+	}
+	if count := sourceMasterDb.GetQueryCalledNum("SHOW CREATE TRIGGER `trigger2`"); count != 0 {
+		t.Errorf("CopySchemaShard must only copy the triggers of the copied tables")
+	}
+}
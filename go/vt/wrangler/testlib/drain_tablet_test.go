@@ -0,0 +1,96 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestDrainTablet(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	master := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, nil)
+	rdonly := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_RDONLY, nil)
+	rdonly.FakeMysqlDaemon.Replicating = true
+	rdonly.FakeMysqlDaemon.SecondsBehindMaster = 5
+	rdonly.StartActionLoop(t, wr)
+	defer rdonly.StopActionLoop(t)
+
+	checkTablet := func(wantType topodatapb.TabletType, wantTag string) {
+		t.Helper()
+		ti, err := ts.GetTablet(ctx, rdonly.Tablet.Alias)
+		if err != nil {
+			t.Fatalf("GetTablet failed: %v", err)
+		}
+		if ti.Type != wantType {
+			t.Errorf("tablet has type %v, want %v", ti.Type, wantType)
+		}
+		if got := ti.Tags[wrangler.DrainedFromTag]; got != wantTag {
+			t.Errorf("tablet has tag %v=%q, want %q", wrangler.DrainedFromTag, got, wantTag)
+		}
+	}
+
+	// A master cannot be drained.
+	if err := vp.Run([]string{"DrainTablet", topoproto.TabletAliasString(master.Tablet.Alias)}); err == nil || !strings.Contains(err.Error(), "only REPLICA and RDONLY tablets can be drained") {
+		t.Errorf("DrainTablet on a master must fail: %v", err)
+	}
+
+	if err := vp.Run([]string{"DrainTablet", "-max_replication_lag", "10s", topoproto.TabletAliasString(rdonly.Tablet.Alias)}); err != nil {
+		t.Fatalf("DrainTablet failed: %v", err)
+	}
+	checkTablet(topodatapb.TabletType_DRAINED, "RDONLY")
+
+	if err := vp.Run([]string{"DrainTablet", topoproto.TabletAliasString(rdonly.Tablet.Alias)}); err == nil || !strings.Contains(err.Error(), "already drained") {
+		t.Errorf("DrainTablet on a drained tablet must fail: %v", err)
+	}
+
+	if err := vp.Run([]string{"UndrainTablet", "-max_replication_lag", "10s", topoproto.TabletAliasString(rdonly.Tablet.Alias)}); err != nil {
+		t.Fatalf("UndrainTablet failed: %v", err)
+	}
+	checkTablet(topodatapb.TabletType_RDONLY, "")
+
+	if err := vp.Run([]string{"UndrainTablet", topoproto.TabletAliasString(rdonly.Tablet.Alias)}); err == nil || !strings.Contains(err.Error(), "not drained") {
+		t.Errorf("UndrainTablet on a serving tablet must fail: %v", err)
+	}
+
+	// A tablet which was drained by someone else requires the type.
+	if err := vp.Run([]string{"ChangeSlaveType", topoproto.TabletAliasString(rdonly.Tablet.Alias), "drained"}); err != nil {
+		t.Fatalf("ChangeSlaveType failed: %v", err)
+	}
+	if err := vp.Run([]string{"UndrainTablet", topoproto.TabletAliasString(rdonly.Tablet.Alias)}); err == nil || !strings.Contains(err.Error(), "specify the tablet type") {
+		t.Errorf("UndrainTablet without the tag and the type must fail: %v", err)
+	}
+	if err := vp.Run([]string{"UndrainTablet", topoproto.TabletAliasString(rdonly.Tablet.Alias), "replica"}); err != nil {
+		t.Fatalf("UndrainTablet failed: %v", err)
+	}
+	checkTablet(topodatapb.TabletType_REPLICA, "")
+}
@@ -209,7 +209,7 @@ func TestEmergencyReparentShardMasterElectNotBest(t *testing.T) {
 	defer moreAdvancedSlave.StopActionLoop(t)
 
 	// run EmergencyReparentShard
-	if err := wr.EmergencyReparentShard(ctx, newMaster.Tablet.Keyspace, newMaster.Tablet.Shard, newMaster.Tablet.Alias, 10*time.Second); err == nil || !strings.Contains(err.Error(), "is more advanced than master elect tablet") {
+	if err := wr.EmergencyReparentShard(ctx, newMaster.Tablet.Keyspace, newMaster.Tablet.Shard, newMaster.Tablet.Alias, 10*time.Second, false /* backfillFromMostAdvanced */); err == nil || !strings.Contains(err.Error(), "is more advanced than master elect tablet") {
 		t.Fatalf("EmergencyReparentShard returned the wrong error: %v", err)
 	}
 
@@ -224,3 +224,105 @@ func TestEmergencyReparentShardMasterElectNotBest(t *testing.T) {
 		t.Fatalf("moreAdvancedSlave.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
 	}
 }
+
+// TestEmergencyReparentShardBackfill reparents to a host that is not the
+// latest in replication position. The new master first replicates the
+// missing transactions from the most advanced slave.
+func TestEmergencyReparentShardBackfill(t *testing.T) {
+	ts := memorytopo.NewServer("cell1", "cell2")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	oldMaster := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, nil)
+	newMaster := NewFakeTablet(t, wr, "cell1", 1, topodatapb.TabletType_REPLICA, nil)
+	moreAdvancedSlave := NewFakeTablet(t, wr, "cell1", 2, topodatapb.TabletType_REPLICA, nil)
+
+	newMasterPos := mysql.Position{
+		GTIDSet: mysql.MariadbGTIDSet{
+			mysql.MariadbGTID{
+				Domain:   2,
+				Server:   123,
+				Sequence: 456,
+			},
+		},
+	}
+	moreAdvancedPos := mysql.Position{
+		GTIDSet: mysql.MariadbGTIDSet{
+			mysql.MariadbGTID{
+				Domain:   2,
+				Server:   123,
+				Sequence: 457,
+			},
+		},
+	}
+
+	// new master, replicates from the more advanced slave before the
+	// promotion
+	newMaster.FakeMysqlDaemon.ReadOnly = true
+	newMaster.FakeMysqlDaemon.Replicating = true
+	newMaster.FakeMysqlDaemon.CurrentMasterPosition = newMasterPos
+	newMaster.FakeMysqlDaemon.WaitMasterPosition = moreAdvancedPos
+	newMaster.FakeMysqlDaemon.PromoteSlaveResult = moreAdvancedPos
+	newMaster.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		// StopReplicationAndGetStatus
+		"STOP SLAVE",
+		// SetMaster to the more advanced slave
+		"FAKE SET MASTER",
+		"START SLAVE",
+		// StopSlaveMinimum
+		"STOP SLAVE",
+		"CREATE DATABASE IF NOT EXISTS _vt",
+		"SUBCREATE TABLE IF NOT EXISTS _vt.reparent_journal",
+		"SUBINSERT INTO _vt.reparent_journal (time_created_ns, action_name, master_alias, replication_position) VALUES",
+	}
+	newMaster.StartActionLoop(t, wr)
+	defer newMaster.StopActionLoop(t)
+
+	// old master, will be scrapped
+	oldMaster.StartActionLoop(t, wr)
+	defer oldMaster.StopActionLoop(t)
+
+	// more advanced slave
+	moreAdvancedSlave.FakeMysqlDaemon.ReadOnly = true
+	moreAdvancedSlave.FakeMysqlDaemon.Replicating = true
+	moreAdvancedSlave.FakeMysqlDaemon.CurrentMasterPosition = moreAdvancedPos
+	moreAdvancedSlave.FakeMysqlDaemon.SetMasterInput = topoproto.MysqlAddr(newMaster.Tablet)
+	moreAdvancedSlave.FakeMysqlDaemon.ExpectedExecuteSuperQueryList = []string{
+		"STOP SLAVE",
+		"STOP SLAVE",
+		"FAKE SET MASTER",
+		"START SLAVE",
+	}
+	moreAdvancedSlave.StartActionLoop(t, wr)
+	defer moreAdvancedSlave.StopActionLoop(t)
+	// The address of the more advanced slave is only known once its action
+	// loop runs.
+	newMaster.FakeMysqlDaemon.SetMasterInput = topoproto.MysqlAddr(moreAdvancedSlave.Tablet)
+
+	// run EmergencyReparentShard
+	if err := vp.Run([]string{"EmergencyReparentShard", "-wait_slave_timeout", "10s", "-backfill_from_most_advanced", "-keyspace_shard", newMaster.Tablet.Keyspace + "/" + newMaster.Tablet.Shard, "-new_master", topoproto.TabletAliasString(newMaster.Tablet.Alias)}); err != nil {
+		t.Fatalf("EmergencyReparentShard failed: %v", err)
+	}
+
+	// check what was run
+	if err := newMaster.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+		t.Fatalf("newMaster.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+	}
+	if err := oldMaster.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+		t.Fatalf("oldMaster.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+	}
+	if err := moreAdvancedSlave.FakeMysqlDaemon.CheckSuperQueryList(); err != nil {
+		t.Fatalf("moreAdvancedSlave.FakeMysqlDaemon.CheckSuperQueryList failed: %v", err)
+	}
+	if newMaster.FakeMysqlDaemon.ReadOnly {
+		t.Errorf("newMaster.FakeMysqlDaemon.ReadOnly set")
+	}
+	si, err := ts.GetShard(context.Background(), newMaster.Tablet.Keyspace, newMaster.Tablet.Shard)
+	if err != nil {
+		t.Fatalf("GetShard failed: %v", err)
+	}
+	if !topoproto.TabletAliasEqual(si.MasterAlias, newMaster.Tablet.Alias) {
+		t.Errorf("shard has master %v, want %v", topoproto.TabletAliasString(si.MasterAlias), topoproto.TabletAliasString(newMaster.Tablet.Alias))
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const testHook = `#!/bin/sh
+echo "hello $1"
+if [ "$TABLET_ALIAS" = "cell1-0000000002" ]; then
+  echo "broken" >&2
+  exit 3
+fi
+`
+
+func TestExecuteHookOnTablets(t *testing.T) {
+	// The fake tablets run the hooks of $VTROOT/vthook.
+	root, err := ioutil.TempDir("", "vthook")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.Mkdir(path.Join(root, "vthook"), 0755); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+	if err := ioutil.WriteFile(path.Join(root, "vthook", "test_hook.sh"), []byte(testHook), 0755); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	defer os.Setenv("VTROOT", os.Getenv("VTROOT"))
+	os.Setenv("VTROOT", root)
+
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	if err := ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	for _, tc := range []struct {
+		uid        uint32
+		tabletType topodatapb.TabletType
+		shard      string
+	}{
+		{0, topodatapb.TabletType_MASTER, "-80"},
+		{1, topodatapb.TabletType_REPLICA, "-80"},
+		{2, topodatapb.TabletType_REPLICA, "80-"},
+		{3, topodatapb.TabletType_MASTER, "80-"},
+	} {
+		ft := NewFakeTablet(t, wr, "cell1", tc.uid, tc.tabletType, nil, TabletKeyspaceShard(t, "ks", tc.shard))
+		ft.StartActionLoop(t, wr)
+		defer ft.StopActionLoop(t)
+	}
+
+	// The hook fails on one of the two replicas.
+	output, err := vp.RunAndOutput([]string{"ExecuteHookOnTablets", "-tablet_types", "replica", "ks", "test_hook.sh", "world"})
+	if err == nil || !strings.Contains(err.Error(), "hook test_hook.sh failed on 1 of 2 tablets: cell1-0000000002 (exit status 3)") {
+		t.Errorf("ExecuteHookOnTablets must report the failed tablet: %v", err)
+	}
+	for _, want := range []string{
+		"cell1-0000000001 stdout: hello world",
+		"cell1-0000000002 stdout: hello world",
+		"cell1-0000000002 stderr: broken",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ExecuteHookOnTablets output does not contain %q:\n%v", want, output)
+		}
+	}
+	if strings.Contains(output, "cell1-0000000000") || strings.Contains(output, "cell1-0000000003") {
+		t.Errorf("ExecuteHookOnTablets must not run the hook on the masters:\n%v", output)
+	}
+
+	// Restricted to shard -80, the hook succeeds.
+	output, err = vp.RunAndOutput([]string{"ExecuteHookOnTablets", "-shards", "-80", "-tablet_types", "replica", "ks", "test_hook.sh", "world"})
+	if err != nil {
+		t.Fatalf("ExecuteHookOnTablets failed: %v", err)
+	}
+	if !strings.Contains(output, "hook test_hook.sh succeeded on 1 tablets") {
+		t.Errorf("ExecuteHookOnTablets output is missing the summary:\n%v", output)
+	}
+
+	if err := vp.Run([]string{"ExecuteHookOnTablets", "-tablet_types", "spare", "ks", "test_hook.sh"}); err == nil || !strings.Contains(err.Error(), "no tablets in keyspace ks") {
+		t.Errorf("ExecuteHookOnTablets without matching tablets must fail: %v", err)
+	}
+}
@@ -18,6 +18,7 @@ package testlib
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -183,6 +184,12 @@ func TestMigrateServedFrom(t *testing.T) {
 		t.Fatalf("replica type doesn't have right blacklisted tables")
 	}
 
+	// the master cannot be migrated back once it was migrated, so
+	// -reverse is rejected for it
+	if err := vp.Run([]string{"MigrateServedFrom", "-reverse", "dest/0", "master"}); err == nil || !strings.Contains(err.Error(), "MigrateServedFrom -reverse is not supported for the master") {
+		t.Fatalf("MigrateServedFrom(master, reverse) must fail: %v", err)
+	}
+
 	// migrate master over
 	if err := vp.Run([]string{"MigrateServedFrom", "dest/0", "master"}); err != nil {
 		t.Fatalf("MigrateServedFrom(master) failed: %v", err)
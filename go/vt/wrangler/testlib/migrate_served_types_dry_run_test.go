@@ -0,0 +1,101 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestMigrateServedTypesDryRun(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	if err := ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{
+		ShardingColumnName: "keyspace_id",
+		ShardingColumnType: topodatapb.KeyspaceIdType_UINT64,
+	}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+
+	// The dry run does not contact any tablet. Therefore, no action loop
+	// is started.
+	NewFakeTablet(t, wr, "cell1", 10, topodatapb.TabletType_MASTER, nil, TabletKeyspaceShard(t, "ks", "0"))
+	NewFakeTablet(t, wr, "cell1", 12, topodatapb.TabletType_RDONLY, nil, TabletKeyspaceShard(t, "ks", "0"))
+	NewFakeTablet(t, wr, "cell1", 20, topodatapb.TabletType_MASTER, nil, TabletKeyspaceShard(t, "ks", "-80"))
+	NewFakeTablet(t, wr, "cell1", 22, topodatapb.TabletType_RDONLY, nil, TabletKeyspaceShard(t, "ks", "-80"))
+	NewFakeTablet(t, wr, "cell1", 30, topodatapb.TabletType_MASTER, nil, TabletKeyspaceShard(t, "ks", "80-"))
+	NewFakeTablet(t, wr, "cell1", 32, topodatapb.TabletType_RDONLY, nil, TabletKeyspaceShard(t, "ks", "80-"))
+	for _, shard := range []string{"ks/-80", "ks/80-"} {
+		if err := vp.Run([]string{"SourceShardAdd", "--key_range=-", shard, "1", "ks/0"}); err != nil {
+			t.Fatalf("SourceShardAdd failed: %v", err)
+		}
+	}
+
+	// The dry run does not take the keyspace lock. It succeeds while
+	// another action holds it.
+	_, unlock, err := ts.LockKeyspace(context.Background(), "ks", "another action")
+	if err != nil {
+		t.Fatalf("LockKeyspace failed: %v", err)
+	}
+	output, err := vp.RunAndOutput([]string{"MigrateServedTypes", "-dry_run", "ks/0", "rdonly"})
+	if err != nil {
+		t.Fatalf("MigrateServedTypes -dry_run failed: %v", err)
+	}
+	unlock(&err)
+	if err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	for _, want := range []string{
+		"Shard ks/0 would be rewritten:",
+		"Shard ks/-80 would be rewritten:",
+		"SrvKeyspace ks in cell cell1 would be rewritten:",
+		"RDONLY: [-80 80-]",
+		"Tablet cell1-0000000012 (RDONLY) of shard ks/0 would be refreshed",
+		"Tablet cell1-0000000022 (RDONLY) of shard ks/-80 would be refreshed",
+		"Tablet cell1-0000000032 (RDONLY) of shard ks/80- would be refreshed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("MigrateServedTypes -dry_run output does not contain %q:\n%v", want, output)
+		}
+	}
+	if strings.Contains(output, "cell1-0000000010") {
+		t.Errorf("MigrateServedTypes -dry_run must not refresh the master for a rdonly migration:\n%v", output)
+	}
+
+	// Nothing was changed.
+	checkShardServedTypes(t, ts, "0", 3)
+	checkShardServedTypes(t, ts, "-80", 0)
+	checkShardServedTypes(t, ts, "80-", 0)
+	checkShardSourceShards(t, ts, "-80", 1)
+
+	// The master cannot be migrated before the other types.
+	if err := vp.Run([]string{"MigrateServedTypes", "-dry_run", "ks/0", "master"}); err == nil || !strings.Contains(err.Error(), "cannot migrate MASTER away") {
+		t.Errorf("MigrateServedTypes -dry_run master must fail while the source still serves other types: %v", err)
+	}
+}
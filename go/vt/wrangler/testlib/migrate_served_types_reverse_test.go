@@ -0,0 +1,149 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestMigrateServedTypesReverseMaster(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{
+		ShardingColumnName: "keyspace_id",
+		ShardingColumnType: topodatapb.KeyspaceIdType_UINT64,
+	}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+
+	// Only the dry run and the safety checks are exercised. They do not
+	// contact any tablet. Therefore, no action loop is started.
+	NewFakeTablet(t, wr, "cell1", 10, topodatapb.TabletType_MASTER, nil, TabletKeyspaceShard(t, "ks", "0"))
+	NewFakeTablet(t, wr, "cell1", 20, topodatapb.TabletType_MASTER, nil, TabletKeyspaceShard(t, "ks", "-80"))
+	NewFakeTablet(t, wr, "cell1", 30, topodatapb.TabletType_MASTER, nil, TabletKeyspaceShard(t, "ks", "80-"))
+
+	// The state after the master was migrated from ks/0 to ks/-80 and ks/80-
+	// without starting the reverse replication.
+	if _, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.ServedTypes = nil
+		si.TabletControls = []*topodatapb.Shard_TabletControl{{
+			TabletType:          topodatapb.TabletType_MASTER,
+			DisableQueryService: true,
+			Frozen:              true,
+		}}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardFields failed: %v", err)
+	}
+	for _, shard := range []string{"-80", "80-"} {
+		if _, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.ServedTypes = []*topodatapb.Shard_ServedType{
+				{TabletType: topodatapb.TabletType_MASTER},
+				{TabletType: topodatapb.TabletType_REPLICA},
+				{TabletType: topodatapb.TabletType_RDONLY},
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateShardFields failed: %v", err)
+		}
+	}
+	for i, shard := range []string{"-80", "80-"} {
+		if err := vp.Run([]string{"SourceShardAdd", "--key_range=" + shard, "ks/0", strconv.Itoa(i + 1), "ks/" + shard}); err != nil {
+			t.Fatalf("SourceShardAdd failed: %v", err)
+		}
+	}
+	checkShardSourceShards(t, ts, "0", 2)
+
+	// Migrating rdonly back would serve stale data.
+	if err := vp.Run([]string{"MigrateServedTypes", "-reverse", "ks/-80", "rdonly"}); err == nil || !strings.Contains(err.Error(), "MigrateServedTypes -reverse ks/-80 master' first") {
+		t.Errorf("MigrateServedTypes -reverse rdonly must fail while the source is frozen: %v", err)
+	}
+
+	// The master cannot be migrated back while ks/-80 and ks/80- serve
+	// the other types.
+	output, err := vp.RunAndOutput([]string{"MigrateServedTypes", "-dry_run", "-reverse", "ks/-80", "master"})
+	if err == nil || !strings.Contains(err.Error(), "cannot migrate MASTER away from ks/-80") {
+		t.Errorf("MigrateServedTypes -dry_run -reverse master must fail while the other types are not migrated: %v", err)
+	}
+	if want := "The reverse replication to ks/0 would be started"; !strings.Contains(output, want) {
+		t.Errorf("MigrateServedTypes -dry_run -reverse master output does not contain %q:\n%v", want, output)
+	}
+
+	// The state after the reverse replication was started and rdonly and
+	// replica were migrated to ks/0.
+	if _, err := ts.UpdateShardFields(ctx, "ks", "0", func(si *topo.ShardInfo) error {
+		si.ServedTypes = []*topodatapb.Shard_ServedType{
+			{TabletType: topodatapb.TabletType_RDONLY},
+			{TabletType: topodatapb.TabletType_REPLICA},
+		}
+		si.GetTabletControl(topodatapb.TabletType_MASTER).Frozen = false
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardFields failed: %v", err)
+	}
+	for _, shard := range []string{"-80", "80-"} {
+		if _, err := ts.UpdateShardFields(ctx, "ks", shard, func(si *topo.ShardInfo) error {
+			si.ServedTypes = []*topodatapb.Shard_ServedType{{TabletType: topodatapb.TabletType_MASTER}}
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateShardFields failed: %v", err)
+		}
+	}
+
+	output, err = vp.RunAndOutput([]string{"MigrateServedTypes", "-dry_run", "-reverse", "ks/-80", "master"})
+	if err != nil {
+		t.Fatalf("MigrateServedTypes -dry_run -reverse master failed: %v", err)
+	}
+	for _, want := range []string{
+		"Shard ks/0 would be rewritten:",
+		"Shard ks/-80 would be rewritten:",
+		"Reverse replication streams would be created on the master of ks/-80",
+		"Reverse replication streams would be created on the master of ks/80-",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("MigrateServedTypes -dry_run -reverse master output does not contain %q:\n%v", want, output)
+		}
+	}
+	// Nothing was changed.
+	checkShardServedTypes(t, ts, "0", 2)
+	checkShardServedTypes(t, ts, "-80", 1)
+
+	// Without a reverse replication stream, the master cannot be migrated
+	// back.
+	if err := vp.Run([]string{"SourceShardDelete", "ks/0", "2"}); err != nil {
+		t.Fatalf("SourceShardDelete failed: %v", err)
+	}
+	if err := vp.Run([]string{"MigrateServedTypes", "-reverse", "ks/-80", "master"}); err == nil || !strings.Contains(err.Error(), "has no reverse replication from ks/80-") {
+		t.Errorf("MigrateServedTypes -reverse master must fail without a reverse replication stream: %v", err)
+	}
+}
@@ -90,3 +90,95 @@ func TestDeleteShardCleanup(t *testing.T) {
 		t.Errorf("shard %v/%v is still in topo: %v", master.Tablet.Keyspace, master.Tablet.Shard, err)
 	}
 }
+
+func TestDeleteShardServingTablets(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1", "cell2")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	master := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, nil)
+	spare := NewFakeTablet(t, wr, "cell2", 1, topodatapb.TabletType_SPARE, nil)
+	keyspace := master.Tablet.Keyspace
+	shard := master.Tablet.Shard
+
+	// The shard is not serving any more, but its master still is.
+	if _, err := ts.UpdateShardFields(ctx, keyspace, shard, func(si *topo.ShardInfo) error {
+		si.ServedTypes = nil
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardFields failed: %v", err)
+	}
+
+	// Both cells still reference the shard in their SrvKeyspace.
+	for _, cell := range []string{"cell1", "cell2"} {
+		if err := ts.UpdateSrvKeyspace(ctx, cell, keyspace, &topodatapb.SrvKeyspace{
+			Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{
+				{
+					ServedType: topodatapb.TabletType_MASTER,
+					ShardReferences: []*topodatapb.ShardReference{
+						{Name: shard},
+						{Name: "other"},
+					},
+				},
+			},
+		}); err != nil {
+			t.Fatalf("UpdateSrvKeyspace(%v) failed: %v", cell, err)
+		}
+	}
+
+	// Recursive delete should fail because of the serving master,
+	// and not delete anything.
+	if err := vp.Run([]string{
+		"DeleteShard",
+		"-recursive",
+		keyspace + "/" + shard,
+	}); err == nil || !strings.Contains(err.Error(), "still has serving tablet") {
+		t.Fatalf("DeleteShard(recursive=true) returned wrong error: %v", err)
+	}
+	for _, ft := range []*FakeTablet{master, spare} {
+		if _, err := ts.GetTablet(ctx, ft.Tablet.Alias); err != nil {
+			t.Errorf("tablet %v should still be in topo: %v", ft.Tablet.Alias, err)
+		}
+	}
+
+	// Same for a recursive keyspace delete.
+	if err := vp.Run([]string{
+		"DeleteKeyspace",
+		"-recursive",
+		keyspace,
+	}); err == nil || !strings.Contains(err.Error(), "still has serving tablet") {
+		t.Fatalf("DeleteKeyspace(recursive=true) returned wrong error: %v", err)
+	}
+	if _, err := ts.GetShard(ctx, keyspace, shard); err != nil {
+		t.Errorf("shard %v/%v should still be in topo: %v", keyspace, shard, err)
+	}
+
+	// With even_if_serving, it should just work.
+	if err := vp.Run([]string{
+		"DeleteShard",
+		"-recursive",
+		"-even_if_serving",
+		keyspace + "/" + shard,
+	}); err != nil {
+		t.Fatalf("DeleteShard(recursive=true, evenIfServing=true) should have worked but returned: %v", err)
+	}
+	for _, ft := range []*FakeTablet{master, spare} {
+		if _, err := ts.GetTablet(ctx, ft.Tablet.Alias); !topo.IsErrType(err, topo.NoNode) {
+			t.Errorf("tablet %v is still in topo: %v", ft.Tablet.Alias, err)
+		}
+	}
+
+	// And the shard is gone from the SrvKeyspace in all cells.
+	for _, cell := range []string{"cell1", "cell2"} {
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+		if err != nil {
+			t.Fatalf("GetSrvKeyspace(%v) failed: %v", cell, err)
+		}
+		refs := srvKeyspace.Partitions[0].ShardReferences
+		if len(refs) != 1 || refs[0].Name != "other" {
+			t.Errorf("SrvKeyspace in cell %v has wrong shard references: %v", cell, refs)
+		}
+	}
+}
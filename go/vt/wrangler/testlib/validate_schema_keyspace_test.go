@@ -0,0 +1,106 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestValidateSchemaKeyspaceJSON(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	if err := ts.CreateKeyspace(context.Background(), "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+
+	schema := func(msgColumn string) *tabletmanagerdatapb.SchemaDefinition {
+		return &tabletmanagerdatapb.SchemaDefinition{
+			DatabaseSchema: "CREATE DATABASE `{{.DatabaseName}}`",
+			TableDefinitions: []*tabletmanagerdatapb.TableDefinition{{
+				Name:   "t1",
+				Schema: "CREATE TABLE `t1` (\n  `id` bigint(20) NOT NULL,\n  " + msgColumn + ",\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB",
+				Type:   tmutils.TableBaseTable,
+			}},
+		}
+	}
+	var tablets []*FakeTablet
+	for _, tc := range []struct {
+		uid        uint32
+		tabletType topodatapb.TabletType
+		shard      string
+		msgColumn  string
+	}{
+		{0, topodatapb.TabletType_MASTER, "-80", "`msg` varchar(64)"},
+		{1, topodatapb.TabletType_REPLICA, "-80", "`msg` varchar(64)"},
+		{2, topodatapb.TabletType_MASTER, "80-", "`msg` varchar(64)"},
+		{3, topodatapb.TabletType_REPLICA, "80-", "`msg` varchar(128)"},
+	} {
+		ft := NewFakeTablet(t, wr, "cell1", tc.uid, tc.tabletType, nil, TabletKeyspaceShard(t, "ks", tc.shard))
+		ft.FakeMysqlDaemon.Schema = schema(tc.msgColumn)
+		ft.StartActionLoop(t, wr)
+		defer ft.StopActionLoop(t)
+		tablets = append(tablets, ft)
+	}
+
+	output, err := vp.RunAndOutput([]string{"ValidateSchemaKeyspace", "-json", "ks"})
+	if err == nil || !strings.Contains(err.Error(), "found 1 schema differences") {
+		t.Errorf("ValidateSchemaKeyspace -json must report the difference: %v", err)
+	}
+	var got []*tmutils.SchemaDiff
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("ValidateSchemaKeyspace -json printed invalid JSON: %v\n%v", err, output)
+	}
+	want := []*tmutils.SchemaDiff{{
+		Type:       tmutils.SchemaDiffColumn,
+		Table:      "t1",
+		Name:       "msg",
+		Left:       "cell1-0000000000",
+		Right:      "cell1-0000000003",
+		LeftValue:  "`msg` varchar(64)",
+		RightValue: "`msg` varchar(128)",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateSchemaKeyspace -json = %v, want %v", output, want)
+	}
+
+	// Without the difference, an empty list is printed.
+	tablets[3].FakeMysqlDaemon.Schema = schema("`msg` varchar(64)")
+	output, err = vp.RunAndOutput([]string{"ValidateSchemaKeyspace", "-json", "ks"})
+	if err != nil {
+		t.Fatalf("ValidateSchemaKeyspace -json failed: %v", err)
+	}
+	if strings.TrimSpace(output) != "[]" {
+		t.Errorf("ValidateSchemaKeyspace -json = %v, want []", output)
+	}
+}
@@ -96,3 +96,50 @@ func TestVersion(t *testing.T) {
 		t.Fatalf("ValidateVersionKeyspace(different) returned an unexpected error: %v", err)
 	}
 }
+
+func TestVersionKeyspaceAcrossCells(t *testing.T) {
+	wrangler.ResetDebugVarsGetVersion()
+
+	ts := memorytopo.NewServer("cell1", "cell2")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	// Two shards with the master in cell1 and a replica in cell2.
+	gitRevs := map[uint32]*string{}
+	for _, tc := range []struct {
+		uid        uint32
+		cell       string
+		tabletType topodatapb.TabletType
+		shard      string
+	}{
+		{10, "cell1", topodatapb.TabletType_MASTER, "-80"},
+		{11, "cell2", topodatapb.TabletType_REPLICA, "-80"},
+		{20, "cell1", topodatapb.TabletType_MASTER, "80-"},
+		{21, "cell2", topodatapb.TabletType_REPLICA, "80-"},
+	} {
+		ft := NewFakeTablet(t, wr, tc.cell, tc.uid, tc.tabletType, nil,
+			TabletKeyspaceShard(t, "source", tc.shard),
+			StartHTTPServer())
+		gitRev := "fake git rev"
+		gitRevs[tc.uid] = &gitRev
+		ft.StartActionLoop(t, wr)
+		ft.HTTPServer.Handler.(*http.ServeMux).HandleFunc("/debug/vars", expvarHandler(gitRevs[tc.uid]))
+		defer ft.StopActionLoop(t)
+	}
+
+	*gitRevs[21] = "different fake git rev"
+	output, err := vp.RunAndOutput([]string{"ValidateVersionKeyspace", "-concurrency", "1", "source"})
+	if err == nil || !strings.Contains(err.Error(), "is different than slave cell2-0000000021") {
+		t.Fatalf("ValidateVersionKeyspace(different) returned an unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"cell1 source/80-: 0 of 1 tablets differ from cell1-0000000010",
+		"cell2 source/-80: 0 of 1 tablets differ from cell1-0000000010",
+		"cell2 source/80-: 1 of 1 tablets differ from cell1-0000000010",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ValidateVersionKeyspace output does not contain %q: %v", want, output)
+		}
+	}
+}
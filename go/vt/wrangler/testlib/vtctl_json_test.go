@@ -0,0 +1,93 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// TestVtctlJSONOutput tests the global -json output mode of vtctl commands.
+func TestVtctlJSONOutput(t *testing.T) {
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	if err := ts.CreateKeyspace(context.Background(), "ks1", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace() failed: %v", err)
+	}
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, nil)
+
+	// The human-readable output is unchanged without -json.
+	got, err := vp.RunAndOutput([]string{"GetKeyspaces"})
+	if err != nil {
+		t.Fatalf("GetKeyspaces failed: %v", err)
+	}
+	if want := "ks1\ntest_keyspace\n"; got != want {
+		t.Errorf("GetKeyspaces: got %q, want %q", got, want)
+	}
+
+	// Lists are printed as JSON arrays.
+	got, err = vp.RunAndOutput([]string{"-json", "GetKeyspaces"})
+	if err != nil {
+		t.Fatalf("-json GetKeyspaces failed: %v", err)
+	}
+	var keyspaces []string
+	if err := json.Unmarshal([]byte(got), &keyspaces); err != nil {
+		t.Fatalf("-json GetKeyspaces returned invalid JSON %q: %v", got, err)
+	}
+	if want := []string{"ks1", "test_keyspace"}; !reflect.DeepEqual(keyspaces, want) {
+		t.Errorf("-json GetKeyspaces: got %v, want %v", keyspaces, want)
+	}
+
+	// Tablets are printed as a list of Tablet objects.
+	got, err = vp.RunAndOutput([]string{"-json", "ListAllTablets", "cell1"})
+	if err != nil {
+		t.Fatalf("-json ListAllTablets failed: %v", err)
+	}
+	var tablets []*topodatapb.Tablet
+	if err := json.Unmarshal([]byte(got), &tablets); err != nil {
+		t.Fatalf("-json ListAllTablets returned invalid JSON %q: %v", got, err)
+	}
+	if len(tablets) != 1 || tablets[0].Keyspace != "test_keyspace" || tablets[0].Type != topodatapb.TabletType_MASTER {
+		t.Errorf("-json ListAllTablets: got %v", tablets)
+	}
+
+	// Validations print their result.
+	got, err = vp.RunAndOutput([]string{"-json", "ValidateKeyspace", "ks1"})
+	if err != nil {
+		t.Fatalf("-json ValidateKeyspace failed: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &result); err != nil {
+		t.Fatalf("-json ValidateKeyspace returned invalid JSON %q: %v", got, err)
+	}
+	if want := map[string]interface{}{"Valid": true}; !reflect.DeepEqual(result, want) {
+		t.Errorf("-json ValidateKeyspace: got %v, want %v", result, want)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testlib
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vttablet/grpcqueryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestWaitForFilteredReplication(t *testing.T) {
+	ctx := context.Background()
+	ts := memorytopo.NewServer("cell1")
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, tmclient.NewTabletManagerClient())
+	vp := NewVtctlPipe(t, ts)
+	defer vp.Close()
+
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	master := NewFakeTablet(t, wr, "cell1", 0, topodatapb.TabletType_MASTER, nil,
+		TabletKeyspaceShard(t, "ks", "-80"))
+	if _, err := ts.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		si.SourceShards = []*topodatapb.Shard_SourceShard{{Uid: 0, Keyspace: "ks", Shard: "0"}}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateShardFields failed: %v", err)
+	}
+
+	qs := fakes.NewStreamHealthQueryService(querypb.Target{
+		Keyspace:   "ks",
+		Shard:      "-80",
+		TabletType: topodatapb.TabletType_MASTER,
+	})
+	grpcqueryservice.Register(master.RPCServer, qs)
+	master.StartActionLoop(t, wr)
+	defer master.StopActionLoop(t)
+
+	// Override with a fake VREngine after Agent is initialized in action loop.
+	dbClient := binlogplayer.NewMockDBClient(t)
+	dbClientFactory := func() binlogplayer.DBClient { return dbClient }
+	master.Agent.VREngine = vreplication.NewEngine(ts, "", master.FakeMysqlDaemon, dbClientFactory)
+	dbClient.ExpectRequest("select * from _vt.vreplication", &sqltypes.Result{}, nil)
+	if err := master.Agent.VREngine.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The positions are read for each progress message.
+	for _, pos := range []string{"MariaDB/5-456-890", "MariaDB/5-456-892"} {
+		dbClient.ExpectRequest("use _vt", &sqltypes.Result{}, nil)
+		dbClient.ExpectRequest("select id, pos from _vt.vreplication", &sqltypes.Result{
+			Fields: sqltypes.MakeTestFields("id|pos", "int64|varbinary"),
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewInt64(1),
+				sqltypes.NewVarBinary(pos),
+			}},
+		}, nil)
+	}
+
+	qs.AddHealthResponseWithFilteredReplicationDelay(60)
+	qs.AddHealthResponseWithFilteredReplicationDelay(10)
+
+	stream, err := vp.RunAndStreamOutput([]string{"WaitForFilteredReplication", "-max_delay", "30s", "ks/-80"})
+	if err != nil {
+		t.Fatalf("VtctlPipe.RunAndStreamOutput() failed: %v", err)
+	}
+	var output []string
+	for {
+		le, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("WaitForFilteredReplication failed: %v", err)
+		}
+		output = append(output, logutil.EventString(le))
+	}
+	dbClient.Wait()
+
+	got := strings.Join(output, "")
+	for _, want := range []string{
+		"Last seen delay: 60.0 seconds Positions: 1: MariaDB/5-456-890",
+		"has caught up. Last seen delay: 10.0 seconds Positions: 1: MariaDB/5-456-892",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WaitForFilteredReplication output does not contain %q: %v", want, got)
+		}
+	}
+}
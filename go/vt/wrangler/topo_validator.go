@@ -0,0 +1,372 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// This file cross-checks the records of the topology with each other,
+// only reading the topology (unlike Validate, which also asks the tablets):
+// - every tablet belongs to an existing shard, which lists its cell, and
+//   is in the replication graph of its cell.
+// - every replication graph belongs to an existing shard, and only
+//   references existing tablets of that shard and cell.
+// - every SrvKeyspace belongs to an existing keyspace, only references
+//   existing shards, and exists in every cell of the keyspace.
+//
+// The safe inconsistencies can be repaired: adding missing entries,
+// removing dangling references, and rebuilding or deleting serving
+// graphs. Orphan tablets are only reported, as deleting them is up to
+// the operator.
+
+// topoValidator keeps the state of a TopoValidate run.
+type topoValidator struct {
+	wr     *Wrangler
+	repair bool
+
+	// shards maps the existing keyspaces to their shards.
+	shards map[string]map[string]*topo.ShardInfo
+
+	// rebuilds maps keyspaces to the cells in which their serving
+	// graph has to be rebuilt, with the number of inconsistencies
+	// the rebuild repairs.
+	rebuilds map[string]map[string]int
+
+	problems int
+	repaired int
+}
+
+// TopoValidate checks the consistency of the tablet records, replication
+// graphs and serving graphs of all cells, and reports orphan records and
+// dangling references. If repair is set, the safe inconsistencies are
+// fixed. It returns an error if some inconsistencies were not fixed.
+func (wr *Wrangler) TopoValidate(ctx context.Context, repair bool) error {
+	v := &topoValidator{
+		wr:       wr,
+		repair:   repair,
+		shards:   make(map[string]map[string]*topo.ShardInfo),
+		rebuilds: make(map[string]map[string]int),
+	}
+	if err := v.readShards(ctx); err != nil {
+		return err
+	}
+	cells, err := wr.ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return fmt.Errorf("GetCellInfoNames failed: %v", err)
+	}
+
+	for _, cell := range cells {
+		if err := v.validateTablets(ctx, cell); err != nil {
+			return err
+		}
+		if err := v.validateReplicationGraph(ctx, cell); err != nil {
+			return err
+		}
+		if err := v.validateServingGraph(ctx, cell); err != nil {
+			return err
+		}
+	}
+	v.rebuildServingGraphs(ctx)
+
+	if v.problems > v.repaired {
+		return fmt.Errorf("found %v inconsistencies in the topology, repaired %v", v.problems, v.repaired)
+	}
+	if v.problems > 0 {
+		wr.Logger().Printf("Found and repaired %v inconsistencies in the topology\n", v.problems)
+	} else {
+		wr.Logger().Printf("The topology is consistent\n")
+	}
+	return nil
+}
+
+// report logs an inconsistency. If fix is not nil and repair is set, it
+// is called to repair it.
+func (v *topoValidator) report(fix func() error, format string, args ...interface{}) {
+	v.problems++
+	msg := fmt.Sprintf(format, args...)
+	if fix == nil || !v.repair {
+		v.wr.Logger().Errorf("%v", msg)
+		return
+	}
+	if err := fix(); err != nil {
+		v.wr.Logger().Errorf("%v: repair failed: %v", msg, err)
+		return
+	}
+	v.repaired++
+	v.wr.Logger().Warningf("%v: repaired", msg)
+}
+
+// scheduleRebuild records that the serving graph of a keyspace in a cell
+// has to be rebuilt, which is done once at the end.
+func (v *topoValidator) scheduleRebuild(keyspace, cell string) func() error {
+	return func() error {
+		if v.rebuilds[keyspace] == nil {
+			v.rebuilds[keyspace] = make(map[string]int)
+		}
+		v.rebuilds[keyspace][cell]++
+		return nil
+	}
+}
+
+func (v *topoValidator) readShards(ctx context.Context) error {
+	keyspaces, err := v.wr.ts.GetKeyspaces(ctx)
+	if err != nil {
+		return fmt.Errorf("GetKeyspaces failed: %v", err)
+	}
+	for _, keyspace := range keyspaces {
+		shards, err := v.wr.ts.FindAllShardsInKeyspace(ctx, keyspace)
+		if err != nil {
+			return fmt.Errorf("FindAllShardsInKeyspace(%v) failed: %v", keyspace, err)
+		}
+		v.shards[keyspace] = shards
+	}
+	return nil
+}
+
+func (v *topoValidator) validateTablets(ctx context.Context, cell string) error {
+	aliases, err := v.wr.ts.GetTabletsByCell(ctx, cell)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return fmt.Errorf("GetTabletsByCell(%v) failed: %v", cell, err)
+	}
+	tablets, err := v.wr.ts.GetTabletMap(ctx, aliases)
+	if err != nil {
+		return fmt.Errorf("GetTabletMap(%v) failed: %v", cell, err)
+	}
+
+	for _, alias := range aliases {
+		aliasStr := topoproto.TabletAliasString(alias)
+		ti, ok := tablets[aliasStr]
+		if !ok {
+			// Deleted since it was listed.
+			continue
+		}
+		if ti.Keyspace == "" || ti.Shard == "" {
+			continue
+		}
+
+		si, ok := v.shards[ti.Keyspace][ti.Shard]
+		if !ok {
+			v.report(nil, "orphan tablet %v: its shard %v/%v does not exist", aliasStr, ti.Keyspace, ti.Shard)
+			continue
+		}
+		if !si.HasCell(cell) {
+			v.report(func() error {
+				si, err := v.wr.ts.UpdateShardFields(ctx, ti.Keyspace, ti.Shard, func(si *topo.ShardInfo) error {
+					if si.HasCell(cell) {
+						return topo.NewError(topo.NoUpdateNeeded, ti.Keyspace+"/"+ti.Shard)
+					}
+					si.Cells = append(si.Cells, cell)
+					return nil
+				})
+				if si != nil {
+					// The other tablets and the serving graph
+					// check use the updated cells.
+					v.shards[ti.Keyspace][ti.Shard] = si
+				}
+				return err
+			}, "shard %v/%v does not list the cell %v of its tablet %v", ti.Keyspace, ti.Shard, cell, aliasStr)
+		}
+
+		sri, err := v.wr.ts.GetShardReplication(ctx, cell, ti.Keyspace, ti.Shard)
+		if err != nil && !topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("GetShardReplication(%v, %v, %v) failed: %v", cell, ti.Keyspace, ti.Shard, err)
+		}
+		if err != nil || !replicationGraphHasTablet(sri.ShardReplication, alias) {
+			v.report(func() error {
+				return topo.UpdateShardReplicationRecord(ctx, v.wr.ts, ti.Keyspace, ti.Shard, alias)
+			}, "tablet %v is missing from the replication graph of %v/%v in %v", aliasStr, ti.Keyspace, ti.Shard, cell)
+		}
+	}
+	return nil
+}
+
+func replicationGraphHasTablet(sr *topodatapb.ShardReplication, alias *topodatapb.TabletAlias) bool {
+	for _, node := range sr.Nodes {
+		if topoproto.TabletAliasEqual(node.TabletAlias, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateReplicationGraph checks all the replication graphs of a cell,
+// including the ones of shards that don't exist any more.
+func (v *topoValidator) validateReplicationGraph(ctx context.Context, cell string) error {
+	conn, err := v.wr.ts.ConnForCell(ctx, cell)
+	if err != nil {
+		return fmt.Errorf("ConnForCell(%v) failed: %v", cell, err)
+	}
+	keyspaces, err := listDirNames(ctx, conn, topo.KeyspacesPath)
+	if err != nil {
+		return err
+	}
+	for _, keyspace := range keyspaces {
+		shards, err := listDirNames(ctx, conn, path.Join(topo.KeyspacesPath, keyspace, topo.ShardsPath))
+		if err != nil {
+			return err
+		}
+		for _, shard := range shards {
+			sri, err := v.wr.ts.GetShardReplication(ctx, cell, keyspace, shard)
+			if topo.IsErrType(err, topo.NoNode) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("GetShardReplication(%v, %v, %v) failed: %v", cell, keyspace, shard, err)
+			}
+			if err := v.validateReplicationNodes(ctx, sri); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateReplicationNodes checks the tablets referenced by a replication
+// graph.
+func (v *topoValidator) validateReplicationNodes(ctx context.Context, sri *topo.ShardReplicationInfo) error {
+	cell, keyspace, shard := sri.Cell(), sri.Keyspace(), sri.Shard()
+	_, shardExists := v.shards[keyspace][shard]
+
+	valid := 0
+	for _, node := range sri.Nodes {
+		aliasStr := topoproto.TabletAliasString(node.TabletAlias)
+		alias := node.TabletAlias
+		remove := func() error {
+			return topo.RemoveShardReplicationRecord(ctx, v.wr.ts, cell, keyspace, shard, alias)
+		}
+		ti, err := v.wr.ts.GetTablet(ctx, alias)
+		switch {
+		case topo.IsErrType(err, topo.NoNode):
+			v.report(remove, "the replication graph of %v/%v in %v references the tablet %v, which does not exist", keyspace, shard, cell, aliasStr)
+		case err != nil:
+			return fmt.Errorf("GetTablet(%v) failed: %v", aliasStr, err)
+		case ti.Keyspace != keyspace || ti.Shard != shard || ti.Alias.Cell != cell:
+			v.report(remove, "the replication graph of %v/%v in %v references the tablet %v, which is in %v/%v in %v", keyspace, shard, cell, aliasStr, ti.Keyspace, ti.Shard, ti.Alias.Cell)
+		default:
+			valid++
+		}
+	}
+
+	if !shardExists {
+		// The replication graph can only be deleted once it does not
+		// reference any tablet of this shard.
+		var fix func() error
+		if valid == 0 {
+			fix = func() error {
+				return v.wr.ts.DeleteShardReplication(ctx, cell, keyspace, shard)
+			}
+		}
+		v.report(fix, "orphan replication graph of %v/%v in %v: the shard does not exist", keyspace, shard, cell)
+	}
+	return nil
+}
+
+// validateServingGraph checks the SrvKeyspace records of a cell.
+func (v *topoValidator) validateServingGraph(ctx context.Context, cell string) error {
+	srvKeyspaces, err := v.wr.ts.GetSrvKeyspaceNames(ctx, cell)
+	if err != nil && !topo.IsErrType(err, topo.NoNode) {
+		return fmt.Errorf("GetSrvKeyspaceNames(%v) failed: %v", cell, err)
+	}
+	served := make(map[string]bool)
+	for _, keyspace := range srvKeyspaces {
+		// The keyspace directory of the cell may only have
+		// replication graphs.
+		srvKeyspace, err := v.wr.ts.GetSrvKeyspace(ctx, cell, keyspace)
+		if topo.IsErrType(err, topo.NoNode) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("GetSrvKeyspace(%v, %v) failed: %v", cell, keyspace, err)
+		}
+		served[keyspace] = true
+
+		shards, ok := v.shards[keyspace]
+		if !ok {
+			v.report(func() error {
+				return v.wr.ts.DeleteSrvKeyspace(ctx, cell, keyspace)
+			}, "orphan serving graph of %v in %v: the keyspace does not exist", keyspace, cell)
+			continue
+		}
+		for _, partition := range srvKeyspace.Partitions {
+			for _, ref := range partition.ShardReferences {
+				if _, ok := shards[ref.Name]; !ok {
+					v.report(v.scheduleRebuild(keyspace, cell), "the serving graph of %v in %v references the shard %v for %v, which does not exist", keyspace, cell, ref.Name, partition.ServedType)
+				}
+			}
+		}
+	}
+
+	for keyspace, shards := range v.shards {
+		if served[keyspace] {
+			continue
+		}
+		for _, si := range shards {
+			if si.HasCell(cell) {
+				v.report(v.scheduleRebuild(keyspace, cell), "the serving graph of %v is missing in %v", keyspace, cell)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// rebuildServingGraphs rebuilds the serving graphs scheduled by the
+// repairs.
+func (v *topoValidator) rebuildServingGraphs(ctx context.Context) {
+	for keyspace, cellCounts := range v.rebuilds {
+		cells := make([]string, 0, len(cellCounts))
+		count := 0
+		for cell, c := range cellCounts {
+			cells = append(cells, cell)
+			count += c
+		}
+		sort.Strings(cells)
+		if err := topotools.RebuildKeyspace(ctx, v.wr.logger, v.wr.ts, keyspace, cells); err != nil {
+			v.wr.Logger().Errorf("rebuilding the serving graph of %v in %v failed: %v", keyspace, cells, err)
+			v.repaired -= count
+		}
+	}
+}
+
+// listDirNames returns the names of the entries of a directory, or nothing
+// if it does not exist.
+func listDirNames(ctx context.Context, conn topo.Conn, dirPath string) ([]string, error) {
+	entries, err := conn.ListDir(ctx, dirPath, false /*full*/)
+	switch {
+	case err == nil:
+	case topo.IsErrType(err, topo.NoNode):
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("ListDir(%v) failed: %v", dirPath, err)
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestTopoValidate(t *testing.T) {
+	ctx := context.Background()
+	cell := "cell1"
+	ts := memorytopo.NewServer(cell)
+	wr := New(logutil.NewMemoryLogger(), ts, nil)
+
+	if err := ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}); err != nil {
+		t.Fatalf("CreateKeyspace failed: %v", err)
+	}
+	if err := ts.CreateShard(ctx, "ks", "0"); err != nil {
+		t.Fatalf("CreateShard failed: %v", err)
+	}
+	tablet := func(uid uint32, keyspace string) *topodatapb.Tablet {
+		return &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: cell, Uid: uid},
+			Keyspace: keyspace,
+			Shard:    "0",
+		}
+	}
+	for _, tablet := range []*topodatapb.Tablet{tablet(1, "ks"), tablet(2, "ks"), tablet(3, "gone")} {
+		if err := ts.CreateTablet(ctx, tablet); err != nil {
+			t.Fatalf("CreateTablet failed: %v", err)
+		}
+	}
+
+	// Break the topology:
+	// - the shard does not list the cell of its tablets.
+	// - tablet 2 is not in the replication graph.
+	// - the replication graph has a tablet that doesn't exist.
+	// - tablet 3 is in a shard that doesn't exist, so is its
+	//   replication graph.
+	// - the keyspace has no serving graph, and a deleted keyspace has one.
+	if err := topo.RemoveShardReplicationRecord(ctx, ts, cell, "ks", "0", tablet(2, "ks").Alias); err != nil {
+		t.Fatalf("RemoveShardReplicationRecord failed: %v", err)
+	}
+	if err := topo.UpdateShardReplicationRecord(ctx, ts, "ks", "0", tablet(4, "ks").Alias); err != nil {
+		t.Fatalf("UpdateShardReplicationRecord failed: %v", err)
+	}
+	if err := ts.UpdateSrvKeyspace(ctx, cell, "gone", &topodatapb.SrvKeyspace{}); err != nil {
+		t.Fatalf("UpdateSrvKeyspace failed: %v", err)
+	}
+
+	// Without -repair, every inconsistency is reported.
+	err := wr.TopoValidate(ctx, false)
+	if err == nil || !strings.Contains(err.Error(), "found 7 inconsistencies in the topology, repaired 0") {
+		t.Fatalf("TopoValidate returned %v", err)
+	}
+
+	// With -repair, all but the orphan tablet and its replication graph
+	// are repaired. Once the cell is added to the shard, its serving
+	// graph is found missing, and rebuilt.
+	err = wr.TopoValidate(ctx, true)
+	if err == nil || !strings.Contains(err.Error(), "found 7 inconsistencies in the topology, repaired 5") {
+		t.Fatalf("TopoValidate -repair returned %v", err)
+	}
+	if _, err := ts.GetSrvKeyspace(ctx, cell, "ks"); err != nil {
+		t.Errorf("the serving graph of ks was not rebuilt: %v", err)
+	}
+	if _, err := ts.GetSrvKeyspace(ctx, cell, "gone"); !topo.IsErrType(err, topo.NoNode) {
+		t.Errorf("the serving graph of gone was not deleted: %v", err)
+	}
+
+	// Once the orphan tablet is deleted, its replication graph is
+	// repaired, and the topology is consistent.
+	if err := ts.DeleteTablet(ctx, tablet(3, "gone").Alias); err != nil {
+		t.Fatalf("DeleteTablet failed: %v", err)
+	}
+	if err := wr.TopoValidate(ctx, true); err != nil {
+		t.Fatalf("TopoValidate -repair after deleting the orphan tablet failed: %v", err)
+	}
+	if err := wr.TopoValidate(ctx, false); err != nil {
+		t.Errorf("TopoValidate after the repairs failed: %v", err)
+	}
+}
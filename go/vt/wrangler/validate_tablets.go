@@ -0,0 +1,105 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wrangler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sync2"
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// tabletCheckFunc compares one tablet with the reference tablet and records
+// each difference in "er".
+type tabletCheckFunc func(ctx context.Context, alias *topodatapb.TabletAlias, er concurrency.ErrorRecorder)
+
+// validateResult counts the checked tablets of one cell and shard.
+type validateResult struct {
+	checked int
+	differ  int
+}
+
+// validateKeyspaceTablets runs "check" on all tablets in the shards of the
+// keyspace except on the reference tablet. The tablets of all shards and
+// cells are listed concurrently. "sema" limits how many checks run
+// at the same time. The number of differing tablets is logged per cell and
+// shard. The returned error contains all differences.
+func (wr *Wrangler) validateKeyspaceTablets(ctx context.Context, keyspace string, shards []string, referenceAlias *topodatapb.TabletAlias, sema *sync2.Semaphore, check tabletCheckFunc) error {
+	var mu sync.Mutex
+	results := make(map[string]*validateResult)
+	er := concurrency.AllErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard string) {
+			defer wg.Done()
+			aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+			if err != nil {
+				er.RecordError(err)
+				return
+			}
+			for _, alias := range aliases {
+				if topoproto.TabletAliasEqual(alias, referenceAlias) {
+					continue
+				}
+				wg.Add(1)
+				go func(alias *topodatapb.TabletAlias) {
+					defer wg.Done()
+					sema.Acquire()
+					defer sema.Release()
+
+					tabletEr := concurrency.AllErrorRecorder{}
+					check(ctx, alias, &tabletEr)
+					for _, err := range tabletEr.Errors {
+						er.RecordError(err)
+					}
+
+					key := fmt.Sprintf("%v %v", alias.Cell, topoproto.KeyspaceShardString(keyspace, shard))
+					mu.Lock()
+					defer mu.Unlock()
+					r, ok := results[key]
+					if !ok {
+						r = &validateResult{}
+						results[key] = r
+					}
+					r.checked++
+					if tabletEr.HasErrors() {
+						r.differ++
+					}
+				}(alias)
+			}
+		}(shard)
+	}
+	wg.Wait()
+
+	var keys []string
+	for key := range results {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		wr.Logger().Printf("%v: %v of %v tablets differ from %v\n", key, results[key].differ, results[key].checked, topoproto.TabletAliasString(referenceAlias))
+	}
+	return er.Error()
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/sync2"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo/topoproto"
@@ -83,9 +84,8 @@ func (wr *Wrangler) GetVersion(ctx context.Context, tabletAlias *topodatapb.Tabl
 	return version, err
 }
 
-// helper method to asynchronously get and diff a version
-func (wr *Wrangler) diffVersion(ctx context.Context, masterVersion string, masterAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
-	defer wg.Done()
+// helper method to get and diff a version
+func (wr *Wrangler) diffVersion(ctx context.Context, masterVersion string, masterAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, er concurrency.ErrorRecorder) {
 	log.Infof("Gathering version for %v", topoproto.TabletAliasString(alias))
 	slaveVersion, err := wr.GetVersion(ctx, alias)
 	if err != nil {
@@ -132,7 +132,10 @@ func (wr *Wrangler) ValidateVersionShard(ctx context.Context, keyspace, shard st
 		}
 
 		wg.Add(1)
-		go wr.diffVersion(ctx, masterVersion, si.MasterAlias, alias, &wg, &er)
+		go func(alias *topodatapb.TabletAlias) {
+			defer wg.Done()
+			wr.diffVersion(ctx, masterVersion, si.MasterAlias, alias, &er)
+		}(alias)
 	}
 	wg.Wait()
 	if er.HasErrors() {
@@ -143,7 +146,7 @@ func (wr *Wrangler) ValidateVersionShard(ctx context.Context, keyspace, shard st
 
 // ValidateVersionKeyspace validates all versions are the same in all
 // tablets in a keyspace
-func (wr *Wrangler) ValidateVersionKeyspace(ctx context.Context, keyspace string) error {
+func (wr *Wrangler) ValidateVersionKeyspace(ctx context.Context, keyspace string, sema *sync2.Semaphore) error {
 	// find all the shards
 	shards, err := wr.ts.GetShardNames(ctx, keyspace)
 	if err != nil {
@@ -175,27 +178,11 @@ func (wr *Wrangler) ValidateVersionKeyspace(ctx context.Context, keyspace string
 	}
 
 	// then diff with all tablets but master 0
-	er := concurrency.AllErrorRecorder{}
-	wg := sync.WaitGroup{}
-	for _, shard := range shards {
-		aliases, err := wr.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
-		if err != nil {
-			er.RecordError(err)
-			continue
-		}
-
-		for _, alias := range aliases {
-			if topoproto.TabletAliasEqual(alias, si.MasterAlias) {
-				continue
-			}
-
-			wg.Add(1)
-			go wr.diffVersion(ctx, referenceVersion, referenceAlias, alias, &wg, &er)
-		}
-	}
-	wg.Wait()
-	if er.HasErrors() {
-		return fmt.Errorf("Version diffs: %v", er.Error().Error())
+	err = wr.validateKeyspaceTablets(ctx, keyspace, shards, referenceAlias, sema, func(ctx context.Context, alias *topodatapb.TabletAlias, er concurrency.ErrorRecorder) {
+		wr.diffVersion(ctx, referenceVersion, referenceAlias, alias, er)
+	})
+	if err != nil {
+		return fmt.Errorf("Version diffs: %v", err)
 	}
 	return nil
 }
@@ -20,7 +20,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   name='vtworkerdata.proto',
   package='vtworkerdata',
   syntax='proto3',
-  serialized_pb=_b('\n\x12vtworkerdata.proto\x12\x0cvtworkerdata\x1a\rlogutil.proto\"-\n\x1d\x45xecuteVtworkerCommandRequest\x12\x0c\n\x04\x61rgs\x18\x01 \x03(\t\"?\n\x1e\x45xecuteVtworkerCommandResponse\x12\x1d\n\x05\x65vent\x18\x01 \x01(\x0b\x32\x0e.logutil.EventB+Z)vitess.io/vitess/go/vt/proto/vtworkerdatab\x06proto3')
+  serialized_pb=_b('\n\x12vtworkerdata.proto\x12\x0cvtworkerdata\x1a\rlogutil.proto\"-\n\x1d\x45xecuteVtworkerCommandRequest\x12\x0c\n\x04\x61rgs\x18\x01 \x03(\t\"?\n\x1e\x45xecuteVtworkerCommandResponse\x12\x1d\n\x05\x65vent\x18\x01 \x01(\x0b\x32\x0e.logutil.Event\"\x12\n\x10GetStatusRequest\"_\n\x11GetStatusResponse\x12\x0e\n\x06worker\x18\x01 \x01(\t\x12\r\n\x05state\x18\x02 \x01(\t\x12\x0e\n\x06status\x18\x03 \x01(\t\x12\x0c\n\x04\x64one\x18\x04 \x01(\x08\x12\r\n\x05\x65rror\x18\x05 \x01(\t\"\x0f\n\rCancelRequest\"\"\n\x0e\x43\x61ncelResponse\x12\x10\n\x08\x63\x61nceled\x18\x01 \x01(\x08\"\x0e\n\x0cPauseRequest\"\x1f\n\rPauseResponse\x12\x0e\n\x06paused\x18\x01 \x01(\x08\"\x0f\n\rResumeRequest\"!\n\x0eResumeResponse\x12\x0f\n\x07resumed\x18\x01 \x01(\x08\x42+Z)vitess.io/vitess/go/vt/proto/vtworkerdatab\x06proto3')
   ,
   dependencies=[logutil__pb2.DESCRIPTOR,])
 
@@ -88,9 +88,265 @@ _EXECUTEVTWORKERCOMMANDRESPONSE = _descriptor.Descriptor(
   serialized_end=161,
 )
 
+
+_GETSTATUSREQUEST = _descriptor.Descriptor(
+  name='GetStatusRequest',
+  full_name='vtworkerdata.GetStatusRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=163,
+  serialized_end=181,
+)
+
+
+_GETSTATUSRESPONSE = _descriptor.Descriptor(
+  name='GetStatusResponse',
+  full_name='vtworkerdata.GetStatusResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='worker', full_name='vtworkerdata.GetStatusResponse.worker', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='state', full_name='vtworkerdata.GetStatusResponse.state', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='status', full_name='vtworkerdata.GetStatusResponse.status', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='done', full_name='vtworkerdata.GetStatusResponse.done', index=3,
+      number=4, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='error', full_name='vtworkerdata.GetStatusResponse.error', index=4,
+      number=5, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=183,
+  serialized_end=278,
+)
+
+
+_CANCELREQUEST = _descriptor.Descriptor(
+  name='CancelRequest',
+  full_name='vtworkerdata.CancelRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=280,
+  serialized_end=295,
+)
+
+
+_CANCELRESPONSE = _descriptor.Descriptor(
+  name='CancelResponse',
+  full_name='vtworkerdata.CancelResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='canceled', full_name='vtworkerdata.CancelResponse.canceled', index=0,
+      number=1, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=297,
+  serialized_end=331,
+)
+
+
+_PAUSEREQUEST = _descriptor.Descriptor(
+  name='PauseRequest',
+  full_name='vtworkerdata.PauseRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=333,
+  serialized_end=347,
+)
+
+
+_PAUSERESPONSE = _descriptor.Descriptor(
+  name='PauseResponse',
+  full_name='vtworkerdata.PauseResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='paused', full_name='vtworkerdata.PauseResponse.paused', index=0,
+      number=1, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=349,
+  serialized_end=380,
+)
+
+
+_RESUMEREQUEST = _descriptor.Descriptor(
+  name='ResumeRequest',
+  full_name='vtworkerdata.ResumeRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=382,
+  serialized_end=397,
+)
+
+
+_RESUMERESPONSE = _descriptor.Descriptor(
+  name='ResumeResponse',
+  full_name='vtworkerdata.ResumeResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='resumed', full_name='vtworkerdata.ResumeResponse.resumed', index=0,
+      number=1, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=399,
+  serialized_end=432,
+)
+
 _EXECUTEVTWORKERCOMMANDRESPONSE.fields_by_name['event'].message_type = logutil__pb2._EVENT
 DESCRIPTOR.message_types_by_name['ExecuteVtworkerCommandRequest'] = _EXECUTEVTWORKERCOMMANDREQUEST
 DESCRIPTOR.message_types_by_name['ExecuteVtworkerCommandResponse'] = _EXECUTEVTWORKERCOMMANDRESPONSE
+DESCRIPTOR.message_types_by_name['GetStatusRequest'] = _GETSTATUSREQUEST
+DESCRIPTOR.message_types_by_name['GetStatusResponse'] = _GETSTATUSRESPONSE
+DESCRIPTOR.message_types_by_name['CancelRequest'] = _CANCELREQUEST
+DESCRIPTOR.message_types_by_name['CancelResponse'] = _CANCELRESPONSE
+DESCRIPTOR.message_types_by_name['PauseRequest'] = _PAUSEREQUEST
+DESCRIPTOR.message_types_by_name['PauseResponse'] = _PAUSERESPONSE
+DESCRIPTOR.message_types_by_name['ResumeRequest'] = _RESUMEREQUEST
+DESCRIPTOR.message_types_by_name['ResumeResponse'] = _RESUMERESPONSE
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
 ExecuteVtworkerCommandRequest = _reflection.GeneratedProtocolMessageType('ExecuteVtworkerCommandRequest', (_message.Message,), dict(
@@ -107,6 +363,62 @@ ExecuteVtworkerCommandResponse = _reflection.GeneratedProtocolMessageType('Execu
   ))
 _sym_db.RegisterMessage(ExecuteVtworkerCommandResponse)
 
+GetStatusRequest = _reflection.GeneratedProtocolMessageType('GetStatusRequest', (_message.Message,), dict(
+  DESCRIPTOR = _GETSTATUSREQUEST,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.GetStatusRequest)
+  ))
+_sym_db.RegisterMessage(GetStatusRequest)
+
+GetStatusResponse = _reflection.GeneratedProtocolMessageType('GetStatusResponse', (_message.Message,), dict(
+  DESCRIPTOR = _GETSTATUSRESPONSE,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.GetStatusResponse)
+  ))
+_sym_db.RegisterMessage(GetStatusResponse)
+
+CancelRequest = _reflection.GeneratedProtocolMessageType('CancelRequest', (_message.Message,), dict(
+  DESCRIPTOR = _CANCELREQUEST,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.CancelRequest)
+  ))
+_sym_db.RegisterMessage(CancelRequest)
+
+CancelResponse = _reflection.GeneratedProtocolMessageType('CancelResponse', (_message.Message,), dict(
+  DESCRIPTOR = _CANCELRESPONSE,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.CancelResponse)
+  ))
+_sym_db.RegisterMessage(CancelResponse)
+
+PauseRequest = _reflection.GeneratedProtocolMessageType('PauseRequest', (_message.Message,), dict(
+  DESCRIPTOR = _PAUSEREQUEST,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.PauseRequest)
+  ))
+_sym_db.RegisterMessage(PauseRequest)
+
+PauseResponse = _reflection.GeneratedProtocolMessageType('PauseResponse', (_message.Message,), dict(
+  DESCRIPTOR = _PAUSERESPONSE,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.PauseResponse)
+  ))
+_sym_db.RegisterMessage(PauseResponse)
+
+ResumeRequest = _reflection.GeneratedProtocolMessageType('ResumeRequest', (_message.Message,), dict(
+  DESCRIPTOR = _RESUMEREQUEST,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.ResumeRequest)
+  ))
+_sym_db.RegisterMessage(ResumeRequest)
+
+ResumeResponse = _reflection.GeneratedProtocolMessageType('ResumeResponse', (_message.Message,), dict(
+  DESCRIPTOR = _RESUMERESPONSE,
+  __module__ = 'vtworkerdata_pb2'
+  # @@protoc_insertion_point(class_scope:vtworkerdata.ResumeResponse)
+  ))
+_sym_db.RegisterMessage(ResumeResponse)
+
 
 DESCRIPTOR.has_options = True
 DESCRIPTOR._options = _descriptor._ParseOptions(descriptor_pb2.FileOptions(), _b('Z)vitess.io/vitess/go/vt/proto/vtworkerdata'))
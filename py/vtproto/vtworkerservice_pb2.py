@@ -20,7 +20,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   name='vtworkerservice.proto',
   package='vtworkerservice',
   syntax='proto3',
-  serialized_pb=_b('\n\x15vtworkerservice.proto\x12\x0fvtworkerservice\x1a\x12vtworkerdata.proto2\x83\x01\n\x08Vtworker\x12w\n\x16\x45xecuteVtworkerCommand\x12+.vtworkerdata.ExecuteVtworkerCommandRequest\x1a,.vtworkerdata.ExecuteVtworkerCommandResponse\"\x00\x30\x01\x42.Z,vitess.io/vitess/go/vt/proto/vtworkerserviceb\x06proto3')
+  serialized_pb=_b('\n\x15vtworkerservice.proto\x12\x0fvtworkerservice\x1a\x12vtworkerdata.proto2\xa5\x03\n\x08Vtworker\x12w\n\x16\x45xecuteVtworkerCommand\x12+.vtworkerdata.ExecuteVtworkerCommandRequest\x1a,.vtworkerdata.ExecuteVtworkerCommandResponse\"\x00\x30\x01\x12N\n\tGetStatus\x12\x1e.vtworkerdata.GetStatusRequest\x1a\x1f.vtworkerdata.GetStatusResponse\"\x00\x12\x45\n\x06\x43\x61ncel\x12\x1b.vtworkerdata.CancelRequest\x1a\x1c.vtworkerdata.CancelResponse\"\x00\x12\x42\n\x05Pause\x12\x1a.vtworkerdata.PauseRequest\x1a\x1b.vtworkerdata.PauseResponse\"\x00\x12\x45\n\x06Resume\x12\x1b.vtworkerdata.ResumeRequest\x1a\x1c.vtworkerdata.ResumeResponse\"\x00\x42.Z,vitess.io/vitess/go/vt/proto/vtworkerserviceb\x06proto3')
   ,
   dependencies=[vtworkerdata__pb2.DESCRIPTOR,])
 
@@ -39,7 +39,7 @@ _VTWORKER = _descriptor.ServiceDescriptor(
   index=0,
   options=None,
   serialized_start=63,
-  serialized_end=194,
+  serialized_end=484,
   methods=[
   _descriptor.MethodDescriptor(
     name='ExecuteVtworkerCommand',
@@ -50,6 +50,42 @@ _VTWORKER = _descriptor.ServiceDescriptor(
     output_type=vtworkerdata__pb2._EXECUTEVTWORKERCOMMANDRESPONSE,
     options=None,
   ),
+  _descriptor.MethodDescriptor(
+    name='GetStatus',
+    full_name='vtworkerservice.Vtworker.GetStatus',
+    index=1,
+    containing_service=None,
+    input_type=vtworkerdata__pb2._GETSTATUSREQUEST,
+    output_type=vtworkerdata__pb2._GETSTATUSRESPONSE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='Cancel',
+    full_name='vtworkerservice.Vtworker.Cancel',
+    index=2,
+    containing_service=None,
+    input_type=vtworkerdata__pb2._CANCELREQUEST,
+    output_type=vtworkerdata__pb2._CANCELRESPONSE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='Pause',
+    full_name='vtworkerservice.Vtworker.Pause',
+    index=3,
+    containing_service=None,
+    input_type=vtworkerdata__pb2._PAUSEREQUEST,
+    output_type=vtworkerdata__pb2._PAUSERESPONSE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='Resume',
+    full_name='vtworkerservice.Vtworker.Resume',
+    index=4,
+    containing_service=None,
+    input_type=vtworkerdata__pb2._RESUMEREQUEST,
+    output_type=vtworkerdata__pb2._RESUMERESPONSE,
+    options=None,
+  ),
 ])
 _sym_db.RegisterServiceDescriptor(_VTWORKER)
 
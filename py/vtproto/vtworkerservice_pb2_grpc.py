@@ -19,6 +19,26 @@ class VtworkerStub(object):
         request_serializer=vtworkerdata__pb2.ExecuteVtworkerCommandRequest.SerializeToString,
         response_deserializer=vtworkerdata__pb2.ExecuteVtworkerCommandResponse.FromString,
         )
+    self.GetStatus = channel.unary_unary(
+        '/vtworkerservice.Vtworker/GetStatus',
+        request_serializer=vtworkerdata__pb2.GetStatusRequest.SerializeToString,
+        response_deserializer=vtworkerdata__pb2.GetStatusResponse.FromString,
+        )
+    self.Cancel = channel.unary_unary(
+        '/vtworkerservice.Vtworker/Cancel',
+        request_serializer=vtworkerdata__pb2.CancelRequest.SerializeToString,
+        response_deserializer=vtworkerdata__pb2.CancelResponse.FromString,
+        )
+    self.Pause = channel.unary_unary(
+        '/vtworkerservice.Vtworker/Pause',
+        request_serializer=vtworkerdata__pb2.PauseRequest.SerializeToString,
+        response_deserializer=vtworkerdata__pb2.PauseResponse.FromString,
+        )
+    self.Resume = channel.unary_unary(
+        '/vtworkerservice.Vtworker/Resume',
+        request_serializer=vtworkerdata__pb2.ResumeRequest.SerializeToString,
+        response_deserializer=vtworkerdata__pb2.ResumeResponse.FromString,
+        )
 
 
 class VtworkerServicer(object):
@@ -33,6 +53,35 @@ class VtworkerServicer(object):
     context.set_details('Method not implemented!')
     raise NotImplementedError('Method not implemented!')
 
+  def GetStatus(self, request, context):
+    """GetStatus returns the status of the current or last vtworker command.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def Cancel(self, request, context):
+    """Cancel cancels the currently running vtworker command.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def Pause(self, request, context):
+    """Pause pauses the currently running vtworker command. The command stops
+    issuing new queries but keeps its state until it is resumed.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def Resume(self, request, context):
+    """Resume resumes the currently paused vtworker command.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
 
 def add_VtworkerServicer_to_server(servicer, server):
   rpc_method_handlers = {
@@ -41,6 +90,26 @@ def add_VtworkerServicer_to_server(servicer, server):
           request_deserializer=vtworkerdata__pb2.ExecuteVtworkerCommandRequest.FromString,
           response_serializer=vtworkerdata__pb2.ExecuteVtworkerCommandResponse.SerializeToString,
       ),
+      'GetStatus': grpc.unary_unary_rpc_method_handler(
+          servicer.GetStatus,
+          request_deserializer=vtworkerdata__pb2.GetStatusRequest.FromString,
+          response_serializer=vtworkerdata__pb2.GetStatusResponse.SerializeToString,
+      ),
+      'Cancel': grpc.unary_unary_rpc_method_handler(
+          servicer.Cancel,
+          request_deserializer=vtworkerdata__pb2.CancelRequest.FromString,
+          response_serializer=vtworkerdata__pb2.CancelResponse.SerializeToString,
+      ),
+      'Pause': grpc.unary_unary_rpc_method_handler(
+          servicer.Pause,
+          request_deserializer=vtworkerdata__pb2.PauseRequest.FromString,
+          response_serializer=vtworkerdata__pb2.PauseResponse.SerializeToString,
+      ),
+      'Resume': grpc.unary_unary_rpc_method_handler(
+          servicer.Resume,
+          request_deserializer=vtworkerdata__pb2.ResumeRequest.FromString,
+          response_serializer=vtworkerdata__pb2.ResumeResponse.SerializeToString,
+      ),
   }
   generic_handler = grpc.method_handlers_generic_handler(
       'vtworkerservice.Vtworker', rpc_method_handlers)
@@ -279,8 +279,13 @@ class TestKeyspace(unittest.TestCase):
         ['GetShardReplication', 'test_nj', 'test_delete_keyspace/0'])
     utils.run_vtctl(['GetSrvKeyspace', 'test_nj', 'test_delete_keyspace'])
 
-    # Recursive DeleteKeyspace
-    utils.run_vtctl(['DeleteKeyspace', '-recursive', 'test_delete_keyspace'])
+    # Recursive DeleteKeyspace refuses to delete the serving master,
+    # unless -even_if_serving is specified.
+    utils.run_vtctl(['DeleteKeyspace', '-recursive', 'test_delete_keyspace'],
+                    expect_fail=True)
+    utils.run_vtctl(['GetShard', 'test_delete_keyspace/0'])
+    utils.run_vtctl(['DeleteKeyspace', '-recursive', '-even_if_serving',
+                     'test_delete_keyspace'])
 
     # Check that everything is gone.
     utils.run_vtctl(['GetKeyspace', 'test_delete_keyspace'], expect_fail=True)
@@ -360,7 +365,8 @@ class TestKeyspace(unittest.TestCase):
         expect_fail=True)
 
     # Clean up.
-    utils.run_vtctl(['DeleteKeyspace', '-recursive', 'test_delete_keyspace'])
+    utils.run_vtctl(['DeleteKeyspace', '-recursive', '-even_if_serving',
+                     'test_delete_keyspace'])
 
   def test_shard_count(self):
     sharded_ks = self._read_srv_keyspace(SHARDED_KEYSPACE)